@@ -0,0 +1,135 @@
+// Command consistency cross-references Neo4j, MongoDB and PostgreSQL and reports data
+// that one store has lost track of:
+//
+//	dataset_missing_metadata       Dataset node without its MongoDB metadata document
+//	attribute_row_missing_dataset  entity_attributes row without a Dataset node
+//	orphan_attribute_table         attr_* table that no entity_attributes row refers to
+//	orphan_document                MongoDB document whose node was deleted from Neo4j
+//
+// With -repair, Dataset metadata and the lookup graph of tabular attributes are rebuilt from
+// the surviving stores. With -quarantine, what cannot be repaired is moved aside: tables to the
+// quarantine schema and documents to the <collection>_quarantine collection.
+// Only the data of one tenant is checked, the default one unless -tenant names another.
+//
+// It connects to the databases using the same environment variables as the CORE service.
+//
+// Usage:
+//
+//	consistency [-categories a,b] [-repair] [-quarantine] [-json] [-out file] [-tenant id]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	dbcommons "lk/datafoundation/core-api/commons/db"
+	engine "lk/datafoundation/core-api/engine"
+	"lk/datafoundation/core-api/pkg/consistency"
+	"lk/datafoundation/core-api/pkg/tenant"
+)
+
+func main() {
+	categoryNames := flag.String("categories", "", "Comma separated categories to report (all if empty)")
+	repair := flag.Bool("repair", false, "Rebuild missing lookup graph nodes and metadata")
+	quarantine := flag.Bool("quarantine", false, "Move dangling tables and documents that cannot be repaired aside")
+	asJSON := flag.Bool("json", false, "Write the report as JSON")
+	out := flag.String("out", "", "Output file (stdout if empty)")
+	tenantID := flag.String("tenant", tenant.Default, "Tenant whose data is read and written")
+	flag.Parse()
+
+	var categories []consistency.Category
+	for _, name := range strings.Split(*categoryNames, ",") {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		category, err := consistency.ParseCategory(name)
+		if err != nil {
+			log.Fatalf("[consistency.main] %v", err)
+		}
+		categories = append(categories, category)
+	}
+
+	if err := tenant.Validate(*tenantID); err != nil {
+		log.Fatalf("[consistency.main] %v", err)
+	}
+	ctx := tenant.WithTenant(context.Background(), *tenantID)
+
+	neo4jRepo, err := dbcommons.GetNeo4jRepository(ctx)
+	if err != nil {
+		log.Fatalf("[consistency.main] Failed to create Neo4j repository: %v", err)
+	}
+	defer neo4jRepo.Close(ctx)
+
+	postgresRepo, err := dbcommons.GetPostgresRepository(ctx)
+	if err != nil {
+		log.Fatalf("[consistency.main] Failed to create PostgreSQL repository: %v", err)
+	}
+	defer postgresRepo.Close()
+
+	checker := engine.NewConsistencyChecker(neo4jRepo, dbcommons.GetMongoRepository(ctx), postgresRepo)
+	report, err := checker.Check(ctx, engine.ConsistencyOptions{
+		Categories: categories,
+		Repair:     *repair,
+		Quarantine: *quarantine,
+	})
+	if err != nil {
+		log.Fatalf("[consistency.main] Consistency check failed: %v", err)
+	}
+
+	output := os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("[consistency.main] Failed to create %s: %v", *out, err)
+		}
+		defer file.Close()
+		output = file
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	} else {
+		err = writeText(output, report)
+	}
+	if err != nil {
+		log.Fatalf("[consistency.main] Failed to write report: %v", err)
+	}
+
+	if len(report.Issues) > 0 {
+		fmt.Fprintf(os.Stderr, "Found %d inconsistencies\n", len(report.Issues))
+	}
+}
+
+// writeText writes the counts per category followed by one line per issue
+func writeText(w io.Writer, report *consistency.Report) error {
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	counts := report.Counts()
+	for _, category := range consistency.Categories {
+		fmt.Fprintf(table, "%s\t%d\n", category, counts[category])
+	}
+	fmt.Fprintln(table)
+
+	if len(report.Issues) > 0 {
+		fmt.Fprintln(table, "CATEGORY\tID\tENTITY\tATTRIBUTE\tTABLE\tACTION\tDETAIL")
+	}
+	for _, issue := range report.Issues {
+		detail := issue.Detail
+		if issue.Error != "" {
+			detail = issue.Error
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			issue.Category, issue.ID, issue.EntityID, issue.AttributeName, issue.TableName, issue.Action, detail)
+	}
+
+	return table.Flush()
+}
@@ -0,0 +1,135 @@
+// Command export-tabular writes a tabular attribute as an Apache Arrow IPC stream or a Parquet file,
+// ready to be opened with pandas, polars or DuckDB.
+//
+// It reads PostgreSQL directly using the same environment variables as the CORE service, so attribute
+// access policies do not apply; use the ExportTabular RPC to export on behalf of a caller.
+//
+// Usage:
+//
+//	export-tabular -entity <entityId> -attribute <name> [-format arrow|parquet] [-filter column=value ...]
+//	               [-fields a,b] [-from RFC3339] [-until RFC3339] [-out file] [-tenant id]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	dbcommons "lk/datafoundation/core-api/commons/db"
+	"lk/datafoundation/core-api/db/repository"
+	engine "lk/datafoundation/core-api/engine"
+	"lk/datafoundation/core-api/pkg/tabularexport"
+	"lk/datafoundation/core-api/pkg/tenant"
+)
+
+// filterFlags collects repeated -filter column=value flags
+type filterFlags map[string]interface{}
+
+func (f filterFlags) String() string {
+	return fmt.Sprint(map[string]interface{}(f))
+}
+
+func (f filterFlags) Set(value string) error {
+	column, v, ok := strings.Cut(value, "=")
+	if !ok || strings.TrimSpace(column) == "" {
+		return fmt.Errorf("filter %q is not column=value", value)
+	}
+	f[strings.TrimSpace(column)] = v
+	return nil
+}
+
+func main() {
+	entityID := flag.String("entity", "", "Id of the entity the attribute belongs to (required)")
+	attrName := flag.String("attribute", "", "Name of the tabular attribute (required)")
+	formatName := flag.String("format", "arrow", "Output format: arrow or parquet")
+	filters := filterFlags{}
+	flag.Var(filters, "filter", "Only export rows where column=value (repeatable)")
+	fields := flag.String("fields", "", "Comma separated columns to export (all if empty)")
+	from := flag.String("from", "", "Only export rows written at or after this RFC3339 instant")
+	until := flag.String("until", "", "Only export rows written before this RFC3339 instant")
+	out := flag.String("out", "", "Output file (stdout if empty)")
+	tenantID := flag.String("tenant", tenant.Default, "Tenant whose data is read")
+	flag.Parse()
+
+	if *entityID == "" || *attrName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	format, err := tabularexport.ParseFormat(*formatName)
+	if err != nil {
+		log.Fatalf("[export-tabular.main] %v", err)
+	}
+
+	query := repository.TabularQuery{Filters: filters}
+	for _, field := range strings.Split(*fields, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			query.Fields = append(query.Fields, field)
+		}
+	}
+	if *from != "" {
+		if query.WrittenFrom, err = time.Parse(time.RFC3339, *from); err != nil {
+			log.Fatalf("[export-tabular.main] Invalid -from: %v", err)
+		}
+	}
+	if *until != "" {
+		if query.WrittenUntil, err = time.Parse(time.RFC3339, *until); err != nil {
+			log.Fatalf("[export-tabular.main] Invalid -until: %v", err)
+		}
+	}
+
+	if err := tenant.Validate(*tenantID); err != nil {
+		log.Fatalf("[export-tabular.main] %v", err)
+	}
+	ctx := tenant.WithTenant(context.Background(), *tenantID)
+
+	postgresRepo, err := dbcommons.GetPostgresRepository(ctx)
+	if err != nil {
+		log.Fatalf("[export-tabular.main] Failed to create PostgreSQL repository: %v", err)
+	}
+	defer postgresRepo.Close()
+
+	exporter := engine.NewTabularExporter(nil, nil, postgresRepo)
+	export, err := exporter.Export(ctx, *entityID, *attrName, query)
+	if err != nil {
+		log.Fatalf("[export-tabular.main] Failed to export tabular attribute: %v", err)
+	}
+
+	output := os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("[export-tabular.main] Failed to create output file: %v", err)
+		}
+		defer file.Close()
+		output = file
+	}
+
+	// The table is read and written a page at a time
+	writer, err := tabularexport.NewWriter(output, export.Columns, format)
+	if err != nil {
+		log.Fatalf("[export-tabular.main] Failed to write %s: %v", format, err)
+	}
+	for {
+		rows, err := export.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatalf("[export-tabular.main] Failed to export tabular attribute: %v", err)
+		}
+		if err := writer.WriteRows(rows); err != nil {
+			log.Fatalf("[export-tabular.main] Failed to write %s: %v", format, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		log.Fatalf("[export-tabular.main] Failed to write %s: %v", format, err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d rows and %d columns of %s of %s\n", export.RowCount, len(export.Columns), *attrName, *entityID)
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+
+	mongorepository "lk/datafoundation/core-api/db/repository/mongo"
+	neo4jrepository "lk/datafoundation/core-api/db/repository/neo4j"
+	engine "lk/datafoundation/core-api/engine"
+	"lk/datafoundation/core-api/pkg/bulkimport"
+)
+
+// importer loads node and edge files through the same repository handlers used by the CORE service
+type importer struct {
+	neo4jRepo *neo4jrepository.Neo4jRepository
+	mongoRepo *mongorepository.MongoRepository
+	processor *engine.EntityAttributeProcessor
+	report    *bulkimport.RejectReport
+	batchSize int
+	workers   int
+	dryRun    bool
+}
+
+// stats summarises the outcome of importing one file
+type stats struct {
+	imported int
+	skipped  int
+	rejected int
+}
+
+// rowHandler imports a single row, returning the id it refers to.
+// When verifyExisting is set the handler first checks whether the row was already imported.
+type rowHandler func(ctx context.Context, row bulkimport.Row, verifyExisting bool) (id string, skipped bool, err error)
+
+// importFile streams a file in batches, skipping the rows already recorded in the checkpoint.
+// onBatch is called with the number of rows completed after every batch.
+func (im *importer) importFile(ctx context.Context, path string, format string, done int, handle rowHandler, onBatch func(done int) error) (stats, error) {
+	var result stats
+
+	file, err := os.Open(path)
+	if err != nil {
+		return result, fmt.Errorf("error opening %s: %v", path, err)
+	}
+	defer file.Close()
+
+	reader, err := bulkimport.NewReader(file, path, format)
+	if err != nil {
+		return result, err
+	}
+
+	// Skip the rows completed by a previous run
+	for i := 0; i < done; i++ {
+		row, err := reader.Next()
+		if err == io.EOF {
+			return result, fmt.Errorf("checkpoint records %d rows but %s has only %d", done, path, i)
+		}
+		if errors.Is(err, bulkimport.ErrFatal) {
+			return result, fmt.Errorf("%s line %d: %v", path, row.Line, err)
+		}
+	}
+	if done > 0 {
+		log.Printf("[import.importFile] Resuming %s after %d rows", path, done)
+	}
+
+	// The batch that was in flight when a previous run stopped may be partly imported
+	verifyExisting := done > 0
+
+	for {
+		batch, readErr := readBatch(reader, im.batchSize)
+		if len(batch) > 0 {
+			batchStats, err := im.processBatch(ctx, path, batch, handle, verifyExisting)
+			if err != nil {
+				return result, err
+			}
+			result.imported += batchStats.imported
+			result.skipped += batchStats.skipped
+			result.rejected += batchStats.rejected
+
+			done += len(batch)
+			if err := onBatch(done); err != nil {
+				return result, err
+			}
+			log.Printf("[import.importFile] %s: %d rows done (%d imported, %d skipped, %d rejected)", path, done, result.imported, result.skipped, result.rejected)
+		}
+		verifyExisting = false
+
+		if readErr == io.EOF {
+			return result, nil
+		}
+		if readErr != nil {
+			return result, fmt.Errorf("%s: %v", path, readErr)
+		}
+	}
+}
+
+// batchRow is a row read from a file, or the error encountered reading it
+type batchRow struct {
+	row bulkimport.Row
+	err error
+}
+
+// readBatch reads up to size rows. Unreadable rows are kept so they can be reported.
+// The batch ends with io.EOF at the end of the input, or with the error after which nothing more can be read.
+func readBatch(reader bulkimport.RowReader, size int) ([]batchRow, error) {
+	batch := make([]batchRow, 0, size)
+	for len(batch) < size {
+		row, err := reader.Next()
+		if err == io.EOF {
+			return batch, io.EOF
+		}
+		if errors.Is(err, bulkimport.ErrFatal) {
+			return batch, fmt.Errorf("line %d: %v", row.Line, err)
+		}
+		batch = append(batch, batchRow{row: row, err: err})
+	}
+	return batch, nil
+}
+
+// processBatch imports the rows of a batch concurrently and records the rejected ones
+func (im *importer) processBatch(ctx context.Context, path string, batch []batchRow, handle rowHandler, verifyExisting bool) (stats, error) {
+	var (
+		mu        sync.Mutex
+		result    stats
+		reportErr error
+		wg        sync.WaitGroup
+	)
+	semaphore := make(chan struct{}, im.workers)
+
+	for _, item := range batch {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(item batchRow) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			id, skipped, err := "", false, item.err
+			if err == nil {
+				id, skipped, err = handle(ctx, item.row, verifyExisting)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				result.rejected++
+				if addErr := im.report.Add(bulkimport.Rejection{File: path, Line: item.row.Line, Id: id, Reason: err.Error()}); addErr != nil {
+					reportErr = addErr
+				}
+			case skipped:
+				result.skipped++
+			default:
+				result.imported++
+			}
+		}(item)
+	}
+	wg.Wait()
+
+	return result, reportErr
+}
+
+// importNode validates a node row and creates the entity and its metadata
+func (im *importer) importNode(mapping bulkimport.Mapping) rowHandler {
+	return func(ctx context.Context, row bulkimport.Row, verifyExisting bool) (string, bool, error) {
+		record, err := bulkimport.ParseNode(row, mapping)
+		if err != nil {
+			return row.Value(mapping[bulkimport.FieldId]), false, err
+		}
+		if im.dryRun {
+			return record.Id, false, nil
+		}
+
+		if verifyExisting {
+			if existing, err := im.neo4jRepo.ReadGraphEntity(ctx, record.Id); err == nil && existing != nil {
+				return record.Id, true, nil
+			}
+		}
+
+		entity := record.ToEntity()
+		success, err := im.neo4jRepo.HandleGraphEntityCreation(ctx, entity)
+		if !success {
+			return record.Id, false, fmt.Errorf("error saving entity in Neo4j: %v", err)
+		}
+
+		if err := im.mongoRepo.HandleMetadata(ctx, entity.Id, entity); err != nil {
+			return record.Id, false, fmt.Errorf("error saving metadata in MongoDB: %v", err)
+		}
+
+		// Attributes go through the attribute processor, which stores tabular data in PostgreSQL
+		if len(entity.Attributes) > 0 {
+			for attrName, result := range im.processor.ProcessEntityAttributes(ctx, entity, "create", nil) {
+				if !result.Success || result.Error != nil {
+					return record.Id, false, fmt.Errorf("error saving attribute %s: %v", attrName, result.Error)
+				}
+			}
+		}
+
+		return record.Id, false, nil
+	}
+}
+
+// importEdge validates an edge row and creates the relationship
+func (im *importer) importEdge(mapping bulkimport.Mapping) rowHandler {
+	return func(ctx context.Context, row bulkimport.Row, verifyExisting bool) (string, bool, error) {
+		record, err := bulkimport.ParseEdge(row, mapping)
+		if err != nil {
+			return row.Value(mapping[bulkimport.FieldId]), false, err
+		}
+		if im.dryRun {
+			return record.Id, false, nil
+		}
+
+		if verifyExisting {
+			if existing, err := im.neo4jRepo.ReadRelationship(ctx, record.Id); err == nil && existing != nil {
+				return record.Id, true, nil
+			}
+		}
+
+		if err := im.neo4jRepo.HandleGraphRelationshipsCreate(ctx, record.ToEntity()); err != nil {
+			return record.Id, false, err
+		}
+
+		return record.Id, false, nil
+	}
+}
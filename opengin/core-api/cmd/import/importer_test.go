@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"lk/datafoundation/core-api/pkg/bulkimport"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportFileStopsOnFatalReadError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nodes.jsonl")
+	input := `{"id": "e1"}` + "\n" + `{"id": "e2"}` + "\n" + strings.Repeat("x", bulkimport.MaxLineSize+1) + "\n" + `{"id": "e4"}` + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(input), 0o644))
+
+	report, err := bulkimport.NewRejectReport(io.Discard, false)
+	require.NoError(t, err)
+	im := &importer{report: report, batchSize: 2, workers: 1}
+
+	var imported []string
+	handle := func(ctx context.Context, row bulkimport.Row, verifyExisting bool) (string, bool, error) {
+		imported = append(imported, row.Value("id"))
+		return row.Value("id"), false, nil
+	}
+	done := 0
+	result, err := im.importFile(context.Background(), path, "", 0, handle, func(n int) error {
+		done = n
+		return nil
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 3")
+	assert.Equal(t, []string{"e1", "e2"}, imported)
+	assert.Equal(t, 2, result.imported)
+	assert.Equal(t, 2, done, "Expected the checkpoint to cover the rows before the unreadable line")
+}
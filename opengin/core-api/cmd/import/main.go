@@ -0,0 +1,162 @@
+// Command import bulk loads entities and relationships from CSV or JSONL files.
+//
+// Node files hold one entity per row and edge files one relationship per row. Columns named
+// metadata.<key> become entity metadata, and an attributes column may hold a JSON object of
+// attribute values. Rows are validated, then written through the same Neo4j, MongoDB and
+// attribute (PostgreSQL) handlers the CORE service uses, so imported data is indistinguishable
+// from data created through the API.
+//
+// Progress is recorded in a checkpoint file after every batch. Re-running the same command
+// resumes after the last completed batch. Rows that fail validation or loading are written
+// to a CSV report instead of stopping the import.
+//
+// Usage:
+//
+//	import -nodes entities.csv -edges relationships.jsonl [-batch-size 500] [-workers 4]
+//	       [-node-columns kind_major=type,name=label] [-edge-columns source=from,target=to]
+//	       [-checkpoint import.checkpoint.json] [-rejects import.rejects.csv] [-dry-run] [-tenant id]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	dbcommons "lk/datafoundation/core-api/commons/db"
+	"lk/datafoundation/core-api/db/repository"
+	engine "lk/datafoundation/core-api/engine"
+	"lk/datafoundation/core-api/pkg/bulkimport"
+	"lk/datafoundation/core-api/pkg/tenant"
+)
+
+func main() {
+	nodesFile := flag.String("nodes", "", "CSV or JSONL file with one entity per row")
+	edgesFile := flag.String("edges", "", "CSV or JSONL file with one relationship per row")
+	format := flag.String("format", "", "Input format (csv or jsonl), inferred from the file extension if empty")
+	nodeColumns := flag.String("node-columns", "", "Node column overrides as field=column pairs (fields: id, kind_major, kind_minor, name, created, terminated, attributes)")
+	edgeColumns := flag.String("edge-columns", "", "Edge column overrides as field=column pairs (fields: id, source, target, name, start_time, end_time)")
+	batchSize := flag.Int("batch-size", 500, "Rows per batch, the checkpoint is updated after each batch")
+	workers := flag.Int("workers", 4, "Rows imported concurrently within a batch")
+	checkpointPath := flag.String("checkpoint", "import.checkpoint.json", "Checkpoint file used to resume an interrupted import")
+	rejectsPath := flag.String("rejects", "import.rejects.csv", "CSV report of rejected rows")
+	dryRun := flag.Bool("dry-run", false, "Validate the files without writing to the databases")
+	tenantID := flag.String("tenant", tenant.Default, "Tenant whose data is read and written")
+	flag.Parse()
+
+	if *nodesFile == "" && *edgesFile == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *batchSize <= 0 || *workers <= 0 {
+		log.Fatalf("[import.main] batch-size and workers must be positive")
+	}
+
+	nodeMapping, err := bulkimport.ParseMapping(*nodeColumns, bulkimport.DefaultNodeMapping())
+	if err != nil {
+		log.Fatalf("[import.main] %v", err)
+	}
+	edgeMapping, err := bulkimport.ParseMapping(*edgeColumns, bulkimport.DefaultEdgeMapping())
+	if err != nil {
+		log.Fatalf("[import.main] %v", err)
+	}
+
+	checkpoint, err := bulkimport.LoadCheckpoint(*checkpointPath)
+	if err != nil {
+		log.Fatalf("[import.main] %v", err)
+	}
+	if !checkpoint.Matches(*nodesFile, *edgesFile) {
+		log.Fatalf("[import.main] checkpoint %s belongs to another import (%s, %s), remove it to start over", *checkpointPath, checkpoint.NodesFile, checkpoint.EdgesFile)
+	}
+	checkpoint.NodesFile = *nodesFile
+	checkpoint.EdgesFile = *edgesFile
+
+	// Rejections from earlier runs are kept when resuming, new ones are appended
+	resuming := checkpoint.NodesDone > 0 || checkpoint.EdgesDone > 0
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if resuming {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	rejectsFile, err := os.OpenFile(*rejectsPath, flags, 0644)
+	if err != nil {
+		log.Fatalf("[import.main] Failed to open reject report: %v", err)
+	}
+	defer rejectsFile.Close()
+
+	info, err := rejectsFile.Stat()
+	if err != nil {
+		log.Fatalf("[import.main] Failed to open reject report: %v", err)
+	}
+
+	report, err := bulkimport.NewRejectReport(rejectsFile, info.Size() == 0)
+	if err != nil {
+		log.Fatalf("[import.main] %v", err)
+	}
+
+	if err := tenant.Validate(*tenantID); err != nil {
+		log.Fatalf("[import.main] %v", err)
+	}
+	ctx := tenant.WithTenant(context.Background(), *tenantID)
+	im := &importer{
+		report:    report,
+		batchSize: *batchSize,
+		workers:   *workers,
+		dryRun:    *dryRun,
+	}
+
+	if !*dryRun {
+		neo4jRepo, err := dbcommons.GetNeo4jRepository(ctx)
+		if err != nil {
+			log.Fatalf("[import.main] Failed to create Neo4j repository: %v", err)
+		}
+		defer neo4jRepo.Close(ctx)
+		im.neo4jRepo = neo4jRepo
+		im.mongoRepo = dbcommons.GetMongoRepository(ctx)
+
+		// Tabular attributes are stored in PostgreSQL, which is only needed when the files carry them
+		var tabularStore repository.TabularStore
+		postgresRepo, err := dbcommons.GetPostgresRepository(ctx)
+		if err != nil {
+			log.Printf("[import.main] Tabular attributes cannot be imported: %v", err)
+		} else {
+			defer postgresRepo.Close()
+			tabularStore = postgresRepo
+		}
+		im.processor = engine.NewEntityAttributeProcessor(neo4jRepo, im.mongoRepo, tabularStore)
+	}
+
+	saveCheckpoint := func() error {
+		if *dryRun {
+			return nil
+		}
+		return checkpoint.Save(*checkpointPath)
+	}
+
+	// Nodes go first so every relationship can find both of its ends
+	if *nodesFile != "" {
+		result, err := im.importFile(ctx, *nodesFile, *format, checkpoint.NodesDone, im.importNode(nodeMapping), func(done int) error {
+			checkpoint.NodesDone = done
+			return saveCheckpoint()
+		})
+		if err != nil {
+			log.Fatalf("[import.main] Failed to import nodes: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Nodes: %d imported, %d already present, %d rejected\n", result.imported, result.skipped, result.rejected)
+	}
+
+	if *edgesFile != "" {
+		result, err := im.importFile(ctx, *edgesFile, *format, checkpoint.EdgesDone, im.importEdge(edgeMapping), func(done int) error {
+			checkpoint.EdgesDone = done
+			return saveCheckpoint()
+		})
+		if err != nil {
+			log.Fatalf("[import.main] Failed to import edges: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Edges: %d imported, %d already present, %d rejected\n", result.imported, result.skipped, result.rejected)
+	}
+
+	if report.Count() > 0 {
+		fmt.Fprintf(os.Stderr, "%d rows rejected, see %s\n", report.Count(), *rejectsPath)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/auth"
+	"lk/datafoundation/core-api/pkg/graphexport"
+)
+
+// authorizeEntity checks that the caller may touch an existing entity.
+// The entity's kind is only looked up for callers limited to some kinds.
+func (s *Server) authorizeEntity(ctx context.Context, entityID string) error {
+	if !auth.KindRestricted(ctx) {
+		return nil
+	}
+	kind, _, _, _, err := s.graphStore.GetGraphEntity(ctx, entityID)
+	if err != nil || kind == nil {
+		// Unknown entities are refused rather than reported, so kinds cannot be probed
+		return auth.AuthorizeKind(ctx, "")
+	}
+	return auth.AuthorizeKind(ctx, kind.Major)
+}
+
+// visibleSubgraph drops the entities the caller may not see, and the relationships touching them
+func visibleSubgraph(ctx context.Context, graph *graphexport.Subgraph) {
+	principal := auth.PrincipalFromContext(ctx)
+	if principal == nil || len(principal.Kinds) == 0 {
+		return
+	}
+
+	visible := make(map[string]bool, len(graph.Entities))
+	entities := graph.Entities[:0]
+	for _, entity := range graph.Entities {
+		if principal.AllowsKind(entity.MajorKind) {
+			visible[entity.Id] = true
+			entities = append(entities, entity)
+		}
+	}
+	graph.Entities = entities
+
+	relationships := graph.Relationships[:0]
+	for _, relationship := range graph.Relationships {
+		if visible[relationship.SourceId] && visible[relationship.TargetId] {
+			relationships = append(relationships, relationship)
+		}
+	}
+	graph.Relationships = relationships
+}
+
+// visiblePaths drops the paths passing through an entity the caller may not see, as visibleSubgraph
+// drops such entities, so the paths reveal no ids or kinds of other entities
+func visiblePaths(ctx context.Context, paths []*pb.EntityPath) []*pb.EntityPath {
+	principal := auth.PrincipalFromContext(ctx)
+	if principal == nil || len(principal.Kinds) == 0 {
+		return paths
+	}
+
+	visible := paths[:0]
+	for _, path := range paths {
+		allowed := true
+		for _, entity := range path.Entities {
+			if !principal.AllowsKind(entity.GetKind().GetMajor()) {
+				allowed = false
+				break
+			}
+		}
+		if allowed {
+			visible = append(visible, path)
+		}
+	}
+	return visible
+}
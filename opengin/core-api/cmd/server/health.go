@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// storeNames are the health service names of the individual stores
+var storeNames = []string{"graph", "metadata", "tabular"}
+
+// storeHealth drives the grpc.health.v1 statuses from the store probes.
+// The overall status ("" and core.COREService) is SERVING only while every store answers;
+// each store is also reported under its own name so a failing dependency can be told apart.
+type storeHealth struct {
+	server *Server
+	health *health.Server
+}
+
+// newStoreHealth creates the health service with every status NOT_SERVING until the first probe passes
+func newStoreHealth(server *Server) *storeHealth {
+	h := &storeHealth{
+		server: server,
+		health: health.NewServer(),
+	}
+	for _, name := range append([]string{"", pb.COREService_ServiceDesc.ServiceName}, storeNames...) {
+		h.health.SetServingStatus(name, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	return h
+}
+
+// update probes the stores, publishes their statuses and reports whether all of them are up
+func (h *storeHealth) update(ctx context.Context) map[string]error {
+	failures := h.server.CheckStores(ctx)
+	for _, name := range storeNames {
+		h.health.SetServingStatus(name, servingStatus(failures[name] == nil))
+	}
+
+	overall := servingStatus(len(failures) == 0)
+	h.health.SetServingStatus("", overall)
+	h.health.SetServingStatus(pb.COREService_ServiceDesc.ServiceName, overall)
+	return failures
+}
+
+// waitUntilReady probes the stores every second until they all respond or the timeout passes
+func (h *storeHealth) waitUntilReady(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		failures := h.update(ctx)
+		if len(failures) == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stores not ready after %s: %v", timeout, failures)
+		case <-time.After(time.Second):
+			slog.InfoContext(ctx, "Waiting for stores", "failures", failureMessages(failures))
+		}
+	}
+}
+
+// watch keeps probing the stores until ctx is cancelled, logging every change of readiness
+func (h *storeHealth) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	ready := true
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			probeCtx, cancel := context.WithTimeout(ctx, interval)
+			failures := h.update(probeCtx)
+			cancel()
+			if ctx.Err() != nil {
+				return
+			}
+
+			if len(failures) > 0 && ready {
+				slog.WarnContext(ctx, "Not ready, stores failing", "failures", failureMessages(failures))
+			} else if len(failures) == 0 && !ready {
+				slog.InfoContext(ctx, "Ready again, all stores respond")
+			}
+			ready = len(failures) == 0
+		}
+	}
+}
+
+// shutdown reports NOT_SERVING for everything; later probes no longer change the statuses
+func (h *storeHealth) shutdown() {
+	h.health.Shutdown()
+}
+
+// failureMessages turns probe errors into strings so they survive JSON logging
+func failureMessages(failures map[string]error) map[string]string {
+	messages := make(map[string]string, len(failures))
+	for name, err := range failures {
+		messages[name] = err.Error()
+	}
+	return messages
+}
+
+func servingStatus(up bool) healthpb.HealthCheckResponse_ServingStatus {
+	if up {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
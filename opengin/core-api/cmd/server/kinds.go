@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"lk/datafoundation/core-api/db/repository"
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/auth"
+	"lk/datafoundation/core-api/pkg/kinds"
+	"lk/datafoundation/core-api/pkg/tenant"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// kindRegistryTTL bounds how long a server keeps validating against a registry another server has changed
+const kindRegistryTTL = 30 * time.Second
+
+// kindRegistryCache keeps the kind registry of each tenant so writes do not read it every time
+type kindRegistryCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedKindRegistry
+}
+
+type cachedKindRegistry struct {
+	registry kinds.Registry
+	loaded   time.Time
+}
+
+// kindRegistry returns the kind registry of the call's tenant
+func (s *Server) kindRegistry(ctx context.Context) (kinds.Registry, error) {
+	id := tenant.FromContext(ctx)
+	s.kindCache.mu.Lock()
+	entry, ok := s.kindCache.entries[id]
+	s.kindCache.mu.Unlock()
+	if ok && time.Since(entry.loaded) < kindRegistryTTL {
+		return entry.registry, nil
+	}
+
+	definitions, err := s.kindStore.ListKinds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	registry := kinds.NewRegistry(definitions)
+
+	s.kindCache.mu.Lock()
+	s.kindCache.entries[id] = cachedKindRegistry{registry: registry, loaded: time.Now()}
+	s.kindCache.mu.Unlock()
+	return registry, nil
+}
+
+// forgetKindRegistry drops the cached registry of the call's tenant after it changed
+func (s *Server) forgetKindRegistry(ctx context.Context) {
+	s.kindCache.mu.Lock()
+	delete(s.kindCache.entries, tenant.FromContext(ctx))
+	s.kindCache.mu.Unlock()
+}
+
+// validateGraphIdentifiers refuses kinds and relationship names that cannot be Neo4j labels and relationship types,
+// before any store is written to
+func validateGraphIdentifiers(entity *pb.Entity) error {
+	if major := entity.GetKind().GetMajor(); major != "" {
+		if err := repository.ValidateGraphIdentifier("kind", major); err != nil {
+			return err
+		}
+	}
+	for _, relationship := range entity.GetRelationships() {
+		if relationship.GetName() == "" {
+			continue
+		}
+		if err := repository.ValidateGraphIdentifier("relationship name", relationship.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkKind validates a new entity, or the update of a stored one, against the kind registry of the tenant.
+// In warn mode the violations are only logged.
+func (s *Server) checkKind(ctx context.Context, entity *pb.Entity, create bool) error {
+	if s.kindMode == kinds.Off || s.kindStore == nil {
+		return nil
+	}
+	registry, err := s.kindRegistry(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error reading the kind registry", "error", err)
+		return status.Errorf(codes.Unavailable, "error reading the kind registry: %v", err)
+	}
+	if len(registry) == 0 {
+		return nil
+	}
+
+	// Updates do not carry the kind, the stored one applies
+	kind := entity.GetKind()
+	if !create {
+		if kind, _, _, _, err = s.graphStore.GetGraphEntity(ctx, entity.Id); err != nil {
+			// The update fails on its own for entities that do not exist
+			return nil
+		}
+	}
+
+	violations := registry.Check(entity, kind, create, func(entityID string) (string, error) {
+		related, _, _, _, err := s.graphStore.GetGraphEntity(ctx, entityID)
+		return related.GetMajor(), err
+	})
+	if len(violations) == 0 {
+		return nil
+	}
+
+	if s.kindMode == kinds.Warn {
+		for _, violation := range violations {
+			slog.WarnContext(ctx, "Entity does not match the kind registry", "entity_id", entity.Id, "kind", kind.GetMajor(), "violation", violation)
+		}
+		return nil
+	}
+	slog.WarnContext(ctx, "Rejected entity not matching the kind registry", "entity_id", entity.Id, "kind", kind.GetMajor(), "violations", len(violations))
+	return status.Errorf(codes.InvalidArgument, "entity %s does not match the kind registry: %s", entity.Id, strings.Join(violations, "; "))
+}
+
+// PutKind registers a major kind or replaces its definition
+func (s *Server) PutKind(ctx context.Context, req *pb.KindDefinition) (*pb.KindDefinition, error) {
+	if s.kindStore == nil {
+		return nil, status.Error(codes.Unimplemented, "the metadata store cannot keep a kind registry")
+	}
+	if err := kinds.ValidateDefinition(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	slog.InfoContext(ctx, "Registering kind", "kind", req.Major)
+	if err := s.kindStore.PutKind(ctx, req); err != nil {
+		return nil, err
+	}
+	s.forgetKindRegistry(ctx)
+	return req, nil
+}
+
+// ListKinds returns the kind registry of the tenant, without the kinds the caller may not see
+func (s *Server) ListKinds(ctx context.Context, req *pb.Empty) (*pb.KindList, error) {
+	if s.kindStore == nil {
+		return nil, status.Error(codes.Unimplemented, "the metadata store cannot keep a kind registry")
+	}
+	definitions, err := s.kindStore.ListKinds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &pb.KindList{Mode: string(s.kindMode)}
+	principal := auth.PrincipalFromContext(ctx)
+	for _, def := range definitions {
+		if principal == nil || principal.AllowsKind(def.Major) {
+			response.Kinds = append(response.Kinds, def)
+		}
+	}
+	sort.Slice(response.Kinds, func(i, j int) bool { return response.Kinds[i].Major < response.Kinds[j].Major })
+	return response, nil
+}
+
+// DeleteKind removes a major kind from the registry. Entities of the kind are kept.
+func (s *Server) DeleteKind(ctx context.Context, req *pb.KindRequest) (*pb.Empty, error) {
+	if s.kindStore == nil {
+		return nil, status.Error(codes.Unimplemented, "the metadata store cannot keep a kind registry")
+	}
+	if req.Major == "" {
+		return nil, status.Error(codes.InvalidArgument, "major is required")
+	}
+
+	slog.InfoContext(ctx, "Removing kind", "kind", req.Major)
+	if err := s.kindStore.DeleteKind(ctx, req.Major); err != nil {
+		return nil, err
+	}
+	s.forgetKindRegistry(ctx)
+	return &pb.Empty{}, nil
+}
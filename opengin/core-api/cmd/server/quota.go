@@ -0,0 +1,21 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// reserveEntity refuses a new entity when the tenant holds, or is creating, as many as its quota allows.
+// Otherwise it reserves room for the entity until release is called, once the entity is stored.
+// The entities are only counted for tenants with an entity quota.
+func (s *Server) reserveEntity(ctx context.Context) (release func(), err error) {
+	return s.quotas.ReserveEntity(ctx, func(ctx context.Context) (int64, error) {
+		count, err := s.graphStore.CountEntities(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error counting entities", "error", err)
+			return 0, fmt.Errorf("error checking the entity quota: %v", err)
+		}
+		return count, nil
+	})
+}
@@ -1,22 +1,51 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
-	"lk/datafoundation/core-api/db/config"
+	dbcommons "lk/datafoundation/core-api/commons/db"
+	"lk/datafoundation/core-api/db/repository"
 	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
 
+	memoryrepository "lk/datafoundation/core-api/db/repository/memory"
 	mongorepository "lk/datafoundation/core-api/db/repository/mongo"
 	neo4jrepository "lk/datafoundation/core-api/db/repository/neo4j"
 	postgres "lk/datafoundation/core-api/db/repository/postgres"
 	engine "lk/datafoundation/core-api/engine"
-
+	"lk/datafoundation/core-api/pkg/auth"
+	"lk/datafoundation/core-api/pkg/consistency"
+	"lk/datafoundation/core-api/pkg/graphexport"
+	"lk/datafoundation/core-api/pkg/kinds"
+	"lk/datafoundation/core-api/pkg/logging"
+	"lk/datafoundation/core-api/pkg/metrics"
+	"lk/datafoundation/core-api/pkg/objectstore"
+	"lk/datafoundation/core-api/pkg/recovery"
+	"lk/datafoundation/core-api/pkg/storageinference"
+	"lk/datafoundation/core-api/pkg/tabularexport"
+	"lk/datafoundation/core-api/pkg/tenant"
+	"lk/datafoundation/core-api/pkg/tracing"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/structpb"
 )
@@ -24,70 +53,136 @@ import (
 // Server implements the COREService
 type Server struct {
 	pb.UnimplementedCOREServiceServer
-	mongoRepo    *mongorepository.MongoRepository
-	neo4jRepo    *neo4jrepository.Neo4jRepository
-	postgresRepo *postgres.PostgresRepository
+	graphStore    repository.GraphStore
+	metadataStore repository.MetadataStore
+	tabularStore  repository.TabularStore
+	processor     *engine.EntityAttributeProcessor
+	blobResolver  *engine.BlobAttributeResolver
+	quotas        *tenant.Quotas
+	kindStore     repository.KindStore // Nil when the metadata store cannot keep a kind registry
+	kindMode      kinds.Mode
+	kindCache     *kindRegistryCache
+}
+
+// NewServer creates a server backed by the given stores.
+// Blob attributes are unavailable when objectStore is nil.
+func NewServer(graphStore repository.GraphStore, metadataStore repository.MetadataStore, tabularStore repository.TabularStore, objectStore objectstore.Store) *Server {
+	kindStore, _ := metadataStore.(repository.KindStore)
+	return &Server{
+		graphStore:    graphStore,
+		metadataStore: metadataStore,
+		tabularStore:  tabularStore,
+		processor:     engine.NewEntityAttributeProcessor(graphStore, metadataStore, tabularStore),
+		blobResolver:  engine.NewBlobAttributeResolver(objectStore, graphStore, metadataStore),
+		kindStore:     kindStore,
+		kindMode:      kinds.Enforce,
+		kindCache:     &kindRegistryCache{entries: make(map[string]cachedKindRegistry)},
+	}
 }
 
 // CreateEntity handles entity creation with relationships, metadata and attributes
 func (s *Server) CreateEntity(ctx context.Context, req *pb.Entity) (*pb.Entity, error) {
-	log.Printf("Creating Entity: %s", req.Id)
+	slog.InfoContext(ctx, "Creating entity", "entity_id", req.Id)
+
+	if err := validateGraphIdentifiers(req); err != nil {
+		return nil, err
+	}
+	if err := auth.AuthorizeKind(ctx, req.GetKind().GetMajor()); err != nil {
+		return nil, err
+	}
+	release, err := s.reserveEntity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if err := s.checkKind(ctx, req, true); err != nil {
+		return nil, err
+	}
+	if err := s.processor.AuthorizeAccessChanges(ctx, req); err != nil {
+		return nil, err
+	}
 
 	// Validate required fields for Neo4j entity creation
-	success, err := s.neo4jRepo.HandleGraphEntityCreation(ctx, req)
+	success, err := s.graphStore.HandleGraphEntityCreation(ctx, req)
+	// Once stored the entity is counted, so its reservation is no longer needed
+	release()
 	if !success {
-		log.Printf("[server.CreateEntity] Error saving entity in Neo4j: %v", err)
+		slog.ErrorContext(ctx, "Error saving entity in Neo4j", "error", err)
 		return nil, err
 	} else {
-		log.Printf("[server.CreateEntity] Successfully saved entity in Neo4j for entity: %s", req.Id)
+		slog.DebugContext(ctx, "Saved entity in Neo4j", "entity_id", req.Id)
 	}
 
 	// Handle relationships
-	err = s.neo4jRepo.HandleGraphRelationshipsCreate(ctx, req)
+	err = s.graphStore.HandleGraphRelationshipsCreate(ctx, req)
 	if err != nil {
-		log.Printf("[server.CreateEntity] Error saving relationships in Neo4j: %v", err)
+		slog.ErrorContext(ctx, "Error saving relationships in Neo4j", "error", err)
 		return nil, err
 	} else {
-		log.Printf("[server.CreateEntity] Successfully saved relationships in Neo4j for entity: %s", req.Id)
+		slog.DebugContext(ctx, "Saved relationships in Neo4j", "entity_id", req.Id)
 	}
 
 	// The HandleMetadata function will only process it if it has metadata
 	// If metadata is not provided, a document will not be created in MongoDB
 	// FIXME: https://github.com/LDFLK/nexoan/issues/120
-	err = s.mongoRepo.HandleMetadata(ctx, req.Id, req)
+	err = s.metadataStore.HandleMetadata(ctx, req.Id, req)
 	if err != nil {
-		log.Printf("[server.CreateEntity] Error saving metadata in MongoDB: %v", err)
+		slog.ErrorContext(ctx, "Error saving metadata in MongoDB", "error", err)
 		return nil, err
 	} else {
-		log.Printf("[server.CreateEntity] Successfully saved metadata in MongoDB for entity: %s", req.Id)
+		slog.DebugContext(ctx, "Saved metadata in MongoDB", "entity_id", req.Id)
 	}
 
 	// Handle attributes
-	processor := engine.NewEntityAttributeProcessor()
-	attributeResults := processor.ProcessEntityAttributes(ctx, req, "create", nil)
+	attributeResults := s.processor.ProcessEntityAttributes(ctx, req, "create", nil)
 
 	// Check if any attributes failed
 	hasErrors := false
 	for attrName, result := range attributeResults {
 		if !result.Success || result.Error != nil {
-			log.Printf("[server.CreateEntity] Error handling attribute %s: %v", attrName, result.Error)
+			slog.ErrorContext(ctx, "Error handling attribute", "entity_id", req.Id, "attribute", attrName, "error", result.Error)
 			hasErrors = true
 		} else {
-			log.Printf("[server.CreateEntity] Successfully handled attribute %s for entity: %s", attrName, req.Id)
+			slog.DebugContext(ctx, "Handled attribute", "entity_id", req.Id, "attribute", attrName)
 		}
 	}
 
 	if hasErrors {
-		log.Printf("[server.CreateEntity] Some attributes failed to process")
+		slog.ErrorContext(ctx, "Some attributes failed to process", "entity_id", req.Id)
 		return nil, fmt.Errorf("some attributes failed to process")
 	}
 
+	// Attributes given as tabular files are returned as the report of how they were read
+	for attrName, reports := range ingestReports(attributeResults) {
+		req.Attributes[attrName] = reports
+	}
+
 	return req, nil
 }
 
+// ingestReports returns the tabular ingest reports of the attributes that were given as files
+func ingestReports(attributeResults map[string]*engine.Result) map[string]*pb.TimeBasedValueList {
+	reports := make(map[string]*pb.TimeBasedValueList)
+	for attrName, result := range attributeResults {
+		if list, ok := result.Data.(*pb.TimeBasedValueList); ok {
+			reports[attrName] = list
+		}
+	}
+	return reports
+}
+
 // ReadEntity retrieves an entity's metadata
 func (s *Server) ReadEntity(ctx context.Context, req *pb.ReadEntityRequest) (*pb.Entity, error) {
-	log.Printf("Reading Entity: %s with output fields: %v", req.Entity.Id, req.Output)
+	slog.InfoContext(ctx, "Reading entity", "entity_id", req.Entity.Id, "output", req.Output)
+
+	tabularFormat, err := repository.ParseTabularFormat(req.TabularFormat)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.authorizeEntity(ctx, req.Entity.Id); err != nil {
+		return nil, err
+	}
 
 	// Initialize a complete response entity with empty fields
 	response := &pb.Entity{
@@ -102,9 +197,9 @@ func (s *Server) ReadEntity(ctx context.Context, req *pb.ReadEntityRequest) (*pb
 	}
 
 	// Always fetch basic entity info from Neo4j
-	kind, name, created, terminated, err := s.neo4jRepo.GetGraphEntity(ctx, req.Entity.Id)
+	kind, name, created, terminated, err := s.graphStore.GetGraphEntity(ctx, req.Entity.Id)
 	if err != nil {
-		log.Printf("Error fetching entity info: %v", err)
+		slog.ErrorContext(ctx, "Error fetching entity info", "error", err)
 		return nil, fmt.Errorf("error fetching entity info: %v", err)
 	} else {
 		response.Kind = kind
@@ -115,24 +210,24 @@ func (s *Server) ReadEntity(ctx context.Context, req *pb.ReadEntityRequest) (*pb
 
 	// If no output fields specified, return the entity with basic info
 	if len(req.Output) == 0 {
-		log.Printf("Returning entity from ReadEntity: %+v", response)
+		slog.DebugContext(ctx, "Returning entity from ReadEntity", "response", logging.Payload(response))
 		return response, nil
 	}
 
 	// Process each requested output field
 	for _, field := range req.Output {
-		log.Printf("[DEBUG] Entering switch statement for entity ID: %s", req.Entity.Id)
+		slog.DebugContext(ctx, "Reading output fields", "entity_id", req.Entity.Id)
 		switch field {
 		case "metadata":
-			log.Printf("[DEBUG] Processing metadata field for entity ID: %s", req.Entity.Id)
+			slog.DebugContext(ctx, "Processing metadata field", "entity_id", req.Entity.Id)
 			// Get metadata from MongoDB
-			metadata, err := s.mongoRepo.GetMetadata(ctx, req.Entity.Id)
+			metadata, err := s.metadataStore.GetMetadata(ctx, req.Entity.Id)
 			if err != nil {
-				log.Printf("Error fetching metadata: %v", err)
+				slog.ErrorContext(ctx, "Error fetching metadata", "error", err)
 				return nil, fmt.Errorf("error fetching metadata: %v", err)
 			} else {
-				log.Printf("[DEBUG] Retrieved metadata: %+v", metadata)
-				response.Metadata = metadata
+				slog.DebugContext(ctx, "Retrieved metadata", "metadata", logging.Payload(metadata))
+				response.Metadata = engine.MaskMetadata(ctx, metadata)
 			}
 
 		case "relationships":
@@ -140,9 +235,9 @@ func (s *Server) ReadEntity(ctx context.Context, req *pb.ReadEntityRequest) (*pb
 			if req.Entity != nil {
 				if len(req.Entity.Relationships) == 0 {
 					// No filters provided, fetch all relationships for the entity
-					filteredRels, err := s.neo4jRepo.GetFilteredRelationships(ctx, req.Entity.Id, "", "", "", "", "", "", req.ActiveAt)
+					filteredRels, err := s.graphStore.GetFilteredRelationships(ctx, req.Entity.Id, "", "", "", "", "", "", req.ActiveAt)
 					if err != nil {
-						log.Printf("Error fetching related entity IDs for entity %s: %v", req.Entity.Id, err)
+						slog.ErrorContext(ctx, "Error fetching related entity IDs", "entity_id", req.Entity.Id, "error", err)
 						return nil, fmt.Errorf("error fetching related entity IDs: %v", err)
 					} else {
 						for id, relationship := range filteredRels {
@@ -152,10 +247,10 @@ func (s *Server) ReadEntity(ctx context.Context, req *pb.ReadEntityRequest) (*pb
 				} else {
 					// Call GetFilteredRelationships for each relationship
 					for _, rel := range req.Entity.Relationships {
-						log.Printf("Fetching related entity IDs for entity %s with relationship %s and start time %s", req.Entity.Id, rel.Name, rel.StartTime)
-						filteredRels, err := s.neo4jRepo.GetFilteredRelationships(ctx, req.Entity.Id, rel.Id, rel.Name, rel.RelatedEntityId, rel.StartTime, rel.EndTime, rel.Direction, req.ActiveAt)
+						slog.DebugContext(ctx, "Fetching related entity IDs", "entity_id", req.Entity.Id, "relationship", rel.Name, "start_time", rel.StartTime)
+						filteredRels, err := s.graphStore.GetFilteredRelationships(ctx, req.Entity.Id, rel.Id, rel.Name, rel.RelatedEntityId, rel.StartTime, rel.EndTime, rel.Direction, req.ActiveAt)
 						if err != nil {
-							log.Printf("Error fetching related entity IDs for entity %s: %v", req.Entity.Id, err)
+							slog.ErrorContext(ctx, "Error fetching related entity IDs", "entity_id", req.Entity.Id, "error", err)
 							return nil, fmt.Errorf("error fetching related entity IDs: %v", err)
 						}
 
@@ -170,40 +265,39 @@ func (s *Server) ReadEntity(ctx context.Context, req *pb.ReadEntityRequest) (*pb
 			}
 
 		case "attributes":
-			log.Printf("Processing attributes for entity: %s", req.Entity.Id)
+			slog.DebugContext(ctx, "Processing attributes", "entity_id", req.Entity.Id)
 
 			// For now, create a minimal entity with test attributes to demonstrate the conversion
 
-			log.Printf("[server.ReadEntity] Processing attributes for entity: %s, attributes: %+v", req.Entity.Id, req.Entity.Attributes)
+			slog.DebugContext(ctx, "Attributes requested", "entity_id", req.Entity.Id, "attributes", logging.Payload(req.Entity.Attributes))
 
 			// Use the EntityAttributeProcessor to read and process attributes
-			processor := engine.NewEntityAttributeProcessor()
-
 			// Extract fields from the request attributes based on storage type
 			fields := extractFieldsFromAttributes(req.Entity.Attributes)
-			log.Printf("Extracted fields from attributes: %v", fields)
+			slog.DebugContext(ctx, "Extracted fields from attributes", "entity_id", req.Entity.Id, "fields", fields)
 
 			readOptions := engine.NewReadOptions(make(map[string]interface{}), fields...)
+			readOptions.ReadOptions.Format = tabularFormat
 
 			// Process the entity with attributes to get the results map
-			attributeResults := processor.ProcessEntityAttributes(ctx, req.Entity, "read", readOptions)
+			attributeResults := s.processor.ProcessEntityAttributes(ctx, req.Entity, "read", readOptions)
 
-			log.Printf("[server.ReadEntity] Successfully processed attributes for entity: %s, results: %+v", req.Entity.Id, attributeResults)
+			slog.DebugContext(ctx, "Processed attributes", "entity_id", req.Entity.Id, "results", logging.Payload(attributeResults))
 
 			// Convert the results map back to TimeBasedValueList and attach to response.Attributes
 			for attrName, result := range attributeResults {
-				log.Printf("[server.ReadEntity] Successfully processed attribute %s for entity: %s, result: %+v", attrName, req.Entity.Id, result)
+				slog.DebugContext(ctx, "Processed attribute", "entity_id", req.Entity.Id, "attribute", attrName, "result", logging.Payload(result))
 				if result.Success && result.Data != nil {
 					// Convert the result data back to TimeBasedValue format
 					if timeBasedValue, ok := result.Data.(*pb.TimeBasedValue); ok {
 						// If the data is already in TimeBasedValue format, use it directly
-						log.Printf("[server.ReadEntity] Successfully processed attribute %s for entity: %s", attrName, req.Entity.Id)
+						slog.DebugContext(ctx, "Added attribute to the response", "entity_id", req.Entity.Id, "attribute", attrName)
 						response.Attributes[attrName] = &pb.TimeBasedValueList{
 							Values: []*pb.TimeBasedValue{timeBasedValue},
 						}
 					} else {
 						// Convert other data types to TimeBasedValue format
-						log.Printf("[server.ReadEntity] Successfully processed attribute %s for entity: %s", attrName, req.Entity.Id)
+						slog.DebugContext(ctx, "Added attribute to the response", "entity_id", req.Entity.Id, "attribute", attrName)
 						response.Attributes[attrName] = &pb.TimeBasedValueList{
 							Values: []*pb.TimeBasedValue{
 								{
@@ -221,7 +315,7 @@ func (s *Server) ReadEntity(ctx context.Context, req *pb.ReadEntityRequest) (*pb
 			}
 
 		default:
-			log.Printf("Unknown output field requested: %s", field)
+			slog.WarnContext(ctx, "Unknown output field requested", "field", field)
 			return nil, fmt.Errorf("unknown output field requested: %s", field)
 		}
 	}
@@ -239,61 +333,73 @@ func (s *Server) UpdateEntity(ctx context.Context, req *pb.UpdateEntityRequest)
 		updateEntity.Id = updateEntityID
 	}
 
+	if err := s.authorizeEntity(ctx, updateEntityID); err != nil {
+		return nil, err
+	}
+	if err := validateGraphIdentifiers(updateEntity); err != nil {
+		return nil, err
+	}
+	if err := s.checkKind(ctx, updateEntity, false); err != nil {
+		return nil, err
+	}
+	if err := s.processor.AuthorizeAccessChanges(ctx, updateEntity); err != nil {
+		return nil, err
+	}
+
 	// Pass the ID and metadata to HandleMetadata- if no metadata was provided this will rerturn nil
-	err := s.mongoRepo.HandleMetadata(ctx, updateEntityID, updateEntity)
+	err := s.metadataStore.HandleMetadata(ctx, updateEntityID, updateEntity)
 	if err != nil {
-		log.Printf("[server.UpdateEntity] Error updating metadata for entity %s: %v", updateEntityID, err)
+		slog.ErrorContext(ctx, "Error updating metadata", "entity_id", updateEntityID, "error", err)
 		return nil, fmt.Errorf("error updating metadata for entity %s: %v", updateEntityID, err)
 	}
 
 	// Handle Graph Entity update if entity has required fields
-	success, err := s.neo4jRepo.HandleGraphEntityUpdate(ctx, updateEntity)
+	success, err := s.graphStore.HandleGraphEntityUpdate(ctx, updateEntity)
 	if !success {
-		log.Printf("[server.UpdateEntity] Error updating graph entity for %s: %v", updateEntityID, err)
+		slog.ErrorContext(ctx, "Error updating graph entity", "entity_id", updateEntityID, "error", err)
 		return nil, fmt.Errorf("error updating graph entity for entity %s: %v", updateEntityID, err)
 	}
 
 	// Handle Relationships update
-	err = s.neo4jRepo.HandleGraphRelationshipsUpdate(ctx, updateEntity)
+	err = s.graphStore.HandleGraphRelationshipsUpdate(ctx, updateEntity)
 	if err != nil {
-		log.Printf("[server.UpdateEntity] Error updating relationships for entity %s: %v", updateEntityID, err)
+		slog.ErrorContext(ctx, "Error updating relationships", "entity_id", updateEntityID, "error", err)
 		return nil, fmt.Errorf("error updating relationships for entity %s: %v", updateEntityID, err)
 	}
 
 	// Handle attributes
-	processor := engine.NewEntityAttributeProcessor()
 	// Note that in the perspective of the attribute this is a creation operation
 	// The entity is already there but here the attribute is set later.
 	// There is no alignment of update operation with the attribute.
 	// TODO: https://github.com/LDFLK/nexoan/issues/286
-	attributeResults := processor.ProcessEntityAttributes(ctx, req.Entity, "create", nil)
+	attributeResults := s.processor.ProcessEntityAttributes(ctx, req.Entity, "create", nil)
 
 	// Check if any attributes failed
 	hasErrors := false
 	for attrName, result := range attributeResults {
 		if !result.Success || result.Error != nil {
-			log.Printf("[server.CreateEntity] Error handling attribute %s: %v", attrName, result.Error)
+			slog.ErrorContext(ctx, "Error handling attribute", "entity_id", req.Id, "attribute", attrName, "error", result.Error)
 			hasErrors = true
 		} else {
-			log.Printf("[server.CreateEntity] Successfully handled attribute %s for entity: %s", attrName, req.Id)
+			slog.DebugContext(ctx, "Handled attribute", "entity_id", req.Id, "attribute", attrName)
 		}
 	}
 
 	if hasErrors {
-		log.Printf("[server.CreateEntity] Some attributes failed to process")
+		slog.ErrorContext(ctx, "Some attributes failed to process", "entity_id", req.Id)
 		return nil, fmt.Errorf("some attributes failed to process")
 	}
 
 	// Prepare the Update Response
 
 	// Read entity data from Neo4j to include in response
-	kind, name, created, terminated, _ := s.neo4jRepo.GetGraphEntity(ctx, updateEntityID)
+	kind, name, created, terminated, _ := s.graphStore.GetGraphEntity(ctx, updateEntityID)
 
 	// Get relationships from Neo4j
-	relationships, _ := s.neo4jRepo.GetGraphRelationships(ctx, updateEntityID)
+	relationships, _ := s.graphStore.GetGraphRelationships(ctx, updateEntityID)
 
 	// Get metadata from MongoDB
-	metadata, _ := s.mongoRepo.GetMetadata(ctx, updateEntityID)
+	metadata, _ := s.metadataStore.GetMetadata(ctx, updateEntityID)
 
 	// Return updated entity with all available information
 	return &pb.Entity{
@@ -302,31 +408,35 @@ func (s *Server) UpdateEntity(ctx context.Context, req *pb.UpdateEntityRequest)
 		Name:          name,
 		Created:       created,
 		Terminated:    terminated,
-		Metadata:      metadata,
-		Attributes:    make(map[string]*pb.TimeBasedValueList), // Empty attributes
+		Metadata:      engine.MaskMetadata(ctx, metadata),
+		Attributes:    ingestReports(attributeResults), // Only the reports of attributes given as tabular files
 		Relationships: relationships,
 	}, nil
 }
 
 // DeleteEntity removes metadata
 func (s *Server) DeleteEntity(ctx context.Context, req *pb.EntityId) (*pb.Empty, error) {
-	log.Printf("[server.DeleteEntity] Deleting Entity metadata: %s", req.Id)
+	slog.InfoContext(ctx, "Deleting entity metadata", "entity_id", req.Id)
+
+	if err := s.authorizeEntity(ctx, req.Id); err != nil {
+		return nil, err
+	}
 
 	// Check if entity exists before deleting
-	_, err := s.mongoRepo.ReadEntity(ctx, req.Id)
+	_, err := s.metadataStore.ReadEntity(ctx, req.Id)
 	if err != nil {
 		// NOTE: Not returning an error here because we want to delete the
 		// entity even if it does not contain metadata
-		log.Printf("[server.DeleteEntity] Entity %s does not contain metadata: %v", req.Id, err)
+		slog.DebugContext(ctx, "Entity does not contain metadata", "entity_id", req.Id, "error", err)
 	} else {
-		log.Printf("[server.DeleteEntity] Entity %s metadata exists.", req.Id)
-		_, err = s.mongoRepo.DeleteEntity(ctx, req.Id)
+		slog.DebugContext(ctx, "Entity metadata exists", "entity_id", req.Id)
+		err = s.metadataStore.DeleteMetadata(ctx, req.Id)
 		if err != nil {
 			// Log error
-			log.Printf("[server.DeleteEntity] Error deleting metadata for entity %s: %v", req.Id, err)
+			slog.ErrorContext(ctx, "Error deleting metadata", "entity_id", req.Id, "error", err)
 			return nil, fmt.Errorf("error deleting metadata for entity %s: %v", req.Id, err)
 		} else {
-			log.Printf("[server.DeleteEntity] Entity %s metadata deleted.", req.Id)
+			slog.DebugContext(ctx, "Entity metadata deleted", "entity_id", req.Id)
 		}
 	}
 	// TODO: Implement Relationship Deletion in Neo4j
@@ -348,21 +458,28 @@ func (s *Server) ReadEntities(ctx context.Context, req *pb.ReadEntityRequest) (*
 
 	// If we have an ID, add it to the filters
 	if req.Entity.Id != "" {
-		log.Printf("Filtering entities by ID: %s", req.Entity.Id)
+		slog.InfoContext(ctx, "Filtering entities by ID", "entity_id", req.Entity.Id)
 	} else {
-		log.Printf("Filtering entities by Kind.Major: %s", req.Entity.Kind.Major)
+		slog.InfoContext(ctx, "Filtering entities by kind", "kind_major", req.Entity.Kind.Major)
+		if err := auth.AuthorizeKind(ctx, req.Entity.Kind.Major); err != nil {
+			return nil, err
+		}
 	}
 
 	// Use HandleGraphEntityFilter to get filtered entities
-	filteredEntities, err := s.neo4jRepo.HandleGraphEntityFilter(ctx, req)
+	filteredEntities, err := s.graphStore.HandleGraphEntityFilter(ctx, req)
 	if err != nil {
-		log.Printf("Error filtering entities: %v", err)
+		slog.ErrorContext(ctx, "Error filtering entities", "error", err)
 		return nil, err
 	}
 
-	// Convert filtered entities to pb.Entity format
+	// Convert filtered entities to pb.Entity format, leaving out kinds the caller may not see
+	principal := auth.PrincipalFromContext(ctx)
 	var entities []*pb.Entity
 	for _, entity := range filteredEntities {
+		if principal != nil && !principal.AllowsKind(entity["kind"].(string)) {
+			continue
+		}
 		pbEntity := &pb.Entity{
 			Id: entity["id"].(string),
 			Kind: &pb.Kind{
@@ -393,6 +510,385 @@ func (s *Server) ReadEntities(ctx context.Context, req *pb.ReadEntityRequest) (*
 	}, nil
 }
 
+// ReadPaths finds the shortest paths connecting two entities
+func (s *Server) ReadPaths(ctx context.Context, req *pb.PathRequest) (*pb.PathList, error) {
+	if req.SourceEntityId == "" || req.TargetEntityId == "" {
+		return nil, fmt.Errorf("sourceEntityId and targetEntityId are required for reading paths")
+	}
+
+	slog.InfoContext(ctx, "Reading paths", "source_entity_id", req.SourceEntityId, "target_entity_id", req.TargetEntityId)
+
+	for _, entityID := range []string{req.SourceEntityId, req.TargetEntityId} {
+		if err := s.authorizeEntity(ctx, entityID); err != nil {
+			return nil, err
+		}
+	}
+
+	paths, err := s.graphStore.GetGraphPaths(ctx, req)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error reading paths", "error", err)
+		return nil, err
+	}
+
+	return &pb.PathList{
+		Paths: visiblePaths(ctx, paths),
+	}, nil
+}
+
+// ExportSubgraph serialises the subgraph reachable from a root entity
+func (s *Server) ExportSubgraph(ctx context.Context, req *pb.ExportRequest) (*pb.ExportResponse, error) {
+	if req.RootEntityId == "" {
+		return nil, fmt.Errorf("rootEntityId is required for exporting a subgraph")
+	}
+
+	format, err := graphexport.ParseFormat(req.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "Exporting subgraph", "root_entity_id", req.RootEntityId, "format", format)
+
+	if err := s.authorizeEntity(ctx, req.RootEntityId); err != nil {
+		return nil, err
+	}
+
+	exporter := engine.NewSubgraphExporter(s.graphStore, s.metadataStore)
+	graph, err := exporter.Export(ctx, req.RootEntityId, engine.TraversalSpec{
+		RelationshipNames: req.RelationshipNames,
+		Direction:         req.Direction,
+		MaxDepth:          int(req.MaxDepth),
+		ActiveAt:          req.ActiveAt,
+		IncludeMetadata:   req.IncludeMetadata,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error exporting subgraph", "error", err)
+		return nil, err
+	}
+	visibleSubgraph(ctx, graph)
+
+	var buf bytes.Buffer
+	if err := graphexport.Write(&buf, graph, format); err != nil {
+		slog.ErrorContext(ctx, "Error serialising subgraph", "error", err)
+		return nil, err
+	}
+
+	return &pb.ExportResponse{
+		Format:            string(format),
+		ContentType:       format.ContentType(),
+		Content:           buf.Bytes(),
+		EntityCount:       int32(len(graph.Entities)),
+		RelationshipCount: int32(len(graph.Relationships)),
+	}, nil
+}
+
+// CheckConsistency cross-references the three stores and optionally repairs or quarantines the drift found
+func (s *Server) CheckConsistency(ctx context.Context, req *pb.ConsistencyRequest) (*pb.ConsistencyReport, error) {
+	var categories []consistency.Category
+	for _, name := range req.Categories {
+		category, err := consistency.ParseCategory(name)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+
+	slog.InfoContext(ctx, "Checking consistency", "repair", req.Repair, "quarantine", req.Quarantine)
+
+	// The checker reads the stores directly and only knows the default backends
+	neo4jRepo, isNeo4j := s.graphStore.(*neo4jrepository.Neo4jRepository)
+	mongoRepo, isMongo := s.metadataStore.(*mongorepository.MongoRepository)
+	postgresRepo, isPostgres := s.tabularStore.(*postgres.PostgresRepository)
+	if !isNeo4j || !isMongo || !isPostgres {
+		return nil, fmt.Errorf("consistency checks are only supported with the Neo4j, MongoDB and PostgreSQL backends")
+	}
+
+	checker := engine.NewConsistencyChecker(neo4jRepo, mongoRepo, postgresRepo)
+	report, err := checker.Check(ctx, engine.ConsistencyOptions{
+		Categories: categories,
+		Repair:     req.Repair,
+		Quarantine: req.Quarantine,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error checking consistency", "error", err)
+		return nil, err
+	}
+
+	response := &pb.ConsistencyReport{
+		StartedAt:  report.StartedAt.Format(time.RFC3339),
+		FinishedAt: report.FinishedAt.Format(time.RFC3339),
+		Scanned:    make(map[string]int64, len(report.Scanned)),
+		Counts:     make(map[string]int64, len(consistency.Categories)),
+	}
+	for kind, count := range report.Scanned {
+		response.Scanned[kind] = int64(count)
+	}
+	for category, count := range report.Counts() {
+		response.Counts[string(category)] = int64(count)
+	}
+	for _, issue := range report.Issues {
+		response.Issues = append(response.Issues, &pb.ConsistencyIssue{
+			Category:      string(issue.Category),
+			Id:            issue.ID,
+			EntityId:      issue.EntityID,
+			AttributeName: issue.AttributeName,
+			TableName:     issue.TableName,
+			Detail:        issue.Detail,
+			Action:        string(issue.Action),
+			Error:         issue.Error,
+		})
+	}
+
+	return response, nil
+}
+
+// UploadBlob stores a streamed binary file as an attribute of an entity.
+// The first chunk describes the blob and the following chunks carry its content.
+func (s *Server) UploadBlob(stream pb.COREService_UploadBlobServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return fmt.Errorf("error receiving blob info: %v", err)
+	}
+	info := first.GetInfo()
+	if info == nil {
+		return fmt.Errorf("the first chunk of a blob upload must carry its info")
+	}
+	if info.EntityId == "" || info.AttributeName == "" {
+		return fmt.Errorf("entityId and attributeName are required for uploading a blob")
+	}
+
+	startTime := time.Now()
+	if info.StartTime != "" {
+		startTime, err = time.Parse(time.RFC3339, info.StartTime)
+		if err != nil {
+			return fmt.Errorf("invalid startTime %q: %v", info.StartTime, err)
+		}
+	}
+
+	ctx := stream.Context()
+	if err := s.authorizeEntity(ctx, info.EntityId); err != nil {
+		return err
+	}
+	if _, err := s.graphStore.ReadGraphEntity(ctx, info.EntityId); err != nil {
+		return fmt.Errorf("entity %s not found: %v", info.EntityId, err)
+	}
+
+	slog.InfoContext(ctx, "Uploading blob", "entity_id", info.EntityId, "attribute", info.AttributeName)
+
+	metadata, err := s.blobResolver.Upload(ctx, info.EntityId, info.AttributeName, &blobChunkReader{stream: stream}, engine.BlobInfo{
+		MimeType: info.MimeType,
+		FileName: info.FileName,
+	}, startTime)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error uploading blob", "error", err)
+		return err
+	}
+
+	response := blobInfoToProto(metadata)
+	response.StartTime = startTime.Format(time.RFC3339)
+	return stream.SendAndClose(response)
+}
+
+// DownloadBlob streams the latest version of a blob attribute, starting with its info
+func (s *Server) DownloadBlob(req *pb.BlobRequest, stream pb.COREService_DownloadBlobServer) error {
+	if req.EntityId == "" || req.AttributeName == "" {
+		return fmt.Errorf("entityId and attributeName are required for downloading a blob")
+	}
+
+	slog.InfoContext(stream.Context(), "Downloading blob", "entity_id", req.EntityId, "attribute", req.AttributeName)
+
+	if err := s.authorizeEntity(stream.Context(), req.EntityId); err != nil {
+		return err
+	}
+
+	reader, metadata, err := s.blobResolver.Open(stream.Context(), req.EntityId, req.AttributeName)
+	if err != nil {
+		slog.ErrorContext(stream.Context(), "Error opening blob", "error", err)
+		return err
+	}
+	defer reader.Close()
+
+	if err := stream.Send(&pb.BlobChunk{Part: &pb.BlobChunk_Info{Info: blobInfoToProto(metadata)}}); err != nil {
+		return err
+	}
+
+	buf := make([]byte, engine.BlobChunkSize)
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			if sendErr := stream.Send(&pb.BlobChunk{Part: &pb.BlobChunk_Data{Data: buf[:n]}}); sendErr != nil {
+				return sendErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			slog.ErrorContext(stream.Context(), "Error reading blob", "error", err)
+			return fmt.Errorf("error reading blob: %v", err)
+		}
+	}
+}
+
+// blobChunkReader reads the data chunks of a blob upload stream
+type blobChunkReader struct {
+	stream  pb.COREService_UploadBlobServer
+	pending []byte
+}
+
+func (r *blobChunkReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		chunk, err := r.stream.Recv()
+		if err != nil {
+			return 0, err // io.EOF once the client has sent everything
+		}
+		if chunk.GetInfo() != nil {
+			return 0, fmt.Errorf("blob info may only be sent in the first chunk")
+		}
+		r.pending = chunk.GetData()
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// blobInfoToProto converts the metadata of a blob attribute to its API form
+func blobInfoToProto(metadata *engine.AttributeMetadata) *pb.BlobInfo {
+	return &pb.BlobInfo{
+		EntityId:      metadata.EntityID,
+		AttributeName: metadata.AttributeName,
+		MimeType:      metadata.Blob.MimeType,
+		FileName:      metadata.Blob.FileName,
+		ContentHash:   metadata.Blob.ContentHash,
+		Size:          metadata.Blob.Size,
+		StoragePath:   metadata.StoragePath,
+	}
+}
+
+// ExportTabular streams a tabular attribute as an Arrow IPC stream or a Parquet file, optionally
+// filtered, projected and limited to the rows written in a time window. The first chunk describes
+// the export and the following chunks carry the file.
+func (s *Server) ExportTabular(req *pb.TabularExportRequest, stream pb.COREService_ExportTabularServer) error {
+	if req.EntityId == "" || req.AttributeName == "" {
+		return status.Error(codes.InvalidArgument, "entityId and attributeName are required for exporting a tabular attribute")
+	}
+	format, err := tabularexport.ParseFormat(req.Format)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	writtenFrom, writtenUntil, err := parseWrittenWindow(req.WrittenFrom, req.WrittenUntil)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx := stream.Context()
+	slog.InfoContext(ctx, "Exporting tabular attribute", "entity_id", req.EntityId, "attribute", req.AttributeName, "format", format)
+
+	if err := s.authorizeEntity(ctx, req.EntityId); err != nil {
+		return err
+	}
+
+	filters := make(map[string]interface{}, len(req.Filters))
+	for column, value := range req.Filters {
+		filters[column] = value
+	}
+	exporter := engine.NewTabularExporter(s.graphStore, s.metadataStore, s.tabularStore)
+	export, err := exporter.Export(ctx, req.EntityId, req.AttributeName, repository.TabularQuery{
+		Filters:      filters,
+		Fields:       req.Fields,
+		WrittenFrom:  writtenFrom,
+		WrittenUntil: writtenUntil,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error exporting tabular attribute", "error", err)
+		return err
+	}
+
+	if err := stream.Send(&pb.TabularExportChunk{Part: &pb.TabularExportChunk_Info{Info: &pb.TabularExportInfo{
+		Format:      string(format),
+		ContentType: format.ContentType(),
+		Columns:     export.Columns,
+		RowCount:    export.RowCount,
+	}}}); err != nil {
+		return err
+	}
+
+	// One page of rows at a time is read, written as a record batch or row group and sent
+	out := &tabularChunkWriter{stream: stream}
+	writer, err := tabularexport.NewWriter(out, export.Columns, format)
+	if err != nil {
+		return err
+	}
+	for {
+		rows, err := export.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "Error exporting tabular attribute", "error", err)
+			return err
+		}
+		if err := writer.WriteRows(rows); err != nil {
+			slog.ErrorContext(ctx, "Error serialising tabular attribute", "error", err)
+			return err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return out.flush()
+}
+
+// parseWrittenWindow reads the optional RFC3339 bounds of a written window
+func parseWrittenWindow(from, until string) (time.Time, time.Time, error) {
+	var writtenFrom, writtenUntil time.Time
+	var err error
+	if from != "" {
+		if writtenFrom, err = time.Parse(time.RFC3339, from); err != nil {
+			return writtenFrom, writtenUntil, fmt.Errorf("invalid writtenFrom %q: %v", from, err)
+		}
+	}
+	if until != "" {
+		if writtenUntil, err = time.Parse(time.RFC3339, until); err != nil {
+			return writtenFrom, writtenUntil, fmt.Errorf("invalid writtenUntil %q: %v", until, err)
+		}
+	}
+	if !writtenFrom.IsZero() && !writtenUntil.IsZero() && !writtenFrom.Before(writtenUntil) {
+		return writtenFrom, writtenUntil, fmt.Errorf("writtenFrom must be before writtenUntil")
+	}
+	return writtenFrom, writtenUntil, nil
+}
+
+// tabularChunkWriter sends what is written to it as data chunks of at most engine.BlobChunkSize bytes
+type tabularChunkWriter struct {
+	stream  pb.COREService_ExportTabularServer
+	pending []byte
+}
+
+func (w *tabularChunkWriter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	for len(w.pending) >= engine.BlobChunkSize {
+		if err := w.send(w.pending[:engine.BlobChunkSize]); err != nil {
+			return 0, err
+		}
+		w.pending = w.pending[engine.BlobChunkSize:]
+	}
+	return len(p), nil
+}
+
+// flush sends what is left after the last full chunk
+func (w *tabularChunkWriter) flush() error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	err := w.send(w.pending)
+	w.pending = nil
+	return err
+}
+
+func (w *tabularChunkWriter) send(data []byte) error {
+	return w.stream.Send(&pb.TabularExportChunk{Part: &pb.TabularExportChunk_Data{Data: data}})
+}
+
 // extractFieldsFromAttributes extracts field names from entity attributes based on storage type
 // TODO: Limitation in multi-value attribute reads.
 // FIXME: https://github.com/LDFLK/nexoan/issues/285
@@ -410,29 +906,38 @@ func extractFieldsFromAttributes(attributes map[string]*pb.TimeBasedValueList) [
 			continue
 		}
 
-		// Determine storage type and extract fields accordingly
-		storageType, err := determineStorageTypeFromValue(value.Value)
+		// A declared storage type takes precedence over the shape of the value
+		hint, anyValue, err := storageinference.UnwrapHint(value.Value)
 		if err != nil {
-			log.Printf("Warning: could not determine storage type for attribute %s: %v", attrName, err)
+			slog.Warn("Invalid storage hint", "attribute", attrName, "error", err)
+			continue
+		}
+
+		// Determine storage type and extract fields accordingly
+		var storageType string
+		if hint != nil {
+			storageType = string(hint.StorageType)
+		} else if storageType, err = determineStorageTypeFromValue(anyValue); err != nil {
+			slog.Warn("Could not determine storage type", "attribute", attrName, "error", err)
 			continue
 		}
 
 		switch storageType {
 		case "tabular":
 			// For tabular data, extract columns from the attribute value
-			if columns, err := extractColumnsFromTabularAttribute(value.Value); err == nil {
+			if columns, err := extractColumnsFromTabularAttribute(anyValue); err == nil {
 				fields = append(fields, columns...)
 			} else {
-				log.Printf("Warning: could not extract columns from tabular attribute %s: %v", attrName, err)
+				slog.Warn("Could not extract columns from tabular attribute", "attribute", attrName, "error", err)
 			}
 		case "graph":
 			// TODO: Handle graph data fields
-			log.Printf("Graph data fields extraction not implemented yet for attribute %s", attrName)
+			slog.Debug("Graph data fields extraction not implemented yet", "attribute", attrName)
 		case "map":
 			// TODO: Handle document/map data fields
-			log.Printf("Document data fields extraction not implemented yet for attribute %s", attrName)
+			slog.Debug("Document data fields extraction not implemented yet", "attribute", attrName)
 		default:
-			log.Printf("Unknown storage type %s for attribute %s", storageType, attrName)
+			slog.Warn("Unknown storage type", "storage_type", storageType, "attribute", attrName)
 		}
 	}
 
@@ -506,30 +1011,52 @@ func extractColumnsFromTabularAttribute(anyValue *anypb.Any) ([]string, error) {
 	return columns, nil
 }
 
-// Start the gRPC server
-func main() {
-	// Initialize MongoDB config
-	mongoConfig := &config.MongoConfig{
-		URI:        os.Getenv("MONGO_URI"),
-		DBName:     os.Getenv("MONGO_DB_NAME"),
-		Collection: os.Getenv("MONGO_COLLECTION"),
+// CheckStores pings every store that supports it and returns the failures keyed by store
+func (s *Server) CheckStores(ctx context.Context) map[string]error {
+	failures := make(map[string]error)
+	for name, store := range map[string]interface{}{
+		"graph":    s.graphStore,
+		"metadata": s.metadataStore,
+		"tabular":  s.tabularStore,
+	} {
+		pinger, ok := store.(repository.Pinger)
+		if !ok {
+			continue
+		}
+		if err := pinger.Ping(ctx); err != nil {
+			failures[name] = err
+		}
 	}
+	return failures
+}
 
-	// Initialize Neo4j config
-	neo4jConfig := &config.Neo4jConfig{
-		URI:      os.Getenv("NEO4J_URI"),
-		Username: os.Getenv("NEO4J_USER"),
-		Password: os.Getenv("NEO4J_PASSWORD"),
+// durationFromEnv reads a duration such as "30s" from the environment, using fallback when unset
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		fatal("Invalid duration", "variable", name, "value", value, "error", err)
 	}
+	return duration
+}
+
+// fatal logs an error and exits; like log.Fatal, deferred calls do not run
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// Start the gRPC server
+func main() {
+	inMemory := flag.Bool("in-memory", false, "Keep all data in memory instead of Neo4j, MongoDB and PostgreSQL (for demos; nothing is persisted)")
+	flag.Parse()
 
-	// Initialize PostgreSQL config
-	postgresConfig := &postgres.Config{
-		Host:     os.Getenv("POSTGRES_HOST"),
-		Port:     os.Getenv("POSTGRES_PORT"),
-		User:     os.Getenv("POSTGRES_USER"),
-		Password: os.Getenv("POSTGRES_PASSWORD"),
-		DBName:   os.Getenv("POSTGRES_DB"),
-		SSLMode:  os.Getenv("POSTGRES_SSL_MODE"),
+	// Structured logs on stderr, configured by CORE_LOG_LEVEL, CORE_LOG_FORMAT and CORE_LOG_PAYLOADS
+	if err := logging.Setup(os.Stderr); err != nil {
+		log.Fatalf("[service.main] Failed to set up logging: %v", err)
 	}
 
 	// Get host and port from environment variables with defaults
@@ -541,44 +1068,248 @@ func main() {
 	if port == "" {
 		port = "50051"
 	}
+	readyTimeout := durationFromEnv("CORE_STORE_READY_TIMEOUT", 30*time.Second)
+	healthInterval := durationFromEnv("CORE_HEALTH_INTERVAL", 10*time.Second)
+	shutdownTimeout := durationFromEnv("CORE_SHUTDOWN_TIMEOUT", 30*time.Second)
+	metricsPort := os.Getenv("CORE_METRICS_PORT")
+	if metricsPort == "" {
+		metricsPort = "9090"
+	}
+	// The admin endpoints have no authentication, so they only listen on the loopback interface
+	// unless another address is chosen; "off" turns them off
+	adminAddr := os.Getenv("CORE_ADMIN_ADDR")
+	if adminAddr == "" {
+		adminAddr = "127.0.0.1:9091"
+	}
+
+	// SIGTERM (sent by Kubernetes and Docker on stop) and Ctrl-C start a clean shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Traces are exported as configured by OTEL_TRACES_EXPORTER; the last spans are flushed on exit
+	shutdownTracing, err := tracing.Setup(ctx)
+	if err != nil {
+		fatal("Failed to set up tracing", "error", err)
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			slog.Error("Error flushing traces", "error", err)
+		}
+	}()
+
+	var graphStore repository.GraphStore
+	var metadataStore repository.MetadataStore
+	var tabularStore repository.TabularStore
+
+	// The stores are opened once and their connection pools are shared by every request
+	var closers []func()
+	closeStores := func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
+	}
+
+	if *inMemory {
+		slog.Warn("Using in-memory stores, data will be lost when the service stops")
+		graphStore = memoryrepository.NewGraphStore()
+		metadataStore = memoryrepository.NewMetadataStore()
+		tabularStore = memoryrepository.NewTabularStore()
+	} else {
+		// Create MongoDB repository
+		mongoRepo := dbcommons.GetMongoRepository(ctx)
+		closers = append(closers, func() {
+			if err := mongoRepo.Close(context.Background()); err != nil {
+				slog.Error("Error closing MongoDB repository", "error", err)
+			}
+		})
+
+		// Create Neo4j repository
+		neo4jRepo, err := dbcommons.GetNeo4jRepository(ctx)
+		if err != nil {
+			closeStores()
+			fatal("Failed to create Neo4j repository", "error", err)
+		}
+		closers = append(closers, func() { neo4jRepo.Close(context.Background()) })
+
+		// Create PostgreSQL repository
+		postgresRepo, err := dbcommons.GetPostgresRepository(ctx)
+		if err != nil {
+			closeStores()
+			fatal("Failed to create PostgreSQL repository", "error", err)
+		}
+		closers = append(closers, func() {
+			if err := postgresRepo.Close(); err != nil {
+				slog.Error("Error closing PostgreSQL repository", "error", err)
+			}
+		})
+
+		graphStore, metadataStore, tabularStore = neo4jRepo, mongoRepo, postgresRepo
+
+		// MongoDB reports its pool through driver events; PostgreSQL is read on every scrape
+		if err := metrics.RegisterPoolStats(metrics.Postgres, func() metrics.PoolStats {
+			stats := postgresRepo.DB().Stats()
+			return metrics.PoolStats{Open: stats.OpenConnections, InUse: stats.InUse}
+		}); err != nil {
+			slog.Error("Failed to register PostgreSQL pool metrics", "error", err)
+		}
+	}
+	defer closeStores()
+
+	// Blob attributes are only available when an object store is configured
+	objectStore, err := dbcommons.GetObjectStore()
+	if err != nil {
+		slog.Warn("Blob attributes are disabled", "error", err)
+	}
 
-	// Create MongoDB repository
-	ctx := context.Background()
-	mongoRepo := mongorepository.NewMongoRepository(ctx, mongoConfig)
+	server := NewServer(graphStore, metadataStore, tabularStore, objectStore)
 
-	// Create Neo4j repository
-	neo4jRepo, err := neo4jrepository.NewNeo4jRepository(ctx, neo4jConfig)
+	// Per tenant entity and request limits are read from CORE_TENANT_QUOTAS_FILE
+	quotas, err := tenant.QuotasFromEnv()
 	if err != nil {
-		log.Fatalf("[service.main] Failed to create Neo4j repository: %v", err)
+		closeStores()
+		fatal("Failed to load tenant quotas", "error", err)
 	}
-	defer neo4jRepo.Close(ctx)
+	server.quotas = quotas
 
-	// Create PostgreSQL repository
-	postgresRepo, err := postgres.NewPostgresRepository(*postgresConfig)
+	// Entities are validated against the kind registry as CORE_KIND_REGISTRY_MODE says
+	kindMode, err := kinds.ModeFromEnv()
 	if err != nil {
-		log.Fatalf("[service.main] Failed to create PostgreSQL repository: %v", err)
+		closeStores()
+		fatal("Invalid kind registry mode", "error", err)
 	}
-	defer postgresRepo.Close()
+	server.kindMode = kindMode
 
-	listener, err := net.Listen("tcp", host+":"+port)
+	// Callers authenticate with a JWT or an API key when CORE_AUTH_MODE is set
+	authenticator, err := auth.FromEnv()
+	if err != nil {
+		closeStores()
+		fatal("Failed to set up authentication", "error", err)
+	}
+	tlsConfig, err := auth.TLSConfigFromEnv()
 	if err != nil {
-		log.Fatalf("[service.main] Failed to listen: %v", err)
+		closeStores()
+		fatal("Failed to set up TLS", "error", err)
 	}
 
-	grpcServer := grpc.NewServer()
-	server := &Server{
-		mongoRepo:    mongoRepo,
-		neo4jRepo:    neo4jRepo,
-		postgresRepo: postgresRepo,
+	listener, err := net.Listen("tcp", host+":"+port)
+	if err != nil {
+		closeStores()
+		fatal("Failed to listen", "error", err)
 	}
 
+	// Every RPC gets a span, joined to the caller's trace when it sends W3C trace context metadata.
+	// Panics are recovered inside logging and metrics so that they are logged with the request ID and
+	// counted as Internal errors. Authorization runs after logging and metrics so that denied calls are
+	// still logged and counted, and the tenant is resolved last because it depends on the tenant the
+	// caller's credentials are bound to.
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		logging.UnaryServerInterceptor(), metrics.UnaryServerInterceptor(), recovery.UnaryServerInterceptor(),
+	}
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		logging.StreamServerInterceptor(), metrics.StreamServerInterceptor(), recovery.StreamServerInterceptor(),
+	}
+	if authenticator != nil {
+		unaryInterceptors = append(unaryInterceptors, auth.UnaryServerInterceptor(authenticator))
+		streamInterceptors = append(streamInterceptors, auth.StreamServerInterceptor(authenticator))
+	} else {
+		slog.Warn("Authentication is off, every caller can read and write; set CORE_AUTH_MODE to require credentials")
+	}
+	unaryInterceptors = append(unaryInterceptors, tenant.UnaryServerInterceptor(quotas))
+	streamInterceptors = append(streamInterceptors, tenant.StreamServerInterceptor(quotas))
+	serverOptions := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler(otelgrpc.WithFilter(func(info *stats.RPCTagInfo) bool {
+			return !strings.HasPrefix(info.FullMethodName, "/grpc.health.v1.")
+		}))),
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	}
+	if tlsConfig != nil {
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	} else if authenticator != nil {
+		slog.Warn("Credentials are sent in plaintext; set CORE_TLS_CERT_FILE and CORE_TLS_KEY_FILE")
+	}
+	grpcServer := grpc.NewServer(serverOptions...)
 	pb.RegisterCOREServiceServer(grpcServer, server)
 
+	// The health service answers from the start, reporting NOT_SERVING until the stores are ready
+	storeHealth := newStoreHealth(server)
+	healthpb.RegisterHealthServer(grpcServer, storeHealth.health)
+
 	// Register reflection service
 	reflection.Register(grpcServer)
 
-	log.Printf("[service.main] CORE Service is running on %s:%s...", host, port)
-	if err := grpcServer.Serve(listener); err != nil {
-		log.Fatalf("[service.main] Failed to serve: %v", err)
+	// Prometheus metrics are served read-only over plain HTTP next to the gRPC port
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", metrics.Handler())
+	metricsServer := &http.Server{Addr: host + ":" + metricsPort, Handler: metricsMux}
+	go func() {
+		slog.Info("Metrics are served", "address", host+":"+metricsPort, "path", "/metrics")
+		if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("Metrics server failed", "error", err)
+		}
+	}()
+	defer metricsServer.Close()
+
+	// The runtime log level can turn on payload logging, so it is changed on the separate admin address
+	if adminAddr != "off" {
+		adminMux := http.NewServeMux()
+		adminMux.Handle("/debug/loglevel", logging.LevelHandler())
+		adminServer := &http.Server{Addr: adminAddr, Handler: adminMux}
+		go func() {
+			slog.Info("Admin endpoints are served", "address", adminAddr, "path", "/debug/loglevel")
+			if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				slog.Error("Admin server failed", "error", err)
+			}
+		}()
+		defer adminServer.Close()
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("CORE Service is listening", "address", host+":"+port, "tls", tlsConfig != nil, "mtls", tlsConfig != nil && tlsConfig.ClientCAs != nil)
+		serveErr <- grpcServer.Serve(listener)
+	}()
+
+	if err := storeHealth.waitUntilReady(ctx, readyTimeout); err != nil {
+		grpcServer.Stop()
+		closeStores()
+		fatal("Stores are not ready", "error", err)
+	}
+	if err := tabularStore.InitializeTables(ctx); err != nil {
+		grpcServer.Stop()
+		closeStores()
+		fatal("Failed to initialize tabular store", "error", err)
+	}
+	slog.Info("CORE Service is ready")
+
+	watchCtx, stopWatch := context.WithCancel(ctx)
+	defer stopWatch()
+	go storeHealth.watch(watchCtx, healthInterval)
+
+	select {
+	case err := <-serveErr:
+		if err != nil {
+			slog.Error("Failed to serve", "error", err)
+		}
+	case <-ctx.Done():
+		// Report NOT_SERVING first so load balancers stop routing new calls, then drain
+		slog.Info("Shutting down, waiting for in-flight requests", "timeout", shutdownTimeout.String())
+		stopWatch()
+		storeHealth.shutdown()
+
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(shutdownTimeout):
+			slog.Warn("In-flight requests did not finish in time, stopping")
+			grpcServer.Stop()
+		}
 	}
+	slog.Info("Closing stores")
 }
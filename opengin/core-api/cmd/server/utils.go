@@ -1,52 +1,39 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"log/slog"
+
 	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
-	"log"
 )
 
-func debugMetadata(req *pb.Entity) {
-
-	log.Printf("Reading Entity metadata: %s", req.Id)
-	fmt.Println("DEBUG - Received request:")
-	fmt.Printf("DEBUG - Entity ID: %s\n", req.Id)
-	fmt.Printf("DEBUG - Kind: %+v\n", req.Kind)
-	fmt.Printf("DEBUG - Created: %s\n", req.Created)
-	fmt.Printf("DEBUG - Terminated: %s\n", req.Terminated)
-	fmt.Printf("DEBUG - Name: %+v\n", req.Name)
-
-	// Print all metadata entries
-	fmt.Println("DEBUG - Metadata entries count:", len(req.Metadata))
-	for key, value := range req.Metadata {
-		fmt.Printf("DEBUG - Metadata key: %s\n", key)
-		if value != nil {
-			fmt.Printf("DEBUG - Metadata value typeUrl: %s\n", value.TypeUrl)
-			fmt.Printf("DEBUG - Metadata value data length: %d\n", len(value.Value))
-		} else {
-			fmt.Println("DEBUG - Metadata value is nil")
-		}
+func debugMetadata(ctx context.Context, req *pb.Entity) {
+	metadataKeys := make([]string, 0, len(req.Metadata))
+	for key := range req.Metadata {
+		metadataKeys = append(metadataKeys, key)
 	}
 
+	slog.DebugContext(ctx, "Received entity",
+		"entity_id", req.Id,
+		"kind", req.Kind.String(),
+		"created", req.Created,
+		"terminated", req.Terminated,
+		"metadata_keys", metadataKeys)
 }
 
-func debugUtils(req *pb.Entity) {
-
-	// Print attributes if present
-	fmt.Println("DEBUG - Attributes count:", len(req.Attributes))
+func debugUtils(ctx context.Context, req *pb.Entity) {
+	attributeValues := make(map[string]int, len(req.Attributes))
 	for key, valueList := range req.Attributes {
-		fmt.Printf("DEBUG - Attribute key: %s\n", key)
-		if valueList != nil {
-			fmt.Printf("DEBUG - Attribute values count: %d\n", len(valueList.Values))
-		}
+		attributeValues[key] = len(valueList.GetValues())
 	}
 
-	// Print relationships if present
-	fmt.Println("DEBUG - Relationships count:", len(req.Relationships))
-	for key, rel := range req.Relationships {
-		fmt.Printf("DEBUG - Relationship key: %s\n", key)
-		if rel != nil {
-			fmt.Printf("DEBUG - Related entity ID: %s\n", rel.RelatedEntityId)
-		}
+	relatedEntityIds := make([]string, 0, len(req.Relationships))
+	for _, rel := range req.Relationships {
+		relatedEntityIds = append(relatedEntityIds, rel.GetRelatedEntityId())
 	}
+
+	slog.DebugContext(ctx, "Received entity contents",
+		"entity_id", req.Id,
+		"attribute_values", attributeValues,
+		"related_entity_ids", relatedEntityIds)
 }
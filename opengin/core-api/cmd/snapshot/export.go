@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	postgres "lk/datafoundation/core-api/db/repository/postgres"
+	"lk/datafoundation/core-api/pkg/snapshot"
+)
+
+// exportSnapshot writes every store into a new archive at path.
+// The archive is written next to path and renamed into place once complete.
+func exportSnapshot(ctx context.Context, s *stores, path string, label string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating archive: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	writer := snapshot.NewWriter(tmp, label)
+	defer writer.Abort()
+
+	if err := exportNeo4j(ctx, s, writer); err != nil {
+		return err
+	}
+	if err := exportMongo(ctx, s, writer); err != nil {
+		return err
+	}
+	if err := exportPostgres(ctx, s, writer); err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing archive: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error saving archive: %v", err)
+	}
+
+	for _, entry := range writer.Manifest().Files {
+		log.Printf("[snapshot.export] %s: %d records", entry.Name, entry.Records)
+	}
+	return nil
+}
+
+// exportNeo4j writes the entity and relationship files from a single read transaction
+func exportNeo4j(ctx context.Context, s *stores, writer *snapshot.Writer) error {
+	entities, err := writer.Create(snapshot.Neo4jEntitiesFile)
+	if err != nil {
+		return err
+	}
+	entitiesOpen := true
+
+	var relationships *snapshot.RecordWriter
+	openRelationships := func() error {
+		if entitiesOpen {
+			entitiesOpen = false
+			if err := entities.Close(); err != nil {
+				return err
+			}
+		}
+		if relationships == nil {
+			relationships, err = writer.Create(snapshot.Neo4jRelationshipsFile)
+		}
+		return err
+	}
+
+	err = s.neo4jRepo.ExportGraph(ctx,
+		func(entity map[string]interface{}) error {
+			return entities.Write(snapshot.GraphEntity{
+				Id:         stringValue(entity, "Id"),
+				Kind:       stringValue(entity, "MajorKind"),
+				MinorKind:  stringValue(entity, "MinorKind"),
+				Name:       stringValue(entity, "Name"),
+				Created:    stringValue(entity, "Created"),
+				Terminated: stringValue(entity, "Terminated"),
+			})
+		},
+		func(rel map[string]interface{}) error {
+			if err := openRelationships(); err != nil {
+				return err
+			}
+			return relationships.Write(snapshot.GraphRelationship{
+				Id:         stringValue(rel, "Id"),
+				Name:       stringValue(rel, "Name"),
+				SourceId:   stringValue(rel, "SourceId"),
+				SourceKind: stringValue(rel, "SourceKind"),
+				TargetId:   stringValue(rel, "TargetId"),
+				TargetKind: stringValue(rel, "TargetKind"),
+				Created:    stringValue(rel, "Created"),
+				Terminated: stringValue(rel, "Terminated"),
+			})
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// The relationship file exists even when the graph has no relationships
+	if err := openRelationships(); err != nil {
+		return err
+	}
+	return relationships.Close()
+}
+
+// exportMongo writes one file per collection
+func exportMongo(ctx context.Context, s *stores, writer *snapshot.Writer) error {
+	collections, err := s.mongoRepo.ListCollectionNames(ctx)
+	if err != nil {
+		return err
+	}
+	sort.Strings(collections)
+
+	for _, collection := range collections {
+		file, err := writer.Create(snapshot.MongoCollectionFile(collection))
+		if err != nil {
+			return err
+		}
+		if err := s.mongoRepo.ExportCollection(ctx, collection, file.WriteRaw); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportPostgres writes the attribute table definitions and the rows of every table from one transaction
+func exportPostgres(ctx context.Context, s *stores, writer *snapshot.Writer) error {
+	tx, err := s.postgresRepo.BeginSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	attributeTables, err := s.postgresRepo.ListAttributeTables(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	definitions, err := writer.Create(snapshot.PostgresTablesFile)
+	if err != nil {
+		return err
+	}
+	for _, table := range attributeTables {
+		definition, err := s.postgresRepo.GetTableDefinition(ctx, tx, table)
+		if err != nil {
+			definitions.Close()
+			return err
+		}
+		if err := definitions.Write(definition); err != nil {
+			definitions.Close()
+			return err
+		}
+	}
+	if err := definitions.Close(); err != nil {
+		return err
+	}
+
+	tables := append(append([]string{}, postgres.CoreTables...), attributeTables...)
+	for _, table := range tables {
+		file, err := writer.Create(snapshot.PostgresTableFile(table))
+		if err != nil {
+			return err
+		}
+		if err := s.postgresRepo.ExportTableRows(ctx, tx, table, file.WriteRaw); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stringValue returns a string field of a repository map, or an empty string
+func stringValue(values map[string]interface{}, key string) string {
+	value, _ := values[key].(string)
+	return value
+}
@@ -0,0 +1,146 @@
+// Command snapshot exports all three stores into one versioned archive and restores it.
+//
+// The archive is a gzip compressed tar of JSONL files, led by a manifest recording the
+// number of records and the checksum of every file. Restore reads the files in this order in one pass:
+//
+//	manifest.json
+//	neo4j/entities.jsonl        every node with its kind, name and lifetime
+//	neo4j/relationships.jsonl   every relationship with its ends and time window
+//	mongo/<collection>.jsonl    every document as canonical extended JSON
+//	postgres/tables.jsonl       the column definitions of every attr_* table
+//	postgres/rows/<table>.jsonl the rows of entity_attributes, attribute_schemas and every attr_* table
+//
+// Each store is read in a single transaction where the store supports it, but the three stores
+// cannot be read atomically together. Stop writes to the CORE service while exporting.
+//
+// A snapshot holds the data of one tenant, the default one unless -tenant names another, and
+// can be restored into any tenant.
+//
+// Usage:
+//
+//	snapshot export [-out snapshot.tar.gz] [-label production] [-tenant acme]
+//	snapshot restore -in snapshot.tar.gz [-batch-size 1000] [-tenant acme]
+//	snapshot verify -in snapshot.tar.gz [-live] [-tenant acme]
+//
+// Database connections use the same environment variables as the CORE service.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	dbcommons "lk/datafoundation/core-api/commons/db"
+	mongorepository "lk/datafoundation/core-api/db/repository/mongo"
+	neo4jrepository "lk/datafoundation/core-api/db/repository/neo4j"
+	postgres "lk/datafoundation/core-api/db/repository/postgres"
+	"lk/datafoundation/core-api/pkg/snapshot"
+	"lk/datafoundation/core-api/pkg/tenant"
+)
+
+// stores holds the repositories of the three databases
+type stores struct {
+	neo4jRepo    *neo4jrepository.Neo4jRepository
+	mongoRepo    *mongorepository.MongoRepository
+	postgresRepo *postgres.PostgresRepository
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	command := os.Args[1]
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	out := flags.String("out", fmt.Sprintf("snapshot-%s.tar.gz", time.Now().UTC().Format("20060102T150405Z")), "Archive to write (export)")
+	in := flags.String("in", "", "Archive to read (restore, verify)")
+	label := flags.String("label", "", "Free-form label stored in the manifest, e.g. the source environment (export)")
+	batchSize := flags.Int("batch-size", 1000, "Records written per database call (restore)")
+	live := flags.Bool("live", false, "Also compare the archive counts with the live databases (verify)")
+	tenantID := flags.String("tenant", tenant.Default, "Tenant whose data is exported, restored or compared")
+	flags.Parse(os.Args[2:])
+
+	if err := tenant.Validate(*tenantID); err != nil {
+		log.Fatalf("[snapshot.main] %v", err)
+	}
+	ctx := tenant.WithTenant(context.Background(), *tenantID)
+
+	switch command {
+	case "export":
+		s := connect(ctx)
+		defer s.close(ctx)
+		if err := exportSnapshot(ctx, s, *out, *label); err != nil {
+			log.Fatalf("[snapshot.main] Export failed: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Snapshot written to %s\n", *out)
+
+	case "restore":
+		if *in == "" || *batchSize <= 0 {
+			usage()
+		}
+		s := connect(ctx)
+		defer s.close(ctx)
+		if err := restoreSnapshot(ctx, s, *in, *batchSize); err != nil {
+			log.Fatalf("[snapshot.main] Restore failed: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Snapshot %s restored and verified\n", *in)
+
+	case "verify":
+		if *in == "" {
+			usage()
+		}
+		reader, err := snapshot.OpenReader(*in)
+		if err != nil {
+			log.Fatalf("[snapshot.main] %v", err)
+		}
+		if err := reader.Verify(); err != nil {
+			log.Fatalf("[snapshot.main] Archive verification failed: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Archive %s is intact (%d files)\n", *in, len(reader.Manifest.Files))
+
+		if *live {
+			s := connect(ctx)
+			defer s.close(ctx)
+			if err := verifyCounts(ctx, s, reader.Manifest); err != nil {
+				log.Fatalf("[snapshot.main] %v", err)
+			}
+			fmt.Fprintf(os.Stderr, "Live databases match the archive counts\n")
+		}
+
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage:\n  snapshot export [-out file] [-label name] [-tenant id]\n  snapshot restore -in file [-batch-size n] [-tenant id]\n  snapshot verify -in file [-live] [-tenant id]\n")
+	os.Exit(2)
+}
+
+// connect opens all three repositories using the environment configuration
+func connect(ctx context.Context) *stores {
+	neo4jRepo, err := dbcommons.GetNeo4jRepository(ctx)
+	if err != nil {
+		log.Fatalf("[snapshot.connect] Failed to create Neo4j repository: %v", err)
+	}
+
+	postgresRepo, err := dbcommons.GetPostgresRepository(ctx)
+	if err != nil {
+		log.Fatalf("[snapshot.connect] Failed to create PostgreSQL repository: %v", err)
+	}
+
+	return &stores{
+		neo4jRepo:    neo4jRepo,
+		mongoRepo:    dbcommons.GetMongoRepository(ctx),
+		postgresRepo: postgresRepo,
+	}
+}
+
+// close closes the repositories that hold connections
+func (s *stores) close(ctx context.Context) {
+	s.neo4jRepo.Close(ctx)
+	s.postgresRepo.Close()
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	postgres "lk/datafoundation/core-api/db/repository/postgres"
+	"lk/datafoundation/core-api/pkg/snapshot"
+)
+
+// restoreSnapshot loads an archive into empty databases and verifies the counts afterwards
+func restoreSnapshot(ctx context.Context, s *stores, path string, batchSize int) error {
+	reader, err := snapshot.OpenReader(path)
+	if err != nil {
+		return err
+	}
+
+	// Refuse to start from a damaged archive rather than failing half way through
+	if err := reader.Verify(); err != nil {
+		return fmt.Errorf("archive verification failed: %v", err)
+	}
+
+	if err := s.postgresRepo.InitializeTables(ctx); err != nil {
+		return err
+	}
+	if err := ensureEmpty(ctx, s, reader.Manifest); err != nil {
+		return err
+	}
+
+	if err := restoreFiles(ctx, s, reader, batchSize); err != nil {
+		return err
+	}
+
+	if err := verifyCounts(ctx, s, reader.Manifest); err != nil {
+		return err
+	}
+
+	// Archives written before attribute tables were named by repository.AttributeTableName hold the old names
+	migrated, err := s.postgresRepo.MigrateAttributeTableNames(ctx)
+	if err != nil {
+		return err
+	}
+	if migrated > 0 {
+		log.Printf("[snapshot.restoreSnapshot] Renamed the tables of %d attributes", migrated)
+	}
+	return nil
+}
+
+// ensureEmpty checks that none of the stores already holds data
+func ensureEmpty(ctx context.Context, s *stores, manifest *snapshot.Manifest) error {
+	entities, relationships, err := s.neo4jRepo.CountGraph(ctx)
+	if err != nil {
+		return err
+	}
+	if entities > 0 || relationships > 0 {
+		return fmt.Errorf("neo4j is not empty (%d entities, %d relationships)", entities, relationships)
+	}
+
+	for _, collection := range manifest.MongoCollections() {
+		count, err := s.mongoRepo.CountDocuments(ctx, collection)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("mongodb collection %s is not empty (%d documents)", collection, count)
+		}
+	}
+
+	for _, table := range postgres.CoreTables {
+		count, err := s.postgresRepo.CountRows(ctx, table)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("postgres table %s is not empty (%d rows)", table, count)
+		}
+	}
+
+	tx, err := s.postgresRepo.BeginSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	attributeTables, err := s.postgresRepo.ListAttributeTables(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if len(attributeTables) > 0 {
+		return fmt.Errorf("postgres already has %d attribute tables", len(attributeTables))
+	}
+
+	return nil
+}
+
+// restoreFiles loads the files of the archive in one pass, in the order export writes them: the Neo4j
+// entities before the relationships between them, then the MongoDB collections, then the attribute
+// table definitions, the bookkeeping tables and the attribute tables that reference them
+func restoreFiles(ctx context.Context, s *stores, reader *snapshot.Reader, batchSize int) error {
+	definitions := make(map[string]*postgres.TableDefinition)
+	restored := 0
+
+	err := reader.Walk(func(file *snapshot.File) error {
+		if file.Name == snapshot.Neo4jEntitiesFile {
+			return restoreEntities(ctx, s, file, batchSize)
+		}
+		if file.Name == snapshot.Neo4jRelationshipsFile {
+			return restoreRelationships(ctx, s, file, batchSize)
+		}
+		if collection, ok := snapshot.ParseMongoCollectionFile(file.Name); ok {
+			return restoreCollection(ctx, s, file, collection, batchSize)
+		}
+		if file.Name == snapshot.PostgresTablesFile {
+			return file.Each(func(record []byte) error {
+				var definition postgres.TableDefinition
+				if err := json.Unmarshal(record, &definition); err != nil {
+					return fmt.Errorf("invalid table definition: %v", err)
+				}
+				if !strings.HasPrefix(definition.Name, postgres.AttributeTablePrefix) {
+					return fmt.Errorf("unexpected table definition for %s", definition.Name)
+				}
+				definitions[definition.Name] = &definition
+				return nil
+			})
+		}
+
+		table, ok := snapshot.ParsePostgresTableFile(file.Name)
+		if !ok {
+			return fmt.Errorf("unexpected file %s in the snapshot", file.Name)
+		}
+		if slices.Contains(postgres.CoreTables, table) {
+			// The bookkeeping tables were created by InitializeTables
+			return restoreTableRows(ctx, s, file, table, batchSize)
+		}
+		definition, ok := definitions[table]
+		if !ok {
+			return fmt.Errorf("the rows of %s come before its definition in %s, or it has none", table, snapshot.PostgresTablesFile)
+		}
+		if err := s.postgresRepo.CreateTableFromDefinition(ctx, definition); err != nil {
+			return err
+		}
+		if err := restoreTableRows(ctx, s, file, table, batchSize); err != nil {
+			return err
+		}
+		restored++
+		return s.postgresRepo.ResetSequences(ctx, definition)
+	})
+	if err != nil {
+		return err
+	}
+	if restored != len(definitions) {
+		return fmt.Errorf("%s defines %d attribute tables but the rows of %d were restored", snapshot.PostgresTablesFile, len(definitions), restored)
+	}
+
+	// The bookkeeping tables were created by InitializeTables, read their definitions to find the sequences
+	tx, err := s.postgresRepo.BeginSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, table := range postgres.CoreTables {
+		definition, err := s.postgresRepo.GetTableDefinition(ctx, tx, table)
+		if err != nil {
+			return err
+		}
+		if err := s.postgresRepo.ResetSequences(ctx, definition); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("[snapshot.restoreFiles] Restored %d attribute tables", restored)
+	return nil
+}
+
+// restoreEntities creates the entities in batches grouped by kind
+func restoreEntities(ctx context.Context, s *stores, file *snapshot.File, batchSize int) error {
+	entityBatches := make(map[string][]map[string]interface{})
+	flushEntities := func(kind string) error {
+		if err := s.neo4jRepo.RestoreGraphEntities(ctx, kind, entityBatches[kind]); err != nil {
+			return err
+		}
+		delete(entityBatches, kind)
+		return nil
+	}
+
+	err := file.Each(func(record []byte) error {
+		var entity snapshot.GraphEntity
+		if err := json.Unmarshal(record, &entity); err != nil {
+			return fmt.Errorf("invalid entity record: %v", err)
+		}
+		entityBatches[entity.Kind] = append(entityBatches[entity.Kind], nullable(map[string]string{
+			"Id":         entity.Id,
+			"MinorKind":  entity.MinorKind,
+			"Name":       entity.Name,
+			"Created":    entity.Created,
+			"Terminated": entity.Terminated,
+		}))
+		if len(entityBatches[entity.Kind]) >= batchSize {
+			return flushEntities(entity.Kind)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for kind := range entityBatches {
+		if err := flushEntities(kind); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("[snapshot.restoreEntities] Restored %d Neo4j entities", file.Records())
+	return nil
+}
+
+// restoreRelationships creates the relationships in batches grouped by type and the kinds of their ends
+func restoreRelationships(ctx context.Context, s *stores, file *snapshot.File, batchSize int) error {
+	type relationshipKey struct{ name, sourceKind, targetKind string }
+	relationshipBatches := make(map[relationshipKey][]map[string]interface{})
+	flushRelationships := func(key relationshipKey) error {
+		if err := s.neo4jRepo.RestoreRelationships(ctx, key.name, key.sourceKind, key.targetKind, relationshipBatches[key]); err != nil {
+			return err
+		}
+		delete(relationshipBatches, key)
+		return nil
+	}
+
+	err := file.Each(func(record []byte) error {
+		var rel snapshot.GraphRelationship
+		if err := json.Unmarshal(record, &rel); err != nil {
+			return fmt.Errorf("invalid relationship record: %v", err)
+		}
+		key := relationshipKey{rel.Name, rel.SourceKind, rel.TargetKind}
+		relationshipBatches[key] = append(relationshipBatches[key], nullable(map[string]string{
+			"Id":         rel.Id,
+			"SourceId":   rel.SourceId,
+			"TargetId":   rel.TargetId,
+			"Created":    rel.Created,
+			"Terminated": rel.Terminated,
+		}))
+		if len(relationshipBatches[key]) >= batchSize {
+			return flushRelationships(key)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for key := range relationshipBatches {
+		if err := flushRelationships(key); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("[snapshot.restoreRelationships] Restored %d Neo4j relationships", file.Records())
+	return nil
+}
+
+// restoreCollection inserts the documents of a MongoDB collection
+func restoreCollection(ctx context.Context, s *stores, file *snapshot.File, collection string, batchSize int) error {
+	var batch [][]byte
+	err := file.Each(func(record []byte) error {
+		batch = append(batch, append([]byte(nil), record...))
+		if len(batch) >= batchSize {
+			err := s.mongoRepo.RestoreDocuments(ctx, collection, batch)
+			batch = nil
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.mongoRepo.RestoreDocuments(ctx, collection, batch); err != nil {
+		return err
+	}
+	log.Printf("[snapshot.restoreCollection] Restored collection %s", collection)
+	return nil
+}
+
+// restoreTableRows loads the rows of one table in batches
+func restoreTableRows(ctx context.Context, s *stores, file *snapshot.File, table string, batchSize int) error {
+	var batch [][]byte
+	err := file.Each(func(record []byte) error {
+		batch = append(batch, append([]byte(nil), record...))
+		if len(batch) >= batchSize {
+			err := s.postgresRepo.RestoreTableRows(ctx, table, batch)
+			batch = nil
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return s.postgresRepo.RestoreTableRows(ctx, table, batch)
+}
+
+// verifyCounts compares the live record counts with the manifest and reports every mismatch
+func verifyCounts(ctx context.Context, s *stores, manifest *snapshot.Manifest) error {
+	var mismatches []string
+	check := func(name string, live int64) {
+		entry, ok := manifest.File(name)
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("%s is missing from the manifest", name))
+			return
+		}
+		if entry.Records != live {
+			mismatches = append(mismatches, fmt.Sprintf("%s: archive has %d records, database has %d", name, entry.Records, live))
+		}
+	}
+
+	entities, relationships, err := s.neo4jRepo.CountGraph(ctx)
+	if err != nil {
+		return err
+	}
+	check(snapshot.Neo4jEntitiesFile, entities)
+	check(snapshot.Neo4jRelationshipsFile, relationships)
+
+	for _, collection := range manifest.MongoCollections() {
+		count, err := s.mongoRepo.CountDocuments(ctx, collection)
+		if err != nil {
+			return err
+		}
+		check(snapshot.MongoCollectionFile(collection), count)
+	}
+
+	for _, table := range manifest.PostgresTables() {
+		count, err := s.postgresRepo.CountRows(ctx, table)
+		if err != nil {
+			mismatches = append(mismatches, fmt.Sprintf("%s: %v", table, err))
+			continue
+		}
+		check(snapshot.PostgresTableFile(table), count)
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("count verification failed:\n  %s", strings.Join(mismatches, "\n  "))
+	}
+	return nil
+}
+
+// nullable converts a string map for a Cypher parameter, using nil for empty values
+func nullable(values map[string]string) map[string]interface{} {
+	result := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		if value == "" {
+			result[key] = nil
+		} else {
+			result[key] = value
+		}
+	}
+	return result
+}
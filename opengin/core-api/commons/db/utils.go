@@ -3,29 +3,49 @@ package dbcommons
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
+	"strconv"
+	"time"
 
 	"lk/datafoundation/core-api/db/config"
+	"lk/datafoundation/core-api/db/repository"
 	mongorepository "lk/datafoundation/core-api/db/repository/mongo"
 	neo4jrepository "lk/datafoundation/core-api/db/repository/neo4j"
 	postgresrepository "lk/datafoundation/core-api/db/repository/postgres"
+	"lk/datafoundation/core-api/pkg/objectstore"
+)
+
+// The default backends implement the store interfaces
+var (
+	_ repository.GraphStore    = (*neo4jrepository.Neo4jRepository)(nil)
+	_ repository.MetadataStore = (*mongorepository.MongoRepository)(nil)
+	_ repository.TabularStore  = (*postgresrepository.PostgresRepository)(nil)
+
+	_ repository.Pinger = (*neo4jrepository.Neo4jRepository)(nil)
+	_ repository.Pinger = (*mongorepository.MongoRepository)(nil)
+	_ repository.Pinger = (*postgresrepository.PostgresRepository)(nil)
 )
 
 // GetNeo4jConfig creates a Neo4jConfig from environment variables
 func GetNeo4jConfig() *config.Neo4jConfig {
 	return &config.Neo4jConfig{
-		URI:      os.Getenv("NEO4J_URI"),
-		Username: os.Getenv("NEO4J_USER"),
-		Password: os.Getenv("NEO4J_PASSWORD"),
+		URI:                          os.Getenv("NEO4J_URI"),
+		Username:                     os.Getenv("NEO4J_USER"),
+		Password:                     os.Getenv("NEO4J_PASSWORD"),
+		MaxConnectionPoolSize:        envInt("NEO4J_MAX_POOL_SIZE"),
+		ConnectionAcquisitionTimeout: envDuration("NEO4J_ACQUISITION_TIMEOUT"),
 	}
 }
 
 // GetMongoConfig creates a MongoConfig from environment variables
 func GetMongoConfig() *config.MongoConfig {
 	return &config.MongoConfig{
-		URI:        os.Getenv("MONGO_URI"),
-		DBName:     os.Getenv("MONGO_DB_NAME"),
-		Collection: os.Getenv("MONGO_COLLECTION"),
+		URI:         os.Getenv("MONGO_URI"),
+		DBName:      os.Getenv("MONGO_DB_NAME"),
+		Collection:  os.Getenv("MONGO_COLLECTION"),
+		MaxPoolSize: uint64(envInt("MONGO_MAX_POOL_SIZE")),
+		MinPoolSize: uint64(envInt("MONGO_MIN_POOL_SIZE")),
 	}
 }
 
@@ -49,12 +69,19 @@ func GetMongoRepository(ctx context.Context) *mongorepository.MongoRepository {
 // GetPostgresConfig creates a PostgresConfig from environment variables
 func GetPostgresConfig() postgresrepository.Config {
 	return postgresrepository.Config{
-		Host:     os.Getenv("POSTGRES_HOST"),
-		Port:     os.Getenv("POSTGRES_PORT"),
-		User:     os.Getenv("POSTGRES_USER"),
-		Password: os.Getenv("POSTGRES_PASSWORD"),
-		DBName:   os.Getenv("POSTGRES_DB"),
-		SSLMode:  os.Getenv("POSTGRES_SSL_MODE"),
+		Host:            os.Getenv("POSTGRES_HOST"),
+		Port:            os.Getenv("POSTGRES_PORT"),
+		User:            os.Getenv("POSTGRES_USER"),
+		Password:        os.Getenv("POSTGRES_PASSWORD"),
+		DBName:          os.Getenv("POSTGRES_DB"),
+		SSLMode:         os.Getenv("POSTGRES_SSL_MODE"),
+		MaxOpenConns:    envInt("POSTGRES_MAX_OPEN_CONNS"),
+		MaxIdleConns:    envInt("POSTGRES_MAX_IDLE_CONNS"),
+		ConnMaxLifetime: envDuration("POSTGRES_CONN_MAX_LIFETIME"),
+
+		MaxTenantPools:        envInt("POSTGRES_MAX_TENANT_POOLS"),
+		TenantMaxOpenConns:    envInt("POSTGRES_TENANT_MAX_OPEN_CONNS"),
+		TenantPoolIdleTimeout: envDuration("POSTGRES_TENANT_POOL_IDLE_TIMEOUT"),
 	}
 }
 
@@ -67,3 +94,55 @@ func GetPostgresRepository(ctx context.Context) (*postgresrepository.PostgresRep
 	}
 	return repo, nil
 }
+
+// GetObjectStoreConfig creates an object store Config from environment variables
+func GetObjectStoreConfig() objectstore.Config {
+	virtualHost, _ := strconv.ParseBool(os.Getenv("BLOB_S3_VIRTUAL_HOST"))
+	return objectstore.Config{
+		Backend:     os.Getenv("BLOB_STORE_BACKEND"),
+		Path:        os.Getenv("BLOB_STORE_PATH"),
+		Endpoint:    os.Getenv("BLOB_S3_ENDPOINT"),
+		Region:      os.Getenv("BLOB_S3_REGION"),
+		Bucket:      os.Getenv("BLOB_S3_BUCKET"),
+		AccessKey:   os.Getenv("BLOB_S3_ACCESS_KEY"),
+		SecretKey:   os.Getenv("BLOB_S3_SECRET_KEY"),
+		VirtualHost: virtualHost,
+	}
+}
+
+// GetObjectStore retrieves the object store blobs are kept in
+func GetObjectStore() (objectstore.Store, error) {
+	store, err := objectstore.New(GetObjectStoreConfig())
+	if err != nil {
+		return nil, fmt.Errorf("[Commons] failed to create object store: %w", err)
+	}
+	return store, nil
+}
+
+// envInt reads a non-negative integer setting, returning 0 (the default) when it is unset or invalid
+func envInt(name string) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		slog.Warn("Ignoring invalid setting, expected a non-negative integer", "name", name, "value", value)
+		return 0
+	}
+	return n
+}
+
+// envDuration reads a duration setting such as "30s", returning 0 (the default) when it is unset or invalid
+func envDuration(name string) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		slog.Warn("Ignoring invalid setting, expected a duration such as 30s", "name", name, "value", value)
+		return 0
+	}
+	return d
+}
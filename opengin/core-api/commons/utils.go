@@ -5,7 +5,9 @@ import (
 	"fmt"
 	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
 	"lk/datafoundation/core-api/pkg/storageinference"
-	"log"
+	"log/slog"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -54,6 +56,8 @@ func ConvertStorageTypeStringToEnum(storageTypeStr string) storageinference.Stor
 		return storageinference.ListData
 	case "scalar":
 		return storageinference.ScalarData
+	case "blob":
+		return storageinference.BlobData
 	default:
 		return storageinference.UnknownData
 	}
@@ -74,7 +78,7 @@ func ExtractAttributeMetadataFields(entity *pb.Entity) (storageTypeStr, storageP
 	updatedStr = ExtractStringFromAny(metadataMap["updated"])
 	schemaStr := ExtractStringFromAny(metadataMap["schema"])
 
-	log.Printf("[Commons.ExtractAttributeMetadataFields] storageTypeStr: %s, storagePath: %s, updatedStr: %s, schemaStr: %s", storageTypeStr, storagePath, updatedStr, schemaStr)
+	slog.Debug("Extracted attribute metadata fields", "storage_type", storageTypeStr, "storage_path", storagePath, "updated", updatedStr, "schema", schemaStr)
 
 	// Convert schema JSON string to map
 	schemaMap, err := ConvertJSONStringToMap(schemaStr)
@@ -86,6 +90,20 @@ func ExtractAttributeMetadataFields(entity *pb.Entity) (storageTypeStr, storageP
 	return storageTypeStr, storagePath, updatedStr, schemaMap
 }
 
+// ExtractBlobMetadataFields extracts the content details of a blob attribute from a MongoDB entity
+func ExtractBlobMetadataFields(entity *pb.Entity) (contentHash, mimeType, fileName string, size int64) {
+	if entity == nil || entity.Metadata == nil {
+		return "", "", "", 0
+	}
+
+	contentHash = ExtractStringFromAny(entity.Metadata["content_hash"])
+	mimeType = ExtractStringFromAny(entity.Metadata["mime_type"])
+	fileName = ExtractStringFromAny(entity.Metadata["file_name"])
+	size, _ = strconv.ParseInt(ExtractStringFromAny(entity.Metadata["size"]), 10, 64)
+
+	return contentHash, mimeType, fileName, size
+}
+
 // ConvertMapToAnyMap converts map[string]interface{} to map[string]*anypb.Any
 func ConvertMapToAnyMap(input map[string]interface{}) map[string]*anypb.Any {
 	result := make(map[string]*anypb.Any)
@@ -149,13 +167,21 @@ func ParseTimestamp(timestampStr string, context string) time.Time {
 
 	parsed, err := time.Parse(time.RFC3339, timestampStr)
 	if err != nil {
-		log.Printf("[Commons.ParseTimestamp] Warning: Failed to parse timestamp '%s' for %s, using zero value", timestampStr, context)
+		slog.Warn("Failed to parse timestamp, using zero value", "timestamp", timestampStr, "context", context)
 		return time.Time{} // Return zero value for invalid timestamps
 	}
 
 	return parsed
 }
 
+// graphIdentifierPattern matches the kinds and relationship names that can be used as Neo4j labels and relationship types
+var graphIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// IsGraphIdentifier reports whether a kind or relationship name can be used as a Neo4j label or relationship type
+func IsGraphIdentifier(s string) bool {
+	return graphIdentifierPattern.MatchString(s)
+}
+
 // SanitizeIdentifier makes a string safe for use as a PostgreSQL identifier
 // IMPROVEME: https://github.com/LDFLK/nexoan/issues/160
 func SanitizeIdentifier(s string) string {
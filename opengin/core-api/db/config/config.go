@@ -1,15 +1,25 @@
 package config
 
+import "time"
+
 type MongoConfig struct {
 	URI        string `env:"MONGO_URI"`
 	DBName     string `env:"MONGO_DB_NAME"`
 	Collection string `env:"MONGO_COLLECTION"`
+
+	// Connection pool, the driver defaults apply when zero
+	MaxPoolSize uint64 `env:"MONGO_MAX_POOL_SIZE"`
+	MinPoolSize uint64 `env:"MONGO_MIN_POOL_SIZE"`
 }
 
 type Neo4jConfig struct {
 	URI      string `env:"NEO4J_URI"`
 	Username string `env:"NEO4J_USER"`
 	Password string `env:"NEO4J_PASSWORD"`
+
+	// Connection pool, the driver defaults apply when zero
+	MaxConnectionPoolSize        int           `env:"NEO4J_MAX_POOL_SIZE"`
+	ConnectionAcquisitionTimeout time.Duration `env:"NEO4J_ACQUISITION_TIMEOUT"`
 }
 
 type PostgresConfig struct {
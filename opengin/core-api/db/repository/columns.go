@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"fmt"
+
+	commons "lk/datafoundation/core-api/commons"
+	"lk/datafoundation/core-api/pkg/schema"
+)
+
+// BookkeepingColumns are the columns every attribute table has besides the data: the row id, the
+// entity_attributes row the row belongs to and the time it was written
+var BookkeepingColumns = []string{"id", "entity_attribute_id", "created_at"}
+
+// maxColumnBase leaves room for a numeric suffix within the 63 byte identifier limit of PostgreSQL
+const maxColumnBase = 54
+
+// AssignTableColumns lays out the table columns of new tabular data. Each column is stored under its
+// sanitized name, or under that name with its position appended when the name is taken by an earlier
+// column or a bookkeeping column, so a producer column called id or created_at is kept too.
+func AssignTableColumns(names []string) []schema.ColumnInfo {
+	taken := make(map[string]bool, len(names)+len(BookkeepingColumns))
+	for _, column := range BookkeepingColumns {
+		taken[column] = true
+	}
+
+	columns := make([]schema.ColumnInfo, len(names))
+	for i, name := range names {
+		base := commons.SanitizeIdentifier(name)
+		if len(base) > maxColumnBase {
+			base = base[:maxColumnBase]
+		}
+		if base == "" {
+			base = "column"
+		}
+		column := base
+		for n := i + 1; taken[column]; n++ {
+			column = fmt.Sprintf("%s_%d", base, n)
+		}
+		taken[column] = true
+		columns[i] = schema.ColumnInfo{Name: name, Column: column, Ordinal: i}
+	}
+	return columns
+}
+
+// TableColumn returns the table column holding a data column of a stored schema, or "" when the
+// schema has no such column. Schemas stored before the column layout was recorded keep every column
+// under its sanitized name.
+func TableColumn(info *schema.SchemaInfo, name string) string {
+	if len(info.Columns) == 0 {
+		return commons.SanitizeIdentifier(name)
+	}
+	for _, column := range info.Columns {
+		if column.Name == name {
+			return column.Column
+		}
+	}
+	return ""
+}
+
+// ResolveColumn finds the table column a reader means by a field or filter name: a data column by
+// its original name, or else a bookkeeping column
+func ResolveColumn(columns []schema.ColumnInfo, name string) (string, bool) {
+	for _, column := range columns {
+		if column.Name == name {
+			return column.Column, true
+		}
+	}
+	for _, column := range BookkeepingColumns {
+		if column == name {
+			return column, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,210 @@
+package memoryrepository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"lk/datafoundation/core-api/db/repository"
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// HandleGraphEntityCreation creates the node of an entity
+func (s *GraphStore) HandleGraphEntityCreation(ctx context.Context, entity *pb.Entity) (bool, error) {
+	if entity.Kind == nil || entity.Kind.GetMajor() == "" || entity.Kind.GetMinor() == "" ||
+		entity.Name == nil || entity.Name.GetValue() == nil || entity.Created == "" {
+		slog.ErrorContext(ctx, "Entity creation failed", "entity_id", entity.Id)
+		return false, fmt.Errorf("[memory.HandleGraphEntityCreation] missing required fields for graph entity creation")
+	}
+
+	var name wrapperspb.StringValue
+	if err := entity.Name.GetValue().UnmarshalTo(&name); err != nil {
+		return false, fmt.Errorf("[memory.HandleGraphEntityCreation] error unpacking Name value: %v", err)
+	}
+
+	entityMap := map[string]interface{}{
+		"Id":      entity.Id,
+		"Name":    name.Value,
+		"Created": entity.Created,
+	}
+	if entity.Terminated != "" {
+		entityMap["Terminated"] = entity.Terminated
+	}
+
+	kind := &pb.Kind{Major: entity.Kind.GetMajor(), Minor: entity.Kind.GetMinor()}
+	if _, err := s.CreateGraphEntity(ctx, kind, entityMap); err != nil {
+		slog.ErrorContext(ctx, "Error creating entity", "error", err)
+		return false, err
+	}
+	return true, nil
+}
+
+// HandleGraphEntityUpdate updates the name and termination time of an existing entity
+func (s *GraphStore) HandleGraphEntityUpdate(ctx context.Context, entity *pb.Entity) (bool, error) {
+	if entity.Id == "" {
+		return false, fmt.Errorf("[memory.HandleGraphEntityUpdate] entity ID is required")
+	}
+	if entity.Kind != nil && (entity.Kind.Major != "" || entity.Kind.Minor != "") {
+		slog.DebugContext(ctx, "Cannot update Kind", "entity_id", entity.Id)
+		return false, fmt.Errorf("[memory.HandleGraphEntityUpdate] Kind cannot be updated")
+	}
+
+	entityMap := map[string]interface{}{
+		"Id": entity.Id,
+	}
+	if entity.Name != nil && entity.Name.GetValue() != nil {
+		var name wrapperspb.StringValue
+		if err := entity.Name.GetValue().UnmarshalTo(&name); err != nil {
+			return false, fmt.Errorf("[memory.HandleGraphEntityUpdate] error unpacking Name value: %v", err)
+		}
+		if name.Value != "" {
+			entityMap["Name"] = name.Value
+		}
+	}
+	if entity.Terminated != "" {
+		entityMap["Terminated"] = entity.Terminated
+	}
+
+	if _, err := s.UpdateGraphEntity(ctx, entity.Id, entityMap); err != nil {
+		slog.ErrorContext(ctx, "Error updating entity", "error", err)
+		return false, err
+	}
+	return true, nil
+}
+
+// HandleGraphRelationshipsCreate creates the relationships of an entity
+func (s *GraphStore) HandleGraphRelationshipsCreate(ctx context.Context, entity *pb.Entity) error {
+	if len(entity.Relationships) == 0 {
+		return nil
+	}
+
+	if _, err := s.ReadGraphEntity(ctx, entity.Id); err != nil {
+		return fmt.Errorf("[memory.HandleGraphRelationshipsCreate] parent entity %s does not exist", entity.Id)
+	}
+
+	for _, relationship := range entity.Relationships {
+		if err := validateRelationshipCreation(relationship); err != nil {
+			return err
+		}
+		if _, err := s.ReadGraphEntity(ctx, relationship.RelatedEntityId); err != nil {
+			return fmt.Errorf("[memory.HandleGraphRelationshipsCreate] child entity %s does not exist", relationship.RelatedEntityId)
+		}
+		if _, err := s.CreateRelationship(ctx, entity.Id, relationship); err != nil {
+			slog.ErrorContext(ctx, "Error creating relationship", "entity_id", entity.Id, "related_entity_id", relationship.RelatedEntityId, "error", err)
+			return fmt.Errorf("[memory.HandleGraphRelationshipsCreate] error creating relationship: %v", err)
+		}
+	}
+	return nil
+}
+
+// HandleGraphRelationshipsUpdate updates the times of existing relationships and creates the new ones
+func (s *GraphStore) HandleGraphRelationshipsUpdate(ctx context.Context, entity *pb.Entity) error {
+	if len(entity.Relationships) == 0 {
+		return nil
+	}
+
+	if _, err := s.ReadGraphEntity(ctx, entity.Id); err != nil {
+		return fmt.Errorf("[memory.HandleGraphRelationshipsUpdate] parent entity %s does not exist", entity.Id)
+	}
+
+	for _, relationship := range entity.Relationships {
+		if relationship == nil || relationship.Id == "" {
+			return fmt.Errorf("relationship missing ID field")
+		}
+
+		if _, err := s.ReadRelationship(ctx, relationship.Id); err == nil {
+			// Only the time bounds of an existing relationship can change
+			var invalidFields []string
+			if relationship.Name != "" {
+				invalidFields = append(invalidFields, "Name")
+			}
+			if relationship.RelatedEntityId != "" {
+				invalidFields = append(invalidFields, "RelatedEntityId")
+			}
+			if relationship.Direction != "" {
+				invalidFields = append(invalidFields, "Direction")
+			}
+			if len(invalidFields) > 0 {
+				return fmt.Errorf("cannot update immutable fields: %v. Only StartTime and EndTime are allowed", invalidFields)
+			}
+
+			relationshipData := map[string]interface{}{}
+			if relationship.StartTime != "" {
+				relationshipData["Created"] = relationship.StartTime
+			}
+			if relationship.EndTime != "" {
+				relationshipData["Terminated"] = relationship.EndTime
+			}
+			if len(relationshipData) == 0 {
+				return fmt.Errorf("no valid fields provided for relationship update. Only StartTime and EndTime are allowed")
+			}
+
+			if _, err := s.UpdateRelationship(ctx, relationship.Id, relationshipData); err != nil {
+				slog.ErrorContext(ctx, "Failed to update relationship", "error", err)
+				return err
+			}
+			continue
+		}
+
+		if err := validateRelationshipCreation(relationship); err != nil {
+			return err
+		}
+		if _, err := s.ReadGraphEntity(ctx, relationship.RelatedEntityId); err != nil {
+			return fmt.Errorf("[memory.HandleGraphRelationshipsUpdate] child entity %s does not exist", relationship.RelatedEntityId)
+		}
+		if _, err := s.CreateRelationship(ctx, entity.Id, relationship); err != nil {
+			slog.ErrorContext(ctx, "Failed to create relationship", "error", err)
+			return fmt.Errorf("[memory.HandleGraphRelationshipsUpdate] failed to create relationship: %v", err)
+		}
+	}
+	return nil
+}
+
+// validateRelationshipCreation checks that a new relationship has the fields required to create it
+func validateRelationshipCreation(relationship *pb.Relationship) error {
+	if relationship == nil || relationship.Id == "" {
+		return fmt.Errorf("relationship missing ID field")
+	}
+	if relationship.RelatedEntityId == "" {
+		return fmt.Errorf("missing RelatedEntityId for relationship %s. Required for creation", relationship.Id)
+	}
+	if relationship.Name == "" {
+		return fmt.Errorf("missing Name for relationship %s. Required for creation", relationship.Id)
+	}
+	if err := repository.ValidateGraphIdentifier("relationship name", relationship.Name); err != nil {
+		return err
+	}
+	if relationship.StartTime == "" {
+		return fmt.Errorf("missing StartTime for relationship %s. Required for creation", relationship.Id)
+	}
+	return nil
+}
+
+// HandleGraphEntityFilter returns the entities matching a read request
+func (s *GraphStore) HandleGraphEntityFilter(ctx context.Context, req *pb.ReadEntityRequest) ([]map[string]interface{}, error) {
+	if req == nil || req.Entity == nil {
+		return nil, fmt.Errorf("invalid request: ReadEntityRequest or Entity is nil")
+	}
+
+	filters := make(map[string]interface{})
+	if req.Entity.Id != "" {
+		filters["id"] = req.Entity.Id
+	} else {
+		if req.Entity.Name != nil && req.Entity.Name.Value != nil {
+			var name wrapperspb.StringValue
+			if err := req.Entity.Name.Value.UnmarshalTo(&name); err == nil {
+				filters["name"] = name.Value
+			}
+		}
+		if req.Entity.Created != "" {
+			filters["created"] = req.Entity.Created
+		}
+		if req.Entity.Terminated != "" {
+			filters["terminated"] = req.Entity.Terminated
+		}
+	}
+
+	return s.FilterEntities(ctx, req.Entity.Kind, filters)
+}
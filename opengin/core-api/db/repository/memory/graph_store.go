@@ -0,0 +1,870 @@
+package memoryrepository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"lk/datafoundation/core-api/db/repository"
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/tenant"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// DefaultPathMaxDepth is the number of hops searched when no maximum depth is given
+const DefaultPathMaxDepth = 10
+
+// MaxPathMaxDepth bounds the search depth of a path query
+const MaxPathMaxDepth = 25
+
+type graphNode struct {
+	id         string
+	name       string
+	majorKind  string
+	minorKind  string
+	created    time.Time
+	terminated *time.Time
+}
+
+type graphRelationship struct {
+	id         string
+	name       string
+	startID    string
+	endID      string
+	created    time.Time
+	terminated *time.Time
+}
+
+// graph holds the entities and relationships of one tenant
+type graph struct {
+	nodes         map[string]*graphNode
+	nodeOrder     []string
+	relationships map[string]*graphRelationship
+	relOrder      []string
+}
+
+// GraphStore keeps entities and relationships in memory with the semantics of the Neo4j repository.
+// Timestamps are parsed on the way in and compared as instants, like Neo4j datetime values.
+// Every tenant has a graph of its own.
+type GraphStore struct {
+	mu     sync.RWMutex
+	graphs map[string]*graph
+}
+
+// NewGraphStore creates an empty in-memory graph store
+func NewGraphStore() *GraphStore {
+	return &GraphStore{graphs: make(map[string]*graph)}
+}
+
+// graphOf returns the graph of the call's tenant, empty when the tenant has none. The caller holds s.mu.
+func (s *GraphStore) graphOf(ctx context.Context) *graph {
+	if g, ok := s.graphs[tenant.FromContext(ctx)]; ok {
+		return g
+	}
+	return &graph{}
+}
+
+// writableGraph returns the graph of the call's tenant, creating it when needed. The caller holds s.mu for writing.
+func (s *GraphStore) writableGraph(ctx context.Context) *graph {
+	id := tenant.FromContext(ctx)
+	g, ok := s.graphs[id]
+	if !ok {
+		g = &graph{
+			nodes:         make(map[string]*graphNode),
+			relationships: make(map[string]*graphRelationship),
+		}
+		s.graphs[id] = g
+	}
+	return g
+}
+
+// parseDateTime parses a timestamp the way Neo4j's datetime() does for the formats the API uses
+func parseDateTime(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("text cannot be parsed to a datetime: %s", value)
+}
+
+// formatDateTime renders a timestamp like Neo4j's toString() of a datetime
+func formatDateTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+// activeAt reports whether a relationship started at or before t and had not ended by then
+func (rel *graphRelationship) activeAt(t time.Time) bool {
+	return !rel.created.After(t) && (rel.terminated == nil || rel.terminated.After(t))
+}
+
+// CreateGraphEntity creates the node of an entity, failing when the Id is taken
+func (s *GraphStore) CreateGraphEntity(ctx context.Context, kind *pb.Kind, entityMap map[string]interface{}) (map[string]interface{}, error) {
+	if kind == nil || kind.Major == "" {
+		return nil, fmt.Errorf("[memory.CreateGraphEntity] missing or invalid 'Kind.Major' field")
+	}
+	if err := repository.ValidateGraphIdentifier("kind", kind.Major); err != nil {
+		return nil, err
+	}
+	id, ok := entityMap["Id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("[memory.CreateGraphEntity] missing or invalid 'Id' field")
+	}
+	name, ok := entityMap["Name"].(string)
+	if !ok {
+		return nil, fmt.Errorf("[memory.CreateGraphEntity] missing or invalid 'Name' field")
+	}
+	createdStr, ok := entityMap["Created"].(string)
+	if !ok {
+		return nil, fmt.Errorf("[memory.CreateGraphEntity] missing or invalid 'Created' field")
+	}
+
+	node := &graphNode{id: id, name: name, majorKind: kind.Major, minorKind: kind.Minor}
+	created, err := parseDateTime(createdStr)
+	if err != nil {
+		return nil, fmt.Errorf("[memory.CreateGraphEntity] error creating entity: %v", err)
+	}
+	node.created = created
+	if terminatedStr, ok := entityMap["Terminated"].(string); ok {
+		terminated, err := parseDateTime(terminatedStr)
+		if err != nil {
+			return nil, fmt.Errorf("[memory.CreateGraphEntity] error creating entity: %v", err)
+		}
+		node.terminated = &terminated
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g := s.writableGraph(ctx)
+
+	if _, exists := g.nodes[id]; exists {
+		return nil, fmt.Errorf("[memory.CreateGraphEntity] entity with Id %s already exists", id)
+	}
+	g.nodes[id] = node
+	g.nodeOrder = append(g.nodeOrder, id)
+
+	return nodeToMap(node), nil
+}
+
+// ReadGraphEntity returns the properties of an entity node
+func (s *GraphStore) ReadGraphEntity(ctx context.Context, entityID string) (map[string]interface{}, error) {
+	if entityID == "" {
+		return nil, fmt.Errorf("entity Id cannot be empty")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	g := s.graphOf(ctx)
+
+	node, ok := g.nodes[entityID]
+	if !ok {
+		return nil, fmt.Errorf("entity with Id %s not found", entityID)
+	}
+	return nodeToMap(node), nil
+}
+
+// UpdateGraphEntity sets the name and termination time of an existing entity
+func (s *GraphStore) UpdateGraphEntity(ctx context.Context, id string, updateData map[string]interface{}) (map[string]interface{}, error) {
+	if id == "" {
+		return nil, fmt.Errorf("entity Id cannot be empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g := s.writableGraph(ctx)
+
+	node, ok := g.nodes[id]
+	if !ok {
+		return nil, fmt.Errorf("entity with Id %s does not exist", id)
+	}
+
+	var terminated *time.Time
+	if value, exists := updateData["Terminated"]; exists {
+		t, err := parseDateTime(fmt.Sprintf("%v", value))
+		if err != nil {
+			return nil, fmt.Errorf("error updating entity: %v", err)
+		}
+		terminated = &t
+	}
+	if name, exists := updateData["Name"]; exists {
+		node.name = fmt.Sprintf("%v", name)
+	}
+	if terminated != nil {
+		node.terminated = terminated
+	}
+
+	return nodeToMap(node), nil
+}
+
+// CreateRelationship creates a relationship from entityID to the related entity
+func (s *GraphStore) CreateRelationship(ctx context.Context, entityID string, rel *pb.Relationship) (map[string]interface{}, error) {
+	if err := repository.ValidateGraphIdentifier("relationship name", rel.Name); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g := s.writableGraph(ctx)
+
+	if _, exists := g.relationships[rel.Id]; exists {
+		return nil, fmt.Errorf("relationship with Id %s already exists", rel.Id)
+	}
+	if g.nodes[entityID] == nil || g.nodes[rel.RelatedEntityId] == nil {
+		return nil, fmt.Errorf("either parent or child entity does not exist")
+	}
+
+	relationship := &graphRelationship{id: rel.Id, name: rel.Name, startID: entityID, endID: rel.RelatedEntityId}
+	created, err := parseDateTime(rel.StartTime)
+	if err != nil {
+		return nil, fmt.Errorf("error creating relationship: %v", err)
+	}
+	relationship.created = created
+	if rel.EndTime != "" {
+		terminated, err := parseDateTime(rel.EndTime)
+		if err != nil {
+			return nil, fmt.Errorf("error creating relationship: %v", err)
+		}
+		relationship.terminated = &terminated
+	}
+
+	g.relationships[rel.Id] = relationship
+	g.relOrder = append(g.relOrder, rel.Id)
+
+	relationshipMap := map[string]interface{}{
+		"Id":               relationship.id,
+		"relationshipType": relationship.name,
+		"Created":          formatDateTime(relationship.created),
+	}
+	if relationship.terminated != nil {
+		relationshipMap["Terminated"] = formatDateTime(*relationship.terminated)
+	}
+	return relationshipMap, nil
+}
+
+// ReadRelationship returns a relationship by its Id
+func (s *GraphStore) ReadRelationship(ctx context.Context, relationshipID string) (map[string]interface{}, error) {
+	if relationshipID == "" {
+		return nil, fmt.Errorf("relationship Id cannot be empty")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	g := s.graphOf(ctx)
+
+	rel, ok := g.relationships[relationshipID]
+	if !ok {
+		return nil, fmt.Errorf("relationship with Id %s not found", relationshipID)
+	}
+
+	relationship := map[string]interface{}{
+		"type":           rel.name,
+		"startEntityID":  rel.startID,
+		"endEntityID":    rel.endID,
+		"Created":        formatDateTime(rel.created),
+		"relationshipID": rel.id,
+	}
+	if rel.terminated != nil {
+		relationship["Terminated"] = formatDateTime(*rel.terminated)
+	}
+	return relationship, nil
+}
+
+// UpdateRelationship sets the start and end time of an existing relationship
+func (s *GraphStore) UpdateRelationship(ctx context.Context, relationshipID string, updateData map[string]interface{}) (map[string]interface{}, error) {
+	if relationshipID == "" {
+		return nil, fmt.Errorf("relationship Id cannot be empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g := s.writableGraph(ctx)
+
+	rel, ok := g.relationships[relationshipID]
+	if !ok {
+		return nil, fmt.Errorf("relationship with Id %s does not exist", relationshipID)
+	}
+
+	for key := range updateData {
+		if key != "Created" && key != "Terminated" {
+			return nil, fmt.Errorf("unsupported field '%s' for relationship update. Only 'Created' and 'Terminated' are allowed", key)
+		}
+	}
+	if len(updateData) == 0 {
+		return nil, fmt.Errorf("no valid fields provided for update")
+	}
+
+	created := rel.created
+	terminated := rel.terminated
+	if value, exists := updateData["Created"]; exists {
+		t, err := parseDateTime(fmt.Sprintf("%v", value))
+		if err != nil {
+			return nil, fmt.Errorf("error updating relationship: %v", err)
+		}
+		created = t
+	}
+	if value, exists := updateData["Terminated"]; exists {
+		t, err := parseDateTime(fmt.Sprintf("%v", value))
+		if err != nil {
+			return nil, fmt.Errorf("error updating relationship: %v", err)
+		}
+		terminated = &t
+	}
+	rel.created = created
+	rel.terminated = terminated
+
+	updated := map[string]interface{}{
+		"Id":      rel.id,
+		"Created": formatDateTime(rel.created),
+	}
+	if rel.terminated != nil {
+		updated["Terminated"] = formatDateTime(*rel.terminated)
+	}
+	return updated, nil
+}
+
+// ReadRelationships returns the relationships of an entity in both directions
+func (s *GraphStore) ReadRelationships(ctx context.Context, entityID string) ([]map[string]interface{}, error) {
+	if entityID == "" {
+		return nil, fmt.Errorf("entity Id cannot be empty")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	g := s.graphOf(ctx)
+
+	var relationships []map[string]interface{}
+	for _, direction := range []string{"OUTGOING", "INCOMING"} {
+		for _, rel := range g.relationshipsOf(entityID, direction) {
+			relationship := map[string]interface{}{
+				"type":           rel.name,
+				"relatedID":      rel.otherEnd(entityID, direction),
+				"direction":      direction,
+				"Created":        formatDateTime(rel.created),
+				"relationshipID": rel.id,
+			}
+			if rel.terminated != nil {
+				relationship["Terminated"] = formatDateTime(*rel.terminated)
+			}
+			relationships = append(relationships, relationship)
+		}
+	}
+	return relationships, nil
+}
+
+// ReadFilteredRelationships returns the relationships of an entity matching the filters and active at activeAt
+func (s *GraphStore) ReadFilteredRelationships(ctx context.Context, entityID string, relationshipFilters map[string]interface{}, activeAt string) ([]map[string]interface{}, error) {
+	if entityID == "" {
+		return nil, fmt.Errorf("entity Id cannot be empty")
+	}
+
+	id, _ := relationshipFilters["id"].(string)
+	relatedEntityID, _ := relationshipFilters["relatedEntityId"].(string)
+	name, _ := relationshipFilters["name"].(string)
+
+	var startTime, endTime, activeAtTime *time.Time
+	for _, bound := range []struct {
+		value  string
+		target **time.Time
+	}{
+		{stringFilter(relationshipFilters, "startTime"), &startTime},
+		{stringFilter(relationshipFilters, "endTime"), &endTime},
+		{activeAt, &activeAtTime},
+	} {
+		if bound.value == "" {
+			continue
+		}
+		t, err := parseDateTime(bound.value)
+		if err != nil {
+			return nil, fmt.Errorf("error querying relationships: %v", err)
+		}
+		*bound.target = &t
+	}
+
+	directions := []string{"OUTGOING", "INCOMING"}
+	if direction, ok := relationshipFilters["direction"].(string); ok && (direction == "OUTGOING" || direction == "INCOMING") {
+		directions = []string{direction}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	g := s.graphOf(ctx)
+
+	var relationships []map[string]interface{}
+	for _, direction := range directions {
+		for _, rel := range g.relationshipsOf(entityID, direction) {
+			relatedID := rel.otherEnd(entityID, direction)
+			if id != "" && rel.id != id {
+				continue
+			}
+			if relatedEntityID != "" && relatedID != relatedEntityID {
+				continue
+			}
+			if name != "" && rel.name != name {
+				continue
+			}
+			if startTime != nil && !rel.created.Equal(*startTime) {
+				continue
+			}
+			if endTime != nil && (rel.terminated == nil || !rel.terminated.Equal(*endTime)) {
+				continue
+			}
+			if activeAtTime != nil && !rel.activeAt(*activeAtTime) {
+				continue
+			}
+
+			var formattedEndTime string
+			if rel.terminated != nil {
+				formattedEndTime = formatDateTime(*rel.terminated)
+			}
+			relationships = append(relationships, map[string]interface{}{
+				"id":              rel.id,
+				"name":            rel.name,
+				"relatedEntityId": relatedID,
+				"startTime":       formatDateTime(rel.created),
+				"endTime":         formattedEndTime,
+				"direction":       direction,
+			})
+		}
+	}
+	return relationships, nil
+}
+
+// FilterEntities returns the entities of a kind matching the filters, or the entity with the given id
+func (s *GraphStore) FilterEntities(ctx context.Context, kind *pb.Kind, filters map[string]interface{}) ([]map[string]interface{}, error) {
+	id := stringFilter(filters, "id")
+	if id == "" && (kind == nil || kind.Major == "") {
+		return nil, fmt.Errorf("kind.Major is required")
+	}
+	if id == "" {
+		if err := repository.ValidateGraphIdentifier("kind", kind.Major); err != nil {
+			return nil, err
+		}
+	}
+
+	var created, terminated *time.Time
+	if id == "" {
+		for _, bound := range []struct {
+			value  string
+			target **time.Time
+		}{
+			{stringFilter(filters, "created"), &created},
+			{stringFilter(filters, "terminated"), &terminated},
+		} {
+			if bound.value == "" {
+				continue
+			}
+			t, err := parseDateTime(bound.value)
+			if err != nil {
+				return nil, fmt.Errorf("error querying entities: %v", err)
+			}
+			*bound.target = &t
+		}
+	}
+	name := stringFilter(filters, "name")
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	g := s.graphOf(ctx)
+
+	var entities []map[string]interface{}
+	for _, nodeID := range g.nodeOrder {
+		node := g.nodes[nodeID]
+		if id != "" {
+			if node.id != id {
+				continue
+			}
+		} else {
+			if node.majorKind != kind.Major {
+				continue
+			}
+			if kind.Minor != "" && node.minorKind != kind.Minor {
+				continue
+			}
+			if created != nil && !node.created.Equal(*created) {
+				continue
+			}
+			if terminated != nil && (node.terminated == nil || !node.terminated.Equal(*terminated)) {
+				continue
+			}
+			if name != "" && node.name != name {
+				continue
+			}
+		}
+
+		var terminatedValue interface{}
+		if node.terminated != nil {
+			terminatedValue = formatDateTime(*node.terminated)
+		}
+		entities = append(entities, map[string]interface{}{
+			"id":         node.id,
+			"kind":       node.majorKind,
+			"created":    formatDateTime(node.created),
+			"terminated": terminatedValue,
+			"name":       node.name,
+			"minorKind":  node.minorKind,
+		})
+	}
+	return entities, nil
+}
+
+// CountEntities returns the number of entities of the call's tenant, not counting attribute datasets
+func (s *GraphStore) CountEntities(ctx context.Context) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	g := s.graphOf(ctx)
+
+	var count int64
+	for _, node := range g.nodes {
+		if node.majorKind != "Dataset" {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ReadShortestPaths finds the shortest path (or every shortest path) between two entities.
+// It follows the contract of the Neo4j repository: only relationships named in relationshipNames are
+// traversed (all when empty), every hop must be active at activeAt when it is set, and each returned
+// path has an ordered "entities" list and an ordered "relationships" list.
+func (s *GraphStore) ReadShortestPaths(ctx context.Context, sourceID string, targetID string, relationshipNames []string, activeAt string, direction string, maxDepth int, allShortest bool) ([]map[string]interface{}, error) {
+	if sourceID == "" || targetID == "" {
+		return nil, fmt.Errorf("source and target entity Ids cannot be empty")
+	}
+	if sourceID == targetID {
+		return nil, fmt.Errorf("source and target entity Ids must be different")
+	}
+
+	if maxDepth <= 0 {
+		maxDepth = DefaultPathMaxDepth
+	}
+	if maxDepth > MaxPathMaxDepth {
+		return nil, fmt.Errorf("maxDepth %d exceeds the allowed maximum of %d", maxDepth, MaxPathMaxDepth)
+	}
+
+	var directions []string
+	switch direction {
+	case "OUTGOING", "INCOMING":
+		directions = []string{direction}
+	case "":
+		directions = []string{"OUTGOING", "INCOMING"}
+	default:
+		return nil, fmt.Errorf("invalid direction %s, expected OUTGOING or INCOMING", direction)
+	}
+
+	var activeAtTime *time.Time
+	if activeAt != "" {
+		t, err := parseDateTime(activeAt)
+		if err != nil {
+			return nil, fmt.Errorf("error querying paths: %v", err)
+		}
+		activeAtTime = &t
+	}
+	names := make(map[string]bool, len(relationshipNames))
+	for _, name := range relationshipNames {
+		names[name] = true
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	g := s.graphOf(ctx)
+
+	if g.nodes[sourceID] == nil || g.nodes[targetID] == nil {
+		return nil, nil
+	}
+
+	type hop struct {
+		from string
+		rel  *graphRelationship
+	}
+
+	// Breadth-first search recording every hop that reaches a node at its shortest distance
+	distance := map[string]int{sourceID: 0}
+	parents := make(map[string][]hop)
+	frontier := []string{sourceID}
+	for depth := 1; depth <= maxDepth && len(frontier) > 0; depth++ {
+		if _, reached := distance[targetID]; reached {
+			break
+		}
+		var next []string
+		for _, nodeID := range frontier {
+			for _, dir := range directions {
+				for _, rel := range g.relationshipsOf(nodeID, dir) {
+					if len(names) > 0 && !names[rel.name] {
+						continue
+					}
+					if activeAtTime != nil && !rel.activeAt(*activeAtTime) {
+						continue
+					}
+					neighbour := rel.otherEnd(nodeID, dir)
+					if d, seen := distance[neighbour]; seen && d < depth {
+						continue
+					}
+					if _, seen := distance[neighbour]; !seen {
+						distance[neighbour] = depth
+						next = append(next, neighbour)
+					}
+					parents[neighbour] = append(parents[neighbour], hop{from: nodeID, rel: rel})
+				}
+			}
+		}
+		frontier = next
+	}
+
+	if _, reached := distance[targetID]; !reached {
+		return nil, nil
+	}
+
+	// Walk the recorded hops back from the target to enumerate the shortest paths
+	var paths []map[string]interface{}
+	var walk func(nodeID string, nodes []string, rels []*graphRelationship) bool
+	walk = func(nodeID string, nodes []string, rels []*graphRelationship) bool {
+		nodes = append([]string{nodeID}, nodes...)
+		if nodeID == sourceID {
+			paths = append(paths, g.pathToMap(nodes, rels))
+			return !allShortest
+		}
+		for _, parent := range parents[nodeID] {
+			if walk(parent.from, nodes, append([]*graphRelationship{parent.rel}, rels...)) {
+				return true
+			}
+		}
+		return false
+	}
+	walk(targetID, nil, nil)
+
+	return paths, nil
+}
+
+// pathToMap renders a path in the shape returned by the Neo4j path projection
+func (g *graph) pathToMap(nodeIDs []string, rels []*graphRelationship) map[string]interface{} {
+	entities := make([]map[string]interface{}, 0, len(nodeIDs))
+	for _, nodeID := range nodeIDs {
+		node := g.nodes[nodeID]
+		entity := map[string]interface{}{
+			"Id":        node.id,
+			"Name":      node.name,
+			"MajorKind": node.majorKind,
+			"MinorKind": node.minorKind,
+			"Created":   formatDateTime(node.created),
+		}
+		if node.terminated != nil {
+			entity["Terminated"] = formatDateTime(*node.terminated)
+		}
+		entities = append(entities, entity)
+	}
+
+	relationships := make([]map[string]interface{}, 0, len(rels))
+	for _, rel := range rels {
+		relationship := map[string]interface{}{
+			"id":            rel.id,
+			"name":          rel.name,
+			"startEntityId": rel.startID,
+			"endEntityId":   rel.endID,
+			"startTime":     formatDateTime(rel.created),
+		}
+		if rel.terminated != nil {
+			relationship["endTime"] = formatDateTime(*rel.terminated)
+		}
+		relationships = append(relationships, relationship)
+	}
+
+	return map[string]interface{}{
+		"entities":      entities,
+		"relationships": relationships,
+	}
+}
+
+// relationshipsOf returns the relationships leaving (OUTGOING) or entering (INCOMING) an entity in creation order.
+// The caller must hold the lock.
+func (g *graph) relationshipsOf(entityID string, direction string) []*graphRelationship {
+	var relationships []*graphRelationship
+	for _, relID := range g.relOrder {
+		rel := g.relationships[relID]
+		if (direction == "OUTGOING" && rel.startID == entityID) || (direction == "INCOMING" && rel.endID == entityID) {
+			relationships = append(relationships, rel)
+		}
+	}
+	return relationships
+}
+
+// otherEnd returns the entity at the far end of a relationship seen from entityID in the given direction
+func (rel *graphRelationship) otherEnd(entityID string, direction string) string {
+	if direction == "OUTGOING" {
+		return rel.endID
+	}
+	return rel.startID
+}
+
+func nodeToMap(node *graphNode) map[string]interface{} {
+	entity := map[string]interface{}{
+		"Id":        node.id,
+		"Name":      node.name,
+		"Created":   formatDateTime(node.created),
+		"MajorKind": node.majorKind,
+		"MinorKind": node.minorKind,
+	}
+	if node.terminated != nil {
+		entity["Terminated"] = formatDateTime(*node.terminated)
+	}
+	return entity
+}
+
+func stringFilter(filters map[string]interface{}, key string) string {
+	value, _ := filters[key].(string)
+	return value
+}
+
+// GetGraphEntity returns the kind, name, created and terminated time of an entity
+func (s *GraphStore) GetGraphEntity(ctx context.Context, entityId string) (*pb.Kind, *pb.TimeBasedValue, string, string, error) {
+	entityMap, err := s.ReadGraphEntity(ctx, entityId)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error reading entity", "entity_id", entityId, "error", err)
+		return nil, nil, "", "", fmt.Errorf("[memory.GetGraphEntity] error reading entity: %v", err)
+	}
+
+	kind := &pb.Kind{
+		Major: entityMap["MajorKind"].(string),
+		Minor: entityMap["MinorKind"].(string),
+	}
+	created := entityMap["Created"].(string)
+	terminated, _ := entityMap["Terminated"].(string)
+
+	value, _ := anypb.New(&wrapperspb.StringValue{
+		Value: entityMap["Name"].(string),
+	})
+	name := &pb.TimeBasedValue{
+		StartTime: created,
+		EndTime:   terminated,
+		Value:     value,
+	}
+
+	return kind, name, created, terminated, nil
+}
+
+// GetGraphRelationships returns the outgoing relationships of an entity keyed by relationship id
+func (s *GraphStore) GetGraphRelationships(ctx context.Context, entityId string) (map[string]*pb.Relationship, error) {
+	relationships := make(map[string]*pb.Relationship)
+	relData, err := s.ReadRelationships(ctx, entityId)
+	if err != nil {
+		return relationships, fmt.Errorf("[memory.GetGraphRelationships] error reading relationships: %v", err)
+	}
+
+	for _, rel := range relData {
+		if rel["direction"] != "OUTGOING" {
+			continue
+		}
+		relationship := &pb.Relationship{
+			Id:              rel["relationshipID"].(string),
+			Name:            rel["type"].(string),
+			RelatedEntityId: rel["relatedID"].(string),
+			StartTime:       rel["Created"].(string),
+		}
+		if terminated, ok := rel["Terminated"].(string); ok {
+			relationship.EndTime = terminated
+		}
+		relationships[relationship.Id] = relationship
+	}
+	return relationships, nil
+}
+
+// GetFilteredRelationships returns the relationships of an entity matching the given fields and active at activeAt
+func (s *GraphStore) GetFilteredRelationships(ctx context.Context, entityId string, relationshipId string, relationship string, relatedEntityId string, startTime string, endTime string, direction string, activeAt string) (map[string]*pb.Relationship, error) {
+	if entityId == "" {
+		return nil, fmt.Errorf("entityId cannot be empty")
+	}
+
+	filters := map[string]interface{}{}
+	for key, value := range map[string]string{
+		"id":              relationshipId,
+		"name":            relationship,
+		"relatedEntityId": relatedEntityId,
+		"startTime":       startTime,
+		"endTime":         endTime,
+		"direction":       direction,
+	} {
+		if value != "" {
+			filters[key] = value
+		}
+	}
+
+	relationshipData, err := s.ReadFilteredRelationships(ctx, entityId, filters, activeAt)
+	if err != nil {
+		return nil, err
+	}
+
+	relationships := make(map[string]*pb.Relationship)
+	for _, rel := range relationshipData {
+		relID := rel["id"].(string)
+		relationships[relID] = &pb.Relationship{
+			Id:              relID,
+			RelatedEntityId: rel["relatedEntityId"].(string),
+			StartTime:       rel["startTime"].(string),
+			EndTime:         rel["endTime"].(string),
+			Name:            rel["name"].(string),
+			Direction:       rel["direction"].(string),
+		}
+	}
+	return relationships, nil
+}
+
+// GetGraphPaths returns the shortest paths between two entities as ordered entity and relationship lists
+func (s *GraphStore) GetGraphPaths(ctx context.Context, req *pb.PathRequest) ([]*pb.EntityPath, error) {
+	if req == nil {
+		return nil, fmt.Errorf("path request cannot be nil")
+	}
+
+	pathData, err := s.ReadShortestPaths(ctx, req.SourceEntityId, req.TargetEntityId, req.RelationshipNames, req.ActiveAt, req.Direction, int(req.MaxDepth), req.AllShortestPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]*pb.EntityPath, 0, len(pathData))
+	for _, path := range pathData {
+		entityData := path["entities"].([]map[string]interface{})
+		relationshipData := path["relationships"].([]map[string]interface{})
+
+		entityPath := &pb.EntityPath{}
+		for _, entityMap := range entityData {
+			created := entityMap["Created"].(string)
+			terminated, _ := entityMap["Terminated"].(string)
+			value, _ := anypb.New(&wrapperspb.StringValue{Value: entityMap["Name"].(string)})
+			entityPath.Entities = append(entityPath.Entities, &pb.Entity{
+				Id: entityMap["Id"].(string),
+				Kind: &pb.Kind{
+					Major: entityMap["MajorKind"].(string),
+					Minor: entityMap["MinorKind"].(string),
+				},
+				Created:    created,
+				Terminated: terminated,
+				Name: &pb.TimeBasedValue{
+					StartTime: created,
+					EndTime:   terminated,
+					Value:     value,
+				},
+			})
+		}
+
+		for i, rel := range relationshipData {
+			endTime, _ := rel["endTime"].(string)
+			direction := "OUTGOING"
+			if rel["startEntityId"] != entityPath.Entities[i].Id {
+				direction = "INCOMING"
+			}
+			entityPath.Relationships = append(entityPath.Relationships, &pb.Relationship{
+				Id:              rel["id"].(string),
+				Name:            rel["name"].(string),
+				RelatedEntityId: entityPath.Entities[i+1].Id,
+				StartTime:       rel["startTime"].(string),
+				EndTime:         endTime,
+				Direction:       direction,
+			})
+		}
+
+		paths = append(paths, entityPath)
+	}
+	return paths, nil
+}
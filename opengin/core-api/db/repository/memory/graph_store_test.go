@@ -0,0 +1,247 @@
+package memoryrepository
+
+import (
+	"context"
+	"testing"
+
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/tenant"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func newTestEntity(id, major, minor, name, created string) *pb.Entity {
+	value, _ := anypb.New(&wrapperspb.StringValue{Value: name})
+	return &pb.Entity{
+		Id:      id,
+		Kind:    &pb.Kind{Major: major, Minor: minor},
+		Name:    &pb.TimeBasedValue{StartTime: created, Value: value},
+		Created: created,
+	}
+}
+
+// newTestGraph builds a -> b -> c with a second, terminated a -> c relationship
+func newTestGraph(t *testing.T) *GraphStore {
+	ctx := context.Background()
+	store := NewGraphStore()
+
+	for _, id := range []string{"a", "b", "c"} {
+		_, err := store.HandleGraphEntityCreation(ctx, newTestEntity(id, "Organisation", "Department", "Entity "+id, "2024-01-01T00:00:00Z"))
+		require.NoError(t, err)
+	}
+
+	a := &pb.Entity{Id: "a", Relationships: map[string]*pb.Relationship{
+		"a_b": {Id: "a_b", Name: "HAS", RelatedEntityId: "b", StartTime: "2024-01-01T00:00:00Z"},
+		"a_c": {Id: "a_c", Name: "HAS", RelatedEntityId: "c", StartTime: "2024-01-01T00:00:00Z", EndTime: "2024-06-01T00:00:00Z"},
+	}}
+	require.NoError(t, store.HandleGraphRelationshipsCreate(ctx, a))
+
+	b := &pb.Entity{Id: "b", Relationships: map[string]*pb.Relationship{
+		"b_c": {Id: "b_c", Name: "HAS", RelatedEntityId: "c", StartTime: "2024-03-01T00:00:00+00:00"},
+	}}
+	require.NoError(t, store.HandleGraphRelationshipsCreate(ctx, b))
+
+	return store
+}
+
+func TestGraphEntityLifecycle(t *testing.T) {
+	ctx := context.Background()
+	store := newTestGraph(t)
+
+	_, err := store.HandleGraphEntityCreation(ctx, newTestEntity("a", "Organisation", "Department", "Duplicate", "2024-01-01T00:00:00Z"))
+	assert.Error(t, err)
+
+	_, err = store.HandleGraphEntityCreation(ctx, newTestEntity("d", "Organisation", "", "No minor kind", "2024-01-01T00:00:00Z"))
+	assert.Error(t, err)
+
+	_, err = store.HandleGraphEntityUpdate(ctx, &pb.Entity{Id: "a", Terminated: "2025-01-01T00:00:00Z"})
+	require.NoError(t, err)
+
+	kind, name, created, terminated, err := store.GetGraphEntity(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "Organisation", kind.Major)
+	assert.Equal(t, "Department", kind.Minor)
+	assert.Equal(t, "2024-01-01T00:00:00Z", created)
+	assert.Equal(t, "2025-01-01T00:00:00Z", terminated)
+	assert.Equal(t, terminated, name.EndTime)
+
+	_, err = store.HandleGraphEntityUpdate(ctx, &pb.Entity{Id: "a", Kind: &pb.Kind{Major: "Person"}})
+	assert.Error(t, err)
+
+	_, _, _, _, err = store.GetGraphEntity(ctx, "missing")
+	assert.Error(t, err)
+}
+
+func TestFilterEntities(t *testing.T) {
+	ctx := context.Background()
+	store := newTestGraph(t)
+
+	entities, err := store.HandleGraphEntityFilter(ctx, &pb.ReadEntityRequest{Entity: &pb.Entity{Kind: &pb.Kind{Major: "Organisation"}}})
+	require.NoError(t, err)
+	assert.Len(t, entities, 3)
+
+	entities, err = store.HandleGraphEntityFilter(ctx, &pb.ReadEntityRequest{Entity: &pb.Entity{
+		Kind: &pb.Kind{Major: "Organisation"},
+		Name: newTestEntity("", "", "", "Entity b", "").Name,
+	}})
+	require.NoError(t, err)
+	require.Len(t, entities, 1)
+	assert.Equal(t, "b", entities[0]["id"])
+	assert.Nil(t, entities[0]["terminated"])
+
+	// Created is compared as an instant, not as text
+	entities, err = store.HandleGraphEntityFilter(ctx, &pb.ReadEntityRequest{Entity: &pb.Entity{
+		Kind:    &pb.Kind{Major: "Organisation"},
+		Created: "2024-01-01T05:30:00+05:30",
+	}})
+	require.NoError(t, err)
+	assert.Len(t, entities, 3)
+
+	_, err = store.HandleGraphEntityFilter(ctx, &pb.ReadEntityRequest{Entity: &pb.Entity{}})
+	assert.Error(t, err)
+}
+
+func TestRelationshipUpdates(t *testing.T) {
+	ctx := context.Background()
+	store := newTestGraph(t)
+
+	err := store.HandleGraphRelationshipsCreate(ctx, &pb.Entity{Id: "a", Relationships: map[string]*pb.Relationship{
+		"a_b": {Id: "a_b", Name: "HAS", RelatedEntityId: "b", StartTime: "2024-01-01T00:00:00Z"},
+	}})
+	assert.Error(t, err, "duplicate relationship ids are rejected")
+
+	err = store.HandleGraphRelationshipsUpdate(ctx, &pb.Entity{Id: "a", Relationships: map[string]*pb.Relationship{
+		"a_b": {Id: "a_b", Name: "OWNS"},
+	}})
+	assert.ErrorContains(t, err, "cannot update immutable fields")
+
+	err = store.HandleGraphRelationshipsUpdate(ctx, &pb.Entity{Id: "a", Relationships: map[string]*pb.Relationship{
+		"a_b": {Id: "a_b", EndTime: "2024-12-31T00:00:00Z"},
+	}})
+	require.NoError(t, err)
+
+	relationships, err := store.GetGraphRelationships(ctx, "a")
+	require.NoError(t, err)
+	assert.Len(t, relationships, 2)
+	assert.Equal(t, "2024-12-31T00:00:00Z", relationships["a_b"].EndTime)
+
+	// Relationships that do not exist yet are created by an update
+	err = store.HandleGraphRelationshipsUpdate(ctx, &pb.Entity{Id: "c", Relationships: map[string]*pb.Relationship{
+		"c_a": {Id: "c_a", Name: "REPORTS_TO", RelatedEntityId: "a", StartTime: "2024-01-01T00:00:00Z"},
+	}})
+	require.NoError(t, err)
+
+	relationships, err = store.GetGraphRelationships(ctx, "c")
+	require.NoError(t, err)
+	assert.Contains(t, relationships, "c_a")
+}
+
+func TestFilteredRelationshipsActiveAt(t *testing.T) {
+	ctx := context.Background()
+	store := newTestGraph(t)
+
+	tests := []struct {
+		name      string
+		direction string
+		activeAt  string
+		expected  []string
+	}{
+		{"all outgoing", "OUTGOING", "", []string{"a_b", "a_c"}},
+		{"before a_c ends", "OUTGOING", "2024-05-31T23:59:59Z", []string{"a_b", "a_c"}},
+		{"at the end of a_c", "OUTGOING", "2024-06-01T00:00:00Z", []string{"a_b"}},
+		{"before anything starts", "OUTGOING", "2023-12-31T00:00:00Z", nil},
+		{"incoming", "INCOMING", "", nil},
+		{"both directions", "", "", []string{"a_b", "a_c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			relationships, err := store.GetFilteredRelationships(ctx, "a", "", "", "", "", "", tt.direction, tt.activeAt)
+			require.NoError(t, err)
+
+			var ids []string
+			for id := range relationships {
+				ids = append(ids, id)
+			}
+			assert.ElementsMatch(t, tt.expected, ids)
+		})
+	}
+
+	relationships, err := store.GetFilteredRelationships(ctx, "c", "", "HAS", "b", "2024-03-01T00:00:00Z", "", "INCOMING", "2024-04-01T00:00:00Z")
+	require.NoError(t, err)
+	require.Contains(t, relationships, "b_c")
+	assert.Equal(t, "INCOMING", relationships["b_c"].Direction)
+	assert.Equal(t, "2024-03-01T00:00:00Z", relationships["b_c"].StartTime)
+}
+
+func TestGraphPaths(t *testing.T) {
+	ctx := context.Background()
+	store := newTestGraph(t)
+
+	paths, err := store.GetGraphPaths(ctx, &pb.PathRequest{SourceEntityId: "a", TargetEntityId: "c"})
+	require.NoError(t, err)
+	require.Len(t, paths, 1)
+	assert.Len(t, paths[0].Relationships, 1)
+	assert.Equal(t, "a_c", paths[0].Relationships[0].Id)
+
+	// Once a_c has ended the only route runs through b
+	paths, err = store.GetGraphPaths(ctx, &pb.PathRequest{SourceEntityId: "a", TargetEntityId: "c", ActiveAt: "2024-07-01T00:00:00Z"})
+	require.NoError(t, err)
+	require.Len(t, paths, 1)
+	require.Len(t, paths[0].Entities, 3)
+	assert.Equal(t, "b", paths[0].Entities[1].Id)
+	assert.Equal(t, "c", paths[0].Relationships[1].RelatedEntityId)
+
+	paths, err = store.GetGraphPaths(ctx, &pb.PathRequest{SourceEntityId: "c", TargetEntityId: "a", Direction: "INCOMING", ActiveAt: "2024-07-01T00:00:00Z"})
+	require.NoError(t, err)
+	require.Len(t, paths, 1)
+	assert.Equal(t, "INCOMING", paths[0].Relationships[0].Direction)
+
+	paths, err = store.GetGraphPaths(ctx, &pb.PathRequest{SourceEntityId: "c", TargetEntityId: "a", Direction: "OUTGOING"})
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+
+	paths, err = store.GetGraphPaths(ctx, &pb.PathRequest{SourceEntityId: "a", TargetEntityId: "c", MaxDepth: 2, AllShortestPaths: true, ActiveAt: "2024-04-01T00:00:00Z"})
+	require.NoError(t, err)
+	assert.Len(t, paths, 1, "only the direct hop is a shortest path")
+
+	_, err = store.GetGraphPaths(ctx, &pb.PathRequest{SourceEntityId: "a", TargetEntityId: "a"})
+	assert.Error(t, err)
+
+	_, err = store.GetGraphPaths(ctx, &pb.PathRequest{SourceEntityId: "a", TargetEntityId: "c", MaxDepth: MaxPathMaxDepth + 1})
+	assert.Error(t, err)
+}
+
+func TestGraphStoreTenants(t *testing.T) {
+	store := newTestGraph(t)
+	acme := tenant.WithTenant(context.Background(), "acme")
+
+	// The graph of the default tenant is invisible to acme, and its ids are free there
+	_, err := store.ReadGraphEntity(acme, "a")
+	assert.Error(t, err)
+	relationships, err := store.ReadRelationships(acme, "a")
+	require.NoError(t, err)
+	assert.Empty(t, relationships)
+
+	_, err = store.HandleGraphEntityCreation(acme, newTestEntity("a", "Organisation", "Department", "Acme a", "2024-01-01T00:00:00Z"))
+	require.NoError(t, err)
+	_, err = store.HandleGraphEntityCreation(acme, newTestEntity("dataset", "Dataset", "tabular", "Dataset", "2024-01-01T00:00:00Z"))
+	require.NoError(t, err)
+
+	entity, err := store.ReadGraphEntity(acme, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "Acme a", entity["Name"])
+	entity, err = store.ReadGraphEntity(context.Background(), "a")
+	require.NoError(t, err)
+	assert.Equal(t, "Entity a", entity["Name"])
+
+	count, err := store.CountEntities(acme)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	count, err = store.CountEntities(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+}
@@ -0,0 +1,127 @@
+package memoryrepository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"lk/datafoundation/core-api/db/repository"
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// MetadataStore keeps metadata documents in memory with the semantics of the MongoDB repository.
+// Like the MongoDB documents, only the id and the metadata of an entity are kept.
+type MetadataStore struct {
+	mu        sync.RWMutex
+	documents map[string]map[string]*anypb.Any
+	kinds     map[string]*pb.KindDefinition
+}
+
+// NewMetadataStore creates an empty in-memory metadata store
+func NewMetadataStore() *MetadataStore {
+	return &MetadataStore{
+		documents: make(map[string]map[string]*anypb.Any),
+		kinds:     make(map[string]*pb.KindDefinition),
+	}
+}
+
+// HandleMetadata creates the document of an entity or replaces its metadata; empty metadata is ignored
+func (s *MetadataStore) HandleMetadata(ctx context.Context, entityId string, entity *pb.Entity) error {
+	if entity == nil || len(entity.GetMetadata()) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.documents[tenantKey(ctx, entityId)] = cloneMetadata(entity.GetMetadata())
+	return nil
+}
+
+// GetMetadata returns the metadata of an entity, empty when it has none
+func (s *MetadataStore) GetMetadata(ctx context.Context, entityId string) (map[string]*anypb.Any, error) {
+	entity, err := s.ReadEntity(ctx, entityId)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error retrieving metadata", "entity_id", entityId, "error", err)
+		return make(map[string]*anypb.Any), nil
+	}
+	if entity.Metadata == nil {
+		return make(map[string]*anypb.Any), nil
+	}
+	return entity.Metadata, nil
+}
+
+// ReadEntity returns the document of an entity, or an error wrapping repository.ErrNotFound when there is none
+func (s *MetadataStore) ReadEntity(ctx context.Context, id string) (*pb.Entity, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	metadata, ok := s.documents[tenantKey(ctx, id)]
+	if !ok {
+		return nil, fmt.Errorf("document with id %s %w", id, repository.ErrNotFound)
+	}
+	return &pb.Entity{
+		Id:       id,
+		Metadata: cloneMetadata(metadata),
+	}, nil
+}
+
+// DeleteMetadata removes the document of an entity; removing a missing document is not an error
+func (s *MetadataStore) DeleteMetadata(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.documents, tenantKey(ctx, id))
+	return nil
+}
+
+// ListKinds returns the kind definitions of the call's tenant
+func (s *MetadataStore) ListKinds(ctx context.Context) ([]*pb.KindDefinition, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	prefix := tenantKey(ctx, "")
+	var kinds []*pb.KindDefinition
+	for key, def := range s.kinds {
+		if strings.HasPrefix(key, prefix) {
+			kinds = append(kinds, proto.Clone(def).(*pb.KindDefinition))
+		}
+	}
+	return kinds, nil
+}
+
+// PutKind creates the definition of a major kind or replaces it
+func (s *MetadataStore) PutKind(ctx context.Context, def *pb.KindDefinition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.kinds[tenantKey(ctx, def.Major)] = proto.Clone(def).(*pb.KindDefinition)
+	return nil
+}
+
+// DeleteKind removes the definition of a major kind; removing a missing one is not an error
+func (s *MetadataStore) DeleteKind(ctx context.Context, major string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.kinds, tenantKey(ctx, major))
+	return nil
+}
+
+// cloneMetadata copies a metadata map so callers cannot change the stored values
+func cloneMetadata(metadata map[string]*anypb.Any) map[string]*anypb.Any {
+	cloned := make(map[string]*anypb.Any, len(metadata))
+	for key, value := range metadata {
+		if value == nil {
+			cloned[key] = nil
+			continue
+		}
+		cloned[key] = proto.Clone(value).(*anypb.Any)
+	}
+	return cloned
+}
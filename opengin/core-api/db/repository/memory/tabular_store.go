@@ -0,0 +1,446 @@
+package memoryrepository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	commons "lk/datafoundation/core-api/commons"
+	"lk/datafoundation/core-api/db/repository"
+	"lk/datafoundation/core-api/db/repository/postgres"
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/schema"
+	"lk/datafoundation/core-api/pkg/typeinference"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type tableColumn struct {
+	name      string // The table column
+	field     string // The column name the producer gave, empty for bookkeeping columns
+	dataType  typeinference.DataType
+	precision int // Of decimal columns, 0 when unconstrained
+	scale     int // Of decimal columns
+}
+
+func (c tableColumn) typeInfo() typeinference.TypeInfo {
+	return typeinference.TypeInfo{Type: c.dataType, Precision: c.precision, Scale: c.scale}
+}
+
+type attributeTable struct {
+	schema  *schema.SchemaInfo
+	columns []tableColumn
+	rows    [][]interface{}
+	nextID  int64
+}
+
+// TabularStore keeps tabular attributes in memory with the semantics of the PostgreSQL repository.
+// Every attribute gets its own table with the same id, entity_attribute_id and created_at columns,
+// values are converted to the column types, and reads return the same JSON payload.
+type TabularStore struct {
+	mu         sync.RWMutex
+	tables     map[string]*attributeTable
+	attributes map[string]int
+}
+
+// NewTabularStore creates an empty in-memory tabular store
+func NewTabularStore() *TabularStore {
+	return &TabularStore{
+		tables:     make(map[string]*attributeTable),
+		attributes: make(map[string]int),
+	}
+}
+
+// InitializeTables is a no-op; the bookkeeping lives in the store itself
+func (s *TabularStore) InitializeTables(ctx context.Context) error {
+	return nil
+}
+
+// HandleTabularData stores the rows of a tabular attribute, creating its table on first use.
+// Later writes must be compatible with the schema the table was created with.
+func (s *TabularStore) HandleTabularData(ctx context.Context, entityID, attrName string, value *pb.TimeBasedValue, schemaInfo *schema.SchemaInfo) error {
+	tableName := repository.AttributeTableName(entityID, attrName)
+
+	var tabularStruct structpb.Struct
+	if err := value.Value.UnmarshalTo(&tabularStruct); err != nil {
+		return fmt.Errorf("error unmarshaling tabular data: %v", err)
+	}
+	columnsValue := tabularStruct.Fields["columns"].GetListValue()
+	rowsValue := tabularStruct.Fields["rows"].GetListValue()
+	if columnsValue == nil || rowsValue == nil {
+		return fmt.Errorf("invalid tabular data format")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	table, exists := s.tables[tenantKey(ctx, tableName)]
+	if exists {
+		compatible, err := postgres.CompareSchemas(table.schema, schemaInfo)
+		if err != nil {
+			return fmt.Errorf("schema compatibility check failed: %v", err)
+		}
+		if !compatible {
+			return fmt.Errorf("incompatible schema changes detected")
+		}
+		if err := postgres.ValidateDataAgainstSchema(&tabularStruct, table.schema); err != nil {
+			return fmt.Errorf("data validation failed: %v", err)
+		}
+	} else {
+		table = newAttributeTable(columnsValue, schemaInfo)
+	}
+
+	attributeKey := tenantKey(ctx, entityID+"\x00"+attrName)
+	attributeID, ok := s.attributes[attributeKey]
+	if !ok {
+		attributeID = len(s.attributes) + 1
+	}
+
+	// Build every row before touching the table so a bad value leaves it unchanged
+	columnNames := make([]string, len(columnsValue.Values))
+	for i, col := range columnsValue.Values {
+		columnNames[i] = repository.TableColumn(table.schema, col.GetStringValue())
+	}
+	nextID := table.nextID
+	rows := make([][]interface{}, 0, len(rowsValue.Values))
+	for i, rowValue := range rowsValue.Values {
+		rowList := rowValue.GetListValue()
+		if rowList == nil {
+			return fmt.Errorf("invalid row format at index %d", i)
+		}
+
+		row := make([]interface{}, len(table.columns))
+		row[table.columnIndex("entity_attribute_id")] = int64(attributeID)
+		row[table.columnIndex("created_at")] = time.Now().UTC()
+		for j, cell := range rowList.Values {
+			if j >= len(columnNames) {
+				return fmt.Errorf("error inserting tabular data: row %d has more values than columns", i)
+			}
+			index := table.columnIndex(columnNames[j])
+			if index < 0 {
+				return fmt.Errorf("error inserting tabular data: column %s does not exist", columnsValue.Values[j].GetStringValue())
+			}
+			converted, err := convertCell(cellValue(cell), table.columns[index])
+			if err != nil {
+				return fmt.Errorf("error inserting tabular data: column %s: %v", columnNames[j], err)
+			}
+			row[index] = converted
+		}
+
+		idIndex := table.columnIndex("id")
+		if row[idIndex] == nil {
+			nextID++
+			row[idIndex] = nextID
+		}
+		rows = append(rows, row)
+	}
+
+	s.tables[tenantKey(ctx, tableName)] = table
+	s.attributes[attributeKey] = attributeID
+	table.nextID = nextID
+	table.rows = append(table.rows, rows...)
+	return nil
+}
+
+// AttributeTable returns the table the rows of a stored tabular attribute are kept in
+func (s *TabularStore) AttributeTable(ctx context.Context, entityID, attrName string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.attributes[tenantKey(ctx, entityID+"\x00"+attrName)]; !ok {
+		return "", fmt.Errorf("tabular attribute %s of entity %s does not exist", attrName, entityID)
+	}
+	return repository.AttributeTableName(entityID, attrName), nil
+}
+
+// GetData returns the rows of an attribute table matching the filters as JSON-formatted tabular data
+func (s *TabularStore) GetData(ctx context.Context, tableName string, filters map[string]interface{}, fields ...string) (*anypb.Any, error) {
+	return s.GetMaskedData(ctx, tableName, filters, repository.ColumnMask{}, fields...)
+}
+
+// GetMaskedData returns the rows like GetData, leaving out or blanking the masked columns.
+// Data columns are named and ordered as the producer gave them.
+func (s *TabularStore) GetMaskedData(ctx context.Context, tableName string, filters map[string]interface{}, mask repository.ColumnMask, fields ...string) (*anypb.Any, error) {
+	columns, _, rows, err := s.read(ctx, tableName, repository.TabularQuery{Filters: filters, Fields: fields, Mask: mask})
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"columns": columns,
+		"rows":    rows,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling tabular data to JSON: %v", err)
+	}
+
+	structValue, err := structpb.NewStruct(map[string]interface{}{
+		"data": string(jsonData),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating struct for JSON data: %v", err)
+	}
+
+	anyValue, err := anypb.New(structValue)
+	if err != nil {
+		return nil, fmt.Errorf("error converting struct to Any: %v", err)
+	}
+	return anyValue, nil
+}
+
+// GetTypedData returns the rows a query selects like GetMaskedData, along with the types of their columns
+func (s *TabularStore) GetTypedData(ctx context.Context, tableName string, query repository.TabularQuery) (*pb.TabularData, error) {
+	columns, types, rows, err := s.read(ctx, tableName, query)
+	if err != nil {
+		return nil, err
+	}
+	data, err := repository.NewTabularData(columns, types, rows)
+	if err != nil {
+		return nil, fmt.Errorf("error converting data from %s: %v", tableName, err)
+	}
+	return data, nil
+}
+
+// CountData returns the number of rows the filters and written window of a query select
+func (s *TabularStore) CountData(ctx context.Context, tableName string, query repository.TabularQuery) (int64, error) {
+	query.Limit, query.Offset = 0, 0
+	_, _, rows, err := s.read(ctx, tableName, query)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(rows)), nil
+}
+
+// read selects the matching rows of a table and the names and types of the returned columns
+func (s *TabularStore) read(ctx context.Context, tableName string, query repository.TabularQuery) ([]string, []typeinference.TypeInfo, [][]interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	table, ok := s.tables[tenantKey(ctx, commons.SanitizeIdentifier(tableName))]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("error querying data from %s: table does not exist", tableName)
+	}
+
+	omit, redact := query.Mask.Columns()
+
+	var selected []int
+	columns := []string{}
+	types := []typeinference.TypeInfo{}
+	if len(query.Fields) > 0 {
+		for _, field := range query.Fields {
+			index := table.fieldIndex(field)
+			if index < 0 {
+				return nil, nil, nil, fmt.Errorf("error querying data from %s: column %s does not exist", tableName, field)
+			}
+			if !omit[commons.SanitizeIdentifier(field)] {
+				selected = append(selected, index)
+				columns = append(columns, field)
+				types = append(types, table.columns[index].typeInfo())
+			}
+		}
+	} else {
+		for i, col := range table.columns {
+			if col.field != "" && !omit[commons.SanitizeIdentifier(col.field)] {
+				selected = append(selected, i)
+				columns = append(columns, col.field)
+				types = append(types, col.typeInfo())
+			}
+		}
+	}
+
+	type condition struct {
+		index int
+		value interface{}
+	}
+	var conditions []condition
+	for key, value := range query.Filters {
+		index := table.fieldIndex(key)
+		if index < 0 {
+			return nil, nil, nil, fmt.Errorf("error querying data from %s: column %s does not exist", tableName, key)
+		}
+		if masked := commons.SanitizeIdentifier(key); omit[masked] || redact[masked] {
+			return nil, nil, nil, fmt.Errorf("error querying data from %s: column %s is restricted and cannot be filtered on", tableName, key)
+		}
+		converted, err := convertCell(value, table.columns[index])
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("error querying data from %s: column %s: %v", tableName, key, err)
+		}
+		conditions = append(conditions, condition{index: index, value: converted})
+	}
+
+	written := table.columnIndex("created_at")
+	var rows [][]interface{}
+	skip := query.Offset
+	for _, row := range table.rows {
+		if query.Limit > 0 && len(rows) == query.Limit {
+			break
+		}
+		createdAt := row[written].(time.Time)
+		if (!query.WrittenFrom.IsZero() && createdAt.Before(query.WrittenFrom)) || (!query.WrittenUntil.IsZero() && !createdAt.Before(query.WrittenUntil)) {
+			continue
+		}
+		matches := true
+		for _, cond := range conditions {
+			if !cellEqual(row[cond.index], cond.value) {
+				matches = false
+				break
+			}
+		}
+		if !matches {
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+
+		projected := make([]interface{}, len(selected))
+		for i, index := range selected {
+			if !redact[commons.SanitizeIdentifier(columns[i])] {
+				projected[i] = row[index]
+			}
+		}
+		rows = append(rows, projected)
+	}
+	return columns, types, rows, nil
+}
+
+// newAttributeTable lays out a table like the PostgreSQL repository does: id and entity_attribute_id first,
+// then the columns of the data in the order they were given under the names repository.AssignTableColumns
+// gives them, and created_at last
+func newAttributeTable(columnsValue *structpb.ListValue, schemaInfo *schema.SchemaInfo) *attributeTable {
+	names := make([]string, len(columnsValue.Values))
+	for i, col := range columnsValue.Values {
+		names[i] = col.GetStringValue()
+	}
+	stored := *schemaInfo
+	stored.Columns = repository.AssignTableColumns(names)
+
+	table := &attributeTable{
+		schema: &stored,
+		columns: []tableColumn{
+			{name: "id", dataType: typeinference.IntType},
+			{name: "entity_attribute_id", dataType: typeinference.IntType},
+		},
+	}
+
+	for _, column := range stored.Columns {
+		col := tableColumn{name: column.Column, field: column.Name, dataType: typeinference.StringType}
+		if field, ok := schemaInfo.Fields[column.Name]; ok && field.TypeInfo != nil {
+			col.dataType, col.precision, col.scale = field.TypeInfo.Type, field.TypeInfo.Precision, field.TypeInfo.Scale
+		}
+		table.columns = append(table.columns, col)
+	}
+
+	table.columns = append(table.columns, tableColumn{name: "created_at", dataType: typeinference.DateTimeType})
+	return table
+}
+
+// fieldIndex finds the column a reader means by a field or filter name, see repository.ResolveColumn
+func (t *attributeTable) fieldIndex(name string) int {
+	column, ok := repository.ResolveColumn(t.schema.Columns, name)
+	if !ok {
+		return -1
+	}
+	return t.columnIndex(column)
+}
+
+func (t *attributeTable) columnIndex(name string) int {
+	for i, col := range t.columns {
+		if col.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// cellValue unpacks a cell the way the PostgreSQL repository does before inserting it
+func cellValue(cell *structpb.Value) interface{} {
+	switch cell.Kind.(type) {
+	case *structpb.Value_NullValue:
+		return nil
+	case *structpb.Value_NumberValue:
+		return cell.GetNumberValue()
+	case *structpb.Value_BoolValue:
+		return cell.GetBoolValue()
+	default:
+		return cell.GetStringValue()
+	}
+}
+
+// convertCell converts a value to the Go type PostgreSQL returns for the column
+func convertCell(value interface{}, column tableColumn) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	text := fmt.Sprintf("%v", value)
+	if f, ok := value.(float64); ok {
+		text = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	switch column.dataType {
+	case typeinference.IntType, typeinference.BigIntType:
+		switch v := value.(type) {
+		case float64:
+			return int64(math.Round(v)), nil
+		case int:
+			return int64(v), nil
+		case int64:
+			return v, nil
+		}
+		i, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid input syntax for type integer: %q", text)
+		}
+		return i, nil
+	case typeinference.FloatType:
+		f, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid input syntax for type double precision: %q", text)
+		}
+		return f, nil
+	case typeinference.DecimalType:
+		// NUMERIC columns return their text, with as many digits after the point as the column's scale
+		decimal, err := typeinference.ParseDecimal(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid input syntax for type numeric: %q", text)
+		}
+		if column.precision == 0 {
+			return decimal, nil
+		}
+		r, _ := new(big.Rat).SetString(decimal)
+		return r.FloatString(column.scale), nil
+	case typeinference.BoolType:
+		b, err := strconv.ParseBool(strings.TrimSpace(text))
+		if err != nil {
+			return nil, fmt.Errorf("invalid input syntax for type boolean: %q", text)
+		}
+		return b, nil
+	case typeinference.DateType, typeinference.DateTimeType:
+		if t, ok := value.(time.Time); ok {
+			return t.UTC(), nil
+		}
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02", "2006-01-02 15:04:05", "2006/01/02", "02/01/2006"} {
+			if t, err := time.Parse(layout, text); err == nil {
+				return t.UTC(), nil
+			}
+		}
+		return nil, fmt.Errorf("invalid input syntax for type timestamp: %q", text)
+	default:
+		return text, nil
+	}
+}
+
+func cellEqual(a, b interface{}) bool {
+	if ta, ok := a.(time.Time); ok {
+		tb, ok := b.(time.Time)
+		return ok && ta.Equal(tb)
+	}
+	return a == b
+}
@@ -0,0 +1,401 @@
+package memoryrepository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"lk/datafoundation/core-api/db/repository"
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/schema"
+	"lk/datafoundation/core-api/pkg/typeinference"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func newTabularValue(t *testing.T, columns []interface{}, rows []interface{}) *pb.TimeBasedValue {
+	data, err := structpb.NewStruct(map[string]interface{}{
+		"columns": columns,
+		"rows":    rows,
+	})
+	require.NoError(t, err)
+	value, err := anypb.New(data)
+	require.NoError(t, err)
+	return &pb.TimeBasedValue{StartTime: "2024-01-01T00:00:00Z", Value: value}
+}
+
+func storeTabular(t *testing.T, store *TabularStore, value *pb.TimeBasedValue) error {
+	schemaInfo, err := schema.GenerateSchema(value.Value)
+	require.NoError(t, err)
+	return store.HandleTabularData(context.Background(), "entity-1", "budget", value, schemaInfo)
+}
+
+func readTabular(t *testing.T, store *TabularStore, filters map[string]interface{}, fields ...string) ([]string, [][]interface{}) {
+	tableName, err := store.AttributeTable(context.Background(), "entity-1", "budget")
+	require.NoError(t, err)
+	anyValue, err := store.GetData(context.Background(), tableName, filters, fields...)
+	require.NoError(t, err)
+
+	var data structpb.Struct
+	require.NoError(t, anyValue.UnmarshalTo(&data))
+
+	var result struct {
+		Columns []string        `json:"columns"`
+		Rows    [][]interface{} `json:"rows"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(data.Fields["data"].GetStringValue()), &result))
+	return result.Columns, result.Rows
+}
+
+func TestTabularStoreFilters(t *testing.T) {
+	store := NewTabularStore()
+
+	value := newTabularValue(t,
+		[]interface{}{"department", "amount", "approved", "date"},
+		[]interface{}{
+			[]interface{}{"Health", 1200, true, "2024-01-15T00:00:00Z"},
+			[]interface{}{"Education", 800.5, false, "2024-02-15T00:00:00Z"},
+			[]interface{}{"Health", 300, false, "2024-03-15T00:00:00Z"},
+		})
+	require.NoError(t, storeTabular(t, store, value))
+
+	columns, rows := readTabular(t, store, nil)
+	assert.Equal(t, []string{"department", "amount", "approved", "date"}, columns)
+	require.Len(t, rows, 3)
+	assert.Equal(t, "Health", rows[0][0])
+	assert.Equal(t, "2024-01-15T00:00:00Z", rows[0][3])
+
+	_, rows = readTabular(t, store, map[string]interface{}{"department": "Health"})
+	assert.Len(t, rows, 2)
+
+	_, rows = readTabular(t, store, map[string]interface{}{"department": "Health", "approved": "false"})
+	require.Len(t, rows, 1)
+	assert.Equal(t, float64(300), rows[0][1])
+
+	_, rows = readTabular(t, store, map[string]interface{}{"date": "2024-02-15"})
+	require.Len(t, rows, 1)
+	assert.Equal(t, "Education", rows[0][0])
+
+	columns, rows = readTabular(t, store, map[string]interface{}{"department": "Education"}, "id", "approved")
+	assert.Equal(t, []string{"id", "approved"}, columns)
+	assert.Equal(t, [][]interface{}{{float64(2), false}}, rows)
+
+	columns, rows = readTabular(t, store, map[string]interface{}{"department": "Nowhere"}, "department", "entity_attribute_id")
+	assert.Equal(t, []string{"department", "entity_attribute_id"}, columns)
+	assert.Empty(t, rows)
+
+	_, err := store.GetData(context.Background(), repository.AttributeTableName("entity-1", "budget"), map[string]interface{}{"missing": "x"})
+	assert.Error(t, err)
+
+	_, err = store.GetData(context.Background(), repository.AttributeTableName("entity-1", "other"), nil)
+	assert.Error(t, err)
+}
+
+func TestTabularStoreMaskedData(t *testing.T) {
+	store := NewTabularStore()
+
+	value := newTabularValue(t,
+		[]interface{}{"name", "salary", "nic"},
+		[]interface{}{[]interface{}{"a", 1000, "123V"}, []interface{}{"b", 2000, "456V"}})
+	require.NoError(t, storeTabular(t, store, value))
+
+	mask := repository.ColumnMask{Omit: []string{"nic"}, Redact: []string{"salary"}}
+	anyValue, err := store.GetMaskedData(context.Background(), repository.AttributeTableName("entity-1", "budget"), nil, mask)
+	require.NoError(t, err)
+
+	var data structpb.Struct
+	require.NoError(t, anyValue.UnmarshalTo(&data))
+	var result struct {
+		Columns []string        `json:"columns"`
+		Rows    [][]interface{} `json:"rows"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(data.Fields["data"].GetStringValue()), &result))
+	assert.NotContains(t, result.Columns, "nic")
+	require.Contains(t, result.Columns, "salary")
+	salary := -1
+	for i, column := range result.Columns {
+		if column == "salary" {
+			salary = i
+		}
+	}
+	for _, row := range result.Rows {
+		assert.Len(t, row, len(result.Columns))
+		assert.Nil(t, row[salary])
+	}
+
+	// Filtering on a masked column would reveal its values
+	_, err = store.GetMaskedData(context.Background(), repository.AttributeTableName("entity-1", "budget"), map[string]interface{}{"salary": 1000}, mask)
+	assert.Error(t, err)
+	_, err = store.GetMaskedData(context.Background(), repository.AttributeTableName("entity-1", "budget"), map[string]interface{}{"nic": "123V"}, mask)
+	assert.Error(t, err)
+}
+
+func TestTabularStoreAppend(t *testing.T) {
+	store := NewTabularStore()
+
+	first := newTabularValue(t,
+		[]interface{}{"name", "count"},
+		[]interface{}{[]interface{}{"a", 1}})
+	require.NoError(t, storeTabular(t, store, first))
+
+	second := newTabularValue(t,
+		[]interface{}{"name", "count"},
+		[]interface{}{[]interface{}{"b", 2}, []interface{}{"c", 3}})
+	require.NoError(t, storeTabular(t, store, second))
+
+	_, rows := readTabular(t, store, nil)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []interface{}{"c", float64(3)}, rows[2])
+
+	// Values must still match the types the table was created with
+	incompatible := newTabularValue(t,
+		[]interface{}{"name", "count"},
+		[]interface{}{[]interface{}{"d", "four"}})
+	assert.Error(t, storeTabular(t, store, incompatible))
+
+	_, rows = readTabular(t, store, nil)
+	assert.Len(t, rows, 3)
+}
+
+func TestTabularStoreNulls(t *testing.T) {
+	store := NewTabularStore()
+
+	value := newTabularValue(t,
+		[]interface{}{"name", "amount", "opened"},
+		[]interface{}{
+			[]interface{}{"a", 1.5, "2024-01-15"},
+			[]interface{}{nil, nil, nil},
+		})
+	require.NoError(t, storeTabular(t, store, value))
+
+	_, rows := readTabular(t, store, nil)
+	require.Len(t, rows, 2)
+	assert.Equal(t, []interface{}{nil, nil, nil}, rows[1])
+}
+
+func TestTabularStoreTableNames(t *testing.T) {
+	store := NewTabularStore()
+	ctx := context.Background()
+
+	// Both ids sanitize to a_1_x, yet each keeps its own table
+	for i, entityID := range []string{"A-1/x", "a_1_x"} {
+		value := newTabularValue(t, []interface{}{"year"}, []interface{}{[]interface{}{2020 + i}})
+		schemaInfo, err := schema.GenerateSchema(value.Value)
+		require.NoError(t, err)
+		require.NoError(t, store.HandleTabularData(ctx, entityID, "budget", value, schemaInfo))
+	}
+
+	first, err := store.AttributeTable(ctx, "A-1/x", "budget")
+	require.NoError(t, err)
+	second, err := store.AttributeTable(ctx, "a_1_x", "budget")
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+
+	anyValue, err := store.GetData(ctx, first, nil)
+	require.NoError(t, err)
+	var data structpb.Struct
+	require.NoError(t, anyValue.UnmarshalTo(&data))
+	assert.JSONEq(t, `{"columns": ["year"], "rows": [[2020]]}`, data.Fields["data"].GetStringValue())
+
+	_, err = store.AttributeTable(ctx, "A-1/x", "missing")
+	assert.Error(t, err)
+}
+
+func TestTabularStoreColumnNames(t *testing.T) {
+	store := NewTabularStore()
+
+	// Names that sanitize alike, a producer id column and a bookkeeping name must all come back as sent
+	columns := []interface{}{"Zone", "id", "Amount (LKR)", "amount_lkr_", "created_at", "A"}
+	value := newTabularValue(t, columns, []interface{}{
+		[]interface{}{"West", "W-1", 10, 11, "2024-01-01T00:00:00Z", "x"},
+		[]interface{}{"East", "E-1", 20, 21, "2024-02-01T00:00:00Z", "y"},
+	})
+	require.NoError(t, storeTabular(t, store, value))
+
+	names, rows := readTabular(t, store, nil)
+	assert.Equal(t, []string{"Zone", "id", "Amount (LKR)", "amount_lkr_", "created_at", "A"}, names)
+	assert.Equal(t, []interface{}{"West", "W-1", float64(10), float64(11), "2024-01-01T00:00:00Z", "x"}, rows[0])
+
+	// Later writes may list the columns in another order
+	reordered := newTabularValue(t,
+		[]interface{}{"A", "created_at", "amount_lkr_", "Amount (LKR)", "id", "Zone"},
+		[]interface{}{[]interface{}{"z", "2024-03-01T00:00:00Z", 31, 30, "N-1", "North"}})
+	require.NoError(t, storeTabular(t, store, reordered))
+
+	names, rows = readTabular(t, store, map[string]interface{}{"id": "N-1"}, "Amount (LKR)", "Zone")
+	assert.Equal(t, []string{"Amount (LKR)", "Zone"}, names)
+	assert.Equal(t, [][]interface{}{{float64(30), "North"}}, rows)
+}
+
+func TestTabularStoreTypedData(t *testing.T) {
+	store := NewTabularStore()
+
+	value := newTabularValue(t,
+		[]interface{}{"department", "year", "approved", "date", "salary"},
+		[]interface{}{
+			[]interface{}{"Health", 2024, true, "2024-01-15T00:00:00Z", 1000},
+			[]interface{}{"Education", 2025, false, "2024-02-15T00:00:00Z", 2000},
+		})
+	require.NoError(t, storeTabular(t, store, value))
+
+	tableName := repository.AttributeTableName("entity-1", "budget")
+	mask := repository.ColumnMask{Redact: []string{"salary"}}
+	data, err := store.GetTypedData(context.Background(), tableName, repository.TabularQuery{Filters: map[string]interface{}{"department": "Health"}, Mask: mask})
+	require.NoError(t, err)
+
+	var columns []string
+	for _, column := range data.Columns {
+		columns = append(columns, column.Name+":"+column.Type)
+	}
+	assert.Equal(t, []string{"department:string", "year:int", "approved:bool", "date:datetime", "salary:int"}, columns)
+	require.Len(t, data.Rows, 1)
+	row := data.Rows[0].Values
+	assert.Equal(t, "Health", row[0].GetStringValue())
+	assert.Equal(t, int64(2024), row[1].GetIntValue())
+	assert.True(t, row[2].GetBoolValue())
+	assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), row[3].GetTimeValue().AsTime())
+	assert.Nil(t, row[4].GetValue(), "redacted cells are null")
+
+	data, err = store.GetTypedData(context.Background(), tableName, repository.TabularQuery{Fields: []string{"id", "created_at"}})
+	require.NoError(t, err)
+	assert.Equal(t, "int", data.Columns[0].Type)
+	assert.Equal(t, "datetime", data.Columns[1].Type)
+	assert.Equal(t, int64(1), data.Rows[0].Values[0].GetIntValue())
+	assert.NotNil(t, data.Rows[0].Values[1].GetTimeValue())
+}
+
+func TestTabularStoreDecimals(t *testing.T) {
+	store := NewTabularStore()
+
+	value := newTabularValue(t,
+		[]interface{}{"account", "budget", "allocated"},
+		[]interface{}{
+			[]interface{}{"9007199254740993", "123456789012345678.05", 0.1},
+			[]interface{}{4102444800000, "1200.5", "2500000000.10"},
+		})
+	require.NoError(t, storeTabular(t, store, value))
+
+	tableName := repository.AttributeTableName("entity-1", "budget")
+	data, err := store.GetTypedData(context.Background(), tableName, repository.TabularQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, "bigint", data.Columns[0].Type)
+	assert.Equal(t, "decimal", data.Columns[1].Type)
+	assert.Equal(t, int32(38), data.Columns[1].Precision)
+	assert.Equal(t, int32(2), data.Columns[1].Scale)
+	assert.Equal(t, "decimal", data.Columns[2].Type)
+
+	// Every digit comes back, padded to the scale of the column as NUMERIC(p,s) pads it
+	first, second := data.Rows[0].Values, data.Rows[1].Values
+	assert.Equal(t, int64(9007199254740993), first[0].GetIntValue())
+	assert.Equal(t, "123456789012345678.05", first[1].GetDecimalValue())
+	assert.Equal(t, "0.10", first[2].GetDecimalValue())
+	assert.Equal(t, "1200.50", second[1].GetDecimalValue())
+
+	_, rows := readTabular(t, store, map[string]interface{}{"budget": "1200.50"})
+	require.Len(t, rows, 1)
+	assert.Equal(t, []interface{}{float64(4102444800000), "1200.50", "2500000000.10"}, rows[0])
+
+	// Values the column would have to round are refused
+	rounded := newTabularValue(t,
+		[]interface{}{"account", "budget", "allocated"},
+		[]interface{}{[]interface{}{1, "10.125", 1}})
+	assert.Error(t, storeTabular(t, store, rounded))
+}
+
+func TestTabularStoreWrittenWindow(t *testing.T) {
+	store := NewTabularStore()
+
+	before := time.Now()
+	require.NoError(t, storeTabular(t, store, newTabularValue(t,
+		[]interface{}{"department", "year"},
+		[]interface{}{[]interface{}{"Health", 2024}})))
+	time.Sleep(time.Millisecond)
+	between := time.Now()
+	time.Sleep(time.Millisecond)
+	require.NoError(t, storeTabular(t, store, newTabularValue(t,
+		[]interface{}{"department", "year"},
+		[]interface{}{[]interface{}{"Education", 2025}})))
+
+	tableName := repository.AttributeTableName("entity-1", "budget")
+	count := func(query repository.TabularQuery) int {
+		data, err := store.GetTypedData(context.Background(), tableName, query)
+		require.NoError(t, err)
+		return len(data.Rows)
+	}
+	assert.Equal(t, 2, count(repository.TabularQuery{}))
+	assert.Equal(t, 2, count(repository.TabularQuery{WrittenFrom: before}), "the start of the window is inclusive")
+	assert.Equal(t, 1, count(repository.TabularQuery{WrittenUntil: between}), "the end of the window is exclusive")
+	assert.Equal(t, 1, count(repository.TabularQuery{WrittenFrom: between}))
+	assert.Equal(t, 0, count(repository.TabularQuery{WrittenFrom: time.Now().Add(time.Hour)}))
+}
+
+func TestTabularStorePages(t *testing.T) {
+	store := NewTabularStore()
+	require.NoError(t, storeTabular(t, store, newTabularValue(t,
+		[]interface{}{"department", "year"},
+		[]interface{}{
+			[]interface{}{"Health", 2022},
+			[]interface{}{"Education", 2023},
+			[]interface{}{"Health", 2024},
+			[]interface{}{"Health", 2025},
+		})))
+
+	tableName := repository.AttributeTableName("entity-1", "budget")
+	years := func(query repository.TabularQuery) []int64 {
+		data, err := store.GetTypedData(context.Background(), tableName, query)
+		require.NoError(t, err)
+		var years []int64
+		for _, row := range data.Rows {
+			years = append(years, row.Values[1].GetIntValue())
+		}
+		return years
+	}
+	health := map[string]interface{}{"department": "Health"}
+	assert.Equal(t, []int64{2022, 2024}, years(repository.TabularQuery{Filters: health, Limit: 2}))
+	assert.Equal(t, []int64{2025}, years(repository.TabularQuery{Filters: health, Limit: 2, Offset: 2}))
+	assert.Empty(t, years(repository.TabularQuery{Filters: health, Limit: 2, Offset: 4}))
+	assert.Equal(t, []int64{2023, 2024, 2025}, years(repository.TabularQuery{Offset: 1}))
+
+	count, err := store.CountData(context.Background(), tableName, repository.TabularQuery{Filters: health, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count, "the page does not limit the count")
+	_, err = store.CountData(context.Background(), tableName, repository.TabularQuery{Filters: health, Mask: repository.ColumnMask{Omit: []string{"department"}}})
+	assert.Error(t, err, "filters on masked columns are refused")
+}
+
+func TestColumnType(t *testing.T) {
+	field := func(dataType typeinference.DataType) *schema.SchemaInfo {
+		return &schema.SchemaInfo{TypeInfo: &typeinference.TypeInfo{Type: dataType}}
+	}
+	laidOut := &schema.SchemaInfo{
+		Fields:  map[string]*schema.SchemaInfo{"id": field(typeinference.StringType), "Amount (LKR)": field(typeinference.FloatType)},
+		Columns: repository.AssignTableColumns([]string{"id", "Amount (LKR)"}),
+	}
+	assert.Equal(t, typeinference.StringType, repository.ColumnType(laidOut, "id_1"))
+	assert.Equal(t, typeinference.FloatType, repository.ColumnType(laidOut, "amount__lkr_"))
+	assert.Equal(t, typeinference.IntType, repository.ColumnType(laidOut, "id"))
+	assert.Equal(t, typeinference.DateTimeType, repository.ColumnType(laidOut, "created_at"))
+
+	laidOut.Fields["Amount (LKR)"].TypeInfo = &typeinference.TypeInfo{Type: typeinference.DecimalType, Precision: 38, Scale: 2, IsNullable: true}
+	assert.Equal(t, typeinference.TypeInfo{Type: typeinference.DecimalType, Precision: 38, Scale: 2}, repository.ColumnTypeInfo(laidOut, "amount__lkr_"))
+
+	// Legacy schemas name fields as the producer did and store them under the sanitized name
+	legacy := &schema.SchemaInfo{Fields: map[string]*schema.SchemaInfo{"Year": field(typeinference.IntType), "note": field(typeinference.NullType)}}
+	assert.Equal(t, typeinference.IntType, repository.ColumnType(legacy, "year"))
+	assert.Equal(t, typeinference.StringType, repository.ColumnType(legacy, "note"))
+	assert.Equal(t, typeinference.StringType, repository.ColumnType(nil, "unknown"))
+}
+
+func TestAssignTableColumns(t *testing.T) {
+	columns := repository.AssignTableColumns([]string{"id", "Amount (LKR)", "amount_lkr_", "", "ID"})
+	var tableColumns []string
+	for i, column := range columns {
+		assert.Equal(t, i, column.Ordinal)
+		tableColumns = append(tableColumns, column.Column)
+	}
+	assert.Equal(t, []string{"id_1", "amount__lkr_", "amount_lkr_", "column", "id_5"}, tableColumns)
+}
@@ -0,0 +1,74 @@
+package mongorepository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// QuarantineCollectionSuffix is appended to the collection name to form the collection quarantined documents are moved to
+const QuarantineCollectionSuffix = "_quarantine"
+
+// ListDocumentIds streams the _id of every document in the collection
+func (repo *MongoRepository) ListDocumentIds(ctx context.Context, fn func(id string) error) error {
+	cursor, err := repo.collection(ctx).Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		slog.ErrorContext(ctx, "Error reading document ids", "error", err)
+		return fmt.Errorf("error reading document ids: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var document struct {
+			ID interface{} `bson:"_id"`
+		}
+		if err := cursor.Decode(&document); err != nil {
+			return fmt.Errorf("error decoding document id: %v", err)
+		}
+		if err := fn(fmt.Sprintf("%v", document.ID)); err != nil {
+			return err
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("error reading document ids: %v", err)
+	}
+	return nil
+}
+
+// DocumentExists reports whether a document with the given id exists
+func (repo *MongoRepository) DocumentExists(ctx context.Context, id string) (bool, error) {
+	count, err := repo.collection(ctx).CountDocuments(ctx, bson.M{"_id": id}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("error reading document %s: %v", id, err)
+	}
+	return count > 0, nil
+}
+
+// QuarantineDocument moves a document into the quarantine collection.
+// The copy replaces any earlier quarantined copy with the same id.
+func (repo *MongoRepository) QuarantineDocument(ctx context.Context, id string) error {
+	var document bson.M
+	if err := repo.collection(ctx).FindOne(ctx, bson.M{"_id": id}).Decode(&document); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return fmt.Errorf("document %s does not exist", id)
+		}
+		return fmt.Errorf("error reading document %s: %v", id, err)
+	}
+
+	quarantine := repo.client.Database(repo.config.DBName).Collection(repo.collectionName(ctx, repo.config.Collection+QuarantineCollectionSuffix))
+	if _, err := quarantine.ReplaceOne(ctx, bson.M{"_id": id}, document, options.Replace().SetUpsert(true)); err != nil {
+		slog.ErrorContext(ctx, "Error copying document", "document_id", id, "error", err)
+		return fmt.Errorf("error copying document %s to quarantine: %v", id, err)
+	}
+
+	if _, err := repo.collection(ctx).DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		slog.ErrorContext(ctx, "Error deleting document", "document_id", id, "error", err)
+		return fmt.Errorf("error deleting quarantined document %s: %v", id, err)
+	}
+	return nil
+}
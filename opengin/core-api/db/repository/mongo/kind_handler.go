@@ -0,0 +1,79 @@
+package mongorepository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// KindCollectionSuffix is appended to the collection name to form the collection the kind registry is kept in
+const KindCollectionSuffix = "_kinds"
+
+// kindDocument keeps one kind definition, keyed by its major kind, as protobuf JSON
+type kindDocument struct {
+	Major      string `bson:"_id"`
+	Definition string `bson:"definition"`
+}
+
+// kindCollection returns the kind registry collection of the call's tenant
+func (repo *MongoRepository) kindCollection(ctx context.Context) *mongo.Collection {
+	return repo.client.Database(repo.config.DBName).Collection(repo.collectionName(ctx, repo.config.Collection+KindCollectionSuffix))
+}
+
+// ListKinds returns the kind definitions of the call's tenant
+func (repo *MongoRepository) ListKinds(ctx context.Context) ([]*pb.KindDefinition, error) {
+	cursor, err := repo.kindCollection(ctx).Find(ctx, bson.M{})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error reading the kind registry", "error", err)
+		return nil, fmt.Errorf("error reading the kind registry: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var kinds []*pb.KindDefinition
+	for cursor.Next(ctx) {
+		var document kindDocument
+		if err := cursor.Decode(&document); err != nil {
+			return nil, fmt.Errorf("error decoding kind definition: %v", err)
+		}
+		def := &pb.KindDefinition{}
+		if err := protojson.Unmarshal([]byte(document.Definition), def); err != nil {
+			return nil, fmt.Errorf("error decoding kind definition %s: %v", document.Major, err)
+		}
+		kinds = append(kinds, def)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("error reading the kind registry: %v", err)
+	}
+	return kinds, nil
+}
+
+// PutKind creates the definition of a major kind or replaces it
+func (repo *MongoRepository) PutKind(ctx context.Context, def *pb.KindDefinition) error {
+	encoded, err := protojson.Marshal(def)
+	if err != nil {
+		return fmt.Errorf("error encoding kind definition %s: %v", def.Major, err)
+	}
+	document := kindDocument{Major: def.Major, Definition: string(encoded)}
+	if _, err := repo.kindCollection(ctx).ReplaceOne(ctx, bson.M{"_id": def.Major}, document, options.Replace().SetUpsert(true)); err != nil {
+		slog.ErrorContext(ctx, "Error saving kind definition", "kind", def.Major, "error", err)
+		return fmt.Errorf("error saving kind definition %s: %v", def.Major, err)
+	}
+	return nil
+}
+
+// DeleteKind removes the definition of a major kind; removing a missing one is not an error
+func (repo *MongoRepository) DeleteKind(ctx context.Context, major string) error {
+	if _, err := repo.kindCollection(ctx).DeleteOne(ctx, bson.M{"_id": major}); err != nil {
+		slog.ErrorContext(ctx, "Error deleting kind definition", "kind", major, "error", err)
+		return fmt.Errorf("error deleting kind definition %s: %v", major, err)
+	}
+	return nil
+}
@@ -2,14 +2,15 @@ package mongorepository
 
 import (
 	"context"
-	"log"
+	"errors"
+	"log/slog"
 
+	"lk/datafoundation/core-api/db/repository"
 	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
 
 	"google.golang.org/protobuf/types/known/anypb"
 
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // Add this function to handle metadata operations
@@ -21,7 +22,7 @@ func (repo *MongoRepository) HandleMetadata(ctx context.Context, entityId string
 
 	// Check if metadata for entity already exists
 	existingEntity, err := repo.ReadEntity(ctx, entityId)
-	if err != nil && err != mongo.ErrNoDocuments {
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
 		return err
 	}
 
@@ -54,7 +55,7 @@ func (repo *MongoRepository) GetMetadata(ctx context.Context, entityId string) (
 	entity, err := repo.ReadEntity(ctx, entityId)
 	if err != nil {
 		// Log error and return empty metadata map
-		log.Printf("Error retrieving metadata for entity %s: %v", entityId, err)
+		slog.ErrorContext(ctx, "Error retrieving metadata", "entity_id", entityId, "error", err)
 		metadata := make(map[string]*anypb.Any)
 		return metadata, nil
 	}
@@ -68,3 +69,9 @@ func (repo *MongoRepository) GetMetadata(ctx context.Context, entityId string) (
 	// Return the original protobuf Any metadata
 	return entity.Metadata, nil
 }
+
+// DeleteMetadata removes the metadata document of an entity
+func (repo *MongoRepository) DeleteMetadata(ctx context.Context, entityId string) error {
+	_, err := repo.DeleteEntity(ctx, entityId)
+	return err
+}
@@ -2,14 +2,20 @@ package mongorepository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"lk/datafoundation/core-api/db/config"
-	"log"
+	"log/slog"
+	"os"
 
+	"lk/datafoundation/core-api/db/repository"
 	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/tenant"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"google.golang.org/protobuf/types/known/anypb"
 )
 
@@ -56,10 +62,19 @@ func fromDocument(data *entityDocument) *pb.Entity {
 // NewMongoRepository initializes a MongoDB client
 // TODO: Handle errors better
 func NewMongoRepository(ctx context.Context, config *config.MongoConfig) *MongoRepository {
-	clientOptions := options.Client().ApplyURI(config.URI)
+	clientOptions := options.Client().ApplyURI(config.URI).
+		SetMonitor(commandMonitor()).
+		SetPoolMonitor(poolMonitor())
+	if config.MaxPoolSize > 0 {
+		clientOptions.SetMaxPoolSize(config.MaxPoolSize)
+	}
+	if config.MinPoolSize > 0 {
+		clientOptions.SetMinPoolSize(config.MinPoolSize)
+	}
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
-		log.Fatal(err)
+		slog.ErrorContext(ctx, "Failed to connect to MongoDB", "error", err)
+		os.Exit(1)
 	}
 	return &MongoRepository{
 		client: client,
@@ -67,8 +82,29 @@ func NewMongoRepository(ctx context.Context, config *config.MongoConfig) *MongoR
 	}
 }
 
-func (repo *MongoRepository) collection() *mongo.Collection {
-	return repo.client.Database(repo.config.DBName).Collection(repo.config.Collection)
+// Ping checks that the primary can be reached
+func (repo *MongoRepository) Ping(ctx context.Context) error {
+	return repo.client.Ping(ctx, readpref.Primary())
+}
+
+// Close disconnects the client and releases its connection pool
+func (repo *MongoRepository) Close(ctx context.Context) error {
+	return repo.client.Disconnect(ctx)
+}
+
+// collectionName returns the name a collection has for the tenant of the call.
+// The default tenant uses the plain name and other tenants prefix it with "<tenant>.",
+// which no tenant name can contain, so the collections of two tenants never share a name.
+func (repo *MongoRepository) collectionName(ctx context.Context, name string) string {
+	if tenant.IsDefault(ctx) {
+		return name
+	}
+	return tenant.FromContext(ctx) + "." + name
+}
+
+// collection returns the entity collection of the call's tenant
+func (repo *MongoRepository) collection(ctx context.Context) *mongo.Collection {
+	return repo.client.Database(repo.config.DBName).Collection(repo.collectionName(ctx, repo.config.Collection))
 }
 
 // CreateEntity inserts a new entity in MongoDB
@@ -76,14 +112,18 @@ func (repo *MongoRepository) collection() *mongo.Collection {
 func (repo *MongoRepository) CreateEntity(ctx context.Context, entity *pb.Entity) (*mongo.InsertOneResult, error) {
 	// Use the entity.Id as MongoDB's _id field
 	doc := toDocument(entity)
-	result, err := repo.collection().InsertOne(ctx, doc)
+	result, err := repo.collection(ctx).InsertOne(ctx, doc)
 	return result, err
 }
 
-// ReadEntity fetches an entity by ID from MongoDB
+// ReadEntity fetches an entity by ID from MongoDB. A missing document is an error wrapping both
+// repository.ErrNotFound and mongo.ErrNoDocuments.
 func (repo *MongoRepository) ReadEntity(ctx context.Context, id string) (*pb.Entity, error) {
 	var doc entityDocument
-	err := repo.collection().FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	err := repo.collection(ctx).FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("document with id %s %w: %w", id, repository.ErrNotFound, err)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -93,12 +133,12 @@ func (repo *MongoRepository) ReadEntity(ctx context.Context, id string) (*pb.Ent
 // UpdateEntity updates an entity's attributes in MongoDB
 func (repo *MongoRepository) UpdateEntity(ctx context.Context, id string, updates bson.M) (*mongo.UpdateResult, error) {
 	update := bson.M{"$set": updates}
-	result, err := repo.collection().UpdateOne(ctx, bson.M{"_id": id}, update)
+	result, err := repo.collection(ctx).UpdateOne(ctx, bson.M{"_id": id}, update)
 	return result, err
 }
 
 // DeleteEntity removes an entity from MongoDB
 func (repo *MongoRepository) DeleteEntity(ctx context.Context, id string) (*mongo.DeleteResult, error) {
-	result, err := repo.collection().DeleteOne(ctx, bson.M{"_id": id})
+	result, err := repo.collection(ctx).DeleteOne(ctx, bson.M{"_id": id})
 	return result, err
 }
@@ -43,7 +43,7 @@ func TestMain(m *testing.M) {
 	testRepo = NewMongoRepository(testCtx, testConfig)
 
 	// Clear test collection before tests
-	// testRepo.collection().Drop(testCtx)
+	// testRepo.collection(testCtx).Drop(testCtx)
 
 	// Add before running tests
 	if err := testRepo.client.Ping(testCtx, nil); err != nil {
@@ -58,7 +58,7 @@ func TestMain(m *testing.M) {
 	exitCode := m.Run()
 
 	// Clean up after tests
-	// testRepo.collection().Drop(testCtx)
+	// testRepo.collection(testCtx).Drop(testCtx)
 
 	log.Println("Tests completed")
 	os.Exit(exitCode)
@@ -0,0 +1,65 @@
+package mongorepository
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"lk/datafoundation/core-api/pkg/metrics"
+	"lk/datafoundation/core-api/pkg/tracing"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// commandMonitor records every command the driver sends (insert, find, update, delete, ...)
+// as a metric and as a span under the caller's context
+func commandMonitor() *event.CommandMonitor {
+	// Spans are kept by request id between the started and finished events
+	var spans sync.Map
+
+	finish := func(requestID int64, err error) {
+		if value, ok := spans.LoadAndDelete(requestID); ok {
+			tracing.End(value.(trace.Span), &err)
+		}
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			attributes := []attribute.KeyValue{attribute.String("db.namespace", evt.DatabaseName)}
+			if collection, ok := evt.Command.Lookup(evt.CommandName).StringValueOK(); ok {
+				attributes = append(attributes, attribute.String("db.collection.name", collection))
+			}
+			_, span := tracing.StartDatabase(ctx, metrics.MongoDB, evt.CommandName, attributes...)
+			spans.Store(evt.RequestID, span)
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			metrics.ObserveDatabaseDuration(metrics.MongoDB, evt.CommandName, evt.Duration, nil)
+			finish(evt.RequestID, nil)
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			err := errors.New(evt.Failure)
+			metrics.ObserveDatabaseDuration(metrics.MongoDB, evt.CommandName, evt.Duration, err)
+			finish(evt.RequestID, err)
+		},
+	}
+}
+
+// poolMonitor keeps the pool gauges in step with the connections the driver opens, closes and checks out
+func poolMonitor() *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(evt *event.PoolEvent) {
+			switch evt.Type {
+			case event.ConnectionCreated:
+				metrics.AddPoolConnections(metrics.MongoDB, "open", 1)
+			case event.ConnectionClosed:
+				metrics.AddPoolConnections(metrics.MongoDB, "open", -1)
+			case event.GetSucceeded:
+				metrics.AddPoolConnections(metrics.MongoDB, "in_use", 1)
+			case event.ConnectionReturned:
+				metrics.AddPoolConnections(metrics.MongoDB, "in_use", -1)
+			}
+		},
+	}
+}
@@ -0,0 +1,93 @@
+package mongorepository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"lk/datafoundation/core-api/pkg/tenant"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// The snapshot methods name collections as the call's tenant sees them, so a snapshot of one
+// tenant can be restored into another.
+
+// ListCollectionNames returns the names of the collections of the call's tenant in the configured database
+func (repo *MongoRepository) ListCollectionNames(ctx context.Context) ([]string, error) {
+	names, err := repo.client.Database(repo.config.DBName).ListCollectionNames(ctx, bson.M{})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error listing collections", "error", err)
+		return nil, fmt.Errorf("error listing collections: %v", err)
+	}
+
+	prefix := tenant.FromContext(ctx) + "."
+	var own []string
+	for _, name := range names {
+		switch {
+		case tenant.IsDefault(ctx) && !strings.Contains(name, "."):
+			own = append(own, name)
+		case !tenant.IsDefault(ctx) && strings.HasPrefix(name, prefix):
+			own = append(own, strings.TrimPrefix(name, prefix))
+		}
+	}
+	return own, nil
+}
+
+// ExportCollection streams every document of a collection as canonical extended JSON,
+// which keeps BSON types such as binary protobuf payloads intact
+func (repo *MongoRepository) ExportCollection(ctx context.Context, collection string, fn func(document []byte) error) error {
+	cursor, err := repo.client.Database(repo.config.DBName).Collection(repo.collectionName(ctx, collection)).Find(ctx, bson.M{})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error reading collection", "collection", collection, "error", err)
+		return fmt.Errorf("error reading collection %s: %v", collection, err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		document, err := bson.MarshalExtJSON(cursor.Current, true, false)
+		if err != nil {
+			return fmt.Errorf("error encoding document from %s: %v", collection, err)
+		}
+		if err := fn(document); err != nil {
+			return err
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("error reading collection %s: %v", collection, err)
+	}
+	return nil
+}
+
+// RestoreDocuments inserts a batch of canonical extended JSON documents into a collection
+func (repo *MongoRepository) RestoreDocuments(ctx context.Context, collection string, documents [][]byte) error {
+	if len(documents) == 0 {
+		return nil
+	}
+
+	batch := make([]interface{}, 0, len(documents))
+	for _, document := range documents {
+		var decoded bson.D
+		if err := bson.UnmarshalExtJSON(document, true, &decoded); err != nil {
+			return fmt.Errorf("error decoding document for %s: %v", collection, err)
+		}
+		batch = append(batch, decoded)
+	}
+
+	if _, err := repo.client.Database(repo.config.DBName).Collection(repo.collectionName(ctx, collection)).InsertMany(ctx, batch); err != nil {
+		slog.ErrorContext(ctx, "Error restoring documents", "collection", collection, "error", err)
+		return fmt.Errorf("error inserting into %s: %v", collection, err)
+	}
+	return nil
+}
+
+// CountDocuments returns the number of documents in a collection
+func (repo *MongoRepository) CountDocuments(ctx context.Context, collection string) (int64, error) {
+	count, err := repo.client.Database(repo.config.DBName).Collection(repo.collectionName(ctx, collection)).CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return 0, fmt.Errorf("error counting documents in %s: %v", collection, err)
+	}
+	return count, nil
+}
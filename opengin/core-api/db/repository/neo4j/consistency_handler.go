@@ -0,0 +1,77 @@
+package neo4jrepository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"lk/datafoundation/core-api/pkg/metrics"
+	"lk/datafoundation/core-api/pkg/tracing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// ListNodeIds streams the Id of every node in the graph, entities and Dataset nodes alike
+func (r *Neo4jRepository) ListNodeIds(ctx context.Context, fn func(id string) error) error {
+	return r.streamRead(ctx, "ListNodeIds", `MATCH (n {Tenant: $tenant}) WHERE n.Id IS NOT NULL RETURN n.Id AS Id`, func(values map[string]interface{}) error {
+		return fn(values["Id"].(string))
+	})
+}
+
+// ListDatasets streams every Dataset node of the attribute lookup graph.
+// Datasets are passed as maps with Id, Name, MinorKind and EntityId, the owner found through IS_ATTRIBUTE.
+// EntityId is missing when the node has no owner.
+func (r *Neo4jRepository) ListDatasets(ctx context.Context, fn func(map[string]interface{}) error) error {
+	query := `
+		MATCH (d:Dataset {Tenant: $tenant})
+		OPTIONAL MATCH (e)-[:IS_ATTRIBUTE]->(d)
+		WITH d, head(collect(e.Id)) AS EntityId
+		RETURN d.Id AS Id, d.Name AS Name, d.MinorKind AS MinorKind, EntityId
+		ORDER BY d.Id`
+	return r.streamRead(ctx, "ListDatasets", query, fn)
+}
+
+// NodeExists reports whether a node with the given Id exists
+func (r *Neo4jRepository) NodeExists(ctx context.Context, id string) (_ bool, err error) {
+	defer metrics.ObserveDatabase(metrics.Neo4j, "NodeExists", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Neo4j, "NodeExists")
+	defer tracing.End(span, &err)
+	session := r.getSession(ctx)
+	defer session.Close(ctx)
+
+	result, err := session.Run(ctx, `MATCH (n {Id: $Id, Tenant: $tenant}) RETURN count(n) > 0 AS exists`, map[string]interface{}{"Id": id})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error querying node", "node_id", id, "error", err)
+		return false, fmt.Errorf("error querying node %s: %v", id, err)
+	}
+	record, err := result.Single(ctx)
+	if err != nil {
+		return false, fmt.Errorf("error querying node %s: %v", id, err)
+	}
+	exists, _ := record.Get("exists")
+	found, _ := exists.(bool)
+	return found, nil
+}
+
+// streamRead runs a query in a read transaction and passes each record to fn
+func (r *Neo4jRepository) streamRead(ctx context.Context, operation string, query string, fn func(map[string]interface{}) error) (err error) {
+	defer metrics.ObserveDatabase(metrics.Neo4j, operation, time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Neo4j, operation)
+	defer tracing.End(span, &err)
+	session := r.client.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	tx, err := session.BeginTransaction(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error starting transaction", "operation", operation, "error", err)
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Close(ctx)
+
+	if err := streamRecords(ctx, tx, query, fn); err != nil {
+		slog.ErrorContext(ctx, "Error reading graph", "operation", operation, "error", err)
+		return fmt.Errorf("error reading graph: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Package cypher builds the Cypher queries of the Neo4j repository that name a label or relationship type.
+//
+// Labels and relationship types cannot be passed as query parameters, so they are spliced into the query
+// text. Every builder here validates them with repository.ValidateGraphIdentifier, refusing anything but
+// letters, digits and underscores with codes.InvalidArgument, and quotes them with backticks, so neither a
+// crafted kind nor a crafted relationship name can change the structure of a query.
+package cypher
+
+import (
+	"strings"
+
+	"lk/datafoundation/core-api/db/repository"
+)
+
+// Quote quotes a label or relationship type with backticks, doubling any backtick it holds
+func Quote(identifier string) string {
+	return "`" + strings.ReplaceAll(identifier, "`", "``") + "`"
+}
+
+// Label validates a kind and returns it quoted for use as a node label
+func Label(kind string) (string, error) {
+	if err := repository.ValidateGraphIdentifier("kind", kind); err != nil {
+		return "", err
+	}
+	return Quote(kind), nil
+}
+
+// RelationshipType validates a relationship name and returns it quoted for use as a relationship type
+func RelationshipType(name string) (string, error) {
+	if err := repository.ValidateGraphIdentifier("relationship name", name); err != nil {
+		return "", err
+	}
+	return Quote(name), nil
+}
+
+// EntityExists matches the entity of a kind with id $Id
+func EntityExists(kind string) (string, error) {
+	label, err := Label(kind)
+	if err != nil {
+		return "", err
+	}
+	return `MATCH (e:` + label + ` {Id: $Id, Tenant: $tenant}) RETURN e`, nil
+}
+
+// CreateEntity creates an entity of a kind from $Id, $Name, $Created, $MinorKind and, when terminated is set, $Terminated
+func CreateEntity(kind string, terminated bool) (string, error) {
+	label, err := Label(kind)
+	if err != nil {
+		return "", err
+	}
+	query := `CREATE (e:` + label + ` {Id: $Id, Tenant: $tenant, Name: $Name, Created: datetime($Created), MinorKind: $MinorKind`
+	if terminated {
+		query += `, Terminated: datetime($Terminated)`
+	}
+	return query + `}) RETURN e`, nil
+}
+
+// CreateRelationship creates a relationship from $parentID to $childID with id $relationshipID starting at
+// $startDate and, when terminated is set, ending at $endDate
+func CreateRelationship(name string, terminated bool) (string, error) {
+	relationshipType, err := RelationshipType(name)
+	if err != nil {
+		return "", err
+	}
+	query := `MATCH (p {Id: $parentID, Tenant: $tenant}), (c {Id: $childID, Tenant: $tenant})
+					CREATE (p)-[r:` + relationshipType + ` {Id: $relationshipID, Tenant: $tenant, Created: datetime($startDate)`
+	if terminated {
+		query += `, Terminated: datetime($endDate)`
+	}
+	return query + `}]->(c) RETURN r`, nil
+}
+
+// RelatedEntities returns the relationships of a type from $entityID that are active at $ts
+func RelatedEntities(name string) (string, error) {
+	relationshipType, err := RelationshipType(name)
+	if err != nil {
+		return "", err
+	}
+	return `
+        MATCH (e {Id: $entityID, Tenant: $tenant})-[r:` + relationshipType + `]->(related)
+        WHERE r.Created <= datetime($ts) AND (r.Terminated IS NULL OR r.Terminated > datetime($ts))
+        RETURN r.Id AS relationshipID, r.Created AS startTime, r.Terminated AS endTime, type(r) AS name, related.Id AS relatedEntityId
+    `, nil
+}
+
+// MatchKind starts a query over the entities of a kind; conditions are appended with AND
+func MatchKind(kind string) (string, error) {
+	label, err := Label(kind)
+	if err != nil {
+		return "", err
+	}
+	return `MATCH (e:` + label + `) WHERE e.Tenant = $tenant `, nil
+}
+
+// RestoreEntities creates the entities of a kind listed in $rows
+func RestoreEntities(kind string) (string, error) {
+	label, err := Label(kind)
+	if err != nil {
+		return "", err
+	}
+	return `
+		UNWIND $rows AS row
+		CREATE (e:` + label + ` {Id: row.Id, Tenant: $tenant})
+		SET e.Name = row.Name,
+		    e.MinorKind = row.MinorKind,
+		    e.Created = CASE WHEN row.Created IS NULL THEN NULL ELSE datetime(row.Created) END,
+		    e.Terminated = CASE WHEN row.Terminated IS NULL THEN NULL ELSE datetime(row.Terminated) END`, nil
+}
+
+// RestoreRelationships creates the relationships of a type listed in $rows between entities of the given kinds
+func RestoreRelationships(name, sourceKind, targetKind string) (string, error) {
+	relationshipType, err := RelationshipType(name)
+	if err != nil {
+		return "", err
+	}
+	sourceLabel, err := Label(sourceKind)
+	if err != nil {
+		return "", err
+	}
+	targetLabel, err := Label(targetKind)
+	if err != nil {
+		return "", err
+	}
+	return `
+		UNWIND $rows AS row
+		MATCH (s:` + sourceLabel + ` {Id: row.SourceId, Tenant: $tenant}), (t:` + targetLabel + ` {Id: row.TargetId, Tenant: $tenant})
+		CREATE (s)-[r:` + relationshipType + ` {Id: row.Id, Tenant: $tenant}]->(t)
+		SET r.Created = CASE WHEN row.Created IS NULL THEN NULL ELSE datetime(row.Created) END,
+		    r.Terminated = CASE WHEN row.Terminated IS NULL THEN NULL ELSE datetime(row.Terminated) END
+		RETURN count(r) AS created`, nil
+}
@@ -0,0 +1,149 @@
+package cypher
+
+import (
+	"strings"
+	"testing"
+
+	"lk/datafoundation/core-api/commons"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// seeds are identifiers and injection attempts every fuzz test starts from
+var seeds = []string{
+	"Person", "HAS_MEMBER", "_private", "Dataset2", "",
+	"Person) DETACH DELETE (e", "REL`]->() MATCH (n) DETACH DELETE n //", "Bad`Label", "a``b", "`",
+	"Person {Id: 'x'}", "Organisation:Admin", "line\nbreak", "ラベル", "1abc", "a-b", "a b", "$tenant",
+}
+
+// splitIdentifiers lexes the backtick quoted identifiers out of a query the way Cypher does, where a doubled
+// backtick stands for one backtick, and returns them with the query left when each is replaced by "?".
+// ok is false when a quoted identifier is not closed.
+func splitIdentifiers(query string) (identifiers []string, rest string, ok bool) {
+	var out strings.Builder
+	for i := 0; i < len(query); i++ {
+		if query[i] != '`' {
+			out.WriteByte(query[i])
+			continue
+		}
+		var identifier strings.Builder
+		closed := false
+		for i++; i < len(query); i++ {
+			if query[i] != '`' {
+				identifier.WriteByte(query[i])
+				continue
+			}
+			if i+1 < len(query) && query[i+1] == '`' {
+				identifier.WriteByte('`')
+				i++
+				continue
+			}
+			closed = true
+			break
+		}
+		if !closed {
+			return nil, "", false
+		}
+		identifiers = append(identifiers, identifier.String())
+		out.WriteString("?")
+	}
+	return identifiers, out.String(), true
+}
+
+// checkBuilder asserts that a builder either refuses the inputs with codes.InvalidArgument or returns its
+// template with exactly the inputs in the identifier positions
+func checkBuilder(t *testing.T, build func(inputs ...string) (string, error), inputs ...string) {
+	t.Helper()
+
+	placeholders := make([]string, len(inputs))
+	for i := range inputs {
+		placeholders[i] = "Placeholder"
+	}
+	template, err := build(placeholders...)
+	require.NoError(t, err)
+	_, templateRest, ok := splitIdentifiers(template)
+	require.True(t, ok)
+
+	valid := true
+	for _, input := range inputs {
+		valid = valid && commons.IsGraphIdentifier(input)
+	}
+
+	query, err := build(inputs...)
+	if !valid {
+		require.Error(t, err, "inputs %q", inputs)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		return
+	}
+	require.NoError(t, err, "inputs %q", inputs)
+
+	identifiers, rest, ok := splitIdentifiers(query)
+	require.True(t, ok, "unterminated identifier in %q", query)
+	assert.Equal(t, templateRest, rest, "inputs %q changed the query structure", inputs)
+	assert.ElementsMatch(t, inputs, identifiers)
+}
+
+func FuzzQuote(f *testing.F) {
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, identifier string) {
+		// Quoting alone keeps any string inside its identifier
+		identifiers, rest, ok := splitIdentifiers("MATCH (e:" + Quote(identifier) + ") RETURN e")
+		require.True(t, ok)
+		assert.Equal(t, []string{identifier}, identifiers)
+		assert.Equal(t, "MATCH (e:?) RETURN e", rest)
+	})
+}
+
+func FuzzLabel(f *testing.F) {
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, kind string) {
+		checkBuilder(t, func(in ...string) (string, error) { return EntityExists(in[0]) }, kind)
+		checkBuilder(t, func(in ...string) (string, error) { return CreateEntity(in[0], false) }, kind)
+		checkBuilder(t, func(in ...string) (string, error) { return CreateEntity(in[0], true) }, kind)
+		checkBuilder(t, func(in ...string) (string, error) { return MatchKind(in[0]) }, kind)
+		checkBuilder(t, func(in ...string) (string, error) { return RestoreEntities(in[0]) }, kind)
+	})
+}
+
+func FuzzRelationshipType(f *testing.F) {
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		checkBuilder(t, func(in ...string) (string, error) { return CreateRelationship(in[0], false) }, name)
+		checkBuilder(t, func(in ...string) (string, error) { return CreateRelationship(in[0], true) }, name)
+		checkBuilder(t, func(in ...string) (string, error) { return RelatedEntities(in[0]) }, name)
+	})
+}
+
+func FuzzRestoreRelationships(f *testing.F) {
+	for _, seed := range seeds {
+		f.Add(seed, "Person", "Organisation")
+		f.Add("HAS_MEMBER", seed, "Organisation")
+		f.Add("HAS_MEMBER", "Person", seed)
+	}
+	f.Fuzz(func(t *testing.T, name, sourceKind, targetKind string) {
+		checkBuilder(t, func(in ...string) (string, error) { return RestoreRelationships(in[0], in[1], in[2]) }, name, sourceKind, targetKind)
+	})
+}
+
+func TestBuilders(t *testing.T) {
+	query, err := CreateEntity("Person", true)
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE (e:`Person` {Id: $Id, Tenant: $tenant, Name: $Name, Created: datetime($Created), MinorKind: $MinorKind, Terminated: datetime($Terminated)}) RETURN e", query)
+
+	_, err = MatchKind("Person) DETACH DELETE (e")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, err.Error(), `invalid kind "Person) DETACH DELETE (e"`)
+
+	_, err = CreateRelationship("REL`]->() MATCH (n) DETACH DELETE n //", false)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, err.Error(), "invalid relationship name")
+}
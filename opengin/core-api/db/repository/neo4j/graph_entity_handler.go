@@ -3,9 +3,11 @@ package neo4jrepository
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 
+	"lk/datafoundation/core-api/db/repository/neo4j/cypher"
 	pb "lk/datafoundation/core-api/lk/datafoundation/core-api" // Replace with your actual protobuf package
+	"lk/datafoundation/core-api/pkg/logging"
 
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
@@ -62,7 +64,7 @@ func (repo *Neo4jRepository) GetGraphEntity(ctx context.Context, entityId string
 			terminated = termValue.(string)
 		}
 	} else {
-		log.Printf("[neo4j_handler.GetGraphEntity] Error reading entity %s: %v", entityId, err)
+		slog.ErrorContext(ctx, "Error reading entity", "entity_id", entityId, "error", err)
 		return nil, nil, "", "", fmt.Errorf("[neo4j_handler.GetGraphEntity] error reading entity: %v", err)
 	}
 
@@ -75,7 +77,7 @@ func (repo *Neo4jRepository) GetGraphRelationships(ctx context.Context, entityId
 	// Retrieve relationships from Neo4j
 	relData, err := repo.ReadRelationships(ctx, entityId)
 	if err != nil {
-		log.Printf("[neo4j_handler.GetGraphRelationships] Error reading relationships for entity %s: %v", entityId, err)
+		slog.ErrorContext(ctx, "Error reading relationships", "entity_id", entityId, "error", err)
 		return relationships, fmt.Errorf("[neo4j_handler.GetGraphRelationships] error reading relationships: %v", err)
 	}
 
@@ -145,7 +147,7 @@ func (repo *Neo4jRepository) GetFilteredRelationships(ctx context.Context, entit
 	relationshipData, err := repo.ReadFilteredRelationships(ctx, entityId, filters, activeAt)
 
 	if err != nil {
-		log.Printf("[GetEntityIdsByRelationship] Error fetching related relationships for entity %s with filters %v: %v", entityId, filters, err)
+		slog.ErrorContext(ctx, "Error fetching related relationships with filters", "entity_id", entityId, "filters", filters, "error", err)
 		return nil, err
 	}
 
@@ -161,7 +163,7 @@ func (repo *Neo4jRepository) GetFilteredRelationships(ctx context.Context, entit
 
 		// Ensure required fields are present
 		if !relIDOk || !relatedEntityIdOk || !startTimeOk || !nameOk || !directionOk {
-			log.Printf("[GetEntityIdsByRelationship] Missing required fields in relationship: %v", rel)
+			slog.DebugContext(ctx, "Missing required fields in relationship", "relationship", rel)
 			return nil, fmt.Errorf("relationship missing required fields: %v", rel)
 		}
 
@@ -179,23 +181,113 @@ func (repo *Neo4jRepository) GetFilteredRelationships(ctx context.Context, entit
 	return relationships, nil
 }
 
+// GetGraphPaths retrieves the shortest paths between two entities as ordered entity and relationship lists
+func (repo *Neo4jRepository) GetGraphPaths(ctx context.Context, req *pb.PathRequest) ([]*pb.EntityPath, error) {
+	if req == nil {
+		return nil, fmt.Errorf("path request cannot be nil")
+	}
+
+	pathData, err := repo.ReadShortestPaths(ctx, req.SourceEntityId, req.TargetEntityId, req.RelationshipNames, req.ActiveAt, req.Direction, int(req.MaxDepth), req.AllShortestPaths)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error reading paths", "source_entity_id", req.SourceEntityId, "target_entity_id", req.TargetEntityId, "error", err)
+		return nil, err
+	}
+
+	paths := make([]*pb.EntityPath, 0, len(pathData))
+	for _, path := range pathData {
+		entityData, _ := path["entities"].([]map[string]interface{})
+		relationshipData, _ := path["relationships"].([]map[string]interface{})
+
+		if len(entityData) != len(relationshipData)+1 {
+			return nil, fmt.Errorf("malformed path with %d entities and %d relationships", len(entityData), len(relationshipData))
+		}
+
+		entityPath := &pb.EntityPath{}
+		for _, entityMap := range entityData {
+			entityPath.Entities = append(entityPath.Entities, pathEntityFromMap(entityMap))
+		}
+
+		for i, rel := range relationshipData {
+			relID, _ := rel["id"].(string)
+			name, _ := rel["name"].(string)
+			startEntityID, _ := rel["startEntityId"].(string)
+			startTime, _ := rel["startTime"].(string)
+			endTime, _ := rel["endTime"].(string)
+
+			// Each hop is expressed from the point of view of entities[i], so the
+			// direction tells whether the stored relationship points along the path or against it
+			fromID := entityPath.Entities[i].Id
+			toID := entityPath.Entities[i+1].Id
+			direction := "OUTGOING"
+			if startEntityID != fromID {
+				direction = "INCOMING"
+			}
+
+			entityPath.Relationships = append(entityPath.Relationships, &pb.Relationship{
+				Id:              relID,
+				Name:            name,
+				RelatedEntityId: toID,
+				StartTime:       startTime,
+				EndTime:         endTime,
+				Direction:       direction,
+			})
+		}
+
+		paths = append(paths, entityPath)
+	}
+
+	return paths, nil
+}
+
+// pathEntityFromMap converts an entity projection from a path query into a pb.Entity
+func pathEntityFromMap(entityMap map[string]interface{}) *pb.Entity {
+	id, _ := entityMap["Id"].(string)
+	majorKind, _ := entityMap["MajorKind"].(string)
+	minorKind, _ := entityMap["MinorKind"].(string)
+	created, _ := entityMap["Created"].(string)
+	terminated, _ := entityMap["Terminated"].(string)
+
+	entity := &pb.Entity{
+		Id: id,
+		Kind: &pb.Kind{
+			Major: majorKind,
+			Minor: minorKind,
+		},
+		Created:    created,
+		Terminated: terminated,
+	}
+
+	if nameValue, ok := entityMap["Name"].(string); ok {
+		value, _ := anypb.New(&wrapperspb.StringValue{
+			Value: nameValue,
+		})
+		entity.Name = &pb.TimeBasedValue{
+			StartTime: created,
+			EndTime:   terminated,
+			Value:     value,
+		}
+	}
+
+	return entity
+}
+
 // validateGraphEntityCreation checks if an entity has all required fields for Neo4j storage
 func validateGraphEntityCreation(entity *pb.Entity) bool {
 	// Check if Kind is present and has a Major value
 	if entity.Kind == nil || entity.Kind.GetMajor() == "" || entity.Kind.GetMinor() == "" {
-		log.Printf("[neo4j_handler.validateGraphEntityCreation] Skipping Neo4j entity creation for %s: Missing or empty Kind.Major", entity.Id)
+		slog.Debug("Skipping Neo4j entity creation: Missing or empty Kind.Major", "entity_id", entity.Id)
 		return false
 	}
 
 	// Check if Name is present and has a Value
 	if entity.Name == nil || entity.Name.GetValue() == nil {
-		log.Printf("[neo4j_handler.validateGraphEntityCreation] Skipping Neo4j entity creation for %s: Missing or empty Name.Value", entity.Id)
+		slog.Debug("Skipping Neo4j entity creation: Missing or empty Name.Value", "entity_id", entity.Id)
 		return false
 	}
 
 	// Check if Created date is present
 	if entity.Created == "" {
-		log.Printf("[neo4j_handler.validateGraphEntityCreation] Skipping Neo4j entity creation for %s: Missing Created date", entity.Id)
+		slog.Debug("Skipping Neo4j entity creation: Missing Created date", "entity_id", entity.Id)
 		return false
 	}
 
@@ -206,11 +298,11 @@ func validateGraphEntityCreation(entity *pb.Entity) bool {
 func (repo *Neo4jRepository) HandleGraphEntityCreation(ctx context.Context, entity *pb.Entity) (bool, error) {
 	// Validate required fields for Neo4j entity creation
 	if !validateGraphEntityCreation(entity) {
-		log.Printf("[neo4j_handler.HandleGraphEntityCreation] Neo4j entity creation failed for entity: %s", entity.Id)
+		slog.ErrorContext(ctx, "Neo4j entity creation failed", "entity_id", entity.Id)
 		return false, fmt.Errorf("[neo4j_handler.HandleGraphEntityCreation] missing required fields for Neo4j entity creation")
 	}
 
-	log.Printf("[neo4j_handler.HandleGraphEntityCreation] Creating new entity in Neo4j: %s", entity.Id)
+	slog.DebugContext(ctx, "Creating new entity in Neo4j", "entity_id", entity.Id)
 
 	// Prepare data for Neo4j with safety checks
 	entityMap := map[string]interface{}{
@@ -240,17 +332,17 @@ func (repo *Neo4jRepository) HandleGraphEntityCreation(ctx context.Context, enti
 					// The first byte is the length, followed by the actual string
 					if len(rawValue) > 1 {
 						entityMap["Name"] = string(rawValue[1:])
-						log.Printf("Using raw value from Any: %s\n", string(rawValue[1:]))
+						slog.DebugContext(ctx, "Using raw value from Any", "value", string(rawValue[1:]))
 					}
 				}
 			} else {
-				fmt.Printf("Error unpacking Name value for entity %s: %v\n", entity.Id, err)
+				slog.ErrorContext(ctx, "Error unpacking Name value", "entity_id", entity.Id, "error", err)
 				return false, fmt.Errorf("[neo4j_handler.HandleGraphEntityCreation] error unpacking Name value: %v", err)
 			}
 		} else {
 			// Successfully unpacked to StringValue
 			entityMap["Name"] = stringValue.Value
-			log.Printf("Using unpacked StringValue: %s\n", stringValue.Value)
+			slog.DebugContext(ctx, "Using unpacked StringValue", "value", stringValue.Value)
 		}
 	}
 
@@ -266,10 +358,10 @@ func (repo *Neo4jRepository) HandleGraphEntityCreation(ctx context.Context, enti
 	// Create the entity
 	result, err := repo.CreateGraphEntity(ctx, kind, entityMap)
 	if err != nil {
-		log.Printf("[neo4j_handler.HandleGraphEntityCreation] Error creating entity in Neo4j: %v", err)
+		slog.ErrorContext(ctx, "Error creating entity in Neo4j", "error", err)
 		return false, err
 	} else {
-		log.Printf("[neo4j_handler.HandleGraphEntityCreation] Successfully created entity in Neo4j: %s", entity.Id)
+		slog.DebugContext(ctx, "Successfully created entity in Neo4j", "entity_id", entity.Id)
 		return result != nil, nil // Success if we got a non-nil result
 	}
 }
@@ -278,17 +370,17 @@ func (repo *Neo4jRepository) HandleGraphEntityCreation(ctx context.Context, enti
 func (repo *Neo4jRepository) HandleGraphEntityUpdate(ctx context.Context, entity *pb.Entity) (bool, error) {
 	// Validate required fields for Neo4j entity update
 	if entity.Id == "" {
-		log.Printf("[neo4j_handler.HandleGraphEntityUpdate] Entity ID is required for Neo4j entity update")
+		slog.DebugContext(ctx, "Entity ID is required for Neo4j entity update")
 		return false, fmt.Errorf("[neo4j_handler.HandleGraphEntityUpdate] entity ID is required")
 	}
 
 	// Check if user is trying to update Kind (not allowed)
 	if entity.Kind != nil && (entity.Kind.Major != "" || entity.Kind.Minor != "") {
-		log.Printf("[neo4j_handler.HandleGraphEntityUpdate] Cannot update Kind for entity %s", entity.Id)
+		slog.DebugContext(ctx, "Cannot update Kind", "entity_id", entity.Id)
 		return false, fmt.Errorf("[neo4j_handler.HandleGraphEntityUpdate] Kind cannot be updated")
 	}
 
-	log.Printf("[neo4j_handler.HandleGraphEntityUpdate] Updating existing entity in Neo4j: %s", entity.Id)
+	slog.DebugContext(ctx, "Updating existing entity in Neo4j", "entity_id", entity.Id)
 
 	// Prepare data for Neo4j with safety checks
 	entityMap := map[string]interface{}{
@@ -301,7 +393,7 @@ func (repo *Neo4jRepository) HandleGraphEntityUpdate(ctx context.Context, entity
 		var stringValue wrapperspb.StringValue
 		err := entity.Name.GetValue().UnmarshalTo(&stringValue)
 		if err != nil {
-			log.Printf("[neo4j_handler.HandleGraphEntityUpdate] Error unpacking Name value for entity %s: %v", entity.Id, err)
+			slog.ErrorContext(ctx, "Error unpacking Name value", "entity_id", entity.Id, "error", err)
 			return false, fmt.Errorf("[neo4j_handler.HandleGraphEntityUpdate] error unpacking Name value: %v", err)
 		}
 		// Get the actual string value from the StringValue and check it's not empty
@@ -321,13 +413,13 @@ func (repo *Neo4jRepository) HandleGraphEntityUpdate(ctx context.Context, entity
 
 	// Update the entity
 	result, err := repo.UpdateGraphEntity(ctx, entity.Id, entityMap)
-	log.Printf("[neo4j_handler.HandleGraphEntityUpdate] Entity map for update: %+v", entityMap)
+	slog.DebugContext(ctx, "Entity map for update", "entity_map", logging.Payload(entityMap))
 	if err != nil {
-		log.Printf("[neo4j_handler.HandleGraphEntityUpdate] Error updating entity in Neo4j: %v", err)
+		slog.ErrorContext(ctx, "Error updating entity in Neo4j", "error", err)
 		return false, err
 	} else {
-		log.Printf("[neo4j_handler.HandleGraphEntityUpdate] Successfully updated entity in Neo4j: %s", entity.Id)
-		log.Printf("[neo4j_handler.HandleGraphEntityUpdate] Update result: %+v", result)
+		slog.DebugContext(ctx, "Successfully updated entity in Neo4j", "entity_id", entity.Id)
+		slog.DebugContext(ctx, "Update result", "result", logging.Payload(result))
 		return result != nil, nil // Success if we got a non-nil result
 	}
 }
@@ -335,58 +427,58 @@ func (repo *Neo4jRepository) HandleGraphEntityUpdate(ctx context.Context, entity
 // HandleGraphRelationshipsCreate handles creating new relationships
 func (repo *Neo4jRepository) HandleGraphRelationshipsCreate(ctx context.Context, entity *pb.Entity) error {
 	if len(entity.Relationships) == 0 {
-		log.Printf("[neo4j_handler.HandleGraphRelationshipsCreate] No relationships to process for entity: %s", entity.Id)
+		slog.DebugContext(ctx, "No relationships to process for entity", "entity_id", entity.Id)
 		return nil
 	}
 
-	log.Printf("[neo4j_handler.HandleGraphRelationshipsCreate] Processing %d relationships for entity: %s", len(entity.Relationships), entity.Id)
+	slog.DebugContext(ctx, "Processing relationships for entity", "relationships_count", len(entity.Relationships), "entity_id", entity.Id)
 
 	// First verify the parent entity exists
 	parentEntity, err := repo.ReadGraphEntity(ctx, entity.Id)
 	if err != nil || parentEntity == nil {
-		log.Printf("[neo4j_handler.HandleGraphRelationshipsCreate] Parent entity %s does not exist in Neo4j", entity.Id)
+		slog.DebugContext(ctx, "Parent entity does not exist in Neo4j", "entity_id", entity.Id)
 		return fmt.Errorf("[neo4j_handler.HandleGraphRelationshipsCreate] parent entity %s does not exist", entity.Id)
 	}
 
 	// Process all child entities
 	for _, relationship := range entity.Relationships {
 		if relationship == nil || relationship.Id == "" {
-			log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] Relationship missing ID field")
+			slog.DebugContext(ctx, "Relationship missing ID field")
 			return fmt.Errorf("relationship missing ID field")
 		}
 
 		// Validate required fields for creation
 		if relationship.RelatedEntityId == "" {
-			log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] Missing RelatedEntityId for relationship creation")
+			slog.DebugContext(ctx, "Missing RelatedEntityId for relationship creation")
 			return fmt.Errorf("missing RelatedEntityId for relationship %s. Required for creation", relationship.Id)
 		}
 		if relationship.Name == "" {
-			log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] Missing Name for relationship creation")
+			slog.DebugContext(ctx, "Missing Name for relationship creation")
 			return fmt.Errorf("missing Name for relationship %s. Required for creation", relationship.Id)
 		}
+		if _, err := cypher.RelationshipType(relationship.Name); err != nil {
+			return err
+		}
 		if relationship.StartTime == "" {
-			log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] Missing StartTime for relationship creation")
+			slog.DebugContext(ctx, "Missing StartTime for relationship creation")
 			return fmt.Errorf("missing StartTime for relationship %s. Required for creation", relationship.Id)
 		}
 
 		// Check if the child entity exists
 		childEntityMap, err := repo.ReadGraphEntity(ctx, relationship.RelatedEntityId)
 		if err != nil || childEntityMap == nil {
-			log.Printf("[neo4j_handler.HandleGraphRelationshipsCreate] Child entity %s does not exist in Neo4j. Make sure to create it first.",
-				relationship.RelatedEntityId)
+			slog.DebugContext(ctx, "Child entity does not exist in Neo4j, create it first", "related_entity_id", relationship.RelatedEntityId)
 			return fmt.Errorf("[neo4j_handler.HandleGraphRelationshipsCreate] child entity %s does not exist", relationship.RelatedEntityId)
 		}
-		log.Printf("[neo4j_handler.HandleGraphRelationshipsCreate] Child entity %s exists in Neo4j", relationship.RelatedEntityId)
+		slog.DebugContext(ctx, "Child entity exists in Neo4j", "related_entity_id", relationship.RelatedEntityId)
 
 		// Create the relationship
 		_, err = repo.CreateRelationship(ctx, entity.Id, relationship)
 		if err != nil {
-			log.Printf("[neo4j_handler.HandleGraphRelationshipsCreate] Error creating relationship from %s to %s: %v",
-				entity.Id, relationship.RelatedEntityId, err)
+			slog.ErrorContext(ctx, "Error creating relationship", "entity_id", entity.Id, "related_entity_id", relationship.RelatedEntityId, "error", err)
 			return fmt.Errorf("[neo4j_handler.HandleGraphRelationshipsCreate] error creating relationship: %v", err)
 		}
-		log.Printf("[neo4j_handler.HandleGraphRelationshipsCreate] Successfully created relationship from %s to %s",
-			entity.Id, relationship.RelatedEntityId)
+		slog.DebugContext(ctx, "Successfully created relationship", "entity_id", entity.Id, "related_entity_id", relationship.RelatedEntityId)
 	}
 
 	return nil
@@ -394,25 +486,25 @@ func (repo *Neo4jRepository) HandleGraphRelationshipsCreate(ctx context.Context,
 
 // HandleGraphRelationshipsUpdate handles updating existing relationships
 func (repo *Neo4jRepository) HandleGraphRelationshipsUpdate(ctx context.Context, entity *pb.Entity) error {
-	log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] Received entity: %+v", entity)
+	slog.DebugContext(ctx, "Received entity", "entity", logging.Payload(entity))
 
 	if len(entity.Relationships) == 0 {
-		log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] No relationships to process for entity: %s", entity.Id)
+		slog.DebugContext(ctx, "No relationships to process for entity", "entity_id", entity.Id)
 		return nil
 	}
 
-	log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] Processing %d relationships for entity: %s", len(entity.Relationships), entity.Id)
+	slog.DebugContext(ctx, "Processing relationships for entity", "relationships_count", len(entity.Relationships), "entity_id", entity.Id)
 
 	// First verify the parent entity exists
 	parentEntity, err := repo.ReadGraphEntity(ctx, entity.Id)
 	if err != nil || parentEntity == nil {
-		log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] Parent entity %s does not exist in Neo4j", entity.Id)
+		slog.DebugContext(ctx, "Parent entity does not exist in Neo4j", "entity_id", entity.Id)
 		return fmt.Errorf("[neo4j_handler.HandleGraphRelationshipsUpdate] parent entity %s does not exist", entity.Id)
 	}
 
 	for _, relationship := range entity.Relationships {
 		if relationship == nil || relationship.Id == "" {
-			log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] Relationship missing ID field")
+			slog.DebugContext(ctx, "Relationship missing ID field")
 			return fmt.Errorf("relationship missing ID field")
 		}
 
@@ -422,7 +514,7 @@ func (repo *Neo4jRepository) HandleGraphRelationshipsUpdate(ctx context.Context,
 
 		if relationshipExists {
 			// RELATIONSHIP EXISTS - UPDATE IT
-			log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] Relationship %s exists, updating...", relationship.Id)
+			slog.DebugContext(ctx, "Relationship exists, updating", "relationship_id", relationship.Id)
 
 			// Validate: only StartTime and EndTime are allowed for updates
 			if relationship.Name != "" || relationship.RelatedEntityId != "" || relationship.Direction != "" {
@@ -436,7 +528,7 @@ func (repo *Neo4jRepository) HandleGraphRelationshipsUpdate(ctx context.Context,
 				if relationship.Direction != "" {
 					invalidFields = append(invalidFields, "Direction")
 				}
-				log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] Cannot update immutable fields: %v", invalidFields)
+				slog.DebugContext(ctx, "Cannot update immutable fields", "invalid_fields", invalidFields)
 				return fmt.Errorf("cannot update immutable fields: %v. Only StartTime and EndTime are allowed", invalidFields)
 			}
 
@@ -451,56 +543,58 @@ func (repo *Neo4jRepository) HandleGraphRelationshipsUpdate(ctx context.Context,
 
 			// Check if we have any valid fields to update
 			if len(relationshipData) == 0 {
-				log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] No valid fields provided for update")
+				slog.DebugContext(ctx, "No valid fields provided for update")
 				return fmt.Errorf("no valid fields provided for relationship update. Only StartTime and EndTime are allowed")
 			}
 
-			log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] Updating relationship with data: %+v", relationshipData)
+			slog.DebugContext(ctx, "Updating relationship with data", "relationship_data", logging.Payload(relationshipData))
 
 			// Update the relationship
 			_, err = repo.UpdateRelationship(ctx, relationship.Id, relationshipData)
 			if err != nil {
-				log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] Failed to update relationship: %v", err)
+				slog.ErrorContext(ctx, "Failed to update relationship", "error", err)
 				return err
 			}
 
-			log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] Successfully updated relationship %s", relationship.Id)
+			slog.DebugContext(ctx, "Successfully updated relationship", "relationship_id", relationship.Id)
 			continue
 
 		} else {
 			// RELATIONSHIP DOESN'T EXIST - CREATE IT
-			log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] Relationship %s doesn't exist, creating...", relationship.Id)
+			slog.DebugContext(ctx, "Relationship doesn't exist, creating", "relationship_id", relationship.Id)
 
 			// Validate required fields for creation
 			if relationship.RelatedEntityId == "" {
-				log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] Missing RelatedEntityId for relationship creation")
+				slog.DebugContext(ctx, "Missing RelatedEntityId for relationship creation")
 				return fmt.Errorf("missing RelatedEntityId for relationship %s. Required for creation", relationship.Id)
 			}
 			if relationship.Name == "" {
-				log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] Missing Name for relationship creation")
+				slog.DebugContext(ctx, "Missing Name for relationship creation")
 				return fmt.Errorf("missing Name for relationship %s. Required for creation", relationship.Id)
 			}
+			if _, err := cypher.RelationshipType(relationship.Name); err != nil {
+				return err
+			}
 			if relationship.StartTime == "" {
-				log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] Missing StartTime for relationship creation")
+				slog.DebugContext(ctx, "Missing StartTime for relationship creation")
 				return fmt.Errorf("missing StartTime for relationship %s. Required for creation", relationship.Id)
 			}
 
 			// Check if the child entity exists
 			childEntityMap, err := repo.ReadGraphEntity(ctx, relationship.RelatedEntityId)
 			if err != nil || childEntityMap == nil {
-				log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] Child entity %s does not exist in Neo4j",
-					relationship.RelatedEntityId)
+				slog.DebugContext(ctx, "Child entity does not exist in Neo4j", "related_entity_id", relationship.RelatedEntityId)
 				return fmt.Errorf("[neo4j_handler.HandleGraphRelationshipsUpdate] child entity %s does not exist", relationship.RelatedEntityId)
 			}
 
 			// Create the relationship
 			_, err = repo.CreateRelationship(ctx, entity.Id, relationship)
 			if err != nil {
-				log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] Failed to create relationship: %v", err)
+				slog.ErrorContext(ctx, "Failed to create relationship", "error", err)
 				return fmt.Errorf("[neo4j_handler.HandleGraphRelationshipsUpdate] failed to create relationship: %v", err)
 			}
 
-			log.Printf("[neo4j_handler.HandleGraphRelationshipsUpdate] Successfully created relationship %s", relationship.Id)
+			slog.DebugContext(ctx, "Successfully created relationship", "relationship_id", relationship.Id)
 			continue
 		}
 	}
@@ -4,8 +4,13 @@ import (
 	"context"
 	"fmt"
 	"lk/datafoundation/core-api/db/config"
+	"lk/datafoundation/core-api/db/repository/neo4j/cypher"
 	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
-	"log"
+	"lk/datafoundation/core-api/pkg/logging"
+	"lk/datafoundation/core-api/pkg/metrics"
+	"lk/datafoundation/core-api/pkg/tracing"
+	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
@@ -18,20 +23,33 @@ type Neo4jRepository struct {
 
 // NewNeo4jRepository initializes a Neo4j driver
 func NewNeo4jRepository(ctx context.Context, config *config.Neo4jConfig) (*Neo4jRepository, error) {
-	client, err := neo4j.NewDriverWithContext(config.URI, neo4j.BasicAuth(config.Username, config.Password, ""))
+	client, err := neo4j.NewDriverWithContext(config.URI, neo4j.BasicAuth(config.Username, config.Password, ""), func(c *neo4j.Config) {
+		if config.MaxConnectionPoolSize > 0 {
+			c.MaxConnectionPoolSize = config.MaxConnectionPoolSize
+		}
+		if config.ConnectionAcquisitionTimeout > 0 {
+			c.ConnectionAcquisitionTimeout = config.ConnectionAcquisitionTimeout
+		}
+	})
 	if err != nil {
-		log.Printf("[neo4j_client.NewNeo4jRepository] failed to create Neo4j driver: %v", err)
+		slog.ErrorContext(ctx, "Failed to create Neo4j driver", "error", err)
 		return nil, fmt.Errorf("failed to create Neo4j driver: %w", err)
 	}
 
 	// Verify connectivity
 	if err := client.VerifyConnectivity(ctx); err != nil {
 		client.Close(ctx) // Close if connectivity check fails
-		log.Printf("[neo4j_client.NewNeo4jRepository] failed to connect to Neo4j: %v", err)
+		slog.ErrorContext(ctx, "Failed to connect to Neo4j", "error", err)
 		return nil, fmt.Errorf("failed to connect to Neo4j: %w", err)
 	}
 
-	log.Println("[neo4j_client.NewNeo4jRepository] Connected to Neo4j successfully!")
+	slog.DebugContext(ctx, "Connected to Neo4j successfully")
+
+	if err := backfillTenant(ctx, client); err != nil {
+		client.Close(ctx)
+		slog.ErrorContext(ctx, "Failed to assign graph records to the default tenant", "error", err)
+		return nil, fmt.Errorf("failed to assign graph records to the default tenant: %w", err)
+	}
 
 	return &Neo4jRepository{
 		client: client,
@@ -43,50 +61,58 @@ func NewNeo4jRepository(ctx context.Context, config *config.Neo4jConfig) (*Neo4j
 func (r *Neo4jRepository) Close(ctx context.Context) {
 	if r.client != nil {
 		r.client.Close(ctx)
-		log.Println("Neo4j connection closed")
+		slog.DebugContext(ctx, "Neo4j connection closed")
 	}
 }
 
-// getSession creates a new session
+// Ping checks that the database can be reached
+func (r *Neo4jRepository) Ping(ctx context.Context) error {
+	return r.client.VerifyConnectivity(ctx)
+}
+
+// getSession creates a new session whose queries are bound to the tenant of the call
 func (r *Neo4jRepository) getSession(ctx context.Context) neo4j.SessionWithContext {
-	return r.client.NewSession(ctx, neo4j.SessionConfig{
+	return tenantSession{r.client.NewSession(ctx, neo4j.SessionConfig{
 		AccessMode: neo4j.AccessModeWrite,
-	})
+	})}
 }
 
 // CreateGraphEntity checks if an entity exists and creates it if it doesn't
-func (r *Neo4jRepository) CreateGraphEntity(ctx context.Context, kind *pb.Kind, entityMap map[string]interface{}) (map[string]interface{}, error) {
+func (r *Neo4jRepository) CreateGraphEntity(ctx context.Context, kind *pb.Kind, entityMap map[string]interface{}) (_ map[string]interface{}, err error) {
+	defer metrics.ObserveDatabase(metrics.Neo4j, "CreateGraphEntity", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Neo4j, "CreateGraphEntity")
+	defer tracing.End(span, &err)
 	// Validate the kind parameter
 	if kind == nil || kind.Major == "" {
-		log.Printf("[neo4j_client.CreateGraphEntity] missing or invalid 'Kind.Major' field")
+		slog.ErrorContext(ctx, "Missing or invalid 'Kind.Major' field")
 		return nil, fmt.Errorf("[neo4j_client.CreateGraphEntity] missing or invalid 'Kind.Major' field")
 	} else {
-		log.Printf("[neo4j_client.CreateGraphEntity] Kind.Major: %v", kind.Major)
+		slog.DebugContext(ctx, "Entity kind", "kind_major", kind.Major)
 	}
 
 	// Extract the required fields from the entityMap
 	id, ok := entityMap["Id"].(string)
 	if !ok {
-		log.Printf("[neo4j_client.CreateGraphEntity] missing or invalid 'Id' field")
+		slog.ErrorContext(ctx, "Missing or invalid 'Id' field")
 		return nil, fmt.Errorf("[neo4j_client.CreateGraphEntity] missing or invalid 'Id' field")
 	} else {
-		log.Printf("[neo4j_client.CreateGraphEntity] Id: %v", id)
+		slog.DebugContext(ctx, "Entity id", "entity_id", id)
 	}
 
 	name, ok := entityMap["Name"].(string)
 	if !ok {
-		log.Printf("[neo4j_client.CreateGraphEntity] missing or invalid 'Name' field")
+		slog.ErrorContext(ctx, "Missing or invalid 'Name' field")
 		return nil, fmt.Errorf("[neo4j_client.CreateGraphEntity] missing or invalid 'Name' field")
 	} else {
-		log.Printf("[neo4j_client.CreateGraphEntity] Name: %v", name)
+		slog.DebugContext(ctx, "Entity name", "name", name)
 	}
 
 	created, ok := entityMap["Created"].(string)
 	if !ok {
-		log.Printf("[neo4j_client.CreateGraphEntity] missing or invalid 'Created' field")
+		slog.ErrorContext(ctx, "Missing or invalid 'Created' field")
 		return nil, fmt.Errorf("[neo4j_client.CreateGraphEntity] missing or invalid 'Created' field")
 	} else {
-		log.Printf("[neo4j_client.CreateGraphEntity] Created: %v", created)
+		slog.DebugContext(ctx, "Entity created", "created", created)
 	}
 
 	// Optional field
@@ -94,7 +120,17 @@ func (r *Neo4jRepository) CreateGraphEntity(ctx context.Context, kind *pb.Kind,
 	if term, ok := entityMap["Terminated"].(string); ok {
 		terminated = &term
 	} else {
-		log.Printf("[neo4j_client.CreateGraphEntity] Terminated: %v", terminated)
+		slog.DebugContext(ctx, "Entity terminated", "terminated", terminated)
+	}
+
+	// The kind becomes the label, which cannot be a parameter
+	existsQuery, err := cypher.EntityExists(kind.Major)
+	if err != nil {
+		return nil, err
+	}
+	createQuery, err := cypher.CreateEntity(kind.Major, terminated != nil)
+	if err != nil {
+		return nil, err
 	}
 
 	// Open a session
@@ -102,29 +138,21 @@ func (r *Neo4jRepository) CreateGraphEntity(ctx context.Context, kind *pb.Kind,
 	defer session.Close(ctx)
 
 	// Check if the node already exists
-	existsQuery := `MATCH (e:` + kind.Major + ` {Id: $Id}) RETURN e`
 	result, err := session.Run(ctx, existsQuery, map[string]interface{}{"Id": id})
 	if err != nil {
-		log.Printf("[neo4j_client.CreateGraphEntity] error checking if entity exists: %v", err)
+		slog.ErrorContext(ctx, "Error checking if entity exists", "error", err)
 		return nil, fmt.Errorf("[neo4j_client.CreateGraphEntity] error checking if entity exists: %v", err)
 	} else {
-		log.Printf("[neo4j_client.CreateGraphEntity] existsQuery: %v", existsQuery)
+		slog.DebugContext(ctx, "Exists query", "exists_query", existsQuery)
 	}
 
 	// If entity exists, return an error
 	if result.Next(ctx) {
-		log.Printf("[neo4j_client.CreateGraphEntity] entity with Id %s already exists", id)
+		slog.DebugContext(ctx, "Entity already exists", "entity_id", id)
 		return nil, fmt.Errorf("[neo4j_client.CreateGraphEntity] entity with Id %s already exists", id)
 	} else {
-		log.Printf("[neo4j_client.CreateGraphEntity] entity with Id %s does not exist", id)
-	}
-
-	// Create the node
-	createQuery := `CREATE (e:` + kind.Major + ` {Id: $Id, Name: $Name, Created: datetime($Created), MinorKind: $MinorKind`
-	if terminated != nil {
-		createQuery += `, Terminated: datetime($Terminated)`
+		slog.DebugContext(ctx, "Entity does not exist", "entity_id", id)
 	}
-	createQuery += `}) RETURN e`
 
 	// Set parameters for the query
 	params := map[string]interface{}{
@@ -140,10 +168,10 @@ func (r *Neo4jRepository) CreateGraphEntity(ctx context.Context, kind *pb.Kind,
 	// Run the query to create the entity and return it
 	result, err = session.Run(ctx, createQuery, params)
 	if err != nil {
-		log.Printf("[neo4j_client.CreateGraphEntity] error creating entity: %v", err)
+		slog.ErrorContext(ctx, "Error creating entity", "error", err)
 		return nil, fmt.Errorf("[neo4j_client.CreateGraphEntity] error creating entity: %v", err)
 	} else {
-		log.Printf("[neo4j_client.CreateGraphEntity] created entity(run query): %v", params)
+		slog.DebugContext(ctx, "Created entity (run query)", "params", params)
 	}
 
 	// Retrieve the created entity
@@ -151,10 +179,10 @@ func (r *Neo4jRepository) CreateGraphEntity(ctx context.Context, kind *pb.Kind,
 		createdEntity, _ := result.Record().Get("e")
 		node, ok := createdEntity.(neo4j.Node)
 		if !ok {
-			log.Printf("[neo4j_client.CreateGraphEntity] failed to cast created entity to neo4j.Node")
+			slog.ErrorContext(ctx, "Failed to cast created entity to neo4j.Node")
 			return nil, fmt.Errorf("[neo4j_client.CreateGraphEntity] failed to cast created entity to neo4j.Node")
 		} else {
-			log.Printf("[neo4j_client.CreateGraphEntity] created entity(retrieved-initial): %v", createdEntity)
+			slog.DebugContext(ctx, "Created entity (retrieved initial)", "created_entity", logging.Payload(createdEntity))
 		}
 
 		// Convert the node properties to a map
@@ -178,51 +206,60 @@ func (r *Neo4jRepository) CreateGraphEntity(ctx context.Context, kind *pb.Kind,
 				createdEntityMap["Terminated"] = fmt.Sprintf("%v", *terminated)
 			}
 		} else {
-			log.Printf("[neo4j_client.CreateGraphEntity] Terminated: %v", terminated)
+			slog.DebugContext(ctx, "Entity terminated", "terminated", terminated)
 		}
-		log.Printf("[neo4j_client.CreateGraphEntity] created entity(retrieved-final): %v", createdEntityMap)
+		slog.DebugContext(ctx, "Created entity (retrieved final)", "created_entity", logging.Payload(createdEntityMap))
 		return createdEntityMap, nil
 	}
 
-	log.Printf("[neo4j_client.CreateGraphEntity] failed to create entity")
+	slog.ErrorContext(ctx, "Failed to create entity")
 	return nil, fmt.Errorf("[neo4j_client.CreateGraphEntity] failed to create entity")
 }
 
 // CreateRelationship creates a relationship between two entities
-func (r *Neo4jRepository) CreateRelationship(ctx context.Context, entityID string, rel *pb.Relationship) (map[string]interface{}, error) {
+func (r *Neo4jRepository) CreateRelationship(ctx context.Context, entityID string, rel *pb.Relationship) (_ map[string]interface{}, err error) {
+	defer metrics.ObserveDatabase(metrics.Neo4j, "CreateRelationship", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Neo4j, "CreateRelationship")
+	defer tracing.End(span, &err)
+	// The relationship name becomes the relationship type, which cannot be a parameter
+	createQuery, err := cypher.CreateRelationship(rel.Name, rel.EndTime != "")
+	if err != nil {
+		return nil, err
+	}
+
 	session := r.getSession(ctx)
 	defer session.Close(ctx)
 
 	// Check if relationship with this ID already exists
-	relExistsQuery := `MATCH ()-[r {Id: $relationshipID}]->() RETURN r`
+	relExistsQuery := `MATCH ()-[r {Id: $relationshipID, Tenant: $tenant}]->() RETURN r`
 	relResult, err := session.Run(ctx, relExistsQuery, map[string]interface{}{
 		"relationshipID": rel.Id,
 	})
 	if err != nil {
-		log.Printf("[neo4j_client.CreateRelationship] error checking if relationship exists: %v", err)
+		slog.ErrorContext(ctx, "Error checking if relationship exists", "error", err)
 		return nil, fmt.Errorf("error checking if relationship exists: %v", err)
 	}
 	if relResult.Next(ctx) {
-		log.Printf("[neo4j_client.CreateRelationship] relationship with Id %s already exists", rel.Id)
+		slog.DebugContext(ctx, "Relationship already exists", "relationship_id", rel.Id)
 		return nil, fmt.Errorf("relationship with Id %s already exists", rel.Id)
 	}
 
-	existsQuery := `MATCH (p {Id: $parentID}), (c {Id: $childID}) RETURN p, c`
+	existsQuery := `MATCH (p {Id: $parentID, Tenant: $tenant}), (c {Id: $childID, Tenant: $tenant}) RETURN p, c`
 	result, err := session.Run(ctx, existsQuery, map[string]interface{}{
 		"parentID": entityID,
 		"childID":  rel.RelatedEntityId,
 	})
 	if err != nil {
-		log.Printf("[neo4j_client.CreateRelationship] error checking entities: %v", err)
+		slog.ErrorContext(ctx, "Error checking entities", "error", err)
 		return nil, fmt.Errorf("error checking entities: %v", err)
 	} else {
-		log.Printf("[neo4j_client.CreateRelationship] existsQuery: %v", existsQuery)
+		slog.DebugContext(ctx, "Exists query", "exists_query", existsQuery)
 	}
 	if !result.Next(ctx) {
-		log.Printf("[neo4j_client.CreateRelationship] either parent or child entity does not exist")
+		slog.DebugContext(ctx, "Either parent or child entity does not exist")
 		return nil, fmt.Errorf("either parent or child entity does not exist")
 	} else {
-		log.Printf("[neo4j_client.CreateRelationship] either parent or child entity exist")
+		slog.DebugContext(ctx, "Both parent and child entities exist")
 	}
 
 	params := map[string]interface{}{
@@ -232,33 +269,27 @@ func (r *Neo4jRepository) CreateRelationship(ctx context.Context, entityID strin
 		"startDate":      rel.StartTime,
 	}
 
-	createQuery := `MATCH (p {Id: $parentID}), (c {Id: $childID})
-					CREATE (p)-[r:` + rel.Name + ` {Id: $relationshipID, Created: datetime($startDate)`
-
 	if rel.EndTime != "" {
-		createQuery += `, Terminated: datetime($endDate)`
 		params["endDate"] = rel.EndTime
 	}
 
-	createQuery += `}]->(c) RETURN r`
-
 	result, err = session.Run(ctx, createQuery, params)
 	if err != nil {
-		log.Printf("[neo4j_client.CreateRelationship] error creating relationship: %v", err)
+		slog.ErrorContext(ctx, "Error creating relationship", "error", err)
 		return nil, fmt.Errorf("error creating relationship: %v", err)
 	} else {
-		log.Printf("[neo4j_client.CreateRelationship] createQuery: %v", createQuery)
-		log.Printf("[neo4j_client.CreateRelationship] params: %v", params)
+		slog.DebugContext(ctx, "Create query", "create_query", createQuery)
+		slog.DebugContext(ctx, "Query params", "params", params)
 	}
 
 	if result.Next(ctx) {
 		createdRel, _ := result.Record().Get("r")
 		relationship, ok := createdRel.(neo4j.Relationship)
 		if !ok {
-			log.Printf("[neo4j_client.CreateRelationship] failed to cast created relationship to neo4j.Relationship")
+			slog.ErrorContext(ctx, "Failed to cast created relationship to neo4j.Relationship")
 			return nil, fmt.Errorf("failed to cast created relationship to neo4j.Relationship")
 		} else {
-			log.Printf("[neo4j_client.CreateRelationship] created relationship: %v", createdRel)
+			slog.DebugContext(ctx, "Created relationship", "relationship", logging.Payload(createdRel))
 		}
 
 		relationshipMap := map[string]interface{}{
@@ -281,17 +312,20 @@ func (r *Neo4jRepository) CreateRelationship(ctx context.Context, entityID strin
 			}
 		}
 
-		log.Printf("[neo4j_client.CreateRelationship] created relationship: %v", relationshipMap)
+		slog.DebugContext(ctx, "Created relationship", "relationship", logging.Payload(relationshipMap))
 		return relationshipMap, nil
 	} else {
-		log.Printf("[neo4j_client.CreateRelationship] failed to retrieve created relationship: %v", result)
+		slog.ErrorContext(ctx, "Failed to retrieve created relationship", "result", logging.Payload(result))
 	}
 
 	return nil, fmt.Errorf("failed to retrieve created relationship")
 }
 
 // ReadGraphEntity retrieves an entity by its ID from the Neo4j database and returns it as a map.
-func (r *Neo4jRepository) ReadGraphEntity(ctx context.Context, entityID string) (map[string]interface{}, error) {
+func (r *Neo4jRepository) ReadGraphEntity(ctx context.Context, entityID string) (_ map[string]interface{}, err error) {
+	defer metrics.ObserveDatabase(metrics.Neo4j, "ReadGraphEntity", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Neo4j, "ReadGraphEntity")
+	defer tracing.End(span, &err)
 	if entityID == "" {
 		return nil, fmt.Errorf("entity Id cannot be empty")
 	}
@@ -302,7 +336,7 @@ func (r *Neo4jRepository) ReadGraphEntity(ctx context.Context, entityID string)
 
 	// Cypher query to retrieve the entity with both Major and Minor kinds
 	query := `
-        MATCH (e {Id: $Id})
+        MATCH (e {Id: $Id, Tenant: $tenant})
         RETURN labels(e)[0] AS MajorKind, e.MinorKind AS MinorKind, e.Id AS Id, e.Name AS Name, 
                toString(e.Created) AS Created, 
                CASE WHEN e.Terminated IS NOT NULL THEN toString(e.Terminated) ELSE NULL END AS Terminated
@@ -311,7 +345,7 @@ func (r *Neo4jRepository) ReadGraphEntity(ctx context.Context, entityID string)
 	// Run the query
 	result, err := session.Run(ctx, query, map[string]interface{}{"Id": entityID})
 	if err != nil {
-		log.Printf("[neo4j_client.ReadGraphEntity] error querying entity: %v", err)
+		slog.ErrorContext(ctx, "Error querying entity", "error", err)
 		return nil, fmt.Errorf("error querying entity: %v", err)
 	}
 
@@ -341,26 +375,28 @@ func (r *Neo4jRepository) ReadGraphEntity(ctx context.Context, entityID string)
 }
 
 // ReadRelatedGraphEntityIds retrieves related relationships based on a given relationship type and timestamp
-func (r *Neo4jRepository) ReadRelatedGraphEntityIds(ctx context.Context, entityID string, relationship string, ts string) ([]map[string]interface{}, error) {
+func (r *Neo4jRepository) ReadRelatedGraphEntityIds(ctx context.Context, entityID string, relationship string, ts string) (_ []map[string]interface{}, err error) {
+	defer metrics.ObserveDatabase(metrics.Neo4j, "ReadRelatedGraphEntityIds", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Neo4j, "ReadRelatedGraphEntityIds")
+	defer tracing.End(span, &err)
 	if entityID == "" {
 		return nil, fmt.Errorf("entity Id cannot be empty")
 	}
 
+	query, err := cypher.RelatedEntities(relationship)
+	if err != nil {
+		return nil, err
+	}
+
 	session := r.getSession(ctx)
 	defer session.Close(ctx)
 
-	query := fmt.Sprintf(`
-        MATCH (e {Id: $entityID})-[r:%s]->(related)
-        WHERE r.Created <= datetime($ts) AND (r.Terminated IS NULL OR r.Terminated > datetime($ts))
-        RETURN r.Id AS relationshipID, r.Created AS startTime, r.Terminated AS endTime, type(r) AS name, related.Id AS relatedEntityId
-    `, relationship)
-
 	result, err := session.Run(ctx, query, map[string]interface{}{
 		"entityID": entityID,
 		"ts":       ts,
 	})
 	if err != nil {
-		log.Printf("[neo4j_client.ReadRelatedGraphEntityIds] error querying related entities: %v", err)
+		slog.ErrorContext(ctx, "Error querying related entities", "error", err)
 		return nil, fmt.Errorf("error querying related entities: %v", err)
 	}
 
@@ -410,15 +446,17 @@ func (r *Neo4jRepository) ReadRelatedGraphEntityIds(ctx context.Context, entityI
 	}
 
 	if err := result.Err(); err != nil {
-		log.Printf("[neo4j_client.ReadRelatedGraphEntityIds] error iterating over query result: %v", err)
+		slog.ErrorContext(ctx, "Error iterating over query result", "error", err)
 		return nil, fmt.Errorf("error iterating over query result: %v", err)
 	}
 
 	return relationships, nil
 }
 
-func (r *Neo4jRepository) ReadRelationships(ctx context.Context, entityID string) ([]map[string]interface{}, error) {
-
+func (r *Neo4jRepository) ReadRelationships(ctx context.Context, entityID string) (_ []map[string]interface{}, err error) {
+	defer metrics.ObserveDatabase(metrics.Neo4j, "ReadRelationships", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Neo4j, "ReadRelationships")
+	defer tracing.End(span, &err)
 	if entityID == "" {
 		return nil, fmt.Errorf("entity Id cannot be empty")
 	}
@@ -429,13 +467,13 @@ func (r *Neo4jRepository) ReadRelationships(ctx context.Context, entityID string
 
 	// Cypher query to get all relationships (incoming and outgoing)
 	query := `
-        MATCH (e {Id: $entityID})-[r]->(related)
+        MATCH (e {Id: $entityID, Tenant: $tenant})-[r]->(related)
         RETURN type(r) AS type, related.Id AS relatedID, "OUTGOING" AS direction, 
                toString(r.Created) AS Created, 
                CASE WHEN r.Terminated IS NOT NULL THEN toString(r.Terminated) ELSE NULL END AS Terminated,
                r.Id AS relationshipID
         UNION
-        MATCH (e {Id: $entityID})<-[r]-(related)
+        MATCH (e {Id: $entityID, Tenant: $tenant})<-[r]-(related)
         RETURN type(r) AS type, related.Id AS relatedID, "INCOMING" AS direction, 
                toString(r.Created) AS Created, 
                CASE WHEN r.Terminated IS NOT NULL THEN toString(r.Terminated) ELSE NULL END AS Terminated,
@@ -447,7 +485,7 @@ func (r *Neo4jRepository) ReadRelationships(ctx context.Context, entityID string
 		"entityID": entityID,
 	})
 	if err != nil {
-		log.Printf("[neo4j_client.ReadRelationships] error querying relationships: %v", err)
+		slog.ErrorContext(ctx, "Error querying relationships", "error", err)
 		return nil, fmt.Errorf("error querying relationships: %v", err)
 	}
 
@@ -483,8 +521,10 @@ func (r *Neo4jRepository) ReadRelationships(ctx context.Context, entityID string
 	return relationships, nil
 }
 
-func (r *Neo4jRepository) ReadRelationship(ctx context.Context, relationshipID string) (map[string]interface{}, error) {
-
+func (r *Neo4jRepository) ReadRelationship(ctx context.Context, relationshipID string) (_ map[string]interface{}, err error) {
+	defer metrics.ObserveDatabase(metrics.Neo4j, "ReadRelationship", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Neo4j, "ReadRelationship")
+	defer tracing.End(span, &err)
 	if relationshipID == "" {
 		return nil, fmt.Errorf("relationship Id cannot be empty")
 	}
@@ -495,7 +535,7 @@ func (r *Neo4jRepository) ReadRelationship(ctx context.Context, relationshipID s
 	// Cypher query to find the relationship by its ID
 	query := `
         MATCH ()-[r]->()
-        WHERE r.Id = $relationshipID
+        WHERE r.Id = $relationshipID AND r.Tenant = $tenant
         RETURN type(r) AS type, startNode(r).Id AS startEntityID, endNode(r).Id AS endEntityID, 
                toString(r.Created) AS Created, 
                CASE WHEN r.Terminated IS NOT NULL THEN toString(r.Terminated) ELSE NULL END AS Terminated, 
@@ -507,7 +547,7 @@ func (r *Neo4jRepository) ReadRelationship(ctx context.Context, relationshipID s
 		"relationshipID": relationshipID,
 	})
 	if err != nil {
-		log.Printf("[neo4j_client.ReadRelationship] error querying relationship: %v", err)
+		slog.ErrorContext(ctx, "Error querying relationship", "error", err)
 		return nil, fmt.Errorf("error querying relationship: %v", err)
 	}
 
@@ -518,7 +558,7 @@ func (r *Neo4jRepository) ReadRelationship(ctx context.Context, relationshipID s
 
 		// Ensure expected values exist
 		if len(values) < 6 {
-			log.Printf("[neo4j_client.ReadRelationship] unexpected data format for relationship")
+			slog.DebugContext(ctx, "Unexpected data format for relationship")
 			return nil, fmt.Errorf("unexpected data format for relationship")
 		}
 
@@ -545,7 +585,10 @@ func (r *Neo4jRepository) ReadRelationship(ctx context.Context, relationshipID s
 }
 
 // UpdateGraphEntity updates the properties of an existing entity
-func (r *Neo4jRepository) UpdateGraphEntity(ctx context.Context, id string, updateData map[string]interface{}) (map[string]interface{}, error) {
+func (r *Neo4jRepository) UpdateGraphEntity(ctx context.Context, id string, updateData map[string]interface{}) (_ map[string]interface{}, err error) {
+	defer metrics.ObserveDatabase(metrics.Neo4j, "UpdateGraphEntity", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Neo4j, "UpdateGraphEntity")
+	defer tracing.End(span, &err)
 	if id == "" {
 		return nil, fmt.Errorf("entity Id cannot be empty")
 	}
@@ -560,21 +603,21 @@ func (r *Neo4jRepository) UpdateGraphEntity(ctx context.Context, id string, upda
 	defer session.Close(ctx)
 
 	// Check if the entity exists
-	existsQuery := `MATCH (e {Id: $Id}) RETURN e`
+	existsQuery := `MATCH (e {Id: $Id, Tenant: $tenant}) RETURN e`
 	result, err := session.Run(ctx, existsQuery, params)
 	if err != nil {
-		log.Printf("[neo4j_client.UpdateGraphEntity] error checking if entity exists: %v", err)
+		slog.ErrorContext(ctx, "Error checking if entity exists", "error", err)
 		return nil, fmt.Errorf("error checking if entity exists: %v", err)
 	}
 
 	if !result.Next(ctx) {
-		log.Printf("[neo4j_client.UpdateGraphEntity] entity with Id %s does not exist", id)
+		slog.DebugContext(ctx, "Entity does not exist", "entity_id", id)
 		return nil, fmt.Errorf("entity with Id %s does not exist", id)
 	}
 
 	// Build Cypher query for updating entity
 	query := `
-        MATCH (e {Id: $Id})
+        MATCH (e {Id: $Id, Tenant: $tenant})
     `
 
 	// Add `Name` if provided
@@ -594,7 +637,7 @@ func (r *Neo4jRepository) UpdateGraphEntity(ctx context.Context, id string, upda
 
 	result, err = session.Run(ctx, query, params)
 	if err != nil {
-		log.Printf("[neo4j_client.UpdateGraphEntity] error updating entity: %v", err)
+		slog.ErrorContext(ctx, "Error updating entity", "error", err)
 		return nil, fmt.Errorf("error updating entity: %v", err)
 	}
 
@@ -602,7 +645,7 @@ func (r *Neo4jRepository) UpdateGraphEntity(ctx context.Context, id string, upda
 	if result.Next(ctx) {
 		node, ok := result.Record().Get("e")
 		if !ok {
-			log.Printf("[neo4j_client.UpdateGraphEntity] unexpected error retrieving entity")
+			slog.ErrorContext(ctx, "Unexpected error retrieving entity")
 			return nil, fmt.Errorf("unexpected error retrieving entity")
 		}
 
@@ -627,11 +670,14 @@ func (r *Neo4jRepository) UpdateGraphEntity(ctx context.Context, id string, upda
 	return nil, fmt.Errorf("failed to retrieve updated entity")
 }
 
-func (r *Neo4jRepository) UpdateRelationship(ctx context.Context, relationshipID string, updateData map[string]interface{}) (map[string]interface{}, error) {
-	log.Printf("[neo4j_client.UpdateRelationship] Updating relationship %s with data: %+v", relationshipID, updateData)
+func (r *Neo4jRepository) UpdateRelationship(ctx context.Context, relationshipID string, updateData map[string]interface{}) (_ map[string]interface{}, err error) {
+	defer metrics.ObserveDatabase(metrics.Neo4j, "UpdateRelationship", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Neo4j, "UpdateRelationship")
+	defer tracing.End(span, &err)
+	slog.DebugContext(ctx, "Updating relationship with data", "relationship_id", relationshipID, "update_data", logging.Payload(updateData))
 
 	if relationshipID == "" {
-		log.Printf("[neo4j_client.UpdateRelationship] relationship Id cannot be empty")
+		slog.ErrorContext(ctx, "Relationship Id cannot be empty")
 		return nil, fmt.Errorf("relationship Id cannot be empty")
 	}
 
@@ -645,21 +691,21 @@ func (r *Neo4jRepository) UpdateRelationship(ctx context.Context, relationshipID
 	defer session.Close(ctx)
 
 	// Check if the relationship exists
-	existsQuery := `MATCH ()-[r {Id: $relationshipID}]->() RETURN r`
+	existsQuery := `MATCH ()-[r {Id: $relationshipID, Tenant: $tenant}]->() RETURN r`
 	result, err := session.Run(ctx, existsQuery, params)
 	if err != nil {
-		log.Printf("[neo4j_client.UpdateRelationship] error checking if relationship exists: %v", err)
+		slog.ErrorContext(ctx, "Error checking if relationship exists", "error", err)
 		return nil, fmt.Errorf("error checking if relationship exists: %v", err)
 	}
 
 	if !result.Next(ctx) {
-		log.Printf("[neo4j_client.UpdateRelationship] relationship with Id %s does not exist", relationshipID)
+		slog.DebugContext(ctx, "Relationship does not exist", "relationship_id", relationshipID)
 		return nil, fmt.Errorf("relationship with Id %s does not exist", relationshipID)
 	}
 
 	// Build Cypher query for updating relationship
 	query := `
-        MATCH ()-[r {Id: $relationshipID}]->()
+        MATCH ()-[r {Id: $relationshipID, Tenant: $tenant}]->()
     `
 
 	// Track if we have any fields to update
@@ -690,14 +736,14 @@ func (r *Neo4jRepository) UpdateRelationship(ctx context.Context, relationshipID
 	// Check for any unsupported fields
 	for key := range updateData {
 		if key != "Created" && key != "Terminated" {
-			log.Printf("[neo4j_client.UpdateRelationship] unsupported field '%s' provided for update", key)
+			slog.DebugContext(ctx, "Unsupported field provided for update", "field", key)
 			return nil, fmt.Errorf("unsupported field '%s' for relationship update. Only 'Created' and 'Terminated' are allowed", key)
 		}
 	}
 
 	// If no fields to update, return error
 	if !hasUpdates {
-		log.Printf("[neo4j_client.UpdateRelationship] no valid fields provided for update")
+		slog.DebugContext(ctx, "No valid fields provided for update")
 		return nil, fmt.Errorf("no valid fields provided for update")
 	}
 
@@ -706,7 +752,7 @@ func (r *Neo4jRepository) UpdateRelationship(ctx context.Context, relationshipID
 	// Execute update query and return updated relationship
 	result, err = session.Run(ctx, query, params)
 	if err != nil {
-		log.Printf("[neo4j_client.UpdateRelationship] error updating relationship: %v", err)
+		slog.ErrorContext(ctx, "Error updating relationship", "error", err)
 		return nil, fmt.Errorf("error updating relationship: %v", err)
 	}
 
@@ -714,7 +760,7 @@ func (r *Neo4jRepository) UpdateRelationship(ctx context.Context, relationshipID
 	if result.Next(ctx) {
 		rel, ok := result.Record().Get("r")
 		if !ok {
-			log.Printf("[neo4j_client.UpdateRelationship] unexpected error retrieving relationship")
+			slog.ErrorContext(ctx, "Unexpected error retrieving relationship")
 			return nil, fmt.Errorf("unexpected error retrieving relationship")
 		}
 
@@ -739,7 +785,10 @@ func (r *Neo4jRepository) UpdateRelationship(ctx context.Context, relationshipID
 	return nil, fmt.Errorf("failed to retrieve updated relationship")
 }
 
-func (r *Neo4jRepository) DeleteRelationship(ctx context.Context, relationshipID string) error {
+func (r *Neo4jRepository) DeleteRelationship(ctx context.Context, relationshipID string) (err error) {
+	defer metrics.ObserveDatabase(metrics.Neo4j, "DeleteRelationship", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Neo4j, "DeleteRelationship")
+	defer tracing.End(span, &err)
 	if relationshipID == "" {
 		return fmt.Errorf("entity Id cannot be empty")
 	}
@@ -754,24 +803,24 @@ func (r *Neo4jRepository) DeleteRelationship(ctx context.Context, relationshipID
 	defer session.Close(ctx)
 
 	// Check if the relationship exists
-	query := `MATCH ()-[r {Id: $relationshipID}]->() RETURN r`
+	query := `MATCH ()-[r {Id: $relationshipID, Tenant: $tenant}]->() RETURN r`
 	result, err := session.Run(ctx, query, params)
 	if err != nil {
-		log.Printf("[neo4j_client.DeleteRelationship] error checking if relationship exists: %v", err)
+		slog.ErrorContext(ctx, "Error checking if relationship exists", "error", err)
 		return fmt.Errorf("error checking if relationship exists: %v", err)
 	}
 
 	// If no relationship is found, return an error
 	if !result.Next(ctx) {
-		log.Printf("[neo4j_client.DeleteRelationship] relationship with Id %s does not exist", relationshipID)
+		slog.DebugContext(ctx, "Relationship does not exist", "relationship_id", relationshipID)
 		return fmt.Errorf("relationship with Id %s does not exist", relationshipID)
 	}
 
 	// Delete the relationship
-	deleteQuery := `MATCH ()-[r {Id: $relationshipID}]->() DELETE r`
+	deleteQuery := `MATCH ()-[r {Id: $relationshipID, Tenant: $tenant}]->() DELETE r`
 	_, err = session.Run(ctx, deleteQuery, params)
 	if err != nil {
-		log.Printf("[neo4j_client.DeleteRelationship] error deleting relationship: %v", err)
+		slog.ErrorContext(ctx, "Error deleting relationship", "error", err)
 		return fmt.Errorf("error deleting relationship: %v", err)
 	}
 
@@ -779,56 +828,62 @@ func (r *Neo4jRepository) DeleteRelationship(ctx context.Context, relationshipID
 }
 
 // DeleteGraphEntity deletes an entity by its ID
-func (r *Neo4jRepository) DeleteGraphEntity(ctx context.Context, entityID string) error {
+func (r *Neo4jRepository) DeleteGraphEntity(ctx context.Context, entityID string) (err error) {
+	defer metrics.ObserveDatabase(metrics.Neo4j, "DeleteGraphEntity", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Neo4j, "DeleteGraphEntity")
+	defer tracing.End(span, &err)
 	if entityID == "" {
-		log.Printf("[neo4j_client.DeleteGraphEntity] entity Id cannot be empty")
+		slog.ErrorContext(ctx, "Entity Id cannot be empty")
 		return fmt.Errorf("entity Id cannot be empty")
 	}
 
 	session := r.getSession(ctx)
 	defer session.Close(ctx)
 
-	query := `MATCH (e {Id: $entityID}) RETURN e`
+	query := `MATCH (e {Id: $entityID, Tenant: $tenant}) RETURN e`
 	params := map[string]interface{}{
 		"entityID": entityID,
 	}
 
 	result, err := session.Run(ctx, query, params)
 	if err != nil {
-		log.Printf("[neo4j_client.DeleteGraphEntity] error checking if entity exists: %v", err)
+		slog.ErrorContext(ctx, "Error checking if entity exists", "error", err)
 		return fmt.Errorf("error checking if entity exists: %v", err)
 	}
 
 	if !result.Next(ctx) {
-		log.Printf("[neo4j_client.DeleteGraphEntity] entity with Id %s does not exist", entityID)
+		slog.DebugContext(ctx, "Entity does not exist", "entity_id", entityID)
 		return fmt.Errorf("entity with Id %s does not exist", entityID)
 	}
 
 	// Get the relationships of the entity
 	relationships, err := r.ReadRelationships(ctx, entityID)
 	if err != nil {
-		log.Printf("[neo4j_client.DeleteGraphEntity] error getting relationships: %v", err)
+		slog.ErrorContext(ctx, "Error getting relationships", "error", err)
 		return fmt.Errorf("error getting relationships: %v", err)
 	}
 
 	// If there are relationships, return an error with relationship details
 	if len(relationships) > 0 {
-		log.Printf("[neo4j_client.DeleteGraphEntity] entity has relationships and cannot be deleted. Relationships: %v", relationships)
+		slog.ErrorContext(ctx, "Entity has relationships and cannot be deleted", "relationships", relationships)
 		return fmt.Errorf("entity has relationships and cannot be deleted. Relationships: %v", relationships)
 	}
 
 	// Delete the entity (node) with the given Id
-	deleteQuery := `MATCH (e {Id: $entityID}) DELETE e`
+	deleteQuery := `MATCH (e {Id: $entityID, Tenant: $tenant}) DELETE e`
 	_, err = session.Run(ctx, deleteQuery, params)
 	if err != nil {
-		log.Printf("[neo4j_client.DeleteGraphEntity] error deleting entity: %v", err)
+		slog.ErrorContext(ctx, "Error deleting entity", "error", err)
 		return fmt.Errorf("error deleting entity: %v", err)
 	}
 
 	return nil
 }
 
-func (r *Neo4jRepository) FilterEntities(ctx context.Context, kind *pb.Kind, filters map[string]interface{}) ([]map[string]interface{}, error) {
+func (r *Neo4jRepository) FilterEntities(ctx context.Context, kind *pb.Kind, filters map[string]interface{}) (_ []map[string]interface{}, err error) {
+	defer metrics.ObserveDatabase(metrics.Neo4j, "FilterEntities", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Neo4j, "FilterEntities")
+	defer tracing.End(span, &err)
 	// Open a session
 	session := r.getSession(ctx)
 	defer session.Close(ctx)
@@ -839,7 +894,7 @@ func (r *Neo4jRepository) FilterEntities(ctx context.Context, kind *pb.Kind, fil
 	// If we have an ID filter, use a simpler query
 	if id, ok := filters["id"].(string); ok && id != "" {
 		query = `
-			MATCH (e {Id: $id})
+			MATCH (e {Id: $id, Tenant: $tenant})
 			RETURN e.Id AS id, labels(e)[0] AS kind, 
 				   toString(e.Created) AS created, 
 				   CASE WHEN e.Terminated IS NOT NULL THEN toString(e.Terminated) ELSE NULL END AS terminated, 
@@ -855,8 +910,11 @@ func (r *Neo4jRepository) FilterEntities(ctx context.Context, kind *pb.Kind, fil
 			return nil, fmt.Errorf("kind.Major is required")
 		}
 
-		// Start building the Cypher query
-		query = `MATCH (e:` + kind.Major + `) WHERE 1=1 ` // Use kind.Major as the label
+		// Start building the Cypher query with kind.Major as the label
+		query, err = cypher.MatchKind(kind.Major)
+		if err != nil {
+			return nil, err
+		}
 		params = map[string]interface{}{}
 
 		// Add MinorKind filter if provided
@@ -893,7 +951,7 @@ func (r *Neo4jRepository) FilterEntities(ctx context.Context, kind *pb.Kind, fil
 	// Run the query
 	result, err := session.Run(ctx, query, params)
 	if err != nil {
-		log.Printf("[neo4j_client.FilterEntities] error querying entities: %v", err)
+		slog.ErrorContext(ctx, "Error querying entities", "error", err)
 		return nil, fmt.Errorf("error querying entities: %v", err)
 	}
 
@@ -916,7 +974,7 @@ func (r *Neo4jRepository) FilterEntities(ctx context.Context, kind *pb.Kind, fil
 
 	// Check for errors during iteration
 	if err := result.Err(); err != nil {
-		log.Printf("[neo4j_client.FilterEntities] error iterating over query results: %v", err)
+		slog.ErrorContext(ctx, "Error iterating over query results", "error", err)
 		return nil, fmt.Errorf("error iterating over query results: %v", err)
 	}
 
@@ -924,7 +982,10 @@ func (r *Neo4jRepository) FilterEntities(ctx context.Context, kind *pb.Kind, fil
 }
 
 // ReadFilteredRelationships retrieves relationships for an entity based on provided filters
-func (r *Neo4jRepository) ReadFilteredRelationships(ctx context.Context, entityID string, relationshipFilters map[string]interface{}, activeAt string) ([]map[string]interface{}, error) {
+func (r *Neo4jRepository) ReadFilteredRelationships(ctx context.Context, entityID string, relationshipFilters map[string]interface{}, activeAt string) (_ []map[string]interface{}, err error) {
+	defer metrics.ObserveDatabase(metrics.Neo4j, "ReadFilteredRelationships", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Neo4j, "ReadFilteredRelationships")
+	defer tracing.End(span, &err)
 	if entityID == "" {
 		return nil, fmt.Errorf("entity Id cannot be empty")
 	}
@@ -943,13 +1004,13 @@ func (r *Neo4jRepository) ReadFilteredRelationships(ctx context.Context, entityI
 
 	// Build outgoing relationships query
 	outgoingQuery = `
-		MATCH (e {Id: $entityID})-[r]->(related)
+		MATCH (e {Id: $entityID, Tenant: $tenant})-[r]->(related)
 		WHERE 1=1
 	`
 
 	// Build incoming relationships query
 	incomingQuery = `
-		MATCH (e {Id: $entityID})<-[r]-(related)
+		MATCH (e {Id: $entityID, Tenant: $tenant})<-[r]-(related)
 		WHERE 1=1
 	`
 
@@ -1041,7 +1102,7 @@ func (r *Neo4jRepository) ReadFilteredRelationships(ctx context.Context, entityI
 	// Execute the query
 	result, err := session.Run(ctx, finalQuery, params)
 	if err != nil {
-		log.Printf("[neo4j_client.ReadFilteredRelationships] error querying relationships: %v", err)
+		slog.ErrorContext(ctx, "Error querying relationships", "error", err)
 		return nil, fmt.Errorf("error querying relationships: %v", err)
 	}
 
@@ -1094,9 +1155,149 @@ func (r *Neo4jRepository) ReadFilteredRelationships(ctx context.Context, entityI
 	}
 
 	if err := result.Err(); err != nil {
-		log.Printf("[neo4j_client.ReadFilteredRelationships] error iterating over query result: %v", err)
+		slog.ErrorContext(ctx, "Error iterating over query result", "error", err)
 		return nil, fmt.Errorf("error iterating over query result: %v", err)
 	}
 
 	return relationships, nil
 }
+
+// DefaultPathMaxDepth is the number of hops searched when no maximum depth is given
+const DefaultPathMaxDepth = 10
+
+// MaxPathMaxDepth bounds the variable-length pattern so a path query cannot scan the whole graph
+const MaxPathMaxDepth = 25
+
+// ReadShortestPaths finds the shortest path (or every shortest path) between two entities.
+// Only relationships whose type is listed in relationshipNames are traversed (all types when empty),
+// and when activeAt is set every hop must be active at that instant.
+// Each returned path is a map with an ordered "entities" list and an ordered "relationships" list,
+// where relationships[i] connects entities[i] and entities[i+1].
+func (r *Neo4jRepository) ReadShortestPaths(ctx context.Context, sourceID string, targetID string, relationshipNames []string, activeAt string, direction string, maxDepth int, allShortest bool) (_ []map[string]interface{}, err error) {
+	defer metrics.ObserveDatabase(metrics.Neo4j, "ReadShortestPaths", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Neo4j, "ReadShortestPaths")
+	defer tracing.End(span, &err)
+	if sourceID == "" || targetID == "" {
+		return nil, fmt.Errorf("source and target entity Ids cannot be empty")
+	}
+	if sourceID == targetID {
+		return nil, fmt.Errorf("source and target entity Ids must be different")
+	}
+
+	if maxDepth <= 0 {
+		maxDepth = DefaultPathMaxDepth
+	}
+	if maxDepth > MaxPathMaxDepth {
+		return nil, fmt.Errorf("maxDepth %d exceeds the allowed maximum of %d", maxDepth, MaxPathMaxDepth)
+	}
+
+	// The depth is an integer we control, the relationship names and timestamps are passed as parameters
+	var pattern string
+	switch direction {
+	case "OUTGOING":
+		pattern = fmt.Sprintf(`(s)-[*..%d]->(t)`, maxDepth)
+	case "INCOMING":
+		pattern = fmt.Sprintf(`(s)<-[*..%d]-(t)`, maxDepth)
+	case "":
+		pattern = fmt.Sprintf(`(s)-[*..%d]-(t)`, maxDepth)
+	default:
+		return nil, fmt.Errorf("invalid direction %s, expected OUTGOING or INCOMING", direction)
+	}
+
+	pathFunction := "shortestPath"
+	if allShortest {
+		pathFunction = "allShortestPaths"
+	}
+
+	params := map[string]interface{}{
+		"sourceID": sourceID,
+		"targetID": targetID,
+	}
+
+	var conditions []string
+	if len(relationshipNames) > 0 {
+		params["relationshipNames"] = relationshipNames
+		conditions = append(conditions, `all(r IN relationships(p) WHERE type(r) IN $relationshipNames)`)
+	}
+
+	if activeAt != "" {
+		params["activeAt"] = activeAt
+		conditions = append(conditions, `all(r IN relationships(p) WHERE r.Created <= datetime($activeAt) AND (r.Terminated IS NULL OR r.Terminated > datetime($activeAt)))`)
+	}
+
+	query := fmt.Sprintf(`
+		MATCH (s {Id: $sourceID, Tenant: $tenant}), (t {Id: $targetID, Tenant: $tenant}), p = %s(%s)`, pathFunction, pattern)
+	if len(conditions) > 0 {
+		query += `
+		WHERE ` + strings.Join(conditions, " AND ")
+	}
+
+	query += `
+		RETURN [n IN nodes(p) | {
+		           Id: n.Id, Name: n.Name, MajorKind: labels(n)[0], MinorKind: n.MinorKind,
+		           Created: toString(n.Created),
+		           Terminated: CASE WHEN n.Terminated IS NOT NULL THEN toString(n.Terminated) ELSE NULL END
+		       }] AS entities,
+		       [r IN relationships(p) | {
+		           id: r.Id, name: type(r), startEntityId: startNode(r).Id, endEntityId: endNode(r).Id,
+		           startTime: toString(r.Created),
+		           endTime: CASE WHEN r.Terminated IS NOT NULL THEN toString(r.Terminated) ELSE NULL END
+		       }] AS relationships
+	`
+
+	session := r.getSession(ctx)
+	defer session.Close(ctx)
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error querying paths", "error", err)
+		return nil, fmt.Errorf("error querying paths: %v", err)
+	}
+
+	var paths []map[string]interface{}
+	for result.Next(ctx) {
+		record := result.Record()
+
+		entitiesValue, _ := record.Get("entities")
+		relationshipsValue, _ := record.Get("relationships")
+
+		path := map[string]interface{}{
+			"entities":      toMapList(entitiesValue),
+			"relationships": toMapList(relationshipsValue),
+		}
+		paths = append(paths, path)
+	}
+
+	if err := result.Err(); err != nil {
+		slog.ErrorContext(ctx, "Error iterating over query result", "error", err)
+		return nil, fmt.Errorf("error iterating over query result: %v", err)
+	}
+
+	return paths, nil
+}
+
+// toMapList converts a list of maps returned by a Cypher projection into a slice of
+// maps with string values, dropping properties that are not set
+func toMapList(value interface{}) []map[string]interface{} {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	maps := make([]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		props, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		converted := make(map[string]interface{}, len(props))
+		for key, val := range props {
+			if val == nil {
+				continue
+			}
+			converted[key] = fmt.Sprintf("%v", val)
+		}
+		maps = append(maps, converted)
+	}
+	return maps
+}
@@ -1137,3 +1137,80 @@ func TestReadFilteredRelationships(t *testing.T) {
 	assert.Equal(t, 1, len(rels), "Expected 1 FRIEND relationship that is OUTGOING and active at 2025-04-15T00:00:00Z")
 	assert.Equal(t, "rel1", rels[0]["id"])
 }
+
+func TestReadShortestPaths(t *testing.T) {
+	ctx := context.Background()
+
+	kind := &pb.Kind{
+		Major: "Person",
+		Minor: "PathTester",
+	}
+
+	// Create a chain P1 -> P2 -> P3 plus a longer detour P1 -> P4 -> P5 -> P3
+	for _, id := range []string{"path1", "path2", "path3", "path4", "path5"} {
+		_, err := repository.CreateGraphEntity(ctx, kind, map[string]interface{}{
+			"Id":      id,
+			"Name":    "Entity " + id,
+			"Created": "2025-01-01T00:00:00Z",
+		})
+		assert.Nil(t, err, "Expected no error when creating entity %s", id)
+	}
+
+	relationships := []struct {
+		from string
+		rel  *pb.Relationship
+	}{
+		{"path1", &pb.Relationship{Id: "pathRel1", Name: "KNOWS", RelatedEntityId: "path2", StartTime: "2025-01-01T00:00:00Z", EndTime: "2025-06-01T00:00:00Z"}},
+		{"path2", &pb.Relationship{Id: "pathRel2", Name: "KNOWS", RelatedEntityId: "path3", StartTime: "2025-01-01T00:00:00Z"}},
+		{"path1", &pb.Relationship{Id: "pathRel3", Name: "WORKS_WITH", RelatedEntityId: "path4", StartTime: "2025-01-01T00:00:00Z"}},
+		{"path4", &pb.Relationship{Id: "pathRel4", Name: "WORKS_WITH", RelatedEntityId: "path5", StartTime: "2025-01-01T00:00:00Z"}},
+		{"path3", &pb.Relationship{Id: "pathRel5", Name: "WORKS_WITH", RelatedEntityId: "path5", StartTime: "2025-01-01T00:00:00Z"}},
+	}
+	for _, r := range relationships {
+		_, err := repository.CreateRelationship(ctx, r.from, r.rel)
+		assert.Nil(t, err, "Expected no error when creating relationship %s", r.rel.Id)
+	}
+
+	// 1. Shortest path ignoring direction goes through path2
+	paths, err := repository.ReadShortestPaths(ctx, "path1", "path3", nil, "", "", 0, false)
+	log.Printf("ReadShortestPaths response (any direction): %+v", paths)
+	assert.Nil(t, err, "Expected no error when reading shortest path")
+	assert.Equal(t, 1, len(paths), "Expected a single shortest path")
+	entities := paths[0]["entities"].([]map[string]interface{})
+	rels := paths[0]["relationships"].([]map[string]interface{})
+	assert.Equal(t, 3, len(entities), "Expected 3 entities on the shortest path")
+	assert.Equal(t, 2, len(rels), "Expected 2 relationships on the shortest path")
+	assert.Equal(t, "path2", entities[1]["Id"])
+	assert.Equal(t, "pathRel1", rels[0]["id"])
+
+	// 2. Restricting the relationship types forces the detour
+	paths, err = repository.ReadShortestPaths(ctx, "path1", "path3", []string{"WORKS_WITH"}, "", "", 0, false)
+	log.Printf("ReadShortestPaths response (WORKS_WITH only): %+v", paths)
+	assert.Nil(t, err, "Expected no error when reading shortest path by relationship name")
+	assert.Equal(t, 1, len(paths), "Expected a single shortest path")
+	assert.Equal(t, 4, len(paths[0]["entities"].([]map[string]interface{})), "Expected the detour through path4 and path5")
+
+	// 3. The detour is not reachable when only following outgoing relationships
+	paths, err = repository.ReadShortestPaths(ctx, "path1", "path3", []string{"WORKS_WITH"}, "", "OUTGOING", 0, false)
+	assert.Nil(t, err, "Expected no error when reading outgoing shortest path")
+	assert.Equal(t, 0, len(paths), "Expected no outgoing WORKS_WITH path from path1 to path3")
+
+	// 4. After pathRel1 ends only the detour remains
+	paths, err = repository.ReadShortestPaths(ctx, "path1", "path3", nil, "2025-07-01T00:00:00Z", "", 0, false)
+	assert.Nil(t, err, "Expected no error when reading shortest path with activeAt")
+	assert.Equal(t, 1, len(paths), "Expected a single shortest path")
+	assert.Equal(t, 4, len(paths[0]["entities"].([]map[string]interface{})), "Expected the detour once pathRel1 has ended")
+
+	// 5. Max depth limits the search
+	paths, err = repository.ReadShortestPaths(ctx, "path1", "path3", []string{"WORKS_WITH"}, "", "", 2, false)
+	assert.Nil(t, err, "Expected no error when reading shortest path with maxDepth")
+	assert.Equal(t, 0, len(paths), "Expected no path within 2 hops")
+
+	// 6. Invalid requests
+	_, err = repository.ReadShortestPaths(ctx, "path1", "path1", nil, "", "", 0, false)
+	assert.NotNil(t, err, "Expected an error when source and target are the same")
+	_, err = repository.ReadShortestPaths(ctx, "path1", "path3", nil, "", "SIDEWAYS", 0, false)
+	assert.NotNil(t, err, "Expected an error for an invalid direction")
+	_, err = repository.ReadShortestPaths(ctx, "path1", "path3", nil, "", "", MaxPathMaxDepth+1, false)
+	assert.NotNil(t, err, "Expected an error when maxDepth exceeds the maximum")
+}
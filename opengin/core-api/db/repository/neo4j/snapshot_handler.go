@@ -0,0 +1,170 @@
+package neo4jrepository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"lk/datafoundation/core-api/db/repository/neo4j/cypher"
+	"lk/datafoundation/core-api/pkg/metrics"
+	"lk/datafoundation/core-api/pkg/tracing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// ExportGraph streams every entity and then every relationship of the call's tenant inside a single
+// read transaction, so both lists describe the same state of the graph.
+// Entities are passed as maps with Id, MajorKind, MinorKind, Name, Created and Terminated.
+// Relationships are passed as maps with Id, Name, SourceId, SourceKind, TargetId, TargetKind, Created and Terminated.
+func (r *Neo4jRepository) ExportGraph(ctx context.Context, entityFn func(map[string]interface{}) error, relationshipFn func(map[string]interface{}) error) (err error) {
+	defer metrics.ObserveDatabase(metrics.Neo4j, "ExportGraph", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Neo4j, "ExportGraph")
+	defer tracing.End(span, &err)
+	session := r.client.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	// An explicit transaction is used instead of ExecuteRead so the callbacks are never retried
+	tx, err := session.BeginTransaction(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error starting transaction", "error", err)
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Close(ctx)
+
+	entityQuery := `
+		MATCH (n {Tenant: $tenant})
+		RETURN n.Id AS Id, labels(n)[0] AS MajorKind, n.MinorKind AS MinorKind, n.Name AS Name,
+		       toString(n.Created) AS Created, toString(n.Terminated) AS Terminated
+		ORDER BY n.Id`
+	if err := streamRecords(ctx, tx, entityQuery, entityFn); err != nil {
+		slog.ErrorContext(ctx, "Error exporting entities", "error", err)
+		return fmt.Errorf("error exporting entities: %v", err)
+	}
+
+	relationshipQuery := `
+		MATCH (s)-[r {Tenant: $tenant}]->(t)
+		RETURN r.Id AS Id, type(r) AS Name, s.Id AS SourceId, labels(s)[0] AS SourceKind,
+		       t.Id AS TargetId, labels(t)[0] AS TargetKind,
+		       toString(r.Created) AS Created, toString(r.Terminated) AS Terminated
+		ORDER BY r.Id`
+	if err := streamRecords(ctx, tx, relationshipQuery, relationshipFn); err != nil {
+		slog.ErrorContext(ctx, "Error exporting relationships", "error", err)
+		return fmt.Errorf("error exporting relationships: %v", err)
+	}
+
+	return nil
+}
+
+// streamRecords runs a query and passes each record as a map, dropping null values
+func streamRecords(ctx context.Context, tx neo4j.ExplicitTransaction, query string, fn func(map[string]interface{}) error) error {
+	result, err := tx.Run(ctx, query, tenantParams(ctx, nil))
+	if err != nil {
+		return err
+	}
+
+	for result.Next(ctx) {
+		record := result.Record()
+		values := make(map[string]interface{}, len(record.Keys))
+		for i, key := range record.Keys {
+			if record.Values[i] != nil {
+				values[key] = fmt.Sprintf("%v", record.Values[i])
+			}
+		}
+		if err := fn(values); err != nil {
+			return err
+		}
+	}
+
+	return result.Err()
+}
+
+// CountGraph returns the number of entities and relationships of the call's tenant
+func (r *Neo4jRepository) CountGraph(ctx context.Context) (_ int64, _ int64, err error) {
+	defer metrics.ObserveDatabase(metrics.Neo4j, "CountGraph", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Neo4j, "CountGraph")
+	defer tracing.End(span, &err)
+	session := r.getSession(ctx)
+	defer session.Close(ctx)
+
+	var counts [2]int64
+	for i, query := range []string{
+		`MATCH (n {Tenant: $tenant}) RETURN count(n) AS count`,
+		`MATCH ()-[r {Tenant: $tenant}]->() RETURN count(r) AS count`,
+	} {
+		result, err := session.Run(ctx, query, nil)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error counting graph", "error", err)
+			return 0, 0, fmt.Errorf("error counting graph: %v", err)
+		}
+		record, err := result.Single(ctx)
+		if err != nil {
+			return 0, 0, fmt.Errorf("error counting graph: %v", err)
+		}
+		count, _ := record.Get("count")
+		counts[i], _ = count.(int64)
+	}
+
+	return counts[0], counts[1], nil
+}
+
+// RestoreGraphEntities creates a batch of entities of one kind for the call's tenant.
+// Each row needs Id and may carry MinorKind, Name, Created and Terminated.
+func (r *Neo4jRepository) RestoreGraphEntities(ctx context.Context, majorKind string, rows []map[string]interface{}) error {
+	query, err := cypher.RestoreEntities(majorKind)
+	if err != nil {
+		return err
+	}
+
+	return r.runBatch(ctx, "RestoreGraphEntities", query, rows)
+}
+
+// RestoreRelationships creates a batch of relationships of one type for the call's tenant.
+// Each row needs Id, SourceId, SourceKind, TargetId and TargetKind and may carry Created and Terminated.
+// The kinds let Neo4j use label indexes to find the ends; rows with the same kinds must be batched together.
+func (r *Neo4jRepository) RestoreRelationships(ctx context.Context, relationshipName string, sourceKind string, targetKind string, rows []map[string]interface{}) error {
+	query, err := cypher.RestoreRelationships(relationshipName, sourceKind, targetKind)
+	if err != nil {
+		return err
+	}
+
+	session := r.getSession(ctx)
+	defer session.Close(ctx)
+
+	result, err := session.Run(ctx, query, map[string]interface{}{"rows": rows})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error restoring relationships", "error", err)
+		return fmt.Errorf("error restoring relationships: %v", err)
+	}
+	record, err := result.Single(ctx)
+	if err != nil {
+		return fmt.Errorf("error restoring relationships: %v", err)
+	}
+	created, _ := record.Get("created")
+	if count, _ := created.(int64); count != int64(len(rows)) {
+		return fmt.Errorf("restored %d of %d %s relationships, some entities are missing", count, len(rows), relationshipName)
+	}
+
+	return nil
+}
+
+// runBatch runs a write query with the rows bound to $rows
+func (r *Neo4jRepository) runBatch(ctx context.Context, operation string, query string, rows []map[string]interface{}) (err error) {
+	defer metrics.ObserveDatabase(metrics.Neo4j, operation, time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Neo4j, operation)
+	defer tracing.End(span, &err)
+	session := r.getSession(ctx)
+	defer session.Close(ctx)
+
+	result, err := session.Run(ctx, query, map[string]interface{}{"rows": rows})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error running batch", "operation", operation, "error", err)
+		return fmt.Errorf("error running %s batch: %v", operation, err)
+	}
+	if _, err := result.Consume(ctx); err != nil {
+		slog.ErrorContext(ctx, "Error running batch", "operation", operation, "error", err)
+		return fmt.Errorf("error running %s batch: %v", operation, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package neo4jrepository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"lk/datafoundation/core-api/pkg/metrics"
+	"lk/datafoundation/core-api/pkg/tenant"
+	"lk/datafoundation/core-api/pkg/tracing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// Every node and relationship carries the tenant it belongs to in its Tenant property,
+// and every query matches on it, so the same Id can be used by several tenants.
+
+// tenantParams returns a copy of params with the tenant of the call bound to $tenant
+func tenantParams(ctx context.Context, params map[string]interface{}) map[string]interface{} {
+	scoped := make(map[string]interface{}, len(params)+1)
+	for key, value := range params {
+		scoped[key] = value
+	}
+	scoped["tenant"] = tenant.FromContext(ctx)
+	return scoped
+}
+
+// tenantSession binds the tenant of the call to $tenant in every query run on the session
+type tenantSession struct {
+	neo4j.SessionWithContext
+}
+
+func (s tenantSession) Run(ctx context.Context, cypher string, params map[string]any, configurers ...func(*neo4j.TransactionConfig)) (neo4j.ResultWithContext, error) {
+	return s.SessionWithContext.Run(ctx, cypher, tenantParams(ctx, params), configurers...)
+}
+
+// backfillTenant assigns the nodes and relationships written before tenants existed to the default tenant
+func backfillTenant(ctx context.Context, client neo4j.DriverWithContext) error {
+	session := client.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	// CALL IN TRANSACTIONS needs an auto-commit query, so session.Run is used rather than ExecuteWrite
+	for _, query := range []string{
+		`MATCH (n) WHERE n.Tenant IS NULL CALL { WITH n SET n.Tenant = $tenant } IN TRANSACTIONS OF 10000 ROWS`,
+		`MATCH ()-[r]->() WHERE r.Tenant IS NULL CALL { WITH r SET r.Tenant = $tenant } IN TRANSACTIONS OF 10000 ROWS`,
+	} {
+		result, err := session.Run(ctx, query, map[string]interface{}{"tenant": tenant.Default})
+		if err != nil {
+			return err
+		}
+		summary, err := result.Consume(ctx)
+		if err != nil {
+			return err
+		}
+		if updated := summary.Counters().PropertiesSet(); updated > 0 {
+			slog.InfoContext(ctx, "Assigned graph records to the default tenant", "count", updated)
+		}
+	}
+	return nil
+}
+
+// CountEntities returns the number of entities of the call's tenant, not counting attribute datasets
+func (r *Neo4jRepository) CountEntities(ctx context.Context) (_ int64, err error) {
+	defer metrics.ObserveDatabase(metrics.Neo4j, "CountEntities", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Neo4j, "CountEntities")
+	defer tracing.End(span, &err)
+	session := r.getSession(ctx)
+	defer session.Close(ctx)
+
+	result, err := session.Run(ctx, `MATCH (n {Tenant: $tenant}) WHERE NOT n:Dataset RETURN count(n) AS count`, nil)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error counting entities", "error", err)
+		return 0, fmt.Errorf("error counting entities: %v", err)
+	}
+	record, err := result.Single(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error counting entities: %v", err)
+	}
+	count, _ := record.Get("count")
+	total, _ := count.(int64)
+	return total, nil
+}
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"lk/datafoundation/core-api/pkg/metrics"
+	"lk/datafoundation/core-api/pkg/tracing"
+
+	"github.com/lib/pq"
+)
+
+// QuarantineSchema is the schema attribute tables of the default tenant are moved to when they are quarantined.
+// The tables of other tenants go to a schema named QuarantineSchema + "_" + tenant.
+const QuarantineSchema = "quarantine"
+
+// EntityAttributeRow is a row of the entity_attributes table
+type EntityAttributeRow struct {
+	EntityID      string
+	AttributeName string
+	TableName     string
+}
+
+// ListEntityAttributes returns every row of entity_attributes
+func (r *PostgresRepository) ListEntityAttributes(ctx context.Context, tx *sql.Tx) (_ []EntityAttributeRow, err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "ListEntityAttributes", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "ListEntityAttributes")
+	defer tracing.End(span, &err)
+	rows, err := tx.QueryContext(ctx, `SELECT entity_id, attribute_name, table_name FROM entity_attributes ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("error listing entity attributes: %v", err)
+	}
+	defer rows.Close()
+
+	var attributes []EntityAttributeRow
+	for rows.Next() {
+		var row EntityAttributeRow
+		if err := rows.Scan(&row.EntityID, &row.AttributeName, &row.TableName); err != nil {
+			return nil, fmt.Errorf("error scanning entity attribute: %v", err)
+		}
+		attributes = append(attributes, row)
+	}
+	return attributes, rows.Err()
+}
+
+// TableHasOwner reports whether an entity_attributes row refers to the table
+func (r *PostgresRepository) TableHasOwner(ctx context.Context, table string) (_ bool, err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "TableHasOwner", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "TableHasOwner")
+	defer tracing.End(span, &err)
+	db, err := r.tenantDB(ctx)
+	if err != nil {
+		return false, err
+	}
+	var owned bool
+	if err := db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM entity_attributes WHERE table_name = $1)`, table).Scan(&owned); err != nil {
+		return false, fmt.Errorf("error looking up owner of %s: %v", table, err)
+	}
+	return owned, nil
+}
+
+// QuarantineAttributeTable moves an attribute table into the quarantine schema and removes its
+// entity_attributes and attribute_schemas rows. The former owner is kept in the table comment.
+// A table that no longer exists only has its bookkeeping rows removed.
+func (r *PostgresRepository) QuarantineAttributeTable(ctx context.Context, table string) (err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "QuarantineAttributeTable", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "QuarantineAttributeTable")
+	defer tracing.End(span, &err)
+	if !tableNamePattern.MatchString(table) {
+		return fmt.Errorf("invalid table name %q", table)
+	}
+
+	db, err := r.tenantDB(ctx)
+	if err != nil {
+		return err
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM pg_tables WHERE schemaname = current_schema() AND tablename = $1)`, table).Scan(&exists); err != nil {
+		return fmt.Errorf("error looking up table %s: %v", table, err)
+	}
+
+	if exists {
+		quarantine := pq.QuoteIdentifier(quarantineSchema(ctx))
+		owner := "none"
+		var entityID, attributeName string
+		err := tx.QueryRowContext(ctx, `SELECT entity_id, attribute_name FROM entity_attributes WHERE table_name = $1`, table).Scan(&entityID, &attributeName)
+		if err == nil {
+			owner = fmt.Sprintf("entity_id=%s attribute_name=%s", entityID, attributeName)
+		} else if err != sql.ErrNoRows {
+			return fmt.Errorf("error looking up owner of %s: %v", table, err)
+		}
+
+		// Foreign keys to entity_attributes would block deleting the bookkeeping rows
+		constraints, err := foreignKeyConstraints(ctx, tx, table)
+		if err != nil {
+			return err
+		}
+		for _, constraint := range constraints {
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT %s`, table, pq.QuoteIdentifier(constraint))); err != nil {
+				return fmt.Errorf("error dropping constraint %s of %s: %v", constraint, table, err)
+			}
+		}
+
+		statements := []string{
+			fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS %s`, quarantine),
+			fmt.Sprintf(`ALTER TABLE %s SET SCHEMA %s`, table, quarantine),
+			fmt.Sprintf(`COMMENT ON TABLE %s.%s IS %s`, quarantine, table,
+				pq.QuoteLiteral(fmt.Sprintf("quarantined %s, owner: %s", time.Now().UTC().Format(time.RFC3339), owner))),
+		}
+		for _, statement := range statements {
+			if _, err := tx.ExecContext(ctx, statement); err != nil {
+				slog.ErrorContext(ctx, "Error quarantining table", "table", table, "error", err)
+				return fmt.Errorf("error quarantining %s: %v", table, err)
+			}
+		}
+	}
+
+	for _, bookkeeping := range CoreTables {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE table_name = $1`, bookkeeping), table); err != nil {
+			return fmt.Errorf("error deleting %s rows of %s: %v", bookkeeping, table, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing quarantine of %s: %v", table, err)
+	}
+	return nil
+}
+
+// foreignKeyConstraints returns the names of the foreign key constraints of a table in the current schema
+func foreignKeyConstraints(ctx context.Context, tx *sql.Tx, table string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT conname FROM pg_constraint
+		WHERE contype = 'f' AND conrelid = (quote_ident(current_schema()) || '.' || quote_ident($1))::regclass`, table)
+	if err != nil {
+		return nil, fmt.Errorf("error listing constraints of %s: %v", table, err)
+	}
+	defer rows.Close()
+
+	var constraints []string
+	for rows.Next() {
+		var constraint string
+		if err := rows.Scan(&constraint); err != nil {
+			return nil, fmt.Errorf("error scanning constraint name: %v", err)
+		}
+		constraints = append(constraints, constraint)
+	}
+	return constraints, rows.Err()
+}
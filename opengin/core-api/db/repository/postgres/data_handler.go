@@ -2,17 +2,23 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	"strconv"
 	"strings"
 	"time"
 
 	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/metrics"
 	"lk/datafoundation/core-api/pkg/schema"
+	"lk/datafoundation/core-api/pkg/tracing"
 	"lk/datafoundation/core-api/pkg/typeinference"
 
 	commons "lk/datafoundation/core-api/commons"
+	"lk/datafoundation/core-api/db/repository"
+	"lk/datafoundation/core-api/pkg/logging"
 
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/structpb"
@@ -107,7 +113,7 @@ func UnmarshalEntityAttributes(attributes map[string]*anypb.Any) (map[string]int
 			continue
 		}
 
-		log.Printf("Warning: Could not unmarshal attribute %s with type %s", key, value.TypeUrl)
+		slog.Warn("Could not unmarshal attribute", "attribute", key, "type_url", value.TypeUrl)
 	}
 
 	return result, nil
@@ -288,8 +294,8 @@ func isDateTime(val string) bool {
 	return false
 }
 
-// validateDataAgainstSchema validates that the data matches the schema
-func validateDataAgainstSchema(data *structpb.Struct, schemaInfo *schema.SchemaInfo) error {
+// ValidateDataAgainstSchema validates that the data matches the schema
+func ValidateDataAgainstSchema(data *structpb.Struct, schemaInfo *schema.SchemaInfo) error {
 	columnsList := data.Fields["columns"].GetListValue()
 	rowsList := data.Fields["rows"].GetListValue()
 
@@ -312,13 +318,27 @@ func validateDataAgainstSchema(data *structpb.Struct, schemaInfo *schema.SchemaI
 		for j, value := range rowData.Values {
 			colName := columnsList.Values[j].GetStringValue()
 			fieldSchema := schemaInfo.Fields[colName]
+			if _, isNull := value.Kind.(*structpb.Value_NullValue); isNull {
+				continue
+			}
 
 			// Validate type
 			switch fieldSchema.TypeInfo.Type {
 			case typeinference.IntType:
-				if v, ok := value.Kind.(*structpb.Value_NumberValue); !ok || v.NumberValue != float64(int64(v.NumberValue)) {
+				if v, ok := value.Kind.(*structpb.Value_NumberValue); !ok || typeinference.NumberType(v.NumberValue) != typeinference.IntType {
 					return fmt.Errorf("row %d, column %s: expected integer, got %v", i, colName, value)
 				}
+			case typeinference.BigIntType:
+				if !isBigInt(value) {
+					return fmt.Errorf("row %d, column %s: expected big integer, got %v", i, colName, value)
+				}
+			case typeinference.DecimalType:
+				// Values with more digits than the column holds would be rounded
+				text, ok := typeinference.NumberText(value)
+				if !ok || !typeinference.FitsDecimal(text, fieldSchema.TypeInfo.Precision, fieldSchema.TypeInfo.Scale) {
+					return fmt.Errorf("row %d, column %s: expected decimal(%d,%d), got %v", i, colName,
+						fieldSchema.TypeInfo.Precision, fieldSchema.TypeInfo.Scale, value)
+				}
 			case typeinference.FloatType:
 				if _, ok := value.Kind.(*structpb.Value_NumberValue); !ok {
 					return fmt.Errorf("row %d, column %s: expected float, got %v", i, colName, value)
@@ -338,8 +358,22 @@ func validateDataAgainstSchema(data *structpb.Struct, schemaInfo *schema.SchemaI
 	return nil
 }
 
-// compareSchemas compares two schemas and returns true if they are compatible
-func compareSchemas(existing, newSchema *schema.SchemaInfo) (bool, error) {
+// isBigInt reports whether a value is a whole number or an integer string within the range of a BIGINT
+func isBigInt(value *structpb.Value) bool {
+	switch v := value.Kind.(type) {
+	case *structpb.Value_NumberValue:
+		dataType := typeinference.NumberType(v.NumberValue)
+		return dataType == typeinference.IntType || dataType == typeinference.BigIntType
+	case *structpb.Value_StringValue:
+		_, err := strconv.ParseInt(strings.TrimSpace(v.StringValue), 10, 64)
+		return err == nil
+	default:
+		return false
+	}
+}
+
+// CompareSchemas compares two schemas and returns true if they are compatible
+func CompareSchemas(existing, newSchema *schema.SchemaInfo) (bool, error) {
 	if existing.StorageType != newSchema.StorageType {
 		return false, fmt.Errorf("storage type mismatch: existing=%s, newSchema=%s",
 			existing.StorageType, newSchema.StorageType)
@@ -380,6 +414,12 @@ func isTypeCompatible(existingType, newType typeinference.DataType) bool {
 	case typeinference.IntType:
 		// Int can be promoted to float
 		return newType == typeinference.FloatType
+	case typeinference.BigIntType:
+		// Bigint holds ints, and whole floats pass validation
+		return newType == typeinference.IntType || newType == typeinference.FloatType
+	case typeinference.DecimalType:
+		// Decimal holds any number that fits its precision and scale
+		return newType == typeinference.IntType || newType == typeinference.BigIntType || newType == typeinference.FloatType
 	case typeinference.StringType:
 		// String can accept any type
 		return true
@@ -392,23 +432,58 @@ func isTypeCompatible(existingType, newType typeinference.DataType) bool {
 }
 
 // handleTabularData processes tabular data attributes
-func (repo *PostgresRepository) HandleTabularData(ctx context.Context, entityID, attrName string, value *pb.TimeBasedValue, schemaInfo *schema.SchemaInfo) error {
-	// Generate table name
-	tableName := fmt.Sprintf("attr_%s_%s", commons.SanitizeIdentifier(entityID), commons.SanitizeIdentifier(attrName))
+func (repo *PostgresRepository) HandleTabularData(ctx context.Context, entityID, attrName string, value *pb.TimeBasedValue, schemaInfo *schema.SchemaInfo) (err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "HandleTabularData", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "HandleTabularData")
+	defer tracing.End(span, &err)
+	db, err := repo.tenantDB(ctx)
+	if err != nil {
+		return err
+	}
 
-	// Convert schema to columns
-	columns := schemaToColumns(schemaInfo)
+	// A stored attribute keeps the table recorded for it, a new one gets a table named after it
+	tableName, err := lookupAttributeTable(ctx, db, entityID, attrName)
+	newAttribute := err == sql.ErrNoRows
+	if newAttribute {
+		tableName = repository.AttributeTableName(entityID, attrName)
+	} else if err != nil {
+		return err
+	}
+
+	// Extract data from the TimeBasedValue
+	var tabularStruct structpb.Struct
+	if err := value.Value.UnmarshalTo(&tabularStruct); err != nil {
+		return fmt.Errorf("error unmarshaling tabular data: %v", err)
+	}
+
+	// Extract columns and rows
+	columnsValue := tabularStruct.Fields["columns"].GetListValue()
+	rowsValue := tabularStruct.Fields["rows"].GetListValue()
+
+	if columnsValue == nil || rowsValue == nil {
+		return fmt.Errorf("invalid tabular data format")
+	}
 
 	// Check if table exists
 	exists, err := repo.TableExists(ctx, tableName)
 	if err != nil {
 		return fmt.Errorf("error checking table existence: %v", err)
 	}
+	if exists && newAttribute {
+		// Never mix the rows of two attributes in one table
+		if owned, err := repo.TableHasOwner(ctx, tableName); err != nil {
+			return err
+		} else if owned {
+			return fmt.Errorf("table %s of attribute %s of entity %s already belongs to another attribute", tableName, attrName, entityID)
+		}
+	}
 
+	// The schema the table was created with says which table column holds each data column
+	var tableSchema *schema.SchemaInfo
 	if exists {
 		// Get existing schema
 		var schemaJSON []byte
-		err = repo.DB().QueryRowContext(ctx,
+		err = db.QueryRowContext(ctx,
 			`SELECT schema_definition FROM attribute_schemas WHERE table_name = $1 ORDER BY schema_version DESC LIMIT 1`,
 			tableName).Scan(&schemaJSON)
 		if err != nil {
@@ -421,7 +496,7 @@ func (repo *PostgresRepository) HandleTabularData(ctx context.Context, entityID,
 		}
 
 		// Compare schemas
-		compatible, err := compareSchemas(&existingSchema, schemaInfo)
+		compatible, err := CompareSchemas(&existingSchema, schemaInfo)
 		if err != nil {
 			return fmt.Errorf("schema compatibility check failed: %v", err)
 		}
@@ -431,28 +506,33 @@ func (repo *PostgresRepository) HandleTabularData(ctx context.Context, entityID,
 		}
 
 		// Validate data against existing schema
-		var tabularStruct structpb.Struct
-		if err := value.Value.UnmarshalTo(&tabularStruct); err != nil {
-			return fmt.Errorf("error unmarshaling tabular data: %v", err)
-		}
-
-		if err := validateDataAgainstSchema(&tabularStruct, &existingSchema); err != nil {
+		if err := ValidateDataAgainstSchema(&tabularStruct, &existingSchema); err != nil {
 			return fmt.Errorf("data validation failed: %v", err)
 		}
+		tableSchema = &existingSchema
 	} else {
+		// Record the columns in the order they were given, with the table column each is kept in
+		names := make([]string, len(columnsValue.Values))
+		for i, col := range columnsValue.Values {
+			names[i] = col.GetStringValue()
+		}
+		stored := *schemaInfo
+		stored.Columns = repository.AssignTableColumns(names)
+		tableSchema = &stored
+
 		// Create new table
-		if err := repo.CreateDynamicTable(ctx, tableName, columns); err != nil {
+		if err := repo.CreateDynamicTable(ctx, tableName, schemaToColumns(tableSchema)); err != nil {
 			return fmt.Errorf("error creating table: %v", err)
 		}
 
 		// Store schema information
-		schemaJSON, err := json.Marshal(schemaInfo)
+		schemaJSON, err := json.Marshal(tableSchema)
 		if err != nil {
 			return fmt.Errorf("error marshaling schema: %v", err)
 		}
 
 		// Insert schema record
-		_, err = repo.DB().ExecContext(ctx,
+		_, err = db.ExecContext(ctx,
 			`INSERT INTO attribute_schemas (table_name, schema_version, schema_definition)
 			VALUES ($1, $2, $3)`,
 			tableName, 1, schemaJSON)
@@ -463,7 +543,7 @@ func (repo *PostgresRepository) HandleTabularData(ctx context.Context, entityID,
 
 	// Create entity attribute record if it doesn't exist
 	var attributeID int
-	err = repo.DB().QueryRowContext(ctx,
+	err = db.QueryRowContext(ctx,
 		`INSERT INTO entity_attributes (entity_id, attribute_name, table_name)
 		VALUES ($1, $2, $3)
 		ON CONFLICT (entity_id, attribute_name) DO UPDATE
@@ -474,24 +554,13 @@ func (repo *PostgresRepository) HandleTabularData(ctx context.Context, entityID,
 		return fmt.Errorf("error creating entity attribute record: %v", err)
 	}
 
-	// Extract data from the TimeBasedValue
-	var tabularStruct structpb.Struct
-	if err := value.Value.UnmarshalTo(&tabularStruct); err != nil {
-		return fmt.Errorf("error unmarshaling tabular data: %v", err)
-	}
-
-	// Extract columns and rows
-	columnsValue := tabularStruct.Fields["columns"].GetListValue()
-	rowsValue := tabularStruct.Fields["rows"].GetListValue()
-
-	if columnsValue == nil || rowsValue == nil {
-		return fmt.Errorf("invalid tabular data format")
-	}
-
-	// Convert columns to string slice
+	// Convert columns to the table columns holding them
 	columnNames := make([]string, len(columnsValue.Values))
 	for i, col := range columnsValue.Values {
-		columnNames[i] = commons.SanitizeIdentifier(col.GetStringValue())
+		columnNames[i] = repository.TableColumn(tableSchema, col.GetStringValue())
+		if columnNames[i] == "" {
+			return fmt.Errorf("column %s not found in schema", col.GetStringValue())
+		}
 	}
 
 	// Convert rows to [][]interface{}
@@ -505,6 +574,8 @@ func (repo *PostgresRepository) HandleTabularData(ctx context.Context, entityID,
 		rows[i] = make([]interface{}, len(rowList.Values))
 		for j, cell := range rowList.Values {
 			switch cell.Kind.(type) {
+			case *structpb.Value_NullValue:
+				rows[i][j] = nil
 			case *structpb.Value_StringValue:
 				rows[i][j] = cell.GetStringValue()
 			case *structpb.Value_NumberValue:
@@ -525,13 +596,43 @@ func (repo *PostgresRepository) HandleTabularData(ctx context.Context, entityID,
 	return nil
 }
 
-// schemaToColumns converts a schema to database columns
+// AttributeTable returns the table recorded in entity_attributes for a tabular attribute
+func (repo *PostgresRepository) AttributeTable(ctx context.Context, entityID, attrName string) (_ string, err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "AttributeTable", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "AttributeTable")
+	defer tracing.End(span, &err)
+	db, err := repo.tenantDB(ctx)
+	if err != nil {
+		return "", err
+	}
+	tableName, err := lookupAttributeTable(ctx, db, entityID, attrName)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("tabular attribute %s of entity %s does not exist", attrName, entityID)
+	}
+	return tableName, err
+}
+
+// lookupAttributeTable reads the table of an attribute from entity_attributes, returning sql.ErrNoRows when it has none
+func lookupAttributeTable(ctx context.Context, db *sql.DB, entityID, attrName string) (string, error) {
+	var tableName string
+	err := db.QueryRowContext(ctx,
+		`SELECT table_name FROM entity_attributes WHERE entity_id = $1 AND attribute_name = $2`,
+		entityID, attrName).Scan(&tableName)
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("error looking up table of attribute %s of entity %s: %v", attrName, entityID, err)
+	}
+	return tableName, err
+}
+
+// schemaToColumns converts a schema to database columns, in the order recorded in schemaInfo.Columns
 func schemaToColumns(schemaInfo *schema.SchemaInfo) []Column {
 	var columns []Column
 
-	for fieldName, field := range schemaInfo.Fields {
-		// Skip "id" columns as they conflict with the auto-generated primary key
-		if strings.ToLower(fieldName) == "id" {
+	for _, column := range schemaInfo.Columns {
+		// Columns without rows to infer a type from are kept as nullable text
+		field := schemaInfo.Fields[column.Name]
+		if field == nil || field.TypeInfo == nil {
+			columns = append(columns, Column{Name: column.Column, Type: "TEXT NULL"})
 			continue
 		}
 
@@ -539,8 +640,15 @@ func schemaToColumns(schemaInfo *schema.SchemaInfo) []Column {
 		switch field.TypeInfo.Type {
 		case typeinference.IntType:
 			colType = "INTEGER"
+		case typeinference.BigIntType:
+			colType = "BIGINT"
 		case typeinference.FloatType:
 			colType = "DOUBLE PRECISION"
+		case typeinference.DecimalType:
+			colType = "NUMERIC"
+			if field.TypeInfo.Precision > 0 {
+				colType = fmt.Sprintf("NUMERIC(%d,%d)", field.TypeInfo.Precision, field.TypeInfo.Scale)
+			}
 		case typeinference.StringType:
 			colType = "TEXT"
 		case typeinference.BoolType:
@@ -560,7 +668,7 @@ func schemaToColumns(schemaInfo *schema.SchemaInfo) []Column {
 		}
 
 		columns = append(columns, Column{
-			Name: commons.SanitizeIdentifier(fieldName),
+			Name: column.Column,
 			Type: colType,
 		})
 	}
@@ -581,7 +689,11 @@ func GetTableList(ctx context.Context, repo *PostgresRepository, entityID string
 		FROM entity_attributes
 		WHERE entity_id = $1
 	`
-	rows, err := repo.DB().QueryContext(ctx, query, entityID)
+	db, err := repo.tenantDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, query, entityID)
 	if err != nil {
 		return nil, fmt.Errorf("error querying for table list: %v", err)
 	}
@@ -612,8 +724,12 @@ func GetSchemaOfTable(ctx context.Context, repo *PostgresRepository, tableName s
 		ORDER BY schema_version DESC
 		LIMIT 1
 	`
+	db, err := repo.tenantDB(ctx)
+	if err != nil {
+		return nil, err
+	}
 	var schemaJSON []byte
-	err := repo.DB().QueryRowContext(ctx, query, tableName).Scan(&schemaJSON)
+	err = db.QueryRowContext(ctx, query, tableName).Scan(&schemaJSON)
 	if err != nil {
 		return nil, fmt.Errorf("error getting schema for table %s: %v", tableName, err)
 	}
@@ -634,11 +750,146 @@ type TabularData struct {
 
 // GetData retrieves data from a table with optional field selection and filters, returns it as pb.Any with JSON-formatted tabular data.
 func (repo *PostgresRepository) GetData(ctx context.Context, tableName string, filters map[string]interface{}, fields ...string) (*anypb.Any, error) {
-	log.Printf("DEBUG: GetData: tableName=%s, \t\nfilters=%v, \t\nfields=%v", tableName, filters, fields)
+	return repo.GetMaskedData(ctx, tableName, filters, repository.ColumnMask{}, fields...)
+}
+
+// GetMaskedData reads rows like GetData; masked columns are left out of the SELECT or selected as NULL
+func (repo *PostgresRepository) GetMaskedData(ctx context.Context, tableName string, filters map[string]interface{}, mask repository.ColumnMask, fields ...string) (_ *anypb.Any, err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "GetData", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "GetData")
+	defer tracing.End(span, &err)
+
+	result, err := repo.readData(ctx, tableName, repository.TabularQuery{Filters: filters, Fields: fields, Mask: mask})
+	if err != nil {
+		return nil, err
+	}
+	return tabularDataToAny(ctx, result.columns, result.rows)
+}
+
+// GetTypedData reads the rows a query selects like GetMaskedData and types their cells by the stored
+// schema of the table
+func (repo *PostgresRepository) GetTypedData(ctx context.Context, tableName string, tabularQuery repository.TabularQuery) (_ *pb.TabularData, err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "GetTypedData", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "GetTypedData")
+	defer tracing.End(span, &err)
+
+	result, err := repo.readData(ctx, tableName, tabularQuery)
+	if err != nil {
+		return nil, err
+	}
+	types := make([]typeinference.TypeInfo, len(result.tableColumns))
+	for i, column := range result.tableColumns {
+		types[i] = repository.ColumnTypeInfo(result.schema, column)
+	}
+	data, err := repository.NewTabularData(result.columns, types, result.rows)
+	if err != nil {
+		return nil, fmt.Errorf("error converting data from %s: %v", tableName, err)
+	}
+	return data, nil
+}
+
+// CountData counts the rows a query selects without reading them; its fields and page are ignored
+func (repo *PostgresRepository) CountData(ctx context.Context, tableName string, tabularQuery repository.TabularQuery) (_ int64, err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "CountData", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "CountData")
+	defer tracing.End(span, &err)
+
+	stored, err := repo.storedSchema(ctx, tableName)
+	if err != nil {
+		return 0, err
+	}
+	omit, redact := tabularQuery.Mask.Columns()
+	var args []interface{}
+	var whereClauses []string
+	for key, value := range tabularQuery.Filters {
+		if masked := commons.SanitizeIdentifier(key); omit[masked] || redact[masked] {
+			return 0, fmt.Errorf("column %s of %s is restricted and cannot be filtered on", key, tableName)
+		}
+		column := commons.SanitizeIdentifier(key)
+		if stored != nil && len(stored.Columns) > 0 {
+			resolved, ok := repository.ResolveColumn(stored.Columns, key)
+			if !ok {
+				return 0, fmt.Errorf("error querying data from %s: column %s does not exist", tableName, key)
+			}
+			column = resolved
+		}
+		args = append(args, value)
+		whereClauses = append(whereClauses, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	whereClauses, args = appendWrittenBetween(tabularQuery, whereClauses, args)
+
+	query := "SELECT COUNT(*) FROM " + commons.SanitizeIdentifier(tableName)
+	if len(whereClauses) > 0 {
+		query += " WHERE " + strings.Join(whereClauses, " AND ")
+	}
+	db, err := repo.tenantDB(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	if err := db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting rows of %s: %v", tableName, err)
+	}
+	return count, nil
+}
+
+// readResult holds the rows a read selected, the names they are returned under, the table columns they
+// came from and the stored schema of the table, if any
+type readResult struct {
+	columns      []string
+	tableColumns []string
+	rows         [][]interface{}
+	schema       *schema.SchemaInfo
+}
+
+// readData runs the SELECT behind GetMaskedData and GetTypedData
+func (repo *PostgresRepository) readData(ctx context.Context, tableName string, tabularQuery repository.TabularQuery) (*readResult, error) {
+	filters, mask, fields := tabularQuery.Filters, tabularQuery.Mask, tabularQuery.Fields
+	slog.DebugContext(ctx, "Getting data", "table", tableName, "filters", filters, "fields", fields, "mask", mask)
+
+	// Tables whose schema records the column layout are read under the producer's column names and order
+	stored, err := repo.storedSchema(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil && len(stored.Columns) > 0 {
+		return repo.getLaidOutData(ctx, tableName, stored, tabularQuery)
+	}
+
+	omit, redact := mask.Columns()
+	for key := range filters {
+		if column := commons.SanitizeIdentifier(key); omit[column] || redact[column] {
+			return nil, fmt.Errorf("column %s of %s is restricted and cannot be filtered on", key, tableName)
+		}
+	}
+	if !mask.Empty() && len(fields) == 0 {
+		// The column list is needed to leave masked columns out; internal columns are filtered below as usual
+		if fields, err = repo.tableColumns(ctx, tableName); err != nil {
+			return nil, err
+		}
+		fields = withoutInternalColumns(fields)
+	}
+
 	// Build the SELECT clause
 	var selectClause string
-	if len(fields) > 0 {
-		log.Printf("DEBUG: [DataHandler.GetData] selectClause: %v", fields)
+	if !mask.Empty() {
+		var selected []string
+		for _, field := range fields {
+			column := commons.SanitizeIdentifier(field)
+			switch {
+			case omit[column]:
+			case redact[column]:
+				selected = append(selected, "NULL AS "+column)
+			default:
+				selected = append(selected, column)
+			}
+		}
+		if len(selected) == 0 {
+			return &readResult{columns: []string{}, tableColumns: []string{}, rows: [][]interface{}{}, schema: stored}, nil
+		}
+		selectClause = strings.Join(selected, ", ")
+	} else if len(fields) > 0 {
+		slog.DebugContext(ctx, "Select clause", "fields", fields)
 		// Sanitize and quote field names
 		sanitizedFields := make([]string, len(fields))
 		for i, field := range fields {
@@ -646,15 +897,15 @@ func (repo *PostgresRepository) GetData(ctx context.Context, tableName string, f
 		}
 		selectClause = strings.Join(sanitizedFields, ", ")
 	} else {
-		log.Printf("DEBUG: [DataHandler.GetData] selectClause: *")
+		slog.DebugContext(ctx, "Select clause: *")
 		selectClause = "*"
 	}
 
-	log.Printf("DEBUG: [DataHandler.GetData] selectClause: %s", selectClause)
+	slog.DebugContext(ctx, "Select clause", "select_clause", selectClause)
 	// Base query
 	query := fmt.Sprintf("SELECT %s FROM %s", selectClause, commons.SanitizeIdentifier(tableName))
 
-	log.Printf("DEBUG: [DataHandler.GetData] query: %s", query)
+	slog.DebugContext(ctx, "Query", "query", query)
 
 	var args []interface{}
 	var whereClauses []string
@@ -666,13 +917,19 @@ func (repo *PostgresRepository) GetData(ctx context.Context, tableName string, f
 		args = append(args, value)
 		argCount++
 	}
+	whereClauses, args = appendWrittenBetween(tabularQuery, whereClauses, args)
 
 	if len(whereClauses) > 0 {
 		query += " WHERE " + strings.Join(whereClauses, " AND ")
 	}
+	query, args = appendPage(query, tabularQuery, args)
 
 	// Execute the query
-	rows, err := repo.DB().QueryContext(ctx, query, args...)
+	db, err := repo.tenantDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error querying data from %s: %v", tableName, err)
 	}
@@ -687,9 +944,9 @@ func (repo *PostgresRepository) GetData(ctx context.Context, tableName string, f
 	// Filter out internal columns that shouldn't be returned by default
 	// unless they are explicitly requested in the fields parameter
 	filteredColumns, columnIndices := filterInternalColumns(resultColumns, fields)
-	log.Printf("DEBUG: [DataHandler.GetData] Original columns: %v", resultColumns)
-	log.Printf("DEBUG: [DataHandler.GetData] Filtered columns: %v", filteredColumns)
-	log.Printf("DEBUG: [DataHandler.GetData] Column indices to keep: %v", columnIndices)
+	slog.DebugContext(ctx, "Original columns", "result_columns", resultColumns)
+	slog.DebugContext(ctx, "Filtered columns", "filtered_columns", filteredColumns)
+	slog.DebugContext(ctx, "Column indices to keep", "column_indices", columnIndices)
 
 	// Log which internal columns were filtered out or included
 	internalColumns := map[string]bool{
@@ -708,9 +965,9 @@ func (repo *PostgresRepository) GetData(ctx context.Context, tableName string, f
 					}
 				}
 				if found {
-					log.Printf("INFO: [DataHandler.GetData] Internal column '%s' included (explicitly requested)", column)
+					slog.DebugContext(ctx, "Internal column included (explicitly requested)", "column", column)
 				} else {
-					log.Printf("INFO: [DataHandler.GetData] Internal column '%s' filtered out (not requested)", column)
+					slog.DebugContext(ctx, "Internal column filtered out (not requested)", "column", column)
 				}
 			}
 		}
@@ -746,21 +1003,177 @@ func (repo *PostgresRepository) GetData(ctx context.Context, tableName string, f
 		return nil, fmt.Errorf("error iterating over rows: %v", err)
 	}
 
-	// Create the tabular data structure
-	tabularData := map[string]interface{}{
-		"columns": filteredColumns,
-		"rows":    tabularRows,
+	slog.DebugContext(ctx, "Result data", "columns", filteredColumns, "rows", len(tabularRows))
+	// Legacy tables return their table column names
+	return &readResult{columns: filteredColumns, tableColumns: filteredColumns, rows: tabularRows, schema: stored}, nil
+}
+
+// storedSchema returns the latest schema recorded for a table, or nil when none was
+func (repo *PostgresRepository) storedSchema(ctx context.Context, tableName string) (*schema.SchemaInfo, error) {
+	db, err := repo.tenantDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var schemaJSON []byte
+	err = db.QueryRowContext(ctx,
+		`SELECT schema_definition FROM attribute_schemas WHERE table_name = $1 ORDER BY schema_version DESC LIMIT 1`,
+		tableName).Scan(&schemaJSON)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting schema for table %s: %v", tableName, err)
+	}
+
+	var schemaInfo schema.SchemaInfo
+	if err := json.Unmarshal(schemaJSON, &schemaInfo); err != nil {
+		return nil, fmt.Errorf("error unmarshaling schema for table %s: %v", tableName, err)
+	}
+	return &schemaInfo, nil
+}
+
+// getLaidOutData reads a table through its recorded column layout. Without fields the data columns are
+// returned under their original names in their original order; fields and filters name data columns by
+// their original names, or bookkeeping columns. Masks apply to the original names.
+func (repo *PostgresRepository) getLaidOutData(ctx context.Context, tableName string, stored *schema.SchemaInfo, tabularQuery repository.TabularQuery) (*readResult, error) {
+	filters, mask, fields := tabularQuery.Filters, tabularQuery.Mask, tabularQuery.Fields
+	columns := stored.Columns
+	type selection struct {
+		name   string
+		column string
+	}
+	var selected []selection
+	if len(fields) == 0 {
+		for _, column := range columns {
+			selected = append(selected, selection{name: column.Name, column: column.Column})
+		}
+	} else {
+		for _, field := range fields {
+			column, ok := repository.ResolveColumn(columns, field)
+			if !ok {
+				return nil, fmt.Errorf("error querying data from %s: column %s does not exist", tableName, field)
+			}
+			selected = append(selected, selection{name: field, column: column})
+		}
 	}
 
-	// Convert to JSON string
-	jsonData, err := json.Marshal(tabularData)
+	omit, redact := mask.Columns()
+	var names, tableColumns, expressions []string
+	for _, s := range selected {
+		switch key := commons.SanitizeIdentifier(s.name); {
+		case omit[key]:
+		case redact[key]:
+			names = append(names, s.name)
+			tableColumns = append(tableColumns, s.column)
+			expressions = append(expressions, "NULL AS "+s.column)
+		default:
+			names = append(names, s.name)
+			tableColumns = append(tableColumns, s.column)
+			expressions = append(expressions, s.column)
+		}
+	}
+	if len(expressions) == 0 {
+		return &readResult{columns: []string{}, tableColumns: []string{}, rows: [][]interface{}{}, schema: stored}, nil
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(expressions, ", "), commons.SanitizeIdentifier(tableName))
+	var args []interface{}
+	var whereClauses []string
+	for key, value := range filters {
+		column, ok := repository.ResolveColumn(columns, key)
+		if !ok {
+			return nil, fmt.Errorf("error querying data from %s: column %s does not exist", tableName, key)
+		}
+		if masked := commons.SanitizeIdentifier(key); omit[masked] || redact[masked] {
+			return nil, fmt.Errorf("column %s of %s is restricted and cannot be filtered on", key, tableName)
+		}
+		args = append(args, value)
+		whereClauses = append(whereClauses, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	whereClauses, args = appendWrittenBetween(tabularQuery, whereClauses, args)
+	if len(whereClauses) > 0 {
+		query += " WHERE " + strings.Join(whereClauses, " AND ")
+	}
+	query, args = appendPage(query, tabularQuery, args)
+	slog.DebugContext(ctx, "Query", "query", query)
+
+	db, err := repo.tenantDB(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("error marshaling tabular data to JSON: %v", err)
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying data from %s: %v", tableName, err)
+	}
+	defer rows.Close()
+
+	var tabularRows [][]interface{}
+	for rows.Next() {
+		row := make([]interface{}, len(expressions))
+		pointers := make([]interface{}, len(row))
+		for i := range row {
+			pointers[i] = &row[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("error scanning row: %v", err)
+		}
+		for i, value := range row {
+			// Handle byte slices (common for text, json, etc.)
+			if b, ok := value.([]byte); ok {
+				row[i] = string(b)
+			}
+		}
+		tabularRows = append(tabularRows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %v", err)
+	}
+
+	slog.DebugContext(ctx, "Result data", "columns", names, "rows", len(tabularRows))
+	return &readResult{columns: names, tableColumns: tableColumns, rows: tabularRows, schema: stored}, nil
+}
+
+// appendWrittenBetween adds the time window of a query to the conditions on the rows' created_at
+func appendWrittenBetween(tabularQuery repository.TabularQuery, whereClauses []string, args []interface{}) ([]string, []interface{}) {
+	if !tabularQuery.WrittenFrom.IsZero() {
+		args = append(args, tabularQuery.WrittenFrom)
+		whereClauses = append(whereClauses, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if !tabularQuery.WrittenUntil.IsZero() {
+		args = append(args, tabularQuery.WrittenUntil)
+		whereClauses = append(whereClauses, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+	return whereClauses, args
+}
+
+// appendPage orders the rows by id, the order they were written in, and keeps the page a query asks for
+func appendPage(query string, tabularQuery repository.TabularQuery, args []interface{}) (string, []interface{}) {
+	if tabularQuery.Limit <= 0 && tabularQuery.Offset <= 0 {
+		return query, args
+	}
+	query += " ORDER BY id"
+	if tabularQuery.Limit > 0 {
+		args = append(args, tabularQuery.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
 	}
+	if tabularQuery.Offset > 0 {
+		args = append(args, tabularQuery.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+	return query, args
+}
 
-	log.Printf("DEBUG: [DataHandler.GetData] jsonData: %s", string(jsonData))
+// tabularDataToAny wraps columns and rows in the JSON form read by clients: a Struct with a "data" string
+func tabularDataToAny(ctx context.Context, columns []string, rows [][]interface{}) (*anypb.Any, error) {
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"columns": columns,
+		"rows":    rows,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling tabular data to JSON: %v", err)
+	}
+	slog.DebugContext(ctx, "Result data", "data", logging.Payload(string(jsonData)))
 
-	// Create a struct with the JSON string
 	structValue, err := structpb.NewStruct(map[string]interface{}{
 		"data": string(jsonData),
 	})
@@ -768,11 +1181,47 @@ func (repo *PostgresRepository) GetData(ctx context.Context, tableName string, f
 		return nil, fmt.Errorf("error creating struct for JSON data: %v", err)
 	}
 
-	// Convert to Any
 	anyValue, err := anypb.New(structValue)
 	if err != nil {
 		return nil, fmt.Errorf("error converting struct to Any: %v", err)
 	}
-
 	return anyValue, nil
 }
+
+// tableColumns lists the columns of a table in their order
+func (repo *PostgresRepository) tableColumns(ctx context.Context, tableName string) ([]string, error) {
+	db, err := repo.tenantDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, `
+		SELECT column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = $1
+		ORDER BY ordinal_position`, commons.SanitizeIdentifier(tableName))
+	if err != nil {
+		return nil, fmt.Errorf("error reading columns of %s: %v", tableName, err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, fmt.Errorf("error scanning column of %s: %v", tableName, err)
+		}
+		columns = append(columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("error querying data from %s: table does not exist", tableName)
+	}
+	return columns, nil
+}
+
+// withoutInternalColumns drops the bookkeeping columns that are only returned when requested
+func withoutInternalColumns(columns []string) []string {
+	kept, _ := filterInternalColumns(columns, nil)
+	return kept
+}
@@ -9,8 +9,11 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/types/known/structpb"
 
+	"lk/datafoundation/core-api/db/repository"
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
 	"lk/datafoundation/core-api/pkg/schema"
 	"lk/datafoundation/core-api/pkg/storageinference"
 	"lk/datafoundation/core-api/pkg/typeinference"
@@ -54,13 +57,15 @@ func setupTestDB(t *testing.T) *PostgresRepository {
 						AND (tablename LIKE 'test_data_table_%' 
 							OR tablename LIKE 'attr_test_%'
 							OR tablename = 'test_data_table'
-							OR tablename = 'attr_test_entity_test_attribute')
+							OR tablename = 'attr_test_entity_test_attribute'
+							OR tablename IN (SELECT ea.table_name FROM entity_attributes ea WHERE ea.entity_id LIKE 'test_%'))
 					LOOP 
 						EXECUTE 'DROP TABLE IF EXISTS ' || quote_ident(table_name) || ' CASCADE';
 					END LOOP;
 				END $$;
 				
-				-- Clean up test entity_attributes entries
+				-- Clean up the schemas of test attribute tables and the test entity_attributes entries
+				DELETE FROM attribute_schemas WHERE table_name IN (SELECT table_name FROM entity_attributes WHERE entity_id LIKE 'test_%');
 				DELETE FROM entity_attributes WHERE entity_id LIKE 'test_%' OR entity_id = 'test_entity';
 				
 				-- Clean up test attribute_schemas entries  
@@ -646,3 +651,218 @@ func TestGetDataTabularFormat(t *testing.T) {
 	assert.Len(t, filteredRows, 1)
 	assert.Equal(t, expectedRows[0], filteredRows[0].([]interface{}))
 }
+
+func TestAttributeTableNamesDoNotCollide(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	// Both ids sanitize to the same string, which used to put their rows in one table
+	suffix := time.Now().UnixNano()
+	entities := []string{fmt.Sprintf("test_A-1/x_%d", suffix), fmt.Sprintf("test_a_1_x_%d", suffix)}
+	for i, entityID := range entities {
+		rows := make([][]interface{}, i+1)
+		for j := range rows {
+			rows[j] = []interface{}{2020 + j, 10.5}
+		}
+		data, err := createTabularDataStruct([]string{"year", "amount"}, rows)
+		require.NoError(t, err)
+		schemaInfo, err := schema.GenerateSchema(data)
+		require.NoError(t, err)
+		require.NoError(t, repo.HandleTabularData(ctx, entityID, "budget", &pb.TimeBasedValue{Value: data}, schemaInfo))
+	}
+
+	for i, entityID := range entities {
+		tableName, err := repo.AttributeTable(ctx, entityID, "budget")
+		require.NoError(t, err)
+		assert.Equal(t, repository.AttributeTableName(entityID, "budget"), tableName)
+		assert.LessOrEqual(t, len(tableName), 63)
+
+		var count int
+		require.NoError(t, repo.DB().QueryRowContext(ctx, fmt.Sprintf("SELECT count(*) FROM %s", tableName)).Scan(&count))
+		assert.Equal(t, i+1, count)
+	}
+
+	_, err := repo.AttributeTable(ctx, entities[0], "missing")
+	assert.Error(t, err)
+}
+
+func TestMigrateAttributeTableNames(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	// Two attributes sharing a table under the old naming scheme
+	suffix := time.Now().UnixNano()
+	legacy := fmt.Sprintf("attr_test_legacy_%d", suffix)
+	require.NoError(t, repo.CreateDynamicTable(ctx, legacy, []Column{{Name: "amount", Type: "INTEGER NULL"}}))
+	_, err := repo.DB().ExecContext(ctx, `INSERT INTO attribute_schemas (table_name, schema_version, schema_definition) VALUES ($1, 1, '{}')`, legacy)
+	require.NoError(t, err)
+
+	entities := []string{fmt.Sprintf("test_legacy_%d", suffix), fmt.Sprintf("test-legacy-%d", suffix)}
+	for i, entityID := range entities {
+		var attributeID int
+		require.NoError(t, repo.DB().QueryRowContext(ctx,
+			`INSERT INTO entity_attributes (entity_id, attribute_name, table_name) VALUES ($1, 'budget', $2) RETURNING id`,
+			entityID, legacy).Scan(&attributeID))
+		rows := make([][]interface{}, i+1)
+		for j := range rows {
+			rows[j] = []interface{}{j}
+		}
+		require.NoError(t, repo.InsertTabularData(ctx, legacy, attributeID, []string{"amount"}, rows))
+	}
+
+	migrated, err := repo.MigrateAttributeTableNames(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, migrated, 2)
+
+	for i, entityID := range entities {
+		tableName, err := repo.AttributeTable(ctx, entityID, "budget")
+		require.NoError(t, err)
+		assert.Equal(t, repository.AttributeTableName(entityID, "budget"), tableName)
+
+		var count int
+		require.NoError(t, repo.DB().QueryRowContext(ctx, fmt.Sprintf("SELECT count(*) FROM %s", tableName)).Scan(&count))
+		assert.Equal(t, i+1, count)
+		_, err = GetSchemaOfTable(ctx, repo, tableName)
+		assert.NoError(t, err)
+	}
+	exists, err := repo.TableExists(ctx, legacy)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Running it again finds nothing left to move
+	_, err = repo.MigrateAttributeTableNames(ctx)
+	assert.NoError(t, err)
+}
+
+func TestTabularColumnNamesAndOrder(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	entityID := fmt.Sprintf("test_columns_%d", time.Now().UnixNano())
+	columns := []string{"Zone", "id", "Amount (LKR)", "amount_lkr_", "created_at"}
+	data, err := createTabularDataStruct(columns, [][]interface{}{
+		{"West", "W-1", 10, 11, "2024-01-01T00:00:00Z"},
+		{"East", "E-1", 20, 21, "2024-02-01T00:00:00Z"},
+	})
+	require.NoError(t, err)
+	schemaInfo, err := schema.GenerateSchema(data)
+	require.NoError(t, err)
+	require.NoError(t, repo.HandleTabularData(ctx, entityID, "budget", &pb.TimeBasedValue{Value: data}, schemaInfo))
+
+	tableName, err := repo.AttributeTable(ctx, entityID, "budget")
+	require.NoError(t, err)
+	stored, err := GetSchemaOfTable(ctx, repo, tableName)
+	require.NoError(t, err)
+	require.Len(t, stored.Columns, len(columns))
+	for i, column := range stored.Columns {
+		assert.Equal(t, columns[i], column.Name)
+		assert.Equal(t, i, column.Ordinal)
+	}
+
+	read := func(filters map[string]interface{}, fields ...string) TabularData {
+		anyData, err := repo.GetData(ctx, tableName, filters, fields...)
+		require.NoError(t, err)
+		var structValue structpb.Struct
+		require.NoError(t, anyData.UnmarshalTo(&structValue))
+		var result TabularData
+		require.NoError(t, json.Unmarshal([]byte(structValue.Fields["data"].GetStringValue()), &result))
+		return result
+	}
+
+	all := read(nil)
+	assert.Equal(t, columns, all.Columns)
+	require.Len(t, all.Rows, 2)
+	assert.Equal(t, "W-1", all.Rows[0][1])
+
+	selected := read(map[string]interface{}{"id": "E-1"}, "Amount (LKR)", "Zone")
+	assert.Equal(t, []string{"Amount (LKR)", "Zone"}, selected.Columns)
+	assert.Equal(t, [][]interface{}{{float64(20), "East"}}, selected.Rows)
+}
+
+func TestGetTypedData(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	entityID := fmt.Sprintf("test_typed_%d", time.Now().UnixNano())
+	data, err := createTabularDataStruct([]string{"id", "year", "amount", "approved", "issued"}, [][]interface{}{
+		{"A-1", 2024, 1200.5, true, "2024-01-15T00:00:00Z"},
+		{"A-2", 2025, 800.25, false, "2025-02-15T00:00:00Z"},
+	})
+	require.NoError(t, err)
+	schemaInfo, err := schema.GenerateSchema(data)
+	require.NoError(t, err)
+	require.NoError(t, repo.HandleTabularData(ctx, entityID, "grants", &pb.TimeBasedValue{Value: data}, schemaInfo))
+
+	tableName, err := repo.AttributeTable(ctx, entityID, "grants")
+	require.NoError(t, err)
+	typed, err := repo.GetTypedData(ctx, tableName, repository.TabularQuery{
+		Filters: map[string]interface{}{"id": "A-1"},
+		Mask:    repository.ColumnMask{Redact: []string{"amount"}},
+	})
+	require.NoError(t, err)
+
+	var columns []string
+	for _, column := range typed.Columns {
+		columns = append(columns, column.Name+":"+column.Type)
+	}
+	assert.Equal(t, []string{"id:string", "year:int", "amount:float", "approved:bool", "issued:datetime"}, columns)
+	require.Len(t, typed.Rows, 1)
+	row := typed.Rows[0].Values
+	assert.Equal(t, "A-1", row[0].GetStringValue())
+	assert.Equal(t, int64(2024), row[1].GetIntValue())
+	assert.Nil(t, row[2].GetValue(), "redacted cells are null")
+	assert.True(t, row[3].GetBoolValue())
+	assert.True(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC).Equal(row[4].GetTimeValue().AsTime()))
+
+	// Pages follow the order the rows were written in
+	for offset, want := range []string{"A-1", "A-2"} {
+		page, err := repo.GetTypedData(ctx, tableName, repository.TabularQuery{Limit: 1, Offset: offset})
+		require.NoError(t, err)
+		require.Len(t, page.Rows, 1)
+		assert.Equal(t, want, page.Rows[0].Values[0].GetStringValue())
+	}
+	page, err := repo.GetTypedData(ctx, tableName, repository.TabularQuery{Limit: 1, Offset: 2})
+	require.NoError(t, err)
+	assert.Empty(t, page.Rows)
+
+	count, err := repo.CountData(ctx, tableName, repository.TabularQuery{Filters: map[string]interface{}{"id": "A-2"}, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	_, err = repo.CountData(ctx, tableName, repository.TabularQuery{Filters: map[string]interface{}{"amount": 800.25}, Mask: repository.ColumnMask{Redact: []string{"amount"}}})
+	assert.Error(t, err, "filters on masked columns are refused")
+}
+
+func TestDecimalAndBigIntColumns(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	entityID := fmt.Sprintf("test_decimal_%d", time.Now().UnixNano())
+	data, err := createTabularDataStruct([]string{"account", "budget"}, [][]interface{}{
+		{"9007199254740993", "123456789012345678.05"},
+		{4102444800000, "1200.5"},
+	})
+	require.NoError(t, err)
+	schemaInfo, err := schema.GenerateSchema(data)
+	require.NoError(t, err)
+	require.NoError(t, repo.HandleTabularData(ctx, entityID, "budget", &pb.TimeBasedValue{Value: data}, schemaInfo))
+
+	tableName, err := repo.AttributeTable(ctx, entityID, "budget")
+	require.NoError(t, err)
+	typed, err := repo.GetTypedData(ctx, tableName, repository.TabularQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, "bigint", typed.Columns[0].Type)
+	assert.Equal(t, "decimal", typed.Columns[1].Type)
+	assert.Equal(t, int32(38), typed.Columns[1].Precision)
+	assert.Equal(t, int32(2), typed.Columns[1].Scale)
+	require.Len(t, typed.Rows, 2)
+	assert.Equal(t, int64(9007199254740993), typed.Rows[0].Values[0].GetIntValue())
+	assert.Equal(t, "123456789012345678.05", typed.Rows[0].Values[1].GetDecimalValue())
+	assert.Equal(t, "1200.50", typed.Rows[1].Values[1].GetDecimalValue())
+
+	// Values the column would have to round are refused
+	rounded, err := createTabularDataStruct([]string{"account", "budget"}, [][]interface{}{{1, "10.125"}})
+	require.NoError(t, err)
+	roundedSchema, err := schema.GenerateSchema(rounded)
+	require.NoError(t, err)
+	assert.Error(t, repo.HandleTabularData(ctx, entityID, "budget", &pb.TimeBasedValue{Value: rounded}, roundedSchema))
+}
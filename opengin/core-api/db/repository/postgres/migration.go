@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"lk/datafoundation/core-api/db/repository"
+	"lk/datafoundation/core-api/pkg/metrics"
+	"lk/datafoundation/core-api/pkg/tracing"
+
+	"github.com/lib/pq"
+)
+
+// Attribute tables used to be named attr_<entity id>_<attribute name> with both parts passed through
+// commons.SanitizeIdentifier, so ids such as A-1/x and a_1_x shared a table and long ids were truncated
+// by PostgreSQL. MigrateAttributeTableNames moves every table to the name repository.AttributeTableName
+// gives it. Tables shared by several attributes are split, each attribute taking its own rows along.
+
+// staleAttribute is an entity_attributes row whose table is not named by repository.AttributeTableName
+type staleAttribute struct {
+	id            int
+	entityID      string
+	attributeName string
+	tableName     string
+}
+
+// MigrateAttributeTableNames renames the attribute tables of the call's tenant that still have the old
+// sanitized names and returns how many attributes were moved. It is run by InitializeTables and can be
+// run again at any time; attributes already under their new name are left alone.
+func (r *PostgresRepository) MigrateAttributeTableNames(ctx context.Context) (_ int, err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "MigrateAttributeTableNames", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "MigrateAttributeTableNames")
+	defer tracing.End(span, &err)
+	db, err := r.tenantDB(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return migrateAttributeTableNames(ctx, db)
+}
+
+// migrateAttributeTableNames renames the attribute tables of the schema the pool resolves table names in
+func migrateAttributeTableNames(ctx context.Context, db *sql.DB) (int, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	// Servers starting together would otherwise both try to rename the same tables
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE entity_attributes IN EXCLUSIVE MODE`); err != nil {
+		return 0, fmt.Errorf("error locking entity_attributes: %v", err)
+	}
+
+	stale, owners, err := staleAttributes(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	for _, attribute := range stale {
+		var exists bool
+		if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM pg_tables WHERE schemaname = current_schema() AND tablename = $1)`,
+			attribute.tableName).Scan(&exists); err != nil {
+			return 0, fmt.Errorf("error looking up table %s: %v", attribute.tableName, err)
+		}
+		if !exists {
+			// Left for the consistency checker to report as a missing attribute table
+			slog.WarnContext(ctx, "Attribute table to rename does not exist", "table", attribute.tableName, "entity_id", attribute.entityID, "attribute", attribute.attributeName)
+			continue
+		}
+
+		target := repository.AttributeTableName(attribute.entityID, attribute.attributeName)
+		if owners[attribute.tableName] > 1 {
+			err = splitAttributeTable(ctx, tx, attribute, target)
+		} else {
+			err = renameAttributeTable(ctx, tx, attribute.tableName, target)
+		}
+		if err != nil {
+			return 0, err
+		}
+		owners[attribute.tableName]--
+
+		if _, err := tx.ExecContext(ctx, `UPDATE entity_attributes SET table_name = $1 WHERE id = $2`, target, attribute.id); err != nil {
+			return 0, fmt.Errorf("error recording table %s of attribute %s of entity %s: %v", target, attribute.attributeName, attribute.entityID, err)
+		}
+		slog.InfoContext(ctx, "Renamed attribute table", "from", attribute.tableName, "to", target, "entity_id", attribute.entityID, "attribute", attribute.attributeName)
+		migrated++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error committing table renames: %v", err)
+	}
+	return migrated, nil
+}
+
+// staleAttributes returns the attributes whose table has an old name, in the order they were created,
+// and how many attributes refer to each table
+func staleAttributes(ctx context.Context, tx *sql.Tx) ([]staleAttribute, map[string]int, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT id, entity_id, attribute_name, table_name FROM entity_attributes ORDER BY id`)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing entity attributes: %v", err)
+	}
+	defer rows.Close()
+
+	var stale []staleAttribute
+	owners := make(map[string]int)
+	for rows.Next() {
+		var attribute staleAttribute
+		if err := rows.Scan(&attribute.id, &attribute.entityID, &attribute.attributeName, &attribute.tableName); err != nil {
+			return nil, nil, fmt.Errorf("error scanning entity attribute: %v", err)
+		}
+		owners[attribute.tableName]++
+		if attribute.tableName != repository.AttributeTableName(attribute.entityID, attribute.attributeName) {
+			stale = append(stale, attribute)
+		}
+	}
+	return stale, owners, rows.Err()
+}
+
+// renameAttributeTable renames the table of a single attribute along with its schema records
+func renameAttributeTable(ctx context.Context, tx *sql.Tx, table, target string) error {
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %s RENAME TO %s`, pq.QuoteIdentifier(table), target)); err != nil {
+		slog.ErrorContext(ctx, "Error renaming attribute table", "table", table, "target", target, "error", err)
+		return fmt.Errorf("error renaming %s to %s: %v", table, target, err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE attribute_schemas SET table_name = $1 WHERE table_name = $2`, target, table); err != nil {
+		return fmt.Errorf("error renaming schemas of %s: %v", table, err)
+	}
+	return nil
+}
+
+// splitAttributeTable moves the rows of one of the attributes sharing a table into a table of its own.
+// The new table has the same columns and a sequence of its own, and gets a copy of the schema records.
+func splitAttributeTable(ctx context.Context, tx *sql.Tx, attribute staleAttribute, target string) error {
+	table := pq.QuoteIdentifier(attribute.tableName)
+	sequence := target + "_id_seq"
+	statements := []string{
+		fmt.Sprintf(`CREATE TABLE %s (LIKE %s INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING INDEXES)`, target, table),
+		fmt.Sprintf(`CREATE SEQUENCE %s OWNED BY %s.id`, sequence, target),
+		fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN id SET DEFAULT nextval('%s')`, target, sequence),
+		fmt.Sprintf(`ALTER TABLE %s ADD FOREIGN KEY (entity_attribute_id) REFERENCES entity_attributes(id)`, target),
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			slog.ErrorContext(ctx, "Error splitting attribute table", "table", attribute.tableName, "target", target, "error", err)
+			return fmt.Errorf("error creating %s from %s: %v", target, attribute.tableName, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO %s SELECT * FROM %s WHERE entity_attribute_id = $1`, target, table), attribute.id); err != nil {
+		return fmt.Errorf("error copying rows of %s to %s: %v", attribute.tableName, target, err)
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE entity_attribute_id = $1`, table), attribute.id); err != nil {
+		return fmt.Errorf("error removing moved rows from %s: %v", attribute.tableName, err)
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`SELECT setval('%[1]s', COALESCE(MAX(id), 1), MAX(id) IS NOT NULL) FROM %[2]s`, sequence, target)); err != nil {
+		return fmt.Errorf("error resetting sequence of %s: %v", target, err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO attribute_schemas (table_name, schema_version, schema_definition)
+		SELECT $1, schema_version, schema_definition FROM attribute_schemas WHERE table_name = $2`,
+		target, attribute.tableName); err != nil {
+		return fmt.Errorf("error copying schemas of %s to %s: %v", attribute.tableName, target, err)
+	}
+	return nil
+}
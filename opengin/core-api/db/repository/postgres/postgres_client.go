@@ -7,7 +7,9 @@ import (
 	"strings"
 	"time"
 
+	"lk/datafoundation/core-api/pkg/metrics"
 	"lk/datafoundation/core-api/pkg/schema"
+	"lk/datafoundation/core-api/pkg/tracing"
 
 	_ "github.com/lib/pq"
 )
@@ -20,11 +22,39 @@ type Config struct {
 	Password string
 	DBName   string
 	SSLMode  string
+
+	// Connection pool, the defaults apply when zero
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+
+	// Pools of the tenants other than the default one: at most MaxTenantPools are open at a time,
+	// each with at most TenantMaxOpenConns connections, and a pool unused for TenantPoolIdleTimeout
+	// is closed. The defaults apply when zero.
+	MaxTenantPools        int
+	TenantMaxOpenConns    int
+	TenantPoolIdleTimeout time.Duration
 }
 
+// Default connection pool settings. With them the service opens at most
+// DefaultMaxOpenConns + DefaultMaxTenantPools*DefaultTenantMaxOpenConns connections.
+const (
+	DefaultMaxOpenConns          = 25
+	DefaultMaxIdleConns          = 25
+	DefaultConnMaxLifetime       = 5 * time.Minute
+	DefaultMaxTenantPools        = 10
+	DefaultTenantMaxOpenConns    = 5
+	DefaultTenantPoolIdleTimeout = 5 * time.Minute
+)
+
 // PostgresRepository represents a PostgreSQL database repository
 type PostgresRepository struct {
 	db *sql.DB
+
+	// The pools of tenants other than the default one, opened on first use
+	dsn     string
+	config  Config
+	tenants *tenantPools
 }
 
 // NewPostgresRepository creates a new PostgreSQL repository
@@ -32,35 +62,65 @@ func NewPostgresRepository(cfg Config) (*PostgresRepository, error) {
 	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode)
 
-	return NewPostgresRepositoryFromDSN(dsn)
+	return openPostgresRepository(dsn, cfg)
 }
 
 // NewPostgresRepositoryFromDSN creates a new PostgreSQL repository from a connection string
 func NewPostgresRepositoryFromDSN(dsn string) (*PostgresRepository, error) {
+	return openPostgresRepository(dsn, Config{})
+}
+
+func openPostgresRepository(dsn string, cfg Config) (*PostgresRepository, error) {
+	db, err := openDB(dsn, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &PostgresRepository{db: db, dsn: dsn, config: cfg, tenants: newTenantPools(cfg)}, nil
+}
+
+// openDB opens a connection pool with the pool settings of cfg
+func openDB(dsn string, cfg Config) (*sql.DB, error) {
 	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("error opening database: %v", err)
 	}
 
 	// Set connection pool settings
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(25)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	maxOpenConns, maxIdleConns, connMaxLifetime := DefaultMaxOpenConns, DefaultMaxIdleConns, DefaultConnMaxLifetime
+	if cfg.MaxOpenConns > 0 {
+		maxOpenConns = cfg.MaxOpenConns
+	}
+	if cfg.MaxIdleConns > 0 {
+		maxIdleConns = cfg.MaxIdleConns
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		connMaxLifetime = cfg.ConnMaxLifetime
+	}
+	db.SetMaxOpenConns(maxOpenConns)
+	db.SetMaxIdleConns(maxIdleConns)
+	db.SetConnMaxLifetime(connMaxLifetime)
 
 	// Test the connection
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("error connecting to the database: %v", err)
 	}
 
-	return &PostgresRepository{db: db}, nil
+	return db, nil
 }
 
-// Close closes the database connection
+// Close closes the connection pools of every tenant
 func (r *PostgresRepository) Close() error {
+	r.tenants.closeAll()
 	return r.db.Close()
 }
 
-// DB returns the underlying *sql.DB instance
+// Ping checks that the database can be reached
+func (r *PostgresRepository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+// DB returns the connection pool of the default tenant
 func (r *PostgresRepository) DB() *sql.DB {
 	return r.db
 }
@@ -80,8 +140,25 @@ func (r *PostgresRepository) DB() *sql.DB {
 // - Separation of metadata (in this table) from the actual attribute values (in dynamic tables)
 //
 // Each attribute's actual data is stored in a separate dynamic table (named in table_name)
-// which is created on-demand when new attributes are added to an entity.
-func (r *PostgresRepository) InitializeTables(ctx context.Context) error {
+// which is created on-demand when new attributes are added to an entity. Tables still carrying
+// the names used before repository.AttributeTableName are renamed, see MigrateAttributeTableNames.
+func (r *PostgresRepository) InitializeTables(ctx context.Context) (err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "InitializeTables", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "InitializeTables")
+	defer tracing.End(span, &err)
+	db, err := r.tenantDB(ctx)
+	if err != nil {
+		return err
+	}
+	if err := createCoreTables(ctx, db); err != nil {
+		return err
+	}
+	_, err = migrateAttributeTableNames(ctx, db)
+	return err
+}
+
+// createCoreTables creates the bookkeeping tables in the schema the pool resolves table names in
+func createCoreTables(ctx context.Context, db *sql.DB) error {
 	// Create entity_attributes table
 	entityAttributesSQL := `
 	CREATE TABLE IF NOT EXISTS entity_attributes (
@@ -115,11 +192,11 @@ func (r *PostgresRepository) InitializeTables(ctx context.Context) error {
 	);`
 
 	// Execute the creation queries
-	if _, err := r.db.ExecContext(ctx, entityAttributesSQL); err != nil {
+	if _, err := db.ExecContext(ctx, entityAttributesSQL); err != nil {
 		return fmt.Errorf("error creating entity_attributes table: %v", err)
 	}
 
-	if _, err := r.db.ExecContext(ctx, attributeSchemasSQL); err != nil {
+	if _, err := db.ExecContext(ctx, attributeSchemasSQL); err != nil {
 		return fmt.Errorf("error creating attribute_schemas table: %v", err)
 	}
 
@@ -127,16 +204,23 @@ func (r *PostgresRepository) InitializeTables(ctx context.Context) error {
 }
 
 // TableExists checks if a table exists in the database
-func (r *PostgresRepository) TableExists(ctx context.Context, tableName string) (bool, error) {
+func (r *PostgresRepository) TableExists(ctx context.Context, tableName string) (_ bool, err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "TableExists", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "TableExists")
+	defer tracing.End(span, &err)
 	query := `
 	SELECT EXISTS (
 		SELECT FROM pg_tables
-		WHERE schemaname = 'public'
+		WHERE schemaname = current_schema()
 		AND tablename = $1
 	);`
 
+	db, err := r.tenantDB(ctx)
+	if err != nil {
+		return false, err
+	}
 	var exists bool
-	err := r.db.QueryRowContext(ctx, query, tableName).Scan(&exists)
+	err = db.QueryRowContext(ctx, query, tableName).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("error checking table existence: %v", err)
 	}
@@ -145,7 +229,10 @@ func (r *PostgresRepository) TableExists(ctx context.Context, tableName string)
 }
 
 // CreateDynamicTable creates a new table for storing attribute data
-func (r *PostgresRepository) CreateDynamicTable(ctx context.Context, tableName string, columns []Column) error {
+func (r *PostgresRepository) CreateDynamicTable(ctx context.Context, tableName string, columns []Column) (err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "CreateDynamicTable", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "CreateDynamicTable")
+	defer tracing.End(span, &err)
 	// Build column definitions
 	var columnDefs []string
 
@@ -168,7 +255,11 @@ func (r *PostgresRepository) CreateDynamicTable(ctx context.Context, tableName s
 	);`, tableName, strings.Join(columnDefs, ",\n"))
 
 	// Execute the creation query
-	if _, err := r.db.ExecContext(ctx, createTableSQL); err != nil {
+	db, err := r.tenantDB(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := db.ExecContext(ctx, createTableSQL); err != nil {
 		return fmt.Errorf("error creating dynamic table: %v", err)
 	}
 
@@ -176,7 +267,10 @@ func (r *PostgresRepository) CreateDynamicTable(ctx context.Context, tableName s
 }
 
 // InsertTabularData inserts rows into a dynamic table
-func (r *PostgresRepository) InsertTabularData(ctx context.Context, tableName string, entityAttributeID int, columns []string, rows [][]interface{}) error {
+func (r *PostgresRepository) InsertTabularData(ctx context.Context, tableName string, entityAttributeID int, columns []string, rows [][]interface{}) (err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "InsertTabularData", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "InsertTabularData")
+	defer tracing.End(span, &err)
 	// Build the INSERT query
 	columnNames := append([]string{"entity_attribute_id"}, columns...)
 	placeholders := make([]string, len(rows))
@@ -205,7 +299,11 @@ func (r *PostgresRepository) InsertTabularData(ctx context.Context, tableName st
 	}
 
 	// Execute the query
-	_, err := r.db.ExecContext(ctx, query, values...)
+	db, err := r.tenantDB(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, query, values...)
 	if err != nil {
 		return fmt.Errorf("error inserting data: %v", err)
 	}
@@ -214,11 +312,17 @@ func (r *PostgresRepository) InsertTabularData(ctx context.Context, tableName st
 }
 
 // GetTableList retrieves a list of attribute tables for a given entity ID.
-func (r *PostgresRepository) GetTableList(ctx context.Context, entityID string) ([]string, error) {
+func (r *PostgresRepository) GetTableList(ctx context.Context, entityID string) (_ []string, err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "GetTableList", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "GetTableList")
+	defer tracing.End(span, &err)
 	return GetTableList(ctx, r, entityID)
 }
 
 // GetSchemaOfTable retrieves the schema for a given attribute table.
-func (r *PostgresRepository) GetSchemaOfTable(ctx context.Context, tableName string) (*schema.SchemaInfo, error) {
+func (r *PostgresRepository) GetSchemaOfTable(ctx context.Context, tableName string) (_ *schema.SchemaInfo, err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "GetSchemaOfTable", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "GetSchemaOfTable")
+	defer tracing.End(span, &err)
 	return GetSchemaOfTable(ctx, r, tableName)
 }
@@ -7,7 +7,7 @@ import (
 	"testing"
 	"time"
 
-	commons "lk/datafoundation/core-api/commons"
+	"lk/datafoundation/core-api/db/repository"
 	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
 	schema "lk/datafoundation/core-api/pkg/schema"
 	"lk/datafoundation/core-api/pkg/typeinference"
@@ -437,9 +437,9 @@ func TestInsertSampleData(t *testing.T) {
 			assert.NoError(t, err, "Failed to handle attributes")
 
 			// Verify table exists
-			tableName := fmt.Sprintf("attr_%s_%s",
-				commons.SanitizeIdentifier(tt.entityID),
-				commons.SanitizeIdentifier(tt.attrName))
+			tableName, err := repo.AttributeTable(ctx, tt.entityID, tt.attrName)
+			assert.NoError(t, err, "Failed to resolve table")
+			assert.Equal(t, repository.AttributeTableName(tt.entityID, tt.attrName), tableName)
 			exists, err := repo.TableExists(ctx, tableName)
 			assert.NoError(t, err, "Failed to check table existence")
 			assert.True(t, exists, "Table should exist")
@@ -484,32 +484,37 @@ func TestQuerySampleData(t *testing.T) {
 	// Test queries for different tables
 	queries := []struct {
 		name       string
-		tableName  string
+		entityID   string
+		attrName   string
 		query      string
 		expectRows bool
 	}{
 		{
 			name:       "Query Employee Salaries",
-			tableName:  "attr_emp_data_employee_records",
-			query:      "SELECT name, salary FROM attr_emp_data_employee_records WHERE salary > 70000",
+			entityID:   "emp_data",
+			attrName:   "employee_records",
+			query:      "SELECT name, salary FROM %s WHERE salary > 70000",
 			expectRows: true,
 		},
 		{
 			name:       "Query Active Employees",
-			tableName:  "attr_emp_data_employee_records",
-			query:      "SELECT name FROM attr_emp_data_employee_records WHERE is_active = true",
+			entityID:   "emp_data",
+			attrName:   "employee_records",
+			query:      "SELECT name FROM %s WHERE is_active = true",
 			expectRows: true,
 		},
 		{
 			name:       "Query Product Stock",
-			tableName:  "attr_inventory_product_stock",
-			query:      "SELECT name, quantity FROM attr_inventory_product_stock WHERE quantity > 100",
+			entityID:   "inventory",
+			attrName:   "product_stock",
+			query:      "SELECT name, quantity FROM %s WHERE quantity > 100",
 			expectRows: true,
 		},
 		{
 			name:       "Query Sensor Temperature",
-			tableName:  "attr_sensor_data_temperature_readings",
-			query:      "SELECT location, temperature FROM attr_sensor_data_temperature_readings WHERE temperature > 23",
+			entityID:   "sensor_data",
+			attrName:   "temperature_readings",
+			query:      "SELECT location, temperature FROM %s WHERE temperature > 23",
 			expectRows: true,
 		},
 	}
@@ -517,14 +522,13 @@ func TestQuerySampleData(t *testing.T) {
 	for _, tt := range queries {
 		t.Run(tt.name, func(t *testing.T) {
 			// Check if table exists
-			exists, err := repo.TableExists(ctx, tt.tableName)
-			if !exists {
-				t.Skipf("Skipping test: table %s does not exist", tt.tableName)
+			tableName, err := repo.AttributeTable(ctx, tt.entityID, tt.attrName)
+			if err != nil {
+				t.Skipf("Skipping test: attribute %s of %s does not exist", tt.attrName, tt.entityID)
 			}
-			assert.NoError(t, err, "Failed to check table existence")
 
 			// Execute query
-			rows, err := repo.DB().QueryContext(ctx, tt.query)
+			rows, err := repo.DB().QueryContext(ctx, fmt.Sprintf(tt.query, tableName))
 			assert.NoError(t, err, "Failed to execute query")
 			defer rows.Close()
 
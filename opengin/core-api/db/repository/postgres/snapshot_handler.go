@@ -0,0 +1,295 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"lk/datafoundation/core-api/pkg/metrics"
+	"lk/datafoundation/core-api/pkg/tracing"
+)
+
+// CoreTables are the bookkeeping tables created by InitializeTables, in the order they must be restored
+var CoreTables = []string{"entity_attributes", "attribute_schemas"}
+
+// AttributeTablePrefix is the prefix of the dynamic tables holding attribute data
+const AttributeTablePrefix = "attr_"
+
+// tableNamePattern matches the table names the snapshot functions accept
+var tableNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// TableColumn describes a column of a dynamic attribute table
+type TableColumn struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	NotNull    bool   `json:"notNull,omitempty"`
+	Default    string `json:"default,omitempty"`
+	PrimaryKey bool   `json:"primaryKey,omitempty"`
+	References string `json:"references,omitempty"`
+	Serial     bool   `json:"serial,omitempty"`
+}
+
+// TableDefinition is the structure of a dynamic attribute table
+type TableDefinition struct {
+	Name    string        `json:"name"`
+	Columns []TableColumn `json:"columns"`
+}
+
+// BeginSnapshot starts a read-only repeatable read transaction so every table is read at the same point in time
+func (r *PostgresRepository) BeginSnapshot(ctx context.Context) (_ *sql.Tx, err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "BeginSnapshot", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "BeginSnapshot")
+	defer tracing.End(span, &err)
+	db, err := r.tenantDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("error starting snapshot transaction: %v", err)
+	}
+	return tx, nil
+}
+
+// ListAttributeTables returns every dynamic attribute table of the call's tenant
+func (r *PostgresRepository) ListAttributeTables(ctx context.Context, tx *sql.Tx) (_ []string, err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "ListAttributeTables", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "ListAttributeTables")
+	defer tracing.End(span, &err)
+	rows, err := tx.QueryContext(ctx, `
+		SELECT tablename FROM pg_tables
+		WHERE schemaname = current_schema() AND tablename LIKE $1
+		ORDER BY tablename`, strings.ReplaceAll(AttributeTablePrefix, "_", `\_`)+"%")
+	if err != nil {
+		return nil, fmt.Errorf("error listing attribute tables: %v", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, fmt.Errorf("error scanning table name: %v", err)
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+// GetTableDefinition reads the columns of a table from the catalog
+func (r *PostgresRepository) GetTableDefinition(ctx context.Context, tx *sql.Tx, table string) (_ *TableDefinition, err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "GetTableDefinition", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "GetTableDefinition")
+	defer tracing.End(span, &err)
+	if !tableNamePattern.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name %q", table)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT a.attname,
+		       format_type(a.atttypid, a.atttypmod),
+		       a.attnotnull,
+		       COALESCE(pg_get_expr(d.adbin, d.adrelid), ''),
+		       EXISTS (
+		           SELECT 1 FROM pg_index i
+		           WHERE i.indrelid = a.attrelid AND i.indisprimary AND a.attnum = ANY(i.indkey)
+		       ),
+		       COALESCE((
+		           SELECT c.confrelid::regclass::text || '(' || fa.attname || ')'
+		           FROM pg_constraint c
+		           JOIN pg_attribute fa ON fa.attrelid = c.confrelid AND fa.attnum = c.confkey[1]
+		           WHERE c.conrelid = a.attrelid AND c.contype = 'f' AND c.conkey[1] = a.attnum
+		           LIMIT 1
+		       ), '')
+		FROM pg_attribute a
+		LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE a.attrelid = $1::regclass AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY a.attnum`, table)
+	if err != nil {
+		return nil, fmt.Errorf("error reading columns of %s: %v", table, err)
+	}
+	defer rows.Close()
+
+	definition := &TableDefinition{Name: table}
+	for rows.Next() {
+		var column TableColumn
+		if err := rows.Scan(&column.Name, &column.Type, &column.NotNull, &column.Default, &column.PrimaryKey, &column.References); err != nil {
+			return nil, fmt.Errorf("error scanning column of %s: %v", table, err)
+		}
+		// Sequences are recreated by SERIAL columns, so the nextval default is not kept
+		if strings.HasPrefix(column.Default, "nextval(") {
+			column.Serial = true
+			column.Default = ""
+		}
+		definition.Columns = append(definition.Columns, column)
+	}
+	return definition, rows.Err()
+}
+
+// ExportTableRows streams every row of a table as a JSON object
+func (r *PostgresRepository) ExportTableRows(ctx context.Context, tx *sql.Tx, table string, fn func(row []byte) error) (err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "ExportTableRows", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "ExportTableRows")
+	defer tracing.End(span, &err)
+	if !tableNamePattern.MatchString(table) {
+		return fmt.Errorf("invalid table name %q", table)
+	}
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`SELECT row_to_json(t)::text FROM %s t`, table))
+	if err != nil {
+		return fmt.Errorf("error reading rows of %s: %v", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row []byte
+		if err := rows.Scan(&row); err != nil {
+			return fmt.Errorf("error scanning row of %s: %v", table, err)
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// CreateTableFromDefinition recreates a dynamic attribute table from its definition
+func (r *PostgresRepository) CreateTableFromDefinition(ctx context.Context, definition *TableDefinition) (err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "CreateTableFromDefinition", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "CreateTableFromDefinition")
+	defer tracing.End(span, &err)
+	if !tableNamePattern.MatchString(definition.Name) {
+		return fmt.Errorf("invalid table name %q", definition.Name)
+	}
+
+	var columnDefs []string
+	var primaryKey []string
+	for _, column := range definition.Columns {
+		if !tableNamePattern.MatchString(column.Name) {
+			return fmt.Errorf("invalid column name %q in %s", column.Name, definition.Name)
+		}
+
+		columnType := column.Type
+		if column.Serial {
+			switch columnType {
+			case "bigint":
+				columnType = "BIGSERIAL"
+			case "smallint":
+				columnType = "SMALLSERIAL"
+			default:
+				columnType = "SERIAL"
+			}
+		}
+
+		columnDef := column.Name + " " + columnType
+		if column.NotNull && !column.PrimaryKey {
+			columnDef += " NOT NULL"
+		}
+		if column.Default != "" {
+			columnDef += " DEFAULT " + column.Default
+		}
+		if column.References != "" {
+			columnDef += " REFERENCES " + column.References
+		}
+		columnDefs = append(columnDefs, columnDef)
+
+		if column.PrimaryKey {
+			primaryKey = append(primaryKey, column.Name)
+		}
+	}
+	if len(primaryKey) > 0 {
+		columnDefs = append(columnDefs, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(primaryKey, ", ")))
+	}
+
+	createTableSQL := fmt.Sprintf("CREATE TABLE %s (\n\t%s\n);", definition.Name, strings.Join(columnDefs, ",\n\t"))
+	db, err := r.tenantDB(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := db.ExecContext(ctx, createTableSQL); err != nil {
+		slog.ErrorContext(ctx, "Error creating table", "table", definition.Name, "error", err)
+		return fmt.Errorf("error creating table %s: %v", definition.Name, err)
+	}
+	return nil
+}
+
+// RestoreTableRows inserts a batch of JSON rows, letting PostgreSQL map the JSON fields back to column types
+func (r *PostgresRepository) RestoreTableRows(ctx context.Context, table string, rows [][]byte) (err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "RestoreTableRows", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "RestoreTableRows")
+	defer tracing.End(span, &err)
+	if !tableNamePattern.MatchString(table) {
+		return fmt.Errorf("invalid table name %q", table)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	batch := make([]json.RawMessage, len(rows))
+	for i, row := range rows {
+		batch[i] = json.RawMessage(row)
+	}
+	payload, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("error encoding rows for %s: %v", table, err)
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %[1]s SELECT * FROM json_populate_recordset(NULL::%[1]s, $1::json)`, table)
+	db, err := r.tenantDB(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := db.ExecContext(ctx, query, string(payload)); err != nil {
+		slog.ErrorContext(ctx, "Error restoring rows", "table", table, "error", err)
+		return fmt.Errorf("error inserting into %s: %v", table, err)
+	}
+	return nil
+}
+
+// ResetSequences moves every serial sequence of a table past the restored ids
+func (r *PostgresRepository) ResetSequences(ctx context.Context, definition *TableDefinition) (err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "ResetSequences", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "ResetSequences")
+	defer tracing.End(span, &err)
+	db, err := r.tenantDB(ctx)
+	if err != nil {
+		return err
+	}
+	for _, column := range definition.Columns {
+		if !column.Serial {
+			continue
+		}
+		query := fmt.Sprintf(
+			`SELECT setval(pg_get_serial_sequence($1, $2), COALESCE(MAX(%[2]s), 1), MAX(%[2]s) IS NOT NULL) FROM %[1]s`,
+			definition.Name, column.Name)
+		if _, err := db.ExecContext(ctx, query, definition.Name, column.Name); err != nil {
+			return fmt.Errorf("error resetting sequence of %s.%s: %v", definition.Name, column.Name, err)
+		}
+	}
+	return nil
+}
+
+// CountRows returns the number of rows in a table
+func (r *PostgresRepository) CountRows(ctx context.Context, table string) (_ int64, err error) {
+	defer metrics.ObserveDatabase(metrics.Postgres, "CountRows", time.Now(), &err)
+	ctx, span := tracing.StartDatabase(ctx, metrics.Postgres, "CountRows")
+	defer tracing.End(span, &err)
+	if !tableNamePattern.MatchString(table) {
+		return 0, fmt.Errorf("invalid table name %q", table)
+	}
+
+	var count int64
+	db, err := r.tenantDB(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := db.QueryRowContext(ctx, fmt.Sprintf(`SELECT count(*) FROM %s`, table)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting rows of %s: %v", table, err)
+	}
+	return count, nil
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotTableRoundTrip(t *testing.T) {
+	dbURI := fmt.Sprintf("postgresql://%s:%s@%s:%s/%s?sslmode=%s",
+		os.Getenv("POSTGRES_USER"),
+		os.Getenv("POSTGRES_PASSWORD"),
+		os.Getenv("POSTGRES_HOST"),
+		os.Getenv("POSTGRES_PORT"),
+		os.Getenv("POSTGRES_DB"),
+		os.Getenv("POSTGRES_SSL_MODE"))
+
+	repo, err := NewPostgresRepositoryFromDSN(dbURI)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	ctx := context.Background()
+	assert.NoError(t, repo.InitializeTables(ctx))
+
+	source := "attr_snapshot_test_source"
+	restored := "attr_snapshot_test_copy"
+	defer repo.DB().ExecContext(ctx, "DROP TABLE IF EXISTS "+source)
+	defer repo.DB().ExecContext(ctx, "DROP TABLE IF EXISTS "+restored)
+
+	err = repo.CreateDynamicTable(ctx, source, []Column{
+		{Name: "name", Type: "TEXT"},
+		{Name: "amount", Type: "DOUBLE PRECISION"},
+		{Name: "active", Type: "BOOLEAN"},
+	})
+	assert.NoError(t, err)
+	_, err = repo.DB().ExecContext(ctx, "INSERT INTO "+source+" (name, amount, active) VALUES ('a', 1.5, true), ('b''s', NULL, false)")
+	assert.NoError(t, err)
+
+	tx, err := repo.BeginSnapshot(ctx)
+	assert.NoError(t, err)
+	defer tx.Rollback()
+
+	tables, err := repo.ListAttributeTables(ctx, tx)
+	assert.NoError(t, err)
+	assert.Contains(t, tables, source)
+
+	definition, err := repo.GetTableDefinition(ctx, tx, source)
+	assert.NoError(t, err)
+	assert.Equal(t, "id", definition.Columns[0].Name)
+	assert.True(t, definition.Columns[0].Serial)
+	assert.True(t, definition.Columns[0].PrimaryKey)
+	assert.Equal(t, "entity_attributes(id)", definition.Columns[1].References)
+
+	var rows [][]byte
+	err = repo.ExportTableRows(ctx, tx, source, func(row []byte) error {
+		rows = append(rows, append([]byte(nil), row...))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(rows))
+
+	// Restore into a table with the same structure
+	definition.Name = restored
+	assert.NoError(t, repo.CreateTableFromDefinition(ctx, definition))
+	assert.NoError(t, repo.RestoreTableRows(ctx, restored, rows))
+	assert.NoError(t, repo.ResetSequences(ctx, definition))
+
+	count, err := repo.CountRows(ctx, restored)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// New rows continue after the restored ids
+	_, err = repo.DB().ExecContext(ctx, "INSERT INTO "+restored+" (name) VALUES ('c')")
+	assert.NoError(t, err)
+
+	var name string
+	var amount *float64
+	err = repo.DB().QueryRowContext(ctx, "SELECT name, amount FROM "+restored+" WHERE name = 'b''s'").Scan(&name, &amount)
+	assert.NoError(t, err)
+	assert.Nil(t, amount)
+
+	_, err = repo.CountRows(ctx, "attr_x; DROP TABLE entity_attributes")
+	assert.Error(t, err, "Expected invalid table names to be rejected")
+}
@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"lk/datafoundation/core-api/pkg/tenant"
+
+	"github.com/lib/pq"
+)
+
+// Each tenant other than the default one keeps its tables in a schema of its own, named by
+// tenantSchema, and gets a connection pool whose search_path points at that schema, so every
+// query resolves table names within the tenant. The default tenant keeps using the public schema.
+// The tenant pools are small and only the recently used ones stay open, see tenantPools, so the
+// connections of all tenants together stay within a bound however many tenants there are.
+
+// ErrTooManyTenants is returned when every tenant pool is busy and another tenant needs one
+var ErrTooManyTenants = errors.New("too many tenants are using the database at once")
+
+// tenantSchema returns the schema holding the tables of a tenant
+func tenantSchema(id string) string {
+	return "tenant_" + id
+}
+
+// quarantineSchema returns the schema the quarantined tables of the call's tenant are moved to
+func quarantineSchema(ctx context.Context) string {
+	if tenant.IsDefault(ctx) {
+		return QuarantineSchema
+	}
+	return QuarantineSchema + "_" + tenant.FromContext(ctx)
+}
+
+// tenantDSN returns the connection string of a tenant's pool
+func tenantDSN(dsn string, id string) (string, error) {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		converted, err := pq.ParseURL(dsn)
+		if err != nil {
+			return "", fmt.Errorf("error parsing connection string: %v", err)
+		}
+		dsn = converted
+	}
+	return dsn + " search_path=" + tenantSchema(id), nil
+}
+
+// tenantDB returns the connection pool of the call's tenant, creating its schema and
+// bookkeeping tables the first time the tenant is seen
+func (r *PostgresRepository) tenantDB(ctx context.Context) (*sql.DB, error) {
+	if tenant.IsDefault(ctx) {
+		return r.db, nil
+	}
+	id := tenant.FromContext(ctx)
+
+	return r.tenants.get(ctx, id, func() (*sql.DB, error) {
+		if _, err := r.db.ExecContext(ctx, fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS %s`, pq.QuoteIdentifier(tenantSchema(id)))); err != nil {
+			slog.ErrorContext(ctx, "Error creating tenant schema", "tenant", id, "error", err)
+			return nil, fmt.Errorf("error creating schema of tenant %s: %v", id, err)
+		}
+		dsn, err := tenantDSN(r.dsn, id)
+		if err != nil {
+			return nil, err
+		}
+		db, err := openDB(dsn, r.tenants.config)
+		if err != nil {
+			return nil, fmt.Errorf("error opening pool of tenant %s: %v", id, err)
+		}
+		if err := createCoreTables(ctx, db); err != nil {
+			db.Close()
+			return nil, err
+		}
+		if _, err := migrateAttributeTableNames(ctx, db); err != nil {
+			db.Close()
+			return nil, err
+		}
+
+		slog.InfoContext(ctx, "Opened PostgreSQL pool of tenant", "tenant", id, "schema", tenantSchema(id))
+		return db, nil
+	})
+}
+
+// tenantPools keeps the pools of the tenants other than the default one. At most max pools are
+// open; the least recently used idle pool is closed to make room for another tenant, and pools
+// unused for idleTimeout are closed, so they hold at most max times config.MaxOpenConns connections.
+type tenantPools struct {
+	mu          sync.Mutex
+	pools       map[string]*tenantPool
+	max         int
+	idleTimeout time.Duration
+	config      Config // Pool settings of each tenant pool
+	now         func() time.Time
+}
+
+// tenantPoolGrace is how long a pool handed out to a call is kept open even to make room for another
+// tenant, so the call finds it open when its query starts
+const tenantPoolGrace = time.Minute
+
+// tenantPool is an open pool and when it was last handed out
+type tenantPool struct {
+	db       *sql.DB
+	lastUsed time.Time
+}
+
+// newTenantPools applies the tenant pool settings of cfg, or their defaults
+func newTenantPools(cfg Config) *tenantPools {
+	pools := &tenantPools{
+		pools:       make(map[string]*tenantPool),
+		max:         DefaultMaxTenantPools,
+		idleTimeout: DefaultTenantPoolIdleTimeout,
+		config:      cfg,
+		now:         time.Now,
+	}
+	if cfg.MaxTenantPools > 0 {
+		pools.max = cfg.MaxTenantPools
+	}
+	if cfg.TenantPoolIdleTimeout > 0 {
+		pools.idleTimeout = cfg.TenantPoolIdleTimeout
+	}
+	pools.config.MaxOpenConns = DefaultTenantMaxOpenConns
+	if cfg.TenantMaxOpenConns > 0 {
+		pools.config.MaxOpenConns = cfg.TenantMaxOpenConns
+	}
+	pools.config.MaxIdleConns = min(pools.config.MaxOpenConns, max(cfg.MaxIdleConns, 1))
+	return pools
+}
+
+// get returns the pool of a tenant, opening it with open when it is not open
+func (p *tenantPools) get(ctx context.Context, id string, open func() (*sql.DB, error)) (*sql.DB, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+
+	// A pool is only closed once it has no connection in use and was not handed out recently,
+	// so a call that was just given the pool never finds it closed
+	for other, pool := range p.pools {
+		if other != id && pool.idle(now, p.idleTimeout) {
+			slog.InfoContext(ctx, "Closing idle PostgreSQL pool of tenant", "tenant", other)
+			p.closePool(other)
+		}
+	}
+
+	if pool, ok := p.pools[id]; ok {
+		pool.lastUsed = now
+		return pool.db, nil
+	}
+
+	if len(p.pools) >= p.max {
+		oldest := ""
+		for other, pool := range p.pools {
+			if !pool.idle(now, tenantPoolGrace) {
+				continue
+			}
+			if oldest == "" || pool.lastUsed.Before(p.pools[oldest].lastUsed) {
+				oldest = other
+			}
+		}
+		if oldest == "" {
+			slog.WarnContext(ctx, "Every PostgreSQL tenant pool is busy", "tenant", id, "pools", len(p.pools))
+			return nil, fmt.Errorf("%w: tenant %s must wait for one of %d tenants", ErrTooManyTenants, id, len(p.pools))
+		}
+		slog.InfoContext(ctx, "Closing least recently used PostgreSQL pool of tenant", "tenant", oldest)
+		p.closePool(oldest)
+	}
+
+	db, err := open()
+	if err != nil {
+		return nil, err
+	}
+	p.pools[id] = &tenantPool{db: db, lastUsed: now}
+	return db, nil
+}
+
+// idle reports whether a pool has no connection in use and was not handed out for the given time
+func (t *tenantPool) idle(now time.Time, unused time.Duration) bool {
+	return t.db.Stats().InUse == 0 && now.Sub(t.lastUsed) >= unused
+}
+
+// closePool closes and forgets the pool of a tenant; the caller holds mu
+func (p *tenantPools) closePool(id string) {
+	p.pools[id].db.Close()
+	delete(p.pools, id)
+}
+
+// closeAll closes the pool of every tenant
+func (p *tenantPools) closeAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id := range p.pools {
+		p.closePool(id)
+	}
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTenantPools tests that tenant pools are capped, closed when idle and reused while open
+func TestTenantPools(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	pools := newTenantPools(Config{MaxTenantPools: 2, TenantPoolIdleTimeout: 10 * time.Minute})
+	pools.now = func() time.Time { return now }
+	assert.Equal(t, DefaultTenantMaxOpenConns, pools.config.MaxOpenConns)
+
+	opened := make(map[string]int)
+	get := func(id string) (*sql.DB, error) {
+		return pools.get(ctx, id, func() (*sql.DB, error) {
+			opened[id]++
+			// Opening a pool does not connect, so no database is needed
+			return sql.Open("postgres", "host=localhost dbname="+id)
+		})
+	}
+
+	acme, err := get("acme")
+	require.NoError(t, err)
+	again, err := get("acme")
+	require.NoError(t, err)
+	assert.Same(t, acme, again, "an open pool is reused")
+
+	now = now.Add(30 * time.Second)
+	_, err = get("globex")
+	require.NoError(t, err)
+
+	// Both pools were handed out within the grace period, so neither makes room for a third tenant
+	_, err = get("initech")
+	assert.True(t, errors.Is(err, ErrTooManyTenants), "got %v", err)
+
+	// Past it the least recently used pool is closed
+	now = now.Add(tenantPoolGrace)
+	_, err = get("initech")
+	require.NoError(t, err)
+	assert.Len(t, pools.pools, 2)
+	assert.NotContains(t, pools.pools, "acme")
+	assert.Error(t, acme.Ping(), "the closed pool cannot be used")
+
+	// Pools unused for the idle timeout are closed on the next call
+	now = now.Add(10 * time.Minute)
+	_, err = get("acme")
+	require.NoError(t, err)
+	assert.Len(t, pools.pools, 1)
+	assert.Equal(t, 2, opened["acme"])
+
+	pools.closeAll()
+	assert.Empty(t, pools.pools)
+}
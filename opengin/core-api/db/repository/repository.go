@@ -0,0 +1,149 @@
+// Package repository defines the storage backends the engine and the service depend on.
+//
+// The service keeps entities and relationships in a graph store, entity and attribute metadata
+// in a metadata store and tabular attributes in a tabular store. The Neo4j, MongoDB and PostgreSQL
+// repositories implement these interfaces; any other backend that does can be swapped in.
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	commons "lk/datafoundation/core-api/commons"
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/schema"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// GraphStore keeps entities, their relationships and the attribute lookup graph
+type GraphStore interface {
+	// HandleGraphEntityCreation creates the node of an entity
+	HandleGraphEntityCreation(ctx context.Context, entity *pb.Entity) (bool, error)
+	// HandleGraphEntityUpdate updates the node of an existing entity
+	HandleGraphEntityUpdate(ctx context.Context, entity *pb.Entity) (bool, error)
+	// HandleGraphRelationshipsCreate creates the relationships of an entity
+	HandleGraphRelationshipsCreate(ctx context.Context, entity *pb.Entity) error
+	// HandleGraphRelationshipsUpdate creates or updates the relationships of an entity
+	HandleGraphRelationshipsUpdate(ctx context.Context, entity *pb.Entity) error
+	// HandleGraphEntityFilter returns the entities matching a read request
+	HandleGraphEntityFilter(ctx context.Context, req *pb.ReadEntityRequest) ([]map[string]interface{}, error)
+
+	// GetGraphEntity returns the kind, name, created and terminated time of an entity
+	GetGraphEntity(ctx context.Context, entityId string) (*pb.Kind, *pb.TimeBasedValue, string, string, error)
+	// GetGraphRelationships returns the relationships of an entity keyed by relationship id
+	GetGraphRelationships(ctx context.Context, entityId string) (map[string]*pb.Relationship, error)
+	// GetFilteredRelationships returns the relationships of an entity matching the given fields and active at activeAt
+	GetFilteredRelationships(ctx context.Context, entityId string, relationshipId string, relationship string, relatedEntityId string, startTime string, endTime string, direction string, activeAt string) (map[string]*pb.Relationship, error)
+	// GetGraphPaths returns the paths between two entities
+	GetGraphPaths(ctx context.Context, req *pb.PathRequest) ([]*pb.EntityPath, error)
+
+	// ReadGraphEntity returns the properties of an entity node
+	ReadGraphEntity(ctx context.Context, entityID string) (map[string]interface{}, error)
+	// ReadRelationships returns the relationships of an entity in both directions
+	ReadRelationships(ctx context.Context, entityID string) ([]map[string]interface{}, error)
+	// ReadFilteredRelationships returns the relationships of an entity matching the filters and active at activeAt
+	ReadFilteredRelationships(ctx context.Context, entityID string, relationshipFilters map[string]interface{}, activeAt string) ([]map[string]interface{}, error)
+
+	// CountEntities returns the number of entities of the call's tenant, not counting attribute datasets
+	CountEntities(ctx context.Context) (int64, error)
+}
+
+// ErrNotFound is returned, possibly wrapped, by MetadataStore.ReadEntity when there is no document
+// with the id; callers test for it with errors.Is
+var ErrNotFound = errors.New("not found")
+
+// MetadataStore keeps the metadata of entities and attributes as documents keyed by id
+type MetadataStore interface {
+	// HandleMetadata creates the document of an entity or replaces its metadata
+	HandleMetadata(ctx context.Context, entityId string, entity *pb.Entity) error
+	// GetMetadata returns the metadata of an entity, empty when it has none
+	GetMetadata(ctx context.Context, entityId string) (map[string]*anypb.Any, error)
+	// ReadEntity returns the document of an entity, or an error wrapping ErrNotFound when there is none
+	ReadEntity(ctx context.Context, id string) (*pb.Entity, error)
+	// DeleteMetadata removes the document of an entity
+	DeleteMetadata(ctx context.Context, id string) error
+}
+
+// TabularStore keeps tabular attributes
+type TabularStore interface {
+	// InitializeTables creates the bookkeeping tables when they do not exist
+	InitializeTables(ctx context.Context) error
+	// HandleTabularData stores the rows of a tabular attribute
+	HandleTabularData(ctx context.Context, entityID, attrName string, value *pb.TimeBasedValue, schemaInfo *schema.SchemaInfo) error
+	// AttributeTable returns the table the rows of a tabular attribute are kept in, as recorded when the
+	// attribute was first stored
+	AttributeTable(ctx context.Context, entityID, attrName string) (string, error)
+	// GetData returns the rows of an attribute table matching the filters
+	GetData(ctx context.Context, tableName string, filters map[string]interface{}, fields ...string) (*anypb.Any, error)
+	// GetMaskedData is GetData with the masked columns left out of the query or selected as NULL,
+	// so their values never leave the store
+	GetMaskedData(ctx context.Context, tableName string, filters map[string]interface{}, mask ColumnMask, fields ...string) (*anypb.Any, error)
+	// GetTypedData reads the rows a query selects and returns them with their column types and typed cells
+	GetTypedData(ctx context.Context, tableName string, query TabularQuery) (*pb.TabularData, error)
+	// CountData returns the number of rows the filters and written window of a query select,
+	// refusing filters on masked columns like the reads do
+	CountData(ctx context.Context, tableName string, query TabularQuery) (int64, error)
+}
+
+// ColumnMask names the columns of a table the caller may not read
+type ColumnMask struct {
+	Omit   []string // Left out of the result
+	Redact []string // Returned with every value NULL
+}
+
+// Empty reports whether the mask hides nothing
+func (m ColumnMask) Empty() bool {
+	return len(m.Omit) == 0 && len(m.Redact) == 0
+}
+
+// Columns indexes the masked columns by their sanitized table column names
+func (m ColumnMask) Columns() (omit, redact map[string]bool) {
+	omit = make(map[string]bool, len(m.Omit))
+	for _, column := range m.Omit {
+		omit[commons.SanitizeIdentifier(column)] = true
+	}
+	redact = make(map[string]bool, len(m.Redact))
+	for _, column := range m.Redact {
+		redact[commons.SanitizeIdentifier(column)] = true
+	}
+	return omit, redact
+}
+
+// Pinger is implemented by stores that can report whether their backend is reachable
+type Pinger interface {
+	// Ping returns an error when the backend cannot serve requests
+	Ping(ctx context.Context) error
+}
+
+// KindStore is implemented by metadata stores that can keep the kind registry of each tenant
+type KindStore interface {
+	// ListKinds returns the kind definitions of the call's tenant
+	ListKinds(ctx context.Context) ([]*pb.KindDefinition, error)
+	// PutKind creates the definition of a major kind or replaces it
+	PutKind(ctx context.Context, def *pb.KindDefinition) error
+	// DeleteKind removes the definition of a major kind; removing a missing one is not an error
+	DeleteKind(ctx context.Context, major string) error
+}
+
+// AttributeTableName names the table a new tabular attribute is stored in: attr_ followed by the first 32 hex
+// digits of the SHA-256 of the entity id and attribute name. Unlike the sanitized names it replaced, distinct
+// attributes get distinct names and every name fits the 63 byte limit of PostgreSQL identifiers.
+// Readers resolve tables through TabularStore.AttributeTable rather than calling this.
+func AttributeTableName(entityID, attrName string) string {
+	sum := sha256.Sum256([]byte(entityID + "\x00" + attrName))
+	return "attr_" + hex.EncodeToString(sum[:16])
+}
+
+// ValidateGraphIdentifier refuses, with codes.InvalidArgument, a kind or relationship name that cannot be
+// used as a graph label or relationship type. what names the value in the error, e.g. "kind".
+func ValidateGraphIdentifier(what, name string) error {
+	if !commons.IsGraphIdentifier(name) {
+		return status.Errorf(codes.InvalidArgument, "invalid %s %q: only letters, digits and underscores are allowed, and it cannot start with a digit", what, name)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	commons "lk/datafoundation/core-api/commons"
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/schema"
+	"lk/datafoundation/core-api/pkg/typeinference"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TabularFormat selects how tabular attributes are encoded when they are read
+type TabularFormat string
+
+const (
+	// TabularJSON is a Struct whose data field holds the columns and rows as a JSON string
+	TabularJSON TabularFormat = "json"
+	// TabularTyped is a TabularData message with typed columns and cells
+	TabularTyped TabularFormat = "typed"
+)
+
+// TabularQuery selects the rows and columns of a typed read
+type TabularQuery struct {
+	Filters map[string]interface{} // Columns, by name, that must equal the given values
+	Fields  []string               // Columns to return, the data columns when empty
+	Mask    ColumnMask             // Columns the caller may not read
+	// Only rows written at or after WrittenFrom and before WrittenUntil; a zero time leaves that end open
+	WrittenFrom  time.Time
+	WrittenUntil time.Time
+	// Limit and Offset page through the selected rows in the order they were written; a zero Limit
+	// returns every row after Offset
+	Limit  int
+	Offset int
+}
+
+// ParseTabularFormat returns the format a read asks for; no format means TabularJSON
+func ParseTabularFormat(name string) (TabularFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "json":
+		return TabularJSON, nil
+	case "typed":
+		return TabularTyped, nil
+	default:
+		return "", fmt.Errorf("unsupported tabular format: %s", name)
+	}
+}
+
+// ColumnType returns the type of a table column of a stored table. Data columns have the type inferred
+// when the table was created, bookkeeping columns fixed types and anything else is stored as text.
+// Schemas stored before the column layout was recorded keep each field under its sanitized name.
+func ColumnType(info *schema.SchemaInfo, column string) typeinference.DataType {
+	return ColumnTypeInfo(info, column).Type
+}
+
+// ColumnTypeInfo is ColumnType with the precision and scale of decimal columns
+func ColumnTypeInfo(info *schema.SchemaInfo, column string) typeinference.TypeInfo {
+	var field *schema.SchemaInfo
+	if info != nil {
+		for _, c := range info.Columns {
+			if c.Column == column {
+				field = info.Fields[c.Name]
+			}
+		}
+		if len(info.Columns) == 0 && !isBookkeepingColumn(column) {
+			for name, candidate := range info.Fields {
+				if commons.SanitizeIdentifier(name) == column {
+					field = candidate
+				}
+			}
+		}
+	}
+
+	switch {
+	case field != nil && field.TypeInfo != nil && field.TypeInfo.Type != typeinference.NullType:
+		return typeinference.TypeInfo{Type: field.TypeInfo.Type, Precision: field.TypeInfo.Precision, Scale: field.TypeInfo.Scale}
+	case field == nil && (column == "id" || column == "entity_attribute_id"):
+		return typeinference.TypeInfo{Type: typeinference.IntType}
+	case field == nil && column == "created_at":
+		return typeinference.TypeInfo{Type: typeinference.DateTimeType}
+	default:
+		return typeinference.TypeInfo{Type: typeinference.StringType}
+	}
+}
+
+func isBookkeepingColumn(column string) bool {
+	for _, bookkeeping := range BookkeepingColumns {
+		if bookkeeping == column {
+			return true
+		}
+	}
+	return false
+}
+
+// NewTabularData builds the typed form of rows read from a store. Cells hold what the stores scan:
+// int64, float64, bool, string, []byte, time.Time or nil, and are converted to their column's type.
+func NewTabularData(columns []string, types []typeinference.TypeInfo, rows [][]interface{}) (*pb.TabularData, error) {
+	data := &pb.TabularData{
+		Columns: make([]*pb.TabularColumn, len(columns)),
+		Rows:    make([]*pb.TabularRow, len(rows)),
+	}
+	for i, name := range columns {
+		data.Columns[i] = &pb.TabularColumn{
+			Name:      name,
+			Type:      string(types[i].Type),
+			Precision: int32(types[i].Precision),
+			Scale:     int32(types[i].Scale),
+		}
+	}
+	for i, row := range rows {
+		values := make([]*pb.TabularValue, len(row))
+		for j, cell := range row {
+			value, err := NewTabularValue(cell, types[j].Type)
+			if err != nil {
+				return nil, fmt.Errorf("row %d, column %s: %v", i, columns[j], err)
+			}
+			values[j] = value
+		}
+		data.Rows[i] = &pb.TabularRow{Values: values}
+	}
+	return data, nil
+}
+
+// NewTabularValue converts a cell to a typed value of the given column type; nil becomes a null cell.
+// Decimals are returned as text so no digit is lost.
+func NewTabularValue(cell interface{}, dataType typeinference.DataType) (*pb.TabularValue, error) {
+	if b, ok := cell.([]byte); ok {
+		cell = string(b)
+	}
+
+	switch v := cell.(type) {
+	case nil:
+		return &pb.TabularValue{}, nil
+	case time.Time:
+		return &pb.TabularValue{Value: &pb.TabularValue_TimeValue{TimeValue: timestamppb.New(v)}}, nil
+	case bool:
+		return &pb.TabularValue{Value: &pb.TabularValue_BoolValue{BoolValue: v}}, nil
+	case int:
+		return intOrFloat(int64(v), dataType), nil
+	case int32:
+		return intOrFloat(int64(v), dataType), nil
+	case int64:
+		return intOrFloat(v, dataType), nil
+	case float64:
+		if (dataType == typeinference.IntType || dataType == typeinference.BigIntType) && v == math.Trunc(v) {
+			return &pb.TabularValue{Value: &pb.TabularValue_IntValue{IntValue: int64(v)}}, nil
+		}
+		if dataType == typeinference.DecimalType {
+			return &pb.TabularValue{Value: &pb.TabularValue_DecimalValue{DecimalValue: strconv.FormatFloat(v, 'f', -1, 64)}}, nil
+		}
+		return &pb.TabularValue{Value: &pb.TabularValue_FloatValue{FloatValue: v}}, nil
+	case string:
+		return parseTabularValue(v, dataType)
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", cell)
+	}
+}
+
+func intOrFloat(v int64, dataType typeinference.DataType) *pb.TabularValue {
+	switch dataType {
+	case typeinference.FloatType:
+		return &pb.TabularValue{Value: &pb.TabularValue_FloatValue{FloatValue: float64(v)}}
+	case typeinference.DecimalType:
+		return &pb.TabularValue{Value: &pb.TabularValue_DecimalValue{DecimalValue: strconv.FormatInt(v, 10)}}
+	}
+	return &pb.TabularValue{Value: &pb.TabularValue_IntValue{IntValue: v}}
+}
+
+// parseTabularValue converts text to the column type, keeping it as text when it does not parse so a
+// value written before the column was typed is still returned
+func parseTabularValue(text string, dataType typeinference.DataType) (*pb.TabularValue, error) {
+	trimmed := strings.TrimSpace(text)
+	switch dataType {
+	case typeinference.IntType, typeinference.BigIntType:
+		if i, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+			return &pb.TabularValue{Value: &pb.TabularValue_IntValue{IntValue: i}}, nil
+		}
+	case typeinference.DecimalType:
+		if d, err := typeinference.ParseDecimal(trimmed); err == nil {
+			return &pb.TabularValue{Value: &pb.TabularValue_DecimalValue{DecimalValue: d}}, nil
+		}
+	case typeinference.FloatType:
+		if f, err := strconv.ParseFloat(trimmed, 64); err == nil {
+			return &pb.TabularValue{Value: &pb.TabularValue_FloatValue{FloatValue: f}}, nil
+		}
+	case typeinference.BoolType:
+		if b, err := strconv.ParseBool(trimmed); err == nil {
+			return &pb.TabularValue{Value: &pb.TabularValue_BoolValue{BoolValue: b}}, nil
+		}
+	case typeinference.DateType, typeinference.DateTimeType:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02", "2006-01-02 15:04:05"} {
+			if t, err := time.Parse(layout, trimmed); err == nil {
+				return &pb.TabularValue{Value: &pb.TabularValue_TimeValue{TimeValue: timestamppb.New(t)}}, nil
+			}
+		}
+	}
+	return &pb.TabularValue{Value: &pb.TabularValue_StringValue{StringValue: text}}, nil
+}
@@ -0,0 +1,196 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"lk/datafoundation/core-api/commons"
+	"lk/datafoundation/core-api/db/repository"
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/auth"
+	"lk/datafoundation/core-api/pkg/storageinference"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// AccessPolicyMetadataKey is the attribute metadata key holding the attribute's access policy as JSON
+const AccessPolicyMetadataKey = "access_policy"
+
+// AttributeIDMetadataKey is the attribute metadata key holding the attribute ID, which marks a metadata
+// document as the one the engine keeps for an attribute
+const AttributeIDMetadataKey = "attribute_id"
+
+// MetadataAccessKey is the reserved entity metadata key holding the rules of the other metadata keys,
+// e.g. {"email": {"minRole": "admin"}, "phone": {"minRole": "ingester", "mask": "redact"}}
+const MetadataAccessKey = "@access"
+
+// accessPolicyFromMetadata reads the access policy stored with an attribute, returning nil when it has none
+func accessPolicyFromMetadata(entity *pb.Entity) (*auth.AccessPolicy, error) {
+	if entity == nil || entity.Metadata == nil {
+		return nil, nil
+	}
+	policyJSON := commons.ExtractStringFromAny(entity.Metadata[AccessPolicyMetadataKey])
+	if policyJSON == "" {
+		return nil, nil
+	}
+	return auth.ParseAccessPolicy([]byte(policyJSON))
+}
+
+// GetAccessPolicy returns the access policy of an attribute, or nil when the attribute is unrestricted.
+// Only an attribute without metadata has no policy. Metadata or a stored policy that cannot be read
+// is a codes.Internal error, so a failing store or a broken policy never exposes the attribute.
+func (g *GraphMetadataManager) GetAccessPolicy(ctx context.Context, entityID, attrName string) (*auth.AccessPolicy, error) {
+	attributeMetadataEntity, err := g.metadataStore.ReadEntity(ctx, GenerateAttributeID(entityID, attrName))
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Error reading attribute metadata for access policy", "entity_id", entityID, "attribute", attrName, "error", err)
+		return nil, status.Errorf(codes.Internal, "error reading access policy of attribute %s of entity %s: %v", attrName, entityID, err)
+	}
+	policy, err := accessPolicyFromMetadata(attributeMetadataEntity)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error reading attribute access policy", "entity_id", entityID, "attribute", attrName, "error", err)
+		return nil, status.Errorf(codes.Internal, "attribute %s of entity %s: %v", attrName, entityID, err)
+	}
+	return policy, nil
+}
+
+// samePolicy reports whether two access policies, either of which may be nil, restrict the same way
+func samePolicy(a, b *auth.AccessPolicy) bool {
+	aJSON, _ := json.Marshal(a)
+	bJSON, _ := json.Marshal(b)
+	return string(aJSON) == string(bJSON)
+}
+
+// authorizePolicyChange checks that only admins set or replace the access policy of an attribute.
+// Sending the stored policy again is not a change, so any writer may carry it through.
+func authorizePolicyChange(ctx context.Context, entityID, attrName string, stored, declared *auth.AccessPolicy) error {
+	if declared == nil || samePolicy(stored, declared) {
+		return nil
+	}
+	return auth.AuthorizeRole(ctx, auth.RoleAdmin, fmt.Sprintf("set the access policy of attribute %s of entity %s", attrName, entityID))
+}
+
+// AuthorizeAccessChanges checks, before anything of an entity is written, that only admins set or
+// change the access policies its attributes declare in "@access" and the rules under MetadataAccessKey.
+// Metadata is replaced as a whole, so leaving out stored rules counts as changing them. The metadata
+// the engine keeps for an attribute, which holds its access policy, is only written directly by admins.
+func (p *EntityAttributeProcessor) AuthorizeAccessChanges(ctx context.Context, entity *pb.Entity) error {
+	principal := auth.PrincipalFromContext(ctx)
+	if principal == nil || principal.Has(auth.RoleAdmin) {
+		return nil
+	}
+
+	if len(entity.GetMetadata()) > 0 {
+		stored, err := p.graphManager.metadataStore.ReadEntity(ctx, entity.Id)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return status.Errorf(codes.Internal, "error reading metadata of entity %s: %v", entity.Id, err)
+		}
+		if p.isAttribute(ctx, entity.Id, stored) {
+			return auth.AuthorizeRole(ctx, auth.RoleAdmin, fmt.Sprintf("write the metadata of attribute %s", entity.Id))
+		}
+		for _, key := range []string{MetadataAccessKey, AccessPolicyMetadataKey} {
+			if !proto.Equal(stored.GetMetadata()[key], entity.Metadata[key]) {
+				return auth.AuthorizeRole(ctx, auth.RoleAdmin, fmt.Sprintf("change the %s metadata of entity %s", key, entity.Id))
+			}
+		}
+	}
+
+	for attrName, values := range entity.GetAttributes() {
+		for _, value := range values.GetValues() {
+			if value == nil || value.Value == nil {
+				continue
+			}
+			// Invalid hints are reported when the attribute is processed
+			hint, _, err := storageinference.UnwrapHint(value.Value)
+			if err != nil || hint == nil || hint.Access == nil {
+				continue
+			}
+			stored, err := p.graphManager.GetAccessPolicy(ctx, entity.Id, attrName)
+			if err != nil {
+				return err
+			}
+			if err := authorizePolicyChange(ctx, entity.Id, attrName, stored, hint.Access); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isAttribute reports whether an ID names an attribute rather than an entity: its stored metadata is
+// the engine's record of an attribute, or its graph node is a dataset
+func (p *EntityAttributeProcessor) isAttribute(ctx context.Context, id string, stored *pb.Entity) bool {
+	if _, ok := stored.GetMetadata()[AttributeIDMetadataKey]; ok {
+		return true
+	}
+	node, err := p.graphManager.graphStore.ReadGraphEntity(ctx, id)
+	return err == nil && node["MajorKind"] == DatasetType
+}
+
+// maskedAttribute returns the result standing in for an attribute the caller may not read,
+// or nil when the caller may read it
+func maskedAttribute(ctx context.Context, entityID, attrName string, policy *auth.AccessPolicy) *Result {
+	if policy == nil || policy.Allows(ctx) {
+		return nil
+	}
+	slog.DebugContext(ctx, "Masking attribute", "entity_id", entityID, "attribute", attrName, "min_role", policy.MinRole, "mask", policy.Mask)
+	if !policy.Redacts() {
+		return &Result{
+			Data:    nil,
+			Success: true,
+			Error:   nil,
+		}
+	}
+	return &Result{
+		Data: &pb.TimeBasedValue{
+			StartTime: "",
+			EndTime:   "",
+			Value:     commons.ConvertStringToAny(auth.RedactedValue),
+		},
+		Success: true,
+		Error:   nil,
+	}
+}
+
+// MaskMetadata applies the rules under MetadataAccessKey to the metadata of an entity.
+// Keys the caller may not read are left out or redacted, and the rules themselves are only
+// returned to admins. Metadata whose rules cannot be read is withheld entirely for non-admins.
+func MaskMetadata(ctx context.Context, metadata map[string]*anypb.Any) map[string]*anypb.Any {
+	rulesValue, ok := metadata[MetadataAccessKey]
+	if !ok || auth.PrincipalFromContext(ctx) == nil {
+		return metadata
+	}
+
+	admin := auth.Rule{MinRole: auth.RoleAdmin}
+	rules, err := auth.ParseKeyRules([]byte(metadataValueToString(rulesValue)))
+	if err != nil {
+		slog.ErrorContext(ctx, "Error reading metadata access rules", "error", err)
+		if admin.Allows(ctx) {
+			return metadata
+		}
+		return make(map[string]*anypb.Any)
+	}
+
+	masked := make(map[string]*anypb.Any, len(metadata))
+	for key, value := range metadata {
+		rule := rules[key]
+		if key == MetadataAccessKey {
+			rule = admin
+		}
+		switch {
+		case rule.Allows(ctx):
+			masked[key] = value
+		case rule.Redacts():
+			masked[key] = commons.ConvertStringToAny(auth.RedactedValue)
+		}
+	}
+	return masked
+}
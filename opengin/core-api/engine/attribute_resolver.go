@@ -3,15 +3,22 @@ package engine
 import (
 	"context"
 	"fmt"
-	commons "lk/datafoundation/core-api/commons"
-	dbcommons "lk/datafoundation/core-api/commons/db"
+	"lk/datafoundation/core-api/db/repository"
 	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/auth"
+	"lk/datafoundation/core-api/pkg/logging"
+	"lk/datafoundation/core-api/pkg/metrics"
 	schema "lk/datafoundation/core-api/pkg/schema"
 	storageinference "lk/datafoundation/core-api/pkg/storageinference"
-	"log"
+	"lk/datafoundation/core-api/pkg/tabularfile"
+	"lk/datafoundation/core-api/pkg/tenant"
+	"lk/datafoundation/core-api/pkg/tracing"
+	"log/slog"
+	"sync"
 
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/protobuf/types/known/anypb"
 )
 
@@ -53,21 +60,23 @@ type EntityAttributeProcessor struct {
 }
 
 // NewEntityAttributeProcessor creates a new processor with all resolvers initialized
-func NewEntityAttributeProcessor() *EntityAttributeProcessor {
+// The stores are shared by every request handled by the processor.
+func NewEntityAttributeProcessor(graphStore repository.GraphStore, metadataStore repository.MetadataStore, tabularStore repository.TabularStore) *EntityAttributeProcessor {
 	processor := &EntityAttributeProcessor{
 		resolvers:    make(map[storageinference.StorageType]AttributeResolver),
-		graphManager: NewGraphMetadataManager(),
+		graphManager: NewGraphMetadataManager(graphStore, metadataStore),
 	}
 
 	// Initialize all resolvers
 	processor.resolvers[storageinference.GraphData] = &GraphAttributeResolver{}
-	processor.resolvers[storageinference.TabularData] = &TabularAttributeResolver{}
+	processor.resolvers[storageinference.TabularData] = &TabularAttributeResolver{store: tabularStore, graphManager: processor.graphManager}
 	processor.resolvers[storageinference.MapData] = &DocumentAttributeResolver{}
+	processor.resolvers[storageinference.BlobData] = &BlobAttributeResolver{graphManager: processor.graphManager, metadataStore: metadataStore}
 
 	// Initialize each resolver
-	for _, resolver := range processor.resolvers {
+	for storageType, resolver := range processor.resolvers {
 		if err := resolver.Initialize(); err != nil {
-			fmt.Printf("Warning: failed to initialize resolver: %v\n", err)
+			slog.Warn("Failed to initialize resolver", "storage_type", storageType, "error", err)
 		}
 	}
 
@@ -81,9 +90,10 @@ func (p *EntityAttributeProcessor) GetResolver(storageType storageinference.Stor
 }
 
 // ProcessEntityAttributes processes all attributes in an Entity with operation options
-// Returns a map of attribute names to their processing results
+// Returns a map of attribute names to their processing results. When a created attribute was given
+// as a tabular file, its result Data is a TimeBasedValueList of TabularIngestReports, one per value.
 func (p *EntityAttributeProcessor) ProcessEntityAttributes(ctx context.Context, entity *pb.Entity, operation string, options *Options) map[string]*Result {
-	log.Printf("Processing entity attributes at [processor.ProcessEntityAttributes] [operation: %s] [entity: %+v]", operation, entity)
+	slog.DebugContext(ctx, "Processing entity attributes", "operation", operation, "entity_id", entity.GetId(), "entity", logging.Payload(entity))
 	if entity == nil || entity.Attributes == nil {
 		return make(map[string]*Result)
 	}
@@ -93,9 +103,9 @@ func (p *EntityAttributeProcessor) ProcessEntityAttributes(ctx context.Context,
 
 	// Process each attribute
 	for attrName, timeBasedValueList := range entity.Attributes {
-		fmt.Printf("DEBUG: Processing attribute[%s] %s\n", operation, attrName)
+		slog.DebugContext(ctx, "Processing attribute", "operation", operation, "attribute", attrName)
 		if timeBasedValueList == nil {
-			log.Printf("DEBUG: Time-based value list is nil for attribute %s", attrName)
+			slog.DebugContext(ctx, "Time-based value list is nil", "attribute", attrName)
 			attributeResults[attrName] = &Result{
 				Success: true,
 				Data:    nil,
@@ -104,7 +114,17 @@ func (p *EntityAttributeProcessor) ProcessEntityAttributes(ctx context.Context,
 			continue
 		}
 
-		log.Printf("DEBUG: Time-based value list is not nil for attribute %s, length: %d", attrName, len(timeBasedValueList.Values))
+		slog.DebugContext(ctx, "Time-based value list", "attribute", attrName, "values_count", len(timeBasedValueList.Values))
+
+		// One span per attribute; the lookup graph, metadata and resolver calls of its values nest below it
+		attrCtx, attrSpan := tracing.Start(ctx, "ProcessEntityAttributes.attribute",
+			attribute.String("entity.id", entity.Id),
+			attribute.String("attribute.name", attrName),
+			attribute.String("attribute.operation", operation),
+			attribute.Int("attribute.values", len(timeBasedValueList.Values)))
+
+		// Reports of the values given as tabular files
+		var reports []*pb.TimeBasedValue
 
 		// Process each time-based value
 		for _, value := range timeBasedValueList.Values {
@@ -112,11 +132,11 @@ func (p *EntityAttributeProcessor) ProcessEntityAttributes(ctx context.Context,
 				continue
 			}
 
-			log.Printf("DEBUG: Processing time-based value for attribute %s: %+v", attrName, value)
+			slog.DebugContext(ctx, "Processing time-based value", "attribute", attrName, "value", logging.Payload(value))
 
-			// Determine storage type
-			storageType, err := p.determineStorageType(value.Value)
-			fmt.Printf("DEBUG: Determined storage type[%s] for attribute %s: %s\n", operation, attrName, storageType)
+			// Determine storage type, honouring a storage type and access policy declared by the producer
+			storageType, hint, value, err := p.resolveStorageType(value)
+			slog.DebugContext(ctx, "Determined storage type", "operation", operation, "attribute", attrName, "storage_type", storageType)
 			if err != nil {
 				attributeResults[attrName] = &Result{
 					Success: false,
@@ -125,12 +145,16 @@ func (p *EntityAttributeProcessor) ProcessEntityAttributes(ctx context.Context,
 				}
 				continue
 			}
+			var access *auth.AccessPolicy
+			if hint != nil {
+				access = hint.Access
+			}
 
 			// Create or update graph metadata BEFORE processing the attribute
 			// NOTE: for the attribute the timestamp is always the value carried at the attribute level
 			// not the entity level. The entity level timestamp is used for the entity itself.
 			attributeStartTime, _ := time.Parse(time.RFC3339, value.StartTime)
-			if err := p.handleAttributeLookUp(ctx, entity.Id, attrName, storageType, operation, attributeStartTime); err != nil {
+			if err := p.handleAttributeLookUp(attrCtx, entity.Id, attrName, storageType, operation, attributeStartTime, access); err != nil {
 				attributeResults[attrName] = &Result{
 					Success: false,
 					Data:    nil,
@@ -139,10 +163,27 @@ func (p *EntityAttributeProcessor) ProcessEntityAttributes(ctx context.Context,
 				continue
 			}
 
+			// Attributes the caller may not read are left out or redacted before reaching any store
+			if operation == "read" {
+				policy, err := p.graphManager.GetAccessPolicy(attrCtx, entity.Id, attrName)
+				if err != nil {
+					attributeResults[attrName] = &Result{
+						Success: false,
+						Data:    nil,
+						Error:   fmt.Errorf("error reading access policy for attribute %s: %v", attrName, err),
+					}
+					continue
+				}
+				if masked := maskedAttribute(attrCtx, entity.Id, attrName, policy); masked != nil {
+					attributeResults[attrName] = masked
+					continue
+				}
+			}
+
 			// Get appropriate resolver
 			resolver, exists := p.resolvers[storageType]
 			if !exists {
-				fmt.Printf("Warning: no resolver found for storage type %s, skipping attribute %s\n", storageType, attrName)
+				slog.WarnContext(ctx, "No resolver found for storage type, skipping attribute", "storage_type", storageType, "attribute", attrName)
 				attributeResults[attrName] = &Result{
 					Success: false,
 					Data:    nil,
@@ -171,19 +212,42 @@ func (p *EntityAttributeProcessor) ProcessEntityAttributes(ctx context.Context,
 				// For non-read operations, pass the options as-is
 				operationOptions = options
 			}
-			result := p.executeOperation(ctx, resolver, operation, entity.Id, attrName, value, operationOptions)
+			operationStart := time.Now()
+			resolveCtx, resolveSpan := tracing.Start(attrCtx, "resolver."+operation,
+				attribute.String("attribute.name", attrName),
+				attribute.String("storage.type", string(storageType)))
+			result := p.executeOperation(resolveCtx, resolver, operation, entity.Id, attrName, value, operationOptions)
+			tracing.End(resolveSpan, &result.Error)
+			metrics.ObserveAttribute(operation, string(storageType), time.Since(operationStart), result.Error)
 
-			log.Printf("DEBUG: Result for attribute %s: %+v", attrName, result)
+			slog.DebugContext(ctx, "Attribute result", "attribute", attrName, "success", result.Success, "data", logging.Payload(result.Data))
 
 			// Store the result for this attribute
 			attributeResults[attrName] = result
 
+			if operation == "create" && result.Success && hint != nil && hint.File != nil {
+				report, err := ingestReport(hint.File, value)
+				if err != nil {
+					slog.WarnContext(ctx, "Failed to build tabular ingest report", "attribute", attrName, "error", err)
+				} else {
+					reports = append(reports, report)
+				}
+			}
+
 			// For read operations, we might want to do something with the result
 			if operation == "read" && result.Data != nil {
-				fmt.Printf("Read operation completed for attribute %s\n", attrName)
+				slog.DebugContext(ctx, "Read operation completed", "attribute", attrName)
 				// TODO: Handle the read result (e.g., store it, return it, etc.)
 			}
 		}
+
+		var attrErr error
+		if result, ok := attributeResults[attrName]; ok && !result.Success {
+			attrErr = result.Error
+		} else if ok && len(reports) > 0 {
+			result.Data = &pb.TimeBasedValueList{Values: reports}
+		}
+		tracing.End(attrSpan, &attrErr)
 	}
 
 	return attributeResults
@@ -193,10 +257,10 @@ func (p *EntityAttributeProcessor) ProcessEntityAttributes(ctx context.Context,
 // This is the first step in the attribute processing pipeline.
 // It creates the attribute look up metadata and the attribute node in the graph.
 // It also creates the IS_ATTRIBUTE relationship between the entity and the attribute.
-// It also creates the attribute metadata in the document database.
-func (p *EntityAttributeProcessor) handleAttributeLookUp(ctx context.Context, entityID, attrName string, storageType storageinference.StorageType, operation string, startTime time.Time) error {
+// It also creates the attribute metadata in the document database, including the access policy declared on create.
+func (p *EntityAttributeProcessor) handleAttributeLookUp(ctx context.Context, entityID, attrName string, storageType storageinference.StorageType, operation string, startTime time.Time, access *auth.AccessPolicy) error {
 	// Generate attribute metadata
-	fmt.Printf("DEBUG: Handling graph metadata for attribute %s\n", attrName)
+	slog.DebugContext(ctx, "Handling graph metadata", "attribute", attrName)
 	attributeID := GenerateAttributeID(entityID, attrName)
 	storagePath := GenerateStoragePath(entityID, attrName, storageType)
 
@@ -209,6 +273,7 @@ func (p *EntityAttributeProcessor) handleAttributeLookUp(ctx context.Context, en
 		Created:       startTime,
 		Updated:       time.Now(),
 		Schema:        make(map[string]interface{}), // TODO: Extract schema from value
+		Access:        access,
 	}
 
 	// Note: endTime parameter is optional and available for future use if needed
@@ -238,16 +303,68 @@ func (p *EntityAttributeProcessor) handleAttributeLookUp(ctx context.Context, en
 		// For read operations, retrieve the attribute metadata from the graph
 		attributeMetadata, err := p.graphManager.GetAttribute(ctx, entityID, attrName, startTime)
 		if err != nil {
-			fmt.Printf("Warning: attribute %s not found in graph metadata for entity %s\n", attrName, entityID)
+			slog.WarnContext(ctx, "Attribute not found in graph metadata", "attribute", attrName, "entity_id", entityID)
 		} else if attributeMetadata != nil {
 			// Store the retrieved metadata for potential use
-			fmt.Printf("DEBUG: Retrieved attribute metadata for %s: %+v\n", attrName, attributeMetadata)
+			slog.DebugContext(ctx, "Retrieved attribute metadata", "attribute", attrName, "attribute_metadata", logging.Payload(attributeMetadata))
 		}
 	}
 
 	return nil
 }
 
+// resolveStorageType returns the storage type of a TimeBasedValue together with its storage hint, nil
+// when there is none, and the value to store. A value wrapped in a storage hint envelope is stored as the
+// wrapped value under the declared storage type and is rejected if the declared dataset kind does not match it.
+func (p *EntityAttributeProcessor) resolveStorageType(value *pb.TimeBasedValue) (storageinference.StorageType, *storageinference.StorageHint, *pb.TimeBasedValue, error) {
+	hint, unwrapped, err := storageinference.UnwrapHint(value.Value)
+	if err != nil {
+		return storageinference.UnknownData, nil, value, fmt.Errorf("invalid storage hint: %v", err)
+	}
+	if hint == nil {
+		storageType, err := p.determineStorageType(value.Value)
+		return storageType, nil, value, err
+	}
+
+	if hint.DatasetKind != "" && hint.DatasetKind != GetDatasetType(hint.StorageType) {
+		return storageinference.UnknownData, nil, value, fmt.Errorf("declared dataset kind %s does not match storage type %s (expected %s)",
+			hint.DatasetKind, hint.StorageType, GetDatasetType(hint.StorageType))
+	}
+
+	return hint.StorageType, hint, &pb.TimeBasedValue{
+		StartTime: value.StartTime,
+		EndTime:   value.EndTime,
+		Value:     unwrapped,
+	}, nil
+}
+
+// ingestReport describes how a tabular file was read, as a TimeBasedValue over the period of the stored value
+func ingestReport(table *tabularfile.Table, value *pb.TimeBasedValue) (*pb.TimeBasedValue, error) {
+	report := &pb.TabularIngestReport{
+		Format:          string(table.Format),
+		RowCount:        int64(len(table.Rows)),
+		SampledRows:     int64(table.SampledRows),
+		FailedCellCount: int64(table.FailedCellCount),
+	}
+	for i, name := range table.Columns {
+		report.Columns = append(report.Columns, &pb.TabularColumn{Name: name, Type: string(table.Types[i])})
+	}
+	for _, cell := range table.FailedCells {
+		report.FailedCells = append(report.FailedCells, &pb.TabularCellError{
+			Row:    int64(cell.Row),
+			Column: cell.Column,
+			Value:  cell.Value,
+			Type:   string(cell.Type),
+		})
+	}
+
+	packed, err := anypb.New(report)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.TimeBasedValue{StartTime: value.StartTime, EndTime: value.EndTime, Value: packed}, nil
+}
+
 // determineStorageType determines the storage type of a TimeBasedValue
 func (p *EntityAttributeProcessor) determineStorageType(anyValue *anypb.Any) (storageinference.StorageType, error) {
 	if anyValue == nil {
@@ -280,6 +397,13 @@ type Options struct {
 type ReadOptions struct {
 	Filters map[string]interface{}
 	Fields  []string
+	// Format is the encoding of tabular attributes, TabularJSON when empty
+	Format repository.TabularFormat
+}
+
+// formattedReader is implemented by resolvers whose values can be read in more than one encoding
+type formattedReader interface {
+	ReadFormatted(ctx context.Context, entityID, attrName string, filters map[string]interface{}, format repository.TabularFormat, fields ...string) *Result
 }
 
 // CreateOptions contains options for create operations
@@ -343,26 +467,31 @@ func (p *EntityAttributeProcessor) executeOperation(ctx context.Context, resolve
 	switch operation {
 	case "create":
 		// TODO: Use CreateOptions when implemented
-		log.Printf("Creating attribute %s for entity %s\n", attrName, entityID)
+		slog.DebugContext(ctx, "Creating attribute", "attribute", attrName, "entity_id", entityID)
 		return resolver.CreateResolve(ctx, entityID, attrName, value)
 	case "read":
 		// Use provided options or default to empty filters
-		log.Printf("Reading attribute %s for entity %s\n", attrName, entityID)
+		slog.DebugContext(ctx, "Reading attribute", "attribute", attrName, "entity_id", entityID)
 		var filters map[string]interface{}
 		var fields []string
+		var format repository.TabularFormat
 		if options != nil && options.ReadOptions != nil {
 			filters = options.ReadOptions.Filters
 			fields = options.ReadOptions.Fields
+			format = options.ReadOptions.Format
 		} else {
 			filters = make(map[string]interface{})
 		}
+		if reader, ok := resolver.(formattedReader); ok && format != "" {
+			return reader.ReadFormatted(ctx, entityID, attrName, filters, format, fields...)
+		}
 		return resolver.ReadResolve(ctx, entityID, attrName, filters, fields...)
 	case "update":
-		log.Printf("Updating attribute %s for entity %s\n", attrName, entityID)
+		slog.DebugContext(ctx, "Updating attribute", "attribute", attrName, "entity_id", entityID)
 		// TODO: Use UpdateOptions when implemented
 		return resolver.UpdateResolve(ctx, entityID, attrName, value)
 	case "delete":
-		log.Printf("Deleting attribute %s for entity %s\n", attrName, entityID)
+		slog.DebugContext(ctx, "Deleting attribute", "attribute", attrName, "entity_id", entityID)
 		// TODO: Use DeleteOptions when implemented
 		return resolver.DeleteResolve(ctx, entityID, attrName, value)
 	default:
@@ -384,7 +513,7 @@ func (r *GraphAttributeResolver) CreateResolve(ctx context.Context, entityID, at
 	// - Validate graph structure (nodes and edges)
 	// - Store in graph database (Neo4j)
 	// - Handle graph relationships
-	fmt.Printf("Creating graph attribute %s for entity %s\n", attrName, entityID)
+	slog.DebugContext(ctx, "Creating graph attribute", "attribute", attrName, "entity_id", entityID)
 	return &Result{
 		Data:    nil,
 		Success: true,
@@ -397,7 +526,7 @@ func (r *GraphAttributeResolver) ReadResolve(ctx context.Context, entityID, attr
 	// - Query graph database
 	// - Retrieve nodes and edges
 	// - Return graph structure
-	fmt.Printf("Reading graph attribute %s for entity %s with filters: %+v and fields: %+v\n", attrName, entityID, filters, fields)
+	slog.DebugContext(ctx, "Reading graph attribute", "attribute", attrName, "entity_id", entityID, "filters", logging.Payload(filters), "fields", logging.Payload(fields))
 
 	// TODO: Return actual graph data from Neo4j
 	// For now, return empty TimeBasedValue
@@ -419,7 +548,7 @@ func (r *GraphAttributeResolver) UpdateResolve(ctx context.Context, entityID, at
 	// - Update nodes and edges
 	// - Handle graph modifications
 	// - Maintain graph consistency
-	fmt.Printf("Updating graph attribute %s for entity %s\n", attrName, entityID)
+	slog.DebugContext(ctx, "Updating graph attribute", "attribute", attrName, "entity_id", entityID)
 	return &Result{
 		Data:    nil,
 		Success: true,
@@ -432,7 +561,7 @@ func (r *GraphAttributeResolver) DeleteResolve(ctx context.Context, entityID, at
 	// - Remove nodes and edges
 	// - Clean up relationships
 	// - Handle cascading deletes
-	fmt.Printf("Deleting graph attribute %s for entity %s\n", attrName, entityID)
+	slog.DebugContext(ctx, "Deleting graph attribute", "attribute", attrName, "entity_id", entityID)
 	return &Result{
 		Data:    nil,
 		Success: true,
@@ -443,6 +572,31 @@ func (r *GraphAttributeResolver) DeleteResolve(ctx context.Context, entityID, at
 // TabularAttributeResolver handles tabular data structures with columns and rows
 type TabularAttributeResolver struct {
 	BaseAttributeResolver
+	store        repository.TabularStore
+	graphManager *GraphMetadataManager
+
+	tablesMu    sync.Mutex
+	tablesReady map[string]bool // keyed by tenant
+}
+
+// ensureTables creates the bookkeeping tables the first time an attribute of a tenant is written.
+// A failure is not remembered, so the next write tries again.
+func (r *TabularAttributeResolver) ensureTables(ctx context.Context) error {
+	r.tablesMu.Lock()
+	defer r.tablesMu.Unlock()
+
+	id := tenant.FromContext(ctx)
+	if r.tablesReady[id] {
+		return nil
+	}
+	if err := r.store.InitializeTables(ctx); err != nil {
+		return err
+	}
+	if r.tablesReady == nil {
+		r.tablesReady = make(map[string]bool)
+	}
+	r.tablesReady[id] = true
+	return nil
 }
 
 func (r *TabularAttributeResolver) CreateResolve(ctx context.Context, entityID, attrName string, value *pb.TimeBasedValue) *Result {
@@ -461,19 +615,18 @@ func (r *TabularAttributeResolver) CreateResolve(ctx context.Context, entityID,
 		}
 	}
 
-	fmt.Printf("Creating tabular attribute %s for entity %s (validated as tabular) from %v to %v\n", attrName, entityID, startDate, endDate)
+	slog.DebugContext(ctx, "Creating tabular attribute", "attribute", attrName, "entity_id", entityID, "start_date", startDate, "end_date", endDate)
 
-	repo, err := dbcommons.GetPostgresRepository(ctx)
-	if err != nil {
+	if r.store == nil {
 		return &Result{
 			Data:    nil,
 			Success: false,
-			Error:   fmt.Errorf("failed to get Postgres repository: %v", err),
+			Error:   fmt.Errorf("tabular store is not configured"),
 		}
 	}
 
 	// Initialize database tables if they don't exist
-	if err := repo.InitializeTables(ctx); err != nil {
+	if err := r.ensureTables(ctx); err != nil {
 		return &Result{
 			Data:    nil,
 			Success: false,
@@ -490,7 +643,7 @@ func (r *TabularAttributeResolver) CreateResolve(ctx context.Context, entityID,
 		}
 	}
 
-	err = repo.HandleTabularData(ctx, entityID, attrName, value, schemaInfo)
+	err = r.store.HandleTabularData(ctx, entityID, attrName, value, schemaInfo)
 	if err != nil {
 		return &Result{
 			Data:    nil,
@@ -507,27 +660,57 @@ func (r *TabularAttributeResolver) CreateResolve(ctx context.Context, entityID,
 }
 
 func (r *TabularAttributeResolver) ReadResolve(ctx context.Context, entityID, attrName string, filters map[string]interface{}, fields ...string) *Result {
-	// TODO: implement tabular-specific read logic
-	// - Query database table
-	// - Retrieve rows and columns
-	// - Return tabular structure
-	fmt.Printf("[TabularAttributeResolver.ReadResolve] Reading tabular attribute %s for entity %s with filters: %+v and fields: %+v\n", attrName, entityID, filters, fields)
+	return r.ReadFormatted(ctx, entityID, attrName, filters, repository.TabularJSON, fields...)
+}
 
-	repo, err := dbcommons.GetPostgresRepository(ctx)
-	if err != nil {
+// ReadFormatted reads a tabular attribute as a Struct holding JSON or, for TabularTyped, as TabularData
+func (r *TabularAttributeResolver) ReadFormatted(ctx context.Context, entityID, attrName string, filters map[string]interface{}, format repository.TabularFormat, fields ...string) *Result {
+	slog.DebugContext(ctx, "Reading tabular attribute", "attribute", attrName, "entity_id", entityID, "filters", logging.Payload(filters), "fields", logging.Payload(fields), "format", format)
+
+	if r.store == nil {
 		return &Result{
 			Data:    nil,
 			Success: false,
-			Error:   fmt.Errorf("failed to get Postgres repository: %v", err),
+			Error:   fmt.Errorf("tabular store is not configured"),
 		}
 	}
 
 	// Get the table name for this attribute
-	tableName := fmt.Sprintf("attr_%s_%s", commons.SanitizeIdentifier(entityID), commons.SanitizeIdentifier(attrName))
-	log.Printf("[TabularAttributeResolver.ReadResolve] tableName: %s", tableName)
+	tableName, err := r.store.AttributeTable(ctx, entityID, attrName)
+	if err != nil {
+		return &Result{
+			Data:    nil,
+			Success: false,
+			Error:   fmt.Errorf("failed to resolve table: %v", err),
+		}
+	}
+	slog.DebugContext(ctx, "Resolved tabular attribute table", "table_name", tableName)
 
-	// Use the GetData method from the repository to retrieve data with filters and fields
-	anyData, err := repo.GetData(ctx, tableName, filters, fields...)
+	// Restricted columns are dropped or nulled by the store, so they never leave the database
+	var policy *auth.AccessPolicy
+	if r.graphManager != nil {
+		policy, err = r.graphManager.GetAccessPolicy(ctx, entityID, attrName)
+		if err != nil {
+			return &Result{
+				Data:    nil,
+				Success: false,
+				Error:   err,
+			}
+		}
+	}
+	omit, redact := policy.HiddenColumns(ctx)
+
+	// Use the GetMaskedData method from the repository to retrieve data with filters and fields
+	mask := repository.ColumnMask{Omit: omit, Redact: redact}
+	var anyData *anypb.Any
+	if format == repository.TabularTyped {
+		var typed *pb.TabularData
+		if typed, err = r.store.GetTypedData(ctx, tableName, repository.TabularQuery{Filters: filters, Fields: fields, Mask: mask}); err == nil {
+			anyData, err = anypb.New(typed)
+		}
+	} else {
+		anyData, err = r.store.GetMaskedData(ctx, tableName, filters, mask, fields...)
+	}
 	if err != nil {
 		return &Result{
 			Data:    nil,
@@ -536,9 +719,9 @@ func (r *TabularAttributeResolver) ReadResolve(ctx context.Context, entityID, at
 		}
 	}
 
-	fmt.Printf("Retrieved data from table %s\n", tableName)
+	slog.DebugContext(ctx, "Retrieved data from table", "table_name", tableName)
 
-	// The data is already in the correct format (pb.Any with JSON)
+	// The data is already in the correct format (pb.Any with JSON or TabularData)
 	timeBasedValue := &pb.TimeBasedValue{
 		StartTime: "",
 		EndTime:   "",
@@ -557,7 +740,7 @@ func (r *TabularAttributeResolver) UpdateResolve(ctx context.Context, entityID,
 	// - Update table schema if needed
 	// - Update data rows
 	// - Handle schema evolution
-	fmt.Printf("Updating tabular attribute %s for entity %s\n", attrName, entityID)
+	slog.DebugContext(ctx, "Updating tabular attribute", "attribute", attrName, "entity_id", entityID)
 	return &Result{
 		Data:    nil,
 		Success: true,
@@ -570,7 +753,7 @@ func (r *TabularAttributeResolver) DeleteResolve(ctx context.Context, entityID,
 	// - Delete data rows
 	// - Optionally drop table
 	// - Clean up schema
-	fmt.Printf("Deleting tabular attribute %s for entity %s\n", attrName, entityID)
+	slog.DebugContext(ctx, "Deleting tabular attribute", "attribute", attrName, "entity_id", entityID)
 	return &Result{
 		Data:    nil,
 		Success: true,
@@ -588,7 +771,7 @@ func (r *DocumentAttributeResolver) CreateResolve(ctx context.Context, entityID,
 	// - Validate document structure
 	// - Store in document database (MongoDB)
 	// - Handle document indexing
-	fmt.Printf("Creating document attribute %s for entity %s\n", attrName, entityID)
+	slog.DebugContext(ctx, "Creating document attribute", "attribute", attrName, "entity_id", entityID)
 	return &Result{
 		Data:    nil,
 		Success: true,
@@ -601,7 +784,7 @@ func (r *DocumentAttributeResolver) ReadResolve(ctx context.Context, entityID, a
 	// - Query document database
 	// - Retrieve document structure
 	// - Return key-value pairs
-	fmt.Printf("Reading document attribute %s for entity %s with filters: %+v and fields: %+v\n", attrName, entityID, filters, fields)
+	slog.DebugContext(ctx, "Reading document attribute", "attribute", attrName, "entity_id", entityID, "filters", logging.Payload(filters), "fields", logging.Payload(fields))
 
 	// TODO: Return actual document data from MongoDB
 	// For now, return empty TimeBasedValue
@@ -623,7 +806,7 @@ func (r *DocumentAttributeResolver) UpdateResolve(ctx context.Context, entityID,
 	// - Update document fields
 	// - Handle partial updates
 	// - Maintain document consistency
-	fmt.Printf("Updating document attribute %s for entity %s\n", attrName, entityID)
+	slog.DebugContext(ctx, "Updating document attribute", "attribute", attrName, "entity_id", entityID)
 	return &Result{
 		Data:    nil,
 		Success: true,
@@ -636,7 +819,7 @@ func (r *DocumentAttributeResolver) DeleteResolve(ctx context.Context, entityID,
 	// - Remove document
 	// - Clean up indexes
 	// - Handle cascading deletes
-	fmt.Printf("Deleting document attribute %s for entity %s\n", attrName, entityID)
+	slog.DebugContext(ctx, "Deleting document attribute", "attribute", attrName, "entity_id", entityID)
 	return &Result{
 		Data:    nil,
 		Success: true,
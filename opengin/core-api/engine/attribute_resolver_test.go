@@ -13,6 +13,8 @@ import (
 	"lk/datafoundation/core-api/pkg/storageinference"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 // createTimeBasedValue creates a TimeBasedValue with the given JSON data
@@ -70,6 +72,22 @@ func saveEntityToDatabase(ctx context.Context, entity *pb.Entity) error {
 	return nil
 }
 
+// newTestProcessor creates a processor backed by the databases configured in the environment
+func newTestProcessor(t *testing.T) *EntityAttributeProcessor {
+	ctx := context.Background()
+
+	neo4jRepository, err := dbcommons.GetNeo4jRepository(ctx)
+	if err != nil {
+		t.Fatalf("failed to get Neo4j repository: %v", err)
+	}
+	postgresRepository, err := dbcommons.GetPostgresRepository(ctx)
+	if err != nil {
+		t.Fatalf("failed to get Postgres repository: %v", err)
+	}
+
+	return NewEntityAttributeProcessor(neo4jRepository, dbcommons.GetMongoRepository(ctx), postgresRepository)
+}
+
 // getOptionsForOperation returns appropriate options for each operation type
 func getOptionsForOperation(operation string) *Options {
 	switch operation {
@@ -109,7 +127,7 @@ func TestEntityWithGraphDataOnly(t *testing.T) {
 	err = saveEntityToDatabase(ctx, entity)
 	assert.NoError(t, err)
 
-	processor := NewEntityAttributeProcessor()
+	processor := newTestProcessor(t)
 
 	// Test all CORE operations
 	// create test merely checks if the ProcessEntityAttributes function is working
@@ -151,7 +169,7 @@ func TestEntityWithTabularDataOnly(t *testing.T) {
 	err = saveEntityToDatabase(ctx, entity)
 	assert.NoError(t, err)
 
-	processor := NewEntityAttributeProcessor()
+	processor := newTestProcessor(t)
 
 	// Test all CORE operations
 	// TODO: "read", "update", "delete"
@@ -202,7 +220,7 @@ func TestEntityWithDocumentDataOnly(t *testing.T) {
 	err = saveEntityToDatabase(ctx, entity)
 	assert.NoError(t, err)
 
-	processor := NewEntityAttributeProcessor()
+	processor := newTestProcessor(t)
 
 	// Test all CORE operations
 	operations := []string{"create", "read", "update", "delete"}
@@ -261,7 +279,7 @@ func TestEntityWithMixedDataTypes(t *testing.T) {
 	err = saveEntityToDatabase(ctx, entity)
 	assert.NoError(t, err)
 
-	processor := NewEntityAttributeProcessor()
+	processor := newTestProcessor(t)
 
 	// Test all CORE operations
 	// TODO: "read", "update", "delete"
@@ -307,7 +325,7 @@ func TestComplexGraphEntity(t *testing.T) {
 	})
 	assert.NoError(t, err)
 
-	processor := NewEntityAttributeProcessor()
+	processor := newTestProcessor(t)
 	ctx := context.Background()
 
 	// save parent entity to the database
@@ -350,7 +368,7 @@ func TestComplexTabularEntity(t *testing.T) {
 	})
 	assert.NoError(t, err)
 
-	processor := NewEntityAttributeProcessor()
+	processor := newTestProcessor(t)
 	ctx := context.Background()
 
 	// save parent entity to the database
@@ -420,7 +438,7 @@ func TestComplexDocumentEntity(t *testing.T) {
 	})
 	assert.NoError(t, err)
 
-	processor := NewEntityAttributeProcessor()
+	processor := newTestProcessor(t)
 	ctx := context.Background()
 
 	// save parent entity to the database
@@ -484,7 +502,7 @@ func TestEntityWithMultipleAttributesOfSameType(t *testing.T) {
 	})
 	assert.NoError(t, err)
 
-	processor := NewEntityAttributeProcessor()
+	processor := newTestProcessor(t)
 	ctx := context.Background()
 
 	// save parent entity to the database
@@ -546,7 +564,7 @@ func TestStorageTypeDetection(t *testing.T) {
 		},
 	}
 
-	processor := NewEntityAttributeProcessor()
+	processor := NewEntityAttributeProcessor(nil, nil, nil)
 
 	for testName, testCase := range testCases {
 		t.Run(testName, func(t *testing.T) {
@@ -560,6 +578,68 @@ func TestStorageTypeDetection(t *testing.T) {
 	}
 }
 
+// TestStorageHintResolution tests that a declared storage type is honoured and mismatching declarations are rejected
+func TestStorageHintResolution(t *testing.T) {
+	processor := NewEntityAttributeProcessor(nil, nil, nil)
+
+	value, err := createTimeBasedValue(`{"@storage": "map", "@datasetKind": "Document", "@value": {"population": 21000000}}`)
+	assert.NoError(t, err)
+
+	storageType, _, resolved, err := processor.resolveStorageType(value)
+	assert.NoError(t, err)
+	assert.Equal(t, storageinference.MapData, storageType)
+	assert.Equal(t, value.StartTime, resolved.StartTime)
+	assert.Equal(t, value.EndTime, resolved.EndTime)
+
+	// The wrapped value is what gets stored
+	population, err := schema.JSONToAny(`{"population": 21000000}`)
+	assert.NoError(t, err)
+	assert.Equal(t, population.Value, resolved.Value.Value)
+
+	// Declared dataset kind does not match the storage type
+	value, err = createTimeBasedValue(`{"@storage": "tabular", "@datasetKind": "Document", "@value": {"columns": ["a"], "rows": [[1]]}}`)
+	assert.NoError(t, err)
+	_, _, _, err = processor.resolveStorageType(value)
+	assert.Error(t, err)
+
+	// Payload does not match the declared storage type
+	value, err = createTimeBasedValue(`{"@storage": "tabular", "@value": {"population": 21000000}}`)
+	assert.NoError(t, err)
+	_, _, _, err = processor.resolveStorageType(value)
+	assert.Error(t, err)
+}
+
+// TestBlobValueResolution tests that blob values resolve to blob storage and decode to their content
+func TestBlobValueResolution(t *testing.T) {
+	processor := NewEntityAttributeProcessor(nil, nil, nil)
+
+	value, err := createTimeBasedValue(`{"@storage": "blob", "@datasetKind": "Blob", "@value": {"content": "JVBERi0xLjQ=", "mimeType": "application/pdf", "fileName": "gazette.pdf"}}`)
+	assert.NoError(t, err)
+
+	storageType, _, resolved, err := processor.resolveStorageType(value)
+	assert.NoError(t, err)
+	assert.Equal(t, storageinference.BlobData, storageType)
+
+	content, details, err := decodeBlobValue(resolved.Value)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("%PDF-1.4"), content)
+	assert.Equal(t, "application/pdf", details.MimeType)
+	assert.Equal(t, "gazette.pdf", details.FileName)
+
+	raw, err := anypb.New(wrapperspb.Bytes([]byte{0x89, 'P', 'N', 'G'}))
+	assert.NoError(t, err)
+	content, details, err = decodeBlobValue(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x89, 'P', 'N', 'G'}, content)
+	assert.Empty(t, details.MimeType)
+
+	// Blobs are not a document dataset
+	value, err = createTimeBasedValue(`{"@storage": "blob", "@datasetKind": "Document", "@value": {"content": "JVBERi0xLjQ="}}`)
+	assert.NoError(t, err)
+	_, _, _, err = processor.resolveStorageType(value)
+	assert.Error(t, err)
+}
+
 // TestEmptyEntity tests an entity with no attributes
 func TestEmptyEntity(t *testing.T) {
 	entity := &pb.Entity{
@@ -572,7 +652,7 @@ func TestEmptyEntity(t *testing.T) {
 		Attributes: make(map[string]*pb.TimeBasedValueList),
 	}
 
-	processor := NewEntityAttributeProcessor()
+	processor := NewEntityAttributeProcessor(nil, nil, nil)
 	ctx := context.Background()
 
 	// Test all CORE operations
@@ -593,7 +673,7 @@ func TestEmptyEntity(t *testing.T) {
 
 // TestNilEntity tests handling of nil entity
 func TestNilEntity(t *testing.T) {
-	processor := NewEntityAttributeProcessor()
+	processor := NewEntityAttributeProcessor(nil, nil, nil)
 	ctx := context.Background()
 
 	// Test all CORE operations
@@ -625,7 +705,7 @@ func TestInvalidOperation(t *testing.T) {
 	})
 	assert.NoError(t, err)
 
-	processor := NewEntityAttributeProcessor()
+	processor := NewEntityAttributeProcessor(nil, nil, nil)
 	ctx := context.Background()
 
 	options := getOptionsForOperation("invalid_operation")
@@ -651,7 +731,7 @@ func TestUnsupportedStorageType(t *testing.T) {
 	})
 	assert.NoError(t, err)
 
-	processor := NewEntityAttributeProcessor()
+	processor := newTestProcessor(t)
 	ctx := context.Background()
 
 	// save parent entity to the database
@@ -680,7 +760,7 @@ func TestUnsupportedStorageType(t *testing.T) {
 // TestBasicFunctionality tests basic functionality of the attribute resolver
 func TestBasicFunctionality(t *testing.T) {
 	// Test that we can create a processor
-	processor := NewEntityAttributeProcessor()
+	processor := newTestProcessor(t)
 	assert.NotNil(t, processor)
 	assert.NotNil(t, processor.resolvers)
 
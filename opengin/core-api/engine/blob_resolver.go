@@ -0,0 +1,335 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"lk/datafoundation/core-api/commons"
+	dbcommons "lk/datafoundation/core-api/commons/db"
+	"lk/datafoundation/core-api/db/repository"
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/objectstore"
+	"lk/datafoundation/core-api/pkg/storageinference"
+	"lk/datafoundation/core-api/pkg/tenant"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// MaxBlobSize bounds the size of a single blob
+const MaxBlobSize = 512 << 20
+
+// BlobChunkSize is the size of the pieces a blob is streamed in
+const BlobChunkSize = 64 << 10
+
+// BlobAttributeResolver handles binary attributes such as PDFs and images.
+// The content is kept in an object store under its SHA-256, so every version of a blob
+// stays addressable, and its hash, MIME type and size are recorded in the attribute metadata.
+type BlobAttributeResolver struct {
+	BaseAttributeResolver
+	store         objectstore.Store
+	graphManager  *GraphMetadataManager
+	metadataStore repository.MetadataStore
+}
+
+// NewBlobAttributeResolver creates a blob resolver backed by the given object store
+func NewBlobAttributeResolver(store objectstore.Store, graphStore repository.GraphStore, metadataStore repository.MetadataStore) *BlobAttributeResolver {
+	resolver := &BlobAttributeResolver{
+		store:         store,
+		graphManager:  NewGraphMetadataManager(graphStore, metadataStore),
+		metadataStore: metadataStore,
+	}
+	resolver.BaseAttributeResolver.Initialize()
+	return resolver
+}
+
+// Initialize opens the object store configured in the environment unless a store was given
+func (r *BlobAttributeResolver) Initialize() error {
+	r.BaseAttributeResolver.Initialize()
+	if r.store != nil {
+		return nil
+	}
+
+	store, err := dbcommons.GetObjectStore()
+	if err != nil {
+		return err
+	}
+	r.store = store
+	return nil
+}
+
+// Upload streams content into the object store and registers it as an attribute of the entity.
+// The content is spooled to a temporary file while its hash and size are computed, and the MIME type
+// is detected from the content when details.MimeType is empty.
+func (r *BlobAttributeResolver) Upload(ctx context.Context, entityID, attrName string, content io.Reader, details BlobInfo, startTime time.Time) (*AttributeMetadata, error) {
+	if r.store == nil {
+		return nil, fmt.Errorf("object store is not configured")
+	}
+
+	spool, err := os.CreateTemp("", "blob-*")
+	if err != nil {
+		return nil, fmt.Errorf("error creating spool file: %v", err)
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(spool, hash), io.LimitReader(content, MaxBlobSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error receiving blob: %v", err)
+	}
+	if size > MaxBlobSize {
+		return nil, fmt.Errorf("blob exceeds the maximum size of %d bytes", MaxBlobSize)
+	}
+
+	if details.MimeType == "" {
+		head := make([]byte, 512)
+		n, _ := spool.ReadAt(head, 0)
+		details.MimeType = http.DetectContentType(head[:n])
+	}
+	details.ContentHash = hex.EncodeToString(hash.Sum(nil))
+	details.Size = size
+
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("error rewinding spool file: %v", err)
+	}
+	storagePath := GenerateStoragePath(entityID, attrName, storageinference.BlobData) + "/" + details.ContentHash
+	if !tenant.IsDefault(ctx) {
+		// Blobs of other tenants live below a prefix of their own, the default tenant keeps the original paths
+		storagePath = "tenants/" + tenant.FromContext(ctx) + "/" + storagePath
+	}
+	if err := r.store.Put(ctx, storagePath, spool, size); err != nil {
+		slog.ErrorContext(ctx, "Error storing blob", "entity_id", entityID, "attribute", attrName, "error", err)
+		return nil, err
+	}
+
+	// The metadata is rewritten below, so carry over the access policy of earlier versions
+	access, err := r.graphManager.GetAccessPolicy(ctx, entityID, attrName)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &AttributeMetadata{
+		EntityID:      entityID,
+		AttributeID:   GenerateAttributeID(entityID, attrName),
+		AttributeName: attrName,
+		StorageType:   storageinference.BlobData,
+		StoragePath:   storagePath,
+		Created:       startTime,
+		Updated:       time.Now(),
+		Blob:          &details,
+		Access:        access,
+	}
+
+	// Register the lookup node like other datasets; it is left untouched when it already exists
+	if err := r.graphManager.CreateAttribute(ctx, metadata); err != nil {
+		return nil, fmt.Errorf("failed to create attribute node: %v", err)
+	}
+
+	// The metadata always points at the latest version of the content
+	if err := r.metadataStore.HandleMetadata(ctx, metadata.AttributeID, &pb.Entity{Metadata: MakeMetadataOfAttributeMetadata(metadata)}); err != nil {
+		return nil, fmt.Errorf("failed to update blob metadata: %v", err)
+	}
+
+	slog.DebugContext(ctx, "Stored blob", "entity_id", entityID, "attribute", attrName, "bytes", size, "mime_type", details.MimeType)
+	return metadata, nil
+}
+
+// Describe returns the metadata of the latest version of a blob attribute
+func (r *BlobAttributeResolver) Describe(ctx context.Context, entityID, attrName string) (*AttributeMetadata, error) {
+	attributeID := GenerateAttributeID(entityID, attrName)
+
+	attributeMetadataEntity, err := r.metadataStore.ReadEntity(ctx, attributeID)
+	if err != nil {
+		return nil, fmt.Errorf("blob attribute %s not found for entity %s", attrName, entityID)
+	}
+
+	storageTypeStr, storagePath, updatedStr, _ := commons.ExtractAttributeMetadataFields(attributeMetadataEntity)
+	blob := blobInfoFromMetadata(attributeMetadataEntity)
+	if commons.ConvertStorageTypeStringToEnum(storageTypeStr) != storageinference.BlobData || blob == nil {
+		return nil, fmt.Errorf("attribute %s of entity %s is not a blob", attrName, entityID)
+	}
+	access, err := accessPolicyFromMetadata(attributeMetadataEntity)
+	if err != nil {
+		return nil, fmt.Errorf("invalid access policy for attribute %s of entity %s: %v", attrName, entityID, err)
+	}
+
+	return &AttributeMetadata{
+		EntityID:      entityID,
+		AttributeID:   attributeID,
+		AttributeName: attrName,
+		StorageType:   storageinference.BlobData,
+		StoragePath:   storagePath,
+		Updated:       commons.ParseTimestamp(updatedStr, fmt.Sprintf("attribute %s (entity %s) update time", attributeID, entityID)),
+		Blob:          blob,
+		Access:        access,
+	}, nil
+}
+
+// Open returns a reader for the latest version of a blob attribute together with its metadata.
+// Callers without the role required by the attribute's access policy are refused. The caller must close the reader.
+func (r *BlobAttributeResolver) Open(ctx context.Context, entityID, attrName string) (io.ReadCloser, *AttributeMetadata, error) {
+	if r.store == nil {
+		return nil, nil, fmt.Errorf("object store is not configured")
+	}
+
+	metadata, err := r.Describe(ctx, entityID, attrName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if metadata.Access != nil {
+		if err := metadata.Access.Authorize(ctx, fmt.Sprintf("attribute %s of entity %s", attrName, entityID)); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	reader, err := r.store.Get(ctx, metadata.StoragePath)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error opening blob", "storage_path", metadata.StoragePath, "error", err)
+		return nil, nil, fmt.Errorf("error opening blob %s: %v", metadata.StoragePath, err)
+	}
+	return reader, metadata, nil
+}
+
+func (r *BlobAttributeResolver) CreateResolve(ctx context.Context, entityID, attrName string, value *pb.TimeBasedValue) *Result {
+	slog.DebugContext(ctx, "Creating blob attribute", "attribute", attrName, "entity_id", entityID)
+
+	content, details, err := decodeBlobValue(value.Value)
+	if err != nil {
+		return &Result{
+			Data:    nil,
+			Success: false,
+			Error:   err,
+		}
+	}
+
+	startTime, _ := time.Parse(time.RFC3339, value.StartTime)
+	if _, err := r.Upload(ctx, entityID, attrName, bytes.NewReader(content), details, startTime); err != nil {
+		return &Result{
+			Data:    nil,
+			Success: false,
+			Error:   fmt.Errorf("failed to store blob: %v", err),
+		}
+	}
+
+	return &Result{
+		Data:    nil,
+		Success: true,
+		Error:   nil,
+	}
+}
+
+// ReadResolve returns a description of the blob; the content itself is streamed with Open
+func (r *BlobAttributeResolver) ReadResolve(ctx context.Context, entityID, attrName string, filters map[string]interface{}, fields ...string) *Result {
+	slog.DebugContext(ctx, "Reading blob attribute", "attribute", attrName, "entity_id", entityID)
+
+	metadata, err := r.Describe(ctx, entityID, attrName)
+	if err != nil {
+		return &Result{
+			Data:    nil,
+			Success: false,
+			Error:   err,
+		}
+	}
+
+	description, err := structpb.NewStruct(map[string]interface{}{
+		"contentHash": metadata.Blob.ContentHash,
+		"mimeType":    metadata.Blob.MimeType,
+		"fileName":    metadata.Blob.FileName,
+		"size":        metadata.Blob.Size,
+		"storagePath": metadata.StoragePath,
+	})
+	if err != nil {
+		return &Result{
+			Data:    nil,
+			Success: false,
+			Error:   fmt.Errorf("failed to describe blob: %v", err),
+		}
+	}
+	anyValue, err := anypb.New(description)
+	if err != nil {
+		return &Result{
+			Data:    nil,
+			Success: false,
+			Error:   fmt.Errorf("failed to describe blob: %v", err),
+		}
+	}
+
+	return &Result{
+		Data: &pb.TimeBasedValue{
+			StartTime: "",
+			EndTime:   "",
+			Value:     anyValue,
+		},
+		Success: true,
+		Error:   nil,
+	}
+}
+
+// UpdateResolve stores a new version of the blob
+func (r *BlobAttributeResolver) UpdateResolve(ctx context.Context, entityID, attrName string, value *pb.TimeBasedValue) *Result {
+	return r.CreateResolve(ctx, entityID, attrName, value)
+}
+
+// DeleteResolve removes the latest version of the blob from the object store
+func (r *BlobAttributeResolver) DeleteResolve(ctx context.Context, entityID, attrName string, value *pb.TimeBasedValue) *Result {
+	slog.DebugContext(ctx, "Deleting blob attribute", "attribute", attrName, "entity_id", entityID)
+
+	metadata, err := r.Describe(ctx, entityID, attrName)
+	if err == nil && r.store != nil {
+		err = r.store.Delete(ctx, metadata.StoragePath)
+	}
+	if err != nil {
+		return &Result{
+			Data:    nil,
+			Success: false,
+			Error:   fmt.Errorf("failed to delete blob: %v", err),
+		}
+	}
+
+	return &Result{
+		Data:    nil,
+		Success: true,
+		Error:   nil,
+	}
+}
+
+// decodeBlobValue extracts the content of a blob attribute value.
+// The value is either raw bytes (wrapperspb.BytesValue) or a struct with base64 content,
+// as produced by a blob storage hint, optionally carrying mimeType and fileName.
+func decodeBlobValue(anyValue *anypb.Any) ([]byte, BlobInfo, error) {
+	if anyValue == nil {
+		return nil, BlobInfo{}, fmt.Errorf("blob value is nil")
+	}
+
+	message, err := anyValue.UnmarshalNew()
+	if err != nil {
+		return nil, BlobInfo{}, fmt.Errorf("failed to unpack blob value: %v", err)
+	}
+
+	switch value := message.(type) {
+	case *wrapperspb.BytesValue:
+		return value.Value, BlobInfo{}, nil
+	case *structpb.Struct:
+		content, err := base64.StdEncoding.DecodeString(value.Fields["content"].GetStringValue())
+		if err != nil {
+			return nil, BlobInfo{}, fmt.Errorf("blob content is not valid base64: %v", err)
+		}
+		return content, BlobInfo{
+			MimeType: value.Fields["mimeType"].GetStringValue(),
+			FileName: value.Fields["fileName"].GetStringValue(),
+		}, nil
+	default:
+		return nil, BlobInfo{}, fmt.Errorf("unsupported blob value type %T", message)
+	}
+}
@@ -0,0 +1,251 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	mongorepository "lk/datafoundation/core-api/db/repository/mongo"
+	neo4jrepository "lk/datafoundation/core-api/db/repository/neo4j"
+	postgres "lk/datafoundation/core-api/db/repository/postgres"
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/consistency"
+	"lk/datafoundation/core-api/pkg/storageinference"
+)
+
+// ConsistencyOptions selects what a consistency check reports and fixes
+type ConsistencyOptions struct {
+	Categories []consistency.Category // Only report these categories (all if empty)
+	Repair     bool                   // Rebuild missing lookup graph nodes and metadata from the surviving stores
+	Quarantine bool                   // Move dangling tables and documents aside when they cannot be repaired
+}
+
+// ConsistencyChecker cross-references the lookup graph, the metadata documents and the attribute tables
+type ConsistencyChecker struct {
+	neo4jRepo    *neo4jrepository.Neo4jRepository
+	mongoRepo    *mongorepository.MongoRepository
+	postgresRepo *postgres.PostgresRepository
+	graphManager *GraphMetadataManager
+}
+
+// NewConsistencyChecker creates a new consistency checker
+func NewConsistencyChecker(neo4jRepo *neo4jrepository.Neo4jRepository, mongoRepo *mongorepository.MongoRepository, postgresRepo *postgres.PostgresRepository) *ConsistencyChecker {
+	return &ConsistencyChecker{
+		neo4jRepo:    neo4jRepo,
+		mongoRepo:    mongoRepo,
+		postgresRepo: postgresRepo,
+		graphManager: NewGraphMetadataManager(neo4jRepo, mongoRepo),
+	}
+}
+
+// Check scans the three stores and reports the inconsistencies found.
+// When repair or quarantine is requested every issue is checked again before it is fixed,
+// so writes that completed after the scan are left alone.
+func (c *ConsistencyChecker) Check(ctx context.Context, options ConsistencyOptions) (*consistency.Report, error) {
+	report := &consistency.Report{StartedAt: time.Now().UTC()}
+
+	inventory, err := c.collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Scanned = map[string]int{
+		"nodes":           len(inventory.NodeIDs),
+		"datasets":        len(inventory.Datasets),
+		"documents":       len(inventory.DocumentIDs),
+		"attributeRows":   len(inventory.AttributeRows),
+		"attributeTables": len(inventory.Tables),
+	}
+	report.Issues = consistency.Detect(inventory)
+	report.Filter(options.Categories)
+
+	if options.Repair || options.Quarantine {
+		for i := range report.Issues {
+			c.fix(ctx, &report.Issues[i], options)
+		}
+	}
+
+	report.FinishedAt = time.Now().UTC()
+	return report, nil
+}
+
+// collect reads the ids held by every store.
+// PostgreSQL is read first and Neo4j last so an attribute written during the scan shows up
+// in the graph rather than as a row without a Dataset node.
+func (c *ConsistencyChecker) collect(ctx context.Context) (*consistency.Inventory, error) {
+	inventory := consistency.NewInventory()
+
+	tx, err := c.postgresRepo.BeginSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := c.postgresRepo.ListEntityAttributes(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		inventory.AttributeRows = append(inventory.AttributeRows, consistency.AttributeRow{
+			EntityID:      row.EntityID,
+			AttributeName: row.AttributeName,
+			AttributeID:   GenerateAttributeID(row.EntityID, row.AttributeName),
+			TableName:     row.TableName,
+		})
+	}
+
+	inventory.Tables, err = c.postgresRepo.ListAttributeTables(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.mongoRepo.ListDocumentIds(ctx, func(id string) error {
+		inventory.DocumentIDs[id] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.neo4jRepo.ListDatasets(ctx, func(dataset map[string]interface{}) error {
+		inventory.Datasets = append(inventory.Datasets, consistency.Dataset{
+			ID:          stringValue(dataset["Id"]),
+			Name:        stringValue(dataset["Name"]),
+			StorageType: stringValue(dataset["MinorKind"]),
+			EntityID:    stringValue(dataset["EntityId"]),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.neo4jRepo.ListNodeIds(ctx, func(id string) error {
+		inventory.NodeIDs[id] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return inventory, nil
+}
+
+// fix repairs or quarantines a single issue and records the outcome on it
+func (c *ConsistencyChecker) fix(ctx context.Context, issue *consistency.Issue, options ConsistencyOptions) {
+	present, err := c.stillPresent(ctx, issue)
+	if err != nil {
+		issue.Action = consistency.ActionFailed
+		issue.Error = err.Error()
+		return
+	}
+	if !present {
+		issue.Action = consistency.ActionSkipped
+		issue.Detail = "resolved since the scan"
+		return
+	}
+
+	action := consistency.ActionSkipped
+	switch issue.Category {
+	case consistency.DatasetMissingMetadata:
+		if options.Repair && issue.EntityID != "" {
+			action, err = consistency.ActionRepaired, c.rebuildMetadata(ctx, issue)
+		}
+	case consistency.AttributeRowMissingDataset:
+		// Rows whose entity or table is gone have a detail and cannot be repaired
+		if options.Repair && issue.Detail == "" {
+			action, err = consistency.ActionRepaired, c.rebuildDataset(ctx, issue)
+		} else if options.Quarantine {
+			action, err = consistency.ActionQuarantined, c.postgresRepo.QuarantineAttributeTable(ctx, issue.TableName)
+		}
+	case consistency.OrphanAttributeTable:
+		if options.Quarantine {
+			action, err = consistency.ActionQuarantined, c.postgresRepo.QuarantineAttributeTable(ctx, issue.TableName)
+		}
+	case consistency.OrphanDocument:
+		if options.Quarantine {
+			action, err = consistency.ActionQuarantined, c.mongoRepo.QuarantineDocument(ctx, issue.ID)
+		}
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "Error fixing consistency issue", "category", issue.Category, "id", issue.ID, "error", err)
+		issue.Action = consistency.ActionFailed
+		issue.Error = err.Error()
+		return
+	}
+	issue.Action = action
+}
+
+// stillPresent checks a single issue against the live stores
+func (c *ConsistencyChecker) stillPresent(ctx context.Context, issue *consistency.Issue) (bool, error) {
+	switch issue.Category {
+	case consistency.DatasetMissingMetadata:
+		exists, err := c.mongoRepo.DocumentExists(ctx, issue.ID)
+		return !exists, err
+	case consistency.AttributeRowMissingDataset, consistency.OrphanDocument:
+		exists, err := c.neo4jRepo.NodeExists(ctx, issue.ID)
+		return !exists, err
+	case consistency.OrphanAttributeTable:
+		owned, err := c.postgresRepo.TableHasOwner(ctx, issue.TableName)
+		return !owned, err
+	default:
+		return false, fmt.Errorf("unknown consistency category %q", issue.Category)
+	}
+}
+
+// rebuildMetadata writes the metadata document of a Dataset node from the node itself
+func (c *ConsistencyChecker) rebuildMetadata(ctx context.Context, issue *consistency.Issue) error {
+	storageType := storageinference.StorageType(issue.StorageType)
+	metadata := &AttributeMetadata{
+		EntityID:      issue.EntityID,
+		AttributeID:   issue.ID,
+		AttributeName: issue.AttributeName,
+		StorageType:   storageType,
+		StoragePath:   GenerateStoragePath(issue.EntityID, issue.AttributeName, storageType),
+		Updated:       time.Now(),
+	}
+
+	_, err := c.mongoRepo.CreateEntity(ctx, &pb.Entity{
+		Id:       issue.ID,
+		Metadata: MakeMetadataOfAttributeMetadata(metadata),
+	})
+	if err != nil {
+		return fmt.Errorf("error writing metadata of %s: %v", issue.ID, err)
+	}
+	return nil
+}
+
+// rebuildDataset recreates the lookup graph of a tabular attribute whose table is still in place.
+// The attribute is made valid from the creation of its entity, as its original start time is lost.
+func (c *ConsistencyChecker) rebuildDataset(ctx context.Context, issue *consistency.Issue) error {
+	created := time.Now()
+	entity, err := c.neo4jRepo.ReadGraphEntity(ctx, issue.EntityID)
+	if err != nil {
+		return fmt.Errorf("error reading entity %s: %v", issue.EntityID, err)
+	}
+	if entityCreated, err := time.Parse(time.RFC3339, stringValue(entity["Created"])); err == nil {
+		created = entityCreated
+	}
+
+	metadata := &AttributeMetadata{
+		EntityID:      issue.EntityID,
+		AttributeID:   issue.ID,
+		AttributeName: issue.AttributeName,
+		StorageType:   storageinference.TabularData,
+		StoragePath:   GenerateStoragePath(issue.EntityID, issue.AttributeName, storageinference.TabularData),
+		Created:       created,
+		Updated:       time.Now(),
+		Schema:        make(map[string]interface{}),
+	}
+	return c.graphManager.CreateAttribute(ctx, metadata)
+}
+
+// stringValue returns the string form of an optional record value
+func stringValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
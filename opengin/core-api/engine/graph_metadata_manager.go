@@ -2,13 +2,16 @@ package engine
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	"strconv"
 	"time"
 
 	"lk/datafoundation/core-api/commons"
-	dbcommons "lk/datafoundation/core-api/commons/db"
+	"lk/datafoundation/core-api/db/repository"
 	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/auth"
 	"lk/datafoundation/core-api/pkg/storageinference"
 
 	"google.golang.org/protobuf/types/known/anypb"
@@ -34,13 +37,16 @@ const IS_ATTRIBUTE_RELATIONSHIP_DIRECTION = "OUTGOING"
 
 // GraphMetadataManager handles the reference graph for tracking attributes
 type GraphMetadataManager struct {
-	// This would typically connect to Neo4j or another graph database
-	// For now, we'll define the interface and structure
+	graphStore    repository.GraphStore
+	metadataStore repository.MetadataStore
 }
 
 // NewGraphMetadataManager creates a new graph metadata manager
-func NewGraphMetadataManager() *GraphMetadataManager {
-	return &GraphMetadataManager{}
+func NewGraphMetadataManager(graphStore repository.GraphStore, metadataStore repository.MetadataStore) *GraphMetadataManager {
+	return &GraphMetadataManager{
+		graphStore:    graphStore,
+		metadataStore: metadataStore,
+	}
 }
 
 // AttributeMetadata represents metadata for an attribute in the graph
@@ -54,12 +60,22 @@ type AttributeMetadata struct {
 	Updated       time.Time
 	EndTime       time.Time
 	Schema        map[string]interface{} // Schema information
+	Blob          *BlobInfo              // Content details of blob attributes
+	Access        *auth.AccessPolicy     // Who may read the attribute and its columns, nil when unrestricted
+}
+
+// BlobInfo describes the content of a blob attribute
+type BlobInfo struct {
+	ContentHash string // Hex encoded SHA-256 of the content
+	MimeType    string
+	FileName    string
+	Size        int64
 }
 
 // CreateAttributeNode creates a node in the graph for an attribute
 func (g *GraphMetadataManager) CreateAttribute(ctx context.Context, metadata *AttributeMetadata) error {
-	fmt.Printf("Creating attribute node: Entity=%s, Attribute=%s, StorageType=%s, Path=%s\n",
-		metadata.EntityID, metadata.AttributeName, metadata.StorageType, metadata.StoragePath)
+	slog.DebugContext(ctx, "Creating attribute node", "entity_id", metadata.EntityID, "attribute", metadata.AttributeName,
+		"storage_type", metadata.StorageType, "storage_path", metadata.StoragePath)
 	// create the attribute look up graph
 	err := g.createAttributeLookUpGraph(ctx, metadata)
 	if err != nil {
@@ -93,6 +109,7 @@ func (g *GraphMetadataManager) CreateAttribute(ctx context.Context, metadata *At
 //   - storage_type: Type of storage
 //   - updated: Last update timestamp
 //   - schema: Schema information as a dictionary
+//   - access_policy: Who may read the attribute and its columns, when restricted
 //
 // Storage Path Details:
 //   - storage_database: Connection details or access method
@@ -111,11 +128,22 @@ func (g *GraphMetadataManager) CreateAttribute(ctx context.Context, metadata *At
 // Note: This method creates the attribute node and relationship but does not create
 // the parent entity node itself.
 func (g *GraphMetadataManager) createAttributeLookUpGraph(ctx context.Context, metadata *AttributeMetadata) error {
-	fmt.Printf("Creating attribute look up graph: Entity=%s, Attribute=%s, StorageType=%s, Path=%s\n",
-		metadata.EntityID, metadata.AttributeName, metadata.StorageType, metadata.StoragePath)
+	slog.DebugContext(ctx, "Creating attribute look up graph", "entity_id", metadata.EntityID, "attribute", metadata.AttributeName,
+		"storage_type", metadata.StorageType, "storage_path", metadata.StoragePath)
 	// TODO: Explore a way to update the Look up graph
 	// FIXME: https://github.com/LDFLK/nexoan/issues/288
 
+	// Only admins set or replace access policies; checked before the graph is touched
+	if metadata.Access != nil {
+		stored, err := g.GetAccessPolicy(ctx, metadata.EntityID, metadata.AttributeName)
+		if err != nil {
+			return err
+		}
+		if err := authorizePolicyChange(ctx, metadata.EntityID, metadata.AttributeName, stored, metadata.Access); err != nil {
+			return err
+		}
+	}
+
 	// create the attribute node in the graph
 	// attribute core data is stored in the graph
 	// attribute metadata is stored in the mongo database
@@ -150,57 +178,61 @@ func (g *GraphMetadataManager) createAttributeLookUpGraph(ctx context.Context, m
 		},
 	}
 
-	neo4jRepository, err := dbcommons.GetNeo4jRepository(ctx)
-	if err != nil {
-		log.Printf("[GraphMetadataManager.CreateAttribute] Error getting Neo4j repository: %v", err)
-		return err
-	}
-
 	// Check if the attribute node already exists
-	existingEntity, err := neo4jRepository.ReadGraphEntity(ctx, metadata.AttributeID)
+	existingEntity, err := g.graphStore.ReadGraphEntity(ctx, metadata.AttributeID)
 	if err == nil && existingEntity != nil {
-		log.Printf("[GraphMetadataManager.CreateAttribute] Attribute node already exists: %s, skipping creation", metadata.AttributeID)
+		slog.DebugContext(ctx, "Attribute node already exists, skipping creation", "attribute_id", metadata.AttributeID)
 		// Node already exists, we can still proceed to create/update the relationship
 	} else {
 		// Node doesn't exist, create it
-		success, err := neo4jRepository.HandleGraphEntityCreation(ctx, attributeNode)
+		success, err := g.graphStore.HandleGraphEntityCreation(ctx, attributeNode)
 		if !success {
-			log.Printf("[GraphMetadataManager.CreateAttribute] Error creating attributeNode as a graph entity: %v", err)
+			slog.ErrorContext(ctx, "Error creating attribute node as a graph entity", "attribute_id", metadata.AttributeID, "error", err)
 			return err
 		}
-		log.Printf("[GraphMetadataManager.CreateAttribute] Successfully created attribute node for entity: %s, attribute: %s", metadata.EntityID, metadata.AttributeName)
+		slog.DebugContext(ctx, "Created attribute node", "entity_id", metadata.EntityID, "attribute", metadata.AttributeName)
 
 		// FIXME: This means that when updating an attribute we cannot update the relationship
 		// FIXME: https://github.com/LDFLK/nexoan/issues/346
 		// create the relationship between the entity and the attribute
-		err = neo4jRepository.HandleGraphRelationshipsUpdate(ctx, parentNode)
+		err = g.graphStore.HandleGraphRelationshipsUpdate(ctx, parentNode)
 		if err != nil {
-			log.Printf("[GraphMetadataManager.CreateAttribute] Error creating relationship between entity and attribute: %v", err)
+			slog.ErrorContext(ctx, "Error creating relationship between entity and attribute", "attribute_id", metadata.AttributeID, "error", err)
 			return err
 		}
 	}
 
-	log.Printf("[GraphMetadataManager.CreateAttribute] Successfully created relationship for entity: %s, attribute: %s", metadata.EntityID, metadata.AttributeName)
+	slog.DebugContext(ctx, "Created attribute relationship", "entity_id", metadata.EntityID, "attribute", metadata.AttributeName)
 
-	// create the attribute metadata in the mongo database
+	// create the attribute metadata in the metadata store
 	// stored parameters: attribute_id, attribute_name, storage_type, storage_path, updated, schema
-	mongoRepository := dbcommons.GetMongoRepository(ctx)
 
 	// Check if the attribute metadata already exists
-	existingMetadata, err := mongoRepository.ReadEntity(ctx, metadata.AttributeID)
-	if err == nil && existingMetadata != nil {
-		log.Printf("[GraphMetadataManager.CreateAttribute] Attribute metadata already exists: %s, skipping creation", metadata.AttributeID)
+	existingMetadata, err := g.metadataStore.ReadEntity(ctx, metadata.AttributeID)
+	if err == nil && existingMetadata != nil && metadata.Access != nil {
+		// A policy declared on a later write by an admin replaces the stored one
+		if existingMetadata.Metadata == nil {
+			existingMetadata.Metadata = make(map[string]*anypb.Any)
+		}
+		existingMetadata.Metadata[AccessPolicyMetadataKey] = MakeMetadataOfAttributeMetadata(metadata)[AccessPolicyMetadataKey]
+		if err := g.metadataStore.HandleMetadata(ctx, metadata.AttributeID, existingMetadata); err != nil {
+			slog.ErrorContext(ctx, "Error updating attribute access policy", "attribute_id", metadata.AttributeID, "error", err)
+			return err
+		}
+		slog.DebugContext(ctx, "Updated attribute access policy", "attribute_id", metadata.AttributeID)
+	} else if err == nil && existingMetadata != nil {
+		slog.DebugContext(ctx, "Attribute metadata already exists, skipping creation", "attribute_id", metadata.AttributeID)
 	} else {
 		// Metadata doesn't exist, create it
-		_, err = mongoRepository.CreateEntity(ctx, attributeNode)
+		err = g.metadataStore.HandleMetadata(ctx, metadata.AttributeID, attributeNode)
 		if err != nil {
-			log.Printf("[GraphMetadataManager.CreateAttribute] Error creating attribute metadata: %v", err)
+			slog.ErrorContext(ctx, "Error creating attribute metadata", "attribute_id", metadata.AttributeID, "error", err)
 			return err
 		}
-		log.Printf("[GraphMetadataManager.CreateAttribute] Successfully created attribute metadata for entity: %s, attribute: %s", metadata.EntityID, metadata.AttributeName)
+		slog.DebugContext(ctx, "Created attribute metadata", "entity_id", metadata.EntityID, "attribute", metadata.AttributeName)
 	}
 
-	log.Print("Lookup graph created successfully!")
+	slog.DebugContext(ctx, "Lookup graph created", "attribute_id", metadata.AttributeID)
 
 	return nil
 }
@@ -210,7 +242,7 @@ func MakeMetadataOfAttributeMetadata(metadata *AttributeMetadata) map[string]*an
 	entityMetadata := make(map[string]*anypb.Any)
 
 	// Add attribute_id
-	entityMetadata["attribute_id"] = commons.ConvertStringToAny(metadata.AttributeID)
+	entityMetadata[AttributeIDMetadataKey] = commons.ConvertStringToAny(metadata.AttributeID)
 
 	// Add storage_path
 	entityMetadata["storage_path"] = commons.ConvertStringToAny(metadata.StoragePath)
@@ -226,9 +258,39 @@ func MakeMetadataOfAttributeMetadata(metadata *AttributeMetadata) map[string]*an
 		entityMetadata["schema"] = commons.ConvertMapToAny(metadata.Schema)
 	}
 
+	// Add the content details of blob attributes
+	if metadata.Blob != nil {
+		entityMetadata["content_hash"] = commons.ConvertStringToAny(metadata.Blob.ContentHash)
+		entityMetadata["mime_type"] = commons.ConvertStringToAny(metadata.Blob.MimeType)
+		entityMetadata["file_name"] = commons.ConvertStringToAny(metadata.Blob.FileName)
+		entityMetadata["size"] = commons.ConvertStringToAny(strconv.FormatInt(metadata.Blob.Size, 10))
+	}
+
+	// Add the access policy of restricted attributes
+	if metadata.Access != nil {
+		policyJSON, err := json.Marshal(metadata.Access)
+		if err == nil {
+			entityMetadata[AccessPolicyMetadataKey] = commons.ConvertStringToAny(string(policyJSON))
+		}
+	}
+
 	return entityMetadata
 }
 
+// blobInfoFromMetadata reads the content details of a blob attribute, returning nil for other attributes
+func blobInfoFromMetadata(entity *pb.Entity) *BlobInfo {
+	contentHash, mimeType, fileName, size := commons.ExtractBlobMetadataFields(entity)
+	if contentHash == "" {
+		return nil
+	}
+	return &BlobInfo{
+		ContentHash: contentHash,
+		MimeType:    mimeType,
+		FileName:    fileName,
+		Size:        size,
+	}
+}
+
 // MakeRelationshipProto creates a Relationship protobuf object for IS_ATTRIBUTE relationship
 func MakeRelationshipFromAttributeMetadata(metadata *AttributeMetadata) *pb.Relationship {
 	return &pb.Relationship{
@@ -243,27 +305,21 @@ func MakeRelationshipFromAttributeMetadata(metadata *AttributeMetadata) *pb.Rela
 
 // GetAttributeMetadata retrieves metadata for an attribute
 func (g *GraphMetadataManager) GetAttribute(ctx context.Context, entityID string, attributeName string, startTime time.Time) (*AttributeMetadata, error) {
-	fmt.Printf("Getting attribute metadata: EntityID=%s, AttributeName=%s\n", entityID, attributeName)
-
-	neo4jRepository, err := dbcommons.GetNeo4jRepository(ctx)
-	if err != nil {
-		log.Printf("[GraphMetadataManager.GetAttribute] Error getting Neo4j repository: %v", err)
-		return nil, err
-	}
+	slog.DebugContext(ctx, "Getting attribute metadata", "entity_id", entityID, "attribute", attributeName)
 
 	// Get all IS_ATTRIBUTE relationships for the entity
-	filteredRelationships, err := neo4jRepository.ReadFilteredRelationships(ctx, entityID, map[string]interface{}{"name": IS_ATTRIBUTE_RELATIONSHIP, "direction": IS_ATTRIBUTE_RELATIONSHIP_DIRECTION, "startTime": startTime.Format(time.RFC3339)}, "")
+	filteredRelationships, err := g.graphStore.ReadFilteredRelationships(ctx, entityID, map[string]interface{}{"name": IS_ATTRIBUTE_RELATIONSHIP, "direction": IS_ATTRIBUTE_RELATIONSHIP_DIRECTION, "startTime": startTime.Format(time.RFC3339)}, "")
 	if err != nil {
-		log.Printf("[GraphMetadataManager.GetAttribute] Error getting relationships: %v", err)
+		slog.ErrorContext(ctx, "Error getting attribute relationships", "entity_id", entityID, "error", err)
 		return nil, err
 	}
 
 	if len(filteredRelationships) == 0 {
-		log.Printf("[GraphMetadataManager.GetAttribute] No attributes found for entity %s", entityID)
+		slog.DebugContext(ctx, "No attributes found", "entity_id", entityID)
 		return nil, fmt.Errorf("no attributes found for entity %s", entityID)
 	}
 
-	fmt.Printf("Number of related entities: %v\n", len(filteredRelationships))
+	slog.DebugContext(ctx, "Found attribute relationships", "entity_id", entityID, "count", len(filteredRelationships))
 
 	// Find the specific attribute by name
 	var targetAttributeID string
@@ -276,14 +332,14 @@ func (g *GraphMetadataManager) GetAttribute(ctx context.Context, entityID string
 		}
 
 		// Get the attribute entity from Neo4j to check its name
-		_, attributeNameTimeBased, _, _, err := neo4jRepository.GetGraphEntity(ctx, attributeID)
+		_, attributeNameTimeBased, _, _, err := g.graphStore.GetGraphEntity(ctx, attributeID)
 		if err != nil {
-			log.Printf("[GraphMetadataManager.GetAttribute] Error getting attribute entity %s: %v", attributeID, err)
+			slog.ErrorContext(ctx, "Error getting attribute entity", "attribute_id", attributeID, "error", err)
 			continue
 		}
 
 		attributeNameStr := commons.ExtractStringFromAny(attributeNameTimeBased.Value)
-		fmt.Printf("Search name: '%s' vs Entity name: '%s'\n", attributeName, attributeNameStr)
+		slog.DebugContext(ctx, "Comparing attribute names", "attribute", attributeName, "candidate", attributeNameStr)
 
 		// Check if this entity has the target attribute name
 		if attributeNameStr == attributeName {
@@ -294,15 +350,14 @@ func (g *GraphMetadataManager) GetAttribute(ctx context.Context, entityID string
 	}
 
 	if !found {
-		log.Printf("[GraphMetadataManager.GetAttribute] Attribute '%s' not found for entity %s", attributeName, entityID)
+		slog.DebugContext(ctx, "Attribute not found", "attribute", attributeName, "entity_id", entityID)
 		return nil, fmt.Errorf("attribute '%s' not found for entity %s", attributeName, entityID)
 	}
 
 	// Get the attribute metadata from MongoDB
-	mongoRepository := dbcommons.GetMongoRepository(ctx)
-	attributeMetadataEntity, err := mongoRepository.ReadEntity(ctx, targetAttributeID)
+	attributeMetadataEntity, err := g.metadataStore.ReadEntity(ctx, targetAttributeID)
 	if err != nil {
-		log.Printf("[GraphMetadataManager.GetAttribute] Error getting attribute metadata from MongoDB for attribute %s (entity %s): %v", targetAttributeID, entityID, err)
+		slog.ErrorContext(ctx, "Error getting attribute metadata from MongoDB", "attribute_id", targetAttributeID, "entity_id", entityID, "error", err)
 		return nil, fmt.Errorf("failed to get attribute metadata from MongoDB for attribute %s (entity %s): %w", targetAttributeID, entityID, err)
 	}
 
@@ -311,18 +366,23 @@ func (g *GraphMetadataManager) GetAttribute(ctx context.Context, entityID string
 
 	// Convert storage type string to StorageType enum
 	storageType := commons.ConvertStorageTypeStringToEnum(storageTypeStr)
-	log.Printf("[GraphMetadataManager.GetAttribute] storageType: %s", storageType)
+	slog.DebugContext(ctx, "Resolved attribute storage type", "attribute_id", targetAttributeID, "storage_type", storageType)
 
 	// Get creation time from the attribute entity
-	_, _, createdTimeStr, _, err := neo4jRepository.GetGraphEntity(ctx, targetAttributeID)
+	_, _, createdTimeStr, _, err := g.graphStore.GetGraphEntity(ctx, targetAttributeID)
 	if err != nil {
-		log.Printf("[GraphMetadataManager.GetAttribute] Error getting creation time for attribute %s: %v", targetAttributeID, err)
+		slog.ErrorContext(ctx, "Error getting attribute creation time", "attribute_id", targetAttributeID, "error", err)
 		createdTimeStr = ""
 	}
 
 	createdTime := commons.ParseTimestamp(createdTimeStr, fmt.Sprintf("attribute %s (entity %s) creation time", targetAttributeID, entityID))
 	updatedTime := commons.ParseTimestamp(updatedStr, fmt.Sprintf("attribute %s (entity %s) update time", targetAttributeID, entityID))
 
+	access, err := accessPolicyFromMetadata(attributeMetadataEntity)
+	if err != nil {
+		return nil, fmt.Errorf("invalid access policy for attribute %s (entity %s): %v", targetAttributeID, entityID, err)
+	}
+
 	return &AttributeMetadata{
 		EntityID:      entityID,
 		AttributeID:   targetAttributeID,
@@ -332,22 +392,18 @@ func (g *GraphMetadataManager) GetAttribute(ctx context.Context, entityID string
 		Created:       createdTime,
 		Updated:       updatedTime,
 		Schema:        schemaMap,
+		Blob:          blobInfoFromMetadata(attributeMetadataEntity),
+		Access:        access,
 	}, nil
 }
 
 // ListEntityAttributes lists all attributes for an entity
 func (g *GraphMetadataManager) ListAttributes(ctx context.Context, entityID string) ([]*AttributeMetadata, error) {
-	fmt.Printf("Listing attributes for entity: %s\n", entityID)
-
-	neo4jRepository, err := dbcommons.GetNeo4jRepository(ctx)
-	if err != nil {
-		log.Printf("[GraphMetadataManager.ListAttributes] Error getting Neo4j repository: %v", err)
-		return nil, err
-	}
+	slog.DebugContext(ctx, "Listing attributes", "entity_id", entityID)
 
-	filteredRelationships, err := neo4jRepository.ReadFilteredRelationships(ctx, entityID, map[string]interface{}{"name": IS_ATTRIBUTE_RELATIONSHIP, "direction": IS_ATTRIBUTE_RELATIONSHIP_DIRECTION}, "")
+	filteredRelationships, err := g.graphStore.ReadFilteredRelationships(ctx, entityID, map[string]interface{}{"name": IS_ATTRIBUTE_RELATIONSHIP, "direction": IS_ATTRIBUTE_RELATIONSHIP_DIRECTION}, "")
 	if err != nil {
-		log.Printf("[GraphMetadataManager.ListAttributes] Error getting relationships: %v", err)
+		slog.ErrorContext(ctx, "Error getting attribute relationships", "entity_id", entityID, "error", err)
 		return nil, err
 	}
 
@@ -362,19 +418,18 @@ func (g *GraphMetadataManager) ListAttributes(ctx context.Context, entityID stri
 		// stored parameters: id, kind, name, created
 		//  out of that the GetGraphEntity returns name and createdTime only and we ignore the terminated in this context.
 		// TODO: determine if an attribute needs to be teriminated based on various conditions.
-		_, attributeName, createdTimeStr, _, err := neo4jRepository.GetGraphEntity(ctx, attributeID)
+		_, attributeName, createdTimeStr, _, err := g.graphStore.GetGraphEntity(ctx, attributeID)
 		if err != nil {
-			log.Printf("[GraphMetadataManager.ListAttributes] Error verifying attribute %s in graph for entity %s: %v", attributeID, entityID, err)
+			slog.ErrorContext(ctx, "Error verifying attribute in graph", "attribute_id", attributeID, "entity_id", entityID, "error", err)
 			return nil, fmt.Errorf("failed to verify attribute %s in graph for entity %s: %w", attributeID, entityID, err)
 		}
 
 		attributeNameStr := commons.ExtractStringFromAny(attributeName.Value)
 
 		// Get the attribute metadata from the mongo database
-		mongoRepository := dbcommons.GetMongoRepository(ctx)
-		attributeMetadataEntity, err := mongoRepository.ReadEntity(ctx, attributeID)
+		attributeMetadataEntity, err := g.metadataStore.ReadEntity(ctx, attributeID)
 		if err != nil {
-			log.Printf("[GraphMetadataManager.ListAttributes] Error getting attribute metadata from MongoDB for attribute %s (entity %s): %v", attributeID, entityID, err)
+			slog.ErrorContext(ctx, "Error getting attribute metadata from MongoDB", "attribute_id", attributeID, "entity_id", entityID, "error", err)
 			return nil, fmt.Errorf("failed to get attribute metadata from MongoDB for attribute %s (entity %s): %w", attributeID, entityID, err)
 		}
 
@@ -388,6 +443,11 @@ func (g *GraphMetadataManager) ListAttributes(ctx context.Context, entityID stri
 		createdTime := commons.ParseTimestamp(createdTimeStr, fmt.Sprintf("attribute %s (entity %s) creation time", attributeID, entityID))
 		updatedTime := commons.ParseTimestamp(updatedStr, fmt.Sprintf("attribute %s (entity %s) update time", attributeID, entityID))
 
+		access, err := accessPolicyFromMetadata(attributeMetadataEntity)
+		if err != nil {
+			return nil, fmt.Errorf("invalid access policy for attribute %s (entity %s): %v", attributeID, entityID, err)
+		}
+
 		attrMetadata := &AttributeMetadata{
 			EntityID:      entityID,
 			AttributeID:   attributeID,
@@ -397,6 +457,8 @@ func (g *GraphMetadataManager) ListAttributes(ctx context.Context, entityID stri
 			Created:       createdTime,
 			Updated:       updatedTime,
 			Schema:        schemaMap,
+			Blob:          blobInfoFromMetadata(attributeMetadataEntity),
+			Access:        access,
 		}
 		attributes = append(attributes, attrMetadata)
 	}
@@ -408,7 +470,7 @@ func (g *GraphMetadataManager) ListAttributes(ctx context.Context, entityID stri
 func (g *GraphMetadataManager) UpdateAttribute(ctx context.Context, metadata *AttributeMetadata) error {
 	// TODO: Implement Neo4j or graph database connection
 	// This would update the attribute node properties
-	fmt.Printf("Updating attribute metadata: Entity=%s, Attribute=%s\n", metadata.EntityID, metadata.AttributeName)
+	slog.DebugContext(ctx, "Updating attribute metadata", "entity_id", metadata.EntityID, "attribute", metadata.AttributeName)
 
 	return nil
 }
@@ -418,7 +480,7 @@ func (g *GraphMetadataManager) DeleteAttribute(ctx context.Context, entityID, at
 	// TODO: Implement Neo4j or graph database connection
 	// This would delete the attribute node and its IS_ATTRIBUTE relationship
 
-	fmt.Printf("Deleting attribute node: Entity=%s, Attribute=%s\n", entityID, attributeName)
+	slog.DebugContext(ctx, "Deleting attribute node", "entity_id", entityID, "attribute", attributeName)
 
 	return nil
 }
@@ -459,6 +521,9 @@ func GenerateStoragePath(entityID, attributeName string, storageType storageinfe
 		return fmt.Sprintf("graphs/attr_%s_%s", entityID, attributeName)
 	case storageinference.MapData, storageinference.ListData, storageinference.ScalarData:
 		return fmt.Sprintf("documents/attr_%s_%s", entityID, attributeName)
+	case storageinference.BlobData:
+		// Each version of the content is kept below this prefix under its content hash
+		return fmt.Sprintf("blobs/attr_%s_%s", entityID, attributeName)
 	default:
 		return fmt.Sprintf("unknown/attr_%s_%s", entityID, attributeName)
 	}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	dbcommons "lk/datafoundation/core-api/commons/db"
 	"lk/datafoundation/core-api/pkg/storageinference"
 
 	"github.com/stretchr/testify/assert"
@@ -12,11 +13,15 @@ import (
 
 // TestGraphMetadataManager tests the graph metadata manager functionality
 func TestGraphMetadataManager(t *testing.T) {
-	manager := NewGraphMetadataManager()
-	assert.NotNil(t, manager)
-
 	ctx := context.Background()
 
+	neo4jRepository, err := dbcommons.GetNeo4jRepository(ctx)
+	if err != nil {
+		t.Fatalf("failed to get Neo4j repository: %v", err)
+	}
+	manager := NewGraphMetadataManager(neo4jRepository, dbcommons.GetMongoRepository(ctx))
+	assert.NotNil(t, manager)
+
 	parentEntityID := "engine-test-entity-1"
 
 	createdTime := time.Now()
@@ -140,7 +145,7 @@ func TestGraphMetadataIntegration(t *testing.T) {
 	})
 	assert.NoError(t, err)
 
-	processor := NewEntityAttributeProcessor()
+	processor := newTestProcessor(t)
 	ctx := context.Background()
 
 	// save the parent entity in the database
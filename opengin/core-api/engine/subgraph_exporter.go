@@ -0,0 +1,252 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"lk/datafoundation/core-api/db/repository"
+	"lk/datafoundation/core-api/pkg/graphexport"
+	"lk/datafoundation/core-api/pkg/tenant"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// DefaultExportMaxDepth is the traversal depth used when the traversal spec does not set one
+const DefaultExportMaxDepth = 2
+
+// MaxExportMaxDepth is the deepest traversal an export may request
+const MaxExportMaxDepth = 10
+
+// MaxExportEntities bounds the size of a single export
+const MaxExportEntities = 10000
+
+// TraversalSpec describes which part of the graph an export walks
+type TraversalSpec struct {
+	RelationshipNames []string // Only follow these relationship types (all types if empty)
+	Direction         string   // OUTGOING, INCOMING or empty to follow both
+	MaxDepth          int      // Number of hops from the root (DefaultExportMaxDepth if 0)
+	ActiveAt          string   // Only follow relationships active at this instant (RFC3339)
+	IncludeMetadata   bool     // Attach the MongoDB metadata of every exported entity
+}
+
+// SubgraphExporter collects the part of the graph reachable from a root entity
+type SubgraphExporter struct {
+	graphStore    repository.GraphStore
+	metadataStore repository.MetadataStore
+}
+
+// NewSubgraphExporter creates a new subgraph exporter.
+// The metadata store may be nil when metadata is never requested.
+func NewSubgraphExporter(graphStore repository.GraphStore, metadataStore repository.MetadataStore) *SubgraphExporter {
+	return &SubgraphExporter{
+		graphStore:    graphStore,
+		metadataStore: metadataStore,
+	}
+}
+
+// Export walks the graph breadth first from the root entity and returns the visited
+// entities together with the relationships that connect them.
+// Attribute lookup relationships (IS_ATTRIBUTE) are skipped unless they are requested by name.
+func (e *SubgraphExporter) Export(ctx context.Context, rootEntityID string, spec TraversalSpec) (*graphexport.Subgraph, error) {
+	if rootEntityID == "" {
+		return nil, fmt.Errorf("root entity Id cannot be empty")
+	}
+	if e.graphStore == nil {
+		return nil, fmt.Errorf("graph store is required for exporting a subgraph")
+	}
+	if spec.IncludeMetadata && e.metadataStore == nil {
+		return nil, fmt.Errorf("metadata store is required for exporting metadata")
+	}
+
+	if spec.MaxDepth <= 0 {
+		spec.MaxDepth = DefaultExportMaxDepth
+	}
+	if spec.MaxDepth > MaxExportMaxDepth {
+		return nil, fmt.Errorf("maxDepth %d exceeds the allowed maximum of %d", spec.MaxDepth, MaxExportMaxDepth)
+	}
+	if spec.Direction != "" && spec.Direction != "OUTGOING" && spec.Direction != "INCOMING" {
+		return nil, fmt.Errorf("invalid direction %s, expected OUTGOING or INCOMING", spec.Direction)
+	}
+
+	var activeAt time.Time
+	if spec.ActiveAt != "" {
+		parsed, err := time.Parse(time.RFC3339, spec.ActiveAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid activeAt %s: %v", spec.ActiveAt, err)
+		}
+		activeAt = parsed
+	}
+
+	allowedNames := make(map[string]bool, len(spec.RelationshipNames))
+	for _, name := range spec.RelationshipNames {
+		allowedNames[name] = true
+	}
+
+	graph := &graphexport.Subgraph{
+		RootId:   rootEntityID,
+		ActiveAt: spec.ActiveAt,
+		Tenant:   tenant.FromContext(ctx),
+	}
+
+	type queueItem struct {
+		id    string
+		depth int
+	}
+
+	visited := map[string]bool{rootEntityID: true}
+	seenRelationships := make(map[string]bool)
+	queue := []queueItem{{id: rootEntityID, depth: 0}}
+
+	for len(queue) > 0 {
+		item := queue[0]
+		queue = queue[1:]
+
+		entity, err := e.readEntity(ctx, item.id, spec.IncludeMetadata)
+		if err != nil {
+			return nil, err
+		}
+		graph.Entities = append(graph.Entities, *entity)
+
+		if item.depth >= spec.MaxDepth {
+			continue
+		}
+
+		relData, err := e.graphStore.ReadRelationships(ctx, item.id)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error reading relationships", "entity_id", item.id, "error", err)
+			return nil, fmt.Errorf("error reading relationships for entity %s: %v", item.id, err)
+		}
+
+		// Keep the output stable between runs
+		sort.Slice(relData, func(i, j int) bool {
+			return fmt.Sprint(relData[i]["relationshipID"]) < fmt.Sprint(relData[j]["relationshipID"])
+		})
+
+		for _, rel := range relData {
+			relType, _ := rel["type"].(string)
+			relatedID, _ := rel["relatedID"].(string)
+			direction, _ := rel["direction"].(string)
+			relID, _ := rel["relationshipID"].(string)
+			created, _ := rel["Created"].(string)
+			terminated, _ := rel["Terminated"].(string)
+
+			if len(allowedNames) > 0 && !allowedNames[relType] {
+				continue
+			}
+			if len(allowedNames) == 0 && relType == IS_ATTRIBUTE_RELATIONSHIP {
+				continue
+			}
+			if spec.Direction != "" && direction != spec.Direction {
+				continue
+			}
+			if !activeAt.IsZero() && !isActiveAt(created, terminated, activeAt) {
+				continue
+			}
+
+			if !visited[relatedID] {
+				if len(visited) >= MaxExportEntities {
+					return nil, fmt.Errorf("export from %s exceeds the maximum of %d entities, narrow the traversal spec", rootEntityID, MaxExportEntities)
+				}
+				visited[relatedID] = true
+				queue = append(queue, queueItem{id: relatedID, depth: item.depth + 1})
+			}
+
+			if seenRelationships[relID] {
+				continue
+			}
+			seenRelationships[relID] = true
+
+			sourceID, targetID := item.id, relatedID
+			if direction == "INCOMING" {
+				sourceID, targetID = relatedID, item.id
+			}
+			graph.Relationships = append(graph.Relationships, graphexport.Relationship{
+				Id:        relID,
+				Name:      relType,
+				SourceId:  sourceID,
+				TargetId:  targetID,
+				StartTime: created,
+				EndTime:   terminated,
+			})
+		}
+	}
+
+	return graph, nil
+}
+
+// readEntity reads an entity from Neo4j and, if requested, its metadata from MongoDB
+func (e *SubgraphExporter) readEntity(ctx context.Context, entityID string, includeMetadata bool) (*graphexport.Entity, error) {
+	entityMap, err := e.graphStore.ReadGraphEntity(ctx, entityID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error reading entity", "entity_id", entityID, "error", err)
+		return nil, fmt.Errorf("error reading entity %s: %v", entityID, err)
+	}
+
+	entity := &graphexport.Entity{Id: entityID}
+	entity.MajorKind, _ = entityMap["MajorKind"].(string)
+	entity.MinorKind, _ = entityMap["MinorKind"].(string)
+	entity.Name, _ = entityMap["Name"].(string)
+	entity.Created, _ = entityMap["Created"].(string)
+	entity.Terminated, _ = entityMap["Terminated"].(string)
+
+	if includeMetadata {
+		metadata, err := e.metadataStore.GetMetadata(ctx, entityID)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error reading metadata", "entity_id", entityID, "error", err)
+			return nil, fmt.Errorf("error reading metadata for entity %s: %v", entityID, err)
+		}
+		metadata = MaskMetadata(ctx, metadata)
+		if len(metadata) > 0 {
+			entity.Metadata = make(map[string]string, len(metadata))
+			for key, value := range metadata {
+				entity.Metadata[key] = metadataValueToString(value)
+			}
+		}
+	}
+
+	return entity, nil
+}
+
+// isActiveAt checks whether a relationship time window contains the given instant
+func isActiveAt(created string, terminated string, activeAt time.Time) bool {
+	start, err := time.Parse(time.RFC3339, created)
+	if err != nil || start.After(activeAt) {
+		return false
+	}
+	if terminated == "" {
+		return true
+	}
+	end, err := time.Parse(time.RFC3339, terminated)
+	if err != nil {
+		return false
+	}
+	return end.After(activeAt)
+}
+
+// metadataValueToString renders a metadata value as text.
+// String values are returned as is, any other message is rendered as JSON.
+func metadataValueToString(value *anypb.Any) string {
+	if value == nil {
+		return ""
+	}
+
+	message, err := value.UnmarshalNew()
+	if err != nil {
+		return string(value.GetValue())
+	}
+
+	if stringValue, ok := message.(*wrapperspb.StringValue); ok {
+		return stringValue.GetValue()
+	}
+
+	encoded, err := protojson.Marshal(message)
+	if err != nil {
+		return string(value.GetValue())
+	}
+	return string(encoded)
+}
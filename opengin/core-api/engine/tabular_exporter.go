@@ -0,0 +1,135 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"lk/datafoundation/core-api/db/repository"
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/logging"
+	"lk/datafoundation/core-api/pkg/tabularexport"
+)
+
+// TabularExporter reads tabular attributes in their typed form for the columnar exports
+type TabularExporter struct {
+	tabularStore repository.TabularStore
+	graphManager *GraphMetadataManager
+	// PageRows is the number of rows read from the store at a time, tabularexport.BatchRows by
+	// default so each page becomes one Arrow record batch or Parquet row group
+	PageRows int
+}
+
+// NewTabularExporter creates a new tabular exporter. Without a graph store the access policies of
+// attributes are not looked up, which only suits tools run with direct database access.
+func NewTabularExporter(graphStore repository.GraphStore, metadataStore repository.MetadataStore, tabularStore repository.TabularStore) *TabularExporter {
+	exporter := &TabularExporter{tabularStore: tabularStore, PageRows: tabularexport.BatchRows}
+	if graphStore != nil {
+		exporter.graphManager = NewGraphMetadataManager(graphStore, metadataStore)
+	}
+	return exporter
+}
+
+// TabularExport is an export in progress: the columns and number of the selected rows, and the pages
+// of rows still to be read
+type TabularExport struct {
+	Columns  []*pb.TabularColumn
+	RowCount int64
+
+	exporter  *TabularExporter
+	tableName string
+	query     repository.TabularQuery
+	first     []*pb.TabularRow // The page read along with the columns, returned by the first Next
+	read      int64            // Rows returned so far
+}
+
+// Export starts exporting the rows of a tabular attribute the query selects. The caller must be allowed
+// to read the attribute, and columns it may not see are left out or redacted as they are in entity reads.
+// The rows are counted and the first page is read to learn the columns; Next returns the pages.
+func (e *TabularExporter) Export(ctx context.Context, entityID, attrName string, query repository.TabularQuery) (*TabularExport, error) {
+	if entityID == "" || attrName == "" {
+		return nil, fmt.Errorf("entity Id and attribute name are required for exporting a tabular attribute")
+	}
+	if e.tabularStore == nil {
+		return nil, fmt.Errorf("tabular store is required for exporting a tabular attribute")
+	}
+	slog.DebugContext(ctx, "Exporting tabular attribute", "entity_id", entityID, "attribute", attrName, "filters", logging.Payload(query.Filters), "fields", logging.Payload(query.Fields), "written_from", query.WrittenFrom, "written_until", query.WrittenUntil)
+
+	if e.graphManager != nil {
+		policy, err := e.graphManager.GetAccessPolicy(ctx, entityID, attrName)
+		if err != nil {
+			return nil, err
+		}
+		if policy != nil {
+			if err := policy.Authorize(ctx, fmt.Sprintf("attribute %s of %s", attrName, entityID)); err != nil {
+				return nil, err
+			}
+		}
+		omit, redact := policy.HiddenColumns(ctx)
+		query.Mask.Omit = append(query.Mask.Omit, omit...)
+		query.Mask.Redact = append(query.Mask.Redact, redact...)
+	}
+
+	tableName, err := e.tabularStore.AttributeTable(ctx, entityID, attrName)
+	if err != nil {
+		return nil, err
+	}
+	count, err := e.tabularStore.CountData(ctx, tableName, query)
+	if err != nil {
+		return nil, fmt.Errorf("error reading attribute %s of %s: %v", attrName, entityID, err)
+	}
+	export := &TabularExport{RowCount: count, exporter: e, tableName: tableName, query: query}
+	data, err := export.page(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading attribute %s of %s: %v", attrName, entityID, err)
+	}
+	export.Columns, export.first = data.Columns, data.Rows
+	return export, nil
+}
+
+// Next returns the next page of rows, or io.EOF once RowCount rows have been returned. Rows written
+// after the export started are left out, so the pages add up to RowCount.
+func (x *TabularExport) Next(ctx context.Context) ([]*pb.TabularRow, error) {
+	if x.first != nil {
+		rows := x.first
+		x.first = nil
+		x.read += int64(len(rows))
+		return rows, nil
+	}
+	if x.read >= x.RowCount {
+		return nil, io.EOF
+	}
+	data, err := x.page(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(data.Rows) == 0 {
+		return nil, fmt.Errorf("table %s has %d rows rather than %d", x.tableName, x.read, x.RowCount)
+	}
+	if len(data.Columns) != len(x.Columns) {
+		return nil, fmt.Errorf("columns of table %s changed during the export", x.tableName)
+	}
+	x.read += int64(len(data.Rows))
+	return data.Rows, nil
+}
+
+// page reads the page of rows after those returned so far
+func (x *TabularExport) page(ctx context.Context) (*pb.TabularData, error) {
+	query := x.query
+	query.Offset = int(x.read)
+	query.Limit = int(min(int64(x.exporter.PageRows), x.RowCount-x.read))
+	if query.Limit > 0 {
+		return x.exporter.tabularStore.GetTypedData(ctx, x.tableName, query)
+	}
+
+	// No rows are selected but the columns are still needed, and a zero Limit would read every row.
+	// A row written since the count is dropped.
+	query.Limit = 1
+	data, err := x.exporter.tabularStore.GetTypedData(ctx, x.tableName, query)
+	if err != nil {
+		return nil, err
+	}
+	data.Rows = nil
+	return data, nil
+}
@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"lk/datafoundation/core-api/db/repository"
+	memoryrepository "lk/datafoundation/core-api/db/repository/memory"
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/schema"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// TestTabularExporterPages tests that exports read the rows a page at a time and stop at the counted rows
+func TestTabularExporterPages(t *testing.T) {
+	ctx := context.Background()
+	store := memoryrepository.NewTabularStore()
+	data, err := structpb.NewStruct(map[string]interface{}{
+		"columns": []interface{}{"year", "amount"},
+		"rows": []interface{}{
+			[]interface{}{2021, 1.5}, []interface{}{2022, 2.5}, []interface{}{2023, 3.5},
+			[]interface{}{2024, 4.5}, []interface{}{2025, 5.5},
+		},
+	})
+	require.NoError(t, err)
+	value, err := anypb.New(data)
+	require.NoError(t, err)
+	schemaInfo, err := schema.GenerateSchema(value)
+	require.NoError(t, err)
+	require.NoError(t, store.HandleTabularData(ctx, "exporter-entity", "budget", &pb.TimeBasedValue{Value: value}, schemaInfo))
+
+	exporter := NewTabularExporter(nil, nil, store)
+	exporter.PageRows = 2
+	export, err := exporter.Export(ctx, "exporter-entity", "budget", repository.TabularQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), export.RowCount)
+	require.Len(t, export.Columns, 2)
+	assert.Equal(t, "int", export.Columns[0].Type)
+
+	var pages []int
+	var years []int64
+	for {
+		rows, err := export.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		pages = append(pages, len(rows))
+		for _, row := range rows {
+			years = append(years, row.Values[0].GetIntValue())
+		}
+	}
+	assert.Equal(t, []int{2, 2, 1}, pages)
+	assert.Equal(t, []int64{2021, 2022, 2023, 2024, 2025}, years)
+
+	empty, err := exporter.Export(ctx, "exporter-entity", "budget", repository.TabularQuery{Filters: map[string]interface{}{"year": 1999}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), empty.RowCount)
+	assert.Len(t, empty.Columns, 2, "the columns are known without rows")
+	_, err = empty.Next(ctx)
+	assert.Equal(t, io.EOF, err)
+}
@@ -10,6 +10,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	anypb "google.golang.org/protobuf/types/known/anypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -369,6 +370,7 @@ type ReadEntityRequest struct {
 	Entity        *Entity                `protobuf:"bytes,1,opt,name=entity,proto3" json:"entity,omitempty"`
 	Output        []string               `protobuf:"bytes,2,rep,name=output,proto3" json:"output,omitempty"`
 	ActiveAt      string                 `protobuf:"bytes,3,opt,name=activeAt,proto3" json:"activeAt,omitempty"`
+	TabularFormat string                 `protobuf:"bytes,4,opt,name=tabularFormat,proto3" json:"tabularFormat,omitempty"` // json (default) or typed, see TabularData
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -424,29 +426,1620 @@ func (x *ReadEntityRequest) GetActiveAt() string {
 	return ""
 }
 
+func (x *ReadEntityRequest) GetTabularFormat() string {
+	if x != nil {
+		return x.TabularFormat
+	}
+	return ""
+}
+
+// TabularData carries tabular attribute values with their types. Reads with tabularFormat typed
+// return it in place of a Struct holding the rows as a JSON string.
+type TabularData struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Columns       []*TabularColumn       `protobuf:"bytes,1,rep,name=columns,proto3" json:"columns,omitempty"`
+	Rows          []*TabularRow          `protobuf:"bytes,2,rep,name=rows,proto3" json:"rows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TabularData) Reset() {
+	*x = TabularData{}
+	mi := &file_types_v1_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TabularData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TabularData) ProtoMessage() {}
+
+func (x *TabularData) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TabularData.ProtoReflect.Descriptor instead.
+func (*TabularData) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TabularData) GetColumns() []*TabularColumn {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *TabularData) GetRows() []*TabularRow {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+// TabularColumn names a column and gives the type its values were stored with
+type TabularColumn struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`            // int, bigint, float, decimal, string, bool, date, time or datetime
+	Precision     int32                  `protobuf:"varint,3,opt,name=precision,proto3" json:"precision,omitempty"` // For decimal columns, the number of digits; 0 when unconstrained
+	Scale         int32                  `protobuf:"varint,4,opt,name=scale,proto3" json:"scale,omitempty"`         // For decimal columns, the number of digits after the point
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TabularColumn) Reset() {
+	*x = TabularColumn{}
+	mi := &file_types_v1_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TabularColumn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TabularColumn) ProtoMessage() {}
+
+func (x *TabularColumn) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TabularColumn.ProtoReflect.Descriptor instead.
+func (*TabularColumn) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TabularColumn) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TabularColumn) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *TabularColumn) GetPrecision() int32 {
+	if x != nil {
+		return x.Precision
+	}
+	return 0
+}
+
+func (x *TabularColumn) GetScale() int32 {
+	if x != nil {
+		return x.Scale
+	}
+	return 0
+}
+
+// TabularRow holds one value per column, in column order
+type TabularRow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []*TabularValue        `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TabularRow) Reset() {
+	*x = TabularRow{}
+	mi := &file_types_v1_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TabularRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TabularRow) ProtoMessage() {}
+
+func (x *TabularRow) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TabularRow.ProtoReflect.Descriptor instead.
+func (*TabularRow) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TabularRow) GetValues() []*TabularValue {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// TabularValue is a single cell; a cell with no value set is null
+type TabularValue struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Value:
+	//
+	//	*TabularValue_IntValue
+	//	*TabularValue_FloatValue
+	//	*TabularValue_StringValue
+	//	*TabularValue_BoolValue
+	//	*TabularValue_TimeValue
+	//	*TabularValue_DecimalValue
+	Value         isTabularValue_Value `protobuf_oneof:"value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TabularValue) Reset() {
+	*x = TabularValue{}
+	mi := &file_types_v1_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TabularValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TabularValue) ProtoMessage() {}
+
+func (x *TabularValue) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TabularValue.ProtoReflect.Descriptor instead.
+func (*TabularValue) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TabularValue) GetValue() isTabularValue_Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *TabularValue) GetIntValue() int64 {
+	if x != nil {
+		if x, ok := x.Value.(*TabularValue_IntValue); ok {
+			return x.IntValue
+		}
+	}
+	return 0
+}
+
+func (x *TabularValue) GetFloatValue() float64 {
+	if x != nil {
+		if x, ok := x.Value.(*TabularValue_FloatValue); ok {
+			return x.FloatValue
+		}
+	}
+	return 0
+}
+
+func (x *TabularValue) GetStringValue() string {
+	if x != nil {
+		if x, ok := x.Value.(*TabularValue_StringValue); ok {
+			return x.StringValue
+		}
+	}
+	return ""
+}
+
+func (x *TabularValue) GetBoolValue() bool {
+	if x != nil {
+		if x, ok := x.Value.(*TabularValue_BoolValue); ok {
+			return x.BoolValue
+		}
+	}
+	return false
+}
+
+func (x *TabularValue) GetTimeValue() *timestamppb.Timestamp {
+	if x != nil {
+		if x, ok := x.Value.(*TabularValue_TimeValue); ok {
+			return x.TimeValue
+		}
+	}
+	return nil
+}
+
+func (x *TabularValue) GetDecimalValue() string {
+	if x != nil {
+		if x, ok := x.Value.(*TabularValue_DecimalValue); ok {
+			return x.DecimalValue
+		}
+	}
+	return ""
+}
+
+type isTabularValue_Value interface {
+	isTabularValue_Value()
+}
+
+type TabularValue_IntValue struct {
+	IntValue int64 `protobuf:"varint,1,opt,name=intValue,proto3,oneof"`
+}
+
+type TabularValue_FloatValue struct {
+	FloatValue float64 `protobuf:"fixed64,2,opt,name=floatValue,proto3,oneof"`
+}
+
+type TabularValue_StringValue struct {
+	StringValue string `protobuf:"bytes,3,opt,name=stringValue,proto3,oneof"`
+}
+
+type TabularValue_BoolValue struct {
+	BoolValue bool `protobuf:"varint,4,opt,name=boolValue,proto3,oneof"`
+}
+
+type TabularValue_TimeValue struct {
+	TimeValue *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=timeValue,proto3,oneof"`
+}
+
+type TabularValue_DecimalValue struct {
+	DecimalValue string `protobuf:"bytes,6,opt,name=decimalValue,proto3,oneof"` // Exact decimal number, such as "1200.50"
+}
+
+func (*TabularValue_IntValue) isTabularValue_Value() {}
+
+func (*TabularValue_FloatValue) isTabularValue_Value() {}
+
+func (*TabularValue_StringValue) isTabularValue_Value() {}
+
+func (*TabularValue_BoolValue) isTabularValue_Value() {}
+
+func (*TabularValue_TimeValue) isTabularValue_Value() {}
+
+func (*TabularValue_DecimalValue) isTabularValue_Value() {}
+
+// TabularIngestReport describes how a tabular attribute given as a file was read. It is returned
+// in place of the value of the attribute in the response to CreateEntity and UpdateEntity.
+type TabularIngestReport struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Format          string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`   // csv, xlsx or parquet
+	Columns         []*TabularColumn       `protobuf:"bytes,2,rep,name=columns,proto3" json:"columns,omitempty"` // Columns with the types they were stored with
+	RowCount        int64                  `protobuf:"varint,3,opt,name=rowCount,proto3" json:"rowCount,omitempty"`
+	SampledRows     int64                  `protobuf:"varint,4,opt,name=sampledRows,proto3" json:"sampledRows,omitempty"`         // Rows whose cells determined the column types, 0 when the file declares them
+	FailedCellCount int64                  `protobuf:"varint,5,opt,name=failedCellCount,proto3" json:"failedCellCount,omitempty"` // Cells that did not hold a value of their column type and were stored as null
+	FailedCells     []*TabularCellError    `protobuf:"bytes,6,rep,name=failedCells,proto3" json:"failedCells,omitempty"`          // The first of those cells
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *TabularIngestReport) Reset() {
+	*x = TabularIngestReport{}
+	mi := &file_types_v1_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TabularIngestReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TabularIngestReport) ProtoMessage() {}
+
+func (x *TabularIngestReport) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TabularIngestReport.ProtoReflect.Descriptor instead.
+func (*TabularIngestReport) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TabularIngestReport) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *TabularIngestReport) GetColumns() []*TabularColumn {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *TabularIngestReport) GetRowCount() int64 {
+	if x != nil {
+		return x.RowCount
+	}
+	return 0
+}
+
+func (x *TabularIngestReport) GetSampledRows() int64 {
+	if x != nil {
+		return x.SampledRows
+	}
+	return 0
+}
+
+func (x *TabularIngestReport) GetFailedCellCount() int64 {
+	if x != nil {
+		return x.FailedCellCount
+	}
+	return 0
+}
+
+func (x *TabularIngestReport) GetFailedCells() []*TabularCellError {
+	if x != nil {
+		return x.FailedCells
+	}
+	return nil
+}
+
+// TabularCellError is a cell of a file that did not convert to the type of its column
+type TabularCellError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Row           int64                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"` // 1-based row of the data, not counting the header
+	Column        string                 `protobuf:"bytes,2,opt,name=column,proto3" json:"column,omitempty"`
+	Value         string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Type          string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TabularCellError) Reset() {
+	*x = TabularCellError{}
+	mi := &file_types_v1_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TabularCellError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TabularCellError) ProtoMessage() {}
+
+func (x *TabularCellError) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TabularCellError.ProtoReflect.Descriptor instead.
+func (*TabularCellError) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *TabularCellError) GetRow() int64 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *TabularCellError) GetColumn() string {
+	if x != nil {
+		return x.Column
+	}
+	return ""
+}
+
+func (x *TabularCellError) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *TabularCellError) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
 // Request message for deleting an entity by ID
 type EntityId struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EntityId) Reset() {
+	*x = EntityId{}
+	mi := &file_types_v1_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EntityId) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EntityId) ProtoMessage() {}
+
+func (x *EntityId) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EntityId.ProtoReflect.Descriptor instead.
+func (*EntityId) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *EntityId) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Request message for updating an entity
+type UpdateEntityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Entity        *Entity                `protobuf:"bytes,2,opt,name=entity,proto3" json:"entity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateEntityRequest) Reset() {
+	*x = UpdateEntityRequest{}
+	mi := &file_types_v1_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateEntityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateEntityRequest) ProtoMessage() {}
+
+func (x *UpdateEntityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateEntityRequest.ProtoReflect.Descriptor instead.
+func (*UpdateEntityRequest) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpdateEntityRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateEntityRequest) GetEntity() *Entity {
+	if x != nil {
+		return x.Entity
+	}
+	return nil
+}
+
+// Empty message response
+type Empty struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Empty) Reset() {
+	*x = Empty{}
+	mi := &file_types_v1_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Empty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Empty) ProtoMessage() {}
+
+func (x *Empty) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
+func (*Empty) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{14}
+}
+
+// EntityList represents a list of entities
+type EntityList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entities      []*Entity              `protobuf:"bytes,1,rep,name=entities,proto3" json:"entities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EntityList) Reset() {
+	*x = EntityList{}
+	mi := &file_types_v1_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EntityList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EntityList) ProtoMessage() {}
+
+func (x *EntityList) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EntityList.ProtoReflect.Descriptor instead.
+func (*EntityList) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *EntityList) GetEntities() []*Entity {
+	if x != nil {
+		return x.Entities
+	}
+	return nil
+}
+
+// Request message for finding the paths connecting two entities
+type PathRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	SourceEntityId    string                 `protobuf:"bytes,1,opt,name=sourceEntityId,proto3" json:"sourceEntityId,omitempty"`
+	TargetEntityId    string                 `protobuf:"bytes,2,opt,name=targetEntityId,proto3" json:"targetEntityId,omitempty"`
+	RelationshipNames []string               `protobuf:"bytes,3,rep,name=relationshipNames,proto3" json:"relationshipNames,omitempty"` // Only traverse these relationship types (all types if empty)
+	ActiveAt          string                 `protobuf:"bytes,4,opt,name=activeAt,proto3" json:"activeAt,omitempty"`                   // Only traverse relationships active at this instant (RFC3339)
+	MaxDepth          int32                  `protobuf:"varint,5,opt,name=maxDepth,proto3" json:"maxDepth,omitempty"`                  // Maximum number of hops (server default if 0)
+	AllShortestPaths  bool                   `protobuf:"varint,6,opt,name=allShortestPaths,proto3" json:"allShortestPaths,omitempty"`  // Return every shortest path instead of a single one
+	Direction         string                 `protobuf:"bytes,7,opt,name=direction,proto3" json:"direction,omitempty"`                 // OUTGOING, INCOMING or empty to ignore direction
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *PathRequest) Reset() {
+	*x = PathRequest{}
+	mi := &file_types_v1_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PathRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PathRequest) ProtoMessage() {}
+
+func (x *PathRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PathRequest.ProtoReflect.Descriptor instead.
+func (*PathRequest) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PathRequest) GetSourceEntityId() string {
+	if x != nil {
+		return x.SourceEntityId
+	}
+	return ""
+}
+
+func (x *PathRequest) GetTargetEntityId() string {
+	if x != nil {
+		return x.TargetEntityId
+	}
+	return ""
+}
+
+func (x *PathRequest) GetRelationshipNames() []string {
+	if x != nil {
+		return x.RelationshipNames
+	}
+	return nil
+}
+
+func (x *PathRequest) GetActiveAt() string {
+	if x != nil {
+		return x.ActiveAt
+	}
+	return ""
+}
+
+func (x *PathRequest) GetMaxDepth() int32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+func (x *PathRequest) GetAllShortestPaths() bool {
+	if x != nil {
+		return x.AllShortestPaths
+	}
+	return false
+}
+
+func (x *PathRequest) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+// EntityPath is an ordered walk from the source entity to the target entity.
+// relationships[i] is the hop connecting entities[i] to entities[i+1].
+type EntityPath struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entities      []*Entity              `protobuf:"bytes,1,rep,name=entities,proto3" json:"entities,omitempty"`
+	Relationships []*Relationship        `protobuf:"bytes,2,rep,name=relationships,proto3" json:"relationships,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EntityPath) Reset() {
+	*x = EntityPath{}
+	mi := &file_types_v1_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EntityPath) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EntityPath) ProtoMessage() {}
+
+func (x *EntityPath) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EntityPath.ProtoReflect.Descriptor instead.
+func (*EntityPath) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *EntityPath) GetEntities() []*Entity {
+	if x != nil {
+		return x.Entities
+	}
+	return nil
+}
+
+func (x *EntityPath) GetRelationships() []*Relationship {
+	if x != nil {
+		return x.Relationships
+	}
+	return nil
+}
+
+// PathList represents a list of paths
+type PathList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Paths         []*EntityPath          `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PathList) Reset() {
+	*x = PathList{}
+	mi := &file_types_v1_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PathList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PathList) ProtoMessage() {}
+
+func (x *PathList) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PathList.ProtoReflect.Descriptor instead.
+func (*PathList) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PathList) GetPaths() []*EntityPath {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+// Request message for exporting the subgraph reachable from a root entity
+type ExportRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	RootEntityId      string                 `protobuf:"bytes,1,opt,name=rootEntityId,proto3" json:"rootEntityId,omitempty"`
+	RelationshipNames []string               `protobuf:"bytes,2,rep,name=relationshipNames,proto3" json:"relationshipNames,omitempty"` // Only follow these relationship types (all types if empty)
+	Direction         string                 `protobuf:"bytes,3,opt,name=direction,proto3" json:"direction,omitempty"`                 // OUTGOING, INCOMING or empty to follow both
+	MaxDepth          int32                  `protobuf:"varint,4,opt,name=maxDepth,proto3" json:"maxDepth,omitempty"`                  // Number of hops from the root (server default if 0)
+	ActiveAt          string                 `protobuf:"bytes,5,opt,name=activeAt,proto3" json:"activeAt,omitempty"`                   // Only follow relationships active at this instant (RFC3339)
+	Format            string                 `protobuf:"bytes,6,opt,name=format,proto3" json:"format,omitempty"`                       // graphml, jgf or cypher
+	IncludeMetadata   bool                   `protobuf:"varint,7,opt,name=includeMetadata,proto3" json:"includeMetadata,omitempty"`    // Attach entity metadata to the exported nodes
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ExportRequest) Reset() {
+	*x = ExportRequest{}
+	mi := &file_types_v1_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportRequest) ProtoMessage() {}
+
+func (x *ExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportRequest.ProtoReflect.Descriptor instead.
+func (*ExportRequest) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ExportRequest) GetRootEntityId() string {
+	if x != nil {
+		return x.RootEntityId
+	}
+	return ""
+}
+
+func (x *ExportRequest) GetRelationshipNames() []string {
+	if x != nil {
+		return x.RelationshipNames
+	}
+	return nil
+}
+
+func (x *ExportRequest) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *ExportRequest) GetMaxDepth() int32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+func (x *ExportRequest) GetActiveAt() string {
+	if x != nil {
+		return x.ActiveAt
+	}
+	return ""
+}
+
+func (x *ExportRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ExportRequest) GetIncludeMetadata() bool {
+	if x != nil {
+		return x.IncludeMetadata
+	}
+	return false
+}
+
+// ExportResponse carries a serialised subgraph
+type ExportResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Format            string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`
+	ContentType       string                 `protobuf:"bytes,2,opt,name=contentType,proto3" json:"contentType,omitempty"`
+	Content           []byte                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	EntityCount       int32                  `protobuf:"varint,4,opt,name=entityCount,proto3" json:"entityCount,omitempty"`
+	RelationshipCount int32                  `protobuf:"varint,5,opt,name=relationshipCount,proto3" json:"relationshipCount,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ExportResponse) Reset() {
+	*x = ExportResponse{}
+	mi := &file_types_v1_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportResponse) ProtoMessage() {}
+
+func (x *ExportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportResponse.ProtoReflect.Descriptor instead.
+func (*ExportResponse) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ExportResponse) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ExportResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *ExportResponse) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *ExportResponse) GetEntityCount() int32 {
+	if x != nil {
+		return x.EntityCount
+	}
+	return 0
+}
+
+func (x *ExportResponse) GetRelationshipCount() int32 {
+	if x != nil {
+		return x.RelationshipCount
+	}
+	return 0
+}
+
+// Request message for a cross-store consistency check
+type ConsistencyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Categories    []string               `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`  // Only report these categories (all if empty)
+	Repair        bool                   `protobuf:"varint,2,opt,name=repair,proto3" json:"repair,omitempty"`         // Rebuild missing lookup graph nodes and metadata from the surviving stores
+	Quarantine    bool                   `protobuf:"varint,3,opt,name=quarantine,proto3" json:"quarantine,omitempty"` // Move dangling tables and documents aside when they cannot be repaired
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConsistencyRequest) Reset() {
+	*x = ConsistencyRequest{}
+	mi := &file_types_v1_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConsistencyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConsistencyRequest) ProtoMessage() {}
+
+func (x *ConsistencyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConsistencyRequest.ProtoReflect.Descriptor instead.
+func (*ConsistencyRequest) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ConsistencyRequest) GetCategories() []string {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *ConsistencyRequest) GetRepair() bool {
+	if x != nil {
+		return x.Repair
+	}
+	return false
+}
+
+func (x *ConsistencyRequest) GetQuarantine() bool {
+	if x != nil {
+		return x.Quarantine
+	}
+	return false
+}
+
+// ConsistencyIssue is a single inconsistency between the stores
+type ConsistencyIssue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Category      string                 `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"` // Dataset id, attribute id, table name or document id
+	EntityId      string                 `protobuf:"bytes,3,opt,name=entityId,proto3" json:"entityId,omitempty"`
+	AttributeName string                 `protobuf:"bytes,4,opt,name=attributeName,proto3" json:"attributeName,omitempty"`
+	TableName     string                 `protobuf:"bytes,5,opt,name=tableName,proto3" json:"tableName,omitempty"`
+	Detail        string                 `protobuf:"bytes,6,opt,name=detail,proto3" json:"detail,omitempty"`
+	Action        string                 `protobuf:"bytes,7,opt,name=action,proto3" json:"action,omitempty"` // repaired, quarantined, skipped or failed when a fix was requested
+	Error         string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConsistencyIssue) Reset() {
+	*x = ConsistencyIssue{}
+	mi := &file_types_v1_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConsistencyIssue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConsistencyIssue) ProtoMessage() {}
+
+func (x *ConsistencyIssue) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConsistencyIssue.ProtoReflect.Descriptor instead.
+func (*ConsistencyIssue) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ConsistencyIssue) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *ConsistencyIssue) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ConsistencyIssue) GetEntityId() string {
+	if x != nil {
+		return x.EntityId
+	}
+	return ""
+}
+
+func (x *ConsistencyIssue) GetAttributeName() string {
+	if x != nil {
+		return x.AttributeName
+	}
+	return ""
+}
+
+func (x *ConsistencyIssue) GetTableName() string {
+	if x != nil {
+		return x.TableName
+	}
+	return ""
+}
+
+func (x *ConsistencyIssue) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+func (x *ConsistencyIssue) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ConsistencyIssue) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// ConsistencyReport is the outcome of a consistency check
+type ConsistencyReport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartedAt     string                 `protobuf:"bytes,1,opt,name=startedAt,proto3" json:"startedAt,omitempty"`
+	FinishedAt    string                 `protobuf:"bytes,2,opt,name=finishedAt,proto3" json:"finishedAt,omitempty"`
+	Scanned       map[string]int64       `protobuf:"bytes,3,rep,name=scanned,proto3" json:"scanned,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // Number of records read per kind
+	Counts        map[string]int64       `protobuf:"bytes,4,rep,name=counts,proto3" json:"counts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`   // Number of issues per category
+	Issues        []*ConsistencyIssue    `protobuf:"bytes,5,rep,name=issues,proto3" json:"issues,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConsistencyReport) Reset() {
+	*x = ConsistencyReport{}
+	mi := &file_types_v1_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConsistencyReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConsistencyReport) ProtoMessage() {}
+
+func (x *ConsistencyReport) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConsistencyReport.ProtoReflect.Descriptor instead.
+func (*ConsistencyReport) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ConsistencyReport) GetStartedAt() string {
+	if x != nil {
+		return x.StartedAt
+	}
+	return ""
+}
+
+func (x *ConsistencyReport) GetFinishedAt() string {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return ""
+}
+
+func (x *ConsistencyReport) GetScanned() map[string]int64 {
+	if x != nil {
+		return x.Scanned
+	}
+	return nil
+}
+
+func (x *ConsistencyReport) GetCounts() map[string]int64 {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+func (x *ConsistencyReport) GetIssues() []*ConsistencyIssue {
+	if x != nil {
+		return x.Issues
+	}
+	return nil
+}
+
+// BlobInfo describes a binary attribute of an entity
+type BlobInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EntityId      string                 `protobuf:"bytes,1,opt,name=entityId,proto3" json:"entityId,omitempty"`
+	AttributeName string                 `protobuf:"bytes,2,opt,name=attributeName,proto3" json:"attributeName,omitempty"`
+	MimeType      string                 `protobuf:"bytes,3,opt,name=mimeType,proto3" json:"mimeType,omitempty"` // Detected from the content when empty on upload
+	FileName      string                 `protobuf:"bytes,4,opt,name=fileName,proto3" json:"fileName,omitempty"`
+	StartTime     string                 `protobuf:"bytes,5,opt,name=startTime,proto3" json:"startTime,omitempty"`     // RFC3339; ignored on download
+	ContentHash   string                 `protobuf:"bytes,6,opt,name=contentHash,proto3" json:"contentHash,omitempty"` // SHA-256 of the content, set by the server
+	Size          int64                  `protobuf:"varint,7,opt,name=size,proto3" json:"size,omitempty"`              // Set by the server
+	StoragePath   string                 `protobuf:"bytes,8,opt,name=storagePath,proto3" json:"storagePath,omitempty"` // Object store key, set by the server
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlobInfo) Reset() {
+	*x = BlobInfo{}
+	mi := &file_types_v1_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlobInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlobInfo) ProtoMessage() {}
+
+func (x *BlobInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlobInfo.ProtoReflect.Descriptor instead.
+func (*BlobInfo) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *BlobInfo) GetEntityId() string {
+	if x != nil {
+		return x.EntityId
+	}
+	return ""
+}
+
+func (x *BlobInfo) GetAttributeName() string {
+	if x != nil {
+		return x.AttributeName
+	}
+	return ""
+}
+
+func (x *BlobInfo) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *BlobInfo) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *BlobInfo) GetStartTime() string {
+	if x != nil {
+		return x.StartTime
+	}
+	return ""
+}
+
+func (x *BlobInfo) GetContentHash() string {
+	if x != nil {
+		return x.ContentHash
+	}
+	return ""
+}
+
+func (x *BlobInfo) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *BlobInfo) GetStoragePath() string {
+	if x != nil {
+		return x.StoragePath
+	}
+	return ""
+}
+
+// BlobChunk is a piece of a streamed blob
+type BlobChunk struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Part:
+	//
+	//	*BlobChunk_Info
+	//	*BlobChunk_Data
+	Part          isBlobChunk_Part `protobuf_oneof:"part"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlobChunk) Reset() {
+	*x = BlobChunk{}
+	mi := &file_types_v1_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlobChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlobChunk) ProtoMessage() {}
+
+func (x *BlobChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlobChunk.ProtoReflect.Descriptor instead.
+func (*BlobChunk) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *BlobChunk) GetPart() isBlobChunk_Part {
+	if x != nil {
+		return x.Part
+	}
+	return nil
+}
+
+func (x *BlobChunk) GetInfo() *BlobInfo {
+	if x != nil {
+		if x, ok := x.Part.(*BlobChunk_Info); ok {
+			return x.Info
+		}
+	}
+	return nil
+}
+
+func (x *BlobChunk) GetData() []byte {
+	if x != nil {
+		if x, ok := x.Part.(*BlobChunk_Data); ok {
+			return x.Data
+		}
+	}
+	return nil
+}
+
+type isBlobChunk_Part interface {
+	isBlobChunk_Part()
+}
+
+type BlobChunk_Info struct {
+	Info *BlobInfo `protobuf:"bytes,1,opt,name=info,proto3,oneof"`
+}
+
+type BlobChunk_Data struct {
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3,oneof"`
+}
+
+func (*BlobChunk_Info) isBlobChunk_Part() {}
+
+func (*BlobChunk_Data) isBlobChunk_Part() {}
+
+// Request message for downloading a blob attribute
+type BlobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EntityId      string                 `protobuf:"bytes,1,opt,name=entityId,proto3" json:"entityId,omitempty"`
+	AttributeName string                 `protobuf:"bytes,2,opt,name=attributeName,proto3" json:"attributeName,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlobRequest) Reset() {
+	*x = BlobRequest{}
+	mi := &file_types_v1_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlobRequest) ProtoMessage() {}
+
+func (x *BlobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlobRequest.ProtoReflect.Descriptor instead.
+func (*BlobRequest) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *BlobRequest) GetEntityId() string {
+	if x != nil {
+		return x.EntityId
+	}
+	return ""
+}
+
+func (x *BlobRequest) GetAttributeName() string {
+	if x != nil {
+		return x.AttributeName
+	}
+	return ""
+}
+
+// Request message for exporting a tabular attribute as an Arrow stream or a Parquet file
+type TabularExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EntityId      string                 `protobuf:"bytes,1,opt,name=entityId,proto3" json:"entityId,omitempty"`
+	AttributeName string                 `protobuf:"bytes,2,opt,name=attributeName,proto3" json:"attributeName,omitempty"`
+	Format        string                 `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`                                                                             // arrow (default) or parquet
+	Filters       map[string]string      `protobuf:"bytes,4,rep,name=filters,proto3" json:"filters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Only export rows whose column equals the value
+	Fields        []string               `protobuf:"bytes,5,rep,name=fields,proto3" json:"fields,omitempty"`                                                                             // Only export these columns (all if empty)
+	WrittenFrom   string                 `protobuf:"bytes,6,opt,name=writtenFrom,proto3" json:"writtenFrom,omitempty"`                                                                   // Only export rows written at or after this instant (RFC3339)
+	WrittenUntil  string                 `protobuf:"bytes,7,opt,name=writtenUntil,proto3" json:"writtenUntil,omitempty"`                                                                 // Only export rows written before this instant (RFC3339)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TabularExportRequest) Reset() {
+	*x = TabularExportRequest{}
+	mi := &file_types_v1_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TabularExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TabularExportRequest) ProtoMessage() {}
+
+func (x *TabularExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TabularExportRequest.ProtoReflect.Descriptor instead.
+func (*TabularExportRequest) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *TabularExportRequest) GetEntityId() string {
+	if x != nil {
+		return x.EntityId
+	}
+	return ""
+}
+
+func (x *TabularExportRequest) GetAttributeName() string {
+	if x != nil {
+		return x.AttributeName
+	}
+	return ""
+}
+
+func (x *TabularExportRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *TabularExportRequest) GetFilters() map[string]string {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *TabularExportRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *TabularExportRequest) GetWrittenFrom() string {
+	if x != nil {
+		return x.WrittenFrom
+	}
+	return ""
+}
+
+func (x *TabularExportRequest) GetWrittenUntil() string {
+	if x != nil {
+		return x.WrittenUntil
+	}
+	return ""
+}
+
+// TabularExportInfo describes an exported tabular attribute
+type TabularExportInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=contentType,proto3" json:"contentType,omitempty"`
+	Columns       []*TabularColumn       `protobuf:"bytes,3,rep,name=columns,proto3" json:"columns,omitempty"`
+	RowCount      int64                  `protobuf:"varint,4,opt,name=rowCount,proto3" json:"rowCount,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *EntityId) Reset() {
-	*x = EntityId{}
-	mi := &file_types_v1_proto_msgTypes[6]
+func (x *TabularExportInfo) Reset() {
+	*x = TabularExportInfo{}
+	mi := &file_types_v1_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *EntityId) String() string {
+func (x *TabularExportInfo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EntityId) ProtoMessage() {}
+func (*TabularExportInfo) ProtoMessage() {}
 
-func (x *EntityId) ProtoReflect() protoreflect.Message {
-	mi := &file_types_v1_proto_msgTypes[6]
+func (x *TabularExportInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -457,42 +2050,239 @@ func (x *EntityId) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EntityId.ProtoReflect.Descriptor instead.
-func (*EntityId) Descriptor() ([]byte, []int) {
-	return file_types_v1_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use TabularExportInfo.ProtoReflect.Descriptor instead.
+func (*TabularExportInfo) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *EntityId) GetId() string {
+func (x *TabularExportInfo) GetFormat() string {
 	if x != nil {
-		return x.Id
+		return x.Format
 	}
 	return ""
 }
 
-// Request message for updating an entity
-type UpdateEntityRequest struct {
+func (x *TabularExportInfo) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *TabularExportInfo) GetColumns() []*TabularColumn {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *TabularExportInfo) GetRowCount() int64 {
+	if x != nil {
+		return x.RowCount
+	}
+	return 0
+}
+
+// TabularExportChunk is a piece of a streamed tabular export
+type TabularExportChunk struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Part:
+	//
+	//	*TabularExportChunk_Info
+	//	*TabularExportChunk_Data
+	Part          isTabularExportChunk_Part `protobuf_oneof:"part"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TabularExportChunk) Reset() {
+	*x = TabularExportChunk{}
+	mi := &file_types_v1_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TabularExportChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TabularExportChunk) ProtoMessage() {}
+
+func (x *TabularExportChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TabularExportChunk.ProtoReflect.Descriptor instead.
+func (*TabularExportChunk) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *TabularExportChunk) GetPart() isTabularExportChunk_Part {
+	if x != nil {
+		return x.Part
+	}
+	return nil
+}
+
+func (x *TabularExportChunk) GetInfo() *TabularExportInfo {
+	if x != nil {
+		if x, ok := x.Part.(*TabularExportChunk_Info); ok {
+			return x.Info
+		}
+	}
+	return nil
+}
+
+func (x *TabularExportChunk) GetData() []byte {
+	if x != nil {
+		if x, ok := x.Part.(*TabularExportChunk_Data); ok {
+			return x.Data
+		}
+	}
+	return nil
+}
+
+type isTabularExportChunk_Part interface {
+	isTabularExportChunk_Part()
+}
+
+type TabularExportChunk_Info struct {
+	Info *TabularExportInfo `protobuf:"bytes,1,opt,name=info,proto3,oneof"`
+}
+
+type TabularExportChunk_Data struct {
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3,oneof"`
+}
+
+func (*TabularExportChunk_Info) isTabularExportChunk_Part() {}
+
+func (*TabularExportChunk_Data) isTabularExportChunk_Part() {}
+
+// KindDefinition declares a major kind in the kind registry and what entities of the kind must look like
+type KindDefinition struct {
+	state            protoimpl.MessageState        `protogen:"open.v1"`
+	Major            string                        `protobuf:"bytes,1,opt,name=major,proto3" json:"major,omitempty"`
+	Minors           []string                      `protobuf:"bytes,2,rep,name=minors,proto3" json:"minors,omitempty"`                                                                                   // Allowed minor kinds (any if empty)
+	RequiredMetadata []string                      `protobuf:"bytes,3,rep,name=requiredMetadata,proto3" json:"requiredMetadata,omitempty"`                                                               // Metadata keys every entity of the kind must have
+	Relationships    []*AllowedRelationship        `protobuf:"bytes,4,rep,name=relationships,proto3" json:"relationships,omitempty"`                                                                     // Outgoing relationships entities of the kind may have (any if empty)
+	Attributes       map[string]*ExpectedAttribute `protobuf:"bytes,5,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Expected attributes by name
+	ClosedAttributes bool                          `protobuf:"varint,6,opt,name=closedAttributes,proto3" json:"closedAttributes,omitempty"`                                                              // Refuse attributes that are not listed in attributes
+	Description      string                        `protobuf:"bytes,7,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *KindDefinition) Reset() {
+	*x = KindDefinition{}
+	mi := &file_types_v1_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KindDefinition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KindDefinition) ProtoMessage() {}
+
+func (x *KindDefinition) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KindDefinition.ProtoReflect.Descriptor instead.
+func (*KindDefinition) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *KindDefinition) GetMajor() string {
+	if x != nil {
+		return x.Major
+	}
+	return ""
+}
+
+func (x *KindDefinition) GetMinors() []string {
+	if x != nil {
+		return x.Minors
+	}
+	return nil
+}
+
+func (x *KindDefinition) GetRequiredMetadata() []string {
+	if x != nil {
+		return x.RequiredMetadata
+	}
+	return nil
+}
+
+func (x *KindDefinition) GetRelationships() []*AllowedRelationship {
+	if x != nil {
+		return x.Relationships
+	}
+	return nil
+}
+
+func (x *KindDefinition) GetAttributes() map[string]*ExpectedAttribute {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *KindDefinition) GetClosedAttributes() bool {
+	if x != nil {
+		return x.ClosedAttributes
+	}
+	return false
+}
+
+func (x *KindDefinition) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+// AllowedRelationship is a relationship name entities of a kind may point to other entities with
+type AllowedRelationship struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Entity        *Entity                `protobuf:"bytes,2,opt,name=entity,proto3" json:"entity,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	TargetKinds   []string               `protobuf:"bytes,2,rep,name=targetKinds,proto3" json:"targetKinds,omitempty"` // Major kinds of the related entity (any if empty)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateEntityRequest) Reset() {
-	*x = UpdateEntityRequest{}
-	mi := &file_types_v1_proto_msgTypes[7]
+func (x *AllowedRelationship) Reset() {
+	*x = AllowedRelationship{}
+	mi := &file_types_v1_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateEntityRequest) String() string {
+func (x *AllowedRelationship) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateEntityRequest) ProtoMessage() {}
+func (*AllowedRelationship) ProtoMessage() {}
 
-func (x *UpdateEntityRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_types_v1_proto_msgTypes[7]
+func (x *AllowedRelationship) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -503,47 +2293,50 @@ func (x *UpdateEntityRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateEntityRequest.ProtoReflect.Descriptor instead.
-func (*UpdateEntityRequest) Descriptor() ([]byte, []int) {
-	return file_types_v1_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use AllowedRelationship.ProtoReflect.Descriptor instead.
+func (*AllowedRelationship) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *UpdateEntityRequest) GetId() string {
+func (x *AllowedRelationship) GetName() string {
 	if x != nil {
-		return x.Id
+		return x.Name
 	}
 	return ""
 }
 
-func (x *UpdateEntityRequest) GetEntity() *Entity {
+func (x *AllowedRelationship) GetTargetKinds() []string {
 	if x != nil {
-		return x.Entity
+		return x.TargetKinds
 	}
 	return nil
 }
 
-// Empty message response
-type Empty struct {
+// ExpectedAttribute describes an attribute entities of a kind are expected to have
+type ExpectedAttribute struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	StorageType   string                 `protobuf:"bytes,1,opt,name=storageType,proto3" json:"storageType,omitempty"`                                                                   // tabular, graph, map, list, scalar or blob (any if empty)
+	Columns       map[string]string      `protobuf:"bytes,2,rep,name=columns,proto3" json:"columns,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Column name to type (int, float, string, bool, date, time, datetime) for tabular attributes
+	Required      bool                   `protobuf:"varint,3,opt,name=required,proto3" json:"required,omitempty"`                                                                        // Every new entity of the kind must have the attribute
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Empty) Reset() {
-	*x = Empty{}
-	mi := &file_types_v1_proto_msgTypes[8]
+func (x *ExpectedAttribute) Reset() {
+	*x = ExpectedAttribute{}
+	mi := &file_types_v1_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Empty) String() string {
+func (x *ExpectedAttribute) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Empty) ProtoMessage() {}
+func (*ExpectedAttribute) ProtoMessage() {}
 
-func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_types_v1_proto_msgTypes[8]
+func (x *ExpectedAttribute) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -554,34 +2347,55 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
-func (*Empty) Descriptor() ([]byte, []int) {
-	return file_types_v1_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use ExpectedAttribute.ProtoReflect.Descriptor instead.
+func (*ExpectedAttribute) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{32}
 }
 
-// EntityList represents a list of entities
-type EntityList struct {
+func (x *ExpectedAttribute) GetStorageType() string {
+	if x != nil {
+		return x.StorageType
+	}
+	return ""
+}
+
+func (x *ExpectedAttribute) GetColumns() map[string]string {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *ExpectedAttribute) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+// Request message naming a major kind of the registry
+type KindRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Entities      []*Entity              `protobuf:"bytes,1,rep,name=entities,proto3" json:"entities,omitempty"`
+	Major         string                 `protobuf:"bytes,1,opt,name=major,proto3" json:"major,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *EntityList) Reset() {
-	*x = EntityList{}
-	mi := &file_types_v1_proto_msgTypes[9]
+func (x *KindRequest) Reset() {
+	*x = KindRequest{}
+	mi := &file_types_v1_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *EntityList) String() string {
+func (x *KindRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EntityList) ProtoMessage() {}
+func (*KindRequest) ProtoMessage() {}
 
-func (x *EntityList) ProtoReflect() protoreflect.Message {
-	mi := &file_types_v1_proto_msgTypes[9]
+func (x *KindRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -592,23 +2406,76 @@ func (x *EntityList) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EntityList.ProtoReflect.Descriptor instead.
-func (*EntityList) Descriptor() ([]byte, []int) {
-	return file_types_v1_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use KindRequest.ProtoReflect.Descriptor instead.
+func (*KindRequest) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *EntityList) GetEntities() []*Entity {
+func (x *KindRequest) GetMajor() string {
 	if x != nil {
-		return x.Entities
+		return x.Major
+	}
+	return ""
+}
+
+// KindList is the kind registry of a tenant
+type KindList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kinds         []*KindDefinition      `protobuf:"bytes,1,rep,name=kinds,proto3" json:"kinds,omitempty"`
+	Mode          string                 `protobuf:"bytes,2,opt,name=mode,proto3" json:"mode,omitempty"` // enforce, warn or off
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KindList) Reset() {
+	*x = KindList{}
+	mi := &file_types_v1_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KindList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KindList) ProtoMessage() {}
+
+func (x *KindList) ProtoReflect() protoreflect.Message {
+	mi := &file_types_v1_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KindList.ProtoReflect.Descriptor instead.
+func (*KindList) Descriptor() ([]byte, []int) {
+	return file_types_v1_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *KindList) GetKinds() []*KindDefinition {
+	if x != nil {
+		return x.Kinds
 	}
 	return nil
 }
 
+func (x *KindList) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
 var File_types_v1_proto protoreflect.FileDescriptor
 
 const file_types_v1_proto_rawDesc = "" +
 	"\n" +
-	"\x0etypes_v1.proto\x12\x04core\x1a\x19google/protobuf/any.proto\"2\n" +
+	"\x0etypes_v1.proto\x12\x04core\x1a\x19google/protobuf/any.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"2\n" +
 	"\x04Kind\x12\x14\n" +
 	"\x05major\x18\x01 \x01(\tR\x05major\x12\x14\n" +
 	"\x05minor\x18\x02 \x01(\tR\x05minor\"t\n" +
@@ -647,11 +2514,45 @@ const file_types_v1_proto_rawDesc = "" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12(\n" +
 	"\x05value\x18\x02 \x01(\v2\x12.core.RelationshipR\x05value:\x028\x01\"B\n" +
 	"\x12TimeBasedValueList\x12,\n" +
-	"\x06values\x18\x01 \x03(\v2\x14.core.TimeBasedValueR\x06values\"m\n" +
+	"\x06values\x18\x01 \x03(\v2\x14.core.TimeBasedValueR\x06values\"\x93\x01\n" +
 	"\x11ReadEntityRequest\x12$\n" +
 	"\x06entity\x18\x01 \x01(\v2\f.core.EntityR\x06entity\x12\x16\n" +
 	"\x06output\x18\x02 \x03(\tR\x06output\x12\x1a\n" +
-	"\bactiveAt\x18\x03 \x01(\tR\bactiveAt\"\x1a\n" +
+	"\bactiveAt\x18\x03 \x01(\tR\bactiveAt\x12$\n" +
+	"\rtabularFormat\x18\x04 \x01(\tR\rtabularFormat\"b\n" +
+	"\vTabularData\x12-\n" +
+	"\acolumns\x18\x01 \x03(\v2\x13.core.TabularColumnR\acolumns\x12$\n" +
+	"\x04rows\x18\x02 \x03(\v2\x10.core.TabularRowR\x04rows\"k\n" +
+	"\rTabularColumn\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1c\n" +
+	"\tprecision\x18\x03 \x01(\x05R\tprecision\x12\x14\n" +
+	"\x05scale\x18\x04 \x01(\x05R\x05scale\"8\n" +
+	"\n" +
+	"TabularRow\x12*\n" +
+	"\x06values\x18\x01 \x03(\v2\x12.core.TabularValueR\x06values\"\xfd\x01\n" +
+	"\fTabularValue\x12\x1c\n" +
+	"\bintValue\x18\x01 \x01(\x03H\x00R\bintValue\x12 \n" +
+	"\n" +
+	"floatValue\x18\x02 \x01(\x01H\x00R\n" +
+	"floatValue\x12\"\n" +
+	"\vstringValue\x18\x03 \x01(\tH\x00R\vstringValue\x12\x1e\n" +
+	"\tboolValue\x18\x04 \x01(\bH\x00R\tboolValue\x12:\n" +
+	"\ttimeValue\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampH\x00R\ttimeValue\x12$\n" +
+	"\fdecimalValue\x18\x06 \x01(\tH\x00R\fdecimalValueB\a\n" +
+	"\x05value\"\xfe\x01\n" +
+	"\x13TabularIngestReport\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12-\n" +
+	"\acolumns\x18\x02 \x03(\v2\x13.core.TabularColumnR\acolumns\x12\x1a\n" +
+	"\browCount\x18\x03 \x01(\x03R\browCount\x12 \n" +
+	"\vsampledRows\x18\x04 \x01(\x03R\vsampledRows\x12(\n" +
+	"\x0ffailedCellCount\x18\x05 \x01(\x03R\x0ffailedCellCount\x128\n" +
+	"\vfailedCells\x18\x06 \x03(\v2\x16.core.TabularCellErrorR\vfailedCells\"f\n" +
+	"\x10TabularCellError\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x03R\x03row\x12\x16\n" +
+	"\x06column\x18\x02 \x01(\tR\x06column\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\"\x1a\n" +
 	"\bEntityId\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"K\n" +
 	"\x13UpdateEntityRequest\x12\x0e\n" +
@@ -660,14 +2561,148 @@ const file_types_v1_proto_rawDesc = "" +
 	"\x05Empty\"6\n" +
 	"\n" +
 	"EntityList\x12(\n" +
-	"\bentities\x18\x01 \x03(\v2\f.core.EntityR\bentities2\x8f\x02\n" +
+	"\bentities\x18\x01 \x03(\v2\f.core.EntityR\bentities\"\x8d\x02\n" +
+	"\vPathRequest\x12&\n" +
+	"\x0esourceEntityId\x18\x01 \x01(\tR\x0esourceEntityId\x12&\n" +
+	"\x0etargetEntityId\x18\x02 \x01(\tR\x0etargetEntityId\x12,\n" +
+	"\x11relationshipNames\x18\x03 \x03(\tR\x11relationshipNames\x12\x1a\n" +
+	"\bactiveAt\x18\x04 \x01(\tR\bactiveAt\x12\x1a\n" +
+	"\bmaxDepth\x18\x05 \x01(\x05R\bmaxDepth\x12*\n" +
+	"\x10allShortestPaths\x18\x06 \x01(\bR\x10allShortestPaths\x12\x1c\n" +
+	"\tdirection\x18\a \x01(\tR\tdirection\"p\n" +
+	"\n" +
+	"EntityPath\x12(\n" +
+	"\bentities\x18\x01 \x03(\v2\f.core.EntityR\bentities\x128\n" +
+	"\rrelationships\x18\x02 \x03(\v2\x12.core.RelationshipR\rrelationships\"2\n" +
+	"\bPathList\x12&\n" +
+	"\x05paths\x18\x01 \x03(\v2\x10.core.EntityPathR\x05paths\"\xf9\x01\n" +
+	"\rExportRequest\x12\"\n" +
+	"\frootEntityId\x18\x01 \x01(\tR\frootEntityId\x12,\n" +
+	"\x11relationshipNames\x18\x02 \x03(\tR\x11relationshipNames\x12\x1c\n" +
+	"\tdirection\x18\x03 \x01(\tR\tdirection\x12\x1a\n" +
+	"\bmaxDepth\x18\x04 \x01(\x05R\bmaxDepth\x12\x1a\n" +
+	"\bactiveAt\x18\x05 \x01(\tR\bactiveAt\x12\x16\n" +
+	"\x06format\x18\x06 \x01(\tR\x06format\x12(\n" +
+	"\x0fincludeMetadata\x18\a \x01(\bR\x0fincludeMetadata\"\xb4\x01\n" +
+	"\x0eExportResponse\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12 \n" +
+	"\vcontentType\x18\x02 \x01(\tR\vcontentType\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\fR\acontent\x12 \n" +
+	"\ventityCount\x18\x04 \x01(\x05R\ventityCount\x12,\n" +
+	"\x11relationshipCount\x18\x05 \x01(\x05R\x11relationshipCount\"l\n" +
+	"\x12ConsistencyRequest\x12\x1e\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\tR\n" +
+	"categories\x12\x16\n" +
+	"\x06repair\x18\x02 \x01(\bR\x06repair\x12\x1e\n" +
+	"\n" +
+	"quarantine\x18\x03 \x01(\bR\n" +
+	"quarantine\"\xe4\x01\n" +
+	"\x10ConsistencyIssue\x12\x1a\n" +
+	"\bcategory\x18\x01 \x01(\tR\bcategory\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x1a\n" +
+	"\bentityId\x18\x03 \x01(\tR\bentityId\x12$\n" +
+	"\rattributeName\x18\x04 \x01(\tR\rattributeName\x12\x1c\n" +
+	"\ttableName\x18\x05 \x01(\tR\ttableName\x12\x16\n" +
+	"\x06detail\x18\x06 \x01(\tR\x06detail\x12\x16\n" +
+	"\x06action\x18\a \x01(\tR\x06action\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\"\xf5\x02\n" +
+	"\x11ConsistencyReport\x12\x1c\n" +
+	"\tstartedAt\x18\x01 \x01(\tR\tstartedAt\x12\x1e\n" +
+	"\n" +
+	"finishedAt\x18\x02 \x01(\tR\n" +
+	"finishedAt\x12>\n" +
+	"\ascanned\x18\x03 \x03(\v2$.core.ConsistencyReport.ScannedEntryR\ascanned\x12;\n" +
+	"\x06counts\x18\x04 \x03(\v2#.core.ConsistencyReport.CountsEntryR\x06counts\x12.\n" +
+	"\x06issues\x18\x05 \x03(\v2\x16.core.ConsistencyIssueR\x06issues\x1a:\n" +
+	"\fScannedEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\x1a9\n" +
+	"\vCountsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\xfa\x01\n" +
+	"\bBlobInfo\x12\x1a\n" +
+	"\bentityId\x18\x01 \x01(\tR\bentityId\x12$\n" +
+	"\rattributeName\x18\x02 \x01(\tR\rattributeName\x12\x1a\n" +
+	"\bmimeType\x18\x03 \x01(\tR\bmimeType\x12\x1a\n" +
+	"\bfileName\x18\x04 \x01(\tR\bfileName\x12\x1c\n" +
+	"\tstartTime\x18\x05 \x01(\tR\tstartTime\x12 \n" +
+	"\vcontentHash\x18\x06 \x01(\tR\vcontentHash\x12\x12\n" +
+	"\x04size\x18\a \x01(\x03R\x04size\x12 \n" +
+	"\vstoragePath\x18\b \x01(\tR\vstoragePath\"O\n" +
+	"\tBlobChunk\x12$\n" +
+	"\x04info\x18\x01 \x01(\v2\x0e.core.BlobInfoH\x00R\x04info\x12\x14\n" +
+	"\x04data\x18\x02 \x01(\fH\x00R\x04dataB\x06\n" +
+	"\x04part\"O\n" +
+	"\vBlobRequest\x12\x1a\n" +
+	"\bentityId\x18\x01 \x01(\tR\bentityId\x12$\n" +
+	"\rattributeName\x18\x02 \x01(\tR\rattributeName\"\xcd\x02\n" +
+	"\x14TabularExportRequest\x12\x1a\n" +
+	"\bentityId\x18\x01 \x01(\tR\bentityId\x12$\n" +
+	"\rattributeName\x18\x02 \x01(\tR\rattributeName\x12\x16\n" +
+	"\x06format\x18\x03 \x01(\tR\x06format\x12A\n" +
+	"\afilters\x18\x04 \x03(\v2'.core.TabularExportRequest.FiltersEntryR\afilters\x12\x16\n" +
+	"\x06fields\x18\x05 \x03(\tR\x06fields\x12 \n" +
+	"\vwrittenFrom\x18\x06 \x01(\tR\vwrittenFrom\x12\"\n" +
+	"\fwrittenUntil\x18\a \x01(\tR\fwrittenUntil\x1a:\n" +
+	"\fFiltersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x98\x01\n" +
+	"\x11TabularExportInfo\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12 \n" +
+	"\vcontentType\x18\x02 \x01(\tR\vcontentType\x12-\n" +
+	"\acolumns\x18\x03 \x03(\v2\x13.core.TabularColumnR\acolumns\x12\x1a\n" +
+	"\browCount\x18\x04 \x01(\x03R\browCount\"a\n" +
+	"\x12TabularExportChunk\x12-\n" +
+	"\x04info\x18\x01 \x01(\v2\x17.core.TabularExportInfoH\x00R\x04info\x12\x14\n" +
+	"\x04data\x18\x02 \x01(\fH\x00R\x04dataB\x06\n" +
+	"\x04part\"\x97\x03\n" +
+	"\x0eKindDefinition\x12\x14\n" +
+	"\x05major\x18\x01 \x01(\tR\x05major\x12\x16\n" +
+	"\x06minors\x18\x02 \x03(\tR\x06minors\x12*\n" +
+	"\x10requiredMetadata\x18\x03 \x03(\tR\x10requiredMetadata\x12?\n" +
+	"\rrelationships\x18\x04 \x03(\v2\x19.core.AllowedRelationshipR\rrelationships\x12D\n" +
+	"\n" +
+	"attributes\x18\x05 \x03(\v2$.core.KindDefinition.AttributesEntryR\n" +
+	"attributes\x12*\n" +
+	"\x10closedAttributes\x18\x06 \x01(\bR\x10closedAttributes\x12 \n" +
+	"\vdescription\x18\a \x01(\tR\vdescription\x1aV\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12-\n" +
+	"\x05value\x18\x02 \x01(\v2\x17.core.ExpectedAttributeR\x05value:\x028\x01\"K\n" +
+	"\x13AllowedRelationship\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vtargetKinds\x18\x02 \x03(\tR\vtargetKinds\"\xcd\x01\n" +
+	"\x11ExpectedAttribute\x12 \n" +
+	"\vstorageType\x18\x01 \x01(\tR\vstorageType\x12>\n" +
+	"\acolumns\x18\x02 \x03(\v2$.core.ExpectedAttribute.ColumnsEntryR\acolumns\x12\x1a\n" +
+	"\brequired\x18\x03 \x01(\bR\brequired\x1a:\n" +
+	"\fColumnsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"#\n" +
+	"\vKindRequest\x12\x14\n" +
+	"\x05major\x18\x01 \x01(\tR\x05major\"J\n" +
+	"\bKindList\x12*\n" +
+	"\x05kinds\x18\x01 \x03(\v2\x14.core.KindDefinitionR\x05kinds\x12\x12\n" +
+	"\x04mode\x18\x02 \x01(\tR\x04mode2\x82\x06\n" +
 	"\vCOREService\x12*\n" +
 	"\fCreateEntity\x12\f.core.Entity\x1a\f.core.Entity\x123\n" +
 	"\n" +
 	"ReadEntity\x12\x17.core.ReadEntityRequest\x1a\f.core.Entity\x129\n" +
 	"\fReadEntities\x12\x17.core.ReadEntityRequest\x1a\x10.core.EntityList\x127\n" +
 	"\fUpdateEntity\x12\x19.core.UpdateEntityRequest\x1a\f.core.Entity\x12+\n" +
-	"\fDeleteEntity\x12\x0e.core.EntityId\x1a\v.core.EmptyB\x1cZ\x1alk/datafoundation/core-apib\x06proto3"
+	"\fDeleteEntity\x12\x0e.core.EntityId\x1a\v.core.Empty\x12.\n" +
+	"\tReadPaths\x12\x11.core.PathRequest\x1a\x0e.core.PathList\x12;\n" +
+	"\x0eExportSubgraph\x12\x13.core.ExportRequest\x1a\x14.core.ExportResponse\x12E\n" +
+	"\x10CheckConsistency\x12\x18.core.ConsistencyRequest\x1a\x17.core.ConsistencyReport\x12/\n" +
+	"\n" +
+	"UploadBlob\x12\x0f.core.BlobChunk\x1a\x0e.core.BlobInfo(\x01\x124\n" +
+	"\fDownloadBlob\x12\x11.core.BlobRequest\x1a\x0f.core.BlobChunk0\x01\x125\n" +
+	"\aPutKind\x12\x14.core.KindDefinition\x1a\x14.core.KindDefinition\x12(\n" +
+	"\tListKinds\x12\v.core.Empty\x1a\x0e.core.KindList\x12,\n" +
+	"\n" +
+	"DeleteKind\x12\x11.core.KindRequest\x1a\v.core.Empty\x12G\n" +
+	"\rExportTabular\x12\x1a.core.TabularExportRequest\x1a\x18.core.TabularExportChunk0\x01B\x1cZ\x1alk/datafoundation/core-apib\x06proto3"
 
 var (
 	file_types_v1_proto_rawDescOnce sync.Once
@@ -681,52 +2716,122 @@ func file_types_v1_proto_rawDescGZIP() []byte {
 	return file_types_v1_proto_rawDescData
 }
 
-var file_types_v1_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_types_v1_proto_msgTypes = make([]protoimpl.MessageInfo, 43)
 var file_types_v1_proto_goTypes = []any{
-	(*Kind)(nil),                // 0: core.Kind
-	(*TimeBasedValue)(nil),      // 1: core.TimeBasedValue
-	(*Relationship)(nil),        // 2: core.Relationship
-	(*Entity)(nil),              // 3: core.Entity
-	(*TimeBasedValueList)(nil),  // 4: core.TimeBasedValueList
-	(*ReadEntityRequest)(nil),   // 5: core.ReadEntityRequest
-	(*EntityId)(nil),            // 6: core.EntityId
-	(*UpdateEntityRequest)(nil), // 7: core.UpdateEntityRequest
-	(*Empty)(nil),               // 8: core.Empty
-	(*EntityList)(nil),          // 9: core.EntityList
-	nil,                         // 10: core.Entity.MetadataEntry
-	nil,                         // 11: core.Entity.AttributesEntry
-	nil,                         // 12: core.Entity.RelationshipsEntry
-	(*anypb.Any)(nil),           // 13: google.protobuf.Any
+	(*Kind)(nil),                  // 0: core.Kind
+	(*TimeBasedValue)(nil),        // 1: core.TimeBasedValue
+	(*Relationship)(nil),          // 2: core.Relationship
+	(*Entity)(nil),                // 3: core.Entity
+	(*TimeBasedValueList)(nil),    // 4: core.TimeBasedValueList
+	(*ReadEntityRequest)(nil),     // 5: core.ReadEntityRequest
+	(*TabularData)(nil),           // 6: core.TabularData
+	(*TabularColumn)(nil),         // 7: core.TabularColumn
+	(*TabularRow)(nil),            // 8: core.TabularRow
+	(*TabularValue)(nil),          // 9: core.TabularValue
+	(*TabularIngestReport)(nil),   // 10: core.TabularIngestReport
+	(*TabularCellError)(nil),      // 11: core.TabularCellError
+	(*EntityId)(nil),              // 12: core.EntityId
+	(*UpdateEntityRequest)(nil),   // 13: core.UpdateEntityRequest
+	(*Empty)(nil),                 // 14: core.Empty
+	(*EntityList)(nil),            // 15: core.EntityList
+	(*PathRequest)(nil),           // 16: core.PathRequest
+	(*EntityPath)(nil),            // 17: core.EntityPath
+	(*PathList)(nil),              // 18: core.PathList
+	(*ExportRequest)(nil),         // 19: core.ExportRequest
+	(*ExportResponse)(nil),        // 20: core.ExportResponse
+	(*ConsistencyRequest)(nil),    // 21: core.ConsistencyRequest
+	(*ConsistencyIssue)(nil),      // 22: core.ConsistencyIssue
+	(*ConsistencyReport)(nil),     // 23: core.ConsistencyReport
+	(*BlobInfo)(nil),              // 24: core.BlobInfo
+	(*BlobChunk)(nil),             // 25: core.BlobChunk
+	(*BlobRequest)(nil),           // 26: core.BlobRequest
+	(*TabularExportRequest)(nil),  // 27: core.TabularExportRequest
+	(*TabularExportInfo)(nil),     // 28: core.TabularExportInfo
+	(*TabularExportChunk)(nil),    // 29: core.TabularExportChunk
+	(*KindDefinition)(nil),        // 30: core.KindDefinition
+	(*AllowedRelationship)(nil),   // 31: core.AllowedRelationship
+	(*ExpectedAttribute)(nil),     // 32: core.ExpectedAttribute
+	(*KindRequest)(nil),           // 33: core.KindRequest
+	(*KindList)(nil),              // 34: core.KindList
+	nil,                           // 35: core.Entity.MetadataEntry
+	nil,                           // 36: core.Entity.AttributesEntry
+	nil,                           // 37: core.Entity.RelationshipsEntry
+	nil,                           // 38: core.ConsistencyReport.ScannedEntry
+	nil,                           // 39: core.ConsistencyReport.CountsEntry
+	nil,                           // 40: core.TabularExportRequest.FiltersEntry
+	nil,                           // 41: core.KindDefinition.AttributesEntry
+	nil,                           // 42: core.ExpectedAttribute.ColumnsEntry
+	(*anypb.Any)(nil),             // 43: google.protobuf.Any
+	(*timestamppb.Timestamp)(nil), // 44: google.protobuf.Timestamp
 }
 var file_types_v1_proto_depIdxs = []int32{
-	13, // 0: core.TimeBasedValue.value:type_name -> google.protobuf.Any
+	43, // 0: core.TimeBasedValue.value:type_name -> google.protobuf.Any
 	0,  // 1: core.Entity.kind:type_name -> core.Kind
 	1,  // 2: core.Entity.name:type_name -> core.TimeBasedValue
-	10, // 3: core.Entity.metadata:type_name -> core.Entity.MetadataEntry
-	11, // 4: core.Entity.attributes:type_name -> core.Entity.AttributesEntry
-	12, // 5: core.Entity.relationships:type_name -> core.Entity.RelationshipsEntry
+	35, // 3: core.Entity.metadata:type_name -> core.Entity.MetadataEntry
+	36, // 4: core.Entity.attributes:type_name -> core.Entity.AttributesEntry
+	37, // 5: core.Entity.relationships:type_name -> core.Entity.RelationshipsEntry
 	1,  // 6: core.TimeBasedValueList.values:type_name -> core.TimeBasedValue
 	3,  // 7: core.ReadEntityRequest.entity:type_name -> core.Entity
-	3,  // 8: core.UpdateEntityRequest.entity:type_name -> core.Entity
-	3,  // 9: core.EntityList.entities:type_name -> core.Entity
-	13, // 10: core.Entity.MetadataEntry.value:type_name -> google.protobuf.Any
-	4,  // 11: core.Entity.AttributesEntry.value:type_name -> core.TimeBasedValueList
-	2,  // 12: core.Entity.RelationshipsEntry.value:type_name -> core.Relationship
-	3,  // 13: core.COREService.CreateEntity:input_type -> core.Entity
-	5,  // 14: core.COREService.ReadEntity:input_type -> core.ReadEntityRequest
-	5,  // 15: core.COREService.ReadEntities:input_type -> core.ReadEntityRequest
-	7,  // 16: core.COREService.UpdateEntity:input_type -> core.UpdateEntityRequest
-	6,  // 17: core.COREService.DeleteEntity:input_type -> core.EntityId
-	3,  // 18: core.COREService.CreateEntity:output_type -> core.Entity
-	3,  // 19: core.COREService.ReadEntity:output_type -> core.Entity
-	9,  // 20: core.COREService.ReadEntities:output_type -> core.EntityList
-	3,  // 21: core.COREService.UpdateEntity:output_type -> core.Entity
-	8,  // 22: core.COREService.DeleteEntity:output_type -> core.Empty
-	18, // [18:23] is the sub-list for method output_type
-	13, // [13:18] is the sub-list for method input_type
-	13, // [13:13] is the sub-list for extension type_name
-	13, // [13:13] is the sub-list for extension extendee
-	0,  // [0:13] is the sub-list for field type_name
+	7,  // 8: core.TabularData.columns:type_name -> core.TabularColumn
+	8,  // 9: core.TabularData.rows:type_name -> core.TabularRow
+	9,  // 10: core.TabularRow.values:type_name -> core.TabularValue
+	44, // 11: core.TabularValue.timeValue:type_name -> google.protobuf.Timestamp
+	7,  // 12: core.TabularIngestReport.columns:type_name -> core.TabularColumn
+	11, // 13: core.TabularIngestReport.failedCells:type_name -> core.TabularCellError
+	3,  // 14: core.UpdateEntityRequest.entity:type_name -> core.Entity
+	3,  // 15: core.EntityList.entities:type_name -> core.Entity
+	3,  // 16: core.EntityPath.entities:type_name -> core.Entity
+	2,  // 17: core.EntityPath.relationships:type_name -> core.Relationship
+	17, // 18: core.PathList.paths:type_name -> core.EntityPath
+	38, // 19: core.ConsistencyReport.scanned:type_name -> core.ConsistencyReport.ScannedEntry
+	39, // 20: core.ConsistencyReport.counts:type_name -> core.ConsistencyReport.CountsEntry
+	22, // 21: core.ConsistencyReport.issues:type_name -> core.ConsistencyIssue
+	24, // 22: core.BlobChunk.info:type_name -> core.BlobInfo
+	40, // 23: core.TabularExportRequest.filters:type_name -> core.TabularExportRequest.FiltersEntry
+	7,  // 24: core.TabularExportInfo.columns:type_name -> core.TabularColumn
+	28, // 25: core.TabularExportChunk.info:type_name -> core.TabularExportInfo
+	31, // 26: core.KindDefinition.relationships:type_name -> core.AllowedRelationship
+	41, // 27: core.KindDefinition.attributes:type_name -> core.KindDefinition.AttributesEntry
+	42, // 28: core.ExpectedAttribute.columns:type_name -> core.ExpectedAttribute.ColumnsEntry
+	30, // 29: core.KindList.kinds:type_name -> core.KindDefinition
+	43, // 30: core.Entity.MetadataEntry.value:type_name -> google.protobuf.Any
+	4,  // 31: core.Entity.AttributesEntry.value:type_name -> core.TimeBasedValueList
+	2,  // 32: core.Entity.RelationshipsEntry.value:type_name -> core.Relationship
+	32, // 33: core.KindDefinition.AttributesEntry.value:type_name -> core.ExpectedAttribute
+	3,  // 34: core.COREService.CreateEntity:input_type -> core.Entity
+	5,  // 35: core.COREService.ReadEntity:input_type -> core.ReadEntityRequest
+	5,  // 36: core.COREService.ReadEntities:input_type -> core.ReadEntityRequest
+	13, // 37: core.COREService.UpdateEntity:input_type -> core.UpdateEntityRequest
+	12, // 38: core.COREService.DeleteEntity:input_type -> core.EntityId
+	16, // 39: core.COREService.ReadPaths:input_type -> core.PathRequest
+	19, // 40: core.COREService.ExportSubgraph:input_type -> core.ExportRequest
+	21, // 41: core.COREService.CheckConsistency:input_type -> core.ConsistencyRequest
+	25, // 42: core.COREService.UploadBlob:input_type -> core.BlobChunk
+	26, // 43: core.COREService.DownloadBlob:input_type -> core.BlobRequest
+	30, // 44: core.COREService.PutKind:input_type -> core.KindDefinition
+	14, // 45: core.COREService.ListKinds:input_type -> core.Empty
+	33, // 46: core.COREService.DeleteKind:input_type -> core.KindRequest
+	27, // 47: core.COREService.ExportTabular:input_type -> core.TabularExportRequest
+	3,  // 48: core.COREService.CreateEntity:output_type -> core.Entity
+	3,  // 49: core.COREService.ReadEntity:output_type -> core.Entity
+	15, // 50: core.COREService.ReadEntities:output_type -> core.EntityList
+	3,  // 51: core.COREService.UpdateEntity:output_type -> core.Entity
+	14, // 52: core.COREService.DeleteEntity:output_type -> core.Empty
+	18, // 53: core.COREService.ReadPaths:output_type -> core.PathList
+	20, // 54: core.COREService.ExportSubgraph:output_type -> core.ExportResponse
+	23, // 55: core.COREService.CheckConsistency:output_type -> core.ConsistencyReport
+	24, // 56: core.COREService.UploadBlob:output_type -> core.BlobInfo
+	25, // 57: core.COREService.DownloadBlob:output_type -> core.BlobChunk
+	30, // 58: core.COREService.PutKind:output_type -> core.KindDefinition
+	34, // 59: core.COREService.ListKinds:output_type -> core.KindList
+	14, // 60: core.COREService.DeleteKind:output_type -> core.Empty
+	29, // 61: core.COREService.ExportTabular:output_type -> core.TabularExportChunk
+	48, // [48:62] is the sub-list for method output_type
+	34, // [34:48] is the sub-list for method input_type
+	34, // [34:34] is the sub-list for extension type_name
+	34, // [34:34] is the sub-list for extension extendee
+	0,  // [0:34] is the sub-list for field type_name
 }
 
 func init() { file_types_v1_proto_init() }
@@ -734,13 +2839,29 @@ func file_types_v1_proto_init() {
 	if File_types_v1_proto != nil {
 		return
 	}
+	file_types_v1_proto_msgTypes[9].OneofWrappers = []any{
+		(*TabularValue_IntValue)(nil),
+		(*TabularValue_FloatValue)(nil),
+		(*TabularValue_StringValue)(nil),
+		(*TabularValue_BoolValue)(nil),
+		(*TabularValue_TimeValue)(nil),
+		(*TabularValue_DecimalValue)(nil),
+	}
+	file_types_v1_proto_msgTypes[25].OneofWrappers = []any{
+		(*BlobChunk_Info)(nil),
+		(*BlobChunk_Data)(nil),
+	}
+	file_types_v1_proto_msgTypes[29].OneofWrappers = []any{
+		(*TabularExportChunk_Info)(nil),
+		(*TabularExportChunk_Data)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_types_v1_proto_rawDesc), len(file_types_v1_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   13,
+			NumMessages:   43,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
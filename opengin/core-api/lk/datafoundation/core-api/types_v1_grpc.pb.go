@@ -19,11 +19,20 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	COREService_CreateEntity_FullMethodName = "/core.COREService/CreateEntity"
-	COREService_ReadEntity_FullMethodName   = "/core.COREService/ReadEntity"
-	COREService_ReadEntities_FullMethodName = "/core.COREService/ReadEntities"
-	COREService_UpdateEntity_FullMethodName = "/core.COREService/UpdateEntity"
-	COREService_DeleteEntity_FullMethodName = "/core.COREService/DeleteEntity"
+	COREService_CreateEntity_FullMethodName     = "/core.COREService/CreateEntity"
+	COREService_ReadEntity_FullMethodName       = "/core.COREService/ReadEntity"
+	COREService_ReadEntities_FullMethodName     = "/core.COREService/ReadEntities"
+	COREService_UpdateEntity_FullMethodName     = "/core.COREService/UpdateEntity"
+	COREService_DeleteEntity_FullMethodName     = "/core.COREService/DeleteEntity"
+	COREService_ReadPaths_FullMethodName        = "/core.COREService/ReadPaths"
+	COREService_ExportSubgraph_FullMethodName   = "/core.COREService/ExportSubgraph"
+	COREService_CheckConsistency_FullMethodName = "/core.COREService/CheckConsistency"
+	COREService_UploadBlob_FullMethodName       = "/core.COREService/UploadBlob"
+	COREService_DownloadBlob_FullMethodName     = "/core.COREService/DownloadBlob"
+	COREService_PutKind_FullMethodName          = "/core.COREService/PutKind"
+	COREService_ListKinds_FullMethodName        = "/core.COREService/ListKinds"
+	COREService_DeleteKind_FullMethodName       = "/core.COREService/DeleteKind"
+	COREService_ExportTabular_FullMethodName    = "/core.COREService/ExportTabular"
 )
 
 // COREServiceClient is the client API for COREService service.
@@ -37,6 +46,15 @@ type COREServiceClient interface {
 	ReadEntities(ctx context.Context, in *ReadEntityRequest, opts ...grpc.CallOption) (*EntityList, error)
 	UpdateEntity(ctx context.Context, in *UpdateEntityRequest, opts ...grpc.CallOption) (*Entity, error)
 	DeleteEntity(ctx context.Context, in *EntityId, opts ...grpc.CallOption) (*Empty, error)
+	ReadPaths(ctx context.Context, in *PathRequest, opts ...grpc.CallOption) (*PathList, error)
+	ExportSubgraph(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (*ExportResponse, error)
+	CheckConsistency(ctx context.Context, in *ConsistencyRequest, opts ...grpc.CallOption) (*ConsistencyReport, error)
+	UploadBlob(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[BlobChunk, BlobInfo], error)
+	DownloadBlob(ctx context.Context, in *BlobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BlobChunk], error)
+	PutKind(ctx context.Context, in *KindDefinition, opts ...grpc.CallOption) (*KindDefinition, error)
+	ListKinds(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*KindList, error)
+	DeleteKind(ctx context.Context, in *KindRequest, opts ...grpc.CallOption) (*Empty, error)
+	ExportTabular(ctx context.Context, in *TabularExportRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TabularExportChunk], error)
 }
 
 type cOREServiceClient struct {
@@ -97,6 +115,117 @@ func (c *cOREServiceClient) DeleteEntity(ctx context.Context, in *EntityId, opts
 	return out, nil
 }
 
+func (c *cOREServiceClient) ReadPaths(ctx context.Context, in *PathRequest, opts ...grpc.CallOption) (*PathList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PathList)
+	err := c.cc.Invoke(ctx, COREService_ReadPaths_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cOREServiceClient) ExportSubgraph(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (*ExportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportResponse)
+	err := c.cc.Invoke(ctx, COREService_ExportSubgraph_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cOREServiceClient) CheckConsistency(ctx context.Context, in *ConsistencyRequest, opts ...grpc.CallOption) (*ConsistencyReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConsistencyReport)
+	err := c.cc.Invoke(ctx, COREService_CheckConsistency_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cOREServiceClient) UploadBlob(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[BlobChunk, BlobInfo], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &COREService_ServiceDesc.Streams[0], COREService_UploadBlob_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[BlobChunk, BlobInfo]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type COREService_UploadBlobClient = grpc.ClientStreamingClient[BlobChunk, BlobInfo]
+
+func (c *cOREServiceClient) DownloadBlob(ctx context.Context, in *BlobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BlobChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &COREService_ServiceDesc.Streams[1], COREService_DownloadBlob_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[BlobRequest, BlobChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type COREService_DownloadBlobClient = grpc.ServerStreamingClient[BlobChunk]
+
+func (c *cOREServiceClient) PutKind(ctx context.Context, in *KindDefinition, opts ...grpc.CallOption) (*KindDefinition, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KindDefinition)
+	err := c.cc.Invoke(ctx, COREService_PutKind_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cOREServiceClient) ListKinds(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*KindList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KindList)
+	err := c.cc.Invoke(ctx, COREService_ListKinds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cOREServiceClient) DeleteKind(ctx context.Context, in *KindRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, COREService_DeleteKind_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cOREServiceClient) ExportTabular(ctx context.Context, in *TabularExportRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TabularExportChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &COREService_ServiceDesc.Streams[2], COREService_ExportTabular_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[TabularExportRequest, TabularExportChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type COREService_ExportTabularClient = grpc.ServerStreamingClient[TabularExportChunk]
+
 // COREServiceServer is the server API for COREService service.
 // All implementations must embed UnimplementedCOREServiceServer
 // for forward compatibility.
@@ -108,6 +237,15 @@ type COREServiceServer interface {
 	ReadEntities(context.Context, *ReadEntityRequest) (*EntityList, error)
 	UpdateEntity(context.Context, *UpdateEntityRequest) (*Entity, error)
 	DeleteEntity(context.Context, *EntityId) (*Empty, error)
+	ReadPaths(context.Context, *PathRequest) (*PathList, error)
+	ExportSubgraph(context.Context, *ExportRequest) (*ExportResponse, error)
+	CheckConsistency(context.Context, *ConsistencyRequest) (*ConsistencyReport, error)
+	UploadBlob(grpc.ClientStreamingServer[BlobChunk, BlobInfo]) error
+	DownloadBlob(*BlobRequest, grpc.ServerStreamingServer[BlobChunk]) error
+	PutKind(context.Context, *KindDefinition) (*KindDefinition, error)
+	ListKinds(context.Context, *Empty) (*KindList, error)
+	DeleteKind(context.Context, *KindRequest) (*Empty, error)
+	ExportTabular(*TabularExportRequest, grpc.ServerStreamingServer[TabularExportChunk]) error
 	mustEmbedUnimplementedCOREServiceServer()
 }
 
@@ -133,6 +271,33 @@ func (UnimplementedCOREServiceServer) UpdateEntity(context.Context, *UpdateEntit
 func (UnimplementedCOREServiceServer) DeleteEntity(context.Context, *EntityId) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteEntity not implemented")
 }
+func (UnimplementedCOREServiceServer) ReadPaths(context.Context, *PathRequest) (*PathList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadPaths not implemented")
+}
+func (UnimplementedCOREServiceServer) ExportSubgraph(context.Context, *ExportRequest) (*ExportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportSubgraph not implemented")
+}
+func (UnimplementedCOREServiceServer) CheckConsistency(context.Context, *ConsistencyRequest) (*ConsistencyReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckConsistency not implemented")
+}
+func (UnimplementedCOREServiceServer) UploadBlob(grpc.ClientStreamingServer[BlobChunk, BlobInfo]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadBlob not implemented")
+}
+func (UnimplementedCOREServiceServer) DownloadBlob(*BlobRequest, grpc.ServerStreamingServer[BlobChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadBlob not implemented")
+}
+func (UnimplementedCOREServiceServer) PutKind(context.Context, *KindDefinition) (*KindDefinition, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutKind not implemented")
+}
+func (UnimplementedCOREServiceServer) ListKinds(context.Context, *Empty) (*KindList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListKinds not implemented")
+}
+func (UnimplementedCOREServiceServer) DeleteKind(context.Context, *KindRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteKind not implemented")
+}
+func (UnimplementedCOREServiceServer) ExportTabular(*TabularExportRequest, grpc.ServerStreamingServer[TabularExportChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportTabular not implemented")
+}
 func (UnimplementedCOREServiceServer) mustEmbedUnimplementedCOREServiceServer() {}
 func (UnimplementedCOREServiceServer) testEmbeddedByValue()                     {}
 
@@ -244,6 +409,143 @@ func _COREService_DeleteEntity_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _COREService_ReadPaths_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PathRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(COREServiceServer).ReadPaths(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: COREService_ReadPaths_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(COREServiceServer).ReadPaths(ctx, req.(*PathRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _COREService_ExportSubgraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(COREServiceServer).ExportSubgraph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: COREService_ExportSubgraph_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(COREServiceServer).ExportSubgraph(ctx, req.(*ExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _COREService_CheckConsistency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConsistencyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(COREServiceServer).CheckConsistency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: COREService_CheckConsistency_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(COREServiceServer).CheckConsistency(ctx, req.(*ConsistencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _COREService_UploadBlob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(COREServiceServer).UploadBlob(&grpc.GenericServerStream[BlobChunk, BlobInfo]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type COREService_UploadBlobServer = grpc.ClientStreamingServer[BlobChunk, BlobInfo]
+
+func _COREService_DownloadBlob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BlobRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(COREServiceServer).DownloadBlob(m, &grpc.GenericServerStream[BlobRequest, BlobChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type COREService_DownloadBlobServer = grpc.ServerStreamingServer[BlobChunk]
+
+func _COREService_PutKind_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KindDefinition)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(COREServiceServer).PutKind(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: COREService_PutKind_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(COREServiceServer).PutKind(ctx, req.(*KindDefinition))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _COREService_ListKinds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(COREServiceServer).ListKinds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: COREService_ListKinds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(COREServiceServer).ListKinds(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _COREService_DeleteKind_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KindRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(COREServiceServer).DeleteKind(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: COREService_DeleteKind_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(COREServiceServer).DeleteKind(ctx, req.(*KindRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _COREService_ExportTabular_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TabularExportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(COREServiceServer).ExportTabular(m, &grpc.GenericServerStream[TabularExportRequest, TabularExportChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type COREService_ExportTabularServer = grpc.ServerStreamingServer[TabularExportChunk]
+
 // COREService_ServiceDesc is the grpc.ServiceDesc for COREService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -271,7 +573,47 @@ var COREService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteEntity",
 			Handler:    _COREService_DeleteEntity_Handler,
 		},
+		{
+			MethodName: "ReadPaths",
+			Handler:    _COREService_ReadPaths_Handler,
+		},
+		{
+			MethodName: "ExportSubgraph",
+			Handler:    _COREService_ExportSubgraph_Handler,
+		},
+		{
+			MethodName: "CheckConsistency",
+			Handler:    _COREService_CheckConsistency_Handler,
+		},
+		{
+			MethodName: "PutKind",
+			Handler:    _COREService_PutKind_Handler,
+		},
+		{
+			MethodName: "ListKinds",
+			Handler:    _COREService_ListKinds_Handler,
+		},
+		{
+			MethodName: "DeleteKind",
+			Handler:    _COREService_DeleteKind_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadBlob",
+			Handler:       _COREService_UploadBlob_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadBlob",
+			Handler:       _COREService_DownloadBlob_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportTabular",
+			Handler:       _COREService_ExportTabular_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "types_v1.proto",
 }
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// APIKeyHeader is the gRPC metadata key carrying an API key
+const APIKeyHeader = "x-api-key"
+
+// APIKey is one entry of the API key file. Only the SHA-256 of the key is stored,
+// e.g. from `printf %s "$KEY" | sha256sum`.
+type APIKey struct {
+	Name      string   `json:"name"`
+	KeySHA256 string   `json:"key_sha256"`
+	Roles     []string `json:"roles"`
+	Kinds     []string `json:"kinds,omitempty"`
+	Tenant    string   `json:"tenant,omitempty"`
+}
+
+// APIKeyAuthenticator accepts "x-api-key: <key>" metadata
+type APIKeyAuthenticator struct {
+	keys []apiKeyEntry
+}
+
+type apiKeyEntry struct {
+	hash      []byte
+	principal *Principal
+}
+
+// NewAPIKeyAuthenticator validates the keys and their roles
+func NewAPIKeyAuthenticator(keys []APIKey) (*APIKeyAuthenticator, error) {
+	authenticator := &APIKeyAuthenticator{}
+	for i, key := range keys {
+		if key.Name == "" {
+			return nil, fmt.Errorf("API key %d has no name", i)
+		}
+		hash, err := hex.DecodeString(strings.TrimSpace(key.KeySHA256))
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("API key %s: key_sha256 must be a hex encoded SHA-256", key.Name)
+		}
+		principal, err := newPrincipal(key.Name, key.Roles, key.Kinds)
+		if err != nil {
+			return nil, fmt.Errorf("API key %v", err)
+		}
+		principal.Tenant = key.Tenant
+		authenticator.keys = append(authenticator.keys, apiKeyEntry{hash: hash, principal: principal})
+	}
+	return authenticator, nil
+}
+
+// LoadAPIKeys reads a JSON array of APIKey entries
+func LoadAPIKeys(path string) (*APIKeyAuthenticator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading API keys: %v", err)
+	}
+	var keys []APIKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("error parsing API keys %s: %v", path, err)
+	}
+	return NewAPIKeyAuthenticator(keys)
+}
+
+// Authenticate looks the key up by its hash, comparing every entry in constant time
+func (a *APIKeyAuthenticator) Authenticate(ctx context.Context, md metadata.MD) (*Principal, error) {
+	values := md.Get(APIKeyHeader)
+	if len(values) == 0 || values[0] == "" {
+		return nil, ErrNoCredentials
+	}
+	hash := sha256.Sum256([]byte(values[0]))
+
+	var principal *Principal
+	for _, entry := range a.keys {
+		if subtle.ConstantTimeCompare(hash[:], entry.hash) == 1 {
+			principal = entry.principal
+		}
+	}
+	if principal == nil {
+		return nil, fmt.Errorf("unknown API key")
+	}
+	return principal, nil
+}
@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func signToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(testSecret)
+	require.NoError(t, err)
+	return token
+}
+
+func TestRoleHierarchy(t *testing.T) {
+	ingester := &Principal{Subject: "importer", Roles: []Role{RoleIngester}}
+	assert.True(t, ingester.Has(RoleReader))
+	assert.True(t, ingester.Has(RoleIngester))
+	assert.False(t, ingester.Has(RoleAdmin))
+
+	_, err := ParseRole("superuser")
+	assert.Error(t, err)
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	authenticator, err := NewHMACAuthenticator(testSecret, "issuer", "core")
+	require.NoError(t, err)
+
+	valid := signToken(t, jwt.MapClaims{
+		"sub": "ingestion-api", "iss": "issuer", "aud": "core",
+		"exp": time.Now().Add(time.Hour).Unix(), "roles": []string{"ingester"}, "kinds": []string{"Person"},
+	})
+	principal, err := authenticator.Authenticate(context.Background(), metadata.Pairs("authorization", "Bearer "+valid))
+	require.NoError(t, err)
+	assert.Equal(t, "ingestion-api", principal.Subject)
+	assert.Equal(t, []Role{RoleIngester}, principal.Roles)
+	assert.True(t, principal.AllowsKind("Person"))
+	assert.False(t, principal.AllowsKind("Organisation"))
+
+	expired := signToken(t, jwt.MapClaims{"sub": "a", "iss": "issuer", "aud": "core", "exp": time.Now().Add(-time.Minute).Unix(), "roles": []string{"reader"}})
+	_, err = authenticator.Authenticate(context.Background(), metadata.Pairs("authorization", "Bearer "+expired))
+	assert.Error(t, err)
+
+	wrongAudience := signToken(t, jwt.MapClaims{"sub": "a", "iss": "issuer", "aud": "other", "exp": time.Now().Add(time.Hour).Unix(), "roles": []string{"reader"}})
+	_, err = authenticator.Authenticate(context.Background(), metadata.Pairs("authorization", "Bearer "+wrongAudience))
+	assert.Error(t, err)
+
+	_, err = authenticator.Authenticate(context.Background(), metadata.MD{})
+	assert.ErrorIs(t, err, ErrNoCredentials)
+
+	_, err = NewHMACAuthenticator([]byte("short"), "", "")
+	assert.Error(t, err)
+}
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	hash := sha256.Sum256([]byte("secret-key"))
+	authenticator, err := NewAPIKeyAuthenticator([]APIKey{{Name: "dashboard", KeySHA256: hex.EncodeToString(hash[:]), Roles: []string{"reader"}}})
+	require.NoError(t, err)
+
+	principal, err := authenticator.Authenticate(context.Background(), metadata.Pairs(APIKeyHeader, "secret-key"))
+	require.NoError(t, err)
+	assert.Equal(t, "dashboard", principal.Subject)
+
+	_, err = authenticator.Authenticate(context.Background(), metadata.Pairs(APIKeyHeader, "wrong-key"))
+	assert.Error(t, err)
+
+	_, err = NewAPIKeyAuthenticator([]APIKey{{Name: "bad", KeySHA256: "abc", Roles: []string{"reader"}}})
+	assert.Error(t, err)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	hash := sha256.Sum256([]byte("reader-key"))
+	apiKeys, err := NewAPIKeyAuthenticator([]APIKey{{Name: "reader", KeySHA256: hex.EncodeToString(hash[:]), Roles: []string{"reader"}}})
+	require.NoError(t, err)
+	interceptor := UnaryServerInterceptor(apiKeys)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return PrincipalFromContext(ctx), nil
+	}
+	call := func(method string, md metadata.MD) (interface{}, error) {
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		return interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	}
+
+	principal, err := call("/core.COREService/ReadEntity", metadata.Pairs(APIKeyHeader, "reader-key"))
+	require.NoError(t, err)
+	assert.Equal(t, "reader", principal.(*Principal).Subject)
+
+	_, err = call("/core.COREService/DeleteEntity", metadata.Pairs(APIKeyHeader, "reader-key"))
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = call("/core.COREService/ReadEntity", metadata.MD{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = call("/core.COREService/ReadEntity", metadata.Pairs(APIKeyHeader, "wrong-key"))
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = call("/grpc.health.v1.Health/Check", metadata.MD{})
+	assert.NoError(t, err)
+}
+
+func TestAccessPolicy(t *testing.T) {
+	policy, err := ParseAccessPolicy([]byte(`{"minRole": "reader", "columns": {
+		"salary": {"minRole": "admin", "mask": "redact"}, "nic": {"minRole": "ingester"}, "name": {}}}`))
+	require.NoError(t, err)
+
+	reader := WithPrincipal(context.Background(), &Principal{Subject: "dashboard", Roles: []Role{RoleReader}})
+	ingester := WithPrincipal(context.Background(), &Principal{Subject: "importer", Roles: []Role{RoleIngester}})
+	admin := WithPrincipal(context.Background(), &Principal{Subject: "ops", Roles: []Role{RoleAdmin}})
+
+	assert.True(t, policy.Allows(reader))
+	omit, redact := policy.HiddenColumns(reader)
+	assert.Equal(t, []string{"nic"}, omit)
+	assert.Equal(t, []string{"salary"}, redact)
+
+	omit, redact = policy.HiddenColumns(ingester)
+	assert.Empty(t, omit)
+	assert.Equal(t, []string{"salary"}, redact)
+
+	omit, redact = policy.HiddenColumns(admin)
+	assert.Empty(t, omit)
+	assert.Empty(t, redact)
+
+	// Everything is visible when authentication is off
+	omit, redact = policy.HiddenColumns(context.Background())
+	assert.Empty(t, omit)
+	assert.Empty(t, redact)
+
+	var unrestricted *AccessPolicy
+	omit, redact = unrestricted.HiddenColumns(reader)
+	assert.Empty(t, omit)
+	assert.Empty(t, redact)
+
+	rule := Rule{MinRole: RoleAdmin}
+	assert.Equal(t, codes.PermissionDenied, status.Code(rule.Authorize(ingester, "attribute salary")))
+	assert.NoError(t, rule.Authorize(admin, "attribute salary"))
+
+	_, err = ParseAccessPolicy([]byte(`{"columns": {"salary": {"minRole": "owner"}}}`))
+	assert.Error(t, err)
+	_, err = ParseKeyRules([]byte(`{"email": {"mask": "blur"}}`))
+	assert.Error(t, err)
+}
+
+// TestMethodRolesCoverService tests that every COREService method is listed, so none falls back to admin by accident
+func TestMethodRolesCoverService(t *testing.T) {
+	var methods []string
+	for _, method := range pb.COREService_ServiceDesc.Methods {
+		methods = append(methods, method.MethodName)
+	}
+	for _, stream := range pb.COREService_ServiceDesc.Streams {
+		methods = append(methods, stream.StreamName)
+	}
+	for _, method := range methods {
+		_, ok := MethodRoles["/"+pb.COREService_ServiceDesc.ServiceName+"/"+method]
+		assert.True(t, ok, "%s is not listed in MethodRoles", method)
+	}
+	assert.Equal(t, RoleReader, MethodRoles["/core.COREService/ExportTabular"])
+}
+
+func TestAuthorizeKind(t *testing.T) {
+	assert.NoError(t, AuthorizeKind(context.Background(), "Person"))
+
+	ctx := WithPrincipal(context.Background(), &Principal{Subject: "hr", Roles: []Role{RoleAdmin}, Kinds: []string{"Person"}})
+	assert.NoError(t, AuthorizeKind(ctx, "Person"))
+	assert.Equal(t, codes.PermissionDenied, status.Code(AuthorizeKind(ctx, "Organisation")))
+	assert.True(t, KindRestricted(ctx))
+}
+
+func TestAuthorizeRole(t *testing.T) {
+	assert.NoError(t, AuthorizeRole(context.Background(), RoleAdmin, "set a policy"))
+
+	admin := WithPrincipal(context.Background(), &Principal{Subject: "ops", Roles: []Role{RoleAdmin}})
+	assert.NoError(t, AuthorizeRole(admin, RoleIngester, "set a policy"))
+
+	ingester := WithPrincipal(context.Background(), &Principal{Subject: "etl", Roles: []Role{RoleIngester}})
+	err := AuthorizeRole(ingester, RoleAdmin, "set a policy")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Contains(t, err.Error(), "etl needs the admin role to set a policy")
+}
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FromEnv builds the authenticator configured by CORE_AUTH_MODE, a comma separated list of
+// "jwt" and "apikey". It returns nil when CORE_AUTH_MODE is empty or "none", leaving the service open.
+//
+//   - jwt: CORE_AUTH_JWT_SECRET (HMAC) or CORE_AUTH_JWT_PUBLIC_KEY_FILE (RSA, ECDSA or Ed25519),
+//     with optional CORE_AUTH_JWT_ISSUER and CORE_AUTH_JWT_AUDIENCE checks
+//   - apikey: CORE_AUTH_API_KEYS_FILE, a JSON array of APIKey entries
+func FromEnv() (Authenticator, error) {
+	mode := strings.TrimSpace(os.Getenv("CORE_AUTH_MODE"))
+	if mode == "" || mode == "none" {
+		return nil, nil
+	}
+
+	var authenticators Authenticators
+	for _, name := range strings.Split(mode, ",") {
+		switch strings.TrimSpace(name) {
+		case "jwt":
+			authenticator, err := jwtFromEnv()
+			if err != nil {
+				return nil, err
+			}
+			authenticators = append(authenticators, authenticator)
+		case "apikey":
+			path := os.Getenv("CORE_AUTH_API_KEYS_FILE")
+			if path == "" {
+				return nil, fmt.Errorf("CORE_AUTH_MODE apikey needs CORE_AUTH_API_KEYS_FILE")
+			}
+			authenticator, err := LoadAPIKeys(path)
+			if err != nil {
+				return nil, err
+			}
+			authenticators = append(authenticators, authenticator)
+		default:
+			return nil, fmt.Errorf("unsupported CORE_AUTH_MODE %q, use jwt, apikey, both separated by a comma, or none", name)
+		}
+	}
+	return authenticators, nil
+}
+
+func jwtFromEnv() (*JWTAuthenticator, error) {
+	issuer := os.Getenv("CORE_AUTH_JWT_ISSUER")
+	audience := os.Getenv("CORE_AUTH_JWT_AUDIENCE")
+	secret := os.Getenv("CORE_AUTH_JWT_SECRET")
+	keyFile := os.Getenv("CORE_AUTH_JWT_PUBLIC_KEY_FILE")
+	switch {
+	case secret != "" && keyFile != "":
+		return nil, fmt.Errorf("set either CORE_AUTH_JWT_SECRET or CORE_AUTH_JWT_PUBLIC_KEY_FILE, not both")
+	case secret != "":
+		return NewHMACAuthenticator([]byte(secret), issuer, audience)
+	case keyFile != "":
+		return NewPublicKeyAuthenticator(keyFile, issuer, audience)
+	default:
+		return nil, fmt.Errorf("CORE_AUTH_MODE jwt needs CORE_AUTH_JWT_SECRET or CORE_AUTH_JWT_PUBLIC_KEY_FILE")
+	}
+}
+
+// TLSConfigFromEnv builds the server TLS configuration from CORE_TLS_CERT_FILE and CORE_TLS_KEY_FILE.
+// With CORE_TLS_CLIENT_CA_FILE set, clients must present a certificate signed by that CA (mTLS).
+// It returns nil when no certificate is configured, leaving the server on plaintext.
+func TLSConfigFromEnv() (*tls.Config, error) {
+	certFile := os.Getenv("CORE_TLS_CERT_FILE")
+	keyFile := os.Getenv("CORE_TLS_KEY_FILE")
+	clientCAFile := os.Getenv("CORE_TLS_CLIENT_CA_FILE")
+	if certFile == "" && keyFile == "" {
+		if clientCAFile != "" {
+			return nil, fmt.Errorf("CORE_TLS_CLIENT_CA_FILE needs CORE_TLS_CERT_FILE and CORE_TLS_KEY_FILE")
+		}
+		return nil, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("set both CORE_TLS_CERT_FILE and CORE_TLS_KEY_FILE")
+	}
+
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error loading TLS certificate: %v", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if clientCAFile != "" {
+		pemData, err := os.ReadFile(clientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading client CA: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pemData) {
+			return nil, fmt.Errorf("no certificates found in %s", clientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MethodRoles is the role each RPC requires, keyed by full method name.
+// Methods that are not listed require RoleAdmin.
+var MethodRoles = map[string]Role{
+	"/core.COREService/ReadEntity":     RoleReader,
+	"/core.COREService/ReadEntities":   RoleReader,
+	"/core.COREService/ReadPaths":      RoleReader,
+	"/core.COREService/ExportSubgraph": RoleReader,
+	"/core.COREService/DownloadBlob":   RoleReader,
+	"/core.COREService/ListKinds":      RoleReader,
+	"/core.COREService/ExportTabular":  RoleReader,
+
+	"/core.COREService/CreateEntity": RoleIngester,
+	"/core.COREService/UpdateEntity": RoleIngester,
+	"/core.COREService/UploadBlob":   RoleIngester,
+
+	"/core.COREService/DeleteEntity":     RoleAdmin,
+	"/core.COREService/CheckConsistency": RoleAdmin,
+	"/core.COREService/PutKind":          RoleAdmin,
+	"/core.COREService/DeleteKind":       RoleAdmin,
+
+	"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo":      RoleReader,
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": RoleReader,
+}
+
+// PublicMethodPrefixes are served without credentials so probes keep working
+var PublicMethodPrefixes = []string{"/grpc.health.v1."}
+
+// Authenticator turns the credentials in the call metadata into a Principal
+type Authenticator interface {
+	// Authenticate returns ErrNoCredentials when the metadata carries none it understands
+	Authenticate(ctx context.Context, md metadata.MD) (*Principal, error)
+}
+
+// ErrNoCredentials lets the next authenticator try
+var ErrNoCredentials = errors.New("no credentials")
+
+// Authenticators tries each authenticator in turn, e.g. JWT then API key
+type Authenticators []Authenticator
+
+// Authenticate returns the principal of the first authenticator that finds its credentials
+func (a Authenticators) Authenticate(ctx context.Context, md metadata.MD) (*Principal, error) {
+	for _, authenticator := range a {
+		principal, err := authenticator.Authenticate(ctx, md)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		return principal, err
+	}
+	return nil, ErrNoCredentials
+}
+
+// authorize authenticates the caller of method and checks its role, returning a context carrying the principal
+func authorize(ctx context.Context, authenticator Authenticator, method string) (context.Context, error) {
+	for _, prefix := range PublicMethodPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return ctx, nil
+		}
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	principal, err := authenticator.Authenticate(ctx, md)
+	if errors.Is(err, ErrNoCredentials) {
+		slog.WarnContext(ctx, "Rejected call without credentials", "method", method)
+		return nil, status.Error(codes.Unauthenticated, "missing credentials, send an authorization bearer token or an x-api-key")
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Rejected call with invalid credentials", "method", method, "error", err)
+		return nil, status.Errorf(codes.Unauthenticated, "invalid credentials: %v", err)
+	}
+
+	required, ok := MethodRoles[method]
+	if !ok {
+		required = RoleAdmin
+	}
+	if !principal.Has(required) {
+		return nil, permissionDenied(ctx, "%s needs the %s role for %s", principal.Subject, required, method)
+	}
+	return WithPrincipal(ctx, principal), nil
+}
+
+// permissionDenied logs the denial and returns it as codes.PermissionDenied
+func permissionDenied(ctx context.Context, format string, args ...any) error {
+	message := fmt.Sprintf(format, args...)
+	slog.WarnContext(ctx, "Permission denied", "reason", message)
+	return status.Error(codes.PermissionDenied, message)
+}
+
+// UnaryServerInterceptor authenticates and authorizes unary calls
+func UnaryServerInterceptor(authenticator Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authorize(ctx, authenticator, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor authenticates and authorizes streaming calls
+func StreamServerInterceptor(authenticator Authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(stream.Context(), authenticator, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &principalStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// principalStream overrides the stream context with one carrying the principal
+type principalStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *principalStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/metadata"
+)
+
+// JWTAuthenticator accepts "authorization: Bearer <token>" metadata.
+// The token's "sub" claim names the caller, "roles" lists its roles, the optional "kinds" claim
+// limits it to entities of those Kind.Major values and the optional "tenant" claim binds it to a tenant.
+type JWTAuthenticator struct {
+	key     interface{}
+	options []jwt.ParserOption
+}
+
+// jwtClaims are the claims read from a token
+type jwtClaims struct {
+	Roles  []string `json:"roles"`
+	Kinds  []string `json:"kinds"`
+	Tenant string   `json:"tenant"`
+	jwt.RegisteredClaims
+}
+
+// NewHMACAuthenticator verifies HS256, HS384 and HS512 tokens signed with a shared secret
+func NewHMACAuthenticator(secret []byte, issuer, audience string) (*JWTAuthenticator, error) {
+	if len(secret) < 32 {
+		return nil, fmt.Errorf("JWT secret must be at least 32 bytes")
+	}
+	return newJWTAuthenticator(secret, []string{"HS256", "HS384", "HS512"}, issuer, audience), nil
+}
+
+// NewPublicKeyAuthenticator verifies RS*, PS*, ES* or EdDSA tokens against a PEM encoded public key file
+func NewPublicKeyAuthenticator(path, issuer, audience string) (*JWTAuthenticator, error) {
+	pemData, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading JWT public key: %v", err)
+	}
+
+	var key crypto.PublicKey
+	var methods []string
+	if key, err = jwt.ParseRSAPublicKeyFromPEM(pemData); err == nil {
+		methods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}
+	} else if key, err = jwt.ParseECPublicKeyFromPEM(pemData); err == nil {
+		methods = []string{"ES256", "ES384", "ES512"}
+	} else if key, err = jwt.ParseEdPublicKeyFromPEM(pemData); err == nil {
+		methods = []string{"EdDSA"}
+	} else {
+		return nil, fmt.Errorf("JWT public key %s is not an RSA, ECDSA or Ed25519 PEM key", path)
+	}
+	return newJWTAuthenticator(key, methods, issuer, audience), nil
+}
+
+func newJWTAuthenticator(key interface{}, methods []string, issuer, audience string) *JWTAuthenticator {
+	options := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithExpirationRequired()}
+	if issuer != "" {
+		options = append(options, jwt.WithIssuer(issuer))
+	}
+	if audience != "" {
+		options = append(options, jwt.WithAudience(audience))
+	}
+	return &JWTAuthenticator{key: key, options: options}
+}
+
+// Authenticate verifies the bearer token and reads the principal from its claims
+func (a *JWTAuthenticator) Authenticate(ctx context.Context, md metadata.MD) (*Principal, error) {
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, ErrNoCredentials
+	}
+	scheme, token, found := strings.Cut(values[0], " ")
+	if !found || !strings.EqualFold(scheme, "bearer") {
+		return nil, ErrNoCredentials
+	}
+
+	claims := &jwtClaims{}
+	_, err := jwt.ParseWithClaims(strings.TrimSpace(token), claims, func(*jwt.Token) (interface{}, error) {
+		return a.key, nil
+	}, a.options...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %v", err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("token has no sub claim")
+	}
+	principal, err := newPrincipal(claims.Subject, claims.Roles, claims.Kinds)
+	if err != nil {
+		return nil, err
+	}
+	principal.Tenant = claims.Tenant
+	return principal, nil
+}
+
+// newPrincipal validates the role names given for a caller
+func newPrincipal(subject string, roleNames, kinds []string) (*Principal, error) {
+	principal := &Principal{Subject: subject, Kinds: kinds}
+	for _, name := range roleNames {
+		role, err := ParseRole(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", subject, err)
+		}
+		principal.Roles = append(principal.Roles, role)
+	}
+	if len(principal.Roles) == 0 {
+		return nil, fmt.Errorf("%s has no roles", subject)
+	}
+	return principal, nil
+}
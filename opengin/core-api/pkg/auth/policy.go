@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Mask says what a caller without the required role gets in place of a restricted value
+type Mask string
+
+const (
+	// MaskOmit leaves the attribute, column or metadata key out (the default)
+	MaskOmit Mask = "omit"
+	// MaskRedact keeps it with its value replaced: NULL for columns, RedactedValue otherwise
+	MaskRedact Mask = "redact"
+)
+
+// RedactedValue replaces redacted attributes and metadata values
+const RedactedValue = "[redacted]"
+
+// Rule restricts an attribute, column or metadata key to callers holding MinRole.
+// A rule without MinRole restricts nothing.
+type Rule struct {
+	MinRole Role `json:"minRole,omitempty"`
+	Mask    Mask `json:"mask,omitempty"`
+}
+
+// AccessPolicy restricts a whole attribute and, for tabular attributes, individual columns
+type AccessPolicy struct {
+	Rule
+	Columns map[string]Rule `json:"columns,omitempty"`
+}
+
+// Validate checks the role and mask names
+func (r Rule) Validate() error {
+	if r.MinRole != "" {
+		if _, err := ParseRole(string(r.MinRole)); err != nil {
+			return err
+		}
+	}
+	switch r.Mask {
+	case "", MaskOmit, MaskRedact:
+		return nil
+	default:
+		return fmt.Errorf("unknown mask %q, use omit or redact", r.Mask)
+	}
+}
+
+// Allows reports whether the caller may see the value. Everything is visible when authentication is off.
+func (r Rule) Allows(ctx context.Context) bool {
+	principal := PrincipalFromContext(ctx)
+	return r.MinRole == "" || principal == nil || principal.Has(r.MinRole)
+}
+
+// Redacts reports whether a hidden value is kept with its content replaced rather than left out
+func (r Rule) Redacts() bool {
+	return r.Mask == MaskRedact
+}
+
+// Authorize returns codes.PermissionDenied when the caller may not see the named resource
+func (r Rule) Authorize(ctx context.Context, resource string) error {
+	if r.Allows(ctx) {
+		return nil
+	}
+	subject := "caller"
+	if principal := PrincipalFromContext(ctx); principal != nil {
+		subject = principal.Subject
+	}
+	return permissionDenied(ctx, "%s needs the %s role to read %s", subject, r.MinRole, resource)
+}
+
+// Validate checks the attribute rule and every column rule
+func (p *AccessPolicy) Validate() error {
+	if err := p.Rule.Validate(); err != nil {
+		return err
+	}
+	for column, rule := range p.Columns {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("column %s: %v", column, err)
+		}
+	}
+	return nil
+}
+
+// HiddenColumns returns the columns the caller may not see, split into those to leave out and those to redact
+func (p *AccessPolicy) HiddenColumns(ctx context.Context) (omit, redact []string) {
+	if p == nil {
+		return nil, nil
+	}
+	for column, rule := range p.Columns {
+		if rule.Allows(ctx) {
+			continue
+		}
+		if rule.Redacts() {
+			redact = append(redact, column)
+		} else {
+			omit = append(omit, column)
+		}
+	}
+	sort.Strings(omit)
+	sort.Strings(redact)
+	return omit, redact
+}
+
+// ParseAccessPolicy reads an attribute policy such as
+// {"minRole": "ingester", "columns": {"salary": {"minRole": "admin", "mask": "redact"}}}
+func ParseAccessPolicy(data []byte) (*AccessPolicy, error) {
+	var policy AccessPolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("invalid access policy: %v", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid access policy: %v", err)
+	}
+	return &policy, nil
+}
+
+// ParseKeyRules reads the rules of metadata keys such as {"email": {"minRole": "admin"}}
+func ParseKeyRules(data []byte) (map[string]Rule, error) {
+	var rules map[string]Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("invalid metadata access rules: %v", err)
+	}
+	for key, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid metadata access rule for %s: %v", key, err)
+		}
+	}
+	return rules, nil
+}
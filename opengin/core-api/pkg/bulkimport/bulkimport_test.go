@@ -0,0 +1,261 @@
+package bulkimport
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"lk/datafoundation/core-api/commons"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// readAll reads every row from a reader, collecting row errors separately
+func readAll(t *testing.T, reader RowReader) ([]Row, []error) {
+	var rows []Row
+	var errs []error
+	for {
+		row, err := reader.Next()
+		if err == io.EOF || errors.Is(err, ErrFatal) {
+			return rows, errs
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		rows = append(rows, row)
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	input := "id,kind_major,kind_minor,name,created,terminated,metadata.source\n" +
+		"e1,Person,Citizen,\"Doe, Jane\",2024-01-01T00:00:00Z,,census\n" +
+		"e2,Person,Citizen,John,2024-01-01T00:00:00Z\n"
+
+	reader, err := NewReader(strings.NewReader(input), "nodes.csv", "")
+	assert.NoError(t, err)
+
+	rows, errs := readAll(t, reader)
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, 1, len(errs), "Expected the short row to be reported")
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Equal(t, "Doe, Jane", rows[0].Value("name"))
+	assert.Equal(t, "census", rows[0].Value("metadata.source"))
+	assert.Equal(t, "", rows[0].Value("missing"))
+}
+
+func TestJSONLReader(t *testing.T) {
+	input := `{"id": "e1", "name": "Jane", "metadata": {"source": "census", "population": 42}}
+
+{"id": "e2", "name": "John", "active": true}
+not json
+`
+	reader, err := NewReader(strings.NewReader(input), "nodes.jsonl", "")
+	assert.NoError(t, err)
+
+	rows, errs := readAll(t, reader)
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, 1, len(errs))
+	assert.Equal(t, 1, rows[0].Line)
+	assert.Equal(t, "census", rows[0].Value("metadata.source"))
+	assert.Equal(t, "42", rows[0].Value("metadata.population"))
+	assert.Equal(t, 3, rows[1].Line)
+	assert.Equal(t, "true", rows[1].Value("active"))
+}
+
+func TestReaderFatalErrors(t *testing.T) {
+	input := `{"id": "e1"}` + "\n" + strings.Repeat("x", MaxLineSize+1) + "\n" + `{"id": "e3"}` + "\n"
+	reader, err := NewReader(strings.NewReader(input), "nodes.jsonl", "")
+	assert.NoError(t, err)
+
+	row, err := reader.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, "e1", row.Value("id"))
+	for i := 0; i < 2; i++ {
+		row, err = reader.Next()
+		assert.ErrorIs(t, err, ErrFatal, "Expected the oversized line to end the input")
+		assert.Equal(t, 2, row.Line)
+	}
+
+	failing := io.MultiReader(strings.NewReader("id,name\ne1,Jane\n"), iotest.ErrReader(errors.New("disk failure")))
+	reader, err = NewReader(failing, "nodes.csv", "")
+	assert.NoError(t, err)
+
+	_, err = reader.Next()
+	assert.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = reader.Next()
+		assert.ErrorIs(t, err, ErrFatal, "Expected an I/O error to end the input")
+	}
+
+	reader, err = NewReader(strings.NewReader("id,name\ne1,\"Jane\"x\ne2,John\n"), "nodes.csv", "")
+	assert.NoError(t, err)
+	_, err = reader.Next()
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrFatal), "Expected a malformed row to only affect that row")
+	row, err = reader.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, "e2", row.Value("id"))
+}
+
+func TestNewReaderUnknownFormat(t *testing.T) {
+	_, err := NewReader(strings.NewReader(""), "nodes.txt", "")
+	assert.Error(t, err)
+
+	_, err = NewReader(strings.NewReader(""), "nodes.csv", "")
+	assert.Error(t, err, "Expected an error for a CSV file without a header")
+}
+
+func TestParseMapping(t *testing.T) {
+	mapping, err := ParseMapping("kind_major=type, name = label", DefaultNodeMapping())
+	assert.NoError(t, err)
+	assert.Equal(t, "type", mapping[FieldKindMajor])
+	assert.Equal(t, "label", mapping[FieldName])
+	assert.Equal(t, "created", mapping[FieldCreated])
+
+	_, err = ParseMapping("colour=hue", DefaultNodeMapping())
+	assert.Error(t, err)
+	_, err = ParseMapping("name", DefaultNodeMapping())
+	assert.Error(t, err)
+}
+
+func TestParseNode(t *testing.T) {
+	mapping, _ := ParseMapping("kind_major=type", DefaultNodeMapping())
+	row := Row{Line: 2, Fields: map[string]string{
+		"id":              "e1",
+		"type":            "Person",
+		"kind_minor":      "Citizen",
+		"name":            " Jane ",
+		"created":         "2024-01-01T00:00:00Z",
+		"terminated":      "2024-06-01T00:00:00Z",
+		"metadata.source": "census",
+		"metadata.empty":  "",
+	}}
+
+	record, err := ParseNode(row, mapping)
+	assert.NoError(t, err)
+	assert.Equal(t, "Person", record.KindMajor)
+	assert.Equal(t, "Jane", record.Name)
+	assert.Equal(t, map[string]string{"source": "census"}, record.Metadata)
+
+	entity := record.ToEntity()
+	assert.Equal(t, "e1", entity.Id)
+	assert.Equal(t, "Citizen", entity.Kind.Minor)
+	assert.Equal(t, "Jane", commons.ExtractStringFromAny(entity.Name.Value))
+	assert.Equal(t, "2024-06-01T00:00:00Z", entity.Terminated)
+	assert.Equal(t, "census", commons.ExtractStringFromAny(entity.Metadata["source"]))
+}
+
+func TestParseNodeValidation(t *testing.T) {
+	testCases := map[string]map[string]string{
+		"missing id":         {"kind_major": "Person", "kind_minor": "Citizen", "name": "Jane", "created": "2024-01-01T00:00:00Z"},
+		"invalid kind major": {"id": "e1", "kind_major": "Person) DETACH DELETE (n", "kind_minor": "Citizen", "name": "Jane", "created": "2024-01-01T00:00:00Z"},
+		"missing kind minor": {"id": "e1", "kind_major": "Person", "name": "Jane", "created": "2024-01-01T00:00:00Z"},
+		"invalid created":    {"id": "e1", "kind_major": "Person", "kind_minor": "Citizen", "name": "Jane", "created": "01/01/2024"},
+		"is before created":  {"id": "e1", "kind_major": "Person", "kind_minor": "Citizen", "name": "Jane", "created": "2024-01-01T00:00:00Z", "terminated": "2023-01-01T00:00:00Z"},
+	}
+
+	for expected, fields := range testCases {
+		_, err := ParseNode(Row{Fields: fields}, DefaultNodeMapping())
+		if assert.Error(t, err, expected) {
+			assert.Contains(t, err.Error(), expected)
+		}
+	}
+}
+
+func TestParseEdge(t *testing.T) {
+	row := Row{Fields: map[string]string{
+		"source":     "e1",
+		"target":     "e2",
+		"name":       "KNOWS",
+		"start_time": "2024-01-01T00:00:00Z",
+	}}
+
+	record, err := ParseEdge(row, DefaultEdgeMapping())
+	assert.NoError(t, err)
+	assert.Equal(t, "e1_KNOWS_e2_2024-01-01T00:00:00Z", record.Id, "Expected a derived id when none is given")
+
+	entity := record.ToEntity()
+	assert.Equal(t, "e1", entity.Id)
+	assert.Equal(t, "e2", entity.Relationships[record.Id].RelatedEntityId)
+	assert.Equal(t, "KNOWS", entity.Relationships[record.Id].Name)
+
+	row.Fields["name"] = "KNOWS`]->() DELETE x//"
+	_, err = ParseEdge(row, DefaultEdgeMapping())
+	assert.Error(t, err)
+
+	_, err = ParseEdge(Row{Fields: map[string]string{}}, DefaultEdgeMapping())
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "missing source")
+		assert.Contains(t, err.Error(), "missing target")
+		assert.Contains(t, err.Error(), "missing start_time")
+	}
+}
+
+func TestCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "import.checkpoint.json")
+
+	checkpoint, err := LoadCheckpoint(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, checkpoint.NodesDone)
+	assert.True(t, checkpoint.Matches("nodes.csv", "edges.csv"))
+
+	checkpoint.NodesFile = "nodes.csv"
+	checkpoint.EdgesFile = "edges.csv"
+	checkpoint.NodesDone = 1500
+	assert.NoError(t, checkpoint.Save(path))
+
+	loaded, err := LoadCheckpoint(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 1500, loaded.NodesDone)
+	assert.True(t, loaded.Matches("nodes.csv", "edges.csv"))
+	assert.False(t, loaded.Matches("other.csv", "edges.csv"))
+}
+
+func TestRejectReport(t *testing.T) {
+	var buf bytes.Buffer
+	report, err := NewRejectReport(&buf, true)
+	assert.NoError(t, err)
+
+	assert.NoError(t, report.Add(Rejection{File: "nodes.csv", Line: 3, Id: "e1", Reason: "missing name, kind"}))
+	assert.Equal(t, 1, report.Count())
+	assert.Equal(t, "file,line,id,reason\nnodes.csv,3,e1,\"missing name, kind\"\n", buf.String())
+
+	buf.Reset()
+	report, err = NewRejectReport(&buf, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "", buf.String())
+}
+
+func TestParseNodeAttributes(t *testing.T) {
+	fields := map[string]string{
+		"id":         "e1",
+		"kind_major": "Organisation",
+		"kind_minor": "Department",
+		"name":       "Finance",
+		"created":    "2024-01-01T00:00:00Z",
+		"attributes": `{"budget": {"columns": ["year", "amount"], "rows": [[2024, 100]]}}`,
+	}
+
+	record, err := ParseNode(Row{Fields: fields}, DefaultNodeMapping())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(record.Attributes))
+
+	entity := record.ToEntity()
+	values := entity.Attributes["budget"].Values
+	assert.Equal(t, 1, len(values))
+	assert.Equal(t, "2024-01-01T00:00:00Z", values[0].StartTime)
+	assert.Equal(t, "type.googleapis.com/google.protobuf.Struct", values[0].Value.TypeUrl)
+
+	fields["attributes"] = `{"budget": 100}`
+	_, err = ParseNode(Row{Fields: fields}, DefaultNodeMapping())
+	assert.Error(t, err)
+
+	fields["attributes"] = `not json`
+	_, err = ParseNode(Row{Fields: fields}, DefaultNodeMapping())
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package bulkimport
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Checkpoint records how far an import has progressed so a failed run can be resumed.
+// Rows are counted in file order, and only whole batches are recorded.
+type Checkpoint struct {
+	NodesFile string `json:"nodesFile,omitempty"`
+	NodesDone int    `json:"nodesDone"`
+	EdgesFile string `json:"edgesFile,omitempty"`
+	EdgesDone int    `json:"edgesDone"`
+}
+
+// LoadCheckpoint reads a checkpoint file, returning an empty checkpoint if the file does not exist
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Checkpoint{}, nil
+		}
+		return nil, fmt.Errorf("error reading checkpoint: %v", err)
+	}
+
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("error parsing checkpoint %s: %v", path, err)
+	}
+	return &checkpoint, nil
+}
+
+// Save writes the checkpoint atomically so an interrupted write never leaves a truncated file
+func (c *Checkpoint) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding checkpoint: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating checkpoint: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing checkpoint: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing checkpoint: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error saving checkpoint: %v", err)
+	}
+	return nil
+}
+
+// Matches reports whether the checkpoint was written for the same input files.
+// An empty checkpoint matches any input.
+func (c *Checkpoint) Matches(nodesFile string, edgesFile string) bool {
+	if c.NodesDone == 0 && c.EdgesDone == 0 {
+		return true
+	}
+	return c.NodesFile == nodesFile && c.EdgesFile == edgesFile
+}
@@ -0,0 +1,190 @@
+package bulkimport
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Row is a single input row keyed by column name
+type Row struct {
+	Line   int
+	Fields map[string]string
+}
+
+// Value returns the trimmed value of a column, or an empty string if the column is absent
+func (r Row) Value(column string) string {
+	if column == "" {
+		return ""
+	}
+	return strings.TrimSpace(r.Fields[column])
+}
+
+// RowReader reads rows one at a time, returning io.EOF when the input is exhausted.
+// Errors wrapping ErrFatal mean the input cannot be read any further; other errors only affect their row.
+type RowReader interface {
+	Next() (Row, error)
+}
+
+// ErrFatal is wrapped by the errors of rows after which nothing more can be read, such as a failing
+// file or a line longer than MaxLineSize. Readers never return io.EOF after it, so callers must stop.
+var ErrFatal = errors.New("input cannot be read further")
+
+// MaxLineSize is the longest JSONL line that is read
+const MaxLineSize = 16 * 1024 * 1024
+
+// NewReader creates a row reader for the given format ("csv" or "jsonl").
+// If format is empty it is inferred from the file name.
+func NewReader(r io.Reader, fileName string, format string) (RowReader, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(fileName)) {
+		case ".csv":
+			format = "csv"
+		case ".jsonl", ".ndjson":
+			format = "jsonl"
+		default:
+			return nil, fmt.Errorf("cannot infer the format of %s, expected a .csv or .jsonl file", fileName)
+		}
+	}
+
+	switch strings.ToLower(format) {
+	case "csv":
+		return newCSVReader(r)
+	case "jsonl", "ndjson":
+		return newJSONLReader(r), nil
+	default:
+		return nil, fmt.Errorf("unsupported input format: %s", format)
+	}
+}
+
+// csvReader reads a CSV file whose first row holds the column names
+type csvReader struct {
+	reader *csv.Reader
+	header []string
+	err    error // The fatal error, returned again by later calls
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("CSV file is empty, expected a header row")
+		}
+		return nil, fmt.Errorf("error reading CSV header: %v", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff"))
+	}
+
+	return &csvReader{reader: reader, header: header}, nil
+}
+
+func (c *csvReader) Next() (Row, error) {
+	if c.err != nil {
+		line, _ := c.reader.FieldPos(0)
+		return Row{Line: line}, c.err
+	}
+	record, err := c.reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return Row{}, io.EOF
+		}
+		line, _ := c.reader.FieldPos(0)
+		// Malformed rows are skipped over by the CSV reader; anything else ends the input
+		var parseErr *csv.ParseError
+		if !errors.As(err, &parseErr) {
+			c.err = fmt.Errorf("error reading CSV row: %v: %w", err, ErrFatal)
+			return Row{Line: line}, c.err
+		}
+		return Row{Line: line}, fmt.Errorf("error reading CSV row: %v", err)
+	}
+
+	line, _ := c.reader.FieldPos(0)
+	if len(record) != len(c.header) {
+		return Row{Line: line}, fmt.Errorf("row has %d columns, header has %d", len(record), len(c.header))
+	}
+
+	fields := make(map[string]string, len(c.header))
+	for i, column := range c.header {
+		fields[column] = record[i]
+	}
+
+	return Row{Line: line, Fields: fields}, nil
+}
+
+// jsonlReader reads one JSON object per line.
+// A nested "metadata" object is flattened into metadata.<key> columns, and
+// values that are not strings are kept as their JSON text.
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	line    int
+	err     error // The fatal error, returned again by later calls
+}
+
+func newJSONLReader(r io.Reader) *jsonlReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), MaxLineSize)
+	return &jsonlReader{scanner: scanner}
+}
+
+func (j *jsonlReader) Next() (Row, error) {
+	// After an error the scanner hands out what is left of its buffer, so it is not asked again
+	if j.err != nil {
+		return Row{Line: j.line + 1}, j.err
+	}
+	for j.scanner.Scan() {
+		j.line++
+		text := strings.TrimSpace(j.scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(text), &object); err != nil {
+			return Row{Line: j.line}, fmt.Errorf("invalid JSON object: %v", err)
+		}
+
+		fields := make(map[string]string, len(object))
+		for key, value := range object {
+			if nested, ok := value.(map[string]interface{}); ok && key == "metadata" {
+				for metadataKey, metadataValue := range nested {
+					fields[MetadataPrefix+metadataKey] = jsonValueToString(metadataValue)
+				}
+				continue
+			}
+			fields[key] = jsonValueToString(value)
+		}
+
+		return Row{Line: j.line, Fields: fields}, nil
+	}
+
+	if err := j.scanner.Err(); err != nil {
+		j.err = fmt.Errorf("error reading JSONL file: %v: %w", err, ErrFatal)
+		return Row{Line: j.line + 1}, j.err
+	}
+	return Row{}, io.EOF
+}
+
+// jsonValueToString returns strings as is and any other JSON value as its encoded text
+func jsonValueToString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(encoded)
+	}
+}
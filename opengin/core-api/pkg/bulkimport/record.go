@@ -0,0 +1,315 @@
+package bulkimport
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"lk/datafoundation/core-api/commons"
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Logical fields of a node row
+const (
+	FieldId         = "id"
+	FieldKindMajor  = "kind_major"
+	FieldKindMinor  = "kind_minor"
+	FieldName       = "name"
+	FieldCreated    = "created"
+	FieldTerminated = "terminated"
+	FieldAttributes = "attributes"
+)
+
+// Logical fields of an edge row
+const (
+	FieldSource    = "source"
+	FieldTarget    = "target"
+	FieldStartTime = "start_time"
+	FieldEndTime   = "end_time"
+)
+
+// MetadataPrefix marks the columns that are stored as entity metadata
+const MetadataPrefix = "metadata."
+
+// Mapping maps logical fields to the column names used in an input file
+type Mapping map[string]string
+
+// DefaultNodeMapping returns the mapping used when node columns are named after the logical fields
+func DefaultNodeMapping() Mapping {
+	return Mapping{
+		FieldId:         FieldId,
+		FieldKindMajor:  FieldKindMajor,
+		FieldKindMinor:  FieldKindMinor,
+		FieldName:       FieldName,
+		FieldCreated:    FieldCreated,
+		FieldTerminated: FieldTerminated,
+		FieldAttributes: FieldAttributes,
+	}
+}
+
+// DefaultEdgeMapping returns the mapping used when edge columns are named after the logical fields
+func DefaultEdgeMapping() Mapping {
+	return Mapping{
+		FieldId:        FieldId,
+		FieldSource:    FieldSource,
+		FieldTarget:    FieldTarget,
+		FieldName:      FieldName,
+		FieldStartTime: FieldStartTime,
+		FieldEndTime:   FieldEndTime,
+	}
+}
+
+// ParseMapping applies overrides of the form "field=column,field=column" to a default mapping
+func ParseMapping(spec string, defaults Mapping) (Mapping, error) {
+	mapping := make(Mapping, len(defaults))
+	for field, column := range defaults {
+		mapping[field] = column
+	}
+
+	if strings.TrimSpace(spec) == "" {
+		return mapping, nil
+	}
+
+	for _, pair := range strings.Split(spec, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+			return nil, fmt.Errorf("invalid column mapping %q, expected field=column", pair)
+		}
+		field := strings.TrimSpace(parts[0])
+		if _, ok := defaults[field]; !ok {
+			return nil, fmt.Errorf("unknown field %q in column mapping", field)
+		}
+		mapping[field] = strings.TrimSpace(parts[1])
+	}
+
+	return mapping, nil
+}
+
+// NodeRecord is a validated entity row
+type NodeRecord struct {
+	Id         string
+	KindMajor  string
+	KindMinor  string
+	Name       string
+	Created    string
+	Terminated string
+	Metadata   map[string]string
+	Attributes map[string]*structpb.Struct
+}
+
+// EdgeRecord is a validated relationship row
+type EdgeRecord struct {
+	Id        string
+	SourceId  string
+	TargetId  string
+	Name      string
+	StartTime string
+	EndTime   string
+}
+
+// ParseNode maps and validates a node row
+func ParseNode(row Row, mapping Mapping) (*NodeRecord, error) {
+	record := &NodeRecord{
+		Id:         row.Value(mapping[FieldId]),
+		KindMajor:  row.Value(mapping[FieldKindMajor]),
+		KindMinor:  row.Value(mapping[FieldKindMinor]),
+		Name:       row.Value(mapping[FieldName]),
+		Created:    row.Value(mapping[FieldCreated]),
+		Terminated: row.Value(mapping[FieldTerminated]),
+	}
+
+	for column, value := range row.Fields {
+		if strings.HasPrefix(column, MetadataPrefix) && value != "" {
+			if record.Metadata == nil {
+				record.Metadata = make(map[string]string)
+			}
+			record.Metadata[strings.TrimPrefix(column, MetadataPrefix)] = value
+		}
+	}
+
+	var problems []string
+	if record.Id == "" {
+		problems = append(problems, "missing id")
+	}
+	if record.KindMajor == "" {
+		problems = append(problems, "missing kind major")
+	} else if !commons.IsGraphIdentifier(record.KindMajor) {
+		problems = append(problems, fmt.Sprintf("invalid kind major %q", record.KindMajor))
+	}
+	if record.KindMinor == "" {
+		problems = append(problems, "missing kind minor")
+	}
+	if record.Name == "" {
+		problems = append(problems, "missing name")
+	}
+	problems = append(problems, validateTimeWindow(record.Created, record.Terminated, "created", "terminated")...)
+
+	if attributes := row.Value(mapping[FieldAttributes]); attributes != "" {
+		parsed, err := parseAttributes(attributes)
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+		record.Attributes = parsed
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+
+	return record, nil
+}
+
+// parseAttributes parses a JSON object mapping attribute names to attribute values.
+// Each value must itself be an object, such as {"columns": [...], "rows": [[...]]} for tabular data.
+func parseAttributes(text string) (map[string]*structpb.Struct, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &raw); err != nil {
+		return nil, fmt.Errorf("invalid attributes, expected a JSON object: %v", err)
+	}
+
+	attributes := make(map[string]*structpb.Struct, len(raw))
+	for name, value := range raw {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("attribute %q must be a JSON object", name)
+		}
+		converted, err := structpb.NewStruct(object)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attribute %q: %v", name, err)
+		}
+		attributes[name] = converted
+	}
+	return attributes, nil
+}
+
+// ParseEdge maps and validates an edge row.
+// When the file has no relationship ids, a stable id is derived from the row so re-runs do not duplicate it.
+func ParseEdge(row Row, mapping Mapping) (*EdgeRecord, error) {
+	record := &EdgeRecord{
+		Id:        row.Value(mapping[FieldId]),
+		SourceId:  row.Value(mapping[FieldSource]),
+		TargetId:  row.Value(mapping[FieldTarget]),
+		Name:      row.Value(mapping[FieldName]),
+		StartTime: row.Value(mapping[FieldStartTime]),
+		EndTime:   row.Value(mapping[FieldEndTime]),
+	}
+
+	var problems []string
+	if record.SourceId == "" {
+		problems = append(problems, "missing source")
+	}
+	if record.TargetId == "" {
+		problems = append(problems, "missing target")
+	}
+	if record.Name == "" {
+		problems = append(problems, "missing name")
+	} else if !commons.IsGraphIdentifier(record.Name) {
+		problems = append(problems, fmt.Sprintf("invalid relationship name %q", record.Name))
+	}
+	problems = append(problems, validateTimeWindow(record.StartTime, record.EndTime, "start_time", "end_time")...)
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+
+	if record.Id == "" {
+		record.Id = strings.Join([]string{record.SourceId, record.Name, record.TargetId, record.StartTime}, "_")
+	}
+
+	return record, nil
+}
+
+// validateTimeWindow checks that start is a valid RFC3339 time and end, if set, is not before it
+func validateTimeWindow(start string, end string, startField string, endField string) []string {
+	if start == "" {
+		return []string{"missing " + startField}
+	}
+
+	startTime, err := time.Parse(time.RFC3339, start)
+	if err != nil {
+		return []string{fmt.Sprintf("invalid %s %q, expected RFC3339", startField, start)}
+	}
+
+	if end == "" {
+		return nil
+	}
+
+	endTime, err := time.Parse(time.RFC3339, end)
+	if err != nil {
+		return []string{fmt.Sprintf("invalid %s %q, expected RFC3339", endField, end)}
+	}
+	if endTime.Before(startTime) {
+		return []string{fmt.Sprintf("%s %s is before %s %s", endField, end, startField, start)}
+	}
+
+	return nil
+}
+
+// ToEntity converts a node record into the entity accepted by the repositories
+func (n *NodeRecord) ToEntity() *pb.Entity {
+	entity := &pb.Entity{
+		Id: n.Id,
+		Kind: &pb.Kind{
+			Major: n.KindMajor,
+			Minor: n.KindMinor,
+		},
+		Name:       commons.CreateTimeBasedValue(n.Created, n.Terminated, n.Name),
+		Created:    n.Created,
+		Terminated: n.Terminated,
+	}
+
+	// Attribute values start with the entity, like a value sent to CreateEntity without its own time window
+	if len(n.Attributes) > 0 {
+		entity.Attributes = make(map[string]*pb.TimeBasedValueList, len(n.Attributes))
+		for name, value := range n.Attributes {
+			anyValue, err := anypb.New(value)
+			if err != nil {
+				continue
+			}
+			entity.Attributes[name] = &pb.TimeBasedValueList{
+				Values: []*pb.TimeBasedValue{
+					{
+						StartTime: n.Created,
+						EndTime:   n.Terminated,
+						Value:     anyValue,
+					},
+				},
+			}
+		}
+	}
+
+	if len(n.Metadata) > 0 {
+		entity.Metadata = make(map[string]*anypb.Any, len(n.Metadata))
+		keys := make([]string, 0, len(n.Metadata))
+		for key := range n.Metadata {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			entity.Metadata[key] = commons.ConvertStringToAny(n.Metadata[key])
+		}
+	}
+
+	return entity
+}
+
+// ToEntity converts an edge record into an entity holding a single relationship, as used by CreateEntity
+func (e *EdgeRecord) ToEntity() *pb.Entity {
+	return &pb.Entity{
+		Id: e.SourceId,
+		Relationships: map[string]*pb.Relationship{
+			e.Id: {
+				Id:              e.Id,
+				Name:            e.Name,
+				RelatedEntityId: e.TargetId,
+				StartTime:       e.StartTime,
+				EndTime:         e.EndTime,
+			},
+		},
+	}
+}
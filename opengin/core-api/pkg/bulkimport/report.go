@@ -0,0 +1,57 @@
+package bulkimport
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Rejection describes a row that was not imported
+type Rejection struct {
+	File   string
+	Line   int
+	Id     string
+	Reason string
+}
+
+// RejectReport writes rejected rows as CSV with the columns file, line, id and reason.
+// It is safe for concurrent use.
+type RejectReport struct {
+	mu     sync.Mutex
+	writer *csv.Writer
+	count  int
+}
+
+// NewRejectReport creates a report writing to w.
+// The header row is written only when writeHeader is set, so a report can be appended to.
+func NewRejectReport(w io.Writer, writeHeader bool) (*RejectReport, error) {
+	writer := csv.NewWriter(w)
+	if writeHeader {
+		if err := writer.Write([]string{"file", "line", "id", "reason"}); err != nil {
+			return nil, fmt.Errorf("error writing reject report header: %v", err)
+		}
+		writer.Flush()
+	}
+	return &RejectReport{writer: writer}, writer.Error()
+}
+
+// Add records a rejected row and flushes it so the report survives a crash
+func (r *RejectReport) Add(rejection Rejection) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.count++
+	if err := r.writer.Write([]string{rejection.File, fmt.Sprintf("%d", rejection.Line), rejection.Id, rejection.Reason}); err != nil {
+		return fmt.Errorf("error writing reject report: %v", err)
+	}
+	r.writer.Flush()
+	return r.writer.Error()
+}
+
+// Count returns the number of rejected rows recorded so far
+func (r *RejectReport) Count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.count
+}
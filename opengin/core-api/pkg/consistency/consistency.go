@@ -0,0 +1,212 @@
+// Package consistency finds data that one of the three stores has lost track of.
+//
+// Writes to Neo4j, MongoDB and PostgreSQL are not transactional across the stores, so a failed
+// or interrupted write can leave, for example, a Dataset node without its metadata document.
+// The caller collects an Inventory of the ids held by every store and Detect cross-references
+// them using the ids the engine derives from an entity id and an attribute name.
+package consistency
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Category names a kind of cross-store inconsistency
+type Category string
+
+const (
+	// DatasetMissingMetadata is a Dataset node in Neo4j without its metadata document in MongoDB
+	DatasetMissingMetadata Category = "dataset_missing_metadata"
+	// AttributeRowMissingDataset is an entity_attributes row in PostgreSQL without a Dataset node in Neo4j
+	AttributeRowMissingDataset Category = "attribute_row_missing_dataset"
+	// OrphanAttributeTable is an attr_* table in PostgreSQL that no entity_attributes row refers to
+	OrphanAttributeTable Category = "orphan_attribute_table"
+	// OrphanDocument is a MongoDB document whose entity or Dataset node no longer exists in Neo4j
+	OrphanDocument Category = "orphan_document"
+)
+
+// Categories lists every category in the order issues are reported
+var Categories = []Category{DatasetMissingMetadata, AttributeRowMissingDataset, OrphanAttributeTable, OrphanDocument}
+
+// ParseCategory validates a category name
+func ParseCategory(name string) (Category, error) {
+	for _, category := range Categories {
+		if string(category) == strings.ToLower(strings.TrimSpace(name)) {
+			return category, nil
+		}
+	}
+	return "", fmt.Errorf("unknown consistency category %q", name)
+}
+
+// Action records what was done about an issue
+type Action string
+
+const (
+	ActionNone        Action = ""
+	ActionRepaired    Action = "repaired"
+	ActionQuarantined Action = "quarantined"
+	ActionSkipped     Action = "skipped"
+	ActionFailed      Action = "failed"
+)
+
+// Dataset is a Dataset node of the attribute lookup graph
+type Dataset struct {
+	ID          string // Attribute id, also the id of the metadata document
+	Name        string // Attribute name
+	StorageType string // Minor kind of the node
+	EntityID    string // Owner found through IS_ATTRIBUTE, empty if the node has no owner
+}
+
+// AttributeRow is a row of the entity_attributes table
+type AttributeRow struct {
+	EntityID      string
+	AttributeName string
+	AttributeID   string // Id the engine derives from EntityID and AttributeName
+	TableName     string
+}
+
+// Inventory holds the ids found in every store
+type Inventory struct {
+	NodeIDs       map[string]bool // Every node in Neo4j, entities and Dataset nodes alike
+	Datasets      []Dataset
+	DocumentIDs   map[string]bool // Every document id in the MongoDB collection
+	AttributeRows []AttributeRow
+	Tables        []string // Every attr_* table in PostgreSQL
+}
+
+// NewInventory creates an empty inventory
+func NewInventory() *Inventory {
+	return &Inventory{
+		NodeIDs:     make(map[string]bool),
+		DocumentIDs: make(map[string]bool),
+	}
+}
+
+// Issue is a single inconsistency
+type Issue struct {
+	Category      Category `json:"category"`
+	ID            string   `json:"id"` // Id of the dangling record: dataset id, attribute id, table name or document id
+	EntityID      string   `json:"entityId,omitempty"`
+	AttributeName string   `json:"attributeName,omitempty"`
+	StorageType   string   `json:"storageType,omitempty"`
+	TableName     string   `json:"tableName,omitempty"`
+	Detail        string   `json:"detail,omitempty"`
+	Action        Action   `json:"action,omitempty"`
+	Error         string   `json:"error,omitempty"`
+}
+
+// Detect cross-references the stores and returns the issues ordered by category and id
+func Detect(inventory *Inventory) []Issue {
+	var issues []Issue
+
+	datasetIDs := make(map[string]bool, len(inventory.Datasets))
+	for _, dataset := range inventory.Datasets {
+		datasetIDs[dataset.ID] = true
+		if inventory.DocumentIDs[dataset.ID] {
+			continue
+		}
+		issue := Issue{
+			Category:      DatasetMissingMetadata,
+			ID:            dataset.ID,
+			EntityID:      dataset.EntityID,
+			AttributeName: dataset.Name,
+			StorageType:   dataset.StorageType,
+		}
+		if dataset.EntityID == "" {
+			issue.Detail = "dataset node has no IS_ATTRIBUTE owner"
+		}
+		issues = append(issues, issue)
+	}
+
+	tables := make(map[string]bool, len(inventory.Tables))
+	for _, table := range inventory.Tables {
+		tables[table] = true
+	}
+
+	ownedTables := make(map[string]bool, len(inventory.AttributeRows))
+	for _, row := range inventory.AttributeRows {
+		ownedTables[row.TableName] = true
+		if datasetIDs[row.AttributeID] {
+			continue
+		}
+		issue := Issue{
+			Category:      AttributeRowMissingDataset,
+			ID:            row.AttributeID,
+			EntityID:      row.EntityID,
+			AttributeName: row.AttributeName,
+			TableName:     row.TableName,
+		}
+		switch {
+		case !inventory.NodeIDs[row.EntityID]:
+			issue.Detail = "owning entity does not exist in Neo4j"
+		case !tables[row.TableName]:
+			issue.Detail = "attribute table does not exist"
+		}
+		issues = append(issues, issue)
+	}
+
+	for _, table := range inventory.Tables {
+		if !ownedTables[table] {
+			issues = append(issues, Issue{Category: OrphanAttributeTable, ID: table, TableName: table})
+		}
+	}
+
+	for id := range inventory.DocumentIDs {
+		if !inventory.NodeIDs[id] {
+			issues = append(issues, Issue{Category: OrphanDocument, ID: id})
+		}
+	}
+
+	order := make(map[Category]int, len(Categories))
+	for i, category := range Categories {
+		order[category] = i
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Category != issues[j].Category {
+			return order[issues[i].Category] < order[issues[j].Category]
+		}
+		return issues[i].ID < issues[j].ID
+	})
+
+	return issues
+}
+
+// Report is the outcome of a consistency check
+type Report struct {
+	StartedAt  time.Time      `json:"startedAt"`
+	FinishedAt time.Time      `json:"finishedAt"`
+	Scanned    map[string]int `json:"scanned"` // Number of records read per kind
+	Issues     []Issue        `json:"issues"`
+}
+
+// Counts returns the number of issues per category, including empty categories
+func (r *Report) Counts() map[Category]int {
+	counts := make(map[Category]int, len(Categories))
+	for _, category := range Categories {
+		counts[category] = 0
+	}
+	for _, issue := range r.Issues {
+		counts[issue.Category]++
+	}
+	return counts
+}
+
+// Filter keeps only the issues of the given categories. An empty list keeps every issue.
+func (r *Report) Filter(categories []Category) {
+	if len(categories) == 0 {
+		return
+	}
+	keep := make(map[Category]bool, len(categories))
+	for _, category := range categories {
+		keep[category] = true
+	}
+	filtered := r.Issues[:0]
+	for _, issue := range r.Issues {
+		if keep[issue.Category] {
+			filtered = append(filtered, issue)
+		}
+	}
+	r.Issues = filtered
+}
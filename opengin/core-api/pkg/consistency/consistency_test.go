@@ -0,0 +1,103 @@
+package consistency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// driftedInventory returns stores holding one healthy attribute and one issue of every category
+func driftedInventory() *Inventory {
+	inventory := NewInventory()
+
+	// Healthy: entity e1 with tabular attribute budget
+	inventory.NodeIDs["e1"] = true
+	inventory.NodeIDs["e1_attr_budget"] = true
+	inventory.Datasets = append(inventory.Datasets, Dataset{ID: "e1_attr_budget", Name: "budget", StorageType: "tabular", EntityID: "e1"})
+	inventory.DocumentIDs["e1"] = true
+	inventory.DocumentIDs["e1_attr_budget"] = true
+	inventory.AttributeRows = append(inventory.AttributeRows, AttributeRow{EntityID: "e1", AttributeName: "budget", AttributeID: "e1_attr_budget", TableName: "attr_e1_budget"})
+	inventory.Tables = append(inventory.Tables, "attr_e1_budget")
+
+	// Dataset node whose metadata document was never written
+	inventory.NodeIDs["e1_attr_staff"] = true
+	inventory.Datasets = append(inventory.Datasets, Dataset{ID: "e1_attr_staff", Name: "staff", StorageType: "map", EntityID: "e1"})
+
+	// Tabular attribute whose lookup graph write failed
+	inventory.AttributeRows = append(inventory.AttributeRows, AttributeRow{EntityID: "e1", AttributeName: "grants", AttributeID: "e1_attr_grants", TableName: "attr_e1_grants"})
+	inventory.Tables = append(inventory.Tables, "attr_e1_grants")
+
+	// Table left behind without bookkeeping
+	inventory.Tables = append(inventory.Tables, "attr_e9_old")
+
+	// Metadata of an entity deleted from Neo4j
+	inventory.DocumentIDs["e2"] = true
+
+	return inventory
+}
+
+func TestDetect(t *testing.T) {
+	issues := Detect(driftedInventory())
+
+	assert.Equal(t, []Issue{
+		{Category: DatasetMissingMetadata, ID: "e1_attr_staff", EntityID: "e1", AttributeName: "staff", StorageType: "map"},
+		{Category: AttributeRowMissingDataset, ID: "e1_attr_grants", EntityID: "e1", AttributeName: "grants", TableName: "attr_e1_grants"},
+		{Category: OrphanAttributeTable, ID: "attr_e9_old", TableName: "attr_e9_old"},
+		{Category: OrphanDocument, ID: "e2"},
+	}, issues)
+}
+
+func TestDetectConsistentStores(t *testing.T) {
+	inventory := driftedInventory()
+	inventory.DocumentIDs["e1_attr_staff"] = true
+	inventory.NodeIDs["e1_attr_grants"] = true
+	inventory.Datasets = append(inventory.Datasets, Dataset{ID: "e1_attr_grants", Name: "grants", StorageType: "tabular", EntityID: "e1"})
+	inventory.DocumentIDs["e1_attr_grants"] = true
+	inventory.Tables = inventory.Tables[:2]
+	delete(inventory.DocumentIDs, "e2")
+
+	assert.Empty(t, Detect(inventory))
+}
+
+func TestDetectDetails(t *testing.T) {
+	inventory := NewInventory()
+	inventory.NodeIDs["e1_attr_staff"] = true
+	inventory.Datasets = []Dataset{{ID: "e1_attr_staff", Name: "staff", StorageType: "map"}}
+	inventory.AttributeRows = []AttributeRow{
+		{EntityID: "e3", AttributeName: "budget", AttributeID: "e3_attr_budget", TableName: "attr_e3_budget"},
+	}
+	inventory.NodeIDs["e4"] = true
+	inventory.AttributeRows = append(inventory.AttributeRows, AttributeRow{EntityID: "e4", AttributeName: "budget", AttributeID: "e4_attr_budget", TableName: "attr_e4_budget"})
+
+	issues := Detect(inventory)
+	assert.Len(t, issues, 3)
+	assert.Equal(t, "dataset node has no IS_ATTRIBUTE owner", issues[0].Detail)
+	assert.Equal(t, "owning entity does not exist in Neo4j", issues[1].Detail)
+	assert.Equal(t, "attribute table does not exist", issues[2].Detail)
+}
+
+func TestReportCountsAndFilter(t *testing.T) {
+	report := &Report{Issues: Detect(driftedInventory())}
+
+	counts := report.Counts()
+	assert.Len(t, counts, len(Categories))
+	for _, category := range Categories {
+		assert.Equal(t, 1, counts[category], category)
+	}
+
+	report.Filter([]Category{OrphanDocument, OrphanAttributeTable})
+	assert.Len(t, report.Issues, 2)
+	assert.Equal(t, 0, report.Counts()[DatasetMissingMetadata])
+
+	report.Filter(nil)
+	assert.Len(t, report.Issues, 2)
+}
+
+func TestParseCategory(t *testing.T) {
+	category, err := ParseCategory(" Orphan_Document ")
+	assert.NoError(t, err)
+	assert.Equal(t, OrphanDocument, category)
+
+	_, err = ParseCategory("missing_everything")
+	assert.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package graphexport
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"lk/datafoundation/core-api/db/repository/neo4j/cypher"
+	"lk/datafoundation/core-api/pkg/tenant"
+)
+
+// WriteCypher serialises the subgraph as a Cypher script that recreates it.
+// Nodes and relationships are written with the same properties the Neo4j repository uses
+// (Id, Tenant, Name, MinorKind, Created and Terminated) and MERGE on Id and Tenant, so replaying
+// the script against a database that already holds some of the entities is safe. They belong to
+// the tenant of the export, whose queries see them once replayed; edit the Tenant to replay into another.
+// Entity metadata lives outside the graph, so it is written as comments only.
+func WriteCypher(w io.Writer, graph *Subgraph) error {
+	writer := bufio.NewWriter(w)
+
+	owner := graph.Tenant
+	if owner == "" {
+		owner = tenant.Default
+	}
+
+	fmt.Fprintf(writer, "// Subgraph export rooted at %s\n", cypherComment(graph.RootId))
+	fmt.Fprintf(writer, "// Tenant %s\n", cypherComment(owner))
+	if graph.ActiveAt != "" {
+		fmt.Fprintf(writer, "// Active at %s\n", cypherComment(graph.ActiveAt))
+	}
+
+	for _, entity := range graph.Entities {
+		if entity.MajorKind == "" {
+			return fmt.Errorf("entity %s has no major kind", entity.Id)
+		}
+
+		writer.WriteString("\n")
+		for _, key := range metadataKeys([]Entity{entity}) {
+			value, _ := json.Marshal(entity.Metadata[key])
+			fmt.Fprintf(writer, "// metadata %s: %s\n", cypherComment(key), cypherComment(string(value)))
+		}
+
+		fmt.Fprintf(writer, "MERGE (e:%s {Id: %s, Tenant: %s})\nSET e.Name = %s, e.MinorKind = %s",
+			cypher.Quote(entity.MajorKind),
+			cypherString(entity.Id),
+			cypherString(owner),
+			cypherString(entity.Name),
+			cypherString(entity.MinorKind),
+		)
+		if entity.Created != "" {
+			fmt.Fprintf(writer, ", e.Created = datetime(%s)", cypherString(entity.Created))
+		}
+		if entity.Terminated != "" {
+			fmt.Fprintf(writer, ", e.Terminated = datetime(%s)", cypherString(entity.Terminated))
+		}
+		writer.WriteString(";\n")
+	}
+
+	for _, rel := range graph.Relationships {
+		if rel.Name == "" {
+			return fmt.Errorf("relationship %s has no name", rel.Id)
+		}
+
+		fmt.Fprintf(writer, "\nMATCH (p {Id: %s, Tenant: %s}), (c {Id: %s, Tenant: %s})\nMERGE (p)-[r:%s {Id: %s, Tenant: %s}]->(c)",
+			cypherString(rel.SourceId),
+			cypherString(owner),
+			cypherString(rel.TargetId),
+			cypherString(owner),
+			cypher.Quote(rel.Name),
+			cypherString(rel.Id),
+			cypherString(owner),
+		)
+		var assignments []string
+		if rel.StartTime != "" {
+			assignments = append(assignments, fmt.Sprintf("r.Created = datetime(%s)", cypherString(rel.StartTime)))
+		}
+		if rel.EndTime != "" {
+			assignments = append(assignments, fmt.Sprintf("r.Terminated = datetime(%s)", cypherString(rel.EndTime)))
+		}
+		if len(assignments) > 0 {
+			fmt.Fprintf(writer, "\nSET %s", strings.Join(assignments, ", "))
+		}
+		writer.WriteString(";\n")
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("error writing Cypher script: %v", err)
+	}
+
+	return nil
+}
+
+// cypherString quotes a value as a Cypher string literal
+func cypherString(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return "'" + replacer.Replace(value) + "'"
+}
+
+// cypherComment keeps a value on a single comment line
+func cypherComment(value string) string {
+	return strings.NewReplacer("\n", `\n`, "\r", `\r`).Replace(value)
+}
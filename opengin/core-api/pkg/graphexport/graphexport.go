@@ -0,0 +1,125 @@
+package graphexport
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Format represents a supported subgraph serialisation format
+type Format string
+
+// Supported export formats
+const (
+	FormatGraphML   Format = "graphml"
+	FormatJSONGraph Format = "jgf"
+	FormatCypher    Format = "cypher"
+)
+
+// Entity is an entity node as it appears in an exported subgraph
+type Entity struct {
+	Id         string
+	MajorKind  string
+	MinorKind  string
+	Name       string
+	Created    string
+	Terminated string
+	Metadata   map[string]string
+}
+
+// Relationship is a directed relationship between two exported entities
+type Relationship struct {
+	Id        string
+	Name      string
+	SourceId  string
+	TargetId  string
+	StartTime string
+	EndTime   string
+}
+
+// Subgraph is the part of the graph reachable from a root entity
+type Subgraph struct {
+	RootId        string
+	ActiveAt      string
+	Tenant        string // The tenant the subgraph was read for, tenant.Default when empty
+	Entities      []Entity
+	Relationships []Relationship
+}
+
+// ParseFormat converts a format name into a Format, accepting a few common aliases
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "graphml", "xml":
+		return FormatGraphML, nil
+	case "jgf", "json", "jsongraph", "json-graph":
+		return FormatJSONGraph, nil
+	case "cypher", "cql":
+		return FormatCypher, nil
+	default:
+		return "", fmt.Errorf("unsupported export format: %s", name)
+	}
+}
+
+// ContentType returns the MIME type of the serialised format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatGraphML:
+		return "application/graphml+xml"
+	case FormatJSONGraph:
+		return "application/vnd.jgf+json"
+	case FormatCypher:
+		return "application/x-cypher-query"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// FileExtension returns the conventional file extension of the format, including the dot
+func (f Format) FileExtension() string {
+	switch f {
+	case FormatGraphML:
+		return ".graphml"
+	case FormatJSONGraph:
+		return ".json"
+	case FormatCypher:
+		return ".cypher"
+	default:
+		return ""
+	}
+}
+
+// Write serialises the subgraph to w in the given format
+func Write(w io.Writer, graph *Subgraph, format Format) error {
+	if graph == nil {
+		return fmt.Errorf("subgraph cannot be nil")
+	}
+
+	switch format {
+	case FormatGraphML:
+		return WriteGraphML(w, graph)
+	case FormatJSONGraph:
+		return WriteJSONGraph(w, graph)
+	case FormatCypher:
+		return WriteCypher(w, graph)
+	default:
+		return fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// metadataKeys returns the sorted union of metadata keys across all entities
+func metadataKeys(entities []Entity) []string {
+	seen := make(map[string]bool)
+	for _, entity := range entities {
+		for key := range entity.Metadata {
+			seen[key] = true
+		}
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
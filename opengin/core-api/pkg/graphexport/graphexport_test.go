@@ -0,0 +1,210 @@
+package graphexport
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sampleSubgraph returns a small subgraph with a terminated relationship and metadata
+func sampleSubgraph() *Subgraph {
+	return &Subgraph{
+		RootId:   "minister-1",
+		ActiveAt: "2024-06-01T00:00:00Z",
+		Entities: []Entity{
+			{
+				Id:        "minister-1",
+				MajorKind: "Organisation",
+				MinorKind: "Minister",
+				Name:      "Minister of Finance",
+				Created:   "2020-01-01T00:00:00Z",
+				Metadata: map[string]string{
+					"source": "gazette",
+					"note":   "contains <xml> & 'quotes'",
+				},
+			},
+			{
+				Id:         "department-1",
+				MajorKind:  "Organisation",
+				MinorKind:  "Department",
+				Name:       "Department of O'Neil",
+				Created:    "2021-01-01T00:00:00Z",
+				Terminated: "2025-01-01T00:00:00Z",
+			},
+		},
+		Relationships: []Relationship{
+			{
+				Id:        "rel-1",
+				Name:      "AS_DEPARTMENT",
+				SourceId:  "minister-1",
+				TargetId:  "department-1",
+				StartTime: "2021-01-01T00:00:00Z",
+				EndTime:   "2025-01-01T00:00:00Z",
+			},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	testCases := map[string]Format{
+		"graphml":  FormatGraphML,
+		"GraphML":  FormatGraphML,
+		"jgf":      FormatJSONGraph,
+		"json":     FormatJSONGraph,
+		" cypher ": FormatCypher,
+		"cql":      FormatCypher,
+	}
+	for name, expected := range testCases {
+		format, err := ParseFormat(name)
+		assert.NoError(t, err, "format %q", name)
+		assert.Equal(t, expected, format, "format %q", name)
+	}
+
+	_, err := ParseFormat("gexf")
+	assert.Error(t, err)
+}
+
+func TestWriteGraphML(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(&buf, sampleSubgraph(), FormatGraphML)
+	assert.NoError(t, err)
+
+	output := buf.String()
+	assert.True(t, strings.HasPrefix(output, xml.Header))
+	assert.Contains(t, output, `edgedefault="directed"`)
+	assert.Contains(t, output, `attr.name="metadata.source"`)
+	assert.Contains(t, output, `&lt;xml&gt; &amp;`)
+
+	// The document must round-trip through an XML decoder
+	var document graphMLDocument
+	err = xml.Unmarshal(buf.Bytes(), &document)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(document.Graph.Nodes))
+	assert.Equal(t, 1, len(document.Graph.Edges))
+	assert.Equal(t, "minister-1", document.Graph.Edges[0].Source)
+	assert.Equal(t, "department-1", document.Graph.Edges[0].Target)
+
+	keysById := make(map[string]graphMLKey)
+	for _, key := range document.Keys {
+		keysById[key.Id] = key
+	}
+	nodeData := make(map[string]string)
+	for _, data := range document.Graph.Nodes[0].Data {
+		nodeData[keysById[data.Key].AttrName] = data.Value
+	}
+	assert.Equal(t, "Minister of Finance", nodeData["name"])
+	assert.Equal(t, "Organisation", nodeData["kindMajor"])
+	assert.Equal(t, "gazette", nodeData["metadata.source"])
+	assert.Equal(t, "contains <xml> & 'quotes'", nodeData["metadata.note"])
+}
+
+func TestWriteJSONGraph(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(&buf, sampleSubgraph(), FormatJSONGraph)
+	assert.NoError(t, err)
+
+	var document map[string]interface{}
+	err = json.Unmarshal(buf.Bytes(), &document)
+	assert.NoError(t, err)
+
+	graph := document["graph"].(map[string]interface{})
+	assert.Equal(t, true, graph["directed"])
+	assert.Equal(t, "2024-06-01T00:00:00Z", graph["metadata"].(map[string]interface{})["activeAt"])
+
+	nodes := graph["nodes"].(map[string]interface{})
+	assert.Equal(t, 2, len(nodes))
+	minister := nodes["minister-1"].(map[string]interface{})
+	assert.Equal(t, "Minister of Finance", minister["label"])
+	ministerMetadata := minister["metadata"].(map[string]interface{})
+	assert.Equal(t, "Minister", ministerMetadata["kindMinor"])
+	assert.Equal(t, "gazette", ministerMetadata["metadata"].(map[string]interface{})["source"])
+
+	department := nodes["department-1"].(map[string]interface{})
+	assert.Equal(t, "2025-01-01T00:00:00Z", department["metadata"].(map[string]interface{})["terminated"])
+
+	edges := graph["edges"].([]interface{})
+	assert.Equal(t, 1, len(edges))
+	edge := edges[0].(map[string]interface{})
+	assert.Equal(t, "AS_DEPARTMENT", edge["relation"])
+	assert.Equal(t, "minister-1", edge["source"])
+	assert.Equal(t, "2025-01-01T00:00:00Z", edge["metadata"].(map[string]interface{})["endTime"])
+}
+
+func TestWriteCypher(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(&buf, sampleSubgraph(), FormatCypher)
+	assert.NoError(t, err)
+
+	output := buf.String()
+	assert.Contains(t, output, "MERGE (e:`Organisation` {Id: 'minister-1', Tenant: 'default'})")
+	assert.Contains(t, output, `e.Name = 'Department of O\'Neil'`)
+	assert.Contains(t, output, "e.Terminated = datetime('2025-01-01T00:00:00Z')")
+	assert.Contains(t, output, "MATCH (p {Id: 'minister-1', Tenant: 'default'}), (c {Id: 'department-1', Tenant: 'default'})")
+	assert.Contains(t, output, "MERGE (p)-[r:`AS_DEPARTMENT` {Id: 'rel-1', Tenant: 'default'}]->(c)")
+	assert.Contains(t, output, "r.Created = datetime('2021-01-01T00:00:00Z'), r.Terminated = datetime('2025-01-01T00:00:00Z')")
+	assert.Contains(t, output, `// metadata source: "gazette"`)
+
+	// Entities are written before the relationships that match on them
+	assert.Less(t, strings.Index(output, "department-1', Tenant: 'default'})\nSET"), strings.Index(output, "MATCH (p"))
+}
+
+func TestWriteCypherTenant(t *testing.T) {
+	graph := sampleSubgraph()
+	graph.Tenant = "acme"
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteCypher(&buf, graph))
+
+	// Every node and relationship is written for the tenant of the export, so its queries see them once replayed
+	output := buf.String()
+	assert.Contains(t, output, "// Tenant acme\n")
+	assert.Equal(t, len(graph.Entities), strings.Count(output, "MERGE (e:"))
+	assert.Equal(t, len(graph.Relationships), strings.Count(output, "MERGE (p)-"))
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "MERGE") || strings.HasPrefix(line, "MATCH") {
+			assert.Equal(t, strings.Count(line, "{Id: "), strings.Count(line, "Tenant: 'acme'"), line)
+		}
+	}
+	assert.NotContains(t, output, "'default'")
+}
+
+func TestWriteCypherEscapesIdentifiers(t *testing.T) {
+	graph := &Subgraph{
+		RootId: "a",
+		Entities: []Entity{
+			{Id: "a", MajorKind: "Bad`Label", Name: "line\nbreak"},
+		},
+		Relationships: []Relationship{
+			{Id: "r", Name: "REL`) DETACH DELETE (n", SourceId: "a", TargetId: "a"},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := WriteCypher(&buf, graph)
+	assert.NoError(t, err)
+
+	output := buf.String()
+	assert.Contains(t, output, "(e:`Bad``Label` {Id: 'a', Tenant: 'default'})")
+	assert.Contains(t, output, "[r:`REL``) DETACH DELETE (n` {Id: 'r', Tenant: 'default'}]")
+	assert.Contains(t, output, `e.Name = 'line\nbreak'`)
+}
+
+func TestWriteCypherRequiresKind(t *testing.T) {
+	graph := &Subgraph{
+		Entities: []Entity{{Id: "a"}},
+	}
+
+	var buf bytes.Buffer
+	err := WriteCypher(&buf, graph)
+	assert.Error(t, err)
+}
+
+func TestWriteUnsupportedFormat(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Error(t, Write(&buf, sampleSubgraph(), Format("gexf")))
+	assert.Error(t, Write(&buf, nil, FormatGraphML))
+}
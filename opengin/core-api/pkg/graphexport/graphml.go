@@ -0,0 +1,133 @@
+package graphexport
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+const graphMLNamespace = "http://graphml.graphdrawing.org/xmlns"
+
+type graphMLDocument struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	Id       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	Id          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Data        []graphMLData `xml:"data"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	Id   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Id     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML serialises the subgraph as a directed GraphML document.
+// Entity metadata is written as one string attribute per metadata key, named metadata.<key>.
+func WriteGraphML(w io.Writer, graph *Subgraph) error {
+	keys := []graphMLKey{
+		{Id: "g_root", For: "graph", AttrName: "rootEntityId", AttrType: "string"},
+		{Id: "g_active_at", For: "graph", AttrName: "activeAt", AttrType: "string"},
+		{Id: "n_name", For: "node", AttrName: "name", AttrType: "string"},
+		{Id: "n_kind_major", For: "node", AttrName: "kindMajor", AttrType: "string"},
+		{Id: "n_kind_minor", For: "node", AttrName: "kindMinor", AttrType: "string"},
+		{Id: "n_created", For: "node", AttrName: "created", AttrType: "string"},
+		{Id: "n_terminated", For: "node", AttrName: "terminated", AttrType: "string"},
+		{Id: "e_name", For: "edge", AttrName: "name", AttrType: "string"},
+		{Id: "e_start_time", For: "edge", AttrName: "startTime", AttrType: "string"},
+		{Id: "e_end_time", For: "edge", AttrName: "endTime", AttrType: "string"},
+	}
+
+	// Metadata keys are free-form, so they get generated key ids
+	metadataKeyIds := make(map[string]string)
+	for i, key := range metadataKeys(graph.Entities) {
+		id := fmt.Sprintf("n_metadata_%d", i)
+		metadataKeyIds[key] = id
+		keys = append(keys, graphMLKey{Id: id, For: "node", AttrName: "metadata." + key, AttrType: "string"})
+	}
+
+	document := graphMLDocument{
+		Xmlns: graphMLNamespace,
+		Keys:  keys,
+		Graph: graphMLGraph{
+			Id:          "G",
+			EdgeDefault: "directed",
+			Data:        optionalData(nil, "g_root", graph.RootId),
+		},
+	}
+	document.Graph.Data = optionalData(document.Graph.Data, "g_active_at", graph.ActiveAt)
+
+	for _, entity := range graph.Entities {
+		var data []graphMLData
+		data = optionalData(data, "n_name", entity.Name)
+		data = optionalData(data, "n_kind_major", entity.MajorKind)
+		data = optionalData(data, "n_kind_minor", entity.MinorKind)
+		data = optionalData(data, "n_created", entity.Created)
+		data = optionalData(data, "n_terminated", entity.Terminated)
+		for _, key := range metadataKeys([]Entity{entity}) {
+			data = append(data, graphMLData{Key: metadataKeyIds[key], Value: entity.Metadata[key]})
+		}
+		document.Graph.Nodes = append(document.Graph.Nodes, graphMLNode{Id: entity.Id, Data: data})
+	}
+
+	for _, rel := range graph.Relationships {
+		var data []graphMLData
+		data = optionalData(data, "e_name", rel.Name)
+		data = optionalData(data, "e_start_time", rel.StartTime)
+		data = optionalData(data, "e_end_time", rel.EndTime)
+		document.Graph.Edges = append(document.Graph.Edges, graphMLEdge{
+			Id:     rel.Id,
+			Source: rel.SourceId,
+			Target: rel.TargetId,
+			Data:   data,
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("error writing GraphML header: %v", err)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return fmt.Errorf("error encoding GraphML: %v", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("error writing GraphML: %v", err)
+	}
+
+	return nil
+}
+
+// optionalData appends a data element only when the value is set
+func optionalData(data []graphMLData, key string, value string) []graphMLData {
+	if value == "" {
+		return data
+	}
+	return append(data, graphMLData{Key: key, Value: value})
+}
@@ -0,0 +1,107 @@
+package graphexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const jsonGraphSchema = "https://jsongraphformat.info/v2.1/json-graph-schema.json"
+
+type jsonGraphDocument struct {
+	Schema string    `json:"$schema"`
+	Graph  jsonGraph `json:"graph"`
+}
+
+type jsonGraph struct {
+	Id       string                   `json:"id,omitempty"`
+	Directed bool                     `json:"directed"`
+	Metadata map[string]string        `json:"metadata,omitempty"`
+	Nodes    map[string]jsonGraphNode `json:"nodes"`
+	Edges    []jsonGraphEdge          `json:"edges"`
+}
+
+type jsonGraphNode struct {
+	Label    string                 `json:"label,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type jsonGraphEdge struct {
+	Id       string            `json:"id,omitempty"`
+	Source   string            `json:"source"`
+	Target   string            `json:"target"`
+	Relation string            `json:"relation,omitempty"`
+	Directed bool              `json:"directed"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// WriteJSONGraph serialises the subgraph in JSON Graph Format (v2).
+// Kind, time window and entity metadata are carried in the node and edge metadata objects.
+func WriteJSONGraph(w io.Writer, graph *Subgraph) error {
+	document := jsonGraphDocument{
+		Schema: jsonGraphSchema,
+		Graph: jsonGraph{
+			Id:       graph.RootId,
+			Directed: true,
+			Metadata: nonEmpty(map[string]string{
+				"rootEntityId": graph.RootId,
+				"activeAt":     graph.ActiveAt,
+			}),
+			Nodes: make(map[string]jsonGraphNode, len(graph.Entities)),
+			Edges: make([]jsonGraphEdge, 0, len(graph.Relationships)),
+		},
+	}
+
+	for _, entity := range graph.Entities {
+		metadata := make(map[string]interface{})
+		for key, value := range nonEmpty(map[string]string{
+			"kindMajor":  entity.MajorKind,
+			"kindMinor":  entity.MinorKind,
+			"created":    entity.Created,
+			"terminated": entity.Terminated,
+		}) {
+			metadata[key] = value
+		}
+		if len(entity.Metadata) > 0 {
+			metadata["metadata"] = entity.Metadata
+		}
+
+		document.Graph.Nodes[entity.Id] = jsonGraphNode{
+			Label:    entity.Name,
+			Metadata: metadata,
+		}
+	}
+
+	for _, rel := range graph.Relationships {
+		document.Graph.Edges = append(document.Graph.Edges, jsonGraphEdge{
+			Id:       rel.Id,
+			Source:   rel.SourceId,
+			Target:   rel.TargetId,
+			Relation: rel.Name,
+			Directed: true,
+			Metadata: nonEmpty(map[string]string{
+				"startTime": rel.StartTime,
+				"endTime":   rel.EndTime,
+			}),
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return fmt.Errorf("error encoding JSON graph: %v", err)
+	}
+
+	return nil
+}
+
+// nonEmpty drops the entries with empty values
+func nonEmpty(values map[string]string) map[string]string {
+	result := make(map[string]string, len(values))
+	for key, value := range values {
+		if value != "" {
+			result[key] = value
+		}
+	}
+	return result
+}
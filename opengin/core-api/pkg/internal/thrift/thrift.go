@@ -0,0 +1,333 @@
+// Package thrift encodes and decodes structs in the Thrift compact protocol, the encoding of the
+// page headers and file metadata of Parquet files (parquet.thrift of the Parquet format).
+//
+// Struct builds a struct field by field and Reader decodes one into Fields, a map of field id to
+// value, so callers name the fields of parquet.thrift by id rather than through generated types.
+package thrift
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Compact protocol types
+const (
+	TypeTrue   = 1
+	TypeFalse  = 2
+	TypeByte   = 3
+	TypeI16    = 4
+	TypeI32    = 5
+	TypeI64    = 6
+	TypeDouble = 7
+	TypeBinary = 8
+	TypeList   = 9
+	TypeSet    = 10
+	TypeMap    = 11
+	TypeStruct = 12
+)
+
+// Struct encodes a struct. Fields must be added in increasing id order.
+type Struct struct {
+	buf  []byte
+	last int16
+}
+
+func (s *Struct) header(id int16, fieldType byte) {
+	if delta := id - s.last; delta > 0 && delta <= 15 {
+		s.buf = append(s.buf, byte(delta)<<4|fieldType)
+	} else {
+		s.buf = append(s.buf, fieldType)
+		s.buf = binary.AppendVarint(s.buf, int64(id))
+	}
+	s.last = id
+}
+
+// Bool adds a boolean field
+func (s *Struct) Bool(id int16, v bool) *Struct {
+	if v {
+		s.header(id, TypeTrue)
+	} else {
+		s.header(id, TypeFalse)
+	}
+	return s
+}
+
+// I32 adds an i32 field
+func (s *Struct) I32(id int16, v int32) *Struct {
+	s.header(id, TypeI32)
+	s.buf = binary.AppendVarint(s.buf, int64(v))
+	return s
+}
+
+// I64 adds an i64 field
+func (s *Struct) I64(id int16, v int64) *Struct {
+	s.header(id, TypeI64)
+	s.buf = binary.AppendVarint(s.buf, v)
+	return s
+}
+
+// String adds a string, a binary field
+func (s *Struct) String(id int16, v string) *Struct {
+	s.header(id, TypeBinary)
+	s.buf = binary.AppendUvarint(s.buf, uint64(len(v)))
+	s.buf = append(s.buf, v...)
+	return s
+}
+
+// Struct adds a struct field
+func (s *Struct) Struct(id int16, v *Struct) *Struct {
+	s.header(id, TypeStruct)
+	s.buf = append(s.buf, v.Encode()...)
+	return s
+}
+
+func (s *Struct) listHeader(id int16, elementType byte, n int) {
+	s.header(id, TypeList)
+	if n < 15 {
+		s.buf = append(s.buf, byte(n)<<4|elementType)
+	} else {
+		s.buf = append(s.buf, 0xF0|elementType)
+		s.buf = binary.AppendUvarint(s.buf, uint64(n))
+	}
+}
+
+// I32s adds a list of i32
+func (s *Struct) I32s(id int16, values ...int32) *Struct {
+	s.listHeader(id, TypeI32, len(values))
+	for _, v := range values {
+		s.buf = binary.AppendVarint(s.buf, int64(v))
+	}
+	return s
+}
+
+// Strings adds a list of strings
+func (s *Struct) Strings(id int16, values ...string) *Struct {
+	s.listHeader(id, TypeBinary, len(values))
+	for _, v := range values {
+		s.buf = binary.AppendUvarint(s.buf, uint64(len(v)))
+		s.buf = append(s.buf, v...)
+	}
+	return s
+}
+
+// Structs adds a list of structs
+func (s *Struct) Structs(id int16, values ...*Struct) *Struct {
+	s.listHeader(id, TypeStruct, len(values))
+	for _, v := range values {
+		s.buf = append(s.buf, v.Encode()...)
+	}
+	return s
+}
+
+// Encode returns the fields followed by the stop field
+func (s *Struct) Encode() []byte {
+	return append(append([]byte(nil), s.buf...), 0)
+}
+
+// Fields is a decoded struct: integers as int64, binary as []byte, lists as []interface{}
+// and structs as Fields, by field id
+type Fields map[int16]interface{}
+
+// Has reports whether the struct has the field
+func (f Fields) Has(id int16) bool {
+	_, ok := f[id]
+	return ok
+}
+
+// I64 returns an integer field, or 0
+func (f Fields) I64(id int16) int64 {
+	v, _ := f[id].(int64)
+	return v
+}
+
+// Bool returns a boolean field, or false
+func (f Fields) Bool(id int16) bool {
+	v, _ := f[id].(bool)
+	return v
+}
+
+// String returns a binary field as a string, or ""
+func (f Fields) String(id int16) string {
+	v, _ := f[id].([]byte)
+	return string(v)
+}
+
+// List returns a list or set field, or nil
+func (f Fields) List(id int16) []interface{} {
+	v, _ := f[id].([]interface{})
+	return v
+}
+
+// Struct returns a struct field, or nil
+func (f Fields) Struct(id int16) Fields {
+	v, _ := f[id].(Fields)
+	return v
+}
+
+// MaxDepth is how deeply structs and lists may nest; the Parquet metadata nests a few levels
+// and a deeper file would only exhaust the stack
+const MaxDepth = 32
+
+// Reader decodes structs from a buffer
+type Reader struct {
+	buf   []byte
+	pos   int
+	depth int
+}
+
+// NewReader returns a reader of the buffer starting at pos
+func NewReader(buf []byte, pos int) *Reader {
+	return &Reader{buf: buf, pos: pos}
+}
+
+// Pos returns the position of the reader, just after what it decoded
+func (r *Reader) Pos() int {
+	return r.pos
+}
+
+func (r *Reader) byte() (byte, error) {
+	if r.pos >= len(r.buf) {
+		return 0, fmt.Errorf("unexpected end of data")
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *Reader) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(r.buf[min(r.pos, len(r.buf)):])
+	if n <= 0 {
+		return 0, fmt.Errorf("invalid varint")
+	}
+	r.pos += n
+	return v, nil
+}
+
+func (r *Reader) varint() (int64, error) {
+	v, err := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1), err
+}
+
+// ReadStruct decodes a struct. Map fields are skipped and decode to nil.
+func (r *Reader) ReadStruct() (Fields, error) {
+	if r.depth++; r.depth > MaxDepth {
+		return nil, fmt.Errorf("structs are nested more than %d deep", MaxDepth)
+	}
+	defer func() { r.depth-- }()
+	fields := Fields{}
+	var last int16
+	for {
+		header, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		if header == 0 {
+			return fields, nil
+		}
+		fieldType := header & 0x0F
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			v, err := r.varint()
+			if err != nil {
+				return nil, err
+			}
+			id = int16(v)
+		}
+		last = id
+
+		switch fieldType {
+		case TypeTrue:
+			fields[id] = true
+		case TypeFalse:
+			fields[id] = false
+		default:
+			if fields[id], err = r.readValue(fieldType); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (r *Reader) readValue(valueType byte) (interface{}, error) {
+	switch valueType {
+	case TypeTrue, TypeFalse:
+		// Booleans inside lists take a byte each
+		b, err := r.byte()
+		return b == TypeTrue, err
+	case TypeByte:
+		b, err := r.byte()
+		return int64(int8(b)), err
+	case TypeI16, TypeI32, TypeI64:
+		return r.varint()
+	case TypeDouble:
+		if r.pos+8 > len(r.buf) {
+			return nil, fmt.Errorf("unexpected end of data")
+		}
+		v := math.Float64frombits(binary.LittleEndian.Uint64(r.buf[r.pos:]))
+		r.pos += 8
+		return v, nil
+	case TypeBinary:
+		n, err := r.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		if uint64(len(r.buf)-r.pos) < n {
+			return nil, fmt.Errorf("unexpected end of data")
+		}
+		v := r.buf[r.pos : r.pos+int(n)]
+		r.pos += int(n)
+		return v, nil
+	case TypeList, TypeSet:
+		header, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size := uint64(header >> 4)
+		if size == 15 {
+			if size, err = r.uvarint(); err != nil {
+				return nil, err
+			}
+		}
+		if size > uint64(len(r.buf)-r.pos) {
+			return nil, fmt.Errorf("list of %d elements is longer than the data", size)
+		}
+		if r.depth++; r.depth > MaxDepth {
+			return nil, fmt.Errorf("lists are nested more than %d deep", MaxDepth)
+		}
+		defer func() { r.depth-- }()
+		items := make([]interface{}, size)
+		for i := range items {
+			if items[i], err = r.readValue(header & 0x0F); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	case TypeMap:
+		size, err := r.uvarint()
+		if err != nil || size == 0 {
+			return nil, err
+		}
+		types, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		if r.depth++; r.depth > MaxDepth {
+			return nil, fmt.Errorf("maps are nested more than %d deep", MaxDepth)
+		}
+		defer func() { r.depth-- }()
+		for i := uint64(0); i < size; i++ {
+			if _, err := r.readValue(types >> 4); err != nil {
+				return nil, err
+			}
+			if _, err := r.readValue(types & 0x0F); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	case TypeStruct:
+		return r.ReadStruct()
+	default:
+		return nil, fmt.Errorf("unknown Thrift type %d", valueType)
+	}
+}
@@ -0,0 +1,66 @@
+package thrift
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	names := make([]string, 20)
+	for i := range names {
+		names[i] = string(rune('a' + i))
+	}
+	child := (&Struct{}).I32(1, -7).Bool(2, false)
+	encoded := (&Struct{}).
+		Bool(1, true).
+		I32(2, -1).
+		I64(3, 1<<40).
+		String(4, "schema").
+		Struct(5, child).
+		I32s(6, 0, 3).
+		Strings(7, names...).
+		Structs(8, child, child).
+		I64(100, 42). // More than 15 ids after the previous field
+		Encode()
+
+	reader := NewReader(append(encoded, 0xFF), 0)
+	fields, err := reader.ReadStruct()
+	require.NoError(t, err)
+	assert.Equal(t, len(encoded), reader.Pos())
+
+	assert.True(t, fields.Bool(1))
+	assert.Equal(t, int64(-1), fields.I64(2))
+	assert.Equal(t, int64(1<<40), fields.I64(3))
+	assert.Equal(t, "schema", fields.String(4))
+	assert.Equal(t, Fields{1: int64(-7), 2: false}, fields.Struct(5))
+	assert.Equal(t, []interface{}{int64(0), int64(3)}, fields.List(6))
+	require.Len(t, fields.List(7), 20)
+	assert.Equal(t, []byte("t"), fields.List(7)[19])
+	assert.Equal(t, []interface{}{Fields{1: int64(-7), 2: false}, Fields{1: int64(-7), 2: false}}, fields.List(8))
+	assert.Equal(t, int64(42), fields.I64(100))
+
+	assert.False(t, fields.Has(9))
+	assert.Zero(t, fields.I64(9))
+	assert.Nil(t, fields.Struct(9))
+}
+
+func TestReaderLimits(t *testing.T) {
+	encoded := (&Struct{}).String(1, "schema").Encode()
+	for n := 0; n < len(encoded); n++ {
+		_, err := NewReader(encoded[:n], 0).ReadStruct()
+		assert.Error(t, err, "truncated to %d bytes", n)
+	}
+
+	// A list claiming more elements than there are bytes left
+	_, err := NewReader([]byte{0x19, 0xF5, 0xFF, 0xFF, 0xFF, 0x0F}, 0).ReadStruct()
+	assert.ErrorContains(t, err, "longer than the data")
+
+	nested := (&Struct{}).I32(1, 1)
+	for i := 0; i < MaxDepth; i++ {
+		nested = (&Struct{}).Struct(1, nested)
+	}
+	_, err = NewReader(nested.Encode(), 0).ReadStruct()
+	assert.ErrorContains(t, err, "nested more than 32 deep")
+}
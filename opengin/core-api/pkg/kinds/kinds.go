@@ -0,0 +1,358 @@
+// Package kinds validates entities against the kind registry.
+//
+// Kinds are free text in the API, so a typo such as Organisation for Organization silently starts a
+// second kind with a Neo4j label of its own. The registry of a tenant declares the major kinds that
+// exist, their minor kinds, the metadata keys and attributes their entities carry and the relationships
+// they may point to other entities with. An empty registry declares nothing and every entity passes.
+package kinds
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"lk/datafoundation/core-api/commons"
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/schema"
+	"lk/datafoundation/core-api/pkg/storageinference"
+	"lk/datafoundation/core-api/pkg/typeinference"
+)
+
+// Mode decides what happens to an entity that does not match the registry
+type Mode string
+
+const (
+	// Enforce refuses the entity
+	Enforce Mode = "enforce"
+	// Warn logs the violations and stores the entity anyway
+	Warn Mode = "warn"
+	// Off skips the validation
+	Off Mode = "off"
+)
+
+// ParseMode validates a mode name
+func ParseMode(name string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(strings.TrimSpace(name))); mode {
+	case Enforce, Warn, Off:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown kind registry mode %q, expected enforce, warn or off", name)
+	}
+}
+
+// ModeFromEnv reads CORE_KIND_REGISTRY_MODE, defaulting to Enforce
+func ModeFromEnv() (Mode, error) {
+	name := os.Getenv("CORE_KIND_REGISTRY_MODE")
+	if name == "" {
+		return Enforce, nil
+	}
+	return ParseMode(name)
+}
+
+// columnTypes are the column types a tabular attribute may be expected to have
+var columnTypes = []typeinference.DataType{
+	typeinference.IntType, typeinference.BigIntType, typeinference.FloatType, typeinference.DecimalType,
+	typeinference.StringType, typeinference.BoolType, typeinference.DateType, typeinference.TimeType, typeinference.DateTimeType,
+}
+
+// ValidateDefinition checks a kind definition before it is stored
+func ValidateDefinition(def *pb.KindDefinition) error {
+	if def == nil || def.Major == "" {
+		return fmt.Errorf("a kind definition needs a major kind")
+	}
+	if !commons.IsGraphIdentifier(def.Major) {
+		return fmt.Errorf("major kind %q can only hold letters, digits and underscores and cannot start with a digit", def.Major)
+	}
+	if err := checkNames("minor kind", def.Minors); err != nil {
+		return err
+	}
+	if err := checkNames("required metadata key", def.RequiredMetadata); err != nil {
+		return err
+	}
+
+	names := make([]string, len(def.Relationships))
+	for i, relationship := range def.Relationships {
+		names[i] = relationship.GetName()
+		if names[i] != "" && !commons.IsGraphIdentifier(names[i]) {
+			return fmt.Errorf("relationship %q can only hold letters, digits and underscores and cannot start with a digit", names[i])
+		}
+		if err := checkNames("target kind of relationship "+names[i], relationship.GetTargetKinds()); err != nil {
+			return err
+		}
+	}
+	if err := checkNames("relationship", names); err != nil {
+		return err
+	}
+
+	for name, attribute := range def.Attributes {
+		if name == "" {
+			return fmt.Errorf("attribute names cannot be empty")
+		}
+		if attribute == nil {
+			return fmt.Errorf("attribute %s has no definition", name)
+		}
+		if attribute.StorageType != "" {
+			storageType, err := storageinference.ParseStorageType(attribute.StorageType)
+			if err != nil {
+				return fmt.Errorf("attribute %s: %v", name, err)
+			}
+			if len(attribute.Columns) > 0 && storageType != storageinference.TabularData {
+				return fmt.Errorf("attribute %s: only tabular attributes have columns", name)
+			}
+		}
+		for column, columnType := range attribute.Columns {
+			if column == "" {
+				return fmt.Errorf("attribute %s: column names cannot be empty", name)
+			}
+			if !slices.Contains(columnTypes, typeinference.DataType(columnType)) {
+				return fmt.Errorf("attribute %s: column %s has unsupported type %q", name, column, columnType)
+			}
+		}
+	}
+	return nil
+}
+
+// checkNames refuses empty and repeated names
+func checkNames(what string, names []string) error {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if name == "" {
+			return fmt.Errorf("%s names cannot be empty", what)
+		}
+		if seen[name] {
+			return fmt.Errorf("%s %s is listed twice", what, name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// Registry holds the kind definitions of a tenant keyed by major kind
+type Registry map[string]*pb.KindDefinition
+
+// NewRegistry keys definitions by their major kind
+func NewRegistry(definitions []*pb.KindDefinition) Registry {
+	registry := make(Registry, len(definitions))
+	for _, def := range definitions {
+		registry[def.Major] = def
+	}
+	return registry
+}
+
+// KindLookup returns the major kind of a stored entity
+type KindLookup func(entityID string) (string, error)
+
+// Check returns the ways an entity does not match the registry, empty when it does.
+// kind is the kind the entity is stored with, which updates do not carry. New entities must have
+// every required metadata key and attribute; updates only have the metadata they replace checked.
+// kindOf resolves the kinds of related entities and may fail for entities that do not exist,
+// whose relationships the graph store refuses anyway.
+func (r Registry) Check(entity *pb.Entity, kind *pb.Kind, create bool, kindOf KindLookup) []string {
+	if len(r) == 0 {
+		return nil
+	}
+	if kind.GetMajor() == "" {
+		return []string{"the entity has no major kind"}
+	}
+	def, ok := r[kind.Major]
+	if !ok {
+		violation := fmt.Sprintf("kind %s is not registered", kind.Major)
+		if suggestion := r.closest(kind.Major); suggestion != "" {
+			violation += fmt.Sprintf(" (did you mean %s?)", suggestion)
+		}
+		return []string{violation}
+	}
+
+	var violations []string
+	if create && len(def.Minors) > 0 && !slices.Contains(def.Minors, kind.Minor) {
+		violations = append(violations, fmt.Sprintf("minor kind %q is not one of %s for %s", kind.Minor, strings.Join(def.Minors, ", "), def.Major))
+	}
+
+	if create || len(entity.GetMetadata()) > 0 {
+		for _, key := range def.RequiredMetadata {
+			if _, ok := entity.GetMetadata()[key]; !ok {
+				violations = append(violations, fmt.Sprintf("metadata key %s is required for %s", key, def.Major))
+			}
+		}
+	}
+
+	violations = append(violations, checkRelationships(def, entity, kindOf)...)
+	violations = append(violations, checkAttributes(def, entity, create)...)
+	return violations
+}
+
+// checkRelationships checks the relationships the entity points to other entities with
+func checkRelationships(def *pb.KindDefinition, entity *pb.Entity, kindOf KindLookup) []string {
+	if len(def.Relationships) == 0 {
+		return nil
+	}
+
+	var violations []string
+	for _, id := range sortedKeys(entity.GetRelationships()) {
+		relationship := entity.Relationships[id]
+		// Updates of an existing relationship may only carry its id and end time
+		if relationship.GetName() == "" {
+			continue
+		}
+		index := slices.IndexFunc(def.Relationships, func(allowed *pb.AllowedRelationship) bool {
+			return allowed.GetName() == relationship.Name
+		})
+		if index < 0 {
+			violations = append(violations, fmt.Sprintf("relationship %s is not allowed from %s", relationship.Name, def.Major))
+			continue
+		}
+		targets := def.Relationships[index].GetTargetKinds()
+		if len(targets) == 0 || kindOf == nil {
+			continue
+		}
+		target, err := kindOf(relationship.RelatedEntityId)
+		if err != nil {
+			continue
+		}
+		if !slices.Contains(targets, target) {
+			violations = append(violations, fmt.Sprintf("relationship %s from %s cannot point to %s %s, only to %s",
+				relationship.Name, def.Major, target, relationship.RelatedEntityId, strings.Join(targets, ", ")))
+		}
+	}
+	return violations
+}
+
+// checkAttributes checks the attributes of the entity against the expected ones
+func checkAttributes(def *pb.KindDefinition, entity *pb.Entity, create bool) []string {
+	var violations []string
+	for _, name := range sortedKeys(entity.GetAttributes()) {
+		expected, ok := def.Attributes[name]
+		if !ok {
+			if def.ClosedAttributes {
+				violations = append(violations, fmt.Sprintf("attribute %s is not declared for %s", name, def.Major))
+			}
+			continue
+		}
+		for _, value := range entity.Attributes[name].GetValues() {
+			if value.GetValue() == nil {
+				continue
+			}
+			if err := checkAttribute(expected, value); err != nil {
+				violations = append(violations, fmt.Sprintf("attribute %s: %v", name, err))
+			}
+		}
+	}
+
+	if create {
+		for _, name := range sortedKeys(def.Attributes) {
+			if def.Attributes[name].GetRequired() && len(entity.GetAttributes()[name].GetValues()) == 0 {
+				violations = append(violations, fmt.Sprintf("attribute %s is required for %s", name, def.Major))
+			}
+		}
+	}
+	return violations
+}
+
+// checkAttribute compares the storage type and columns of one attribute value with the expected ones
+func checkAttribute(expected *pb.ExpectedAttribute, value *pb.TimeBasedValue) error {
+	if expected.StorageType == "" && len(expected.Columns) == 0 {
+		return nil
+	}
+
+	hint, anyValue, err := storageinference.UnwrapHint(value.Value)
+	if err != nil {
+		return err
+	}
+	var storageType storageinference.StorageType
+	if hint != nil {
+		storageType = hint.StorageType
+	} else if storageType, err = (&storageinference.StorageInferrer{}).InferType(anyValue); err != nil {
+		return err
+	}
+	if expected.StorageType != "" && storageType != storageinference.StorageType(expected.StorageType) {
+		return fmt.Errorf("expected %s data, got %s", expected.StorageType, storageType)
+	}
+	if len(expected.Columns) == 0 || storageType != storageinference.TabularData {
+		return nil
+	}
+
+	info, err := schema.GenerateSchema(anyValue)
+	if err != nil {
+		return err
+	}
+	var missing []string
+	for _, column := range sortedKeys(expected.Columns) {
+		field, ok := info.Fields[column]
+		if !ok {
+			missing = append(missing, column)
+			continue
+		}
+		if got := field.TypeInfo.Type; !compatible(typeinference.DataType(expected.Columns[column]), got) {
+			return fmt.Errorf("column %s is %s, expected %s", column, got, expected.Columns[column])
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing columns %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// compatible reports whether a column inferred as got can hold values of the expected type.
+// Nulls fit any type, whole numbers fit bigint and float columns and any number fits decimal columns.
+func compatible(expected, got typeinference.DataType) bool {
+	if expected == got || got == typeinference.NullType {
+		return true
+	}
+	switch expected {
+	case typeinference.BigIntType:
+		return got == typeinference.IntType
+	case typeinference.FloatType:
+		return got == typeinference.IntType || got == typeinference.BigIntType
+	case typeinference.DecimalType:
+		return got == typeinference.IntType || got == typeinference.BigIntType || got == typeinference.FloatType
+	default:
+		return false
+	}
+}
+
+// closest returns the registered major kind an unknown one is most likely a typo of, if any
+func (r Registry) closest(major string) string {
+	best, bestDistance := "", 3
+	for _, candidate := range sortedKeys(r) {
+		if strings.EqualFold(candidate, major) {
+			return candidate
+		}
+		if distance := editDistance(strings.ToLower(candidate), strings.ToLower(major)); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	previous := make([]int, len(br)+1)
+	current := make([]int, len(br)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		current[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(br)]
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,198 @@
+package kinds
+
+import (
+	"fmt"
+	"testing"
+
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/typeinference"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func organization() *pb.KindDefinition {
+	return &pb.KindDefinition{
+		Major:            "Organization",
+		Minors:           []string{"Department", "Ministry"},
+		RequiredMetadata: []string{"code"},
+		Relationships: []*pb.AllowedRelationship{
+			{Name: "HAS_MEMBER", TargetKinds: []string{"Person"}},
+			{Name: "REFERENCES"},
+		},
+		Attributes: map[string]*pb.ExpectedAttribute{
+			"budget":   {StorageType: "tabular", Columns: map[string]string{"year": "int", "amount": "float"}, Required: true},
+			"profile":  {StorageType: "map"},
+			"comments": {},
+		},
+		ClosedAttributes: true,
+	}
+}
+
+func attribute(t *testing.T, value interface{}) *pb.TimeBasedValueList {
+	structValue, err := structpb.NewStruct(value.(map[string]interface{}))
+	require.NoError(t, err)
+	anyValue, err := anypb.New(structValue)
+	require.NoError(t, err)
+	return &pb.TimeBasedValueList{Values: []*pb.TimeBasedValue{{StartTime: "2025-01-01T00:00:00Z", Value: anyValue}}}
+}
+
+func budget(t *testing.T, columns []interface{}, row []interface{}) *pb.TimeBasedValueList {
+	return attribute(t, map[string]interface{}{"columns": columns, "rows": []interface{}{row}})
+}
+
+func TestParseMode(t *testing.T) {
+	for name, want := range map[string]Mode{"enforce": Enforce, " Warn ": Warn, "OFF": Off} {
+		mode, err := ParseMode(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, mode)
+	}
+	_, err := ParseMode("lenient")
+	assert.Error(t, err)
+
+	t.Setenv("CORE_KIND_REGISTRY_MODE", "")
+	mode, err := ModeFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, Enforce, mode)
+}
+
+func TestValidateDefinition(t *testing.T) {
+	assert.NoError(t, ValidateDefinition(organization()))
+
+	for name, def := range map[string]*pb.KindDefinition{
+		"no major":           {},
+		"padded major":       {Major: " Person"},
+		"empty minor":        {Major: "Person", Minors: []string{""}},
+		"repeated metadata":  {Major: "Person", RequiredMetadata: []string{"nic", "nic"}},
+		"repeated relation":  {Major: "Person", Relationships: []*pb.AllowedRelationship{{Name: "KNOWS"}, {Name: "KNOWS"}}},
+		"unnamed relation":   {Major: "Person", Relationships: []*pb.AllowedRelationship{{}}},
+		"bad storage type":   {Major: "Person", Attributes: map[string]*pb.ExpectedAttribute{"cv": {StorageType: "document"}}},
+		"columns of a map":   {Major: "Person", Attributes: map[string]*pb.ExpectedAttribute{"cv": {StorageType: "map", Columns: map[string]string{"a": "int"}}}},
+		"bad column type":    {Major: "Person", Attributes: map[string]*pb.ExpectedAttribute{"pay": {Columns: map[string]string{"a": "money"}}}},
+		"nil attribute":      {Major: "Person", Attributes: map[string]*pb.ExpectedAttribute{"pay": nil}},
+		"unnamed target":     {Major: "Person", Relationships: []*pb.AllowedRelationship{{Name: "KNOWS", TargetKinds: []string{""}}}},
+		"unnamed attribute":  {Major: "Person", Attributes: map[string]*pb.ExpectedAttribute{"": {}}},
+		"unnamed column":     {Major: "Person", Attributes: map[string]*pb.ExpectedAttribute{"pay": {Columns: map[string]string{"": "int"}}}},
+		"repeated target":    {Major: "Person", Relationships: []*pb.AllowedRelationship{{Name: "KNOWS", TargetKinds: []string{"Person", "Person"}}}},
+		"repeated minor":     {Major: "Person", Minors: []string{"Citizen", "Citizen"}},
+		"empty metadata key": {Major: "Person", RequiredMetadata: []string{""}},
+		"injected major":     {Major: "Person) DETACH DELETE (e"},
+		"injected relation":  {Major: "Person", Relationships: []*pb.AllowedRelationship{{Name: "KNOWS`]->()"}}},
+	} {
+		assert.Error(t, ValidateDefinition(def), name)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	registry := NewRegistry([]*pb.KindDefinition{organization(), {Major: "Person"}})
+	kindOf := func(entityID string) (string, error) {
+		switch entityID {
+		case "person":
+			return "Person", nil
+		case "org":
+			return "Organization", nil
+		}
+		return "", fmt.Errorf("entity %s not found", entityID)
+	}
+	code, err := anypb.New(wrapperspb.String("ORG-1"))
+	require.NoError(t, err)
+
+	valid := func() *pb.Entity {
+		return &pb.Entity{
+			Id:       "org",
+			Kind:     &pb.Kind{Major: "Organization", Minor: "Ministry"},
+			Metadata: map[string]*anypb.Any{"code": code},
+			Relationships: map[string]*pb.Relationship{
+				"r1": {Id: "r1", Name: "HAS_MEMBER", RelatedEntityId: "person"},
+				"r2": {Id: "r2", Name: "REFERENCES", RelatedEntityId: "org"},
+			},
+			Attributes: map[string]*pb.TimeBasedValueList{
+				"budget":  budget(t, []interface{}{"year", "amount"}, []interface{}{2025, 10.5}),
+				"profile": attribute(t, map[string]interface{}{"motto": "serve", "founded": 1948}),
+			},
+		}
+	}
+	assert.Empty(t, registry.Check(valid(), valid().Kind, true, kindOf))
+
+	// Whole numbers fit float columns
+	entity := valid()
+	entity.Attributes["budget"] = budget(t, []interface{}{"year", "amount"}, []interface{}{2025, 10})
+	assert.Empty(t, registry.Check(entity, entity.Kind, true, kindOf))
+	entity.Attributes["budget"] = budget(t, []interface{}{"year", "amount"}, []interface{}{2025, 4102444800000})
+	assert.Empty(t, registry.Check(entity, entity.Kind, true, kindOf))
+	assert.True(t, compatible(typeinference.DecimalType, typeinference.FloatType))
+	assert.False(t, compatible(typeinference.IntType, typeinference.BigIntType))
+
+	assert.Empty(t, Registry{}.Check(&pb.Entity{Kind: &pb.Kind{Major: "Anything"}}, &pb.Kind{Major: "Anything"}, true, kindOf))
+
+	assert.Equal(t, []string{"kind Organisation is not registered (did you mean Organization?)"},
+		registry.Check(valid(), &pb.Kind{Major: "Organisation"}, true, kindOf))
+	assert.Equal(t, []string{"kind person is not registered (did you mean Person?)"},
+		registry.Check(valid(), &pb.Kind{Major: "person"}, true, kindOf))
+	assert.Equal(t, []string{"kind Vehicle is not registered"},
+		registry.Check(valid(), &pb.Kind{Major: "Vehicle"}, true, kindOf))
+	assert.Equal(t, []string{"the entity has no major kind"}, registry.Check(valid(), nil, true, kindOf))
+
+	for name, tc := range map[string]struct {
+		change func(*pb.Entity)
+		want   string
+	}{
+		"minor": {
+			func(e *pb.Entity) { e.Kind.Minor = "Company" },
+			`minor kind "Company" is not one of Department, Ministry for Organization`,
+		},
+		"metadata": {
+			func(e *pb.Entity) { e.Metadata = nil },
+			"metadata key code is required for Organization",
+		},
+		"relationship name": {
+			func(e *pb.Entity) { e.Relationships["r3"] = &pb.Relationship{Name: "OWNS", RelatedEntityId: "person"} },
+			"relationship OWNS is not allowed from Organization",
+		},
+		"relationship target": {
+			func(e *pb.Entity) { e.Relationships["r1"].RelatedEntityId = "org" },
+			"relationship HAS_MEMBER from Organization cannot point to Organization org, only to Person",
+		},
+		"undeclared attribute": {
+			func(e *pb.Entity) { e.Attributes["logo"] = attribute(t, map[string]interface{}{"url": "x"}) },
+			"attribute logo is not declared for Organization",
+		},
+		"required attribute": {
+			func(e *pb.Entity) { delete(e.Attributes, "budget") },
+			"attribute budget is required for Organization",
+		},
+		"storage type": {
+			func(e *pb.Entity) {
+				e.Attributes["profile"] = budget(t, []interface{}{"year", "amount"}, []interface{}{2025, 1.5})
+			},
+			"attribute profile: expected map data, got tabular",
+		},
+		"missing column": {
+			func(e *pb.Entity) { e.Attributes["budget"] = budget(t, []interface{}{"year"}, []interface{}{2025}) },
+			"attribute budget: missing columns amount",
+		},
+		"column type": {
+			func(e *pb.Entity) {
+				e.Attributes["budget"] = budget(t, []interface{}{"year", "amount"}, []interface{}{"soon", 1.5})
+			},
+			"attribute budget: column year is string, expected int",
+		},
+	} {
+		entity := valid()
+		tc.change(entity)
+		assert.Equal(t, []string{tc.want}, registry.Check(entity, entity.Kind, true, kindOf), name)
+	}
+
+	// Updates carry neither the kind nor every attribute, and relationships to unknown entities are left to the graph store
+	update := &pb.Entity{
+		Id:            "org",
+		Relationships: map[string]*pb.Relationship{"r1": {Id: "r1", EndTime: "2026-01-01T00:00:00Z"}, "r4": {Name: "REFERENCES", RelatedEntityId: "missing"}},
+	}
+	assert.Empty(t, registry.Check(update, &pb.Kind{Major: "Organization", Minor: "Company"}, false, kindOf))
+	update.Metadata = map[string]*anypb.Any{"name": code}
+	assert.Equal(t, []string{"metadata key code is required for Organization"},
+		registry.Check(update, &pb.Kind{Major: "Organization"}, false, kindOf))
+}
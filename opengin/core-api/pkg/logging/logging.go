@@ -0,0 +1,97 @@
+// Package logging configures structured logging for the core service on top of log/slog.
+//
+// Records are written as JSON (or text) at a level that can be changed while the service runs.
+// Records logged with a context carry the request ID of the gRPC call and, when tracing is on,
+// its trace and span IDs. Payloads such as entities and attribute values go through Payload so
+// they are redacted or truncated instead of being written out in full.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Level is the minimum level written; it can be changed at runtime with SetLevel or LevelHandler
+var Level = new(slog.LevelVar)
+
+// Setup installs the default slog logger from the environment:
+// CORE_LOG_LEVEL (debug, info, warn, error; default info), CORE_LOG_FORMAT (json or text; default json),
+// CORE_LOG_PAYLOADS (redact, truncate or full; default redact) and CORE_LOG_PAYLOAD_LIMIT (default 256).
+// Output of the standard log package goes through the same handler.
+func Setup(w io.Writer) error {
+	if value := os.Getenv("CORE_LOG_LEVEL"); value != "" {
+		if err := SetLevel(value); err != nil {
+			return err
+		}
+	}
+	if err := configurePayloads(os.Getenv("CORE_LOG_PAYLOADS"), os.Getenv("CORE_LOG_PAYLOAD_LIMIT")); err != nil {
+		return err
+	}
+
+	options := &slog.HandlerOptions{Level: Level}
+	var handler slog.Handler
+	switch format := os.Getenv("CORE_LOG_FORMAT"); format {
+	case "", "json":
+		handler = slog.NewJSONHandler(w, options)
+	case "text":
+		handler = slog.NewTextHandler(w, options)
+	default:
+		return fmt.Errorf("unsupported CORE_LOG_FORMAT %q, use json or text", format)
+	}
+
+	slog.SetDefault(slog.New(NewContextHandler(handler)))
+	// Remaining log.Printf callers are written as info records
+	log.SetFlags(0)
+	return nil
+}
+
+// SetLevel changes the minimum level, e.g. to "debug" while investigating an issue
+func SetLevel(name string) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+		return fmt.Errorf("invalid log level %q, use debug, info, warn or error", name)
+	}
+	Level.Set(level)
+	return nil
+}
+
+// ContextHandler adds the request, trace and span IDs found in the record's context
+type ContextHandler struct {
+	slog.Handler
+}
+
+// NewContextHandler wraps a handler so records logged with a context carry its correlation IDs
+func NewContextHandler(handler slog.Handler) *ContextHandler {
+	return &ContextHandler{Handler: handler}
+}
+
+// Handle adds the correlation IDs and passes the record on
+func (h *ContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", spanContext.TraceID().String()),
+			slog.String("span_id", spanContext.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs keeps the wrapper around the derived handler
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup keeps the wrapper around the derived handler
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestContextHandlerAddsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil)))
+
+	logger.InfoContext(WithRequestID(context.Background(), "req-1"), "Reading entity", "entity_id", "e1")
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "req-1", record["request_id"])
+	assert.Equal(t, "e1", record["entity_id"])
+}
+
+func TestUnaryServerInterceptorRequestID(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return RequestID(ctx), nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDHeader, "from-caller"))
+	id, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "from-caller", id)
+
+	id, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+	require.NoError(t, err)
+	assert.Len(t, id, 32, "a new ID is made when the caller sends none")
+}
+
+func TestPayloadModes(t *testing.T) {
+	defer configurePayloads(PayloadRedact, "256")
+
+	value, err := structpb.NewStruct(map[string]interface{}{"secret": strings.Repeat("x", 100)})
+	require.NoError(t, err)
+
+	redacted := Payload(value).LogValue()
+	assert.Equal(t, slog.KindGroup, redacted.Kind())
+	assert.NotContains(t, redacted.String(), "secret")
+
+	require.NoError(t, configurePayloads(PayloadTruncate, "20"))
+	truncated := Payload(value).LogValue().String()
+	assert.Less(t, len(truncated), 50)
+	assert.Contains(t, truncated, "more bytes)")
+
+	assert.Error(t, configurePayloads("everything", ""))
+	assert.Equal(t, "abc", Truncate("abc", 10))
+}
+
+func TestLevelHandler(t *testing.T) {
+	defer Level.Set(slog.LevelInfo)
+
+	recorder := httptest.NewRecorder()
+	LevelHandler().ServeHTTP(recorder, httptest.NewRequest("PUT", "/debug/loglevel", strings.NewReader("debug")))
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, slog.LevelDebug, Level.Level())
+
+	recorder = httptest.NewRecorder()
+	LevelHandler().ServeHTTP(recorder, httptest.NewRequest("PUT", "/debug/loglevel", strings.NewReader("loud")))
+	assert.Equal(t, 400, recorder.Code)
+	assert.Equal(t, slog.LevelDebug, Level.Level())
+}
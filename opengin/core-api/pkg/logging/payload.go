@@ -0,0 +1,99 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Payload modes
+const (
+	// PayloadRedact logs only the type and size of a payload
+	PayloadRedact = "redact"
+	// PayloadTruncate logs the payload cut to the payload limit
+	PayloadTruncate = "truncate"
+	// PayloadFull logs the whole payload; meant for local debugging only
+	PayloadFull = "full"
+)
+
+// DefaultPayloadLimit is the number of bytes kept when payloads are truncated
+const DefaultPayloadLimit = 256
+
+var (
+	payloadMode  atomic.Value
+	payloadLimit atomic.Int64
+)
+
+func init() {
+	payloadMode.Store(PayloadRedact)
+	payloadLimit.Store(DefaultPayloadLimit)
+}
+
+func configurePayloads(mode string, limit string) error {
+	switch mode {
+	case "":
+	case PayloadRedact, PayloadTruncate, PayloadFull:
+		payloadMode.Store(mode)
+	default:
+		return fmt.Errorf("unsupported CORE_LOG_PAYLOADS %q, use redact, truncate or full", mode)
+	}
+	if limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value <= 0 {
+			return fmt.Errorf("invalid CORE_LOG_PAYLOAD_LIMIT %q", limit)
+		}
+		payloadLimit.Store(int64(value))
+	}
+	return nil
+}
+
+// Payload wraps a value such as an entity, a request or attribute results so that it is only
+// rendered, redacted or truncated, when the record is actually written
+func Payload(value interface{}) slog.LogValuer {
+	return payload{value: value}
+}
+
+type payload struct {
+	value interface{}
+}
+
+// LogValue renders the payload according to CORE_LOG_PAYLOADS
+func (p payload) LogValue() slog.Value {
+	switch payloadMode.Load().(string) {
+	case PayloadFull:
+		return slog.StringValue(fmt.Sprintf("%+v", p.value))
+	case PayloadTruncate:
+		return slog.StringValue(Truncate(fmt.Sprintf("%+v", p.value), int(payloadLimit.Load())))
+	default:
+		return redact(p.value)
+	}
+}
+
+// redact describes a payload by type and size without any of its content
+func redact(value interface{}) slog.Value {
+	if value == nil {
+		return slog.StringValue("<nil>")
+	}
+	attrs := []slog.Attr{slog.String("type", fmt.Sprintf("%T", value))}
+	if message, ok := value.(proto.Message); ok {
+		attrs = append(attrs, slog.Int("bytes", proto.Size(message)))
+		return slog.GroupValue(attrs...)
+	}
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.String:
+		attrs = append(attrs, slog.Int("len", v.Len()))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// Truncate cuts s to limit bytes, noting how much was left out
+func Truncate(s string, limit int) string {
+	if limit <= 0 || len(s) <= limit {
+		return s
+	}
+	return fmt.Sprintf("%s...(%d more bytes)", s[:limit], len(s)-limit)
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor counts and times unary calls by method and status code
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observeRPC(info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor counts and times streaming calls by method and status code
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, stream)
+		observeRPC(info.FullMethod, start, err)
+		return err
+	}
+}
+
+func observeRPC(fullMethod string, start time.Time, err error) {
+	operation := methodName(fullMethod)
+	grpcRequests.WithLabelValues(operation, status.Code(err).String()).Inc()
+	grpcDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+// methodName turns /core.COREService/CreateEntity into CreateEntity.
+// Calls to other services, such as health checks, keep their service name.
+func methodName(fullMethod string) string {
+	service, method, found := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !found {
+		return fullMethod
+	}
+	if service == "core.COREService" {
+		return method
+	}
+	return service + "/" + method
+}
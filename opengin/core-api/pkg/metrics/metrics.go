@@ -0,0 +1,160 @@
+// Package metrics exposes Prometheus metrics for the core service: gRPC calls, attribute
+// resolvers and database calls, each with its latency and outcome, and the database connection pools.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Database label values
+const (
+	Neo4j    = "neo4j"
+	MongoDB  = "mongodb"
+	Postgres = "postgres"
+)
+
+// Outcome label values for resolver and database calls; gRPC calls use the status code
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// Registry holds every metric of the service, together with the Go runtime and process collectors
+var Registry = prometheus.NewRegistry()
+
+var (
+	grpcRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "core",
+		Name:      "grpc_requests_total",
+		Help:      "gRPC calls handled, by method and status code.",
+	}, []string{"operation", "outcome"})
+
+	grpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "core",
+		Name:      "grpc_request_duration_seconds",
+		Help:      "Time spent handling gRPC calls, by method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	attributeOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "core",
+		Name:      "attribute_operations_total",
+		Help:      "Attribute values resolved, by operation, storage type and outcome.",
+	}, []string{"operation", "storage_type", "outcome"})
+
+	attributeDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "core",
+		Name:      "attribute_operation_duration_seconds",
+		Help:      "Time spent resolving attribute values, by operation and storage type.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "storage_type"})
+
+	databaseOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "core",
+		Name:      "database_operations_total",
+		Help:      "Database calls made, by database, operation and outcome.",
+	}, []string{"database", "operation", "outcome"})
+
+	databaseDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "core",
+		Name:      "database_operation_duration_seconds",
+		Help:      "Time spent in database calls, by database and operation.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"database", "operation"})
+
+	poolConnections = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "core",
+		Name:      "database_pool_connections",
+		Help:      "Connections in the database pools, by database and state (open, in_use).",
+	}, []string{"database", "state"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		grpcRequests, grpcDuration,
+		attributeOperations, attributeDuration,
+		databaseOperations, databaseDuration,
+		poolConnections,
+	)
+}
+
+// Handler serves the registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// Outcome maps an error to the outcome label
+func Outcome(err error) string {
+	if err != nil {
+		return OutcomeError
+	}
+	return OutcomeSuccess
+}
+
+// ObserveAttribute records one attribute value handled by a resolver
+func ObserveAttribute(operation, storageType string, duration time.Duration, err error) {
+	attributeOperations.WithLabelValues(operation, storageType, Outcome(err)).Inc()
+	attributeDuration.WithLabelValues(operation, storageType).Observe(duration.Seconds())
+}
+
+// ObserveDatabase records a database call that started at start.
+// It is meant to be deferred with a pointer to the call's named error result:
+//
+//	defer metrics.ObserveDatabase(metrics.Neo4j, "ReadGraphEntity", time.Now(), &err)
+func ObserveDatabase(database, operation string, start time.Time, err *error) {
+	var callErr error
+	if err != nil {
+		callErr = *err
+	}
+	ObserveDatabaseDuration(database, operation, time.Since(start), callErr)
+}
+
+// ObserveDatabaseDuration records a database call whose duration is already known, such as a driver event
+func ObserveDatabaseDuration(database, operation string, duration time.Duration, err error) {
+	databaseOperations.WithLabelValues(database, operation, Outcome(err)).Inc()
+	databaseDuration.WithLabelValues(database, operation).Observe(duration.Seconds())
+}
+
+// AddPoolConnections moves the pool gauge of a database for drivers that report pool events
+func AddPoolConnections(database, state string, delta float64) {
+	poolConnections.WithLabelValues(database, state).Add(delta)
+}
+
+// PoolStats is a snapshot of a connection pool
+type PoolStats struct {
+	Open  int
+	InUse int
+}
+
+// RegisterPoolStats publishes the pool of a database whose client can report its own statistics.
+// The stats function is called on every scrape.
+func RegisterPoolStats(database string, stats func() PoolStats) error {
+	return Registry.Register(poolStatsCollector{database: database, stats: stats})
+}
+
+var poolDesc = prometheus.NewDesc("core_database_pool_connections",
+	"Connections in the database pools, by database and state (open, in_use).",
+	[]string{"database", "state"}, nil)
+
+// poolStatsCollector reads a pool snapshot when scraped
+type poolStatsCollector struct {
+	database string
+	stats    func() PoolStats
+}
+
+func (c poolStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	// Unchecked: the series share core_database_pool_connections with the event driven gauge
+}
+
+func (c poolStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.stats()
+	ch <- prometheus.MustNewConstMetric(poolDesc, prometheus.GaugeValue, float64(stats.Open), c.database, "open")
+	ch <- prometheus.MustNewConstMetric(poolDesc, prometheus.GaugeValue, float64(stats.InUse), c.database, "in_use")
+}
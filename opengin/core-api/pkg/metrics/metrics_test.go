@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMethodName(t *testing.T) {
+	assert.Equal(t, "CreateEntity", methodName("/core.COREService/CreateEntity"))
+	assert.Equal(t, "grpc.health.v1.Health/Check", methodName("/grpc.health.v1.Health/Check"))
+	assert.Equal(t, "odd", methodName("odd"))
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/core.COREService/ReadEntity"}
+
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+	_, err = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "missing")
+	})
+	require.Error(t, err)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(grpcRequests.WithLabelValues("ReadEntity", "OK")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(grpcRequests.WithLabelValues("ReadEntity", "NotFound")))
+}
+
+func TestObserveDatabase(t *testing.T) {
+	call := func(fail bool) (err error) {
+		defer ObserveDatabase(Postgres, "TestCall", time.Now(), &err)
+		if fail {
+			return errors.New("boom")
+		}
+		return nil
+	}
+	_ = call(false)
+	_ = call(true)
+	_ = call(true)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(databaseOperations.WithLabelValues(Postgres, "TestCall", OutcomeSuccess)))
+	assert.Equal(t, float64(2), testutil.ToFloat64(databaseOperations.WithLabelValues(Postgres, "TestCall", OutcomeError)))
+
+	ObserveAttribute("read", "tabular", time.Millisecond, nil)
+	assert.Equal(t, float64(1), testutil.ToFloat64(attributeOperations.WithLabelValues("read", "tabular", OutcomeSuccess)))
+}
+
+func TestHandlerServesPoolStats(t *testing.T) {
+	AddPoolConnections(MongoDB, "open", 2)
+	require.NoError(t, RegisterPoolStats(Postgres, func() PoolStats { return PoolStats{Open: 3, InUse: 1} }))
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, 200, recorder.Code)
+
+	body := recorder.Body.String()
+	assert.True(t, strings.Contains(body, `core_database_pool_connections{database="mongodb",state="open"} 2`), body)
+	assert.True(t, strings.Contains(body, `core_database_pool_connections{database="postgres",state="in_use"} 1`), body)
+	assert.Contains(t, body, "go_goroutines")
+}
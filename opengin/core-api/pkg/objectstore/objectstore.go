@@ -0,0 +1,71 @@
+// Package objectstore keeps binary objects, such as the source documents attached to entities,
+// in a local directory or an S3-compatible bucket.
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrNotFound is returned when an object does not exist
+var ErrNotFound = errors.New("object not found")
+
+// Store writes, reads and deletes objects by key.
+// Keys are slash separated relative paths such as blobs/attr_e1_gazette/<sha256>.
+type Store interface {
+	// Put writes size bytes from content to the object, replacing any existing object
+	Put(ctx context.Context, key string, content io.Reader, size int64) error
+	// Get opens the object for reading. The caller must close the reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Backend names
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// Config selects and configures the backend
+type Config struct {
+	Backend     string // local or s3
+	Path        string // Root directory of the local backend
+	Endpoint    string // URL of the S3-compatible service, e.g. https://s3.eu-west-1.amazonaws.com or http://minio:9000
+	Region      string
+	Bucket      string
+	AccessKey   string
+	SecretKey   string
+	VirtualHost bool // Address the bucket as <bucket>.<host> instead of <host>/<bucket>
+}
+
+// New creates the store described by the configuration
+func New(config Config) (Store, error) {
+	switch strings.ToLower(config.Backend) {
+	case "", BackendLocal:
+		if config.Path == "" {
+			return nil, fmt.Errorf("object store path is not configured")
+		}
+		return NewLocalStore(config.Path)
+	case BackendS3:
+		return NewS3Store(config)
+	default:
+		return nil, fmt.Errorf("unknown object store backend %q", config.Backend)
+	}
+}
+
+// ValidateKey rejects keys that could escape the store root
+func ValidateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid object key %q", key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("invalid object key %q", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+// Package recovery turns a panic in an RPC handler into an Internal error for that call.
+//
+// Without it a single request that makes a handler panic, such as a malformed tabular file a
+// reader does not expect, takes the whole server down with every call in flight. The panic and
+// its stack are logged with the context of the call, so they carry its request ID.
+package recovery
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor recovers from panics in unary handlers
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				resp, err = nil, internal(ctx, info.FullMethod, p)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor recovers from panics in streaming handlers
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = internal(stream.Context(), info.FullMethod, p)
+			}
+		}()
+		return handler(srv, stream)
+	}
+}
+
+// internal logs a recovered panic and returns the error sent to the caller, which does not
+// include the panic value since it may hold request data
+func internal(ctx context.Context, method string, p interface{}) error {
+	slog.ErrorContext(ctx, "RPC handler panicked", "method", method, "panic", p, "stack", string(debug.Stack()))
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,67 @@
+package recovery
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// captureLogs sends the default logger to a buffer for the rest of the test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	logs := captureLogs(t)
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/core.COREService/CreateEntity"}
+
+	resp, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		var rows []int
+		return rows[3], nil
+	})
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, "internal error", status.Convert(err).Message())
+	assert.Contains(t, logs.String(), "RPC handler panicked")
+	assert.Contains(t, logs.String(), "/core.COREService/CreateEntity")
+	assert.Contains(t, logs.String(), "index out of range")
+
+	// Calls that do not panic pass through unchanged
+	resp, err = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "entity", status.Error(codes.NotFound, "not found")
+	})
+	assert.Equal(t, "entity", resp)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+type serverStream struct {
+	grpc.ServerStream
+}
+
+func (serverStream) Context() context.Context { return context.Background() }
+
+func TestStreamServerInterceptor(t *testing.T) {
+	captureLogs(t)
+	interceptor := StreamServerInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: "/core.COREService/ExportTabular"}
+
+	err := interceptor(nil, serverStream{}, info, func(srv interface{}, stream grpc.ServerStream) error {
+		panic("makeslice: len out of range")
+	})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	err = interceptor(nil, serverStream{}, info, func(srv interface{}, stream grpc.ServerStream) error { return nil })
+	require.NoError(t, err)
+}
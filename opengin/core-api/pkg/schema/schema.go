@@ -16,6 +16,7 @@ package schema
 
 import (
 	"fmt"
+	"log/slog"
 	"time"
 
 	"google.golang.org/protobuf/types/known/anypb"
@@ -179,12 +180,23 @@ import (
 //   - Fields: For tabular/graph data, contains schemas for each field
 //   - Items: For list data, contains the schema for list items
 //   - Properties: For map data, contains schemas for each property
+//   - Columns: For tabular data kept in a table, the columns in the order the producer gave them
 type SchemaInfo struct {
 	StorageType storageinference.StorageType // The storage type (tabular, graph, list, map, scalar)
 	TypeInfo    *typeinference.TypeInfo      // The type information
 	Fields      map[string]*SchemaInfo       // For tabular/graph data, contains field schemas
 	Items       *SchemaInfo                  // For list data, contains item schema
 	Properties  map[string]*SchemaInfo       // For map data, contains property schemas
+	Columns     []ColumnInfo                 `json:",omitempty"` // For stored tabular data, the column order and table columns
+}
+
+// ColumnInfo ties a column of tabular data to the table column it is stored in.
+// Column names given by the producer are kept as they are; the table column is derived from the
+// name but made unique and kept clear of the bookkeeping columns of the table.
+type ColumnInfo struct {
+	Name    string // The column name as the producer gave it
+	Column  string // The table column holding the values
+	Ordinal int    // The position of the column in the data, from 0
 }
 
 // SchemaGenerator combines storage and type inference to generate complete schema information.
@@ -324,7 +336,7 @@ func (sg *SchemaGenerator) GenerateSchema(anyValue *anypb.Any) (*SchemaInfo, err
 				TypeInfo:    &typeinference.TypeInfo{Type: typeinference.BoolType},
 			}, nil
 		default:
-			fmt.Printf("Schema generator hit default case with message type: %T, message: %+v\n", message, message)
+			slog.Debug("Schema generator hit default case", "message_type", fmt.Sprintf("%T", message))
 			return nil, fmt.Errorf("expected struct value or supported wrapper type, got %T", message)
 		}
 	}
@@ -486,12 +498,16 @@ func isDateOrDateTime(str string) (bool, bool) {
 //  1. Validates the presence of both columns and rows fields
 //  2. Verifies that columns is a list of strings
 //  3. Verifies that rows is a list of lists
-//  4. Processes the first row to determine column types
-//  5. Creates field schemas for each column based on its data type
+//  4. Samples up to TabularSampleRows rows to determine column types, skipping nulls and
+//     widening mixed types with typeinference.CommonType (ints and floats make a float column);
+//     a column is nullable when any row holds a null in it
+//  5. Sizes decimal columns to the digits of the values of all rows (see decimalSize)
+//  6. Creates field schemas for each column based on its data type
 //
 // The function handles the following data types:
-//   - String: Regular text or date/datetime values
-//   - Number: Integer or floating-point values
+//   - String: Regular text, date/datetime values, or decimal and bigint numbers
+//     (see typeinference.NumericStringType)
+//   - Number: Integer (int or bigint by magnitude) or floating-point values
 //   - Boolean: True/false values
 //   - Null: Nullable fields
 //
@@ -584,61 +600,112 @@ func (sg *SchemaGenerator) handleTabularData(structValue *structpb.Struct, schem
 		}
 	}
 
-	// Process first row to determine types
+	// Sample rows to determine types
 	if len(rowsList.ListValue.Values) == 0 {
 		return nil, fmt.Errorf("table must have at least one row")
 	}
 
-	firstRow := rowsList.ListValue.Values[0]
-	rowValues, ok := firstRow.GetKind().(*structpb.Value_ListValue)
-	if !ok {
-		return nil, fmt.Errorf("row must be a list")
+	types := make([]typeinference.DataType, len(columnNames))
+	for i := range types {
+		types[i] = typeinference.NullType
 	}
+	nullable := make([]bool, len(columnNames))
+	digits := make([]decimalDigits, len(columnNames))
+	for r, row := range rowsList.ListValue.Values {
+		rowValues, ok := row.GetKind().(*structpb.Value_ListValue)
+		if !ok {
+			return nil, fmt.Errorf("row must be a list")
+		}
+
+		if len(rowValues.ListValue.Values) != len(columnNames) {
+			return nil, fmt.Errorf("row length does not match number of columns")
+		}
 
-	if len(rowValues.ListValue.Values) != len(columnNames) {
-		return nil, fmt.Errorf("row length does not match number of columns")
+		for i, value := range rowValues.ListValue.Values {
+			if _, isNull := value.GetKind().(*structpb.Value_NullValue); isNull {
+				nullable[i] = true
+				continue
+			}
+			digits[i].add(value)
+			if r >= TabularSampleRows {
+				continue
+			}
+			dataType, err := cellType(value)
+			if err != nil {
+				return nil, err
+			}
+			types[i] = typeinference.CommonType(types[i], dataType)
+		}
 	}
 
-	// Create field schemas based on first row values
-	for i, value := range rowValues.ListValue.Values {
-		columnName := columnNames[i]
-		fieldSchema := &SchemaInfo{
+	// Create field schemas based on the sampled values
+	for i, columnName := range columnNames {
+		typeInfo := &typeinference.TypeInfo{
+			Type:       types[i],
+			IsNullable: nullable[i],
+		}
+		if types[i] == typeinference.DecimalType {
+			typeInfo.Precision, typeInfo.Scale = digits[i].size()
+		}
+		schema.Fields[columnName] = &SchemaInfo{
 			StorageType: storageinference.ScalarData,
-			TypeInfo:    &typeinference.TypeInfo{},
+			TypeInfo:    typeInfo,
 		}
+	}
 
-		switch value.GetKind().(type) {
-		case *structpb.Value_StringValue:
-			str := value.GetStringValue()
-			if isDate, isDateTime := isDateOrDateTime(str); isDate {
-				if isDateTime {
-					fieldSchema.TypeInfo.Type = typeinference.DateTimeType
-				} else {
-					fieldSchema.TypeInfo.Type = typeinference.DateType
-				}
-			} else {
-				fieldSchema.TypeInfo.Type = typeinference.StringType
-			}
-		case *structpb.Value_NumberValue:
-			num := value.GetNumberValue()
-			if num == float64(int64(num)) {
-				fieldSchema.TypeInfo.Type = typeinference.IntType
-			} else {
-				fieldSchema.TypeInfo.Type = typeinference.FloatType
+	return schema, nil
+}
+
+// TabularSampleRows is the number of rows of tabular data whose values determine the column types
+const TabularSampleRows = 1000
+
+// cellType returns the type of a value of a table row
+func cellType(value *structpb.Value) (typeinference.DataType, error) {
+	switch value.GetKind().(type) {
+	case *structpb.Value_StringValue:
+		str := value.GetStringValue()
+		if isDate, isDateTime := isDateOrDateTime(str); isDate {
+			if isDateTime {
+				return typeinference.DateTimeType, nil
 			}
-		case *structpb.Value_BoolValue:
-			fieldSchema.TypeInfo.Type = typeinference.BoolType
-		case *structpb.Value_NullValue:
-			fieldSchema.TypeInfo.Type = typeinference.NullType
-			fieldSchema.TypeInfo.IsNullable = true
-		default:
-			return nil, fmt.Errorf("unsupported value type in row: of type %v", value.GetKind())
+			return typeinference.DateType, nil
 		}
+		return typeinference.NumericStringType(str), nil
+	case *structpb.Value_NumberValue:
+		return typeinference.NumberType(value.GetNumberValue()), nil
+	case *structpb.Value_BoolValue:
+		return typeinference.BoolType, nil
+	case *structpb.Value_NullValue:
+		return typeinference.NullType, nil
+	default:
+		return "", fmt.Errorf("unsupported value type in row: of type %v", value.GetKind())
+	}
+}
+
+// decimalDigits tracks the most digits before and after the point of the numbers of a column
+type decimalDigits struct {
+	integer, scale int
+}
 
-		schema.Fields[columnName] = fieldSchema
+// add counts the digits of a number or numeric string; other values are skipped
+func (d *decimalDigits) add(value *structpb.Value) {
+	text, ok := typeinference.NumberText(value)
+	if !ok {
+		return
 	}
+	integer, scale, _ := typeinference.DecimalDigits(text)
+	d.integer, d.scale = max(d.integer, integer), max(d.scale, scale)
+}
 
-	return schema, nil
+// size returns the precision and scale of a decimal column holding the counted numbers. The
+// precision is at least typeinference.DecimalPrecision, leaving room for larger values added later,
+// and 0 (unconstrained) when the numbers need more than typeinference.MaxDecimalPrecision digits.
+func (d *decimalDigits) size() (precision, scale int) {
+	precision = max(d.integer+d.scale, typeinference.DecimalPrecision)
+	if precision > typeinference.MaxDecimalPrecision {
+		return 0, d.scale
+	}
+	return precision, d.scale
 }
 
 // handleGraphData processes graph data and generates schemas for nodes and edges.
@@ -555,6 +555,92 @@ func TestSchemaGeneration(t *testing.T) {
 				}
 			}`,
 		},
+		"tabular_data_with_sampled_types": {
+			input: `{
+				"columns": ["amount", "region", "issued", "note"],
+				"rows": [
+					[1200, null, "2024-01-15", null],
+					[800.5, "north", "2024-02-15T10:00:00Z", null]
+				]
+			}`,
+			expected: `{
+				"storage_type": "tabular",
+				"type_info": {
+					"type": "string"
+				},
+				"fields": {
+					"amount": {
+						"storage_type": "scalar",
+						"type_info": {
+							"type": "float"
+						}
+					},
+					"region": {
+						"storage_type": "scalar",
+						"type_info": {
+							"type": "string",
+							"is_nullable": true
+						}
+					},
+					"issued": {
+						"storage_type": "scalar",
+						"type_info": {
+							"type": "datetime"
+						}
+					},
+					"note": {
+						"storage_type": "scalar",
+						"type_info": {
+							"type": "null",
+							"is_nullable": true
+						}
+					}
+				}
+			}`,
+		},
+		"tabular_data_with_decimal_and_bigint_types": {
+			input: `{
+				"columns": ["budget", "rate", "account_id", "code"],
+				"rows": [
+					["1200.50", 0.125, 4102444800000, "007"],
+					["12345678901234567890123456789012345678901.5", 3, 42, "012"]
+				]
+			}`,
+			expected: `{
+				"storage_type": "tabular",
+				"type_info": {
+					"type": "string"
+				},
+				"fields": {
+					"budget": {
+						"storage_type": "scalar",
+						"type_info": {
+							"type": "decimal",
+							"precision": 43,
+							"scale": 2
+						}
+					},
+					"rate": {
+						"storage_type": "scalar",
+						"type_info": {
+							"type": "float"
+						}
+					},
+					"account_id": {
+						"storage_type": "scalar",
+						"type_info": {
+							"type": "bigint"
+						}
+					},
+					"code": {
+						"storage_type": "scalar",
+						"type_info": {
+							"type": "string"
+						}
+					}
+				}
+			}`,
+		},
 	}
 
 	generator := NewSchemaGenerator()
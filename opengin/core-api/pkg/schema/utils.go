@@ -3,7 +3,7 @@ package schema
 import (
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/structpb"
@@ -35,6 +35,8 @@ type TypeInfoJSON struct {
 	IsNullable bool          `json:"is_nullable,omitempty"`
 	IsArray    bool          `json:"is_array,omitempty"`
 	ArrayType  *TypeInfoJSON `json:"array_type,omitempty"`
+	Precision  int           `json:"precision,omitempty"`
+	Scale      int           `json:"scale,omitempty"`
 }
 
 // SchemaInfoToJSON converts a SchemaInfo to its JSON representation
@@ -91,6 +93,8 @@ func TypeInfoToJSON(typeInfo *typeinference.TypeInfo) *TypeInfoJSON {
 		Type:       string(typeInfo.Type),
 		IsNullable: typeInfo.IsNullable,
 		IsArray:    typeInfo.IsArray,
+		Precision:  typeInfo.Precision,
+		Scale:      typeInfo.Scale,
 	}
 
 	if typeInfo.ArrayType != nil {
@@ -399,28 +403,26 @@ func GenerateSchema(value *anypb.Any) (*SchemaInfo, error) {
 // logSchemaInfo logs schema information in a readable format
 func LogSchemaInfo(schemaInfo *SchemaInfo) {
 	if schemaInfo == nil {
-		log.Printf("Schema is nil")
+		slog.Debug("Schema is nil")
 		return
 	}
 
 	// Log the schema information
-	log.Printf("Schema: StorageType=%v, TypeInfo=%v",
-		schemaInfo.StorageType,
-		schemaInfo.TypeInfo)
+	slog.Debug("Schema", "storage_type", schemaInfo.StorageType, "type_info", schemaInfo.TypeInfo)
 
 	// Convert schema to JSON for logging
 	schemaJSON, err := SchemaInfoToJSON(schemaInfo)
 	if err != nil {
-		log.Printf("Failed to convert schema to JSON: %v", err)
+		slog.Warn("Failed to convert schema to JSON", "error", err)
 		return
 	}
 
 	// Marshal to pretty JSON for better readability
 	prettyJSON, err := json.MarshalIndent(schemaJSON, "", "  ")
 	if err != nil {
-		log.Printf("Failed to marshal schema to JSON: %v", err)
+		slog.Warn("Failed to marshal schema to JSON", "error", err)
 		return
 	}
 
-	log.Printf("Schema JSON:\n%s", string(prettyJSON))
+	slog.Debug("Schema JSON", "schema", string(prettyJSON))
 }
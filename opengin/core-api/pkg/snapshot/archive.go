@@ -0,0 +1,435 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"time"
+)
+
+// FormatVersion is the archive layout version written to the manifest.
+// Readers refuse archives with a newer version than they understand.
+// Version 2 archives start with the manifest; version 1 archives end with it and are still read.
+const FormatVersion = 2
+
+// ManifestName is the name of the manifest entry inside the archive
+const ManifestName = "manifest.json"
+
+// Manifest describes the content of a snapshot archive
+type Manifest struct {
+	FormatVersion int         `json:"formatVersion"`
+	CreatedAt     string      `json:"createdAt"`
+	Label         string      `json:"label,omitempty"`
+	Files         []FileEntry `json:"files"`
+}
+
+// FileEntry describes one JSONL data file in the archive
+type FileEntry struct {
+	Name    string `json:"name"`
+	Records int64  `json:"records"`
+	Bytes   int64  `json:"bytes"`
+	SHA256  string `json:"sha256"`
+}
+
+// File returns the manifest entry with the given name
+func (m *Manifest) File(name string) (FileEntry, bool) {
+	for _, entry := range m.Files {
+		if entry.Name == name {
+			return entry, true
+		}
+	}
+	return FileEntry{}, false
+}
+
+// Writer writes a gzip compressed tar archive of the manifest followed by JSONL files.
+// Tar entries need their size up front, so each file is spooled to a temporary file first,
+// and the files are spooled together until the manifest listing them has been written.
+type Writer struct {
+	out      io.Writer
+	body     *os.File    // The tar entries of the files closed so far
+	tw       *tar.Writer // Writes to body
+	manifest Manifest
+	open     *RecordWriter
+}
+
+// NewWriter creates an archive writer
+func NewWriter(w io.Writer, label string) *Writer {
+	return &Writer{
+		out: w,
+		manifest: Manifest{
+			FormatVersion: FormatVersion,
+			CreatedAt:     time.Now().UTC().Format(time.RFC3339),
+			Label:         label,
+		},
+	}
+}
+
+// Create starts a new JSONL file in the archive. Only one file can be open at a time.
+func (w *Writer) Create(name string) (*RecordWriter, error) {
+	if w.open != nil {
+		return nil, fmt.Errorf("file %s is still open", w.open.name)
+	}
+	if name == ManifestName {
+		return nil, fmt.Errorf("%s is reserved", ManifestName)
+	}
+	if _, exists := w.manifest.File(name); exists {
+		return nil, fmt.Errorf("file %s already exists in the archive", name)
+	}
+
+	if w.body == nil {
+		body, err := os.CreateTemp("", "snapshot-*.tar")
+		if err != nil {
+			return nil, fmt.Errorf("error creating spool file: %v", err)
+		}
+		w.body = body
+		w.tw = tar.NewWriter(body)
+	}
+
+	spool, err := os.CreateTemp("", "snapshot-*.jsonl")
+	if err != nil {
+		return nil, fmt.Errorf("error creating spool file: %v", err)
+	}
+
+	checksum := sha256.New()
+	record := &RecordWriter{
+		archive: w,
+		name:    name,
+		spool:   spool,
+		hash:    checksum,
+		buf:     bufio.NewWriter(io.MultiWriter(spool, checksum)),
+	}
+	w.open = record
+	return record, nil
+}
+
+// Close writes the manifest, then the files, and closes the archive
+func (w *Writer) Close() error {
+	if w.open != nil {
+		return fmt.Errorf("file %s is still open", w.open.name)
+	}
+	defer w.Abort()
+
+	data, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding manifest: %v", err)
+	}
+
+	gz := gzip.NewWriter(w.out)
+	tw := tar.NewWriter(gz)
+	header := &tar.Header{
+		Name:    ManifestName,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("error writing manifest: %v", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("error writing manifest: %v", err)
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("error writing manifest: %v", err)
+	}
+
+	// The spooled entries are complete tar entries without the end of archive, which tw adds on Close
+	if w.body != nil {
+		if err := w.tw.Flush(); err != nil {
+			return fmt.Errorf("error writing archive: %v", err)
+		}
+		if _, err := w.body.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("error reading spool: %v", err)
+		}
+		if _, err := io.Copy(gz, w.body); err != nil {
+			return fmt.Errorf("error writing archive: %v", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("error closing archive: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("error closing archive: %v", err)
+	}
+	return nil
+}
+
+// Abort removes the spooled files of an archive that is not closed. It does nothing after Close.
+func (w *Writer) Abort() {
+	if w.body != nil {
+		w.body.Close()
+		os.Remove(w.body.Name())
+		w.body = nil
+	}
+}
+
+// Manifest returns the manifest as written so far
+func (w *Writer) Manifest() Manifest {
+	return w.manifest
+}
+
+// RecordWriter writes one JSON record per line
+type RecordWriter struct {
+	archive *Writer
+	name    string
+	spool   *os.File
+	hash    hash.Hash
+	buf     *bufio.Writer
+	records int64
+	bytes   int64
+}
+
+// Write encodes a value as a single JSON line
+func (r *RecordWriter) Write(value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("error encoding record for %s: %v", r.name, err)
+	}
+	return r.WriteRaw(data)
+}
+
+// WriteRaw writes an already encoded JSON value as a single line
+func (r *RecordWriter) WriteRaw(data []byte) error {
+	if bytes.ContainsAny(data, "\n\r") {
+		// Compact to keep one record per line
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, data); err != nil {
+			return fmt.Errorf("error encoding record for %s: %v", r.name, err)
+		}
+		data = compacted.Bytes()
+	}
+
+	if _, err := r.buf.Write(data); err != nil {
+		return fmt.Errorf("error writing %s: %v", r.name, err)
+	}
+	if err := r.buf.WriteByte('\n'); err != nil {
+		return fmt.Errorf("error writing %s: %v", r.name, err)
+	}
+	r.records++
+	r.bytes += int64(len(data)) + 1
+	return nil
+}
+
+// Close adds the file to the archive and records it in the manifest
+func (r *RecordWriter) Close() error {
+	defer os.Remove(r.spool.Name())
+	defer r.spool.Close()
+
+	r.archive.open = nil
+
+	if err := r.buf.Flush(); err != nil {
+		return fmt.Errorf("error writing %s: %v", r.name, err)
+	}
+	if _, err := r.spool.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("error reading spool for %s: %v", r.name, err)
+	}
+
+	header := &tar.Header{
+		Name:    r.name,
+		Mode:    0644,
+		Size:    r.bytes,
+		ModTime: time.Now(),
+	}
+	if err := r.archive.tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("error adding %s to the archive: %v", r.name, err)
+	}
+	if _, err := io.Copy(r.archive.tw, r.spool); err != nil {
+		return fmt.Errorf("error adding %s to the archive: %v", r.name, err)
+	}
+
+	r.archive.manifest.Files = append(r.archive.manifest.Files, FileEntry{
+		Name:    r.name,
+		Records: r.records,
+		Bytes:   r.bytes,
+		SHA256:  hex.EncodeToString(r.hash.Sum(nil)),
+	})
+	return nil
+}
+
+// Reader reads a snapshot archive from disk
+type Reader struct {
+	path     string
+	Manifest *Manifest
+}
+
+// OpenReader opens an archive and loads its manifest
+func OpenReader(path string) (*Reader, error) {
+	reader := &Reader{path: path}
+
+	err := reader.walk(func(header *tar.Header, content io.Reader) (bool, error) {
+		if header.Name != ManifestName {
+			return true, nil
+		}
+		var manifest Manifest
+		if err := json.NewDecoder(content).Decode(&manifest); err != nil {
+			return false, fmt.Errorf("error parsing manifest: %v", err)
+		}
+		reader.Manifest = &manifest
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if reader.Manifest == nil {
+		return nil, fmt.Errorf("%s is not a snapshot archive: %s is missing", path, ManifestName)
+	}
+	if reader.Manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("snapshot format version %d is newer than the supported version %d", reader.Manifest.FormatVersion, FormatVersion)
+	}
+	return reader, nil
+}
+
+// File is a data file of the archive as Walk reaches it
+type File struct {
+	Name    string
+	entry   FileEntry
+	content io.Reader
+	read    bool
+}
+
+// Records returns the number of records the manifest lists for the file
+func (f *File) Records() int64 {
+	return f.entry.Records
+}
+
+// Each calls fn with every record of the file, then checks the record count and checksum against the manifest.
+// The records can only be read once.
+func (f *File) Each(fn func(record []byte) error) error {
+	if f.read {
+		return fmt.Errorf("%s has already been read", f.Name)
+	}
+	f.read = true
+
+	checksum := sha256.New()
+	scanner := bufio.NewScanner(io.TeeReader(f.content, checksum))
+	scanner.Buffer(make([]byte, 64*1024), 256*1024*1024)
+
+	var records int64
+	for scanner.Scan() {
+		records++
+		if err := fn(scanner.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading %s: %v", f.Name, err)
+	}
+
+	if records != f.entry.Records {
+		return fmt.Errorf("%s has %d records, manifest records %d", f.Name, records, f.entry.Records)
+	}
+	if sum := hex.EncodeToString(checksum.Sum(nil)); sum != f.entry.SHA256 {
+		return fmt.Errorf("%s checksum mismatch: got %s, manifest records %s", f.Name, sum, f.entry.SHA256)
+	}
+	return nil
+}
+
+// Walk reads the archive once, calling fn with every data file in the order they were written.
+// The files fn does not read are still checked against the manifest, as is that no file is missing.
+func (r *Reader) Walk(fn func(file *File) error) error {
+	seen := make(map[string]bool, len(r.Manifest.Files))
+	err := r.walk(func(header *tar.Header, content io.Reader) (bool, error) {
+		if header.Name == ManifestName {
+			return true, nil
+		}
+		entry, ok := r.Manifest.File(header.Name)
+		if !ok {
+			return false, fmt.Errorf("file %s is in the archive but not in the manifest", header.Name)
+		}
+		seen[header.Name] = true
+
+		file := &File{Name: header.Name, entry: entry, content: content}
+		if err := fn(file); err != nil {
+			return false, err
+		}
+		if !file.read {
+			if err := file.Each(func([]byte) error { return nil }); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range r.Manifest.Files {
+		if !seen[entry.Name] {
+			return fmt.Errorf("file %s is listed in the manifest but missing from the archive", entry.Name)
+		}
+	}
+	return nil
+}
+
+// Each calls fn with every record of a file, then checks the record count and checksum against the manifest.
+// It reads the archive up to the file, so use Walk to read several files.
+func (r *Reader) Each(name string, fn func(record []byte) error) error {
+	entry, ok := r.Manifest.File(name)
+	if !ok {
+		return fmt.Errorf("file %s is not in the snapshot", name)
+	}
+
+	found := false
+	err := r.walk(func(header *tar.Header, content io.Reader) (bool, error) {
+		if header.Name != name {
+			return true, nil
+		}
+		found = true
+		return false, (&File{Name: name, entry: entry, content: content}).Each(fn)
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("file %s is listed in the manifest but missing from the archive", name)
+	}
+	return nil
+}
+
+// Verify reads every file in one pass and checks it against the manifest
+func (r *Reader) Verify() error {
+	return r.Walk(func(*File) error { return nil })
+}
+
+// walk calls fn for each archive entry until fn returns false
+func (r *Reader) walk(fn func(header *tar.Header, content io.Reader) (bool, error)) error {
+	file, err := os.Open(r.path)
+	if err != nil {
+		return fmt.Errorf("error opening snapshot: %v", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("error reading snapshot: %v", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading snapshot: %v", err)
+		}
+
+		next, err := fn(header, tr)
+		if err != nil {
+			return err
+		}
+		if !next {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeTestArchive writes an archive with a few files and returns its path
+func writeTestArchive(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+	file, err := os.Create(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	writer := NewWriter(file, "staging")
+
+	entities, err := writer.Create(Neo4jEntitiesFile)
+	assert.NoError(t, err)
+	assert.NoError(t, entities.Write(GraphEntity{Id: "e1", Kind: "Person", Name: "Jane"}))
+	assert.NoError(t, entities.Write(GraphEntity{Id: "e2", Kind: "Person", Name: "John"}))
+
+	// Only one file can be open at a time
+	_, err = writer.Create(Neo4jRelationshipsFile)
+	assert.Error(t, err)
+	assert.NoError(t, entities.Close())
+
+	metadata, err := writer.Create(MongoCollectionFile("metadata"))
+	assert.NoError(t, err)
+	assert.NoError(t, metadata.WriteRaw([]byte("{\n  \"_id\": \"e1\"\n}")))
+	assert.NoError(t, metadata.Close())
+
+	rows, err := writer.Create(PostgresTableFile("attr_e1_budget"))
+	assert.NoError(t, err)
+	assert.NoError(t, rows.Close())
+
+	_, err = writer.Create(Neo4jEntitiesFile)
+	assert.Error(t, err, "Expected an error for a duplicate file")
+	_, err = writer.Create(ManifestName)
+	assert.Error(t, err)
+
+	assert.NoError(t, writer.Close())
+	return path
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	path := writeTestArchive(t)
+
+	reader, err := OpenReader(path)
+	assert.NoError(t, err)
+	assert.Equal(t, FormatVersion, reader.Manifest.FormatVersion)
+	assert.Equal(t, "staging", reader.Manifest.Label)
+	assert.Equal(t, 3, len(reader.Manifest.Files))
+	assert.Equal(t, []string{"metadata"}, reader.Manifest.MongoCollections())
+	assert.Equal(t, []string{"attr_e1_budget"}, reader.Manifest.PostgresTables())
+
+	entry, ok := reader.Manifest.File(Neo4jEntitiesFile)
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), entry.Records)
+
+	var entities []GraphEntity
+	err = reader.Each(Neo4jEntitiesFile, func(record []byte) error {
+		var entity GraphEntity
+		if err := json.Unmarshal(record, &entity); err != nil {
+			return err
+		}
+		entities = append(entities, entity)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(entities))
+	assert.Equal(t, "John", entities[1].Name)
+
+	// Multi-line records are compacted to a single line
+	var lines []string
+	err = reader.Each(MongoCollectionFile("metadata"), func(record []byte) error {
+		lines = append(lines, string(record))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`{"_id":"e1"}`}, lines)
+
+	assert.NoError(t, reader.Verify())
+
+	err = reader.Each("missing.jsonl", func([]byte) error { return nil })
+	assert.Error(t, err)
+}
+
+func TestArchiveDetectsTampering(t *testing.T) {
+	path := writeTestArchive(t)
+
+	reader, err := OpenReader(path)
+	assert.NoError(t, err)
+
+	// Pretend the manifest was written for different content
+	reader.Manifest.Files[0].SHA256 = "0000"
+	assert.Error(t, reader.Verify())
+
+	reader.Manifest.Files[0].Records = 5
+	assert.Error(t, reader.Each(reader.Manifest.Files[0].Name, func([]byte) error { return nil }))
+}
+
+func TestArchiveStartsWithManifest(t *testing.T) {
+	path := writeTestArchive(t)
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	assert.NoError(t, err)
+
+	var names []string
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, header.Name)
+	}
+	assert.Equal(t, []string{ManifestName, Neo4jEntitiesFile, MongoCollectionFile("metadata"), PostgresTableFile("attr_e1_budget")}, names)
+}
+
+func TestWalk(t *testing.T) {
+	path := writeTestArchive(t)
+	reader, err := OpenReader(path)
+	assert.NoError(t, err)
+
+	// Only the entities are read, the other files are checked without being read
+	var names []string
+	var entities int
+	err = reader.Walk(func(file *File) error {
+		names = append(names, file.Name)
+		if file.Name != Neo4jEntitiesFile {
+			return nil
+		}
+		assert.Equal(t, int64(2), file.Records())
+		if err := file.Each(func([]byte) error { entities++; return nil }); err != nil {
+			return err
+		}
+		assert.Error(t, file.Each(func([]byte) error { return nil }), "Expected an error reading a file twice")
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{Neo4jEntitiesFile, MongoCollectionFile("metadata"), PostgresTableFile("attr_e1_budget")}, names)
+	assert.Equal(t, 2, entities)
+
+	// A file fn leaves unread is still checked
+	reader.Manifest.Files[1].SHA256 = "0000"
+	assert.Error(t, reader.Walk(func(*File) error { return nil }))
+
+	// As is a file the manifest lists but the archive lacks
+	reader, err = OpenReader(path)
+	assert.NoError(t, err)
+	reader.Manifest.Files = append(reader.Manifest.Files, FileEntry{Name: "neo4j/missing.jsonl"})
+	assert.Error(t, reader.Walk(func(*File) error { return nil }))
+}
+
+func TestReadsVersion1Archive(t *testing.T) {
+	// Version 1 archives end with the manifest
+	data := []byte("{\"id\":\"e1\"}\n")
+	checksum := sha256.Sum256(data)
+	manifest, err := json.Marshal(Manifest{FormatVersion: 1, Files: []FileEntry{
+		{Name: Neo4jEntitiesFile, Records: 1, Bytes: int64(len(data)), SHA256: hex.EncodeToString(checksum[:])},
+	}})
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "v1.tar.gz")
+	file, err := os.Create(path)
+	assert.NoError(t, err)
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	for _, entry := range []struct {
+		name    string
+		content []byte
+	}{{Neo4jEntitiesFile, data}, {ManifestName, manifest}} {
+		assert.NoError(t, tw.WriteHeader(&tar.Header{Name: entry.name, Mode: 0644, Size: int64(len(entry.content))}))
+		_, err := tw.Write(entry.content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gz.Close())
+	assert.NoError(t, file.Close())
+
+	reader, err := OpenReader(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reader.Manifest.FormatVersion)
+	assert.NoError(t, reader.Verify())
+
+	var records []string
+	err = reader.Walk(func(file *File) error {
+		return file.Each(func(record []byte) error {
+			records = append(records, string(record))
+			return nil
+		})
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`{"id":"e1"}`}, records)
+}
+
+func TestOpenReaderRejectsOtherFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "not-a-snapshot.tar.gz")
+	assert.NoError(t, os.WriteFile(path, []byte("plain text"), 0644))
+
+	_, err := OpenReader(path)
+	assert.Error(t, err)
+}
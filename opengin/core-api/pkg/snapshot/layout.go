@@ -0,0 +1,84 @@
+package snapshot
+
+import "strings"
+
+// Names of the data files inside a snapshot archive
+const (
+	Neo4jEntitiesFile      = "neo4j/entities.jsonl"
+	Neo4jRelationshipsFile = "neo4j/relationships.jsonl"
+	PostgresTablesFile     = "postgres/tables.jsonl"
+	mongoPrefix            = "mongo/"
+	postgresPrefix         = "postgres/"
+	dataSuffix             = ".jsonl"
+)
+
+// GraphEntity is a Neo4j node as stored in a snapshot
+type GraphEntity struct {
+	Id         string `json:"id"`
+	Kind       string `json:"kind"`
+	MinorKind  string `json:"minorKind,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Created    string `json:"created,omitempty"`
+	Terminated string `json:"terminated,omitempty"`
+}
+
+// GraphRelationship is a Neo4j relationship as stored in a snapshot
+type GraphRelationship struct {
+	Id         string `json:"id"`
+	Name       string `json:"name"`
+	SourceId   string `json:"sourceId"`
+	SourceKind string `json:"sourceKind"`
+	TargetId   string `json:"targetId"`
+	TargetKind string `json:"targetKind"`
+	Created    string `json:"created,omitempty"`
+	Terminated string `json:"terminated,omitempty"`
+}
+
+// MongoCollectionFile returns the archive file holding a MongoDB collection
+func MongoCollectionFile(collection string) string {
+	return mongoPrefix + collection + dataSuffix
+}
+
+// PostgresTableFile returns the archive file holding the rows of a PostgreSQL table
+func PostgresTableFile(table string) string {
+	return postgresPrefix + "rows/" + table + dataSuffix
+}
+
+// ParseMongoCollectionFile returns the MongoDB collection an archive file holds, if it holds one
+func ParseMongoCollectionFile(name string) (string, bool) {
+	return baseName(name, mongoPrefix)
+}
+
+// ParsePostgresTableFile returns the PostgreSQL table whose rows an archive file holds, if it holds rows
+func ParsePostgresTableFile(name string) (string, bool) {
+	return baseName(name, postgresPrefix+"rows/")
+}
+
+// MongoCollections lists the MongoDB collections stored in the archive
+func (m *Manifest) MongoCollections() []string {
+	return m.namesWithPrefix(mongoPrefix)
+}
+
+// PostgresTables lists the PostgreSQL tables whose rows are stored in the archive
+func (m *Manifest) PostgresTables() []string {
+	return m.namesWithPrefix(postgresPrefix + "rows/")
+}
+
+// namesWithPrefix returns the base names of the data files under a prefix
+func (m *Manifest) namesWithPrefix(prefix string) []string {
+	var names []string
+	for _, entry := range m.Files {
+		if name, ok := baseName(entry.Name, prefix); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// baseName returns the name of a data file under a prefix without the prefix and suffix
+func baseName(name, prefix string) (string, bool) {
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, dataSuffix) {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(name, prefix), dataSuffix), true
+}
@@ -0,0 +1,202 @@
+package storageinference
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"lk/datafoundation/core-api/pkg/auth"
+	"lk/datafoundation/core-api/pkg/tabularfile"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Keys of the storage hint envelope.
+//
+// A producer that does not want the storage type to be guessed wraps the value:
+//
+//	{
+//	  "@storage": "map",
+//	  "@datasetKind": "Document",
+//	  "@value": {"population": 21000000}
+//	}
+//
+// "@storage" is required and must be one of tabular, graph, map, list, scalar or blob.
+// "@datasetKind" is optional and must match the kind of Dataset the storage type is kept in.
+// "@value" holds the data and must have the shape of the declared storage type.
+// "@access" is optional and restricts who may read the attribute or, for tables, some of its columns:
+// {"minRole": "ingester", "columns": {"salary": {"minRole": "admin", "mask": "redact"}}}.
+// Only admins may set a policy or replace a stored one; other writers may only repeat it.
+// A blob is given as {"content": "<base64>", "mimeType": "application/pdf", "fileName": "gazette.pdf"}
+// where only content is required.
+// "@source" is optional for tabular values and says that "@value" is a file given as base64
+// instead of columns and rows: {"format": "csv", "delimiter": ";", "headerRow": 2, "encoding": "windows-1252"},
+// {"format": "xlsx", "sheet": "2024"} or {"format": "parquet"}. The file is parsed and stored as its columns and rows.
+const (
+	HintStorageKey     = "@storage"
+	HintDatasetKindKey = "@datasetKind"
+	HintValueKey       = "@value"
+	HintAccessKey      = "@access"
+	HintSourceKey      = "@source"
+)
+
+// StorageHint is a storage type declared by the producer of a value
+type StorageHint struct {
+	StorageType StorageType
+	DatasetKind string             // Empty when not declared
+	Access      *auth.AccessPolicy // Nil when not declared
+	File        *tabularfile.Table // The parsed file when the value is given through @source, nil otherwise
+}
+
+// ParseStorageType converts a declared storage type name to a StorageType
+func ParseStorageType(name string) (StorageType, error) {
+	switch storageType := StorageType(strings.ToLower(strings.TrimSpace(name))); storageType {
+	case TabularData, GraphData, MapData, ListData, ScalarData, BlobData:
+		return storageType, nil
+	default:
+		return UnknownData, fmt.Errorf("unsupported storage type %q", name)
+	}
+}
+
+// UnwrapHint returns the declared hint and the wrapped value when the value is a storage hint envelope.
+// List and scalar values are returned in the {"value": ...} form the inference uses for them.
+// Values without an envelope are returned unchanged with a nil hint.
+func UnwrapHint(anyValue *anypb.Any) (*StorageHint, *anypb.Any, error) {
+	message, err := anyValue.UnmarshalNew()
+	if err != nil {
+		return nil, nil, err
+	}
+	structValue, ok := message.(*structpb.Struct)
+	if !ok {
+		return nil, anyValue, nil
+	}
+	storageField, ok := structValue.Fields[HintStorageKey]
+	if !ok {
+		return nil, anyValue, nil
+	}
+
+	for key := range structValue.Fields {
+		if key != HintStorageKey && key != HintDatasetKindKey && key != HintValueKey && key != HintAccessKey && key != HintSourceKey {
+			return nil, nil, fmt.Errorf("unexpected key %q in storage hint", key)
+		}
+	}
+
+	storageType, err := ParseStorageType(storageField.GetStringValue())
+	if err != nil {
+		return nil, nil, err
+	}
+	hint := &StorageHint{StorageType: storageType}
+
+	if datasetKind, ok := structValue.Fields[HintDatasetKindKey]; ok {
+		if _, isString := datasetKind.GetKind().(*structpb.Value_StringValue); !isString || datasetKind.GetStringValue() == "" {
+			return nil, nil, fmt.Errorf("%s must be a non-empty string", HintDatasetKindKey)
+		}
+		hint.DatasetKind = datasetKind.GetStringValue()
+	}
+
+	if access, ok := structValue.Fields[HintAccessKey]; ok {
+		if access.GetStructValue() == nil {
+			return nil, nil, fmt.Errorf("%s must be an object", HintAccessKey)
+		}
+		data, err := json.Marshal(access.GetStructValue().AsMap())
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid %s: %v", HintAccessKey, err)
+		}
+		if hint.Access, err = auth.ParseAccessPolicy(data); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	value, ok := structValue.Fields[HintValueKey]
+	if !ok {
+		return nil, nil, fmt.Errorf("storage hint has no %s", HintValueKey)
+	}
+
+	var data *structpb.Struct
+	if source, ok := structValue.Fields[HintSourceKey]; ok {
+		if storageType != TabularData {
+			return nil, nil, fmt.Errorf("%s is only supported for tabular values", HintSourceKey)
+		}
+		if hint.File, err = parseSource(source, value); err != nil {
+			return nil, nil, err
+		}
+		if data, err = hint.File.Struct(); err != nil {
+			return nil, nil, fmt.Errorf("invalid %s file: %v", hint.File.Format, err)
+		}
+	} else {
+		if err := checkShape(value, storageType); err != nil {
+			return nil, nil, fmt.Errorf("value does not match declared storage type %s: %v", storageType, err)
+		}
+		data = value.GetStructValue()
+	}
+	if storageType == ListData || storageType == ScalarData {
+		data = &structpb.Struct{Fields: map[string]*structpb.Value{"value": value}}
+	}
+
+	unwrapped, err := anypb.New(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to pack hinted value: %v", err)
+	}
+	return hint, unwrapped, nil
+}
+
+// parseSource reads a tabular file given as base64 with the options of its source
+func parseSource(source *structpb.Value, value *structpb.Value) (*tabularfile.Table, error) {
+	if source.GetStructValue() == nil {
+		return nil, fmt.Errorf("%s must be an object", HintSourceKey)
+	}
+	opts, err := tabularfile.ParseOptions(source.GetStructValue().AsMap())
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", HintSourceKey, err)
+	}
+	if _, isString := value.GetKind().(*structpb.Value_StringValue); !isString {
+		return nil, fmt.Errorf("%s must be the base64 content of the file when %s is given", HintValueKey, HintSourceKey)
+	}
+	content, err := base64.StdEncoding.DecodeString(value.GetStringValue())
+	if err != nil {
+		return nil, fmt.Errorf("file content is not valid base64: %v", err)
+	}
+	return tabularfile.Parse(content, opts)
+}
+
+// checkShape verifies that a hinted value has the structure its storage type requires
+func checkShape(value *structpb.Value, storageType StorageType) error {
+	switch storageType {
+	case BlobData:
+		structValue := value.GetStructValue()
+		if structValue == nil {
+			return fmt.Errorf("expected an object with base64 content")
+		}
+		content, ok := structValue.Fields["content"]
+		if !ok || content.GetKind() == nil {
+			return fmt.Errorf("expected a content field")
+		}
+		if _, err := base64.StdEncoding.DecodeString(content.GetStringValue()); err != nil {
+			return fmt.Errorf("content is not valid base64: %v", err)
+		}
+	case TabularData, GraphData, MapData:
+		structValue := value.GetStructValue()
+		if structValue == nil {
+			return fmt.Errorf("expected an object")
+		}
+		if storageType == TabularData && !isTabular(structValue) {
+			return fmt.Errorf("expected list fields columns and rows")
+		}
+		if storageType == GraphData && !isGraph(structValue) {
+			return fmt.Errorf("expected fields nodes and edges")
+		}
+	case ListData:
+		if value.GetListValue() == nil {
+			return fmt.Errorf("expected an array")
+		}
+	case ScalarData:
+		switch value.GetKind().(type) {
+		case *structpb.Value_NumberValue, *structpb.Value_StringValue, *structpb.Value_BoolValue, *structpb.Value_NullValue:
+		default:
+			return fmt.Errorf("expected a number, string, boolean or null")
+		}
+	}
+	return nil
+}
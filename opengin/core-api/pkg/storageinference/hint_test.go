@@ -0,0 +1,204 @@
+package storageinference
+
+import (
+	"testing"
+
+	"lk/datafoundation/core-api/pkg/auth"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestStorageHintOverridesInference(t *testing.T) {
+	inferrer := &StorageInferrer{}
+
+	tests := []struct {
+		name     string
+		json     string
+		inferred StorageType
+		declared StorageType
+	}{
+		{
+			name:     "one key map",
+			json:     `{"@storage": "map", "@value": {"population": 21000000}}`,
+			inferred: ScalarData,
+			declared: MapData,
+		},
+		{
+			name:     "map with columns and rows",
+			json:     `{"@storage": "map", "@value": {"columns": ["a"], "rows": [[1]]}}`,
+			inferred: TabularData,
+			declared: MapData,
+		},
+		{
+			name:     "single key list wrapper",
+			json:     `{"@storage": "map", "@datasetKind": "Document", "@value": {"members": ["a", "b"]}}`,
+			inferred: ListData,
+			declared: MapData,
+		},
+		{
+			name:     "declared list",
+			json:     `{"@storage": "list", "@value": [1, 2, 3]}`,
+			inferred: ListData,
+			declared: ListData,
+		},
+		{
+			name:     "declared scalar",
+			json:     `{"@storage": "Scalar", "@value": "Colombo"}`,
+			inferred: ScalarData,
+			declared: ScalarData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anyValue, err := JSONToAny(tt.json)
+			assert.NoError(t, err)
+
+			storageType, err := inferrer.InferType(anyValue)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.declared, storageType)
+
+			hint, unwrapped, err := UnwrapHint(anyValue)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.declared, hint.StorageType)
+
+			// Without the envelope the heuristics would have guessed differently
+			storageType, err = inferrer.InferType(unwrapped)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.inferred, storageType)
+		})
+	}
+}
+
+func TestBlobStorage(t *testing.T) {
+	inferrer := &StorageInferrer{}
+
+	anyValue, err := anypb.New(wrapperspb.Bytes([]byte("%PDF-1.4")))
+	assert.NoError(t, err)
+	storageType, err := inferrer.InferType(anyValue)
+	assert.NoError(t, err)
+	assert.Equal(t, BlobData, storageType)
+
+	anyValue, err = JSONToAny(`{"@storage": "blob", "@value": {"content": "JVBERi0xLjQ=", "mimeType": "application/pdf"}}`)
+	assert.NoError(t, err)
+	hint, unwrapped, err := UnwrapHint(anyValue)
+	assert.NoError(t, err)
+	assert.Equal(t, BlobData, hint.StorageType)
+
+	var data structpb.Struct
+	assert.NoError(t, unwrapped.UnmarshalTo(&data))
+	assert.Equal(t, "JVBERi0xLjQ=", data.Fields["content"].GetStringValue())
+}
+
+func TestUnwrapHintWithoutEnvelope(t *testing.T) {
+	anyValue, err := JSONToAny(`{"columns": ["a"], "rows": [[1]]}`)
+	assert.NoError(t, err)
+
+	hint, unwrapped, err := UnwrapHint(anyValue)
+	assert.NoError(t, err)
+	assert.Nil(t, hint)
+	assert.Same(t, anyValue, unwrapped)
+}
+
+func TestUnwrapHintListAndScalarForm(t *testing.T) {
+	anyValue, err := JSONToAny(`{"@storage": "list", "@value": ["x", "y"]}`)
+	assert.NoError(t, err)
+
+	_, unwrapped, err := UnwrapHint(anyValue)
+	assert.NoError(t, err)
+
+	var data structpb.Struct
+	assert.NoError(t, unwrapped.UnmarshalTo(&data))
+	assert.Equal(t, map[string]interface{}{"value": []interface{}{"x", "y"}}, data.AsMap())
+}
+
+func TestStorageHintRejectsMismatches(t *testing.T) {
+	inferrer := &StorageInferrer{}
+
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"tabular without rows", `{"@storage": "tabular", "@value": {"columns": ["a"]}}`},
+		{"graph without edges", `{"@storage": "graph", "@value": {"nodes": []}}`},
+		{"map from array", `{"@storage": "map", "@value": [1, 2]}`},
+		{"list from object", `{"@storage": "list", "@value": {"a": 1}}`},
+		{"scalar from object", `{"@storage": "scalar", "@value": {"a": 1}}`},
+		{"unknown storage type", `{"@storage": "video", "@value": "abc"}`},
+		{"blob without content", `{"@storage": "blob", "@value": {"mimeType": "application/pdf"}}`},
+		{"blob with invalid base64", `{"@storage": "blob", "@value": {"content": "not base64!"}}`},
+		{"missing value", `{"@storage": "map"}`},
+		{"unexpected key", `{"@storage": "map", "@value": {"a": 1}, "extra": true}`},
+		{"empty dataset kind", `{"@storage": "map", "@datasetKind": "", "@value": {"a": 1}}`},
+		{"access policy with unknown role", `{"@storage": "map", "@access": {"minRole": "root"}, "@value": {"a": 1}}`},
+		{"access policy with unknown mask", `{"@storage": "map", "@access": {"minRole": "admin", "mask": "hash"}, "@value": {"a": 1}}`},
+		{"access policy that is not an object", `{"@storage": "map", "@access": "admin", "@value": {"a": 1}}`},
+		{"source of a map", `{"@storage": "map", "@source": {"format": "csv"}, "@value": "YSxiCjEsMgo="}`},
+		{"source without format", `{"@storage": "tabular", "@source": {"delimiter": ";"}, "@value": "YSxiCjEsMgo="}`},
+		{"source with columns and rows", `{"@storage": "tabular", "@source": {"format": "csv"}, "@value": {"columns": ["a"], "rows": [[1]]}}`},
+		{"source with invalid base64", `{"@storage": "tabular", "@source": {"format": "csv"}, "@value": "not base64!"}`},
+		{"source that does not parse", `{"@storage": "tabular", "@source": {"format": "parquet"}, "@value": "YSxiCjEsMgo="}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anyValue, err := JSONToAny(tt.json)
+			assert.NoError(t, err)
+
+			_, _, err = UnwrapHint(anyValue)
+			assert.Error(t, err)
+
+			storageType, err := inferrer.InferType(anyValue)
+			assert.Error(t, err)
+			assert.Equal(t, UnknownData, storageType)
+		})
+	}
+}
+
+func TestUnwrapHintAccessPolicy(t *testing.T) {
+	anyValue, err := JSONToAny(`{"@storage": "tabular", "@access": {"minRole": "ingester", "columns": {"salary": {"minRole": "admin", "mask": "redact"}}},
+		"@value": {"columns": ["name", "salary"], "rows": [["a", 1]]}}`)
+	assert.NoError(t, err)
+
+	hint, _, err := UnwrapHint(anyValue)
+	assert.NoError(t, err)
+	assert.Equal(t, TabularData, hint.StorageType)
+	assert.Equal(t, auth.RoleIngester, hint.Access.MinRole)
+	assert.Equal(t, auth.Rule{MinRole: auth.RoleAdmin, Mask: auth.MaskRedact}, hint.Access.Columns["salary"])
+}
+
+func TestUnwrapHintSourceFile(t *testing.T) {
+	// "name,amount\nHealth,1200.5\nEducation,n/a\n" as base64
+	anyValue, err := JSONToAny(`{"@storage": "tabular", "@source": {"format": "csv", "sampleRows": 1},
+		"@value": "bmFtZSxhbW91bnQKSGVhbHRoLDEyMDAuNQpFZHVjYXRpb24sbi9hCg=="}`)
+	assert.NoError(t, err)
+
+	hint, unwrapped, err := UnwrapHint(anyValue)
+	assert.NoError(t, err)
+	assert.Equal(t, TabularData, hint.StorageType)
+	assert.Equal(t, 1, hint.File.FailedCellCount)
+
+	var data structpb.Struct
+	assert.NoError(t, unwrapped.UnmarshalTo(&data))
+	assert.Equal(t, map[string]interface{}{
+		"columns": []interface{}{"name", "amount"},
+		"rows":    []interface{}{[]interface{}{"Health", 1200.5}, []interface{}{"Education", nil}},
+	}, data.AsMap())
+
+	storageType, err := (&StorageInferrer{}).InferType(anyValue)
+	assert.NoError(t, err)
+	assert.Equal(t, TabularData, storageType)
+}
+
+func TestUnwrapHintIgnoresNonStructValues(t *testing.T) {
+	anyValue, err := anypb.New(structpb.NewStringValue("plain"))
+	assert.NoError(t, err)
+
+	hint, unwrapped, err := UnwrapHint(anyValue)
+	assert.NoError(t, err)
+	assert.Nil(t, hint)
+	assert.Same(t, anyValue, unwrapped)
+}
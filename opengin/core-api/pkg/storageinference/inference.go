@@ -5,6 +5,7 @@ import (
 
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 // StorageType represents the type of data structure
@@ -16,6 +17,7 @@ const (
 	ListData    StorageType = "list"
 	MapData     StorageType = "map"
 	GraphData   StorageType = "graph"
+	BlobData    StorageType = "blob"
 	UnknownData StorageType = "unknown"
 )
 
@@ -25,20 +27,25 @@ type StorageInferrer struct{}
 // InferType attempts to determine the storage type from a protobuf Any value.
 // The function follows a hierarchical approach to identify the storage type:
 //
+// 0. A storage type declared with a storage hint envelope (see HintStorageKey) is returned
+// as is, once the wrapped value has been checked against it. The heuristics below are skipped.
+//
 // 1. First, it unpacks the Any value to get the underlying message:
 //
 //   - Uses UnmarshalNew() to convert the Any value to its concrete type
 //
 //   - Handles any unmarshaling errors that might occur
 //
-// 2. For structpb.Struct messages:
+// 2. A wrapperspb.BytesValue message is BlobData
+//
+// 3. For structpb.Struct messages:
 //   - Checks for tabular structure (has both "columns" and "rows" fields)
 //   - Checks for graph structure (has both "nodes" and "edges" fields)
 //   - Checks for list structure (has "value" field with ListValue)
 //   - Checks for scalar structure (single field with scalar value)
 //   - If none of the above, defaults to MapData
 //
-// 3. For non-structpb.Struct messages:
+// 4. For non-structpb.Struct messages:
 //   - Uses reflection to determine the type:
 //   - Slice/Array types return ListData
 //   - Map types return MapData
@@ -50,6 +57,7 @@ type StorageInferrer struct{}
 // - ListData: For array-like data structures
 // - MapData: For key-value pair structures
 // - ScalarData: For single value data
+// - BlobData: For binary content such as PDFs and images
 //
 // Example JSON structures for each type:
 //
@@ -91,12 +99,26 @@ type StorageInferrer struct{}
 // For example, if a structure has both "items" and "nodes"/"edges",
 // it will be classified based on the more specific structure first.
 func (si *StorageInferrer) InferType(anyValue *anypb.Any) (StorageType, error) {
+	// A declared storage type is never reclassified
+	hint, _, err := UnwrapHint(anyValue)
+	if err != nil {
+		return UnknownData, err
+	}
+	if hint != nil {
+		return hint.StorageType, nil
+	}
+
 	// Unpack the Any value to get the underlying message
 	message, err := anyValue.UnmarshalNew()
 	if err != nil {
 		return UnknownData, err
 	}
 
+	// Raw bytes are kept as a blob
+	if _, isBytes := message.(*wrapperspb.BytesValue); isBytes {
+		return BlobData, nil
+	}
+
 	// Get the struct value from the message
 	structValue, ok := message.(*structpb.Struct)
 	if !ok {
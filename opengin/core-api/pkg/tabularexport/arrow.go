@@ -0,0 +1,327 @@
+package tabularexport
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+)
+
+// The Arrow IPC stream format is a schema message followed by one message per record batch and an
+// end-of-stream marker. Each message is a flatbuffer (Message.fbs and Schema.fbs of the Arrow format)
+// prefixed with a continuation marker and its length, followed by the buffers of the batch.
+
+const (
+	arrowMetadataV5 = 4
+
+	arrowHeaderSchema      = 1
+	arrowHeaderRecordBatch = 3
+
+	arrowTypeInt           = 2
+	arrowTypeFloatingPoint = 3
+	arrowTypeUtf8          = 5
+	arrowTypeBool          = 6
+	arrowTypeDecimal       = 7
+	arrowTypeDate          = 8
+	arrowTypeTimestamp     = 10
+
+	arrowPrecisionDouble = 2
+	arrowDateDay         = 0
+	arrowTimeMicrosecond = 2
+)
+
+// arrowContinuation starts every encapsulated message
+const arrowContinuation = 0xFFFFFFFF
+
+// WriteArrow writes the data as an Arrow IPC stream
+func WriteArrow(w io.Writer, data *pb.TabularData) error {
+	return Write(w, data, FormatArrow)
+}
+
+// writeArrowEnd ends the stream: a continuation marker followed by a zero length
+func writeArrowEnd(w io.Writer) error {
+	var eos [8]byte
+	binary.LittleEndian.PutUint32(eos[0:], arrowContinuation)
+	_, err := w.Write(eos[:])
+	return err
+}
+
+// writeArrowMessage encapsulates a message: marker, metadata length, metadata padded to 8 bytes, body
+func writeArrowMessage(w io.Writer, message *fbTable, body []byte) error {
+	metadata := buildFlatbuffer(message)
+	padded := make([]byte, align(len(metadata), 8))
+	copy(padded, metadata)
+
+	var prefix [8]byte
+	binary.LittleEndian.PutUint32(prefix[0:], arrowContinuation)
+	binary.LittleEndian.PutUint32(prefix[4:], uint32(len(padded)))
+	for _, part := range [][]byte{prefix[:], padded, body} {
+		if _, err := w.Write(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// arrowSchema builds the schema message of the columns
+func arrowSchema(columns []*pb.TabularColumn) *fbTable {
+	fields := make([]fbObject, len(columns))
+	for i, c := range columns {
+		typeID, typeTable := arrowType(c)
+		fields[i] = &fbTable{fields: []fbField{
+			fbChild(&fbString{value: c.Name}), // name
+			fbUint8(1),                        // nullable
+			fbUint8(typeID),                   // type_type
+			fbChild(typeTable),                // type
+			{},                                // dictionary
+			fbChild(&fbTableVector{}),         // children
+		}}
+	}
+
+	schema := &fbTable{fields: []fbField{
+		fbInt16(0), // endianness: little
+		fbChild(&fbTableVector{tables: fields}),
+	}}
+	return &fbTable{fields: []fbField{
+		fbInt16(arrowMetadataV5),   // version
+		fbUint8(arrowHeaderSchema), // header_type
+		fbChild(schema),            // header
+		fbInt64(0),                 // bodyLength
+	}}
+}
+
+// arrowType returns the Type union member of a column and its table
+func arrowType(c *pb.TabularColumn) (uint8, *fbTable) {
+	switch columnKind(c) {
+	case kindInt:
+		return arrowTypeInt, &fbTable{fields: []fbField{fbInt32(64), fbUint8(1)}} // bitWidth, is_signed
+	case kindFloat:
+		return arrowTypeFloatingPoint, &fbTable{fields: []fbField{fbInt16(arrowPrecisionDouble)}}
+	case kindBool:
+		return arrowTypeBool, &fbTable{}
+	case kindDate:
+		return arrowTypeDate, &fbTable{fields: []fbField{fbInt16(arrowDateDay)}}
+	case kindDecimal:
+		return arrowTypeDecimal, &fbTable{fields: []fbField{fbInt32(c.Precision), fbInt32(c.Scale), fbInt32(128)}} // precision, scale, bitWidth
+	case kindTimestamp:
+		return arrowTypeTimestamp, &fbTable{fields: []fbField{fbInt16(arrowTimeMicrosecond), fbChild(&fbString{value: "UTC"})}}
+	default:
+		return arrowTypeUtf8, &fbTable{}
+	}
+}
+
+// arrowRecordBatch builds the message and body of a record batch. Each column has a validity bitmap
+// and its values, strings an offsets buffer and the bytes of the values; every buffer starts on an
+// 8 byte boundary of the body.
+func arrowRecordBatch(columns []*column, length int) (*fbTable, []byte) {
+	var body []byte
+	var buffers, nodes []byte
+	addBuffer := func(buffer []byte) {
+		buffers = appendInt64s(buffers, int64(len(body)), int64(len(buffer)))
+		body = append(body, buffer...)
+		body = append(body, make([]byte, align(len(body), 8)-len(body))...)
+	}
+
+	for _, col := range columns {
+		nodes = appendInt64s(nodes, int64(length), int64(col.nulls))
+		addBuffer(bitmap(col.valid))
+
+		switch col.kind {
+		case kindInt, kindTimestamp:
+			values := make([]byte, 8*len(col.ints))
+			for i, v := range col.ints {
+				binary.LittleEndian.PutUint64(values[8*i:], uint64(v))
+			}
+			addBuffer(values)
+		case kindDate:
+			values := make([]byte, 4*len(col.ints))
+			for i, v := range col.ints {
+				binary.LittleEndian.PutUint32(values[4*i:], uint32(int32(v)))
+			}
+			addBuffer(values)
+		case kindFloat:
+			values := make([]byte, 8*len(col.floats))
+			for i, v := range col.floats {
+				binary.LittleEndian.PutUint64(values[8*i:], math.Float64bits(v))
+			}
+			addBuffer(values)
+		case kindBool:
+			addBuffer(bitmap(col.bools))
+		case kindDecimal:
+			// Arrow keeps decimals little-endian
+			values := make([]byte, 16*len(col.decimals))
+			for i, v := range col.decimals {
+				for j := range v {
+					values[16*i+j] = v[15-j]
+				}
+			}
+			addBuffer(values)
+		default:
+			offsets := make([]byte, 4*(len(col.strings)+1))
+			var values []byte
+			for i, v := range col.strings {
+				values = append(values, v...)
+				binary.LittleEndian.PutUint32(offsets[4*(i+1):], uint32(len(values)))
+			}
+			addBuffer(offsets)
+			addBuffer(values)
+		}
+	}
+
+	batch := &fbTable{fields: []fbField{
+		fbInt64(int64(length)),                            // length
+		fbChild(&fbStructVector{size: 16, data: nodes}),   // nodes
+		fbChild(&fbStructVector{size: 16, data: buffers}), // buffers
+	}}
+	return &fbTable{fields: []fbField{
+		fbInt16(arrowMetadataV5),
+		fbUint8(arrowHeaderRecordBatch),
+		fbChild(batch),
+		fbInt64(int64(len(body))),
+	}}, body
+}
+
+func appendInt64s(buf []byte, values ...int64) []byte {
+	for _, v := range values {
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(v))
+	}
+	return buf
+}
+
+func align(n, to int) int {
+	return (n + to - 1) / to * to
+}
+
+// The flatbuffers below are laid out front to back: the root offset, then every table followed by
+// the objects it refers to. Offsets to tables, vectors and strings therefore always point forward,
+// as the format requires, and each table's vtable is written just before it.
+
+// fbObject is a table, vector or string in a flatbuffer
+type fbObject interface {
+	// write appends the object to the buffer and returns the position offsets to it point at
+	write(buf []byte) ([]byte, int)
+}
+
+// fbField is a table field: an inline scalar of 1, 2, 4 or 8 bytes or an offset to a child object.
+// The zero value is an absent field.
+type fbField struct {
+	size  int
+	value uint64
+	child fbObject
+}
+
+func fbUint8(v uint8) fbField    { return fbField{size: 1, value: uint64(v)} }
+func fbInt16(v int16) fbField    { return fbField{size: 2, value: uint64(uint16(v))} }
+func fbInt32(v int32) fbField    { return fbField{size: 4, value: uint64(uint32(v))} }
+func fbInt64(v int64) fbField    { return fbField{size: 8, value: uint64(v)} }
+func fbChild(o fbObject) fbField { return fbField{size: 4, child: o} }
+
+// fbTable is a table whose fields are given by their slot in the schema
+type fbTable struct {
+	fields []fbField
+}
+
+// fbTableVector is a vector of tables
+type fbTableVector struct {
+	tables []fbObject
+}
+
+// fbStructVector is a vector of structs of the given size, already encoded
+type fbStructVector struct {
+	size int
+	data []byte
+}
+
+// fbString is a string
+type fbString struct {
+	value string
+}
+
+// buildFlatbuffer returns the flatbuffer whose root is the table
+func buildFlatbuffer(root *fbTable) []byte {
+	buf := make([]byte, 4)
+	buf, pos := root.write(buf)
+	binary.LittleEndian.PutUint32(buf[0:], uint32(pos))
+	return buf
+}
+
+func (t *fbTable) write(buf []byte) ([]byte, int) {
+	// Lay out the inline fields after the vtable offset, each aligned to its size
+	offsets := make([]int, len(t.fields))
+	size := 4
+	for i, field := range t.fields {
+		if field.size == 0 {
+			continue
+		}
+		size = align(size, field.size)
+		offsets[i] = size
+		size += field.size
+	}
+
+	vtable := align(len(buf), 2)
+	buf = append(buf, make([]byte, vtable-len(buf))...)
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(4+2*len(t.fields)))
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(size))
+	for _, offset := range offsets {
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(offset))
+	}
+
+	table := align(len(buf), 8)
+	buf = append(buf, make([]byte, table+size-len(buf))...)
+	binary.LittleEndian.PutUint32(buf[table:], uint32(int32(table-vtable)))
+	for i, field := range t.fields {
+		at := table + offsets[i]
+		switch {
+		case field.size == 0 || field.child != nil:
+		case field.size == 1:
+			buf[at] = byte(field.value)
+		case field.size == 2:
+			binary.LittleEndian.PutUint16(buf[at:], uint16(field.value))
+		case field.size == 4:
+			binary.LittleEndian.PutUint32(buf[at:], uint32(field.value))
+		default:
+			binary.LittleEndian.PutUint64(buf[at:], field.value)
+		}
+	}
+
+	for i, field := range t.fields {
+		if field.child == nil {
+			continue
+		}
+		var pos int
+		buf, pos = field.child.write(buf)
+		at := table + offsets[i]
+		binary.LittleEndian.PutUint32(buf[at:], uint32(pos-at))
+	}
+	return buf, table
+}
+
+func (v *fbTableVector) write(buf []byte) ([]byte, int) {
+	start := align(len(buf), 4)
+	buf = append(buf, make([]byte, start+4+4*len(v.tables)-len(buf))...)
+	binary.LittleEndian.PutUint32(buf[start:], uint32(len(v.tables)))
+	for i, table := range v.tables {
+		var pos int
+		buf, pos = table.write(buf)
+		at := start + 4 + 4*i
+		binary.LittleEndian.PutUint32(buf[at:], uint32(pos-at))
+	}
+	return buf, start
+}
+
+func (v *fbStructVector) write(buf []byte) ([]byte, int) {
+	// The length comes just before the first element, which is aligned to 8 bytes
+	start := align(len(buf)+4, 8) - 4
+	buf = append(buf, make([]byte, start-len(buf))...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(v.data)/v.size))
+	return append(buf, v.data...), start
+}
+
+func (s *fbString) write(buf []byte) ([]byte, int) {
+	start := align(len(buf), 4)
+	buf = append(buf, make([]byte, start-len(buf))...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(s.value)))
+	buf = append(buf, s.value...)
+	return append(buf, 0), start
+}
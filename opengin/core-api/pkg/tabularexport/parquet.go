@@ -0,0 +1,222 @@
+package tabularexport
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/internal/thrift"
+)
+
+// A Parquet file is the magic PAR1, the row groups, the file metadata and its length, and PAR1 again.
+// Each column of a row group is written as a single uncompressed data page: the definition levels
+// (0 for null, 1 for a value) followed by the values in PLAIN encoding. Page headers and the file
+// metadata are Thrift structs in the compact protocol (parquet.thrift of the Parquet format).
+
+const parquetMagic = "PAR1"
+
+// Physical types, repetition types, encodings and converted types of parquet.thrift
+const (
+	parquetBoolean        = 0
+	parquetInt32          = 1
+	parquetInt64          = 2
+	parquetDouble         = 5
+	parquetByteArray      = 6
+	parquetFixedByteArray = 7
+
+	parquetOptional = 1
+
+	parquetPlain = 0
+	parquetRLE   = 3
+
+	parquetUTF8            = 0
+	parquetDecimal         = 5
+	parquetDate            = 6
+	parquetTimestampMicros = 10
+)
+
+// parquetCreatedBy identifies the writer in the file metadata
+const parquetCreatedBy = "opengin core-api"
+
+// countingWriter tracks the offset of what is written, which the file metadata refers to
+type countingWriter struct {
+	w      io.Writer
+	offset int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.offset += int64(n)
+	return n, err
+}
+
+// WriteParquet writes the data as a Parquet file with one row group per batch of rows
+func WriteParquet(w io.Writer, data *pb.TabularData) error {
+	return Write(w, data, FormatParquet)
+}
+
+// writeParquetFooter writes the file metadata, listing the row groups written before it, and the end of the file
+func writeParquetFooter(out io.Writer, columns []*pb.TabularColumn, rows int, rowGroups []*thrift.Struct) error {
+	schema := []*thrift.Struct{(&thrift.Struct{}).
+		String(4, "schema").
+		I32(5, int32(len(columns)))}
+	for _, c := range columns {
+		schema = append(schema, parquetSchemaElement(c))
+	}
+	metadata := (&thrift.Struct{}).
+		I32(1, 1).
+		Structs(2, schema...).
+		I64(3, int64(rows)).
+		Structs(4, rowGroups...).
+		String(6, parquetCreatedBy).
+		Encode()
+
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(metadata)))
+	for _, part := range [][]byte{metadata, length[:], []byte(parquetMagic)} {
+		if _, err := out.Write(part); err != nil {
+			return fmt.Errorf("error writing Parquet footer: %v", err)
+		}
+	}
+	return nil
+}
+
+// parquetSchemaElement describes a column: its physical type and the logical type readers map it to
+func parquetSchemaElement(c *pb.TabularColumn) *thrift.Struct {
+	k := columnKind(c)
+	element := (&thrift.Struct{}).I32(1, parquetPhysicalType(k))
+	if k == kindDecimal {
+		element.I32(2, 16) // type_length
+	}
+	element.I32(3, parquetOptional).String(4, c.Name)
+	switch k {
+	case kindDecimal:
+		decimal := (&thrift.Struct{}).I32(1, c.Scale).I32(2, c.Precision)
+		element.I32(6, parquetDecimal).I32(7, c.Scale).I32(8, c.Precision).
+			Struct(10, (&thrift.Struct{}).Struct(5, decimal)) // LogicalType.DECIMAL
+	case kindDate:
+		element.I32(6, parquetDate).
+			Struct(10, (&thrift.Struct{}).Struct(6, &thrift.Struct{})) // LogicalType.DATE
+	case kindTimestamp:
+		unit := (&thrift.Struct{}).Struct(2, &thrift.Struct{}) // TimeUnit.MICROS
+		timestamp := (&thrift.Struct{}).Bool(1, true).Struct(2, unit)
+		element.I32(6, parquetTimestampMicros).
+			Struct(10, (&thrift.Struct{}).Struct(8, timestamp)) // LogicalType.TIMESTAMP
+	case kindString:
+		element.I32(6, parquetUTF8).
+			Struct(10, (&thrift.Struct{}).Struct(1, &thrift.Struct{})) // LogicalType.STRING
+	}
+	return element
+}
+
+func parquetPhysicalType(k kind) int32 {
+	switch k {
+	case kindInt, kindTimestamp:
+		return parquetInt64
+	case kindFloat:
+		return parquetDouble
+	case kindBool:
+		return parquetBoolean
+	case kindDate:
+		return parquetInt32
+	case kindDecimal:
+		return parquetFixedByteArray
+	default:
+		return parquetByteArray
+	}
+}
+
+// writeRowGroup writes a data page per column and returns the RowGroup metadata describing them
+func writeRowGroup(out *countingWriter, columns []*column, rows int) (*thrift.Struct, error) {
+	var chunks []*thrift.Struct
+	var total int64
+	for _, col := range columns {
+		page := parquetPage(col)
+		header := (&thrift.Struct{}).
+			I32(1, 0). // DATA_PAGE
+			I32(2, int32(len(page))).
+			I32(3, int32(len(page))).
+			Struct(5, (&thrift.Struct{}).
+				I32(1, int32(rows)).
+				I32(2, parquetPlain).
+				I32(3, parquetRLE).
+				I32(4, parquetRLE)).
+			Encode()
+
+		start := out.offset
+		if _, err := out.Write(header); err != nil {
+			return nil, err
+		}
+		if _, err := out.Write(page); err != nil {
+			return nil, err
+		}
+		size := out.offset - start
+		total += size
+
+		metadata := (&thrift.Struct{}).
+			I32(1, parquetPhysicalType(col.kind)).
+			I32s(2, parquetPlain, parquetRLE).
+			Strings(3, col.name).
+			I32(4, 0). // UNCOMPRESSED
+			I64(5, int64(rows)).
+			I64(6, size).
+			I64(7, size).
+			I64(9, start)
+		chunks = append(chunks, (&thrift.Struct{}).I64(2, start).Struct(3, metadata))
+	}
+	return (&thrift.Struct{}).Structs(1, chunks...).I64(2, total).I64(3, int64(rows)), nil
+}
+
+// parquetPage encodes the definition levels and the non-null values of a column
+func parquetPage(col *column) []byte {
+	// Definition levels as a single bit-packed run of the RLE/bit-packing hybrid, prefixed by its length
+	levels := binary.AppendUvarint(nil, uint64((len(col.valid)+7)/8)<<1|1)
+	levels = append(levels, bitmap(col.valid)...)
+	page := binary.LittleEndian.AppendUint32(nil, uint32(len(levels)))
+	page = append(page, levels...)
+
+	switch col.kind {
+	case kindInt, kindTimestamp:
+		for i, v := range col.ints {
+			if col.valid[i] {
+				page = binary.LittleEndian.AppendUint64(page, uint64(v))
+			}
+		}
+	case kindDate:
+		for i, v := range col.ints {
+			if col.valid[i] {
+				page = binary.LittleEndian.AppendUint32(page, uint32(int32(v)))
+			}
+		}
+	case kindFloat:
+		for i, v := range col.floats {
+			if col.valid[i] {
+				page = binary.LittleEndian.AppendUint64(page, math.Float64bits(v))
+			}
+		}
+	case kindBool:
+		var values []bool
+		for i, v := range col.bools {
+			if col.valid[i] {
+				values = append(values, v)
+			}
+		}
+		page = append(page, bitmap(values)...)
+	case kindDecimal:
+		for i, v := range col.decimals {
+			if col.valid[i] {
+				page = append(page, v[:]...)
+			}
+		}
+	default:
+		for i, v := range col.strings {
+			if col.valid[i] {
+				page = binary.LittleEndian.AppendUint32(page, uint32(len(v)))
+				page = append(page, v...)
+			}
+		}
+	}
+	return page
+}
@@ -0,0 +1,403 @@
+// Package tabularexport writes tabular attributes in the columnar formats data tools read directly:
+// the Apache Arrow IPC stream format and Apache Parquet files.
+//
+// Both writers take the typed form of a tabular read (pb.TabularData) and map its column types to
+// the closest native type, so pandas, polars or DuckDB get integers, floats, decimals, booleans,
+// dates and timestamps without parsing text:
+//
+//	int, bigint  Arrow Int64               Parquet INT64
+//	float        Arrow Float64             Parquet DOUBLE
+//	decimal      Arrow Decimal128(p, s)    Parquet FIXED_LEN_BYTE_ARRAY(16) (DECIMAL(p, s))
+//	bool         Arrow Bool                Parquet BOOLEAN
+//	date         Arrow Date32              Parquet INT32 (DATE)
+//	datetime     Arrow Timestamp(us, UTC)  Parquet INT64 (TIMESTAMP micros, UTC)
+//	other        Arrow Utf8                Parquet BYTE_ARRAY (STRING)
+//
+// Decimal columns with a precision above 38 digits, or none, do not fit 128 bits and are written as
+// text, like other columns.
+//
+// Every column is nullable. Rows are written in batches of BatchRows: one Arrow record batch or one
+// Parquet row group each, so readers can start on the first batch before the last one is written.
+// A Writer takes the rows a batch at a time, so a table can be exported without holding all of it.
+package tabularexport
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/internal/thrift"
+	"lk/datafoundation/core-api/pkg/typeinference"
+)
+
+// Format represents a supported tabular export format
+type Format string
+
+// Supported export formats
+const (
+	FormatArrow   Format = "arrow"
+	FormatParquet Format = "parquet"
+)
+
+// BatchRows is the number of rows in an Arrow record batch or a Parquet row group
+const BatchRows = 65536
+
+// ParseFormat converts a format name into a Format, accepting a few common aliases
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "arrow", "arrows", "ipc", "arrow-stream":
+		return FormatArrow, nil
+	case "parquet", "pq":
+		return FormatParquet, nil
+	default:
+		return "", fmt.Errorf("unsupported tabular export format: %s", name)
+	}
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatArrow:
+		return "application/vnd.apache.arrow.stream"
+	case FormatParquet:
+		return "application/vnd.apache.parquet"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// FileExtension returns the conventional file extension of the format, including the dot
+func (f Format) FileExtension() string {
+	switch f {
+	case FormatArrow:
+		return ".arrows"
+	case FormatParquet:
+		return ".parquet"
+	default:
+		return ""
+	}
+}
+
+// Write serialises the tabular data to w in the given format
+func Write(w io.Writer, data *pb.TabularData, format Format) error {
+	if data == nil {
+		return fmt.Errorf("tabular data cannot be nil")
+	}
+	writer, err := NewWriter(w, data.Columns, format)
+	if err != nil {
+		return err
+	}
+	if err := writer.WriteRows(data.Rows); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// Writer writes a table in batches: NewWriter writes what comes before the rows, WriteRows the rows
+// and Close what comes after them, the end of the Arrow stream or the Parquet footer
+type Writer struct {
+	out       *countingWriter
+	format    Format
+	columns   []*pb.TabularColumn
+	rows      int              // Rows written so far
+	batches   int              // Batches written so far
+	rowGroups []*thrift.Struct // Parquet row groups written so far, listed in the footer
+}
+
+// NewWriter starts writing a table with the given columns to w in the given format
+func NewWriter(w io.Writer, columns []*pb.TabularColumn, format Format) (*Writer, error) {
+	writer := &Writer{out: &countingWriter{w: w}, format: format, columns: columns}
+	switch format {
+	case FormatArrow:
+		if err := writeArrowMessage(writer.out, arrowSchema(columns), nil); err != nil {
+			return nil, fmt.Errorf("error writing Arrow schema: %v", err)
+		}
+	case FormatParquet:
+		if _, err := io.WriteString(writer.out, parquetMagic); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported tabular export format: %s", format)
+	}
+	return writer, nil
+}
+
+// WriteRows writes rows as one Arrow record batch or Parquet row group per BatchRows of them.
+// Callers paging through a table pass pages of BatchRows rows, so each page is one batch.
+func (w *Writer) WriteRows(rows []*pb.TabularRow) error {
+	for start := 0; start < len(rows); start += BatchRows {
+		if err := w.writeBatch(rows[start:min(start+BatchRows, len(rows))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Writer) writeBatch(rows []*pb.TabularRow) error {
+	columns, err := newColumns(w.columns, rows, w.rows)
+	if err != nil {
+		return err
+	}
+	switch w.format {
+	case FormatArrow:
+		message, body := arrowRecordBatch(columns, len(rows))
+		if err := writeArrowMessage(w.out, message, body); err != nil {
+			return fmt.Errorf("error writing Arrow record batch: %v", err)
+		}
+	case FormatParquet:
+		rowGroup, err := writeRowGroup(w.out, columns, len(rows))
+		if err != nil {
+			return fmt.Errorf("error writing Parquet row group: %v", err)
+		}
+		w.rowGroups = append(w.rowGroups, rowGroup)
+	}
+	w.rows += len(rows)
+	w.batches++
+	return nil
+}
+
+// Close finishes the file. An Arrow stream without rows gets one empty record batch, while a
+// Parquet file without rows has no row groups.
+func (w *Writer) Close() error {
+	switch w.format {
+	case FormatArrow:
+		if w.batches == 0 {
+			if err := w.writeBatch(nil); err != nil {
+				return err
+			}
+		}
+		return writeArrowEnd(w.out)
+	case FormatParquet:
+		return writeParquetFooter(w.out, w.columns, w.rows, w.rowGroups)
+	default:
+		return fmt.Errorf("unsupported tabular export format: %s", w.format)
+	}
+}
+
+// kind is the physical representation of an exported column
+type kind int
+
+const (
+	kindString kind = iota
+	kindInt
+	kindFloat
+	kindBool
+	kindDate
+	kindTimestamp
+	kindDecimal
+)
+
+// decimal128Digits is the largest precision a 128-bit decimal holds
+const decimal128Digits = 38
+
+// columnKind maps a stored column to the representation it is exported in
+func columnKind(c *pb.TabularColumn) kind {
+	switch typeinference.DataType(c.Type) {
+	case typeinference.IntType, typeinference.BigIntType:
+		return kindInt
+	case typeinference.FloatType:
+		return kindFloat
+	case typeinference.DecimalType:
+		if c.Precision > 0 && c.Precision <= decimal128Digits {
+			return kindDecimal
+		}
+		return kindString
+	case typeinference.BoolType:
+		return kindBool
+	case typeinference.DateType:
+		return kindDate
+	case typeinference.DateTimeType:
+		return kindTimestamp
+	default:
+		return kindString
+	}
+}
+
+// column holds one batch of a column, its cells converted to the Go type of its kind. Dates are
+// days since the epoch and timestamps microseconds since the epoch, both kept in ints. Decimals are
+// kept as their 128-bit two's complement, big-endian, of the value times 10^scale.
+type column struct {
+	name     string
+	kind     kind
+	scale    int
+	digits   *big.Int // 10^precision, the bound of the scaled decimals
+	valid    []bool
+	nulls    int
+	ints     []int64
+	floats   []float64
+	bools    []bool
+	strings  []string
+	decimals [][16]byte
+}
+
+// newColumns converts a batch of rows into columns; offset is the number of rows before the batch
+func newColumns(tableColumns []*pb.TabularColumn, rows []*pb.TabularRow, offset int) ([]*column, error) {
+	columns := make([]*column, len(tableColumns))
+	for i, c := range tableColumns {
+		columns[i] = &column{name: c.Name, kind: columnKind(c), valid: make([]bool, len(rows))}
+		if columns[i].kind == kindDecimal {
+			columns[i].scale = int(c.Scale)
+			columns[i].digits = new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(c.Precision)), nil)
+		}
+	}
+
+	for r, row := range rows {
+		if len(row.Values) != len(columns) {
+			return nil, fmt.Errorf("row %d has %d values for %d columns", offset+r, len(row.Values), len(columns))
+		}
+		for i, col := range columns {
+			if err := col.append(r, row.Values[i]); err != nil {
+				return nil, fmt.Errorf("row %d, column %s: %v", offset+r, col.name, err)
+			}
+		}
+	}
+	return columns, nil
+}
+
+// append converts a cell and adds it as row r of the column
+func (c *column) append(r int, value *pb.TabularValue) error {
+	valid := value != nil && value.Value != nil
+	c.valid[r] = valid
+	if !valid {
+		c.nulls++
+	}
+
+	switch c.kind {
+	case kindInt:
+		var v int64
+		if valid {
+			switch cell := value.Value.(type) {
+			case *pb.TabularValue_IntValue:
+				v = cell.IntValue
+			case *pb.TabularValue_FloatValue:
+				if cell.FloatValue != math.Trunc(cell.FloatValue) {
+					return fmt.Errorf("%v is not an integer", cell.FloatValue)
+				}
+				v = int64(cell.FloatValue)
+			default:
+				return fmt.Errorf("%s is not an integer", cellText(value))
+			}
+		}
+		c.ints = append(c.ints, v)
+	case kindFloat:
+		var v float64
+		if valid {
+			switch cell := value.Value.(type) {
+			case *pb.TabularValue_FloatValue:
+				v = cell.FloatValue
+			case *pb.TabularValue_IntValue:
+				v = float64(cell.IntValue)
+			default:
+				return fmt.Errorf("%s is not a number", cellText(value))
+			}
+		}
+		c.floats = append(c.floats, v)
+	case kindBool:
+		var v bool
+		if valid {
+			cell, ok := value.Value.(*pb.TabularValue_BoolValue)
+			if !ok {
+				return fmt.Errorf("%s is not a boolean", cellText(value))
+			}
+			v = cell.BoolValue
+		}
+		c.bools = append(c.bools, v)
+	case kindDate, kindTimestamp:
+		var v int64
+		if valid {
+			cell, ok := value.Value.(*pb.TabularValue_TimeValue)
+			if !ok {
+				return fmt.Errorf("%s is not a date or timestamp", cellText(value))
+			}
+			t := cell.TimeValue.AsTime()
+			if c.kind == kindDate {
+				v = floorDiv(t.Unix(), 86400)
+			} else {
+				v = t.UnixMicro()
+			}
+		}
+		c.ints = append(c.ints, v)
+	case kindDecimal:
+		var v [16]byte
+		if valid {
+			var err error
+			if v, err = c.decimal128(value); err != nil {
+				return err
+			}
+		}
+		c.decimals = append(c.decimals, v)
+	default:
+		var v string
+		if valid {
+			v = cellText(value)
+		}
+		c.strings = append(c.strings, v)
+	}
+	return nil
+}
+
+// decimal128 converts a number to the unscaled 128-bit value of a decimal of the column's scale,
+// refusing numbers the column would have to round or that have more digits than its precision
+func (c *column) decimal128(value *pb.TabularValue) ([16]byte, error) {
+	var out [16]byte
+	r, ok := new(big.Rat).SetString(cellText(value))
+	if !ok {
+		return out, fmt.Errorf("%s is not a number", cellText(value))
+	}
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(c.scale)), nil)))
+	if !r.IsInt() || new(big.Int).Abs(r.Num()).Cmp(c.digits) >= 0 {
+		return out, fmt.Errorf("%s does not fit the precision and scale of the column", cellText(value))
+	}
+
+	// Negative values wrap around 2^128
+	unscaled := r.Num()
+	if unscaled.Sign() < 0 {
+		unscaled = new(big.Int).Add(unscaled, new(big.Int).Lsh(big.NewInt(1), 128))
+	}
+	unscaled.FillBytes(out[:])
+	return out, nil
+}
+
+// cellText renders a cell as text, the way a string column holds values of any type
+func cellText(value *pb.TabularValue) string {
+	switch cell := value.GetValue().(type) {
+	case *pb.TabularValue_StringValue:
+		return cell.StringValue
+	case *pb.TabularValue_DecimalValue:
+		return cell.DecimalValue
+	case *pb.TabularValue_IntValue:
+		return strconv.FormatInt(cell.IntValue, 10)
+	case *pb.TabularValue_FloatValue:
+		return strconv.FormatFloat(cell.FloatValue, 'f', -1, 64)
+	case *pb.TabularValue_BoolValue:
+		return strconv.FormatBool(cell.BoolValue)
+	case *pb.TabularValue_TimeValue:
+		return cell.TimeValue.AsTime().UTC().Format(time.RFC3339Nano)
+	default:
+		return ""
+	}
+}
+
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// bitmap packs flags into a least significant bit first bitmap, as both Arrow and Parquet use
+func bitmap(flags []bool) []byte {
+	out := make([]byte, (len(flags)+7)/8)
+	for i, flag := range flags {
+		if flag {
+			out[i/8] |= 1 << (i % 8)
+		}
+	}
+	return out
+}
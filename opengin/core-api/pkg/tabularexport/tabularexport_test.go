@@ -0,0 +1,447 @@
+package tabularexport
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/internal/thrift"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// sampleData returns typed tabular data with a column of every kind and a null in each
+func sampleData() *pb.TabularData {
+	cell := func(v interface{}) *pb.TabularValue {
+		switch v := v.(type) {
+		case int:
+			return &pb.TabularValue{Value: &pb.TabularValue_IntValue{IntValue: int64(v)}}
+		case float64:
+			return &pb.TabularValue{Value: &pb.TabularValue_FloatValue{FloatValue: v}}
+		case bool:
+			return &pb.TabularValue{Value: &pb.TabularValue_BoolValue{BoolValue: v}}
+		case string:
+			return &pb.TabularValue{Value: &pb.TabularValue_StringValue{StringValue: v}}
+		case time.Time:
+			return &pb.TabularValue{Value: &pb.TabularValue_TimeValue{TimeValue: timestamppb.New(v)}}
+		default:
+			return &pb.TabularValue{}
+		}
+	}
+	row := func(values ...interface{}) *pb.TabularRow {
+		r := &pb.TabularRow{}
+		for _, v := range values {
+			r.Values = append(r.Values, cell(v))
+		}
+		return r
+	}
+
+	return &pb.TabularData{
+		Columns: []*pb.TabularColumn{
+			{Name: "year", Type: "int"},
+			{Name: "amount", Type: "float"},
+			{Name: "approved", Type: "bool"},
+			{Name: "day", Type: "date"},
+			{Name: "issued", Type: "datetime"},
+			{Name: "department", Type: "string"},
+		},
+		Rows: []*pb.TabularRow{
+			row(2024, 1200.5, true, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC), "Health"),
+			row(nil, 800, false, nil, nil, "Education"),
+			row(2025, nil, nil, time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), nil),
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	testCases := map[string]Format{
+		"":         FormatArrow,
+		"arrow":    FormatArrow,
+		" IPC ":    FormatArrow,
+		"parquet":  FormatParquet,
+		"Parquet ": FormatParquet,
+	}
+	for name, want := range testCases {
+		got, err := ParseFormat(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, want, got, name)
+	}
+
+	_, err := ParseFormat("csv")
+	assert.Error(t, err)
+}
+
+func TestWriteRejectsMismatchedCells(t *testing.T) {
+	data := &pb.TabularData{
+		Columns: []*pb.TabularColumn{{Name: "year", Type: "int"}},
+		Rows:    []*pb.TabularRow{{Values: []*pb.TabularValue{{Value: &pb.TabularValue_StringValue{StringValue: "soon"}}}}},
+	}
+	for _, format := range []Format{FormatArrow, FormatParquet} {
+		err := Write(&bytes.Buffer{}, data, format)
+		assert.ErrorContains(t, err, "row 0, column year: soon is not an integer", format)
+	}
+}
+
+// fbReader reads the tables of a flatbuffer
+type fbReader []byte
+
+func (b fbReader) root() int { return int(binary.LittleEndian.Uint32(b)) }
+
+// field returns the position of a table field, or 0 when it is absent
+func (b fbReader) field(table, slot int) int {
+	vtable := table - int(int32(binary.LittleEndian.Uint32(b[table:])))
+	if 4+2*slot >= int(binary.LittleEndian.Uint16(b[vtable:])) {
+		return 0
+	}
+	offset := int(binary.LittleEndian.Uint16(b[vtable+4+2*slot:]))
+	if offset == 0 {
+		return 0
+	}
+	return table + offset
+}
+
+func (b fbReader) deref(pos int) int { return pos + int(binary.LittleEndian.Uint32(b[pos:])) }
+func (b fbReader) u8(table, slot int) int {
+	return int(b[b.field(table, slot)])
+}
+func (b fbReader) i16(table, slot int) int {
+	return int(int16(binary.LittleEndian.Uint16(b[b.field(table, slot):])))
+}
+func (b fbReader) i64(table, slot int) int64 {
+	return int64(binary.LittleEndian.Uint64(b[b.field(table, slot):]))
+}
+func (b fbReader) child(table, slot int) int { return b.deref(b.field(table, slot)) }
+func (b fbReader) vector(table, slot int) (int, int) {
+	vector := b.child(table, slot)
+	return int(binary.LittleEndian.Uint32(b[vector:])), vector + 4
+}
+func (b fbReader) str(table, slot int) string {
+	n, start := b.vector(table, slot)
+	return string(b[start : start+n])
+}
+
+// arrowMessage reads an encapsulated message and returns its Message table and body
+func readArrowMessage(t *testing.T, stream []byte) (fbReader, int, []byte, []byte) {
+	require.Equal(t, uint32(arrowContinuation), binary.LittleEndian.Uint32(stream))
+	length := int(binary.LittleEndian.Uint32(stream[4:]))
+	require.Zero(t, length%8, "metadata is padded to 8 bytes")
+	metadata := fbReader(stream[8 : 8+length])
+	message := metadata.root()
+	require.Zero(t, message%8, "tables are aligned")
+	bodyLength := int(metadata.i64(message, 3))
+	body := stream[8+length : 8+length+bodyLength]
+	return metadata, message, body, stream[8+length+bodyLength:]
+}
+
+func TestWriteArrow(t *testing.T) {
+	data := sampleData()
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, data, FormatArrow))
+
+	// Schema
+	schemaMessage, message, body, rest := readArrowMessage(t, buf.Bytes())
+	assert.Equal(t, arrowMetadataV5, schemaMessage.i16(message, 0))
+	require.Equal(t, arrowHeaderSchema, schemaMessage.u8(message, 1))
+	assert.Empty(t, body)
+	schema := schemaMessage.child(message, 2)
+	n, fields := schemaMessage.vector(schema, 1)
+	require.Equal(t, len(data.Columns), n)
+
+	wantTypes := []int{arrowTypeInt, arrowTypeFloatingPoint, arrowTypeBool, arrowTypeDate, arrowTypeTimestamp, arrowTypeUtf8}
+	for i := 0; i < n; i++ {
+		field := schemaMessage.deref(fields + 4*i)
+		assert.Equal(t, data.Columns[i].Name, schemaMessage.str(field, 0))
+		assert.Equal(t, 1, schemaMessage.u8(field, 1), "nullable")
+		assert.Equal(t, wantTypes[i], schemaMessage.u8(field, 2))
+		children, _ := schemaMessage.vector(field, 5)
+		assert.Zero(t, children)
+
+		typeTable := schemaMessage.child(field, 3)
+		switch wantTypes[i] {
+		case arrowTypeInt:
+			assert.Equal(t, uint32(64), binary.LittleEndian.Uint32(schemaMessage[schemaMessage.field(typeTable, 0):]))
+			assert.Equal(t, 1, schemaMessage.u8(typeTable, 1))
+		case arrowTypeFloatingPoint:
+			assert.Equal(t, arrowPrecisionDouble, schemaMessage.i16(typeTable, 0))
+		case arrowTypeDate:
+			assert.Equal(t, arrowDateDay, schemaMessage.i16(typeTable, 0))
+		case arrowTypeTimestamp:
+			assert.Equal(t, arrowTimeMicrosecond, schemaMessage.i16(typeTable, 0))
+			assert.Equal(t, "UTC", schemaMessage.str(typeTable, 1))
+		}
+	}
+
+	// Record batch
+	batchMessage, message, body, rest := readArrowMessage(t, rest)
+	require.Equal(t, arrowHeaderRecordBatch, batchMessage.u8(message, 1))
+	batch := batchMessage.child(message, 2)
+	assert.Equal(t, int64(3), batchMessage.i64(batch, 0))
+
+	n, nodes := batchMessage.vector(batch, 1)
+	require.Equal(t, len(data.Columns), n)
+	require.Zero(t, nodes%8, "structs are aligned")
+	var nullCounts []int64
+	for i := 0; i < n; i++ {
+		assert.Equal(t, int64(3), int64(binary.LittleEndian.Uint64(batchMessage[nodes+16*i:])))
+		nullCounts = append(nullCounts, int64(binary.LittleEndian.Uint64(batchMessage[nodes+16*i+8:])))
+	}
+	assert.Equal(t, []int64{1, 1, 1, 1, 1, 1}, nullCounts)
+
+	n, buffers := batchMessage.vector(batch, 2)
+	require.Equal(t, 2*5+3, n, "validity and values per column, offsets and data for strings")
+	buffer := func(i int) []byte {
+		offset := int(binary.LittleEndian.Uint64(batchMessage[buffers+16*i:]))
+		length := int(binary.LittleEndian.Uint64(batchMessage[buffers+16*i+8:]))
+		assert.Zero(t, offset%8, "buffers are aligned")
+		return body[offset : offset+length]
+	}
+
+	assert.Equal(t, []byte{0b101}, buffer(0), "year validity")
+	assert.Equal(t, uint64(2024), binary.LittleEndian.Uint64(buffer(1)))
+	assert.Equal(t, uint64(2025), binary.LittleEndian.Uint64(buffer(1)[16:]))
+	assert.Equal(t, 800.0, math.Float64frombits(binary.LittleEndian.Uint64(buffer(3)[8:])))
+	assert.Equal(t, []byte{0b001}, buffer(5), "approved values")
+	assert.Equal(t, int32(19737), int32(binary.LittleEndian.Uint32(buffer(7))), "2024-01-15 in days")
+	assert.Equal(t, int32(-1), int32(binary.LittleEndian.Uint32(buffer(7)[8:])), "1969-12-31 in days")
+	assert.Equal(t, time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC).UnixMicro(), int64(binary.LittleEndian.Uint64(buffer(9))))
+	assert.Equal(t, []byte{0b011}, buffer(10), "department validity")
+	offsets := buffer(11)
+	assert.Equal(t, []uint32{0, 6, 15, 15}, []uint32{
+		binary.LittleEndian.Uint32(offsets), binary.LittleEndian.Uint32(offsets[4:]),
+		binary.LittleEndian.Uint32(offsets[8:]), binary.LittleEndian.Uint32(offsets[12:]),
+	})
+	assert.Equal(t, "HealthEducation", string(buffer(12)))
+
+	// End of stream
+	assert.Equal(t, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0, 0, 0, 0}, rest)
+}
+
+func TestWriteArrowDecimals(t *testing.T) {
+	decimal := func(v string) *pb.TabularValue {
+		return &pb.TabularValue{Value: &pb.TabularValue_DecimalValue{DecimalValue: v}}
+	}
+	data := &pb.TabularData{
+		Columns: []*pb.TabularColumn{
+			{Name: "budget", Type: "decimal", Precision: 38, Scale: 2},
+			{Name: "ratio", Type: "decimal"},
+			{Name: "account", Type: "bigint"},
+		},
+		Rows: []*pb.TabularRow{
+			{Values: []*pb.TabularValue{decimal("1200.50"), decimal("0.333333333333333333333"), {Value: &pb.TabularValue_IntValue{IntValue: 9007199254740993}}}},
+			{Values: []*pb.TabularValue{decimal("-0.05"), {}, {}}},
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, data, FormatArrow))
+
+	schemaMessage, message, _, rest := readArrowMessage(t, buf.Bytes())
+	_, fields := schemaMessage.vector(schemaMessage.child(message, 2), 1)
+	field := schemaMessage.deref(fields)
+	require.Equal(t, arrowTypeDecimal, schemaMessage.u8(field, 2))
+	typeTable := schemaMessage.child(field, 3)
+	for slot, want := range []uint32{38, 2, 128} {
+		assert.Equal(t, want, binary.LittleEndian.Uint32(schemaMessage[schemaMessage.field(typeTable, slot):]))
+	}
+	assert.Equal(t, arrowTypeUtf8, schemaMessage.u8(schemaMessage.deref(fields+4), 2), "decimals without a precision are text")
+	assert.Equal(t, arrowTypeInt, schemaMessage.u8(schemaMessage.deref(fields+8), 2))
+
+	batchMessage, message, body, _ := readArrowMessage(t, rest)
+	_, buffers := batchMessage.vector(batchMessage.child(message, 2), 2)
+	offset := int(binary.LittleEndian.Uint64(batchMessage[buffers+16:]))
+	values := body[offset : offset+32]
+	assert.Equal(t, uint64(120050), binary.LittleEndian.Uint64(values), "1200.50 unscaled")
+	assert.Zero(t, binary.LittleEndian.Uint64(values[8:]))
+	assert.Equal(t, int64(-5), int64(binary.LittleEndian.Uint64(values[16:])), "-0.05 in two's complement")
+	assert.Equal(t, uint64(math.MaxUint64), binary.LittleEndian.Uint64(values[24:]))
+
+	// Numbers the column would have to round are refused
+	data.Rows[1].Values[0] = decimal("0.125")
+	assert.ErrorContains(t, Write(&bytes.Buffer{}, data, FormatParquet), "0.125 does not fit")
+}
+
+func TestWriteArrowBatches(t *testing.T) {
+	data := &pb.TabularData{Columns: []*pb.TabularColumn{{Name: "n", Type: "int"}}}
+	for i := 0; i < BatchRows+1; i++ {
+		data.Rows = append(data.Rows, &pb.TabularRow{Values: []*pb.TabularValue{{Value: &pb.TabularValue_IntValue{IntValue: int64(i)}}}})
+	}
+	var buf bytes.Buffer
+	require.NoError(t, WriteArrow(&buf, data))
+
+	_, _, _, rest := readArrowMessage(t, buf.Bytes())
+	var lengths []int64
+	for len(rest) > 8 {
+		var metadata fbReader
+		var message int
+		metadata, message, _, rest = readArrowMessage(t, rest)
+		lengths = append(lengths, metadata.i64(metadata.child(message, 2), 0))
+	}
+	assert.Equal(t, []int64{BatchRows, 1}, lengths)
+}
+
+// readFooter decodes the file metadata of a Parquet file
+func readFooter(t *testing.T, file []byte) thrift.Fields {
+	footerLength := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footer, err := thrift.NewReader(file[len(file)-8-footerLength:len(file)-8], 0).ReadStruct()
+	require.NoError(t, err)
+	return footer
+}
+
+func TestWriteParquet(t *testing.T) {
+	data := sampleData()
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, data, FormatParquet))
+	file := buf.Bytes()
+
+	require.Equal(t, parquetMagic, string(file[:4]))
+	require.Equal(t, parquetMagic, string(file[len(file)-4:]))
+	footer := readFooter(t, file)
+
+	assert.Equal(t, int64(3), footer.I64(3), "num_rows")
+	schema := footer.List(2)
+	require.Len(t, schema, len(data.Columns)+1)
+	assert.Equal(t, int64(len(data.Columns)), schema[0].(thrift.Fields).I64(5))
+	wantTypes := []int64{parquetInt64, parquetDouble, parquetBoolean, parquetInt32, parquetInt64, parquetByteArray}
+	for i, element := range schema[1:] {
+		fields := element.(thrift.Fields)
+		assert.Equal(t, data.Columns[i].Name, fields.String(4))
+		assert.Equal(t, wantTypes[i], fields.I64(1))
+		assert.Equal(t, int64(parquetOptional), fields.I64(3))
+	}
+	assert.Equal(t, int64(parquetDate), schema[4].(thrift.Fields).I64(6))
+	timestamp := schema[5].(thrift.Fields).Struct(10).Struct(8)
+	assert.True(t, timestamp.Bool(1), "adjusted to UTC")
+
+	rowGroups := footer.List(4)
+	require.Len(t, rowGroups, 1)
+	chunks := rowGroups[0].(thrift.Fields).List(1)
+	require.Len(t, chunks, len(data.Columns))
+
+	page := func(i int) []byte {
+		metadata := chunks[i].(thrift.Fields).Struct(3)
+		assert.Equal(t, []interface{}{[]byte(data.Columns[i].Name)}, metadata.List(3))
+		reader := thrift.NewReader(file, int(metadata.I64(9)))
+		header, err := reader.ReadStruct()
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), header.Struct(5).I64(1), "num_values")
+		assert.Equal(t, metadata.I64(6), int64(reader.Pos())-metadata.I64(9)+header.I64(3))
+		return file[reader.Pos() : reader.Pos()+int(header.I64(3))]
+	}
+
+	// Definition levels: 4 byte length, a bit-packed run header for one group and the levels
+	year := page(0)
+	assert.Equal(t, []byte{2, 0, 0, 0, 0b11, 0b101}, year[:6])
+	assert.Equal(t, uint64(2024), binary.LittleEndian.Uint64(year[6:]))
+	assert.Equal(t, uint64(2025), binary.LittleEndian.Uint64(year[14:]))
+	assert.Len(t, year, 22, "nulls have no values")
+
+	department := page(5)
+	assert.Equal(t, []byte{0b011}, department[5:6])
+	assert.Equal(t, "\x06\x00\x00\x00Health\x09\x00\x00\x00Education", string(department[6:]))
+
+	day := page(3)
+	assert.Equal(t, int32(-1), int32(binary.LittleEndian.Uint32(day[10:])))
+}
+
+func TestWriterPages(t *testing.T) {
+	data := sampleData()
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, data.Columns, FormatParquet)
+	require.NoError(t, err)
+	for _, row := range data.Rows {
+		require.NoError(t, writer.WriteRows([]*pb.TabularRow{row}))
+	}
+	require.NoError(t, writer.Close())
+
+	footer := readFooter(t, buf.Bytes())
+	assert.Equal(t, int64(len(data.Rows)), footer.I64(3))
+	assert.Len(t, footer.List(4), len(data.Rows), "each page is a row group")
+
+	buf.Reset()
+	_, err = NewWriter(&buf, data.Columns, Format("csv"))
+	assert.Error(t, err)
+}
+
+func TestWriteParquetWithoutRows(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteParquet(&buf, &pb.TabularData{Columns: []*pb.TabularColumn{{Name: "n", Type: "int"}}}))
+	file := buf.Bytes()
+	footerLength := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	assert.Equal(t, 4+footerLength+8, len(file))
+	footer := readFooter(t, file)
+	assert.Equal(t, int64(0), footer.I64(3))
+	assert.Empty(t, footer.List(4))
+}
+
+// TestWritersInterop writes a table in two batches in both formats and reads the files with
+// testdata/verify.py, which decodes them from the format specifications and with pyarrow when
+// it is installed
+func TestWritersInterop(t *testing.T) {
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 is not installed")
+	}
+
+	decimal := func(v string) *pb.TabularValue {
+		return &pb.TabularValue{Value: &pb.TabularValue_DecimalValue{DecimalValue: v}}
+	}
+	at := func(v time.Time) *pb.TabularValue {
+		return &pb.TabularValue{Value: &pb.TabularValue_TimeValue{TimeValue: timestamppb.New(v)}}
+	}
+	columns := []*pb.TabularColumn{
+		{Name: "year", Type: "int"},
+		{Name: "amount", Type: "float"},
+		{Name: "approved", Type: "bool"},
+		{Name: "day", Type: "date"},
+		{Name: "issued", Type: "datetime"},
+		{Name: "budget", Type: "decimal", Precision: 12, Scale: 2},
+		{Name: "department", Type: "string"},
+	}
+	rows := []*pb.TabularRow{
+		{Values: []*pb.TabularValue{
+			{Value: &pb.TabularValue_IntValue{IntValue: 2024}}, {Value: &pb.TabularValue_FloatValue{FloatValue: 1200.5}},
+			{Value: &pb.TabularValue_BoolValue{BoolValue: true}}, at(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)),
+			at(time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC)), decimal("1234567890.12"),
+			{Value: &pb.TabularValue_StringValue{StringValue: "Health"}},
+		}},
+		{Values: []*pb.TabularValue{
+			{}, {Value: &pb.TabularValue_IntValue{IntValue: 800}}, {Value: &pb.TabularValue_BoolValue{BoolValue: false}},
+			{}, nil, decimal("-0.50"), {Value: &pb.TabularValue_StringValue{StringValue: "Education"}},
+		}},
+		{Values: []*pb.TabularValue{
+			{Value: &pb.TabularValue_IntValue{IntValue: -9007199254740993}}, {}, {},
+			at(time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC)), at(time.Date(1969, 12, 31, 23, 59, 59, 999999000, time.UTC)),
+			{}, {Value: &pb.TabularValue_StringValue{StringValue: "Ports — ලංකා"}},
+		}},
+		{Values: []*pb.TabularValue{
+			{Value: &pb.TabularValue_IntValue{IntValue: 0}}, {Value: &pb.TabularValue_FloatValue{FloatValue: -1.25}},
+			{Value: &pb.TabularValue_BoolValue{BoolValue: true}}, at(time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)),
+			at(time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)), decimal("9999999999.99"),
+			{Value: &pb.TabularValue_StringValue{StringValue: ""}},
+		}},
+	}
+
+	dir := t.TempDir()
+	paths := map[Format]string{FormatArrow: filepath.Join(dir, "table.arrows"), FormatParquet: filepath.Join(dir, "table.parquet")}
+	for format, path := range paths {
+		var buf bytes.Buffer
+		writer, err := NewWriter(&buf, columns, format)
+		require.NoError(t, err)
+		require.NoError(t, writer.WriteRows(rows[:2]))
+		require.NoError(t, writer.WriteRows(rows[2:]))
+		require.NoError(t, writer.Close())
+		require.NoError(t, os.WriteFile(path, buf.Bytes(), 0600))
+	}
+
+	out, err := exec.Command(python, filepath.Join("testdata", "verify.py"), paths[FormatArrow], paths[FormatParquet]).CombinedOutput()
+	require.NoError(t, err, string(out))
+	t.Log(strings.TrimSpace(string(out)))
+}
@@ -0,0 +1,527 @@
+"""Reads the files of TestWritersInterop and checks they hold the expected columns and rows.
+
+The files are read here from the format specifications (Message.fbs and Schema.fbs of Arrow,
+parquet.thrift of Parquet), without the Go writers or the readers of pkg/tabularfile, so the writers
+are checked against an independent decoder. The decoder also holds the files to the rules of the
+reference readers the writers' own tests do not look at: flatbuffer scalars and vectors aligned to
+their size, forward offsets and vtables within the buffer, Arrow buffers on 8 byte boundaries inside
+the body, and Parquet column chunks, pages and row counts that agree with the footer.
+
+When pyarrow is installed the files are read with it as well, through pyarrow.ipc and
+pyarrow.parquet, and must give the same schema and rows.
+
+Run it with the standard library of Python 3 and the two files written by the test:
+
+    python3 verify.py table.arrows table.parquet
+"""
+
+import datetime
+import decimal
+import struct
+import sys
+
+UTC = datetime.timezone.utc
+
+# The table TestWritersInterop writes, in two batches of two rows
+COLUMNS = [
+    ("year", "int64"),
+    ("amount", "double"),
+    ("approved", "bool"),
+    ("day", "date32"),
+    ("issued", "timestamp[us, UTC]"),
+    ("budget", "decimal128(12, 2)"),
+    ("department", "string"),
+]
+ROWS = [
+    [2024, 1200.5, True, datetime.date(2024, 1, 15), datetime.datetime(2024, 1, 15, 9, 30, tzinfo=UTC),
+     decimal.Decimal("1234567890.12"), "Health"],
+    [None, 800.0, False, None, None, decimal.Decimal("-0.50"), "Education"],
+    [-9007199254740993, None, None, datetime.date(1969, 12, 31),
+     datetime.datetime(1969, 12, 31, 23, 59, 59, 999999, tzinfo=UTC), None, "Ports — ලංකා"],
+    [0, -1.25, True, datetime.date(1970, 1, 1), datetime.datetime(1970, 1, 1, tzinfo=UTC),
+     decimal.Decimal("9999999999.99"), ""],
+]
+BATCHES = [2, 2]
+
+EPOCH_DATE = datetime.date(1970, 1, 1)
+EPOCH = datetime.datetime(1970, 1, 1, tzinfo=UTC)
+
+
+class Invalid(Exception):
+    pass
+
+
+def check(condition, message, *args):
+    if not condition:
+        raise Invalid(message % args)
+
+
+# Flatbuffers
+
+
+class Flatbuffer:
+    """Reads tables of a flatbuffer, checking the bounds and alignment rules of the flatbuffers verifier."""
+
+    def __init__(self, buf):
+        self.buf = buf
+
+    def scalar(self, fmt, pos):
+        size = struct.calcsize(fmt)
+        check(0 <= pos and pos + size <= len(self.buf), "scalar at %d out of bounds", pos)
+        check(pos % size == 0, "scalar of %d bytes at %d is not aligned", size, pos)
+        return struct.unpack_from("<" + fmt, self.buf, pos)[0]
+
+    def offset(self, pos):
+        target = pos + self.scalar("I", pos)
+        check(target < len(self.buf), "offset at %d points out of the buffer", pos)
+        return target
+
+    def root(self):
+        return Table(self, self.offset(0))
+
+
+class Table:
+    def __init__(self, fb, pos):
+        self.fb = fb
+        self.pos = pos
+        self.vtable = pos - fb.scalar("i", pos)
+        vtable_size = fb.scalar("H", self.vtable)
+        check(vtable_size >= 4 and vtable_size % 2 == 0, "vtable of table %d has size %d", pos, vtable_size)
+        check(self.vtable + vtable_size <= len(fb.buf), "vtable of table %d out of bounds", pos)
+        self.size = fb.scalar("H", self.vtable + 2)
+        check(pos + self.size <= len(fb.buf), "table %d out of bounds", pos)
+        self.slots = (vtable_size - 4) // 2
+
+    def field(self, slot):
+        if slot >= self.slots:
+            return None
+        offset = self.fb.scalar("H", self.vtable + 4 + 2 * slot)
+        if offset == 0:
+            return None
+        check(offset < self.size, "field %d of table %d is outside the table", slot, self.pos)
+        return self.pos + offset
+
+    def scalar(self, fmt, slot, default=0):
+        pos = self.field(slot)
+        return default if pos is None else self.fb.scalar(fmt, pos)
+
+    def table(self, slot):
+        pos = self.field(slot)
+        return None if pos is None else Table(self.fb, self.fb.offset(pos))
+
+    def vector(self, slot, element_size, element_align):
+        """Returns the position of the first element and the number of elements"""
+        pos = self.field(slot)
+        if pos is None:
+            return 0, 0
+        start = self.fb.offset(pos)
+        n = self.fb.scalar("I", start)
+        check((start + 4) % element_align == 0, "vector at %d is not aligned to %d", start, element_align)
+        check(start + 4 + n * element_size <= len(self.fb.buf), "vector at %d out of bounds", start)
+        return start + 4, n
+
+    def tables(self, slot):
+        first, n = self.vector(slot, 4, 4)
+        return [Table(self.fb, self.fb.offset(first + 4 * i)) for i in range(n)]
+
+    def string(self, slot):
+        first, n = self.vector(slot, 1, 1)
+        check(first + n < len(self.fb.buf) and self.fb.buf[first + n] == 0, "string at %d is not terminated", first)
+        return self.fb.buf[first:first + n].decode()
+
+
+# Arrow IPC stream
+
+MESSAGE_SCHEMA, MESSAGE_RECORD_BATCH = 1, 3
+TYPE_INT, TYPE_FLOAT, TYPE_UTF8, TYPE_BOOL, TYPE_DECIMAL, TYPE_DATE, TYPE_TIMESTAMP = 2, 3, 5, 6, 7, 8, 10
+METADATA_V5 = 4
+
+
+def arrow_type(field):
+    """Returns the name of a field's type in the form of COLUMNS"""
+    type_id = field.scalar("B", 2)
+    t = field.table(3)
+    check(t is not None, "field without a type")
+    if type_id == TYPE_INT:
+        check(t.scalar("i", 0) == 64 and t.scalar("B", 1) == 1, "only signed 64-bit integers are expected")
+        return "int64"
+    if type_id == TYPE_FLOAT:
+        check(t.scalar("h", 0) == 2, "only double precision floats are expected")
+        return "double"
+    if type_id == TYPE_BOOL:
+        return "bool"
+    if type_id == TYPE_DATE:
+        check(t.scalar("h", 0, default=1) == 0, "dates are expected in days")
+        return "date32"
+    if type_id == TYPE_TIMESTAMP:
+        check(t.scalar("h", 0) == 2, "timestamps are expected in microseconds")
+        return "timestamp[us, %s]" % t.string(1)
+    if type_id == TYPE_DECIMAL:
+        check(t.scalar("i", 2, default=128) == 128, "decimals are expected in 128 bits")
+        return "decimal128(%d, %d)" % (t.scalar("i", 0), t.scalar("i", 1))
+    if type_id == TYPE_UTF8:
+        return "string"
+    raise Invalid("unexpected type %d" % type_id)
+
+
+def read_arrow_messages(data):
+    pos = 0
+    while True:
+        check(pos + 8 <= len(data), "stream ends without an end-of-stream marker")
+        marker, length = struct.unpack_from("<Ii", data, pos)
+        check(marker == 0xFFFFFFFF, "message at %d has no continuation marker", pos)
+        if length == 0:
+            check(pos + 8 == len(data), "bytes after the end-of-stream marker")
+            return
+        check(length % 8 == 0, "metadata of the message at %d is not padded to 8 bytes", pos)
+        fb = Flatbuffer(data[pos + 8:pos + 8 + length])
+        message = fb.root()
+        check(message.scalar("h", 0) == METADATA_V5, "message at %d is not of metadata version V5", pos)
+        body_length = message.scalar("q", 3)
+        check(body_length % 8 == 0, "body of the message at %d is not padded to 8 bytes", pos)
+        body_start = pos + 8 + length
+        check(body_start + body_length <= len(data), "body of the message at %d out of bounds", pos)
+        yield message, data[body_start:body_start + body_length]
+        pos = body_start + body_length
+
+
+def bit(buf, i):
+    return buf[i // 8] >> (i % 8) & 1 == 1
+
+
+def read_arrow(data):
+    """Returns the columns, the number of rows of each batch and the rows of an Arrow IPC stream"""
+    messages = read_arrow_messages(data)
+    schema_message, _ = next(messages)
+    check(schema_message.scalar("B", 1) == MESSAGE_SCHEMA, "stream does not start with a schema")
+    schema = schema_message.table(2)
+    check(schema.scalar("h", 0) == 0, "schema is not little-endian")
+    fields = schema.tables(1)
+    columns = []
+    for field in fields:
+        check(field.table(4) is None, "dictionary encoded fields are not expected")
+        columns.append((field.string(0), arrow_type(field)))
+
+    batches, rows = [], []
+    for message, body in messages:
+        check(message.scalar("B", 1) == MESSAGE_RECORD_BATCH, "message after the schema is not a record batch")
+        batch = message.table(2)
+        length = batch.scalar("q", 0)
+        nodes_at, n_nodes = batch.vector(1, 16, 8)
+        buffers_at, n_buffers = batch.vector(2, 16, 8)
+        check(n_nodes == len(columns), "batch has %d nodes for %d columns", n_nodes, len(columns))
+        fb = batch.fb
+        nodes = [struct.unpack_from("<qq", fb.buf, nodes_at + 16 * i) for i in range(n_nodes)]
+        buffers = []
+        for i in range(n_buffers):
+            offset, size = struct.unpack_from("<qq", fb.buf, buffers_at + 16 * i)
+            check(offset % 8 == 0, "buffer %d starts at %d, not on an 8 byte boundary", i, offset)
+            check(offset + size <= len(body), "buffer %d out of the body", i)
+            buffers.append(body[offset:offset + size])
+
+        cells = []
+        b = 0
+        for (name, type_name), (node_length, null_count) in zip(columns, nodes):
+            check(node_length == length, "column %s has %d values in a batch of %d", name, node_length, length)
+            validity = buffers[b]
+            check(len(validity) >= (length + 7) // 8, "validity bitmap of %s is too short", name)
+            valid = [bit(validity, i) for i in range(length)]
+            check(valid.count(False) == null_count, "null count of %s is %d, not %d", name, null_count, valid.count(False))
+            values = buffers[b + 1]
+            b += 2
+            if type_name == "string":
+                offsets = struct.unpack_from("<%di" % (length + 1), values)
+                check(offsets[0] == 0 and list(offsets) == sorted(offsets), "offsets of %s are not increasing", name)
+                text = buffers[b]
+                b += 1
+                check(offsets[-1] <= len(text), "offsets of %s point past its values", name)
+                column = [text[offsets[i]:offsets[i + 1]].decode() for i in range(length)]
+            elif type_name == "int64":
+                column = list(struct.unpack_from("<%dq" % length, values))
+            elif type_name == "double":
+                column = list(struct.unpack_from("<%dd" % length, values))
+            elif type_name == "bool":
+                column = [bit(values, i) for i in range(length)]
+            elif type_name == "date32":
+                column = [EPOCH_DATE + datetime.timedelta(days=v) for v in struct.unpack_from("<%di" % length, values)]
+            elif type_name.startswith("timestamp"):
+                column = [EPOCH + datetime.timedelta(microseconds=v) for v in struct.unpack_from("<%dq" % length, values)]
+            else:
+                scale = int(type_name.split(",")[1].strip(" )"))
+                column = [unscaled(values[16 * i:16 * i + 16], "little", scale) for i in range(length)]
+            cells.append([v if ok else None for v, ok in zip(column, valid)])
+        check(b == n_buffers, "batch has %d buffers, the columns use %d", n_buffers, b)
+        batches.append(length)
+        rows.extend([list(row) for row in zip(*cells)] if cells else [])
+    return columns, batches, rows
+
+
+def unscaled(raw, byteorder, scale):
+    value = int.from_bytes(raw, byteorder, signed=True)
+    return decimal.Decimal(value).scaleb(-scale)
+
+
+# Parquet
+
+T_TRUE, T_FALSE, T_BYTE, T_I16, T_I32, T_I64, T_DOUBLE, T_BINARY, T_LIST, T_SET, T_MAP, T_STRUCT = 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12
+BOOLEAN, INT32, INT64, DOUBLE, BYTE_ARRAY, FIXED_LEN_BYTE_ARRAY = 0, 1, 2, 5, 6, 7
+OPTIONAL = 1
+UTF8, DECIMAL, DATE, TIMESTAMP_MICROS = 0, 5, 6, 10
+PLAIN, RLE = 0, 3
+UNCOMPRESSED = 0
+DATA_PAGE = 0
+
+
+class Compact:
+    """Reads Thrift structs in the compact protocol into dicts of field id to value"""
+
+    def __init__(self, data, pos=0):
+        self.data = data
+        self.pos = pos
+
+    def byte(self):
+        check(self.pos < len(self.data), "Thrift struct runs past the end")
+        self.pos += 1
+        return self.data[self.pos - 1]
+
+    def uvarint(self):
+        n, shift = 0, 0
+        while True:
+            b = self.byte()
+            n |= (b & 0x7F) << shift
+            if b < 0x80:
+                return n
+            shift += 7
+
+    def varint(self):
+        n = self.uvarint()
+        return (n >> 1) ^ -(n & 1)
+
+    def value(self, field_type):
+        if field_type in (T_TRUE, T_FALSE):
+            return field_type == T_TRUE
+        if field_type == T_BYTE:
+            return self.byte()
+        if field_type in (T_I16, T_I32, T_I64):
+            return self.varint()
+        if field_type == T_DOUBLE:
+            self.pos += 8
+            return struct.unpack_from("<d", self.data, self.pos - 8)[0]
+        if field_type == T_BINARY:
+            n = self.uvarint()
+            check(self.pos + n <= len(self.data), "Thrift binary runs past the end")
+            self.pos += n
+            return self.data[self.pos - n:self.pos]
+        if field_type in (T_LIST, T_SET):
+            header = self.byte()
+            n = header >> 4
+            if n == 15:
+                n = self.uvarint()
+            element_type = header & 0x0F
+            if element_type == T_FALSE:
+                element_type = T_TRUE
+            return [self.byte() == 1 if element_type == T_TRUE else self.value(element_type) for _ in range(n)]
+        if field_type == T_STRUCT:
+            return self.struct()
+        raise Invalid("unexpected Thrift type %d" % field_type)
+
+    def struct(self):
+        fields = {}
+        last = 0
+        while True:
+            header = self.byte()
+            if header == 0:
+                return fields
+            delta, field_type = header >> 4, header & 0x0F
+            field_id = last + delta if delta else self.varint()
+            check(field_id not in fields, "Thrift field %d repeated", field_id)
+            fields[field_id] = self.value(field_type)
+            last = field_id
+
+
+def hybrid(data, bit_width, count):
+    """Decodes count values of the RLE/bit-packing hybrid"""
+    reader = Compact(data)
+    values = []
+    while len(values) < count:
+        header = reader.uvarint()
+        if header & 1:
+            n = (header >> 1) * 8
+            raw = data[reader.pos:reader.pos + (header >> 1) * bit_width]
+            reader.pos += len(raw)
+            bits = int.from_bytes(raw, "little")
+            values.extend(bits >> (i * bit_width) & ((1 << bit_width) - 1) for i in range(n))
+        else:
+            width = (bit_width + 7) // 8
+            value = int.from_bytes(data[reader.pos:reader.pos + width], "little")
+            reader.pos += width
+            values.extend([value] * (header >> 1))
+    check(reader.pos == len(data), "definition levels have %d bytes left over", len(data) - reader.pos)
+    return values[:count]
+
+
+def parquet_type(element):
+    physical = element.get(1)
+    converted = element.get(6)
+    logical = element.get(10, {})
+    if physical == INT64 and converted is None and not logical:
+        return "int64"
+    if physical == DOUBLE:
+        return "double"
+    if physical == BOOLEAN:
+        return "bool"
+    if physical == INT32 and converted == DATE:
+        check(6 in logical, "date column without the DATE logical type")
+        return "date32"
+    if physical == INT64 and converted == TIMESTAMP_MICROS:
+        timestamp = logical.get(8)
+        check(timestamp is not None and 2 in timestamp.get(2, {}), "timestamp column without micros logical type")
+        return "timestamp[us, UTC]" if timestamp.get(1) else "timestamp[us]"
+    if physical == FIXED_LEN_BYTE_ARRAY and converted == DECIMAL:
+        check(element.get(2) == 16, "decimals are expected in 16 bytes")
+        check(logical.get(5) == {1: element.get(7), 2: element.get(8)}, "decimal logical type does not match")
+        return "decimal128(%d, %d)" % (element.get(8), element.get(7))
+    if physical == BYTE_ARRAY and converted == UTF8:
+        check(1 in logical, "string column without the STRING logical type")
+        return "string"
+    raise Invalid("unexpected schema element %r" % element)
+
+
+def read_parquet(data):
+    """Returns the columns, the number of rows of each row group and the rows of a Parquet file"""
+    check(data[:4] == b"PAR1" and data[-4:] == b"PAR1", "file does not start and end with PAR1")
+    length = struct.unpack_from("<I", data, len(data) - 8)[0]
+    footer = Compact(data[len(data) - 8 - length:len(data) - 8])
+    metadata = footer.struct()
+    check(footer.pos == length, "file metadata has %d bytes left over", length - footer.pos)
+
+    schema = metadata[2]
+    check(schema[0].get(5) == len(schema) - 1, "schema root has %r children", schema[0].get(5))
+    columns = []
+    for element in schema[1:]:
+        check(element.get(3) == OPTIONAL, "column %s is not optional", element.get(4))
+        columns.append((element[4].decode(), parquet_type(element)))
+
+    batches, rows = [], []
+    end = 4
+    for group in metadata.get(4, []):
+        group_rows = group[3]
+        chunks = group[1]
+        check(len(chunks) == len(columns), "row group has %d column chunks for %d columns", len(chunks), len(columns))
+        total = 0
+        cells = []
+        for (name, type_name), element, chunk in zip(columns, schema[1:], chunks):
+            meta = chunk[3]
+            check(meta[3] == [name.encode()], "column chunk path %r is not %s", meta[3], name)
+            check(meta[1] == element[1], "column chunk of %s has type %d", name, meta[1])
+            check(meta[4] == UNCOMPRESSED, "column chunk of %s is compressed", name)
+            check(meta[5] == group_rows, "column chunk of %s has %d values for %d rows", name, meta[5], group_rows)
+            start = meta[9]
+            check(start >= end, "column chunk of %s overlaps what comes before it", name)
+            page = Compact(data, start)
+            header = page.struct()
+            check(header[1] == DATA_PAGE and header[2] == header[3], "column chunk of %s is not one uncompressed data page", name)
+            check(page.pos - start + header[3] == meta[6] == meta[7], "column chunk of %s has the wrong size", name)
+            data_page = header[5]
+            check(data_page[1] == group_rows and data_page[2] == PLAIN and data_page[3] == RLE, "data page of %s", name)
+            body = data[page.pos:page.pos + header[3]]
+            end = page.pos + header[3]
+            total += meta[6]
+
+            levels_length = struct.unpack_from("<I", body)[0]
+            valid = [level == 1 for level in hybrid(body[4:4 + levels_length], 1, group_rows)]
+            values = plain(body[4 + levels_length:], type_name, valid.count(True), element)
+            it = iter(values)
+            cells.append([next(it) if ok else None for ok in valid])
+        check(group[2] == total, "row group size is %d, its chunks take %d", group[2], total)
+        batches.append(group_rows)
+        rows.extend(list(row) for row in zip(*cells))
+    check(metadata[3] == len(rows), "file metadata counts %d rows, the row groups %d", metadata[3], len(rows))
+    check(end == len(data) - 8 - length, "bytes between the last page and the footer")
+    return columns, batches, rows
+
+
+def plain(body, type_name, n, element):
+    """Decodes n values in PLAIN encoding, which must take the whole page"""
+    if type_name in ("int64",) or type_name.startswith("timestamp"):
+        values, used = list(struct.unpack_from("<%dq" % n, body)), 8 * n
+        if type_name != "int64":
+            values = [EPOCH + datetime.timedelta(microseconds=v) for v in values]
+    elif type_name == "double":
+        values, used = list(struct.unpack_from("<%dd" % n, body)), 8 * n
+    elif type_name == "date32":
+        values, used = [EPOCH_DATE + datetime.timedelta(days=v) for v in struct.unpack_from("<%di" % n, body)], 4 * n
+    elif type_name == "bool":
+        values, used = [bit(body, i) for i in range(n)], (n + 7) // 8
+    elif type_name.startswith("decimal"):
+        values, used = [unscaled(body[16 * i:16 * i + 16], "big", element[7]) for i in range(n)], 16 * n
+    else:
+        values, used = [], 0
+        for _ in range(n):
+            size = struct.unpack_from("<I", body, used)[0]
+            values.append(body[used + 4:used + 4 + size].decode())
+            used += 4 + size
+    check(used == len(body), "page of a %s column has %d bytes left over", type_name, len(body) - used)
+    return values
+
+
+# pyarrow, when installed
+
+# pyarrow's names of the types whose name in COLUMNS differs
+PYARROW_TYPES = {"date32[day]": "date32", "timestamp[us, tz=UTC]": "timestamp[us, UTC]"}
+
+
+def read_with_pyarrow(arrow_path, parquet_path):
+    import pyarrow.ipc
+    import pyarrow.parquet
+
+    with open(arrow_path, "rb") as f:
+        reader = pyarrow.ipc.open_stream(f)
+        arrow_batches = [batch.num_rows for batch in reader]
+    with open(arrow_path, "rb") as f:
+        arrow_table = pyarrow.ipc.open_stream(f).read_all()
+    parquet_file = pyarrow.parquet.ParquetFile(parquet_path)
+    parquet_batches = [parquet_file.metadata.row_group(i).num_rows for i in range(parquet_file.num_row_groups)]
+    parquet_table = parquet_file.read()
+
+    results = []
+    for table, batches in ((arrow_table, arrow_batches), (parquet_table, parquet_batches)):
+        table.validate(full=True)
+        columns = [(field.name, PYARROW_TYPES.get(str(field.type), str(field.type))) for field in table.schema]
+        rows = [[row[name] for name, _ in columns] for row in table.to_pylist()]
+        results.append((columns, batches, rows))
+    return results
+
+
+def compare(reader, columns, batches, rows):
+    check(columns == COLUMNS, "%s: columns %r, want %r", reader, columns, COLUMNS)
+    check(batches == BATCHES, "%s: batches of %r rows, want %r", reader, batches, BATCHES)
+    check(len(rows) == len(ROWS), "%s: %d rows, want %d", reader, len(rows), len(ROWS))
+    for i, (got, want) in enumerate(zip(rows, ROWS)):
+        check(got == want, "%s: row %d is %r, want %r", reader, i, got, want)
+
+
+def main(arrow_path, parquet_path):
+    with open(arrow_path, "rb") as f:
+        compare("Arrow", *read_arrow(f.read()))
+    with open(parquet_path, "rb") as f:
+        compare("Parquet", *read_parquet(f.read()))
+    readers = ["specification"]
+
+    try:
+        import pyarrow
+    except ImportError:
+        pyarrow = None
+    if pyarrow is not None:
+        arrow, parquet = read_with_pyarrow(arrow_path, parquet_path)
+        compare("pyarrow.ipc", *arrow)
+        compare("pyarrow.parquet", *parquet)
+        readers.append("pyarrow " + pyarrow.__version__)
+    print("read with: " + ", ".join(readers))
+
+
+if __name__ == "__main__":
+    try:
+        main(*sys.argv[1:])
+    except Invalid as e:
+        sys.exit(str(e))
@@ -0,0 +1,74 @@
+package tabularfile
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/transform"
+)
+
+// delimiterCandidates are the delimiters detected when none is given, in order of preference
+var delimiterCandidates = []rune{',', ';', '\t', '|'}
+
+// readCSV reads delimited text, decoding it from the given encoding first
+func readCSV(content []byte, opts Options) (*grid, error) {
+	var text io.Reader = bytes.NewReader(content)
+	if opts.Encoding != "" && !strings.EqualFold(opts.Encoding, "utf-8") && !strings.EqualFold(opts.Encoding, "utf8") {
+		encoding, err := htmlindex.Get(opts.Encoding)
+		if err != nil {
+			return nil, fmt.Errorf("unsupported encoding %q", opts.Encoding)
+		}
+		text = transform.NewReader(text, encoding.NewDecoder())
+	}
+	decoded, err := io.ReadAll(text)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s text: %v", opts.Encoding, err)
+	}
+	decoded = bytes.TrimPrefix(decoded, []byte("\ufeff"))
+
+	reader := csv.NewReader(bytes.NewReader(decoded))
+	reader.Comma = opts.Delimiter
+	if reader.Comma == 0 {
+		reader.Comma = detectDelimiter(decoded)
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var records [][]interface{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		cells := make([]interface{}, len(record))
+		for i, field := range record {
+			if strings.TrimSpace(field) != "" {
+				cells[i] = field
+			}
+		}
+		records = append(records, cells)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("file is empty")
+	}
+	return headerAndRows(records, opts)
+}
+
+// detectDelimiter picks the candidate delimiter that occurs most often in the first line
+func detectDelimiter(text []byte) rune {
+	line, _, _ := bytes.Cut(text, []byte("\n"))
+	best, bestCount := delimiterCandidates[0], 0
+	for _, candidate := range delimiterCandidates {
+		if count := bytes.Count(line, []byte(string(candidate))); count > bestCount {
+			best, bestCount = candidate, count
+		}
+	}
+	return best
+}
@@ -0,0 +1,395 @@
+package tabularfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// An xlsx workbook is a zip archive of XML parts (ECMA-376, SpreadsheetML). The workbook part lists
+// the sheets and, through its relationships part, where each sheet is kept. Cells hold numbers,
+// indexes into the shared strings part, inline strings or booleans; dates are numbers of days
+// since the epoch of the workbook that the number format of their style shows as dates.
+
+// xlsxWorkbook is xl/workbook.xml
+type xlsxWorkbook struct {
+	Properties struct {
+		Date1904 bool `xml:"date1904,attr"`
+	} `xml:"workbookPr"`
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		ID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// xlsxRelationships is xl/_rels/workbook.xml.rels
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxSharedStrings is xl/sharedStrings.xml
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+// xlsxText is plain text or rich text made of runs
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var b strings.Builder
+	for _, run := range t.Runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+// xlsxStyles is xl/styles.xml; only the number formats of cell styles matter here
+type xlsxStyles struct {
+	NumberFormats []struct {
+		ID   int    `xml:"numFmtId,attr"`
+		Code string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellFormats []struct {
+		NumberFormatID int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+// xlsxWorksheet is a sheet part such as xl/worksheets/sheet1.xml
+type xlsxWorksheet struct {
+	Rows []struct {
+		Number int        `xml:"r,attr"`
+		Cells  []xlsxCell `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+type xlsxCell struct {
+	Ref    string   `xml:"r,attr"`
+	Style  int      `xml:"s,attr"`
+	Type   string   `xml:"t,attr"`
+	Value  string   `xml:"v"`
+	Inline xlsxText `xml:"is"`
+}
+
+// The limits of a worksheet (ECMA-376 and Excel) and of a decompressed part. A zip entry expands
+// to far more than its compressed size, so parts are read no further than maxPartSize.
+const (
+	maxExcelRows    = 1048576
+	maxExcelColumns = 16384
+	maxPartSize     = 1 << 27
+)
+
+// numberKind says how a number format shows numbers
+type numberKind int
+
+const (
+	numberPlain numberKind = iota
+	numberDate
+	numberDateTime
+	numberTime
+)
+
+// readExcel reads a worksheet of an xlsx workbook, rendering every cell as text
+func readExcel(content []byte, opts Options) (*grid, error) {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("not an xlsx workbook: %v", err)
+	}
+	parts := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		parts[file.Name] = file
+	}
+
+	var workbook xlsxWorkbook
+	if err := readPart(parts, "xl/workbook.xml", &workbook, true); err != nil {
+		return nil, err
+	}
+	var relationships xlsxRelationships
+	if err := readPart(parts, "xl/_rels/workbook.xml.rels", &relationships, true); err != nil {
+		return nil, err
+	}
+	var sharedStrings xlsxSharedStrings
+	if err := readPart(parts, "xl/sharedStrings.xml", &sharedStrings, false); err != nil {
+		return nil, err
+	}
+	var styles xlsxStyles
+	if err := readPart(parts, "xl/styles.xml", &styles, false); err != nil {
+		return nil, err
+	}
+
+	sheetPart, err := sheetPartName(workbook, relationships, opts.Sheet)
+	if err != nil {
+		return nil, err
+	}
+	var sheet xlsxWorksheet
+	if err := readPart(parts, sheetPart, &sheet, true); err != nil {
+		return nil, err
+	}
+
+	customFormats := make(map[int]string, len(styles.NumberFormats))
+	for _, format := range styles.NumberFormats {
+		customFormats[format.ID] = format.Code
+	}
+	kinds := make([]numberKind, len(styles.CellFormats))
+	for i, format := range styles.CellFormats {
+		kinds[i] = formatKind(format.NumberFormatID, customFormats[format.NumberFormatID])
+	}
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	if workbook.Properties.Date1904 {
+		epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	// Rows are placed by their number, so the header row option counts rows as the sheet shows them
+	var records [][]interface{}
+	cellCount := 0
+	for _, row := range sheet.Rows {
+		number := row.Number
+		if number == 0 {
+			number = len(records) + 1
+		}
+		if number < 1 || number > maxExcelRows {
+			return nil, fmt.Errorf("row number %d is outside the sheet", row.Number)
+		}
+		for len(records) < number {
+			records = append(records, nil)
+		}
+
+		var cells []interface{}
+		for _, cell := range row.Cells {
+			column := len(cells)
+			if cell.Ref != "" {
+				if column, err = columnIndex(cell.Ref); err != nil {
+					return nil, err
+				}
+			}
+			if column >= maxExcelColumns {
+				return nil, fmt.Errorf("row %d has more than %d columns", number, maxExcelColumns)
+			}
+			if cellCount += max(column+1-len(cells), 0); cellCount > MaxCells {
+				return nil, fmt.Errorf("sheet has more than %d cells", MaxCells)
+			}
+			for len(cells) <= column {
+				cells = append(cells, nil)
+			}
+			kind := numberPlain
+			if cell.Style >= 0 && cell.Style < len(kinds) {
+				kind = kinds[cell.Style]
+			}
+			if cells[column], err = cellText(cell, sharedStrings, kind, epoch); err != nil {
+				return nil, fmt.Errorf("cell %s: %v", cell.Ref, err)
+			}
+		}
+		records[number-1] = cells
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("sheet is empty")
+	}
+	return headerAndRows(records, opts)
+}
+
+// readPart decodes an XML part of the workbook; optional parts that are missing are left empty
+func readPart(parts map[string]*zip.File, name string, v interface{}, required bool) error {
+	file, ok := parts[name]
+	if !ok {
+		if required {
+			return fmt.Errorf("workbook has no %s", name)
+		}
+		return nil
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("error opening %s: %v", name, err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(io.LimitReader(reader, maxPartSize+1))
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", name, err)
+	}
+	if len(data) > maxPartSize {
+		return fmt.Errorf("%s is larger than %d bytes", name, maxPartSize)
+	}
+	if err := xml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("error decoding %s: %v", name, err)
+	}
+	return nil
+}
+
+// sheetPartName finds the part holding the named sheet, or the first sheet when no name is given
+func sheetPartName(workbook xlsxWorkbook, relationships xlsxRelationships, name string) (string, error) {
+	if len(workbook.Sheets) == 0 {
+		return "", fmt.Errorf("workbook has no sheets")
+	}
+	index := -1
+	for i, sheet := range workbook.Sheets {
+		if name == "" || sheet.Name == name {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		for i, sheet := range workbook.Sheets {
+			if strings.EqualFold(sheet.Name, name) {
+				index = i
+				break
+			}
+		}
+	}
+	if index < 0 {
+		names := make([]string, len(workbook.Sheets))
+		for i, sheet := range workbook.Sheets {
+			names[i] = sheet.Name
+		}
+		return "", fmt.Errorf("workbook has no sheet %q, it has %s", name, strings.Join(names, ", "))
+	}
+
+	for _, relationship := range relationships.Relationships {
+		if relationship.ID != workbook.Sheets[index].ID {
+			continue
+		}
+		if strings.HasPrefix(relationship.Target, "/") {
+			return strings.TrimPrefix(relationship.Target, "/"), nil
+		}
+		return path.Join("xl", relationship.Target), nil
+	}
+	return "", fmt.Errorf("sheet %q has no part", workbook.Sheets[index].Name)
+}
+
+// columnIndex converts the letters of a cell reference such as AB12 to a 0-based column, up to XFD,
+// the last column of a sheet
+func columnIndex(ref string) (int, error) {
+	column := 0
+	letters := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		column = column*26 + int(r-'A'+1)
+		letters++
+		if column > maxExcelColumns {
+			return 0, fmt.Errorf("cell reference %q is outside the sheet", ref)
+		}
+	}
+	if letters == 0 {
+		return 0, fmt.Errorf("invalid cell reference %q", ref)
+	}
+	return column - 1, nil
+}
+
+// cellText renders a cell as the text a CSV export of the sheet would hold: dates as ISO 8601 and
+// booleans as true or false. Error values such as #DIV/0! are kept as text. Empty cells are nil.
+func cellText(cell xlsxCell, sharedStrings xlsxSharedStrings, kind numberKind, epoch time.Time) (interface{}, error) {
+	var text string
+	switch cell.Type {
+	case "s":
+		index, err := strconv.Atoi(cell.Value)
+		if err != nil || index < 0 || index >= len(sharedStrings.Items) {
+			return nil, fmt.Errorf("invalid shared string %q", cell.Value)
+		}
+		text = sharedStrings.Items[index].String()
+	case "inlineStr":
+		text = cell.Inline.String()
+	case "b":
+		text = strconv.FormatBool(cell.Value == "1")
+	case "str", "e", "d":
+		text = cell.Value
+	default:
+		text = cell.Value
+		if text == "" {
+			break
+		}
+		number, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", text)
+		}
+		if kind == numberPlain {
+			// Excel keeps 15 significant digits but writes computed values with 17, such as
+			// 400.16666666666669, which would otherwise make a column of formulas decimal text
+			text = strconv.FormatFloat(number, 'g', 15, 64)
+		} else {
+			text = serialTime(number, kind, epoch)
+		}
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	return text, nil
+}
+
+// serialTime converts a number of days since the epoch to the text of a date, datetime or time.
+// The 1900 date system wrongly counts 29 February 1900, which only shifts dates before March 1900.
+func serialTime(serial float64, kind numberKind, epoch time.Time) string {
+	t := epoch.Add(time.Duration(math.Round(serial*86400*1000)) * time.Millisecond)
+	switch kind {
+	case numberDate:
+		return t.Format("2006-01-02")
+	case numberTime:
+		return t.Format("15:04:05")
+	default:
+		return t.Format("2006-01-02T15:04:05.999Z07:00")
+	}
+}
+
+// formatKind tells whether a number format shows dates, times or both. The built-in formats are
+// listed in ECMA-376 18.8.30; custom formats are recognised by their date and time codes.
+func formatKind(id int, code string) numberKind {
+	switch {
+	case id >= 14 && id <= 17, id >= 27 && id <= 36, id >= 50 && id <= 58:
+		return numberDate
+	case id == 22:
+		return numberDateTime
+	case id >= 18 && id <= 21, id >= 45 && id <= 47:
+		return numberTime
+	case code == "":
+		return numberPlain
+	}
+
+	// Leave out quoted text, escaped characters, colours and conditions, and AM/PM markers
+	var b strings.Builder
+	for i := 0; i < len(code); i++ {
+		switch c := code[i]; c {
+		case '"':
+			for i++; i < len(code) && code[i] != '"'; i++ {
+			}
+		case '\\', '_', '*':
+			i++
+		case '[':
+			for i++; i < len(code) && code[i] != ']'; i++ {
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	format := strings.ToLower(b.String())
+	format = strings.ReplaceAll(format, "am/pm", "")
+	format = strings.ReplaceAll(format, "a/p", "")
+
+	hasTime := strings.ContainsAny(format, "hs")
+	hasDate := strings.ContainsAny(format, "dy") || (!hasTime && strings.Contains(format, "m"))
+	switch {
+	case hasDate && hasTime:
+		return numberDateTime
+	case hasDate:
+		return numberDate
+	case hasTime:
+		return numberTime
+	default:
+		return numberPlain
+	}
+}
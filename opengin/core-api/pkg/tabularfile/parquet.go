@@ -0,0 +1,699 @@
+package tabularfile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"lk/datafoundation/core-api/pkg/internal/thrift"
+	"lk/datafoundation/core-api/pkg/typeinference"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// A Parquet file ends with its metadata, a Thrift struct in the compact protocol (parquet.thrift
+// of the Parquet format) followed by its length and the magic PAR1. The metadata describes the
+// schema and where the pages of each column of each row group start. Only flat schemas are read:
+// every column a required or optional top-level field. Pages may be version 1 or 2, PLAIN or
+// dictionary encoded, and uncompressed or compressed with snappy, gzip or zstd.
+
+const parquetMagic = "PAR1"
+
+// Physical types of parquet.thrift
+const (
+	parquetBoolean           = 0
+	parquetInt32             = 1
+	parquetInt64             = 2
+	parquetInt96             = 3
+	parquetFloat             = 4
+	parquetDouble            = 5
+	parquetByteArray         = 6
+	parquetFixedLenByteArray = 7
+)
+
+// Page types, encodings and compression codecs of parquet.thrift
+const (
+	parquetDataPage       = 0
+	parquetDictionaryPage = 2
+	parquetDataPageV2     = 3
+
+	parquetPlain          = 0
+	parquetPlainDictonary = 2
+	parquetRLE            = 3
+	parquetRLEDictionary  = 8
+
+	parquetUncompressed = 0
+	parquetSnappy       = 1
+	parquetGzip         = 2
+	parquetZstd         = 6
+)
+
+// parquetLogical is what the values of a column mean, from its logical or converted type
+type parquetLogical int
+
+const (
+	logicalNone parquetLogical = iota
+	logicalString
+	logicalDecimal
+	logicalDate
+	logicalTime
+	logicalTimestamp
+	logicalUUID
+)
+
+// parquetColumn describes a column of the schema
+type parquetColumn struct {
+	name       string
+	physical   int
+	typeLength int
+	optional   bool
+	logical    parquetLogical
+	precision  int           // Of decimals
+	scale      int           // Of decimals
+	unit       time.Duration // Of times and timestamps
+}
+
+// readParquet reads every row group of a Parquet file, keeping the types its schema declares
+func readParquet(content []byte) (*grid, error) {
+	if len(content) < 12 || string(content[:4]) != parquetMagic || string(content[len(content)-4:]) != parquetMagic {
+		return nil, fmt.Errorf("not a Parquet file")
+	}
+	length := int(binary.LittleEndian.Uint32(content[len(content)-8:]))
+	if length <= 0 || length > len(content)-12 {
+		return nil, fmt.Errorf("invalid footer length %d", length)
+	}
+	metadata, err := thrift.NewReader(content[len(content)-8-length:len(content)-8], 0).ReadStruct()
+	if err != nil {
+		return nil, fmt.Errorf("error decoding file metadata: %v", err)
+	}
+
+	columns, err := parquetSchema(metadata.List(2))
+	if err != nil {
+		return nil, err
+	}
+	g := &grid{types: make([]typeinference.DataType, len(columns))}
+	for i, column := range columns {
+		g.header = append(g.header, column.name)
+		g.types[i] = column.dataType()
+	}
+
+	for _, item := range metadata.List(4) {
+		rowGroup, _ := item.(thrift.Fields)
+		rows := rowGroup.I64(3)
+		chunks := rowGroup.List(1)
+		if len(chunks) != len(columns) {
+			return nil, fmt.Errorf("row group has %d columns, the schema %d", len(chunks), len(columns))
+		}
+		// Compressed pages hold far more rows than their size suggests, so the rows are bounded by cells
+		if rows < 0 || rows > int64((MaxCells-len(g.rows)*len(columns))/len(columns)) {
+			return nil, fmt.Errorf("file has more than %d cells", MaxCells)
+		}
+		columnValues := make([][]interface{}, len(chunks))
+		for i, chunk := range chunks {
+			chunkFields, _ := chunk.(thrift.Fields)
+			if columnValues[i], err = readColumnChunk(content, columns[i], chunkFields.Struct(3), int(rows)); err != nil {
+				return nil, fmt.Errorf("column %s: %v", columns[i].name, err)
+			}
+		}
+		for r := 0; r < int(rows); r++ {
+			row := make([]interface{}, len(columns))
+			for i, values := range columnValues {
+				row[i] = values[r]
+			}
+			g.rows = append(g.rows, row)
+		}
+	}
+
+	// Integer columns hold bigints when any value needs more than 32 bits, as text files infer them
+	for i, dataType := range g.types {
+		if dataType != typeinference.IntType {
+			continue
+		}
+		for _, row := range g.rows {
+			if v, ok := row[i].(int64); ok && typeinference.IntegerType(v) == typeinference.BigIntType {
+				g.types[i] = typeinference.BigIntType
+				break
+			}
+		}
+	}
+	return g, nil
+}
+
+// parquetSchema reads the flattened schema tree, whose first element is the root
+func parquetSchema(elements []interface{}) ([]parquetColumn, error) {
+	if len(elements) < 2 {
+		return nil, fmt.Errorf("file has no columns")
+	}
+	var columns []parquetColumn
+	for _, item := range elements[1:] {
+		element, _ := item.(thrift.Fields)
+		column := parquetColumn{
+			name:       element.String(4),
+			physical:   int(element.I64(1)),
+			typeLength: int(element.I64(2)),
+			optional:   element.I64(3) == 1,
+			scale:      int(element.I64(7)),
+			precision:  int(element.I64(8)),
+		}
+		if element.I64(5) > 0 || element.I64(3) == 2 || !element.Has(1) {
+			return nil, fmt.Errorf("column %s is nested or repeated, only flat schemas are supported", column.name)
+		}
+		if column.typeLength < 0 || column.typeLength > maxPageSize {
+			return nil, fmt.Errorf("column %s has invalid type length %d", column.name, column.typeLength)
+		}
+
+		if logical := element.Struct(10); logical != nil {
+			switch {
+			case logical.Has(1), logical.Has(4), logical.Has(12):
+				column.logical = logicalString
+			case logical.Has(5):
+				column.logical = logicalDecimal
+				column.scale = int(logical.Struct(5).I64(1))
+				column.precision = int(logical.Struct(5).I64(2))
+			case logical.Has(6):
+				column.logical = logicalDate
+			case logical.Has(7):
+				column.logical = logicalTime
+				column.unit = timeUnit(logical.Struct(7).Struct(2))
+			case logical.Has(8):
+				column.logical = logicalTimestamp
+				column.unit = timeUnit(logical.Struct(8).Struct(2))
+			case logical.Has(14):
+				column.logical = logicalUUID
+			}
+		} else if element.Has(6) {
+			switch element.I64(6) {
+			case 0, 4, 19: // UTF8, ENUM, JSON
+				column.logical = logicalString
+			case 5:
+				column.logical = logicalDecimal
+			case 6:
+				column.logical = logicalDate
+			case 7, 8: // TIME_MILLIS, TIME_MICROS
+				column.logical = logicalTime
+				column.unit = map[int64]time.Duration{7: time.Millisecond, 8: time.Microsecond}[element.I64(6)]
+			case 9, 10: // TIMESTAMP_MILLIS, TIMESTAMP_MICROS
+				column.logical = logicalTimestamp
+				column.unit = map[int64]time.Duration{9: time.Millisecond, 10: time.Microsecond}[element.I64(6)]
+			}
+		}
+		if column.logical == logicalDecimal &&
+			(column.scale < 0 || column.scale > column.precision || column.precision > typeinference.MaxDecimalPrecision) {
+			return nil, fmt.Errorf("column %s has invalid decimal precision %d and scale %d", column.name, column.precision, column.scale)
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// timeUnit reads the TimeUnit union of a TIME or TIMESTAMP logical type
+func timeUnit(unit thrift.Fields) time.Duration {
+	switch {
+	case unit.Has(1):
+		return time.Millisecond
+	case unit.Has(3):
+		return time.Nanosecond
+	default:
+		return time.Microsecond
+	}
+}
+
+// dataType is the type a column is stored as
+func (c parquetColumn) dataType() typeinference.DataType {
+	switch {
+	case c.logical == logicalDate:
+		return typeinference.DateType
+	case c.logical == logicalTime:
+		return typeinference.TimeType
+	case c.logical == logicalTimestamp, c.physical == parquetInt96:
+		return typeinference.DateTimeType
+	case c.logical == logicalDecimal && !c.integerDecimal():
+		return typeinference.DecimalType
+	case c.physical == parquetFloat, c.physical == parquetDouble:
+		return typeinference.FloatType
+	case c.physical == parquetBoolean:
+		return typeinference.BoolType
+	case c.logical == logicalDecimal, c.physical == parquetInt32, c.physical == parquetInt64:
+		return typeinference.IntType
+	default:
+		return typeinference.StringType
+	}
+}
+
+// readColumnChunk decodes the pages of a column of a row group into cell values
+func readColumnChunk(content []byte, column parquetColumn, metadata thrift.Fields, rows int) ([]interface{}, error) {
+	if metadata == nil {
+		return nil, fmt.Errorf("column chunk has no metadata")
+	}
+	codec := metadata.I64(4)
+	offset := metadata.I64(9)
+	if dictionaryOffset := metadata.I64(11); dictionaryOffset > 0 && dictionaryOffset < offset {
+		offset = dictionaryOffset
+	}
+
+	var dictionary []interface{}
+	values := make([]interface{}, 0, rows)
+	for len(values) < rows {
+		if offset < 0 || offset >= int64(len(content)) {
+			return nil, fmt.Errorf("page offset %d is outside the file", offset)
+		}
+		reader := thrift.NewReader(content, int(offset))
+		header, err := reader.ReadStruct()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding page header: %v", err)
+		}
+		size, start := header.I64(3), reader.Pos()
+		if size < 0 || size > int64(len(content)-start) {
+			return nil, fmt.Errorf("page of %d bytes is outside the file", size)
+		}
+		page := content[start : start+int(size)]
+		offset = int64(start) + size
+
+		switch header.I64(1) {
+		case parquetDictionaryPage:
+			data, err := decompress(codec, page, header.I64(2))
+			if err != nil {
+				return nil, err
+			}
+			count := header.Struct(7).I64(1)
+			if count < 0 || count > 8*int64(len(data)) {
+				return nil, fmt.Errorf("dictionary of %d values is longer than its page", count)
+			}
+			if dictionary, _, err = decodePlain(data, column, int(count)); err != nil {
+				return nil, fmt.Errorf("error decoding dictionary: %v", err)
+			}
+		case parquetDataPage:
+			data, err := decompress(codec, page, header.I64(2))
+			if err != nil {
+				return nil, err
+			}
+			pageHeader := header.Struct(5)
+			count, err := pageValues(pageHeader, rows-len(values))
+			if err != nil {
+				return nil, err
+			}
+			defined := allDefined(count)
+			if column.optional {
+				if len(data) < 4 {
+					return nil, fmt.Errorf("page is too short for its definition levels")
+				}
+				levelsLength := int(binary.LittleEndian.Uint32(data))
+				if 4+levelsLength > len(data) {
+					return nil, fmt.Errorf("definition levels are longer than the page")
+				}
+				if defined, err = decodeLevels(data[4:4+levelsLength], count); err != nil {
+					return nil, err
+				}
+				data = data[4+levelsLength:]
+			}
+			if values, err = appendPageValues(values, data, column, pageHeader.I64(2), defined, dictionary); err != nil {
+				return nil, err
+			}
+		case parquetDataPageV2:
+			pageHeader := header.Struct(8)
+			count, err := pageValues(pageHeader, rows-len(values))
+			if err != nil {
+				return nil, err
+			}
+			// Repetition levels come first, then definition levels, both uncompressed
+			definitionLength, repetitionLength := pageHeader.I64(5), pageHeader.I64(6)
+			if definitionLength < 0 || repetitionLength < 0 || definitionLength > int64(len(page))-repetitionLength {
+				return nil, fmt.Errorf("levels are longer than the page")
+			}
+			levelsLength := int(repetitionLength + definitionLength)
+			defined := allDefined(count)
+			if column.optional {
+				levels := page[repetitionLength:levelsLength]
+				if defined, err = decodeLevels(levels, count); err != nil {
+					return nil, err
+				}
+			}
+			data := page[levelsLength:]
+			if !pageHeader.Has(7) || pageHeader.Bool(7) {
+				if data, err = decompress(codec, data, header.I64(2)-int64(levelsLength)); err != nil {
+					return nil, err
+				}
+			}
+			if values, err = appendPageValues(values, data, column, pageHeader.I64(4), defined, dictionary); err != nil {
+				return nil, err
+			}
+		default:
+			// Index pages and pages of unknown types hold no values
+		}
+	}
+	return values[:rows], nil
+}
+
+// pageValues returns the number of values of a data page, which holds one per row of a flat column
+// and so cannot hold more than the rows of the row group that are left
+func pageValues(pageHeader thrift.Fields, rowsLeft int) (int, error) {
+	count := pageHeader.I64(1)
+	if count < 0 || count > int64(rowsLeft) {
+		return 0, fmt.Errorf("page has %d values with %d rows left in the row group", count, rowsLeft)
+	}
+	return int(count), nil
+}
+
+func allDefined(count int) []bool {
+	defined := make([]bool, count)
+	for i := range defined {
+		defined[i] = true
+	}
+	return defined
+}
+
+// decodeLevels reads the definition levels of a flat optional column, 1 for a value and 0 for null
+func decodeLevels(data []byte, count int) ([]bool, error) {
+	levels, err := decodeHybrid(data, 1, count)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding definition levels: %v", err)
+	}
+	defined := make([]bool, count)
+	for i, level := range levels {
+		defined[i] = level == 1
+	}
+	return defined, nil
+}
+
+// appendPageValues decodes the values of a data page, placing nulls where the levels say so
+func appendPageValues(values []interface{}, data []byte, column parquetColumn, encoding int64, defined []bool, dictionary []interface{}) ([]interface{}, error) {
+	count := 0
+	for _, d := range defined {
+		if d {
+			count++
+		}
+	}
+
+	var decoded []interface{}
+	var err error
+	switch encoding {
+	case parquetPlain:
+		decoded, _, err = decodePlain(data, column, count)
+	case parquetPlainDictonary, parquetRLEDictionary:
+		if dictionary == nil {
+			return nil, fmt.Errorf("dictionary encoded page without a dictionary")
+		}
+		if len(data) == 0 && count > 0 {
+			return nil, fmt.Errorf("dictionary encoded page has no indexes")
+		}
+		var indexes []int
+		if count > 0 {
+			if indexes, err = decodeHybrid(data[1:], int(data[0]), count); err != nil {
+				return nil, err
+			}
+		}
+		decoded = make([]interface{}, count)
+		for i, index := range indexes {
+			if index >= len(dictionary) {
+				return nil, fmt.Errorf("dictionary index %d is out of range", index)
+			}
+			decoded[i] = dictionary[index]
+		}
+	case parquetRLE:
+		if column.physical != parquetBoolean || len(data) < 4 {
+			return nil, fmt.Errorf("RLE encoding is only supported for booleans")
+		}
+		var bits []int
+		if bits, err = decodeHybrid(data[4:], 1, count); err != nil {
+			return nil, err
+		}
+		decoded = make([]interface{}, count)
+		for i, bit := range bits {
+			decoded[i] = bit == 1
+		}
+	default:
+		return nil, fmt.Errorf("unsupported encoding %d", encoding)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	next := 0
+	for _, d := range defined {
+		if !d {
+			values = append(values, nil)
+			continue
+		}
+		value, err := column.convert(decoded[next])
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		next++
+	}
+	return values, nil
+}
+
+// decodePlain reads count values in PLAIN encoding: booleans as bits, numbers little endian, byte
+// arrays prefixed by their length. Values are bool, int64, float64, []byte or, for INT96, time.Time.
+func decodePlain(data []byte, column parquetColumn, count int) ([]interface{}, int, error) {
+	values := make([]interface{}, count)
+	pos := 0
+	need := func(n int) error {
+		if n < 0 || n > len(data)-pos {
+			return fmt.Errorf("values end early")
+		}
+		return nil
+	}
+	for i := range values {
+		switch column.physical {
+		case parquetBoolean:
+			if err := need(0); err != nil || i/8 >= len(data) {
+				return nil, 0, fmt.Errorf("values end early")
+			}
+			values[i] = data[i/8]&(1<<(i%8)) != 0
+			pos = (i + 8) / 8
+		case parquetInt32:
+			if err := need(4); err != nil {
+				return nil, 0, err
+			}
+			values[i] = int64(int32(binary.LittleEndian.Uint32(data[pos:])))
+			pos += 4
+		case parquetInt64:
+			if err := need(8); err != nil {
+				return nil, 0, err
+			}
+			values[i] = int64(binary.LittleEndian.Uint64(data[pos:]))
+			pos += 8
+		case parquetInt96:
+			if err := need(12); err != nil {
+				return nil, 0, err
+			}
+			// Nanoseconds of the day followed by the Julian day
+			nanos := int64(binary.LittleEndian.Uint64(data[pos:]))
+			day := int64(binary.LittleEndian.Uint32(data[pos+8:]))
+			values[i] = time.Unix((day-2440588)*86400, nanos).UTC()
+			pos += 12
+		case parquetFloat:
+			if err := need(4); err != nil {
+				return nil, 0, err
+			}
+			values[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(data[pos:])))
+			pos += 4
+		case parquetDouble:
+			if err := need(8); err != nil {
+				return nil, 0, err
+			}
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[pos:]))
+			pos += 8
+		case parquetByteArray:
+			if err := need(4); err != nil {
+				return nil, 0, err
+			}
+			n := int(binary.LittleEndian.Uint32(data[pos:]))
+			pos += 4
+			if err := need(n); err != nil || n < 0 {
+				return nil, 0, fmt.Errorf("values end early")
+			}
+			values[i] = data[pos : pos+n]
+			pos += n
+		case parquetFixedLenByteArray:
+			if err := need(column.typeLength); err != nil || column.typeLength <= 0 {
+				return nil, 0, fmt.Errorf("values end early")
+			}
+			values[i] = data[pos : pos+column.typeLength]
+			pos += column.typeLength
+		default:
+			return nil, 0, fmt.Errorf("unsupported physical type %d", column.physical)
+		}
+	}
+	return values, pos, nil
+}
+
+// integerDecimal reports whether the decimals of the column are integers that fit an int64
+func (c parquetColumn) integerDecimal() bool {
+	return c.scale == 0 && c.precision > 0 && c.precision <= 18
+}
+
+// decimal returns an unscaled decimal value of the column as an int64 or, with the column's scale,
+// as exact text
+func (c parquetColumn) decimal(unscaled *big.Int) interface{} {
+	if c.integerDecimal() && unscaled.IsInt64() {
+		return unscaled.Int64()
+	}
+	denominator := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(c.scale)), nil)
+	return new(big.Rat).SetFrac(unscaled, denominator).FloatString(c.scale)
+}
+
+// convert turns a decoded value into a cell of the column's type
+func (c parquetColumn) convert(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, nil
+		}
+		return v, nil
+	case int64:
+		switch c.logical {
+		case logicalDate:
+			return time.Unix(v*86400, 0).UTC().Format("2006-01-02"), nil
+		case logicalTime:
+			return time.Unix(0, 0).UTC().Add(time.Duration(v) * c.unit).Format("15:04:05.999999999"), nil
+		case logicalTimestamp:
+			return time.Unix(0, 0).UTC().Add(time.Duration(v) * c.unit).Format(time.RFC3339Nano), nil
+		case logicalDecimal:
+			return c.decimal(big.NewInt(v)), nil
+		default:
+			return v, nil
+		}
+	case []byte:
+		switch {
+		case c.logical == logicalDecimal:
+			// Big-endian two's complement unscaled value
+			unscaled := new(big.Int).SetBytes(v)
+			if len(v) > 0 && v[0]&0x80 != 0 {
+				unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(8*len(v))))
+			}
+			return c.decimal(unscaled), nil
+		case c.logical == logicalUUID && len(v) == 16:
+			h := hex.EncodeToString(v)
+			return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:], nil
+		case c.physical == parquetFixedLenByteArray:
+			return hex.EncodeToString(v), nil
+		case c.logical == logicalString:
+			return strings.ToValidUTF8(string(v), "�"), nil
+		default:
+			// Byte arrays without a logical type are usually text; anything else is kept as base64
+			if s := string(v); strings.ToValidUTF8(s, "") == s {
+				return s, nil
+			}
+			return base64.StdEncoding.EncodeToString(v), nil
+		}
+	default:
+		return nil, fmt.Errorf("unexpected value %v", value)
+	}
+}
+
+// maxPageSize is the largest uncompressed page read; writers usually keep pages around a megabyte
+const maxPageSize = 1 << 27
+
+// decompress undoes the compression codec of a column chunk. size is the uncompressed size the page
+// header gives, which the data must decompress to exactly.
+func decompress(codec int64, data []byte, size int64) ([]byte, error) {
+	if codec == parquetUncompressed {
+		return data, nil
+	}
+	if size < 0 || size > maxPageSize {
+		return nil, fmt.Errorf("invalid uncompressed page size %d", size)
+	}
+
+	var decompressed []byte
+	switch codec {
+	case parquetSnappy:
+		if n, err := snappy.DecodedLen(data); err != nil || int64(n) != size {
+			return nil, fmt.Errorf("snappy page does not decompress to %d bytes", size)
+		}
+		var err error
+		if decompressed, err = snappy.Decode(nil, data); err != nil {
+			return nil, err
+		}
+	case parquetGzip:
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		if decompressed, err = io.ReadAll(io.LimitReader(reader, size+1)); err != nil {
+			return nil, err
+		}
+	case parquetZstd:
+		decoder, err := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(uint64(max(size, 1))), zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		defer decoder.Close()
+		if decompressed, err = decoder.DecodeAll(data, nil); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported compression codec %d, use snappy, gzip, zstd or none", codec)
+	}
+	if int64(len(decompressed)) != size {
+		return nil, fmt.Errorf("page decompresses to %d bytes rather than %d", len(decompressed), size)
+	}
+	return decompressed, nil
+}
+
+// decodeHybrid reads count values of the RLE/bit-packing hybrid encoding
+func decodeHybrid(data []byte, bitWidth, count int) ([]int, error) {
+	if bitWidth > 32 {
+		return nil, fmt.Errorf("invalid bit width %d", bitWidth)
+	}
+	values := make([]int, 0, count)
+	pos := 0
+	for len(values) < count {
+		header, n := binary.Uvarint(data[pos:])
+		if n <= 0 {
+			return nil, fmt.Errorf("hybrid encoded values end early")
+		}
+		pos += n
+		if header&1 == 1 {
+			// Bit-packed groups of 8 values, least significant bit first
+			groups := header >> 1
+			if bitWidth > 0 && groups > uint64((len(data)-pos)/bitWidth) {
+				return nil, fmt.Errorf("bit-packed values end early")
+			}
+			end := pos + int(groups)*bitWidth
+			for i := 0; uint64(i/8) < groups && len(values) < count; i++ {
+				value := 0
+				for b := 0; b < bitWidth; b++ {
+					bit := i*bitWidth + b
+					if data[pos+bit/8]&(1<<(bit%8)) != 0 {
+						value |= 1 << b
+					}
+				}
+				values = append(values, value)
+			}
+			pos = end
+		} else {
+			// A run of one value stored in as few bytes as its bit width needs
+			run := int(header >> 1)
+			width := (bitWidth + 7) / 8
+			if pos+width > len(data) {
+				return nil, fmt.Errorf("run length values end early")
+			}
+			value := 0
+			for b := 0; b < width; b++ {
+				value |= int(data[pos+b]) << (8 * b)
+			}
+			pos += width
+			for i := 0; i < run && len(values) < count; i++ {
+				values = append(values, value)
+			}
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,356 @@
+// Package tabularfile reads tabular attributes given as files, so producers can send a CSV export,
+// an Excel workbook or a Parquet file as they are instead of converting them to columns and rows.
+//
+// Text cells (CSV, and Excel cells once rendered) carry no type, so the type of each column is
+// inferred from a sample of its cells with typeinference.InferTextType and every cell is then
+// converted to that type. Cells that do not convert, such as "n/a" in a column of numbers, are
+// stored as nulls and reported in Table.FailedCells. Parquet columns keep their declared types.
+package tabularfile
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"lk/datafoundation/core-api/pkg/typeinference"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Format represents a supported tabular file format
+type Format string
+
+// Supported file formats
+const (
+	FormatCSV     Format = "csv"
+	FormatExcel   Format = "xlsx"
+	FormatParquet Format = "parquet"
+)
+
+// DefaultSampleRows is the number of rows whose cells determine the column types when no sample size is given
+const DefaultSampleRows = 1000
+
+// MaxReportedCells is the number of failed cells listed in a Table; all of them are counted
+const MaxReportedCells = 100
+
+// MaxCells is the number of cells, rows times columns, a file may hold. Compressed formats and
+// sparse sheets hold far more cells than their size suggests, so files above it are rejected
+// before their rows are allocated.
+const MaxCells = 1 << 22
+
+// ParseFormat converts a format name into a Format, accepting a few common aliases
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "csv", "tsv", "text/csv":
+		return FormatCSV, nil
+	case "xlsx", "excel", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":
+		return FormatExcel, nil
+	case "parquet", "pq", "application/vnd.apache.parquet":
+		return FormatParquet, nil
+	default:
+		return "", fmt.Errorf("unsupported tabular file format %q, use csv, xlsx or parquet", name)
+	}
+}
+
+// Options says how to read a file
+type Options struct {
+	Format     Format
+	Delimiter  rune   // CSV field delimiter, detected from the first line when zero
+	HeaderRow  int    // 1-based row holding the column names, 1 when zero; rows above it are skipped
+	NoHeader   bool   // The file has no header and columns are named column_1, column_2 and so on
+	Sheet      string // Excel worksheet to read, the first one when empty
+	Encoding   string // CSV text encoding such as windows-1252 or utf-16le, UTF-8 when empty
+	SampleRows int    // Rows whose cells determine the column types, DefaultSampleRows when zero
+}
+
+// ParseOptions reads options given as a JSON object such as
+// {"format": "csv", "delimiter": ";", "headerRow": 2, "header": true, "sheet": "2024", "encoding": "windows-1252", "sampleRows": 500}
+func ParseOptions(fields map[string]interface{}) (Options, error) {
+	var opts Options
+	for key, value := range fields {
+		var err error
+		switch key {
+		case "format":
+			var name string
+			if name, err = stringOption(key, value); err == nil {
+				opts.Format, err = ParseFormat(name)
+			}
+		case "delimiter":
+			var delimiter string
+			if delimiter, err = stringOption(key, value); err == nil {
+				opts.Delimiter, err = parseDelimiter(delimiter)
+			}
+		case "headerRow":
+			opts.HeaderRow, err = intOption(key, value)
+		case "header":
+			header, ok := value.(bool)
+			if !ok {
+				err = fmt.Errorf("%s must be a boolean", key)
+			}
+			opts.NoHeader = !header
+		case "sheet":
+			opts.Sheet, err = stringOption(key, value)
+		case "encoding":
+			opts.Encoding, err = stringOption(key, value)
+		case "sampleRows":
+			opts.SampleRows, err = intOption(key, value)
+		default:
+			err = fmt.Errorf("unknown option %q", key)
+		}
+		if err != nil {
+			return opts, err
+		}
+	}
+	if opts.Format == "" {
+		return opts, fmt.Errorf("format is required")
+	}
+	if opts.HeaderRow < 0 || opts.SampleRows < 0 {
+		return opts, fmt.Errorf("headerRow and sampleRows cannot be negative")
+	}
+	return opts, nil
+}
+
+func stringOption(key string, value interface{}) (string, error) {
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%s must be a string", key)
+	}
+	return str, nil
+}
+
+func intOption(key string, value interface{}) (int, error) {
+	number, ok := value.(float64)
+	if !ok || number != float64(int(number)) {
+		return 0, fmt.Errorf("%s must be a whole number", key)
+	}
+	return int(number), nil
+}
+
+// parseDelimiter accepts a single character, or tab written out
+func parseDelimiter(delimiter string) (rune, error) {
+	switch delimiter {
+	case `\t`, "tab":
+		return '\t', nil
+	}
+	runes := []rune(delimiter)
+	if len(runes) != 1 || runes[0] == '"' || runes[0] == '\r' || runes[0] == '\n' {
+		return 0, fmt.Errorf("delimiter must be a single character other than a quote or line break")
+	}
+	return runes[0], nil
+}
+
+// Table is a parsed file: the column names, the type of each column and the rows, with cells as
+// int64, float64, bool or string values (dates as YYYY-MM-DD and datetimes as RFC3339) or nil
+type Table struct {
+	Format          Format
+	Columns         []string
+	Types           []typeinference.DataType
+	Rows            [][]interface{}
+	SampledRows     int         // Rows whose cells determined the column types
+	FailedCells     []CellError // The first MaxReportedCells cells that did not convert to their column type
+	FailedCellCount int
+}
+
+// CellError is a cell that does not hold a value of its column type; it is stored as null
+type CellError struct {
+	Row    int // 1-based row of the data, not counting the header
+	Column string
+	Value  string
+	Type   typeinference.DataType
+}
+
+// grid is what the readers return: the header and the cells as they are in the file. Text cells
+// are strings, with nil for empty cells. Readers of typed formats also return the column types
+// and cells that already have them.
+type grid struct {
+	header []string
+	types  []typeinference.DataType // Nil when the types are to be inferred
+	rows   [][]interface{}
+}
+
+// Parse reads a file into a Table
+func Parse(content []byte, opts Options) (*Table, error) {
+	var g *grid
+	var err error
+	switch opts.Format {
+	case FormatCSV:
+		g, err = readCSV(content, opts)
+	case FormatExcel:
+		g, err = readExcel(content, opts)
+	case FormatParquet:
+		g, err = readParquet(content)
+	default:
+		return nil, fmt.Errorf("unsupported tabular file format %q", opts.Format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s file: %v", opts.Format, err)
+	}
+	if len(g.header) == 0 {
+		return nil, fmt.Errorf("%s file has no columns", opts.Format)
+	}
+	if len(g.rows) == 0 {
+		return nil, fmt.Errorf("%s file has no rows", opts.Format)
+	}
+
+	table := &Table{Format: opts.Format, Columns: uniqueNames(g.header), Rows: g.rows}
+	if g.types != nil {
+		table.Types = g.types
+		return table, nil
+	}
+
+	sample := opts.SampleRows
+	if sample == 0 {
+		sample = DefaultSampleRows
+	}
+	table.SampledRows = min(sample, len(g.rows))
+	table.Types = make([]typeinference.DataType, len(table.Columns))
+	for i := range table.Types {
+		dataType := typeinference.NullType
+		for _, row := range g.rows[:table.SampledRows] {
+			if text, ok := row[i].(string); ok {
+				dataType = typeinference.CommonType(dataType, typeinference.InferTextType(text))
+			}
+		}
+		if dataType == typeinference.NullType {
+			dataType = typeinference.StringType
+		}
+		table.Types[i] = dataType
+	}
+
+	for r, row := range table.Rows {
+		for i, cell := range row {
+			text, ok := cell.(string)
+			if !ok {
+				continue
+			}
+			value, err := typeinference.ParseText(text, table.Types[i])
+			if err != nil {
+				table.FailedCellCount++
+				if len(table.FailedCells) < MaxReportedCells {
+					table.FailedCells = append(table.FailedCells, CellError{Row: r + 1, Column: table.Columns[i], Value: text, Type: table.Types[i]})
+				}
+			}
+			row[i] = value
+		}
+	}
+	return table, nil
+}
+
+// Struct returns the table in the {"columns": [...], "rows": [[...]]} form of tabular attributes.
+// Numbers a JSON number cannot hold exactly are strings, so schema inference finds the same types:
+// integers beyond 2^53 are bigint strings, and the whole numbers of a decimal column with at most
+// typeinference.MaxExactDigits digits are numbers rather than text.
+func (t *Table) Struct() (*structpb.Struct, error) {
+	columns := make([]*structpb.Value, len(t.Columns))
+	for i, name := range t.Columns {
+		columns[i] = structpb.NewStringValue(name)
+	}
+	rows := make([]*structpb.Value, len(t.Rows))
+	for r, row := range t.Rows {
+		cells := make([]*structpb.Value, len(row))
+		for i, cell := range row {
+			switch v := cell.(type) {
+			case int64:
+				if v > maxExactInt || v < -maxExactInt {
+					cell = strconv.FormatInt(v, 10)
+				}
+			case string:
+				integer, scale, ok := typeinference.DecimalDigits(v)
+				if ok && scale == 0 && integer <= typeinference.MaxExactDigits && t.Types[i] == typeinference.DecimalType {
+					cell, _ = strconv.ParseFloat(v, 64)
+				}
+			}
+			value, err := structpb.NewValue(cell)
+			if err != nil {
+				return nil, fmt.Errorf("row %d, column %s: %v", r+1, t.Columns[i], err)
+			}
+			cells[i] = value
+		}
+		rows[r] = structpb.NewListValue(&structpb.ListValue{Values: cells})
+	}
+	return &structpb.Struct{Fields: map[string]*structpb.Value{
+		"columns": structpb.NewListValue(&structpb.ListValue{Values: columns}),
+		"rows":    structpb.NewListValue(&structpb.ListValue{Values: rows}),
+	}}, nil
+}
+
+// maxExactInt is 2^53, the largest integer from which a float64 holds every smaller one exactly
+const maxExactInt = 1 << 53
+
+// uniqueNames names unnamed columns after their position and numbers repeated names
+func uniqueNames(header []string) []string {
+	names := make([]string, len(header))
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			name = "column_" + strconv.Itoa(i+1)
+		}
+		unique := name
+		for n := 2; seen[unique]; n++ {
+			unique = name + "_" + strconv.Itoa(n)
+		}
+		seen[unique] = true
+		names[i] = unique
+	}
+	return names
+}
+
+// headerAndRows splits records into the header and the rows below it, naming columns by position
+// when there is no header. Rows are padded to the width of the table; rows wider than the header
+// are only accepted when the extra cells are empty, and rows without any value are skipped.
+func headerAndRows(records [][]interface{}, opts Options) (*grid, error) {
+	headerRow := opts.HeaderRow
+	if headerRow == 0 {
+		headerRow = 1
+	}
+	if headerRow > len(records) {
+		return nil, fmt.Errorf("header row %d is past the end of the file", headerRow)
+	}
+	records = records[headerRow-1:]
+
+	g := &grid{}
+	if opts.NoHeader {
+		width := 0
+		for _, record := range records {
+			width = max(width, len(record))
+		}
+		g.header = make([]string, width)
+	} else {
+		for _, cell := range records[0] {
+			name, _ := cell.(string)
+			g.header = append(g.header, name)
+		}
+		records = records[1:]
+	}
+
+	for _, record := range records {
+		if isEmpty(record) {
+			continue
+		}
+		if (len(g.rows)+1)*len(g.header) > MaxCells {
+			return nil, fmt.Errorf("file has more than %d cells", MaxCells)
+		}
+		row := make([]interface{}, len(g.header))
+		for i, cell := range record {
+			if cell == nil {
+				continue
+			}
+			if i >= len(row) {
+				return nil, fmt.Errorf("row %d has %d cells for %d columns", len(g.rows)+1, len(record), len(g.header))
+			}
+			row[i] = cell
+		}
+		g.rows = append(g.rows, row)
+	}
+	return g, nil
+}
+
+func isEmpty(record []interface{}) bool {
+	for _, cell := range record {
+		if cell != nil {
+			return false
+		}
+	}
+	return true
+}
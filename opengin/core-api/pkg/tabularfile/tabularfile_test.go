@@ -0,0 +1,606 @@
+package tabularfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pb "lk/datafoundation/core-api/lk/datafoundation/core-api"
+	"lk/datafoundation/core-api/pkg/internal/thrift"
+	"lk/datafoundation/core-api/pkg/tabularexport"
+	"lk/datafoundation/core-api/pkg/typeinference"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestParseOptions(t *testing.T) {
+	opts, err := ParseOptions(map[string]interface{}{
+		"format":     "text/csv",
+		"delimiter":  "tab",
+		"headerRow":  float64(3),
+		"header":     true,
+		"encoding":   "windows-1252",
+		"sampleRows": float64(50),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Options{Format: FormatCSV, Delimiter: '\t', HeaderRow: 3, Encoding: "windows-1252", SampleRows: 50}, opts)
+
+	opts, err = ParseOptions(map[string]interface{}{"format": "excel", "sheet": "2024", "header": false})
+	require.NoError(t, err)
+	assert.Equal(t, Options{Format: FormatExcel, Sheet: "2024", NoHeader: true}, opts)
+
+	for _, fields := range []map[string]interface{}{
+		{},
+		{"format": "json"},
+		{"format": "csv", "delimiter": ";;"},
+		{"format": "csv", "headerRow": 1.5},
+		{"format": "csv", "sampleRows": float64(-1)},
+		{"format": "csv", "quote": "'"},
+	} {
+		_, err := ParseOptions(fields)
+		assert.Error(t, err, "%v", fields)
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	content := "department;year;budget;approved;opened\n" +
+		"Health;2024;1200.50;true;2024-01-15\n" +
+		"Education;2023;n/a;false;\n" +
+		";2022;800;TRUE;2022-03-01\n" +
+		"\n"
+	table, err := Parse([]byte(content), Options{Format: FormatCSV})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"department", "year", "budget", "approved", "opened"}, table.Columns)
+	assert.Equal(t, []typeinference.DataType{
+		typeinference.StringType, typeinference.IntType, typeinference.StringType, typeinference.BoolType, typeinference.DateType,
+	}, table.Types, "n/a in the sample makes budget text")
+	assert.Equal(t, [][]interface{}{
+		{"Health", int64(2024), "1200.50", true, "2024-01-15"},
+		{"Education", int64(2023), "n/a", false, nil},
+		{nil, int64(2022), "800", true, "2022-03-01"},
+	}, table.Rows)
+	assert.Equal(t, 3, table.SampledRows)
+	assert.Zero(t, table.FailedCellCount)
+
+	// Beyond the sample, n/a no longer decides the type and is reported instead
+	table, err = Parse([]byte(content), Options{Format: FormatCSV, SampleRows: 1})
+	require.NoError(t, err)
+	assert.Equal(t, typeinference.FloatType, table.Types[2])
+	assert.Equal(t, 1, table.FailedCellCount)
+	assert.Equal(t, []CellError{{Row: 2, Column: "budget", Value: "n/a", Type: typeinference.FloatType}}, table.FailedCells)
+	assert.Nil(t, table.Rows[1][2])
+	assert.Equal(t, 800.0, table.Rows[2][2])
+}
+
+func TestParseCSVOptions(t *testing.T) {
+	// windows-1252 text whose header is on the second line, below a title
+	content := []byte("Annual report|\nname|city\nJos\xe9|Colombo\n")
+	table, err := Parse(content, Options{Format: FormatCSV, Delimiter: '|', HeaderRow: 2, Encoding: "windows-1252"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "city"}, table.Columns)
+	assert.Equal(t, [][]interface{}{{"José", "Colombo"}}, table.Rows)
+
+	// Without a header, columns are named by position and repeated names are numbered
+	table, err = Parse([]byte("\ufeff1\t2\n3\t4\t5\n"), Options{Format: FormatCSV, NoHeader: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"column_1", "column_2", "column_3"}, table.Columns)
+	assert.Equal(t, [][]interface{}{{int64(1), int64(2), nil}, {int64(3), int64(4), int64(5)}}, table.Rows)
+
+	table, err = Parse([]byte("id,id,\n1,2,3\n"), Options{Format: FormatCSV})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "id_2", "column_3"}, table.Columns)
+
+	_, err = Parse([]byte("a,b\n1,2,3\n"), Options{Format: FormatCSV})
+	assert.ErrorContains(t, err, "row 1 has 3 cells for 2 columns")
+	_, err = Parse([]byte("a,b\n"), Options{Format: FormatCSV})
+	assert.ErrorContains(t, err, "no rows")
+	_, err = Parse([]byte("a,b\n1,2\n"), Options{Format: FormatCSV, Encoding: "klingon"})
+	assert.ErrorContains(t, err, "unsupported encoding")
+}
+
+// xlsxFile builds a workbook with two sheets; the second holds numbers styled as dates and times
+func xlsxFile(t testing.TB) []byte {
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Notes" sheetId="1" r:id="rId1"/><sheet name="Budget 2024" sheetId="2" r:id="rId2"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Target="worksheets/sheet2.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>department</t></si><si><t>opened</t></si><si><r><t>Heal</t></r><r><t>th</t></r></si><si><t>amount</t></si><si><t>at</t></si></sst>`,
+		"xl/styles.xml": `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts><numFmt numFmtId="164" formatCode="[$-409]h:mm\ AM/PM"/></numFmts>
+<cellXfs><xf numFmtId="0"/><xf numFmtId="14"/><xf numFmtId="164"/><xf numFmtId="4"/></cellXfs></styleSheet>`,
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>note</t></is></c></row><row r="2"><c r="A2" t="inlineStr"><is><t>draft</t></is></c></row></sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>3</v></c><c r="D1" t="s"><v>4</v></c><c r="E1" t="inlineStr"><is><t>approved</t></is></c></row>
+<row r="2"><c r="A2" t="s"><v>2</v></c><c r="B2" s="1"><v>45306</v></c><c r="C2" s="3"><v>1200.5</v></c><c r="D2" s="2"><v>0.5625</v></c><c r="E2" t="b"><v>1</v></c></row>
+<row r="4"><c r="A4" t="inlineStr"><is><t>Education</t></is></c><c r="C4"><v>800</v></c><c r="E4" t="b"><v>0</v></c></row>
+</sheetData></worksheet>`,
+	}
+	return xlsxArchive(t, parts)
+}
+
+// xlsxArchive zips the parts of a workbook
+func xlsxArchive(t testing.TB, parts map[string]string) []byte {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range parts {
+		w, err := archive.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, archive.Close())
+	return buf.Bytes()
+}
+
+func TestParseExcel(t *testing.T) {
+	content := xlsxFile(t)
+	table, err := Parse(content, Options{Format: FormatExcel, Sheet: "budget 2024"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"department", "opened", "amount", "at", "approved"}, table.Columns)
+	assert.Equal(t, []typeinference.DataType{
+		typeinference.StringType, typeinference.DateType, typeinference.FloatType, typeinference.TimeType, typeinference.BoolType,
+	}, table.Types)
+	assert.Equal(t, [][]interface{}{
+		{"Health", "2024-01-15", 1200.5, "13:30:00", true},
+		{"Education", nil, 800.0, nil, false},
+	}, table.Rows)
+
+	table, err = Parse(content, Options{Format: FormatExcel})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"note"}, table.Columns, "the first sheet is read by default")
+
+	_, err = Parse(content, Options{Format: FormatExcel, Sheet: "2025"})
+	assert.ErrorContains(t, err, `workbook has no sheet "2025", it has Notes, Budget 2024`)
+	_, err = Parse([]byte("department,opened"), Options{Format: FormatExcel})
+	assert.ErrorContains(t, err, "not an xlsx workbook")
+}
+
+// xlsxSheet builds a workbook of one sheet with the given sheet data
+func xlsxSheet(t testing.TB, sheetData string) []byte {
+	return xlsxArchive(t, map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+			sheetData + `</sheetData></worksheet>`,
+	})
+}
+
+// TestParseExcelLimits checks that rows and columns outside a sheet and oversized parts are refused
+// rather than allocated
+func TestParseExcelLimits(t *testing.T) {
+	testCases := []struct {
+		sheetData string
+		err       string
+	}{
+		{`<row r="-5"><c r="A1"><v>1</v></c></row>`, "row number -5 is outside the sheet"},
+		{`<row r="1048577"><c r="A1"><v>1</v></c></row>`, "row number 1048577 is outside the sheet"},
+		{`<row r="1"><c r="ZZZZZZZZ1"><v>1</v></c></row>`, `cell reference "ZZZZZZZZ1" is outside the sheet`},
+		{`<row r="1"><c r="XFE1"><v>1</v></c></row>`, `cell reference "XFE1" is outside the sheet`},
+		{`<row r="1"><c r="XFD1"><v>1</v></c><c><v>2</v></c></row>`, "row 1 has more than 16384 columns"},
+		{strings.Repeat(`<row><c r="XFD1"><v>1</v></c></row>`, MaxCells/maxExcelColumns+1), "sheet has more than 4194304 cells"},
+	}
+	for _, tc := range testCases {
+		_, err := Parse(xlsxSheet(t, tc.sheetData), Options{Format: FormatExcel})
+		assert.ErrorContains(t, err, tc.err, "%.60s", tc.sheetData)
+	}
+
+	// The last row and column of a sheet are still read
+	table, err := Parse(xlsxSheet(t, `<row r="1048575"><c r="XFD1048575" t="inlineStr"><is><t>last</t></is></c></row>`+
+		`<row r="1048576"><c r="XFD1048576"><v>1</v></c></row>`), Options{Format: FormatExcel, HeaderRow: 1048575})
+	require.NoError(t, err)
+	assert.Equal(t, "last", table.Columns[maxExcelColumns-1])
+	assert.Equal(t, int64(1), table.Rows[0][maxExcelColumns-1])
+
+	// A part that decompresses to more than maxPartSize is not read in full
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"xl/workbook.xml":            `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+	} {
+		w, err := archive.Create(name)
+		require.NoError(t, err)
+		_, err = io.WriteString(w, content)
+		require.NoError(t, err)
+	}
+	w, err := archive.Create("xl/worksheets/sheet1.xml")
+	require.NoError(t, err)
+	padding := bytes.Repeat([]byte(" "), 1<<20)
+	for written := 0; written <= maxPartSize; written += len(padding) {
+		_, err = w.Write(padding)
+		require.NoError(t, err)
+	}
+	require.NoError(t, archive.Close())
+	_, err = Parse(buf.Bytes(), Options{Format: FormatExcel})
+	assert.ErrorContains(t, err, "xl/worksheets/sheet1.xml is larger than 134217728 bytes")
+}
+
+func TestParseCSVLimits(t *testing.T) {
+	// A wide header over many short rows would need more cells than MaxCells
+	content := strings.Repeat("c,", 1<<11-1) + "c\n" + strings.Repeat("1\n", 1<<11+1)
+	_, err := Parse([]byte(content), Options{Format: FormatCSV})
+	assert.ErrorContains(t, err, "file has more than 4194304 cells")
+}
+
+func FuzzParseExcel(f *testing.F) {
+	f.Add(xlsxFile(f))
+	f.Add(xlsxSheet(f, `<row r="2"><c r="B2" t="s"><v>0</v></c><c t="b"><v>1</v></c></row><row><c s="1"><v>45306.5</v></c></row>`))
+	content, err := os.ReadFile(filepath.Join("testdata", "budget.xlsx"))
+	require.NoError(f, err)
+	f.Add(content)
+	f.Fuzz(func(t *testing.T, content []byte) {
+		for _, opts := range []Options{{Format: FormatExcel}, {Format: FormatExcel, NoHeader: true, HeaderRow: 2}} {
+			if table, err := Parse(content, opts); err == nil {
+				_, _ = table.Struct()
+			}
+		}
+	})
+}
+
+func TestFormatKind(t *testing.T) {
+	testCases := []struct {
+		id   int
+		code string
+		kind numberKind
+	}{
+		{0, "", numberPlain},
+		{14, "", numberDate},
+		{22, "", numberDateTime},
+		{21, "", numberTime},
+		{164, "yyyy-mm-dd", numberDate},
+		{165, "dd/mm/yyyy hh:mm", numberDateTime},
+		{166, "[h]:mm:ss", numberTime},
+		{167, `#,##0.00 "days"`, numberPlain},
+		{168, "mmm-yy", numberDate},
+		{169, "[Red]0.00", numberPlain},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.kind, formatKind(tc.id, tc.code), "%d %q", tc.id, tc.code)
+	}
+}
+
+func TestParseParquet(t *testing.T) {
+	cell := func(v interface{}) *pb.TabularValue {
+		switch v := v.(type) {
+		case int:
+			return &pb.TabularValue{Value: &pb.TabularValue_IntValue{IntValue: int64(v)}}
+		case float64:
+			return &pb.TabularValue{Value: &pb.TabularValue_FloatValue{FloatValue: v}}
+		case bool:
+			return &pb.TabularValue{Value: &pb.TabularValue_BoolValue{BoolValue: v}}
+		case string:
+			return &pb.TabularValue{Value: &pb.TabularValue_StringValue{StringValue: v}}
+		case time.Time:
+			return &pb.TabularValue{Value: &pb.TabularValue_TimeValue{TimeValue: timestamppb.New(v)}}
+		default:
+			return &pb.TabularValue{}
+		}
+	}
+	row := func(values ...interface{}) *pb.TabularRow {
+		r := &pb.TabularRow{}
+		for _, v := range values {
+			r.Values = append(r.Values, cell(v))
+		}
+		return r
+	}
+	data := &pb.TabularData{
+		Columns: []*pb.TabularColumn{
+			{Name: "year", Type: "int"},
+			{Name: "amount", Type: "float"},
+			{Name: "approved", Type: "bool"},
+			{Name: "day", Type: "date"},
+			{Name: "issued", Type: "datetime"},
+			{Name: "department", Type: "string"},
+		},
+		Rows: []*pb.TabularRow{
+			row(2024, 1200.5, true, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC), "Health"),
+			row(nil, 800.0, false, nil, nil, "Education"),
+			row(2025, nil, nil, time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), nil),
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, tabularexport.WriteParquet(&buf, data))
+
+	table, err := Parse(buf.Bytes(), Options{Format: FormatParquet})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"year", "amount", "approved", "day", "issued", "department"}, table.Columns)
+	assert.Equal(t, []typeinference.DataType{
+		typeinference.IntType, typeinference.FloatType, typeinference.BoolType,
+		typeinference.DateType, typeinference.DateTimeType, typeinference.StringType,
+	}, table.Types)
+	assert.Equal(t, [][]interface{}{
+		{int64(2024), 1200.5, true, "2024-01-15", "2024-01-15T09:30:00Z", "Health"},
+		{nil, 800.0, false, nil, nil, "Education"},
+		{int64(2025), nil, nil, "1969-12-31", "2025-02-01T00:00:00Z", nil},
+	}, table.Rows)
+	assert.Zero(t, table.SampledRows, "Parquet columns keep their declared types")
+
+	_, err = Parse([]byte("PAR1 not really PAR1"), Options{Format: FormatParquet})
+	assert.Error(t, err)
+}
+
+// TestParseParquetDecimals reads decimal and big integer columns back digit for digit
+func TestParseParquetDecimals(t *testing.T) {
+	decimal := func(v string) *pb.TabularValue {
+		return &pb.TabularValue{Value: &pb.TabularValue_DecimalValue{DecimalValue: v}}
+	}
+	integer := func(v int64) *pb.TabularValue {
+		return &pb.TabularValue{Value: &pb.TabularValue_IntValue{IntValue: v}}
+	}
+	data := &pb.TabularData{
+		Columns: []*pb.TabularColumn{
+			{Name: "budget", Type: "decimal", Precision: 38, Scale: 2},
+			{Name: "account", Type: "bigint"},
+			{Name: "year", Type: "int"},
+		},
+		Rows: []*pb.TabularRow{
+			{Values: []*pb.TabularValue{decimal("123456789012345678.05"), integer(9007199254740993), integer(2024)}},
+			{Values: []*pb.TabularValue{decimal("-0.50"), integer(7), {}}},
+			{Values: []*pb.TabularValue{decimal("12"), {}, integer(2025)}},
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, tabularexport.WriteParquet(&buf, data))
+
+	table, err := Parse(buf.Bytes(), Options{Format: FormatParquet})
+	require.NoError(t, err)
+	assert.Equal(t, []typeinference.DataType{typeinference.DecimalType, typeinference.BigIntType, typeinference.IntType}, table.Types,
+		"INT64 columns are bigint only when a value needs more than 32 bits")
+	assert.Equal(t, [][]interface{}{
+		{"123456789012345678.05", int64(9007199254740993), int64(2024)},
+		{"-0.50", int64(7), nil},
+		{"12.00", nil, int64(2025)},
+	}, table.Rows)
+
+	// Integers a JSON number cannot hold are passed on as text
+	value, err := table.Struct()
+	require.NoError(t, err)
+	rows := value.Fields["rows"].GetListValue().Values
+	assert.Equal(t, "9007199254740993", rows[0].GetListValue().Values[1].GetStringValue())
+	assert.Equal(t, 7.0, rows[1].GetListValue().Values[1].GetNumberValue())
+	assert.Equal(t, "12.00", rows[2].GetListValue().Values[0].GetStringValue())
+}
+
+// TestStructDecimalIntegers checks that whole numbers of a decimal column keep it a decimal column
+func TestStructDecimalIntegers(t *testing.T) {
+	table, err := Parse([]byte("amount\n12\n99999999999999999999.5\n"), Options{Format: FormatCSV})
+	require.NoError(t, err)
+	require.Equal(t, typeinference.DecimalType, table.Types[0])
+
+	value, err := table.Struct()
+	require.NoError(t, err)
+	rows := value.Fields["rows"].GetListValue().Values
+	assert.Equal(t, 12.0, rows[0].GetListValue().Values[0].GetNumberValue())
+	assert.Equal(t, "99999999999999999999.5", rows[1].GetListValue().Values[0].GetStringValue())
+}
+
+// parquetFooter ends a hand-built file with its metadata: a schema of the one column the element
+// describes and a row group of the given rows whose column chunk has the given metadata
+func parquetFooter(file []byte, element *thrift.Struct, rows int64, chunk *thrift.Struct) []byte {
+	metadata := (&thrift.Struct{}).
+		I64(1, 1).
+		Structs(2, (&thrift.Struct{}).String(4, "schema").I64(5, 1), element).
+		I64(3, rows).
+		Structs(4, (&thrift.Struct{}).Structs(1, (&thrift.Struct{}).Struct(3, chunk)).I64(3, rows)).
+		Encode()
+	file = append(file, metadata...)
+	file = binary.LittleEndian.AppendUint32(file, uint32(len(metadata)))
+	return append(file, parquetMagic...)
+}
+
+// parquetDictionaryFile builds a file of five rows of a required string column whose values are
+// dictionary encoded in snappy compressed pages, as most writers produce them
+func parquetDictionaryFile() []byte {
+	var dictionary []byte
+	for _, word := range []string{"Health", "Education"} {
+		dictionary = binary.LittleEndian.AppendUint32(dictionary, uint32(len(word)))
+		dictionary = append(dictionary, word...)
+	}
+	// Bit width 1, then a bit-packed group of the indexes 0 1 1 0 0
+	indexes := []byte{1, 0x03, 0b00110}
+
+	file := []byte(parquetMagic)
+	chunkStart := int64(len(file))
+	for _, page := range []struct {
+		header *thrift.Struct
+		data   []byte
+	}{
+		{(&thrift.Struct{}).I64(1, parquetDictionaryPage), dictionary},
+		{(&thrift.Struct{}).I64(1, parquetDataPage), indexes},
+	} {
+		compressed := snappy.Encode(nil, page.data)
+		header := page.header.I64(2, int64(len(page.data))).I64(3, int64(len(compressed)))
+		if page.data[0] == 1 {
+			header.Struct(5, (&thrift.Struct{}).I64(1, 5).I64(2, parquetRLEDictionary))
+		} else {
+			header.Struct(7, (&thrift.Struct{}).I64(1, 2).I64(2, parquetPlain))
+		}
+		file = append(file, header.Encode()...)
+		file = append(file, compressed...)
+	}
+
+	element := (&thrift.Struct{}).I64(1, parquetByteArray).I64(3, 0).String(4, "department").I64(6, 0)
+	return parquetFooter(file, element, 5,
+		(&thrift.Struct{}).I64(4, parquetSnappy).I64(5, 5).I64(9, chunkStart+1000).I64(11, chunkStart))
+}
+
+// TestParseParquetDictionary reads the dictionary encoded column, starting from the dictionary page
+// offset since the data page offset is past it
+func TestParseParquetDictionary(t *testing.T) {
+	table, err := Parse(parquetDictionaryFile(), Options{Format: FormatParquet})
+	require.NoError(t, err)
+	assert.Equal(t, []typeinference.DataType{typeinference.StringType}, table.Types)
+	assert.Equal(t, [][]interface{}{{"Health"}, {"Education"}, {"Education"}, {"Health"}, {"Health"}}, table.Rows)
+}
+
+// TestParseParquetLimits checks that sizes and counts taken from the file are refused when they do not
+// fit the file, rather than allocated or sliced
+func TestParseParquetLimits(t *testing.T) {
+	required := (&thrift.Struct{}).I64(1, parquetInt64).I64(3, 0).String(4, "n")
+	optional := (&thrift.Struct{}).I64(1, parquetInt64).I64(3, 1).String(4, "n")
+	values := binary.LittleEndian.AppendUint64(nil, 7)
+
+	// file builds a file of one column chunk of a single page
+	file := func(element *thrift.Struct, rows, codec int64, header *thrift.Struct, page []byte) []byte {
+		content := append([]byte(parquetMagic), header.Encode()...)
+		content = append(content, page...)
+		return parquetFooter(content, element, rows, (&thrift.Struct{}).I64(4, codec).I64(5, rows).I64(9, 4))
+	}
+	dataPage := func(uncompressed, compressed, count int64) *thrift.Struct {
+		return (&thrift.Struct{}).I64(1, parquetDataPage).I64(2, uncompressed).I64(3, compressed).
+			Struct(5, (&thrift.Struct{}).I64(1, count).I64(2, parquetPlain))
+	}
+	dataPageV2 := func(size, count, definitionLength, repetitionLength int64) *thrift.Struct {
+		return (&thrift.Struct{}).I64(1, parquetDataPageV2).I64(2, size).I64(3, size).
+			Struct(8, (&thrift.Struct{}).I64(1, count).I64(4, parquetPlain).I64(5, definitionLength).I64(6, repetitionLength))
+	}
+	compressed := snappy.Encode(nil, values)
+
+	nested := (&thrift.Struct{}).I64(1, 1)
+	for i := 0; i < 100; i++ {
+		nested = (&thrift.Struct{}).Struct(1, nested)
+	}
+	nestedFile := append([]byte(parquetMagic), nested.Encode()...)
+	nestedFile = binary.LittleEndian.AppendUint32(nestedFile, uint32(len(nested.Encode())))
+	nestedFile = append(nestedFile, parquetMagic...)
+
+	testCases := []struct {
+		name    string
+		content []byte
+		err     string
+	}{
+		{"valid", file(required, 1, parquetUncompressed, dataPage(8, 8, 1), values), ""},
+		{"row group rows", file(required, 1<<40, parquetUncompressed, dataPage(8, 8, 1), values), "file has more than 4194304 cells"},
+		{"negative rows", file(required, -1, parquetUncompressed, dataPage(8, 8, 1), values), "file has more than 4194304 cells"},
+		{"page values", file(required, 1, parquetUncompressed, dataPage(8, 8, 1<<40), values), "page has 1099511627776 values with 1 rows left"},
+		{"negative page values", file(required, 1, parquetUncompressed, dataPage(8, 8, -1), values), "page has -1 values"},
+		{"page size", file(required, 1, parquetUncompressed, dataPage(8, 1<<40, 1), values), "page of 1099511627776 bytes is outside the file"},
+		{"uncompressed size", file(required, 1, parquetSnappy, dataPage(1<<40, int64(len(compressed)), 1), compressed), "invalid uncompressed page size"},
+		{"snappy size", file(required, 1, parquetSnappy, dataPage(4, int64(len(compressed)), 1), compressed), "snappy page does not decompress to 4 bytes"},
+		{"levels", file(optional, 1, parquetUncompressed, dataPage(8, 8, 1), []byte{0xFF, 0xFF, 0xFF, 0x7F, 0, 0, 0, 0}), "definition levels are longer than the page"},
+		{"v2 levels", file(optional, 1, parquetUncompressed, dataPageV2(8, 1, 9, 0), values), "levels are longer than the page"},
+		{"v2 negative levels", file(optional, 1, parquetUncompressed, dataPageV2(8, 1, 2, -1), values), "levels are longer than the page"},
+		{"v2 level overflow", file(optional, 1, parquetUncompressed, dataPageV2(8, 1, 1<<62, 1<<62), values), "levels are longer than the page"},
+		{"dictionary", file(required, 1, parquetUncompressed, (&thrift.Struct{}).I64(1, parquetDictionaryPage).I64(2, 8).I64(3, 8).
+			Struct(7, (&thrift.Struct{}).I64(1, 1<<40)), values), "dictionary of 1099511627776 values is longer than its page"},
+		{"type length", file((&thrift.Struct{}).I64(1, parquetFixedLenByteArray).I64(2, 1<<40).I64(3, 0).String(4, "n"), 1, parquetUncompressed,
+			dataPage(8, 8, 1), values), "invalid type length"},
+		{"decimal", file((&thrift.Struct{}).I64(1, parquetInt64).I64(3, 0).String(4, "n").I64(6, 5).I64(7, 1<<40).I64(8, 2), 1, parquetUncompressed,
+			dataPage(8, 8, 1), values), "invalid decimal precision 2 and scale 1099511627776"},
+		{"nesting", nestedFile, "nested more than 32 deep"},
+	}
+	for _, tc := range testCases {
+		_, err := Parse(tc.content, Options{Format: FormatParquet})
+		if tc.err == "" {
+			assert.NoError(t, err, tc.name)
+		} else {
+			assert.ErrorContains(t, err, tc.err, tc.name)
+		}
+	}
+}
+
+// TestParseGoldenFiles reads files written by testdata/generate.py, which encodes them without this
+// package or tabularexport and lays them out as pyarrow and Excel do
+func TestParseGoldenFiles(t *testing.T) {
+	parquetColumns := []string{"department", "year", "amount", "approved", "opened", "updated", "allocation", "code"}
+	parquetTypes := []typeinference.DataType{
+		typeinference.StringType, typeinference.IntType, typeinference.FloatType, typeinference.BoolType,
+		typeinference.DateType, typeinference.DateTimeType, typeinference.DecimalType, typeinference.StringType,
+	}
+	parquetRows := [][]interface{}{
+		{"Health", int64(2024), 1200.5, true, "2024-01-15", "2024-01-15T09:30:00Z", "1234567890.12", "007"},
+		{"Education", int64(2023), nil, false, nil, "2023-06-30T23:59:59.5Z", "-0.50", "010"},
+		{nil, int64(2022), 800.0, true, "2022-03-01", "2022-03-01T00:00:00Z", "0.00", "007"},
+		{"Health", int64(2025), 99.99, false, "1969-12-31", "1970-01-01T00:00:00Z", "9999999999.99", nil},
+		{"Health", int64(2021), -1.25, true, "2021-12-31", "2021-12-31T12:00:00.123456Z", "1.05", "100"},
+	}
+
+	testCases := []struct {
+		file    string
+		opts    Options
+		columns []string
+		types   []typeinference.DataType
+		rows    [][]interface{}
+	}{
+		{"budget_dictionary_snappy.parquet", Options{Format: FormatParquet}, parquetColumns, parquetTypes, parquetRows},
+		{"budget_v2_gzip.parquet", Options{Format: FormatParquet}, parquetColumns, parquetTypes, parquetRows},
+		{
+			"budget.xlsx", Options{Format: FormatExcel, Sheet: "Budget", HeaderRow: 2},
+			[]string{"Department", "Opened", "Updated", "Allocation", "Code", "Approved"},
+			[]typeinference.DataType{
+				typeinference.StringType, typeinference.DateType, typeinference.DateTimeType,
+				typeinference.FloatType, typeinference.StringType, typeinference.BoolType,
+			},
+			[][]interface{}{
+				{"Health", "2024-01-15", "2024-01-15T09:30:00Z", 1200.5, "007", true},
+				{"Education ", nil, "2023-06-30T23:59:59Z", 400.166666666667, "010", false},
+				{"  Transport", "2021-12-31", "2021-12-31T12:00:00Z", 800.333333333333, "#N/A", true},
+				{"Defence", nil, nil, -1.25, nil, false},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		content, err := os.ReadFile(filepath.Join("testdata", tc.file))
+		require.NoError(t, err)
+		table, err := Parse(content, tc.opts)
+		require.NoError(t, err, tc.file)
+		assert.Equal(t, tc.columns, table.Columns, tc.file)
+		assert.Equal(t, tc.types, table.Types, tc.file)
+		assert.Equal(t, tc.rows, table.Rows, tc.file)
+		assert.Zero(t, table.FailedCellCount, tc.file)
+	}
+}
+
+func FuzzParseParquet(f *testing.F) {
+	var buf bytes.Buffer
+	require.NoError(f, tabularexport.WriteParquet(&buf, &pb.TabularData{
+		Columns: []*pb.TabularColumn{{Name: "year", Type: "int"}, {Name: "department", Type: "string"}, {Name: "budget", Type: "decimal", Precision: 38, Scale: 2}},
+		Rows: []*pb.TabularRow{
+			{Values: []*pb.TabularValue{{Value: &pb.TabularValue_IntValue{IntValue: 2024}}, {Value: &pb.TabularValue_StringValue{StringValue: "Health"}}, {}}},
+			{Values: []*pb.TabularValue{{}, {}, {Value: &pb.TabularValue_DecimalValue{DecimalValue: "-0.50"}}}},
+		},
+	}))
+	f.Add(buf.Bytes())
+	f.Add(parquetDictionaryFile())
+	for _, file := range []string{"budget_dictionary_snappy.parquet", "budget_v2_gzip.parquet"} {
+		content, err := os.ReadFile(filepath.Join("testdata", file))
+		require.NoError(f, err)
+		f.Add(content)
+	}
+	f.Fuzz(func(t *testing.T, content []byte) {
+		if table, err := Parse(content, Options{Format: FormatParquet}); err == nil {
+			_, _ = table.Struct()
+		}
+	})
+}
+
+func TestDecodeHybrid(t *testing.T) {
+	// A run of four 3s, then a bit-packed group of 1 2 3 0 1 2 3 0 at width 2
+	data := []byte{4 << 1, 3, 1<<1 | 1, 0b00111001, 0b00111001}
+	values, err := decodeHybrid(data, 2, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 3, 3, 3, 1, 2, 3, 0, 1, 2}, values)
+
+	_, err = decodeHybrid(data[:3], 2, 10)
+	assert.Error(t, err)
+
+	// A bit-packed run of more groups than the data holds
+	_, err = decodeHybrid(binary.AppendUvarint(nil, 1<<62|1), 2, 10)
+	assert.ErrorContains(t, err, "bit-packed values end early")
+}
@@ -0,0 +1,423 @@
+"""Writes the files of TestParseGoldenFiles.
+
+The files are built here from the format specifications, without the Go readers or the writers of
+pkg/tabularexport, so the readers are checked against an independent encoder. They follow the layout
+of files written by pyarrow (parquet-cpp) and Excel rather than the simpler layout of this repo's own
+writers: every column dictionary encoded but booleans, index bit widths of 0, several row groups,
+fixed-length decimals of the minimal width, statistics and column orders in the metadata, version 2
+data pages with gzip, and workbooks with themes, rich text, formulas, shared formulas and sparse rows.
+
+Run it from this directory with the standard library of Python 3:
+
+    python3 generate.py
+"""
+
+import datetime
+import decimal
+import gzip
+import io
+import struct
+import zipfile
+
+# Thrift compact protocol types
+T_TRUE, T_FALSE, T_BYTE, T_I16, T_I32, T_I64, T_BINARY, T_LIST, T_STRUCT = 1, 2, 3, 4, 5, 6, 8, 9, 12
+
+
+def uvarint(n):
+    out = bytearray()
+    while True:
+        b = n & 0x7F
+        n >>= 7
+        if n:
+            out.append(b | 0x80)
+        else:
+            out.append(b)
+            return bytes(out)
+
+
+def zigzag(n):
+    return uvarint((n << 1) ^ (n >> 63))
+
+
+class Struct:
+    """A Thrift struct; fields are (id, type, value) and written in id order."""
+
+    def __init__(self, *fields):
+        self.fields = [f for f in fields if f is not None]
+
+    def encode(self):
+        out = bytearray()
+        last = 0
+        for field_id, field_type, value in sorted(self.fields, key=lambda f: f[0]):
+            if field_type == "bool":
+                field_type = T_TRUE if value else T_FALSE
+            delta = field_id - last
+            if 0 < delta <= 15:
+                out.append(delta << 4 | field_type)
+            else:
+                out.append(field_type)
+                out += zigzag(field_id)
+            last = field_id
+            out += encode_value(field_type, value)
+        out.append(0)
+        return bytes(out)
+
+
+def encode_value(field_type, value):
+    if field_type in (T_TRUE, T_FALSE):
+        return b""
+    if field_type == T_BYTE:
+        return bytes([value & 0xFF])
+    if field_type in (T_I16, T_I32, T_I64):
+        return zigzag(value)
+    if field_type == T_BINARY:
+        value = value.encode() if isinstance(value, str) else value
+        return uvarint(len(value)) + value
+    if field_type == T_STRUCT:
+        return value.encode()
+    if field_type == T_LIST:
+        element_type, items = value
+        header = bytes([len(items) << 4 | element_type]) if len(items) < 15 else bytes([0xF0 | element_type]) + uvarint(len(items))
+        return header + b"".join(encode_value(element_type, item) for item in items)
+    raise ValueError(field_type)
+
+
+def i32(field_id, v):
+    return (field_id, T_I32, v)
+
+
+def i64(field_id, v):
+    return (field_id, T_I64, v)
+
+
+def binary(field_id, v):
+    return (field_id, T_BINARY, v)
+
+
+def struct_field(field_id, v):
+    return (field_id, T_STRUCT, v)
+
+
+def boolean(field_id, v):
+    return (field_id, "bool", v)
+
+
+def struct_list(field_id, items):
+    return (field_id, T_LIST, (T_STRUCT, items))
+
+
+def i32_list(field_id, items):
+    return (field_id, T_LIST, (T_I32, items))
+
+
+def binary_list(field_id, items):
+    return (field_id, T_LIST, (T_BINARY, items))
+
+
+# Physical types, converted types, encodings and codecs of parquet.thrift
+BOOLEAN, INT32, INT64, DOUBLE, BYTE_ARRAY, FIXED_LEN_BYTE_ARRAY = 0, 1, 2, 5, 6, 7
+UTF8, DECIMAL, DATE, TIMESTAMP_MICROS, INT_64 = 0, 5, 6, 10, 18
+PLAIN, RLE, RLE_DICTIONARY = 0, 3, 8
+UNCOMPRESSED, SNAPPY, GZIP = 0, 1, 2
+DATA_PAGE, DICTIONARY_PAGE, DATA_PAGE_V2 = 0, 2, 3
+
+
+def hybrid(values, bit_width):
+    """RLE/bit-packing hybrid: runs of 8 or more equal values as RLE runs, the rest bit-packed in groups
+    of 8, as parquet-cpp's RleEncoder does."""
+    out = bytearray()
+    i = 0
+    pending = []
+
+    def flush():
+        if not pending:
+            return
+        groups = (len(pending) + 7) // 8
+        padded = pending + [0] * (groups * 8 - len(pending))
+        out.extend(uvarint(groups << 1 | 1))
+        bits = 0
+        nbits = 0
+        for v in padded:
+            bits |= v << nbits
+            nbits += bit_width
+            while nbits >= 8:
+                out.append(bits & 0xFF)
+                bits >>= 8
+                nbits -= 8
+        pending.clear()
+
+    while i < len(values):
+        run = 1
+        while i + run < len(values) and values[i + run] == values[i]:
+            run += 1
+        if run >= 8 and len(pending) % 8 == 0:
+            flush()
+            out.extend(uvarint(run << 1))
+            out.extend(values[i].to_bytes((bit_width + 7) // 8, "little"))
+            i += run
+        else:
+            pending.append(values[i])
+            i += 1
+    flush()
+    return bytes(out)
+
+
+def snappy(data):
+    """Snappy block of literals only, which every decoder accepts."""
+    out = bytearray(uvarint(len(data)))
+    for start in range(0, len(data), 60):
+        chunk = data[start : start + 60]
+        out.append((len(chunk) - 1) << 2)
+        out += chunk
+    return bytes(out)
+
+
+def compress(codec, data):
+    if codec == SNAPPY:
+        return snappy(data)
+    if codec == GZIP:
+        buf = io.BytesIO()
+        with gzip.GzipFile(fileobj=buf, mode="wb", mtime=0) as f:
+            f.write(data)
+        return buf.getvalue()
+    return data
+
+
+class Column:
+    def __init__(self, name, physical, optional, plain, converted=None, logical=None, type_length=None, scale=None, precision=None):
+        self.name = name
+        self.physical = physical
+        self.optional = optional
+        self.plain = plain  # Encodes one value in PLAIN encoding
+        self.converted = converted
+        self.logical = logical
+        self.type_length = type_length
+        self.scale = scale
+        self.precision = precision
+
+    def element(self):
+        return Struct(
+            i32(1, self.physical),
+            i32(2, self.type_length) if self.type_length else None,
+            i32(3, 1 if self.optional else 0),
+            binary(4, self.name),
+            i32(6, self.converted) if self.converted is not None else None,
+            i32(7, self.scale) if self.scale is not None else None,
+            i32(8, self.precision) if self.precision is not None else None,
+            struct_field(10, self.logical) if self.logical else None,
+        )
+
+    def plain_values(self, values):
+        if self.physical == BOOLEAN:
+            out = bytearray((len(values) + 7) // 8)
+            for i, v in enumerate(values):
+                if v:
+                    out[i // 8] |= 1 << (i % 8)
+            return bytes(out)
+        return b"".join(self.plain(v) for v in values)
+
+
+def byte_array(v):
+    v = v.encode()
+    return struct.pack("<I", len(v)) + v
+
+
+def fixed_decimal(length, scale):
+    def plain(v):
+        unscaled = int(decimal.Decimal(v).scaleb(scale))
+        return unscaled.to_bytes(length, "big", signed=True)
+
+    return plain
+
+
+EPOCH = datetime.datetime(1970, 1, 1, tzinfo=datetime.timezone.utc)
+
+
+def micros(text):
+    t = datetime.datetime.fromisoformat(text.replace("Z", "+00:00"))
+    delta = t - EPOCH
+    return (delta.days * 86400 + delta.seconds) * 1_000_000 + delta.microseconds
+
+
+def days(text):
+    return (datetime.date.fromisoformat(text) - datetime.date(1970, 1, 1)).days
+
+
+COLUMNS = [
+    Column("department", BYTE_ARRAY, True, byte_array, UTF8, Struct(struct_field(1, Struct()))),
+    Column("year", INT64, False, lambda v: struct.pack("<q", v), INT_64, Struct(struct_field(10, Struct((1, T_BYTE, 64), boolean(2, True))))),
+    Column("amount", DOUBLE, True, lambda v: struct.pack("<d", v)),
+    Column("approved", BOOLEAN, False, None),
+    Column("opened", INT32, True, lambda v: struct.pack("<i", days(v)), DATE, Struct(struct_field(6, Struct()))),
+    Column(
+        "updated", INT64, False, lambda v: struct.pack("<q", micros(v)), TIMESTAMP_MICROS,
+        Struct(struct_field(8, Struct(boolean(1, True), struct_field(2, Struct(struct_field(2, Struct())))))),
+    ),
+    Column(
+        "allocation", FIXED_LEN_BYTE_ARRAY, False, fixed_decimal(6, 2), DECIMAL,
+        Struct(struct_field(5, Struct(i32(1, 2), i32(2, 12)))), type_length=6, scale=2, precision=12,
+    ),
+    Column("code", BYTE_ARRAY, True, byte_array, UTF8, Struct(struct_field(1, Struct()))),
+]
+
+ROWS = [
+    ["Health", 2024, 1200.5, True, "2024-01-15", "2024-01-15T09:30:00Z", "1234567890.12", "007"],
+    ["Education", 2023, None, False, None, "2023-06-30T23:59:59.5Z", "-0.50", "010"],
+    [None, 2022, 800.0, True, "2022-03-01", "2022-03-01T00:00:00Z", "0.00", "007"],
+    ["Health", 2025, 99.99, False, "1969-12-31", "1970-01-01T00:00:00Z", "9999999999.99", None],
+    ["Health", 2021, -1.25, True, "2021-12-31", "2021-12-31T12:00:00.123456Z", "1.05", "100"],
+]
+
+
+def statistics(column, values):
+    present = [v for v in values if v is not None]
+    fields = [i64(3, len(values) - len(present))]
+    if present and column.physical != BOOLEAN:
+        key = (lambda v: decimal.Decimal(v)) if column.physical == FIXED_LEN_BYTE_ARRAY else (lambda v: v)
+        encode = column.plain
+        if column.physical == BYTE_ARRAY:
+            encode = lambda v: v.encode()
+        fields += [binary(5, encode(max(present, key=key))), binary(6, encode(min(present, key=key)))]
+    return Struct(*fields)
+
+
+def write_chunk(out, column, values, codec, version, dictionary):
+    """Writes the pages of a column chunk and returns its ColumnMetaData."""
+    start = len(out)
+    present = [v for v in values if v is not None]
+    levels = [0 if v is None else 1 for v in values]
+    dictionary_offset = None
+    encodings = [RLE, PLAIN]
+
+    if dictionary and column.physical != BOOLEAN:
+        entries = list(dict.fromkeys(present))
+        page = column.plain_values(entries)
+        compressed = compress(codec, page)
+        header = Struct(
+            i32(1, DICTIONARY_PAGE), i32(2, len(page)), i32(3, len(compressed)),
+            struct_field(7, Struct(i32(1, len(entries)), i32(2, PLAIN))),
+        )
+        dictionary_offset = start
+        out += header.encode() + compressed
+        bit_width = (len(entries) - 1).bit_length()
+        encoded = bytes([bit_width]) + hybrid([entries.index(v) for v in present], bit_width)
+        encoding = RLE_DICTIONARY
+        encodings = [PLAIN, RLE, RLE_DICTIONARY]
+    else:
+        encoded = column.plain_values(present)
+        encoding = PLAIN
+
+    data_offset = len(out)
+    stats = statistics(column, values)
+    if version == 1:
+        page = b""
+        if column.optional:
+            level_bytes = hybrid(levels, 1)
+            page = struct.pack("<I", len(level_bytes)) + level_bytes
+        page += encoded
+        compressed = compress(codec, page)
+        header = Struct(
+            i32(1, DATA_PAGE), i32(2, len(page)), i32(3, len(compressed)),
+            struct_field(5, Struct(i32(1, len(values)), i32(2, encoding), i32(3, RLE), i32(4, RLE), struct_field(5, stats))),
+        )
+        out += header.encode() + compressed
+    else:
+        level_bytes = hybrid(levels, 1) if column.optional else b""
+        compressed = compress(codec, encoded)
+        header = Struct(
+            i32(1, DATA_PAGE_V2), i32(2, len(level_bytes) + len(encoded)), i32(3, len(level_bytes) + len(compressed)),
+            struct_field(8, Struct(
+                i32(1, len(values)), i32(2, len(values) - len(present)), i32(3, len(values)), i32(4, encoding),
+                i32(5, len(level_bytes)), i32(6, 0), boolean(7, codec != UNCOMPRESSED), struct_field(8, stats),
+            )),
+        )
+        out += header.encode() + level_bytes + compressed
+
+    size = len(out) - start
+    return Struct(
+        i32(1, column.physical),
+        i32_list(2, encodings),
+        binary_list(3, [column.name]),
+        i32(4, codec),
+        i64(5, len(values)),
+        i64(6, size),
+        i64(7, size),
+        i64(9, data_offset),
+        i64(11, dictionary_offset) if dictionary_offset is not None else None,
+        struct_field(12, stats),
+    ), start, size
+
+
+def write_parquet(path, row_group_size, codec, version, dictionary):
+    out = bytearray(b"PAR1")
+    row_groups = []
+    for first in range(0, len(ROWS), row_group_size):
+        rows = ROWS[first : first + row_group_size]
+        chunks = []
+        group_start = len(out)
+        for i, column in enumerate(COLUMNS):
+            metadata, start, size = write_chunk(out, column, [row[i] for row in rows], codec, version, dictionary)
+            chunks.append(Struct(i64(2, start), struct_field(3, metadata)))
+        total = len(out) - group_start
+        row_groups.append(Struct(
+            struct_list(1, chunks), i64(2, total), i64(3, len(rows)), i64(5, group_start), i64(6, total), (7, T_I16, len(row_groups)),
+        ))
+
+    schema = [Struct(binary(4, "schema"), i32(5, len(COLUMNS)))] + [c.element() for c in COLUMNS]
+    metadata = Struct(
+        i32(1, 2 if version == 2 else 1),
+        struct_list(2, schema),
+        i64(3, len(ROWS)),
+        struct_list(4, row_groups),
+        struct_list(5, [Struct(binary(1, "origin"), binary(2, "tabularfile/testdata/generate.py"))]),
+        binary(6, "tabularfile testdata generator"),
+        struct_list(7, [Struct(struct_field(1, Struct())) for _ in COLUMNS]),  # TypeDefinedOrder
+    ).encode()
+    out += metadata + struct.pack("<I", len(metadata)) + b"PAR1"
+    with open(path, "wb") as f:
+        f.write(out)
+
+
+# An Excel workbook: the parts Excel writes, with a theme, document properties and a calculation chain
+# left out only where the reader never looks
+SPREADSHEET = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+RELATIONSHIPS = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+
+XLSX_PARTS = {
+    "[Content_Types].xml": f"""<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/><Override PartName="/xl/worksheets/sheet2.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/><Override PartName="/xl/theme/theme1.xml" ContentType="application/vnd.openxmlformats-officedocument.theme+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/><Override PartName="/xl/sharedStrings.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sharedStrings+xml"/><Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/><Override PartName="/docProps/app.xml" ContentType="application/vnd.openxmlformats-officedocument.extended-properties+xml"/></Types>""",
+    "_rels/.rels": f"""<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/extended-properties" Target="docProps/app.xml"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>""",
+    "docProps/app.xml": """<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties" xmlns:vt="http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes"><Application>Microsoft Excel</Application><DocSecurity>0</DocSecurity><ScaleCrop>false</ScaleCrop><TitlesOfParts><vt:vector size="2" baseType="lpstr"><vt:lpstr>Summary</vt:lpstr><vt:lpstr>Budget</vt:lpstr></vt:vector></TitlesOfParts><AppVersion>16.0300</AppVersion></Properties>""",
+    "docProps/core.xml": """<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"><dc:creator>Finance</dc:creator><dcterms:created xsi:type="dcterms:W3CDTF">2024-01-15T09:30:00Z</dcterms:created></cp:coreProperties>""",
+    "xl/workbook.xml": f"""<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="{SPREADSHEET}" xmlns:r="{RELATIONSHIPS}" xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006" mc:Ignorable="x15 xr xr6 xr10 xr2" xmlns:x15="http://schemas.microsoft.com/office/spreadsheetml/2010/11/main" xmlns:xr="http://schemas.microsoft.com/office/spreadsheetml/2014/revision" xmlns:xr6="http://schemas.microsoft.com/office/spreadsheetml/2016/revision6" xmlns:xr10="http://schemas.microsoft.com/office/spreadsheetml/2016/revision10" xmlns:xr2="http://schemas.microsoft.com/office/spreadsheetml/2015/revision2"><fileVersion appName="xl" lastEdited="7" lowestEdited="7" rupBuild="27425"/><workbookPr defaultThemeVersion="166925"/><mc:AlternateContent xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006"><mc:Choice Requires="x15"><x15ac:absPath url="C:\\Users\\finance\\Documents\\" xmlns:x15ac="http://schemas.microsoft.com/office/spreadsheetml/2010/11/ac"/></mc:Choice></mc:AlternateContent><bookViews><workbookView xWindow="-120" yWindow="-120" windowWidth="29040" windowHeight="15840" activeTab="1"/></bookViews><sheets><sheet name="Summary" sheetId="3" r:id="rId1"/><sheet name="Budget" sheetId="1" r:id="rId2"/></sheets><definedNames><definedName name="_xlnm._FilterDatabase" localSheetId="1" hidden="1">Budget!$A$2:$F$7</definedName></definedNames><calcPr calcId="191029"/></workbook>""",
+    "xl/_rels/workbook.xml.rels": """<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/theme" Target="theme/theme1.xml"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet2.xml"/><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId5" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings" Target="sharedStrings.xml"/><Relationship Id="rId4" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>""",
+    "xl/theme/theme1.xml": """<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<a:theme xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" name="Office Theme"><a:themeElements><a:clrScheme name="Office"><a:dk1><a:sysClr val="windowText" lastClr="000000"/></a:dk1><a:lt1><a:sysClr val="window" lastClr="FFFFFF"/></a:lt1></a:clrScheme><a:fontScheme name="Office"><a:majorFont><a:latin typeface="Calibri Light"/></a:majorFont><a:minorFont><a:latin typeface="Calibri"/></a:minorFont></a:fontScheme></a:themeElements></a:theme>""",
+    "xl/styles.xml": f"""<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="{SPREADSHEET}" xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006" mc:Ignorable="x14ac x16r2 xr" xmlns:x14ac="http://schemas.microsoft.com/office/spreadsheetml/2009/9/ac"><numFmts count="2"><numFmt numFmtId="164" formatCode="yyyy\\-mm\\-dd\\ hh:mm:ss"/><numFmt numFmtId="165" formatCode="&quot;LKR&quot;\\ #,##0.00"/></numFmts><fonts count="2" x14ac:knownFonts="1"><font><sz val="11"/><color theme="1"/><name val="Calibri"/><family val="2"/><scheme val="minor"/></font><font><b/><sz val="11"/><color theme="1"/><name val="Calibri"/><family val="2"/><scheme val="minor"/></font></fonts><fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills><borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders><cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs><cellXfs count="6"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/><xf numFmtId="14" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/><xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/><xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/><xf numFmtId="49" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs><cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles><dxfs count="0"/><tableStyles count="0" defaultTableStyle="TableStyleMedium2" defaultPivotStyle="PivotStyleLight16"/></styleSheet>""",
+    "xl/sharedStrings.xml": f"""<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<sst xmlns="{SPREADSHEET}" count="14" uniqueCount="11"><si><t>Budget by department</t></si><si><t>Department</t></si><si><t>Opened</t></si><si><t>Updated</t></si><si><t>Allocation</t></si><si><t>Code</t></si><si><t>Approved</t></si><si><t>Health</t></si><si><r><rPr><b/><sz val="11"/><color theme="1"/><rFont val="Calibri"/><family val="2"/><scheme val="minor"/></rPr><t>Edu</t></r><r><rPr><sz val="11"/><color theme="1"/><rFont val="Calibri"/><family val="2"/><scheme val="minor"/></rPr><t xml:space="preserve">cation </t></r></si><si><t xml:space="preserve">  Transport</t></si><si><t>007</t></si></sst>""",
+    "xl/worksheets/sheet1.xml": f"""<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="{SPREADSHEET}" xmlns:r="{RELATIONSHIPS}"><dimension ref="A1"/><sheetViews><sheetView workbookViewId="0"/></sheetViews><sheetFormatPr defaultRowHeight="15"/><sheetData><row r="1"><c r="A1" t="s"><v>0</v></c></row></sheetData><pageMargins left="0.7" right="0.7" top="0.75" bottom="0.75" header="0.3" footer="0.3"/></worksheet>""",
+    "xl/worksheets/sheet2.xml": f"""<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="{SPREADSHEET}" xmlns:r="{RELATIONSHIPS}" xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006" mc:Ignorable="x14ac xr xr2 xr3" xmlns:x14ac="http://schemas.microsoft.com/office/spreadsheetml/2009/9/ac" xmlns:xr="http://schemas.microsoft.com/office/spreadsheetml/2014/revision" xr:uid="{{00000000-0001-0000-0000-000000000000}}"><sheetPr filterMode="false"/><dimension ref="A1:F7"/><sheetViews><sheetView tabSelected="1" workbookViewId="0"><pane ySplit="2" topLeftCell="A3" activePane="bottomLeft" state="frozen"/><selection pane="bottomLeft" activeCell="A3" sqref="A3"/></sheetView></sheetViews><sheetFormatPr defaultRowHeight="15" x14ac:dyDescent="0.25"/><cols><col min="1" max="1" width="14.7109375" customWidth="1"/><col min="3" max="3" width="19.7109375" customWidth="1"/></cols><sheetData><row r="1" spans="1:6" x14ac:dyDescent="0.25"><c r="A1" s="1" t="s"><v>0</v></c></row><row r="2" spans="1:6" s="1" customFormat="1" x14ac:dyDescent="0.25"><c r="A2" s="1" t="s"><v>1</v></c><c r="B2" s="1" t="s"><v>2</v></c><c r="C2" s="1" t="s"><v>3</v></c><c r="D2" s="1" t="s"><v>4</v></c><c r="E2" s="1" t="s"><v>5</v></c><c r="F2" s="1" t="s"><v>6</v></c></row><row r="3" spans="1:6" x14ac:dyDescent="0.25"><c r="A3" t="s"><v>7</v></c><c r="B3" s="2"><v>45306</v></c><c r="C3" s="3"><v>45306.395833333336</v></c><c r="D3" s="4"><v>1200.5</v></c><c r="E3" s="5" t="s"><v>10</v></c><c r="F3" t="b"><v>1</v></c></row><row r="4" spans="1:6" x14ac:dyDescent="0.25"><c r="A4" t="s"><v>8</v></c><c r="B4" s="2"/><c r="C4" s="3"><v>45107.999988425923</v></c><c r="D4" s="4"><f>D3/3</f><v>400.16666666666669</v></c><c r="E4" s="5" t="str"><f>TEXT(10,"000")</f><v>010</v></c><c r="F4" t="b"><v>0</v></c></row><row r="6" spans="1:6" x14ac:dyDescent="0.25"><c r="A6" t="s"><v>9</v></c><c r="B6" s="2"><v>44561</v></c><c r="C6" s="3"><v>44561.5</v></c><c r="D6" s="4"><f t="shared" ref="D6:D7" si="0">D3-D4</f><v>800.33333333333326</v></c><c r="E6" t="e"><v>#N/A</v></c><c r="F6" t="b"><v>1</v></c></row><row r="7" spans="1:6" x14ac:dyDescent="0.25"><c r="A7" t="inlineStr"><is><t>Defence</t></is></c><c r="D7" s="4"><f t="shared" si="0"/><v>-1.25</v></c><c r="F7" t="b"><v>0</v></c></row></sheetData><autoFilter ref="A2:F7"/><pageMargins left="0.7" right="0.7" top="0.75" bottom="0.75" header="0.3" footer="0.3"/><pageSetup orientation="portrait" r:id="rId1"/></worksheet>""",
+}
+
+
+def write_xlsx(path):
+    with zipfile.ZipFile(path, "w", zipfile.ZIP_DEFLATED) as archive:
+        for name, content in XLSX_PARTS.items():
+            info = zipfile.ZipInfo(name, date_time=(1980, 1, 1, 0, 0, 0))
+            info.compress_type = zipfile.ZIP_DEFLATED
+            archive.writestr(info, content.encode())
+
+
+if __name__ == "__main__":
+    write_parquet("budget_dictionary_snappy.parquet", row_group_size=3, codec=SNAPPY, version=1, dictionary=True)
+    write_parquet("budget_v2_gzip.parquet", row_group_size=5, codec=GZIP, version=2, dictionary=False)
+    write_xlsx("budget.xlsx")
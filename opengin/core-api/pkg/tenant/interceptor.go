@@ -0,0 +1,98 @@
+package tenant
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"lk/datafoundation/core-api/pkg/auth"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// resolve picks the tenant of a call and charges it to the tenant's quota, returning a context carrying the tenant.
+// Callers bound to a tenant get that tenant and may not name another one. Unbound callers
+// need RoleAdmin to name a tenant other than Default. Without authentication there is no principal
+// and any caller may name any tenant, so tenants are only isolated from each other when auth is on.
+func resolve(ctx context.Context, quotas *Quotas, method string) (context.Context, error) {
+	for _, prefix := range auth.PublicMethodPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return ctx, nil
+		}
+	}
+
+	var requested string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(Header); len(values) > 0 {
+			requested = strings.TrimSpace(values[0])
+		}
+	}
+
+	principal := auth.PrincipalFromContext(ctx)
+	bound := ""
+	if principal != nil {
+		bound = principal.Tenant
+	}
+
+	id := requested
+	switch {
+	case requested == "" && bound != "":
+		id = bound
+	case requested == "":
+		id = Default
+	default:
+		if err := Validate(requested); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if bound != "" && bound != requested {
+			slog.WarnContext(ctx, "Rejected call for another tenant", "method", method, "tenant", requested, "bound_tenant", bound)
+			return nil, status.Errorf(codes.PermissionDenied, "credentials are bound to tenant %s, not %s", bound, requested)
+		}
+		if principal != nil && bound == "" && requested != Default && !principal.Has(auth.RoleAdmin) {
+			slog.WarnContext(ctx, "Rejected unbound call for another tenant", "method", method, "tenant", requested, "subject", principal.Subject)
+			return nil, status.Errorf(codes.PermissionDenied, "credentials are not bound to tenant %s", requested)
+		}
+	}
+
+	ctx = WithTenant(ctx, id)
+	if err := quotas.Allow(ctx); err != nil {
+		slog.WarnContext(ctx, "Rejected call over the tenant rate", "method", method, "tenant", id)
+		return nil, err
+	}
+	return ctx, nil
+}
+
+// UnaryServerInterceptor runs unary calls for the tenant they name. It must follow the auth interceptor.
+func UnaryServerInterceptor(quotas *Quotas) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := resolve(ctx, quotas, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor runs streaming calls for the tenant they name. It must follow the auth interceptor.
+func StreamServerInterceptor(quotas *Quotas) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := resolve(stream.Context(), quotas, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &tenantStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// tenantStream overrides the stream context with one carrying the tenant
+type tenantStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tenantStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,165 @@
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AnyTenant keys the limits of tenants without limits of their own
+const AnyTenant = "*"
+
+// Limits bounds what one tenant may use; zero values are unlimited
+type Limits struct {
+	// MaxEntities caps the entities of the tenant, not counting attribute datasets
+	MaxEntities int64 `json:"maxEntities,omitempty"`
+	// RequestsPerSecond caps the sustained call rate, allowing bursts of Burst calls
+	RequestsPerSecond float64 `json:"requestsPerSecond,omitempty"`
+	Burst             int     `json:"burst,omitempty"`
+}
+
+// Quotas holds the limits of every tenant and the rate limiters enforcing them.
+// A nil *Quotas limits nothing.
+type Quotas struct {
+	limits map[string]Limits
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	entities map[string]*entityReservations
+}
+
+// entityReservations holds the entity creations of a tenant that passed its quota but may not be stored yet
+type entityReservations struct {
+	mu      sync.Mutex // Held while the stored entities are counted, so reservations are made one at a time
+	pending int64
+}
+
+// NewQuotas validates the limits, keyed by tenant or AnyTenant
+func NewQuotas(limits map[string]Limits) (*Quotas, error) {
+	for id, limit := range limits {
+		if id != AnyTenant {
+			if err := Validate(id); err != nil {
+				return nil, err
+			}
+		}
+		if limit.MaxEntities < 0 || limit.RequestsPerSecond < 0 || limit.Burst < 0 {
+			return nil, fmt.Errorf("tenant %s: limits cannot be negative", id)
+		}
+	}
+	return &Quotas{limits: limits, limiters: make(map[string]*rate.Limiter), entities: make(map[string]*entityReservations)}, nil
+}
+
+// LoadQuotas reads a JSON object of Limits keyed by tenant, e.g.
+// {"acme": {"maxEntities": 100000, "requestsPerSecond": 50, "burst": 100}, "*": {"requestsPerSecond": 10}}
+func LoadQuotas(path string) (*Quotas, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading tenant quotas: %v", err)
+	}
+	var limits map[string]Limits
+	if err := json.Unmarshal(data, &limits); err != nil {
+		return nil, fmt.Errorf("error parsing tenant quotas %s: %v", path, err)
+	}
+	return NewQuotas(limits)
+}
+
+// QuotasFromEnv loads the quotas named by CORE_TENANT_QUOTAS_FILE, returning nil when it is unset
+func QuotasFromEnv() (*Quotas, error) {
+	path := os.Getenv("CORE_TENANT_QUOTAS_FILE")
+	if path == "" {
+		return nil, nil
+	}
+	return LoadQuotas(path)
+}
+
+// For returns the limits of a tenant
+func (q *Quotas) For(id string) Limits {
+	if q == nil {
+		return Limits{}
+	}
+	if limit, ok := q.limits[id]; ok {
+		return limit
+	}
+	return q.limits[AnyTenant]
+}
+
+// Allow takes one call from the tenant's rate, returning codes.ResourceExhausted when it is spent
+func (q *Quotas) Allow(ctx context.Context) error {
+	id := FromContext(ctx)
+	limit := q.For(id)
+	if limit.RequestsPerSecond == 0 {
+		return nil
+	}
+
+	q.mu.Lock()
+	limiter, ok := q.limiters[id]
+	if !ok {
+		burst := limit.Burst
+		if burst == 0 {
+			burst = max(1, int(limit.RequestsPerSecond))
+		}
+		limiter = rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), burst)
+		q.limiters[id] = limiter
+	}
+	q.mu.Unlock()
+
+	if !limiter.Allow() {
+		return status.Errorf(codes.ResourceExhausted, "tenant %s exceeded %v requests per second", id, limit.RequestsPerSecond)
+	}
+	return nil
+}
+
+// ReserveEntity makes room for one new entity of the call's tenant under its entity quota, counting the
+// stored entities with count. Reservations not yet released count as entities, and a tenant's reservations
+// are made one at a time, so concurrent calls cannot together exceed the quota. release must be called
+// once the entity is stored or was not created; it may be called more than once. The reservations are
+// kept by this process, so replicas of the service sharing a database each check the quota on their own.
+func (q *Quotas) ReserveEntity(ctx context.Context, count func(ctx context.Context) (int64, error)) (release func(), err error) {
+	id := FromContext(ctx)
+	if q.For(id).MaxEntities == 0 {
+		return func() {}, nil
+	}
+
+	q.mu.Lock()
+	reservations, ok := q.entities[id]
+	if !ok {
+		reservations = &entityReservations{}
+		q.entities[id] = reservations
+	}
+	q.mu.Unlock()
+
+	reservations.mu.Lock()
+	defer reservations.mu.Unlock()
+	stored, err := count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.CheckEntities(ctx, stored+reservations.pending); err != nil {
+		return nil, err
+	}
+	reservations.pending++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			reservations.mu.Lock()
+			reservations.pending--
+			reservations.mu.Unlock()
+		})
+	}, nil
+}
+
+// CheckEntities returns codes.ResourceExhausted when a tenant holding count entities may not create another
+func (q *Quotas) CheckEntities(ctx context.Context, count int64) error {
+	id := FromContext(ctx)
+	if limit := q.For(id).MaxEntities; limit > 0 && count >= limit {
+		return status.Errorf(codes.ResourceExhausted, "tenant %s reached its quota of %d entities", id, limit)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// Package tenant isolates the data of the organisations sharing one deployment.
+//
+// Every call runs for a tenant, named by the "x-tenant-id" metadata or by the tenant a caller's
+// credentials are bound to, and the stores keep each tenant's entities apart: Neo4j nodes and
+// relationships carry a Tenant property, MongoDB documents live in a collection per tenant,
+// PostgreSQL tables in a schema per tenant and blobs below a prefix per tenant. The default tenant
+// keeps the names used before tenants existed, so a single tenant deployment is unchanged.
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// Header is the gRPC metadata key naming the tenant of a call
+const Header = "x-tenant-id"
+
+// Default is the tenant of calls that name none
+const Default = "default"
+
+// validID keeps tenant IDs usable in collection, schema and object names
+var validID = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+type tenantKey struct{}
+
+// Validate checks that id is a lower case name of at most 32 letters, digits and underscores
+func Validate(id string) error {
+	if !validID.MatchString(id) {
+		return fmt.Errorf("invalid tenant %q, use up to 32 lower case letters, digits and underscores starting with a letter", id)
+	}
+	return nil
+}
+
+// WithTenant returns a context for calls made on behalf of a tenant
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext returns the tenant of the call, Default when none was set
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(tenantKey{}).(string); ok && id != "" {
+		return id
+	}
+	return Default
+}
+
+// IsDefault reports whether the call runs for the default tenant
+func IsDefault(ctx context.Context) bool {
+	return FromContext(ctx) == Default
+}
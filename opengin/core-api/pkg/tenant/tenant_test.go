@@ -0,0 +1,169 @@
+package tenant
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"lk/datafoundation/core-api/pkg/auth"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidate(t *testing.T) {
+	for _, id := range []string{"acme", "a", "tenant_2", "abcdefghijklmnopqrstuvwxyz012345"} {
+		assert.NoError(t, Validate(id), id)
+	}
+	for _, id := range []string{"", "Acme", "2acme", "_acme", "acme-corp", "acme.corp", "abcdefghijklmnopqrstuvwxyz0123456"} {
+		assert.Error(t, Validate(id), id)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, Default, FromContext(context.Background()))
+	assert.True(t, IsDefault(context.Background()))
+
+	ctx := WithTenant(context.Background(), "acme")
+	assert.Equal(t, "acme", FromContext(ctx))
+	assert.False(t, IsDefault(ctx))
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor(nil)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return FromContext(ctx), nil
+	}
+	call := func(principal *auth.Principal, md metadata.MD) (interface{}, error) {
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		if principal != nil {
+			ctx = auth.WithPrincipal(ctx, principal)
+		}
+		return interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/core.COREService/ReadEntity"}, handler)
+	}
+
+	got, err := call(nil, metadata.MD{})
+	require.NoError(t, err)
+	assert.Equal(t, Default, got)
+
+	got, err = call(nil, metadata.Pairs(Header, "acme"))
+	require.NoError(t, err)
+	assert.Equal(t, "acme", got)
+
+	_, err = call(nil, metadata.Pairs(Header, "Acme Corp"))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	bound := &auth.Principal{Subject: "acme-ingest", Roles: []auth.Role{auth.RoleIngester}, Tenant: "acme"}
+	got, err = call(bound, metadata.MD{})
+	require.NoError(t, err)
+	assert.Equal(t, "acme", got)
+
+	got, err = call(bound, metadata.Pairs(Header, "acme"))
+	require.NoError(t, err)
+	assert.Equal(t, "acme", got)
+
+	_, err = call(bound, metadata.Pairs(Header, "globex"))
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Unbound callers keep to the default tenant unless they are admins
+	unbound := &auth.Principal{Subject: "reader", Roles: []auth.Role{auth.RoleReader}}
+	got, err = call(unbound, metadata.MD{})
+	require.NoError(t, err)
+	assert.Equal(t, Default, got)
+
+	got, err = call(unbound, metadata.Pairs(Header, Default))
+	require.NoError(t, err)
+	assert.Equal(t, Default, got)
+
+	_, err = call(unbound, metadata.Pairs(Header, "acme"))
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	admin := &auth.Principal{Subject: "admin", Roles: []auth.Role{auth.RoleAdmin}}
+	got, err = call(admin, metadata.Pairs(Header, "acme"))
+	require.NoError(t, err)
+	assert.Equal(t, "acme", got)
+}
+
+func TestQuotas(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotas.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"acme": {"maxEntities": 2, "requestsPerSecond": 1, "burst": 2},
+		"*": {"maxEntities": 10}
+	}`), 0600))
+	quotas, err := LoadQuotas(path)
+	require.NoError(t, err)
+
+	acme := WithTenant(context.Background(), "acme")
+	assert.Equal(t, Limits{MaxEntities: 2, RequestsPerSecond: 1, Burst: 2}, quotas.For("acme"))
+	assert.Equal(t, Limits{MaxEntities: 10}, quotas.For("globex"))
+
+	assert.NoError(t, quotas.CheckEntities(acme, 1))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(quotas.CheckEntities(acme, 2)))
+	assert.NoError(t, quotas.CheckEntities(context.Background(), 9))
+
+	// The burst is spent by the first two calls, the next one is refused
+	assert.NoError(t, quotas.Allow(acme))
+	assert.NoError(t, quotas.Allow(acme))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(quotas.Allow(acme)))
+	assert.NoError(t, quotas.Allow(context.Background()))
+
+	var unlimited *Quotas
+	assert.NoError(t, unlimited.Allow(acme))
+	assert.NoError(t, unlimited.CheckEntities(acme, 1000))
+
+	release, err := unlimited.ReserveEntity(acme, nil)
+	require.NoError(t, err)
+	release()
+
+	_, err = NewQuotas(map[string]Limits{"Bad Tenant": {}})
+	assert.Error(t, err)
+	_, err = NewQuotas(map[string]Limits{"acme": {MaxEntities: -1}})
+	assert.Error(t, err)
+}
+
+func TestQuotasReserveEntity(t *testing.T) {
+	quotas, err := NewQuotas(map[string]Limits{"acme": {MaxEntities: 5}})
+	require.NoError(t, err)
+	acme := WithTenant(context.Background(), "acme")
+
+	// Concurrent creations only store as many entities as the quota allows
+	var stored atomic.Int64
+	count := func(context.Context) (int64, error) { return stored.Load(), nil }
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := quotas.ReserveEntity(acme, count)
+			if err != nil {
+				assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+				return
+			}
+			stored.Add(1)
+			release()
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(5), stored.Load())
+
+	// A released reservation that was not stored frees its room
+	quotas, err = NewQuotas(map[string]Limits{"acme": {MaxEntities: 1}})
+	require.NoError(t, err)
+	none := func(context.Context) (int64, error) { return 0, nil }
+	release, err := quotas.ReserveEntity(acme, none)
+	require.NoError(t, err)
+	_, err = quotas.ReserveEntity(acme, none)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	release()
+	release()
+	release, err = quotas.ReserveEntity(acme, none)
+	require.NoError(t, err)
+	release()
+}
@@ -0,0 +1,96 @@
+// Package tracing sets up OpenTelemetry tracing for the core service and offers small helpers
+// for the spans opened around attribute resolvers and database calls.
+//
+// The exporter is chosen with OTEL_TRACES_EXPORTER: "otlp" sends spans over OTLP/gRPC to
+// OTEL_EXPORTER_OTLP_ENDPOINT, "stdout" prints them for local runs and "none" (the default)
+// keeps tracing off. Incoming W3C trace context is honoured so spans join the caller's trace.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultServiceName names the service in exported spans unless OTEL_SERVICE_NAME is set
+const DefaultServiceName = "core-api"
+
+const instrumentationName = "lk/datafoundation/core-api"
+
+// Setup installs the global tracer provider and propagator from the environment.
+// The returned function flushes and stops the exporter; it is safe to call when tracing is off.
+func Setup(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch name := os.Getenv("OTEL_TRACES_EXPORTER"); name {
+	case "", "none":
+		return func(context.Context) error { return nil }, nil
+	case "otlp":
+		exporter, err = otlptracegrpc.New(ctx)
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unsupported OTEL_TRACES_EXPORTER %q, use otlp, stdout or none", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error creating trace exporter: %v", err)
+	}
+
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = DefaultServiceName
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attribute.String("service.name", serviceName)))
+	if err != nil {
+		return nil, fmt.Errorf("error creating trace resource: %v", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start opens a span under the one carried by ctx
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// StartDatabase opens a client span for a database call named after the database and operation,
+// e.g. neo4j.ReadGraphEntity
+func StartDatabase(ctx context.Context, database, operation string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	attributes = append(attributes,
+		attribute.String("db.system", database),
+		attribute.String("db.operation.name", operation),
+	)
+	return otel.Tracer(instrumentationName).Start(ctx, database+"."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attributes...))
+}
+
+// End records the error, if any, and ends the span.
+// It takes a pointer so it can be deferred with a named error result:
+//
+//	ctx, span := tracing.StartDatabase(ctx, metrics.Neo4j, "ReadGraphEntity")
+//	defer tracing.End(span, &err)
+func End(span trace.Span, err *error) {
+	if err != nil && *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestDatabaseSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	call := func(ctx context.Context, fail bool) (err error) {
+		_, span := StartDatabase(ctx, "neo4j", "ReadGraphEntity")
+		defer End(span, &err)
+		if fail {
+			return errors.New("boom")
+		}
+		return nil
+	}
+
+	ctx, parent := Start(context.Background(), "parent")
+	require.NoError(t, call(ctx, false))
+	require.Error(t, call(ctx, true))
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	ok, failed := spans[0], spans[1]
+	assert.Equal(t, "neo4j.ReadGraphEntity", ok.Name())
+	assert.Equal(t, trace.SpanKindClient, ok.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), ok.Parent().SpanID())
+	assert.Contains(t, ok.Attributes(), attribute.String("db.system", "neo4j"))
+	assert.Equal(t, codes.Unset, ok.Status().Code)
+
+	assert.Equal(t, codes.Error, failed.Status().Code)
+	assert.Equal(t, "boom", failed.Status().Description)
+}
+
+func TestSetupRejectsUnknownExporter(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", "zipkin")
+	_, err := Setup(context.Background())
+	assert.Error(t, err)
+
+	t.Setenv("OTEL_TRACES_EXPORTER", "none")
+	shutdown, err := Setup(context.Background())
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
@@ -0,0 +1,133 @@
+package typeinference
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Decimals are kept as text so amounts such as budget figures are stored and read back digit for
+// digit; a float64 only holds about 15 significant digits and cannot hold 0.1 exactly.
+
+// DecimalPrecision is the least precision given to a decimal column: the 38 digits of a 128-bit
+// decimal, so later values with more integer digits than the sampled ones still fit
+const DecimalPrecision = 38
+
+// MaxDecimalPrecision is the largest precision of a PostgreSQL NUMERIC column; decimal columns that
+// need more are stored without a declared precision
+const MaxDecimalPrecision = 1000
+
+// MaxExactDigits is the number of digits a float64 holds exactly. Integers written as text with more
+// digits are bigints or decimals rather than text, since a JSON number could not have carried them.
+const MaxExactDigits = 15
+
+// decimalPattern matches plain decimal numbers, without an exponent
+var decimalPattern = regexp.MustCompile(`^[+-]?(\d*)(?:\.(\d*))?$`)
+
+// DecimalDigits returns the number of digits before and after the point of a plain decimal number
+// such as "-1200.50", not counting leading zeros of the integer part. ok is false for anything else,
+// including numbers with an exponent.
+func DecimalDigits(text string) (integer, scale int, ok bool) {
+	match := decimalPattern.FindStringSubmatch(strings.TrimSpace(text))
+	if match == nil || match[1]+match[2] == "" {
+		return 0, 0, false
+	}
+	return len(strings.TrimLeft(match[1], "0")), len(match[2]), true
+}
+
+// ParseDecimal returns a plain decimal number in canonical form: no plus sign, no leading zeros
+// before the integer digit and at least one digit on each side of a point, with the fraction digits
+// kept as written, so "+007.50" is "7.50" and "-.5" is "-0.5"
+func ParseDecimal(text string) (string, error) {
+	match := decimalPattern.FindStringSubmatch(strings.TrimSpace(text))
+	if match == nil || match[1]+match[2] == "" {
+		return "", fmt.Errorf("%q is not a decimal number", text)
+	}
+	integer := strings.TrimLeft(match[1], "0")
+	if integer == "" {
+		integer = "0"
+	}
+	decimal := integer
+	if match[2] != "" {
+		decimal += "." + match[2]
+	}
+	if strings.HasPrefix(strings.TrimSpace(text), "-") && strings.Trim(decimal, "0.") != "" {
+		decimal = "-" + decimal
+	}
+	return decimal, nil
+}
+
+// FitsDecimal reports whether a plain decimal number fits a decimal column of the given precision and
+// scale without rounding; a precision of 0 holds any number of integer digits
+func FitsDecimal(text string, precision, scale int) bool {
+	integer, _, ok := DecimalDigits(text)
+	if !ok {
+		return false
+	}
+	// Trailing zeros of the fraction are not lost when the column has fewer digits after the point
+	fraction := decimalPattern.FindStringSubmatch(strings.TrimSpace(text))[2]
+	return len(strings.TrimRight(fraction, "0")) <= scale && (precision == 0 || integer <= precision-scale)
+}
+
+// NumberText returns the text of a number or numeric string of structured data as a plain decimal
+// number, so that its digits can be counted; ok is false for other values
+func NumberText(value *structpb.Value) (text string, ok bool) {
+	switch v := value.GetKind().(type) {
+	case *structpb.Value_NumberValue:
+		if math.IsInf(v.NumberValue, 0) || math.IsNaN(v.NumberValue) {
+			return "", false
+		}
+		return strconv.FormatFloat(v.NumberValue, 'f', -1, 64), true
+	case *structpb.Value_StringValue:
+		_, _, ok := DecimalDigits(v.StringValue)
+		return strings.TrimSpace(v.StringValue), ok
+	default:
+		return "", false
+	}
+}
+
+// IntegerType returns int for integers a PostgreSQL INTEGER column holds and bigint for larger ones
+func IntegerType(v int64) DataType {
+	if v < math.MinInt32 || v > math.MaxInt32 {
+		return BigIntType
+	}
+	return IntType
+}
+
+// NumberType returns the type of a number of structured data: int or bigint for whole numbers within
+// the range of an int64 and float for everything else
+func NumberType(num float64) DataType {
+	if num != math.Trunc(num) || num < math.MinInt64 || num >= math.MaxInt64 {
+		return FloatType
+	}
+	return IntegerType(int64(num))
+}
+
+// NumericStringType returns the type of a string of structured data that holds a number. Producers
+// send amounts as strings to keep them exact, so:
+//   - a plain decimal with a fraction, such as "1200.50", is a decimal
+//   - an integer with more than MaxExactDigits digits is a bigint, or a decimal when it does not fit
+//     an int64
+//
+// Anything else, including shorter integers and numbers with leading zeros such as "007", is a string,
+// since those are usually codes and identifiers.
+func NumericStringType(str string) DataType {
+	integer, scale, ok := DecimalDigits(str)
+	if !ok || hasLeadingZero(strings.TrimSpace(str)) {
+		return StringType
+	}
+	switch {
+	case scale > 0:
+		return DecimalType
+	case integer <= MaxExactDigits:
+		return StringType
+	}
+	if _, err := strconv.ParseInt(strings.TrimSpace(str), 10, 64); err == nil {
+		return BigIntType
+	}
+	return DecimalType
+}
@@ -0,0 +1,89 @@
+package typeinference
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// TestDecimalDigits tests counting the digits of plain decimal numbers
+func TestDecimalDigits(t *testing.T) {
+	integer, scale, ok := DecimalDigits("-001200.50")
+	assert.True(t, ok)
+	assert.Equal(t, 4, integer)
+	assert.Equal(t, 2, scale)
+
+	integer, scale, ok = DecimalDigits(".125")
+	assert.True(t, ok)
+	assert.Equal(t, 0, integer)
+	assert.Equal(t, 3, scale)
+
+	for _, text := range []string{"", ".", "1e6", "1,200", "12a"} {
+		_, _, ok := DecimalDigits(text)
+		assert.False(t, ok, "DecimalDigits(%q)", text)
+	}
+}
+
+// TestParseDecimal tests writing decimal numbers in canonical form
+func TestParseDecimal(t *testing.T) {
+	testCases := map[string]string{
+		"1200.50": "1200.50",
+		"+007.50": "7.50",
+		"-.5":     "-0.5",
+		"12.":     "12",
+		"-0.00":   "0.00",
+		" 42 ":    "42",
+	}
+	for text, expected := range testCases {
+		decimal, err := ParseDecimal(text)
+		assert.NoError(t, err, "ParseDecimal(%q)", text)
+		assert.Equal(t, expected, decimal, "ParseDecimal(%q)", text)
+	}
+
+	_, err := ParseDecimal("1.5e3")
+	assert.Error(t, err)
+}
+
+// TestFitsDecimal tests checking numbers against the precision and scale of a decimal column
+func TestFitsDecimal(t *testing.T) {
+	assert.True(t, FitsDecimal("1200.50", 6, 2))
+	assert.True(t, FitsDecimal("1200.500", 6, 2))
+	assert.False(t, FitsDecimal("1200.505", 6, 2))
+	assert.False(t, FitsDecimal("12000.5", 6, 2))
+	assert.True(t, FitsDecimal("99999999999999999999999999999999999.5", 0, 1))
+	assert.False(t, FitsDecimal("1e3", 6, 2))
+
+	text, ok := NumberText(structpb.NewNumberValue(0.1))
+	assert.True(t, ok)
+	assert.Equal(t, "0.1", text)
+	text, ok = NumberText(structpb.NewStringValue(" 1200.50 "))
+	assert.True(t, ok)
+	assert.Equal(t, "1200.50", text)
+	_, ok = NumberText(structpb.NewStringValue("n/a"))
+	assert.False(t, ok)
+	_, ok = NumberText(structpb.NewBoolValue(true))
+	assert.False(t, ok)
+}
+
+// TestNumberTypes tests the types of numbers and numeric strings of structured data
+func TestNumberTypes(t *testing.T) {
+	assert.Equal(t, IntType, NumberType(2024))
+	assert.Equal(t, BigIntType, NumberType(4102444800000))
+	assert.Equal(t, FloatType, NumberType(3.5))
+	assert.Equal(t, FloatType, NumberType(1e20))
+
+	testCases := map[string]DataType{
+		"1200.50":              DecimalType,
+		"-0.125":               DecimalType,
+		"123456789012345678":   BigIntType,
+		"99999999999999999999": DecimalType,
+		"2024":                 StringType,
+		"007.5":                StringType,
+		"1e6":                  StringType,
+		"LKR 1200":             StringType,
+	}
+	for str, expected := range testCases {
+		assert.Equal(t, expected, NumericStringType(str), "NumericStringType(%q)", str)
+	}
+}
@@ -16,11 +16,13 @@ type DataType string
 
 const (
 	// Primitive Types
-	IntType    DataType = "int"    // Integer values (e.g., 42, -1)
-	FloatType  DataType = "float"  // Floating-point numbers (e.g., 3.14, -0.001)
-	StringType DataType = "string" // Text data
-	BoolType   DataType = "bool"   // Boolean values (true/false)
-	NullType   DataType = "null"   // Null values
+	IntType     DataType = "int"     // Integer values (e.g., 42, -1)
+	BigIntType  DataType = "bigint"  // Integers beyond 32 bits (e.g., 9007199254740993)
+	FloatType   DataType = "float"   // Floating-point numbers (e.g., 3.14, -0.001)
+	DecimalType DataType = "decimal" // Exact decimal numbers kept as text (e.g., "1200.50")
+	StringType  DataType = "string"  // Text data
+	BoolType    DataType = "bool"    // Boolean values (true/false)
+	NullType    DataType = "null"    // Null values
 
 	// Special Types
 	DateType     DataType = "date"     // Date values (e.g., "2024-03-20")
@@ -36,6 +38,8 @@ type TypeInfo struct {
 	IsArray    bool                 // Whether the type is an array
 	ArrayType  *TypeInfo            // For array elements, contains the type of array elements
 	Properties map[string]*TypeInfo // For map types, contains property types
+	Precision  int                  `json:",omitempty"` // For decimals, the number of digits; 0 when unconstrained
+	Scale      int                  `json:",omitempty"` // For decimals, the number of digits after the point
 }
 
 // TypeInferrer provides functionality to infer data types from protobuf Any values.
@@ -217,6 +221,17 @@ func (ti *TypeInferrer) inferTypeFromReflection(rv reflect.Value) (*TypeInfo, er
 	}
 }
 
+// dateFormats are the layouts isDate accepts, tried in order, so ambiguous dates read as DD/MM/YYYY
+var dateFormats = []string{
+	"2006-01-02", // YYYY-MM-DD
+	"02/01/2006", // DD/MM/YYYY
+	"01/02/2006", // MM/DD/YYYY
+	"2006.01.02", // YYYY.MM.DD
+	"02-01-2006", // DD-MM-YYYY
+	"01-02-2006", // MM-DD-YYYY
+	"2006/01/02", // YYYY/MM/DD
+}
+
 // isDate checks if a string represents a valid date.
 // It supports multiple common date formats including:
 // - YYYY-MM-DD (e.g., "2024-03-20")
@@ -233,16 +248,6 @@ func (ti *TypeInferrer) inferTypeFromReflection(rv reflect.Value) (*TypeInfo, er
 // Returns:
 //   - bool: True if the string matches any of the supported date formats
 func isDate(str string) bool {
-	dateFormats := []string{
-		"2006-01-02", // YYYY-MM-DD
-		"02/01/2006", // DD/MM/YYYY
-		"01/02/2006", // MM/DD/YYYY
-		"2006.01.02", // YYYY.MM.DD
-		"02-01-2006", // DD-MM-YYYY
-		"01-02-2006", // MM-DD-YYYY
-		"2006/01/02", // YYYY/MM/DD
-	}
-
 	for _, format := range dateFormats {
 		if _, err := time.Parse(format, str); err == nil {
 			return true
@@ -251,6 +256,16 @@ func isDate(str string) bool {
 	return false
 }
 
+// timeFormats are the layouts isTime accepts
+var timeFormats = []string{
+	"15:04:05",       // HH:MM:SS
+	"15:04",          // HH:MM
+	"3:04 PM",        // h:MM AM/PM
+	"15:04:05.000",   // HH:MM:SS.mmm
+	"15:04:05-07:00", // HH:MM:SS±HH:MM
+	"15:04:05Z",      // HH:MM:SSZ
+}
+
 // isTime checks if a string represents a valid time.
 // It supports multiple common time formats including:
 // - HH:MM:SS (e.g., "14:30:00")
@@ -266,15 +281,6 @@ func isDate(str string) bool {
 // Returns:
 //   - bool: True if the string matches any of the supported time formats
 func isTime(str string) bool {
-	timeFormats := []string{
-		"15:04:05",       // HH:MM:SS
-		"15:04",          // HH:MM
-		"3:04 PM",        // h:MM AM/PM
-		"15:04:05.000",   // HH:MM:SS.mmm
-		"15:04:05-07:00", // HH:MM:SS±HH:MM
-		"15:04:05Z",      // HH:MM:SSZ
-	}
-
 	for _, format := range timeFormats {
 		if _, err := time.Parse(format, str); err == nil {
 			return true
@@ -283,6 +289,17 @@ func isTime(str string) bool {
 	return false
 }
 
+// datetimeFormats are the layouts isDateTime accepts
+var datetimeFormats = []string{
+	time.RFC3339,              // 2006-01-02T15:04:05Z07:00
+	"2006-01-02 15:04:05",     // YYYY-MM-DD HH:MM:SS
+	"2006-01-02T15:04:05",     // YYYY-MM-DDTHH:MM:SS
+	"02/01/2006 15:04:05",     // DD/MM/YYYY HH:MM:SS
+	"01/02/2006 15:04:05",     // MM/DD/YYYY HH:MM:SS
+	"2006.01.02 15:04:05",     // YYYY.MM.DD HH:MM:SS
+	"2006-01-02 15:04:05.000", // YYYY-MM-DD HH:MM:SS.mmm
+}
+
 // isDateTime checks if a string represents a valid datetime.
 // It supports multiple common datetime formats including:
 // - RFC3339 (e.g., "2024-03-20T14:30:00Z07:00")
@@ -299,16 +316,6 @@ func isTime(str string) bool {
 // Returns:
 //   - bool: True if the string matches any of the supported datetime formats
 func isDateTime(str string) bool {
-	datetimeFormats := []string{
-		time.RFC3339,              // 2006-01-02T15:04:05Z07:00
-		"2006-01-02 15:04:05",     // YYYY-MM-DD HH:MM:SS
-		"2006-01-02T15:04:05",     // YYYY-MM-DDTHH:MM:SS
-		"02/01/2006 15:04:05",     // DD/MM/YYYY HH:MM:SS
-		"01/02/2006 15:04:05",     // MM/DD/YYYY HH:MM:SS
-		"2006.01.02 15:04:05",     // YYYY.MM.DD HH:MM:SS
-		"2006-01-02 15:04:05.000", // YYYY-MM-DD HH:MM:SS.mmm
-	}
-
 	for _, format := range datetimeFormats {
 		if _, err := time.Parse(format, str); err == nil {
 			return true
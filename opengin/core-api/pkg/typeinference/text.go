@@ -0,0 +1,179 @@
+package typeinference
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// numberPattern matches decimal numbers as spreadsheets and CSV files write them
+var numberPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// InferTextType infers the type of a value read as text, such as a cell of a CSV file.
+// Unlike the string values of structured data, text may hold numbers and booleans:
+//   - "" (after trimming spaces) is null
+//   - "42" is an int, integers beyond 32 bits are bigints and beyond 64 bits decimals; numbers with
+//     leading zeros such as "007" are codes and stay strings
+//   - "3.14" or "1e6" is a float, and a plain decimal with more than MaxExactDigits significant
+//     digits, which a float would round, is a decimal
+//   - "true" and "false" in any case are bools
+//   - dates, times and datetimes are recognised as isDate, isTime and isDateTime do
+func InferTextType(text string) DataType {
+	str := strings.TrimSpace(text)
+	switch {
+	case str == "":
+		return NullType
+	case numberPattern.MatchString(str):
+		if hasLeadingZero(str) {
+			return StringType
+		}
+		if v, err := strconv.ParseInt(str, 10, 64); err == nil {
+			return IntegerType(v)
+		}
+		if integer, scale, ok := DecimalDigits(str); ok && (!strings.Contains(str, ".") || integer+scale > MaxExactDigits) {
+			return DecimalType
+		}
+		return FloatType
+	case strings.EqualFold(str, "true") || strings.EqualFold(str, "false"):
+		return BoolType
+	case isDate(str):
+		return DateType
+	case isDateTime(str):
+		return DateTimeType
+	case isTime(str):
+		return TimeType
+	default:
+		return StringType
+	}
+}
+
+// CommonType returns the type a column holding values of both types is stored as.
+// Nulls fit any type, ints widen to bigints, integers to floats, numbers to decimals and dates to
+// datetimes; anything else mixed is a string.
+func CommonType(a, b DataType) DataType {
+	switch {
+	case a == b || b == NullType:
+		return a
+	case a == NullType:
+		return b
+	case numberRank[a] > 0 && numberRank[b] > 0:
+		if numberRank[a] > numberRank[b] {
+			return a
+		}
+		return b
+	case (a == DateType && b == DateTimeType) || (a == DateTimeType && b == DateType):
+		return DateTimeType
+	default:
+		return StringType
+	}
+}
+
+// numberRank orders the number types by the values they hold: each holds all values of the lower ones
+var numberRank = map[DataType]int{IntType: 1, BigIntType: 2, FloatType: 3, DecimalType: 4}
+
+// ParseText converts text to a value of the given type: an int64, float64 or bool for numbers and
+// booleans, and a string for everything else, with decimals as ParseDecimal writes them, dates as
+// YYYY-MM-DD, datetimes as RFC3339 and times as HH:MM:SS. Empty text is nil. Text of a string column
+// is returned unchanged.
+func ParseText(text string, dataType DataType) (interface{}, error) {
+	str := strings.TrimSpace(text)
+	if str == "" {
+		return nil, nil
+	}
+
+	switch dataType {
+	case IntType, BigIntType:
+		v, err := parseInteger(str)
+		if err != nil || (dataType == IntType && IntegerType(v) != IntType) {
+			return nil, fmt.Errorf("%q is not an integer", text)
+		}
+		return v, nil
+	case DecimalType:
+		if _, _, ok := DecimalDigits(str); ok {
+			return ParseDecimal(str)
+		}
+		// Numbers with an exponent, such as 1.5e3
+		if !numberPattern.MatchString(str) {
+			return nil, fmt.Errorf("%q is not a number", text)
+		}
+		v, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", text)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case FloatType:
+		if !numberPattern.MatchString(str) {
+			return nil, fmt.Errorf("%q is not a number", text)
+		}
+		v, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", text)
+		}
+		return v, nil
+	case BoolType:
+		switch {
+		case strings.EqualFold(str, "true"):
+			return true, nil
+		case strings.EqualFold(str, "false"):
+			return false, nil
+		default:
+			return nil, fmt.Errorf("%q is not a boolean", text)
+		}
+	case DateType:
+		if t, ok := parseLayouts(str, dateFormats); ok {
+			return t.Format("2006-01-02"), nil
+		}
+		return nil, fmt.Errorf("%q is not a date", text)
+	case DateTimeType:
+		// Dates in a datetime column are midnight UTC
+		if t, ok := parseLayouts(str, datetimeFormats); ok {
+			return t.Format(time.RFC3339Nano), nil
+		}
+		if t, ok := parseLayouts(str, dateFormats); ok {
+			return t.Format(time.RFC3339Nano), nil
+		}
+		return nil, fmt.Errorf("%q is not a datetime", text)
+	case TimeType:
+		if t, ok := parseLayouts(str, timeFormats); ok {
+			return t.Format("15:04:05.999999999"), nil
+		}
+		return nil, fmt.Errorf("%q is not a time", text)
+	default:
+		return text, nil
+	}
+}
+
+// parseInteger parses an integer, including whole numbers written with a decimal point or an exponent
+// such as 12.0
+func parseInteger(str string) (int64, error) {
+	if !numberPattern.MatchString(str) {
+		return 0, fmt.Errorf("%q is not an integer", str)
+	}
+	if v, err := strconv.ParseInt(str, 10, 64); err == nil {
+		return v, nil
+	}
+	v, err := strconv.ParseFloat(str, 64)
+	if err != nil || v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+		return 0, fmt.Errorf("%q is not an integer", str)
+	}
+	return int64(v), nil
+}
+
+// parseLayouts parses the string with the first layout that accepts it
+func parseLayouts(str string, layouts []string) (time.Time, bool) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, str); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// hasLeadingZero reports whether a number is written with a leading zero, as codes and ids often are
+func hasLeadingZero(number string) bool {
+	digits := strings.TrimLeft(number, "+-")
+	return len(digits) > 1 && digits[0] == '0' && digits[1] >= '0' && digits[1] <= '9'
+}
@@ -0,0 +1,108 @@
+package typeinference
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInferTextType tests type inference for values read as text
+func TestInferTextType(t *testing.T) {
+	testCases := map[string]DataType{
+		"":                     NullType,
+		"   ":                  NullType,
+		"42":                   IntType,
+		"-7":                   IntType,
+		"0":                    IntType,
+		"3.14":                 FloatType,
+		"1e6":                  FloatType,
+		".5":                   FloatType,
+		"99999999999999999999": DecimalType,
+		"3000000000":           BigIntType,
+		"-9007199254740993":    BigIntType,
+		"1234567890123.4567":   DecimalType,
+		"1200.50":              FloatType,
+		"007":                  StringType,
+		"0.25":                 FloatType,
+		"TRUE":                 BoolType,
+		"false":                BoolType,
+		"2024-03-20":           DateType,
+		"20/03/2024":           DateType,
+		"2024-03-20T14:30:00Z": DateTimeType,
+		"2024-03-20 14:30:00":  DateTimeType,
+		"14:30":                TimeType,
+		"NaN":                  StringType,
+		"Colombo":              StringType,
+		"1,200":                StringType,
+	}
+
+	for text, expected := range testCases {
+		assert.Equal(t, expected, InferTextType(text), "InferTextType(%q)", text)
+	}
+}
+
+// TestCommonType tests how the types of the values of a column combine
+func TestCommonType(t *testing.T) {
+	assert.Equal(t, IntType, CommonType(IntType, IntType))
+	assert.Equal(t, IntType, CommonType(NullType, IntType))
+	assert.Equal(t, DateType, CommonType(DateType, NullType))
+	assert.Equal(t, FloatType, CommonType(IntType, FloatType))
+	assert.Equal(t, BigIntType, CommonType(IntType, BigIntType))
+	assert.Equal(t, FloatType, CommonType(BigIntType, FloatType))
+	assert.Equal(t, DecimalType, CommonType(DecimalType, IntType))
+	assert.Equal(t, DecimalType, CommonType(FloatType, DecimalType))
+	assert.Equal(t, StringType, CommonType(DecimalType, BoolType))
+	assert.Equal(t, DateTimeType, CommonType(DateTimeType, DateType))
+	assert.Equal(t, StringType, CommonType(IntType, BoolType))
+	assert.Equal(t, StringType, CommonType(DateType, TimeType))
+	assert.Equal(t, NullType, CommonType(NullType, NullType))
+}
+
+// TestParseText tests converting text to values of a column type
+func TestParseText(t *testing.T) {
+	testCases := []struct {
+		text     string
+		dataType DataType
+		expected interface{}
+	}{
+		{"42", IntType, int64(42)},
+		{" 12.0 ", IntType, int64(12)},
+		{"3.5", FloatType, 3.5},
+		{"7", FloatType, 7.0},
+		{"9007199254740993", BigIntType, int64(9007199254740993)},
+		{"+0012345678901234567890.10", DecimalType, "12345678901234567890.10"},
+		{"-.5", DecimalType, "-0.5"},
+		{"1.5e3", DecimalType, "1500"},
+		{"True", BoolType, true},
+		{"20/03/2024", DateType, "2024-03-20"},
+		{"2024-03-20", DateTimeType, "2024-03-20T00:00:00Z"},
+		{"2024-03-20 14:30:00", DateTimeType, "2024-03-20T14:30:00Z"},
+		{"2:30 PM", TimeType, "14:30:00"},
+		{" padded ", StringType, " padded "},
+		{"", IntType, nil},
+	}
+	for _, tc := range testCases {
+		value, err := ParseText(tc.text, tc.dataType)
+		assert.NoError(t, err, "ParseText(%q, %s)", tc.text, tc.dataType)
+		assert.Equal(t, tc.expected, value, "ParseText(%q, %s)", tc.text, tc.dataType)
+	}
+
+	for _, tc := range []struct {
+		text     string
+		dataType DataType
+	}{
+		{"n/a", IntType},
+		{"12.5", IntType},
+		{"3000000000", IntType},
+		{"99999999999999999999", BigIntType},
+		{"1,200.50", DecimalType},
+		{"1,200", FloatType},
+		{"yes", BoolType},
+		{"2024-13-40", DateType},
+		{"soon", DateTimeType},
+		{"25:00", TimeType},
+	} {
+		_, err := ParseText(tc.text, tc.dataType)
+		assert.Error(t, err, "ParseText(%q, %s)", tc.text, tc.dataType)
+	}
+}